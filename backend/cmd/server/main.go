@@ -93,8 +93,20 @@ func main() {
 	}
 	slog.Info("connected to Redis")
 
-	// Initialize email service
-	emailService := utils.NewEmailService(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPFrom)
+	// Initialize email services; with email disabled nothing is sent
+	var (
+		authEmailSvc services.EmailService     = services.NoopEmailService{}
+		userEmailSvc services.UserEmailService = services.NoopEmailService{}
+		poMailer     services.POMailer         = services.NoopEmailService{}
+	)
+	if cfg.EmailEnabled {
+		emailService := utils.NewEmailService(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPFrom)
+		authEmailSvc = emailService
+		userEmailSvc = &userEmailAdapter{svc: emailService}
+		poMailer = emailService
+	} else {
+		slog.Info("email disabled, no emails will be sent")
+	}
 
 	// Initialize repositories
 	userRepo := repositories.NewUserRepository(db)
@@ -105,7 +117,12 @@ func main() {
 	productRepo := repositories.NewProductRepository(db)
 	poRepo := repositories.NewPORepository(db)
 	stockMovementRepo := repositories.NewStockMovementRepository(db)
+	reportRepo := repositories.NewReportRepository(db)
+	alertRepo := repositories.NewAlertRepository(db)
+	stockSnapshotRepo := repositories.NewStockSnapshotRepository(db)
 	salesRepo := repositories.NewSalesRepository(db)
+	stockAdjustmentRepo := repositories.NewStockAdjustmentRepository(db)
+	auditLogRepo := repositories.NewAuditLogRepository(db)
 
 	var imageStorage services.ImageStorage
 	if cfg.MinIOEnabled {
@@ -126,21 +143,84 @@ func main() {
 	}
 
 	// Initialize services
-	authService := services.NewAuthService(userRepo, rdb, cfg, emailService)
-	userEmailSvc := &userEmailAdapter{svc: emailService}
+	authService := services.NewAuthService(userRepo, rdb, cfg, authEmailSvc)
+	authService.SetPasswordPolicy(cfg.PasswordPolicy())
 	userService := services.NewUserService(userRepo, rdb, cfg, userEmailSvc)
-	roleService := services.NewRoleService(roleRepo)
+	userService.SetPasswordPolicy(cfg.PasswordPolicy())
+	userService.SetEmailEnabled(cfg.EmailEnabled)
+	userService.SetAuditRecorder(auditLogRepo)
+	roleService := services.NewRoleService(roleRepo, services.RoleConfig{Redis: rdb, Audit: auditLogRepo})
 	categoryService := services.NewCategoryService(categoryRepo)
-	supplierService := services.NewSupplierService(supplierRepo)
+	supplierService := services.NewSupplierService(supplierRepo, services.SupplierConfig{
+		UniqueEmail:          cfg.SupplierUniqueEmail,
+		BankAccountMinLength: cfg.SupplierBankAccountMinLength,
+		BankAccountMaxLength: cfg.SupplierBankAccountMaxLength,
+		PaymentTermDays:      cfg.SupplierPaymentTermDays,
+	})
 	rackService := services.NewRackService(rackRepo)
-	productService := services.NewProductService(productRepo, imageStorage)
 	seqService := services.NewSequenceService(db)
-	poService := services.NewPOService(db, poRepo, stockMovementRepo, seqService)
-	salesService := services.NewSalesService(db, salesRepo, seqService)
+	productService := services.NewProductService(productRepo, imageStorage)
+	productService.SetDocumentFormat(cfg.DocumentFormat())
+	productService.SetSKUSequence(seqService)
+	productService.SetImageLimits(services.ProductImageLimits{
+		MaxProductImages: cfg.ProductMaxImages,
+		MaxVariantImages: cfg.VariantMaxImages,
+		MaxBytes:         cfg.ProductImageMaxSize,
+	})
+	poService := services.NewPOService(db, poRepo, stockMovementRepo, seqService, services.POConfig{
+		ApprovalThreshold:    cfg.POApprovalThreshold,
+		Storage:              imageStorage,
+		AttachmentMaxBytes:   cfg.POAttachmentMaxSize,
+		MaxItems:             cfg.POMaxItems,
+		GapFreeNumbers:       cfg.POGapFreeNumbers,
+		RequireReceivedItems: cfg.POReceiveRequireItems,
+		RequireVerifiedItems: cfg.POReceiveRequireVerified,
+		StoreName:            cfg.StoreName,
+		DocumentFormat:       cfg.DocumentFormat(),
+		Mailer:               poMailer,
+		EmailDisabled:        !cfg.EmailEnabled,
+		Redis:                rdb,
+		ReceiveTokenTTL:      cfg.POReceiveTokenTTL,
+	})
+	stockMovementService := services.NewStockMovementService(stockMovementRepo)
+	stockAdjustmentService := services.NewStockAdjustmentService(db, stockAdjustmentRepo)
+	auditLogService := services.NewAuditLogService(auditLogRepo)
+	reportService := services.NewReportService(reportRepo, services.ReportConfig{
+		Location: cfg.StoreTimezone,
+	})
+	alertService := services.NewAlertService(alertRepo, services.AlertConfig{
+		Location: cfg.StoreTimezone,
+	})
+	if cfg.AlertCheckInterval > 0 {
+		alertService.Start(context.Background(), cfg.AlertCheckInterval)
+	}
+	stockSnapshotService := services.NewStockSnapshotService(stockSnapshotRepo, services.StockSnapshotConfig{
+		Location: cfg.StoreTimezone,
+	})
+	if cfg.StockSnapshotEnabled {
+		stockSnapshotService.Start(context.Background(), cfg.StockSnapshotTime)
+	}
+	salesService := services.NewSalesService(db, salesRepo, seqService, services.SalesConfig{
+		CheckoutMaxRetries:   cfg.CheckoutMaxRetries,
+		CheckoutRetryBackoff: cfg.CheckoutRetryBackoff,
+		PaymentSurcharges:    cfg.PaymentSurcharges,
+		RequirePricingTier:   cfg.RequirePricingTier,
+		MaxCartItems:         cfg.CheckoutMaxItems,
+		VariantCheckoutLimit: cfg.CheckoutVariantLimit,
+		ZeroStockPolicy:      cfg.CatalogZeroStock,
+		MaxDiscountPercent:   cfg.CheckoutMaxDiscount,
+		RoleMaxDiscounts:     cfg.RoleMaxDiscounts,
+		TaxPercent:           cfg.SalesTaxPercent,
+		TaxInclusive:         cfg.SalesTaxInclusive,
+		Redis:                rdb,
+	})
 
 	// Initialize middleware
 	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTAccessSecret, rdb, userRepo)
-	permMiddleware := middleware.NewPermissionMiddleware(db, rdb)
+	permMiddleware := middleware.NewPermissionMiddleware(db, rdb, middleware.PermissionConfig{
+		AuditDenials: cfg.AuditPermissionDenials,
+	})
+	rateLimiter := middleware.NewRateLimiter(rdb)
 
 	// Initialize handlers
 	healthHandler := handlers.NewHealthHandler(db, rdb)
@@ -154,10 +234,16 @@ func main() {
 	productHandler := handlers.NewProductHandler(productService)
 	poHandler := handlers.NewPOHandler(poService)
 	salesHandler := handlers.NewSalesHandler(salesService)
+	stockHandler := handlers.NewStockHandler(stockMovementService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	alertHandler := handlers.NewAlertHandler(alertService)
+	stockSnapshotHandler := handlers.NewStockSnapshotHandler(stockSnapshotService)
+	stockAdjustmentHandler := handlers.NewStockAdjustmentHandler(stockAdjustmentService)
+	auditLogHandler := handlers.NewAuditLogHandler(auditLogService)
 
 	// Setup router and routes
 	r := chi.NewRouter()
-	routes.Setup(r, healthHandler, authHandler, userHandler, roleHandler, permissionHandler, categoryHandler, supplierHandler, rackHandler, productHandler, poHandler, salesHandler, authMiddleware, permMiddleware, cfg)
+	routes.Setup(r, healthHandler, authHandler, userHandler, roleHandler, permissionHandler, categoryHandler, supplierHandler, rackHandler, productHandler, poHandler, salesHandler, stockHandler, reportHandler, alertHandler, stockSnapshotHandler, stockAdjustmentHandler, auditLogHandler, authMiddleware, permMiddleware, rateLimiter, cfg)
 
 	// Start server
 	addr := fmt.Sprintf(":%s", cfg.AppPort)
@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+	// Embedded zoneinfo so STORE_TIMEZONE loads on images without tzdata
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
+	"github.com/pointofsale/backend/utils"
 )
 
 type Config struct {
@@ -26,9 +30,11 @@ type Config struct {
 	JWTRefreshSecret string
 	JWTAccessExpiry  time.Duration
 	JWTRefreshExpiry time.Duration
+	SingleSession    bool
 	SMTPHost         string
 	SMTPPort         string
 	SMTPFrom         string
+	EmailEnabled     bool
 	MinIOEnabled     bool
 	MinIOEndpoint    string
 	MinIOAccessKey   string
@@ -36,12 +42,87 @@ type Config struct {
 	MinIOBucket      string
 	MinIOUseSSL      bool
 	MinIOPublicURL   string
+
+	CheckoutMaxRetries   int
+	CheckoutRetryBackoff time.Duration
+	PaymentSurcharges    map[string]float64
+	RequirePricingTier   bool
+	CheckoutMaxItems     int
+	CheckoutVariantLimit int
+	CatalogZeroStock     string
+	CheckoutMaxDiscount  float64
+	RoleMaxDiscounts     map[string]float64
+	SalesTaxPercent      float64
+	SalesTaxInclusive    bool
+
+	POApprovalThreshold      float64
+	POAttachmentMaxSize      int64
+	POMaxItems               int
+	POGapFreeNumbers         bool
+	POReceiveRequireItems    bool
+	POReceiveRequireVerified bool
+	POReceiveTokenTTL        time.Duration
+	SupplierUniqueEmail      bool
+
+	ProductMaxImages    int
+	VariantMaxImages    int
+	ProductImageMaxSize int64
+
+	SupplierBankAccountMinLength int
+	SupplierBankAccountMaxLength int
+	SupplierPaymentTermDays      int
+
+	LoginMaxAttempts    int
+	LoginLockoutWindow  time.Duration
+	PasswordHistorySize int
+
+	PasswordMinLength     int
+	PasswordMaxLength     int
+	PasswordRequireUpper  bool
+	PasswordRequireLower  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+
+	RateLimitEnabled bool
+	RateLimits       map[string]RateLimit
+
+	StoreName          string
+	StoreTimezone      *time.Location
+	CurrencySymbol     string
+	ThousandsSeparator string
+	DecimalSeparator   string
+	CurrencyDecimals   int
+	DateFormat         string
+
+	AlertCheckInterval time.Duration
+
+	StockSnapshotEnabled bool
+	StockSnapshotTime    time.Duration
+
+	AuditPermissionDenials bool
+}
+
+// RateLimit allows Requests state-changing requests per client, refilled over Window.
+type RateLimit struct {
+	Requests int
+	Window   time.Duration
 }
 
 func Load() (*Config, error) {
 	// Load .env file (ignore error if not found, env vars may be set directly)
 	_ = godotenv.Load()
 
+	// envInt reads an integer setting, keeping the first invalid one so Load
+	// can report it once every setting has been read.
+	var intErr error
+	envInt := func(key string, fallback int) int {
+		value, err := getEnvInt(key, fallback)
+		if err != nil && intErr == nil {
+			intErr = err
+		}
+		return value
+	}
+
 	accessExpiry, err := time.ParseDuration(getEnv("JWT_ACCESS_EXPIRY", "15m"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid JWT_ACCESS_EXPIRY: %w", err)
@@ -52,7 +133,82 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid JWT_REFRESH_EXPIRY: %w", err)
 	}
 
-	return &Config{
+	checkoutRetryBackoff, err := time.ParseDuration(getEnv("CHECKOUT_RETRY_BACKOFF", "20ms"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CHECKOUT_RETRY_BACKOFF: %w", err)
+	}
+
+	paymentSurcharges, err := parseSurcharges(getEnv("PAYMENT_SURCHARGES", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PAYMENT_SURCHARGES: %w", err)
+	}
+
+	checkoutMaxDiscount, err := strconv.ParseFloat(getEnv("CHECKOUT_MAX_DISCOUNT", "0"), 64)
+	if err != nil || checkoutMaxDiscount < 0 || checkoutMaxDiscount > 100 {
+		return nil, fmt.Errorf("invalid CHECKOUT_MAX_DISCOUNT: %q", getEnv("CHECKOUT_MAX_DISCOUNT", "0"))
+	}
+
+	salesTaxPercent, err := strconv.ParseFloat(getEnv("SALES_TAX_PERCENT", "0"), 64)
+	if err != nil || salesTaxPercent < 0 || salesTaxPercent > 100 {
+		return nil, fmt.Errorf("invalid SALES_TAX_PERCENT: %q", getEnv("SALES_TAX_PERCENT", "0"))
+	}
+
+	roleMaxDiscounts, err := parseRoleDiscounts(getEnv("CHECKOUT_ROLE_MAX_DISCOUNTS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CHECKOUT_ROLE_MAX_DISCOUNTS: %w", err)
+	}
+
+	loginLockoutWindow, err := time.ParseDuration(getEnv("LOGIN_LOCKOUT_WINDOW", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_LOCKOUT_WINDOW: %w", err)
+	}
+
+	rateLimits, err := parseRateLimits(getEnv("RATE_LIMITS", "sales:120/1m,purchase-orders:60/1m,products:60/1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMITS: %w", err)
+	}
+
+	poApprovalThreshold, err := strconv.ParseFloat(getEnv("PO_APPROVAL_THRESHOLD", "0"), 64)
+	if err != nil || poApprovalThreshold < 0 {
+		return nil, fmt.Errorf("invalid PO_APPROVAL_THRESHOLD: %q", getEnv("PO_APPROVAL_THRESHOLD", "0"))
+	}
+
+	poReceiveTokenTTL, err := time.ParseDuration(getEnv("PO_RECEIVE_TOKEN_TTL", "10m"))
+	if err != nil || poReceiveTokenTTL < 0 {
+		return nil, fmt.Errorf("invalid PO_RECEIVE_TOKEN_TTL: %q", getEnv("PO_RECEIVE_TOKEN_TTL", "10m"))
+	}
+
+	alertCheckInterval, err := time.ParseDuration(getEnv("ALERT_CHECK_INTERVAL", "15m"))
+	if err != nil || alertCheckInterval < 0 {
+		return nil, fmt.Errorf("invalid ALERT_CHECK_INTERVAL: %q", getEnv("ALERT_CHECK_INTERVAL", "15m"))
+	}
+
+	stockSnapshotTime, err := parseTimeOfDay(getEnv("STOCK_SNAPSHOT_TIME", "23:55"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STOCK_SNAPSHOT_TIME: %q (expected HH:MM)", getEnv("STOCK_SNAPSHOT_TIME", "23:55"))
+	}
+
+	storeTimezone, err := time.LoadLocation(getEnv("STORE_TIMEZONE", "UTC"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STORE_TIMEZONE: %w", err)
+	}
+
+	catalogZeroStock := strings.ToLower(getEnv("CATALOG_ZERO_STOCK", "show"))
+	if catalogZeroStock != "show" && catalogZeroStock != "hide" {
+		return nil, fmt.Errorf("invalid CATALOG_ZERO_STOCK: %q (must be show or hide)", catalogZeroStock)
+	}
+
+	// A zero PASSWORD_MAX_LENGTH means no upper limit
+	passwordMinLength := envInt("PASSWORD_MIN_LENGTH", 8)
+	passwordMaxLength := envInt("PASSWORD_MAX_LENGTH", 0)
+	if intErr != nil {
+		return nil, intErr
+	}
+	if passwordMaxLength < 0 || (passwordMaxLength > 0 && passwordMaxLength < passwordMinLength) {
+		return nil, fmt.Errorf("invalid PASSWORD_MAX_LENGTH: %d (must be 0 or at least PASSWORD_MIN_LENGTH %d)", passwordMaxLength, passwordMinLength)
+	}
+
+	cfg := &Config{
 		AppEnv:           getEnv("APP_ENV", "development"),
 		AppPort:          getEnv("APP_PORT", "8080"),
 		FrontendURL:      getEnv("FRONTEND_URL", "http://localhost:3000"),
@@ -69,9 +225,11 @@ func Load() (*Config, error) {
 		JWTRefreshSecret: getEnv("JWT_REFRESH_SECRET", ""),
 		JWTAccessExpiry:  accessExpiry,
 		JWTRefreshExpiry: refreshExpiry,
+		SingleSession:    getEnvBool("SINGLE_SESSION", false),
 		SMTPHost:         getEnv("SMTP_HOST", "localhost"),
 		SMTPPort:         getEnv("SMTP_PORT", "1025"),
 		SMTPFrom:         getEnv("SMTP_FROM", "noreply@pointofsale.local"),
+		EmailEnabled:     getEnvBool("EMAIL_ENABLED", true),
 		MinIOEnabled:     getEnvBool("MINIO_ENABLED", false),
 		MinIOEndpoint:    getEnv("MINIO_ENDPOINT", "minio:9000"),
 		MinIOAccessKey:   getEnv("MINIO_ACCESS_KEY", "minioadmin"),
@@ -79,7 +237,69 @@ func Load() (*Config, error) {
 		MinIOBucket:      getEnv("MINIO_BUCKET", "pos-images"),
 		MinIOUseSSL:      getEnvBool("MINIO_USE_SSL", false),
 		MinIOPublicURL:   getEnv("MINIO_PUBLIC_URL", "http://localhost:9000"),
-	}, nil
+
+		CheckoutMaxRetries:   envInt("CHECKOUT_MAX_RETRIES", 3),
+		CheckoutRetryBackoff: checkoutRetryBackoff,
+		PaymentSurcharges:    paymentSurcharges,
+		RequirePricingTier:   getEnvBool("REQUIRE_PRICING_TIER", true),
+		CheckoutMaxItems:     envInt("CHECKOUT_MAX_ITEMS", 200),
+		CheckoutVariantLimit: envInt("CHECKOUT_VARIANT_CONCURRENCY", 0),
+		CatalogZeroStock:     catalogZeroStock,
+		CheckoutMaxDiscount:  checkoutMaxDiscount,
+		RoleMaxDiscounts:     roleMaxDiscounts,
+		SalesTaxPercent:      salesTaxPercent,
+		SalesTaxInclusive:    getEnvBool("SALES_TAX_INCLUSIVE", false),
+
+		POApprovalThreshold:      poApprovalThreshold,
+		POAttachmentMaxSize:      int64(envInt("PO_ATTACHMENT_MAX_SIZE", 10<<20)),
+		POMaxItems:               envInt("PO_MAX_ITEMS", 500),
+		POGapFreeNumbers:         getEnvBool("PO_GAP_FREE_NUMBERS", false),
+		POReceiveRequireItems:    getEnvBool("PO_RECEIVE_REQUIRE_ITEMS", true),
+		POReceiveRequireVerified: getEnvBool("PO_RECEIVE_REQUIRE_VERIFIED", false),
+		POReceiveTokenTTL:        poReceiveTokenTTL,
+		SupplierUniqueEmail:      getEnvBool("SUPPLIER_UNIQUE_EMAIL", false),
+
+		ProductMaxImages:    envInt("PRODUCT_MAX_IMAGES", 10),
+		VariantMaxImages:    envInt("VARIANT_MAX_IMAGES", 5),
+		ProductImageMaxSize: int64(envInt("PRODUCT_IMAGE_MAX_SIZE", 5<<20)),
+
+		SupplierBankAccountMinLength: envInt("SUPPLIER_BANK_ACCOUNT_MIN_LENGTH", 6),
+		SupplierBankAccountMaxLength: envInt("SUPPLIER_BANK_ACCOUNT_MAX_LENGTH", 20),
+		SupplierPaymentTermDays:      envInt("SUPPLIER_PAYMENT_TERM_DAYS", 30),
+
+		LoginMaxAttempts:    envInt("LOGIN_MAX_ATTEMPTS", 5),
+		LoginLockoutWindow:  loginLockoutWindow,
+		PasswordHistorySize: envInt("PASSWORD_HISTORY_SIZE", 5),
+
+		PasswordMinLength:     passwordMinLength,
+		PasswordMaxLength:     passwordMaxLength,
+		PasswordRequireUpper:  getEnvBool("PASSWORD_REQUIRE_UPPER", true),
+		PasswordRequireLower:  getEnvBool("PASSWORD_REQUIRE_LOWER", true),
+		PasswordRequireDigit:  getEnvBool("PASSWORD_REQUIRE_DIGIT", true),
+		PasswordRequireSymbol: getEnvBool("PASSWORD_REQUIRE_SYMBOL", true),
+
+		RateLimitEnabled: getEnvBool("RATE_LIMIT_ENABLED", true),
+		RateLimits:       rateLimits,
+
+		StoreName:          getEnv("STORE_NAME", "Point of Sale"),
+		StoreTimezone:      storeTimezone,
+		CurrencySymbol:     getEnv("CURRENCY_SYMBOL", "Rp"),
+		ThousandsSeparator: getEnv("THOUSANDS_SEPARATOR", "."),
+		DecimalSeparator:   getEnv("DECIMAL_SEPARATOR", ","),
+		CurrencyDecimals:   envInt("CURRENCY_DECIMALS", 0),
+		DateFormat:         getEnv("DATE_FORMAT", "02/01/2006"),
+
+		AlertCheckInterval: alertCheckInterval,
+
+		StockSnapshotEnabled: getEnvBool("STOCK_SNAPSHOT_ENABLED", true),
+		StockSnapshotTime:    stockSnapshotTime,
+
+		AuditPermissionDenials: getEnvBool("AUDIT_PERMISSION_DENIALS", false),
+	}
+	if intErr != nil {
+		return nil, intErr
+	}
+	return cfg, nil
 }
 
 func (c *Config) DSN() string {
@@ -89,6 +309,29 @@ func (c *Config) DSN() string {
 	)
 }
 
+// PasswordPolicy returns the rules new passwords must satisfy.
+func (c *Config) PasswordPolicy() utils.PasswordPolicy {
+	return utils.PasswordPolicy{
+		MinLength:     c.PasswordMinLength,
+		MaxLength:     c.PasswordMaxLength,
+		RequireUpper:  c.PasswordRequireUpper,
+		RequireLower:  c.PasswordRequireLower,
+		RequireDigit:  c.PasswordRequireDigit,
+		RequireSymbol: c.PasswordRequireSymbol,
+	}
+}
+
+// DocumentFormat returns the locale settings used by document generators.
+func (c *Config) DocumentFormat() utils.DocumentFormat {
+	return utils.DocumentFormat{
+		CurrencySymbol:     c.CurrencySymbol,
+		ThousandsSeparator: c.ThousandsSeparator,
+		DecimalSeparator:   c.DecimalSeparator,
+		CurrencyDecimals:   c.CurrencyDecimals,
+		DateFormat:         c.DateFormat,
+	}
+}
+
 func getEnv(key, fallback string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
@@ -107,3 +350,98 @@ func getEnvBool(key string, fallback bool) bool {
 	}
 	return parsed
 }
+
+// getEnvInt reads an integer setting, returning fallback when it is unset and
+// an error when it is set but not a number.
+func getEnvInt(key string, fallback int) (int, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(strings.TrimSpace(val))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %q", key, val)
+	}
+	return parsed, nil
+}
+
+// parseSurcharges parses "method:percent" pairs separated by commas, e.g. "card:1,qris:0.7".
+func parseSurcharges(val string) (map[string]float64, error) {
+	surcharges := make(map[string]float64)
+	for _, pair := range strings.Split(val, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		method, percentStr, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("expected method:percent, got %q", pair)
+		}
+		percent, err := strconv.ParseFloat(strings.TrimSpace(percentStr), 64)
+		if err != nil || percent < 0 {
+			return nil, fmt.Errorf("invalid surcharge percent for %q", method)
+		}
+		surcharges[strings.ToLower(strings.TrimSpace(method))] = percent
+	}
+	return surcharges, nil
+}
+
+// parseRoleDiscounts parses "role:percent" pairs separated by commas, e.g.
+// "Cashier:5,Store Manager:20". Role names are matched case-insensitively.
+func parseRoleDiscounts(val string) (map[string]float64, error) {
+	discounts := make(map[string]float64)
+	for _, pair := range strings.Split(val, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		role, percentStr, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("expected role:percent, got %q", pair)
+		}
+		percent, err := strconv.ParseFloat(strings.TrimSpace(percentStr), 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid discount percent for %q", role)
+		}
+		discounts[strings.ToLower(strings.TrimSpace(role))] = percent
+	}
+	return discounts, nil
+}
+
+// parseRateLimits parses "group:requests/window" pairs, e.g. "sales:120/1m".
+func parseRateLimits(val string) (map[string]RateLimit, error) {
+	limits := make(map[string]RateLimit)
+	for _, pair := range strings.Split(val, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		group, spec, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("expected group:requests/window, got %q", pair)
+		}
+		requestsStr, windowStr, ok := strings.Cut(spec, "/")
+		if !ok {
+			return nil, fmt.Errorf("expected requests/window for %q", group)
+		}
+		requests, err := strconv.Atoi(strings.TrimSpace(requestsStr))
+		if err != nil || requests <= 0 {
+			return nil, fmt.Errorf("invalid request count for %q", group)
+		}
+		window, err := time.ParseDuration(strings.TrimSpace(windowStr))
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid window for %q", group)
+		}
+		limits[strings.ToLower(strings.TrimSpace(group))] = RateLimit{Requests: requests, Window: window}
+	}
+	return limits, nil
+}
+
+// parseTimeOfDay parses "HH:MM" into an offset from midnight.
+func parseTimeOfDay(val string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(val))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pointofsale/backend/middleware"
+	"github.com/pointofsale/backend/services"
+	"github.com/pointofsale/backend/utils"
+)
+
+// AlertHandler handles alert HTTP requests.
+type AlertHandler struct {
+	alertService *services.AlertService
+}
+
+// NewAlertHandler creates a new alert handler instance.
+func NewAlertHandler(alertService *services.AlertService) *AlertHandler {
+	return &AlertHandler{alertService: alertService}
+}
+
+// ListAlerts handles GET /api/v1/alerts
+// It returns the unacknowledged alerts addressed to the current user's roles.
+func (h *AlertHandler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	alerts, err := h.alertService.ListOpen(middleware.GetUserID(r.Context()), middleware.GetIsSuperAdmin(r.Context()))
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, "Failed to load alerts", "INTERNAL_ERROR")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", alerts)
+}
+
+// AcknowledgeAlert handles POST /api/v1/alerts/{id}/ack
+func (h *AlertHandler) AcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid alert ID", "VALIDATION_ERROR")
+		return
+	}
+
+	err = h.alertService.Acknowledge(uint(id), middleware.GetUserID(r.Context()), middleware.GetIsSuperAdmin(r.Context()))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to acknowledge alert"
+		code := "INTERNAL_ERROR"
+
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			switch serviceErr.Err {
+			case services.ErrNotFound:
+				status = http.StatusNotFound
+			case services.ErrConflict:
+				status = http.StatusConflict
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Alert acknowledged", nil)
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/pointofsale/backend/middleware"
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/services"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupAlertTestRouter(t *testing.T) (chi.Router, *gorm.DB, *services.AlertService) {
+	t.Helper()
+
+	db := testutil.SetupTestDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	userRepo := repositories.NewUserRepository(db)
+	alertService := services.NewAlertService(repositories.NewAlertRepository(db))
+	alertHandler := NewAlertHandler(alertService)
+
+	authMiddleware := middleware.NewAuthMiddleware(testutil.TestJWTAccessSecret, rdb, userRepo)
+
+	r := chi.NewRouter()
+	r.Route("/api/v1/alerts", func(r chi.Router) {
+		r.Use(authMiddleware.Authenticate)
+		r.Get("/", alertHandler.ListAlerts)
+		r.Post("/{id}/ack", alertHandler.AcknowledgeAlert)
+	})
+
+	return r, db, alertService
+}
+
+func createAlertTestUserWithRole(t *testing.T, db *gorm.DB, roleName string) *models.User {
+	t.Helper()
+	role := testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = roleName
+	})
+	return testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Roles = []models.Role{*role}
+	})
+}
+
+func TestAlerts_LowStockCheck_VisibleToWarehouseUntilAcknowledged(t *testing.T) {
+	router, db, alertService := setupAlertTestRouter(t)
+
+	warehouseUser := createAlertTestUserWithRole(t, db, "Warehouse")
+	cashierUser := createAlertTestUserWithRole(t, db, "Cashier")
+	warehouseToken := testutil.GenerateTestAccessToken(t, warehouseUser.ID, false)
+	cashierToken := testutil.GenerateTestAccessToken(t, cashierUser.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", product.Variants[0].ID).
+		Updates(map[string]interface{}{"current_stock": 3, "reorder_point": 10}).Error)
+
+	created, err := alertService.CheckLowStock()
+	require.NoError(t, err)
+	require.Equal(t, 1, created)
+
+	// Warehouse users see the alert
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/alerts", nil, warehouseToken)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	alerts := testutil.AssertJSONResponse(t, rr)["data"].([]interface{})
+	require.Len(t, alerts, 1)
+	alert := alerts[0].(map[string]interface{})
+	assert.Equal(t, services.AlertTypeLowStock, alert["type"])
+
+	// Cashiers do not
+	req = testutil.AuthenticatedRequest(t, "GET", "/api/v1/alerts", nil, cashierToken)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, testutil.AssertJSONResponse(t, rr)["data"])
+
+	// Acknowledging removes it from the open list
+	req = testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/alerts/%.0f/ack", alert["id"]), nil, warehouseToken)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	// The next check leaves the acknowledged condition alone
+	created, err = alertService.CheckLowStock()
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+
+	req = testutil.AuthenticatedRequest(t, "GET", "/api/v1/alerts", nil, warehouseToken)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, testutil.AssertJSONResponse(t, rr)["data"])
+}
+
+func TestAcknowledgeAlert_OtherRole_Returns404(t *testing.T) {
+	router, db, alertService := setupAlertTestRouter(t)
+
+	cashierUser := createAlertTestUserWithRole(t, db, "Cashier")
+	token := testutil.GenerateTestAccessToken(t, cashierUser.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", product.Variants[0].ID).
+		Updates(map[string]interface{}{"current_stock": 3, "reorder_point": 10}).Error)
+	_, err := alertService.CheckLowStock()
+	require.NoError(t, err)
+
+	var alert models.Alert
+	require.NoError(t, db.First(&alert).Error)
+
+	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/alerts/%d/ack", alert.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
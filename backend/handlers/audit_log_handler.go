@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pointofsale/backend/services"
+	"github.com/pointofsale/backend/utils"
+)
+
+// AuditLogHandler handles audit log HTTP requests.
+type AuditLogHandler struct {
+	auditLogService *services.AuditLogService
+}
+
+// NewAuditLogHandler creates a new audit log handler instance.
+func NewAuditLogHandler(auditLogService *services.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{auditLogService: auditLogService}
+}
+
+// ListAuditLogs handles GET /api/v1/audit-logs?search=&actor=&action=&entity=&from=&to=&page=&pageSize=
+// search matches values in the before and after payloads, actor is a user ID,
+// action an audit event, and from and to are YYYY-MM-DD dates (inclusive).
+func (h *AuditLogHandler) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	params, err := utils.ParsePaginationParams(r, nil)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	query := r.URL.Query()
+	input := services.AuditLogSearchInput{
+		Search: params.Search,
+		Action: query.Get("action"),
+		Entity: query.Get("entity"),
+	}
+	if actor := query.Get("actor"); actor != "" {
+		actorID, err := strconv.ParseUint(actor, 10, 64)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid 'actor', expected a user ID", "VALIDATION_ERROR")
+			return
+		}
+		id := uint(actorID)
+		input.ActorID = &id
+	}
+	if from := query.Get("from"); from != "" {
+		if input.From, err = time.Parse("2006-01-02", from); err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid 'from' date, expected YYYY-MM-DD", "VALIDATION_ERROR")
+			return
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		if input.To, err = time.Parse("2006-01-02", to); err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid 'to' date, expected YYYY-MM-DD", "VALIDATION_ERROR")
+			return
+		}
+	}
+
+	items, total, err := h.auditLogService.Search(params.Page, params.PageSize, input)
+	if err != nil {
+		writeReportError(w, err, "Failed to list audit logs")
+		return
+	}
+
+	meta := utils.CalculatePaginationMeta(params.Page, params.PageSize, int(total))
+	utils.JSON(w, http.StatusOK, utils.PaginatedResponse{
+		Data: items,
+		Meta: meta,
+	})
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/pointofsale/backend/config"
+	"github.com/pointofsale/backend/middleware"
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/services"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupAuditLogTestRouter(t *testing.T) (chi.Router, *gorm.DB) {
+	t.Helper()
+
+	db := testutil.SetupTestDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	cfg := &config.Config{
+		JWTAccessSecret: testutil.TestJWTAccessSecret,
+	}
+
+	userRepo := repositories.NewUserRepository(db)
+	auditLogService := services.NewAuditLogService(repositories.NewAuditLogRepository(db))
+	auditLogHandler := NewAuditLogHandler(auditLogService)
+
+	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTAccessSecret, rdb, userRepo)
+	permMiddleware := middleware.NewPermissionMiddleware(db, rdb)
+
+	r := chi.NewRouter()
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(authMiddleware.Authenticate)
+		r.With(permMiddleware.RequirePermission("Settings", "Roles & Permissions", "read")).Get("/audit-logs", auditLogHandler.ListAuditLogs)
+	})
+
+	return r, db
+}
+
+func createAuditLogEntry(t *testing.T, db *gorm.DB, userID uint, event, entity, entityID, before, after string, at time.Time) *models.AuditLog {
+	t.Helper()
+	entry := &models.AuditLog{
+		Event:     event,
+		UserID:    &userID,
+		Entity:    entity,
+		EntityID:  entityID,
+		Before:    models.JSONPayload(before),
+		After:     models.JSONPayload(after),
+		CreatedAt: at,
+	}
+	require.NoError(t, db.Create(entry).Error)
+	return entry
+}
+
+func TestListAuditLogs_SearchChangedValue_FindsEntry(t *testing.T) {
+	router, db := setupAuditLogTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	changed := createAuditLogEntry(t, db, admin.ID, "user_updated", "user", "42",
+		`{"email": "rina@old.example"}`, `{"email": "rina@new.example"}`, day)
+	createAuditLogEntry(t, db, admin.ID, "user_updated", "user", "43",
+		`{"name": "Budi"}`, `{"name": "Budi Santoso"}`, day)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/audit-logs?search="+url.QueryEscape("rina@new.example"), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	response := testutil.AssertJSONResponse(t, rr)
+	items := response["data"].([]interface{})
+	require.Len(t, items, 1)
+	first := items[0].(map[string]interface{})
+	assert.Equal(t, float64(changed.ID), first["id"])
+	assert.Equal(t, "user", first["entity"])
+	assert.Equal(t, "42", first["entityId"])
+	assert.Equal(t, "rina@old.example", first["before"].(map[string]interface{})["email"])
+	assert.Equal(t, "rina@new.example", first["after"].(map[string]interface{})["email"])
+	assert.Equal(t, admin.Name, first["actorName"])
+
+	meta := response["meta"].(map[string]interface{})
+	assert.Equal(t, float64(1), meta["totalItems"])
+}
+
+func TestListAuditLogs_Filters_NarrowResults(t *testing.T) {
+	router, db := setupAuditLogTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+	rina := testutil.CreateTestUser(t, db, func(u *models.User) { u.Name = "Rina" })
+
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	match := createAuditLogEntry(t, db, rina.ID, "supplier_updated", "supplier", "7", `{"phone": "0811"}`, `{"phone": "0812"}`, day)
+	// Another actor
+	createAuditLogEntry(t, db, admin.ID, "supplier_updated", "supplier", "7", `{"phone": "0812"}`, `{"phone": "0813"}`, day)
+	// Another action
+	createAuditLogEntry(t, db, rina.ID, "supplier_created", "supplier", "8", "", `{"phone": "0899"}`, day)
+	// Outside the date range
+	createAuditLogEntry(t, db, rina.ID, "supplier_updated", "supplier", "7", `{"phone": "0810"}`, `{"phone": "0811"}`, day.AddDate(0, 0, 10))
+
+	query := url.Values{}
+	query.Set("actor", strconv.FormatUint(uint64(rina.ID), 10))
+	query.Set("action", "supplier_updated")
+	query.Set("entity", "supplier")
+	query.Set("from", "2026-03-01")
+	query.Set("to", "2026-03-02")
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/audit-logs?"+query.Encode(), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	response := testutil.AssertJSONResponse(t, rr)
+	items := response["data"].([]interface{})
+	require.Len(t, items, 1)
+	assert.Equal(t, float64(match.ID), items[0].(map[string]interface{})["id"])
+}
+
+func TestListAuditLogs_Pagination_ReturnsNewestFirst(t *testing.T) {
+	router, db := setupAuditLogTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	var entries []*models.AuditLog
+	for i := 0; i < 3; i++ {
+		entries = append(entries, createAuditLogEntry(t, db, admin.ID, "user_updated", "user", strconv.Itoa(i), "", `{"name": "x"}`, day.Add(time.Duration(i)*time.Minute)))
+	}
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/audit-logs?page=2&pageSize=2", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	response := testutil.AssertJSONResponse(t, rr)
+	items := response["data"].([]interface{})
+	require.Len(t, items, 1)
+	first := items[0].(map[string]interface{})
+	assert.Equal(t, float64(entries[0].ID), first["id"])
+	assert.Nil(t, first["before"])
+
+	meta := response["meta"].(map[string]interface{})
+	assert.Equal(t, float64(3), meta["totalItems"])
+}
+
+func TestListAuditLogs_InvalidParams_Returns400(t *testing.T) {
+	router, db := setupAuditLogTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+
+	for _, query := range []string{"actor=rina", "from=02-03-2026", "to=yesterday", "from=2026-03-05&to=2026-03-01"} {
+		req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/audit-logs?"+query, nil, token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func TestListAuditLogs_NoPermission_Returns403(t *testing.T) {
+	router, db := setupAuditLogTestRouter(t)
+
+	user := testutil.CreateTestUser(t, db)
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/audit-logs", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
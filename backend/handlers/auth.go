@@ -40,7 +40,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	utils.Success(w, http.StatusCreated, "Registration successful. Please wait for admin approval.", user)
+	utils.Success(w, http.StatusCreated, "Registration successful. Please verify your email and wait for admin approval.", user)
 }
 
 // Login handles user authentication
@@ -51,6 +51,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	input.UserAgent = r.UserAgent()
 	loginResp, serviceErr := h.authService.Login(input)
 	if serviceErr != nil {
 		// Map service error to HTTP status code
@@ -67,6 +68,37 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if loginResp.TwoFactorRequired {
+		utils.Success(w, http.StatusOK, "Two-factor code required", loginResp)
+		return
+	}
+	utils.Success(w, http.StatusOK, "Login successful", loginResp)
+}
+
+// VerifyTwoFactor completes a login for users with two-factor enabled
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TwoFactorToken string `json:"twoFactorToken"`
+		Code           string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+
+	loginResp, serviceErr := h.authService.VerifyTwoFactor(req.TwoFactorToken, req.Code, r.UserAgent())
+	if serviceErr != nil {
+		status := http.StatusInternalServerError
+		switch serviceErr.Err {
+		case services.ErrUnauthorized:
+			status = http.StatusUnauthorized
+		case services.ErrForbidden:
+			status = http.StatusForbidden
+		}
+		utils.Error(w, status, serviceErr.Message, serviceErr.Code)
+		return
+	}
+
 	utils.Success(w, http.StatusOK, "Login successful", loginResp)
 }
 
@@ -85,7 +117,7 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	tokenPair, serviceErr := h.authService.RefreshToken(req.RefreshToken)
+	tokenPair, serviceErr := h.authService.RefreshToken(req.RefreshToken, r.UserAgent())
 	if serviceErr != nil {
 		utils.Error(w, http.StatusUnauthorized, serviceErr.Message, serviceErr.Code)
 		return
@@ -121,6 +153,42 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	utils.Success(w, http.StatusOK, "Logged out successfully", nil)
 }
 
+// LogoutAll revokes all sessions of the current user (requires authentication)
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		utils.Error(w, http.StatusUnauthorized, "User not authenticated", "UNAUTHORIZED")
+		return
+	}
+
+	revoked, serviceErr := h.authService.LogoutAll(userID)
+	if serviceErr != nil {
+		utils.Error(w, http.StatusInternalServerError, serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Logged out from all devices", map[string]int{
+		"revokedSessions": revoked,
+	})
+}
+
+// ListSessions returns the active sessions of the current user (requires authentication)
+func (h *AuthHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		utils.Error(w, http.StatusUnauthorized, "User not authenticated", "UNAUTHORIZED")
+		return
+	}
+
+	sessions, serviceErr := h.authService.ListSessions(userID)
+	if serviceErr != nil {
+		utils.Error(w, http.StatusInternalServerError, serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", sessions)
+}
+
 // ForgotPassword handles password reset request
 func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -162,6 +230,50 @@ func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	utils.Success(w, http.StatusOK, "Password reset successfully. Please login with your new password.", nil)
 }
 
+// VerifyEmail confirms a user's email address using the emailed token
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+
+	serviceErr := h.authService.VerifyEmail(input.Token)
+	if serviceErr != nil {
+		status := http.StatusInternalServerError
+		switch serviceErr.Err {
+		case services.ErrValidation:
+			status = http.StatusBadRequest
+		case services.ErrUnauthorized:
+			status = http.StatusUnauthorized
+		case services.ErrNotFound:
+			status = http.StatusNotFound
+		}
+		utils.Error(w, status, serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Email verified successfully.", nil)
+}
+
+// ResendVerification emails a new verification link to a pending user
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+
+	// Always returns nil to avoid email enumeration
+	_ = h.authService.ResendVerification(req.Email)
+
+	utils.Success(w, http.StatusOK, "If the account is awaiting verification, a new link has been sent.", nil)
+}
+
 // GetMe returns the current authenticated user's details (requires authentication)
 func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 	// Get user ID from context (set by auth middleware)
@@ -183,3 +295,61 @@ func (h *AuthHandler) GetMe(w http.ResponseWriter, r *http.Request) {
 
 	utils.Success(w, http.StatusOK, "", currentUser)
 }
+
+// EnableTwoFactor starts two-factor setup for the current user (requires authentication)
+func (h *AuthHandler) EnableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		utils.Error(w, http.StatusUnauthorized, "User not authenticated", "UNAUTHORIZED")
+		return
+	}
+
+	setup, serviceErr := h.authService.EnableTwoFactor(userID)
+	if serviceErr != nil {
+		status := http.StatusInternalServerError
+		switch serviceErr.Err {
+		case services.ErrNotFound:
+			status = http.StatusNotFound
+		case services.ErrConflict:
+			status = http.StatusConflict
+		}
+		utils.Error(w, status, serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Scan the secret with your authenticator app, then confirm a code", setup)
+}
+
+// ConfirmTwoFactor enables two-factor for the current user once a code is verified (requires authentication)
+func (h *AuthHandler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		utils.Error(w, http.StatusUnauthorized, "User not authenticated", "UNAUTHORIZED")
+		return
+	}
+
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+
+	serviceErr := h.authService.ConfirmTwoFactor(userID, req.Code)
+	if serviceErr != nil {
+		status := http.StatusInternalServerError
+		switch serviceErr.Err {
+		case services.ErrValidation:
+			status = http.StatusBadRequest
+		case services.ErrNotFound:
+			status = http.StatusNotFound
+		case services.ErrConflict:
+			status = http.StatusConflict
+		}
+		utils.Error(w, status, serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Two-factor authentication enabled", nil)
+}
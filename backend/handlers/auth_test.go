@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -46,7 +47,7 @@ func setupTestRouter(t *testing.T) (chi.Router, *gorm.DB, *redis.Client) {
 
 	// Initialize layers
 	userRepo := repositories.NewUserRepository(db)
-	authService := services.NewAuthService(userRepo, rdb, cfg, nil) // nil email service
+	authService := services.NewAuthService(userRepo, rdb, cfg, services.NoopEmailService{})
 	authHandler := NewAuthHandler(authService)
 	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTAccessSecret, rdb, userRepo)
 	healthHandler := NewHealthHandler(db, rdb)
@@ -61,10 +62,17 @@ func setupTestRouter(t *testing.T) (chi.Router, *gorm.DB, *redis.Client) {
 			r.Post("/refresh", authHandler.Refresh)
 			r.Post("/forgot-password", authHandler.ForgotPassword)
 			r.Post("/reset-password", authHandler.ResetPassword)
+			r.Post("/verify-email", authHandler.VerifyEmail)
+			r.Post("/resend-verification", authHandler.ResendVerification)
+			r.Post("/2fa/verify", authHandler.VerifyTwoFactor)
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware.Authenticate)
 				r.Post("/logout", authHandler.Logout)
+				r.Post("/logout-all", authHandler.LogoutAll)
+				r.Get("/sessions", authHandler.ListSessions)
 				r.Get("/me", authHandler.GetMe)
+				r.Post("/2fa/enable", authHandler.EnableTwoFactor)
+				r.Post("/2fa/confirm", authHandler.ConfirmTwoFactor)
 			})
 		})
 	})
@@ -339,6 +347,86 @@ func TestLogoutHandler_NoAuth_Returns401(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, rr.Code)
 }
 
+func TestLogoutAllHandler_Authenticated_ReturnsRevokedCount(t *testing.T) {
+	router, db, rdb := setupTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Status = "active"
+	})
+	accessToken := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	// Two sessions for the user
+	rdb.Set(testutil.Context(), "refresh:session-1", user.ID, time.Hour)
+	rdb.Set(testutil.Context(), "refresh:session-2", user.ID, time.Hour)
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/logout-all", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, float64(2), data["revokedSessions"])
+	assert.Empty(t, rdb.Keys(testutil.Context(), "refresh:*").Val())
+}
+
+func TestLogoutAllHandler_NoAuth_Returns401(t *testing.T) {
+	router, db, _ := setupTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/logout-all", nil)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestSessionsHandler_AfterLogin_ListsSessionWithUserAgent(t *testing.T) {
+	router, db, _ := setupTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Email = "sessions@example.com"
+		u.Status = "active"
+	})
+
+	body := `{"email": "sessions@example.com", "password": "Password@123"}`
+	req := httptest.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "TestBrowser/1.0")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var loginResp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &loginResp))
+	accessToken := loginResp["data"].(map[string]interface{})["accessToken"].(string)
+
+	req = httptest.NewRequest("GET", "/api/v1/auth/sessions", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	sessions := response["data"].([]interface{})
+	require.Len(t, sessions, 1)
+	session := sessions[0].(map[string]interface{})
+	assert.Equal(t, "TestBrowser/1.0", session["userAgent"])
+	assert.NotEmpty(t, session["id"])
+	assert.NotEmpty(t, session["createdAt"])
+}
+
 func TestForgotPasswordHandler_ValidEmail_Returns200(t *testing.T) {
 	router, db, _ := setupTestRouter(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -389,6 +477,62 @@ func TestResetPasswordHandler_ValidToken_Returns200(t *testing.T) {
 	assert.Contains(t, rr.Body.String(), "Password reset successfully")
 }
 
+func TestVerifyEmailHandler_ValidToken_Returns200(t *testing.T) {
+	router, db, rdb := setupTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Status = "pending"
+	})
+	rdb.Set(testutil.Context(), "verify:test-verify-token", user.ID, time.Hour)
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/verify-email", strings.NewReader(`{"token": "test-verify-token"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var updated models.User
+	require.NoError(t, db.First(&updated, user.ID).Error)
+	assert.True(t, updated.EmailVerified)
+}
+
+func TestVerifyEmailHandler_InvalidToken_Returns401(t *testing.T) {
+	router, db, _ := setupTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/verify-email", strings.NewReader(`{"token": "unknown"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestResendVerificationHandler_PendingUser_StoresNewToken(t *testing.T) {
+	router, db, rdb := setupTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Email = "pending@example.com"
+		u.Status = "pending"
+	})
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/resend-verification", strings.NewReader(`{"email": "pending@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	keys, err := rdb.Keys(testutil.Context(), "verify:*").Result()
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, fmt.Sprintf("%d", user.ID), rdb.Get(testutil.Context(), keys[0]).Val())
+}
+
 func TestMeHandler_Authenticated_ReturnsUserData(t *testing.T) {
 	router, db, _ := setupTestRouter(t)
 	defer testutil.CleanupTestDB(t, db)
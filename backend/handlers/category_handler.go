@@ -25,7 +25,19 @@ func NewCategoryHandler(categoryService *services.CategoryService) *CategoryHand
 var categorySortFields = []string{"id", "name", "description"}
 
 // ListCategories handles GET /api/v1/categories
+// With ?tree=true it returns every category nested under its parent instead
+// of a paginated flat list.
 func (h *CategoryHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("tree") == "true" {
+		tree, err := h.categoryService.CategoryTree()
+		if err != nil {
+			utils.Error(w, http.StatusInternalServerError, "Failed to fetch categories", "INTERNAL_ERROR")
+			return
+		}
+		utils.Success(w, http.StatusOK, "", tree)
+		return
+	}
+
 	paginationParams, err := utils.ParsePaginationParams(r, categorySortFields)
 	if err != nil {
 		utils.Error(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
@@ -55,6 +67,17 @@ func (h *CategoryHandler) ListCategories(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// GetProductCounts handles GET /api/v1/categories/product-counts
+func (h *CategoryHandler) GetProductCounts(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.categoryService.ActiveProductCounts()
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, "Failed to count products", "INTERNAL_ERROR")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", counts)
+}
+
 // GetCategory handles GET /api/v1/categories/{id}
 func (h *CategoryHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -54,6 +54,7 @@ func setupCategoryTestRouter(t *testing.T) (chi.Router, *gorm.DB, *redis.Client,
 	r.Route("/api/v1/categories", func(r chi.Router) {
 		r.Use(authMiddleware.Authenticate)
 		r.With(permMiddleware.RequirePermission("Master Data", "Category", "read")).Get("/", categoryHandler.ListCategories)
+		r.With(permMiddleware.RequirePermission("Master Data", "Category", "read")).Get("/product-counts", categoryHandler.GetProductCounts)
 		r.With(permMiddleware.RequirePermission("Master Data", "Category", "read")).Get("/{id}", categoryHandler.GetCategory)
 		r.With(permMiddleware.RequirePermission("Master Data", "Category", "create")).Post("/", categoryHandler.CreateCategory)
 		r.With(permMiddleware.RequirePermission("Master Data", "Category", "update")).Put("/{id}", categoryHandler.UpdateCategory)
@@ -188,6 +189,28 @@ func TestListCategories_WithSort_OrdersResults(t *testing.T) {
 	assert.Equal(t, "Zebra", last["name"])
 }
 
+func TestGetCategoryProductCounts_CountsActiveProducts_Returns200(t *testing.T) {
+	router, db, _, _ := setupCategoryTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupCategoryTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	cat := createTestCategoryInDB(t, db, "Snacks", "")
+	testutil.CreateTestProduct(t, db, func(p *models.Product) { p.CategoryID = cat.ID })
+	testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.CategoryID = cat.ID
+		p.Status = "inactive"
+	})
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/categories/product-counts", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(1), data[fmt.Sprintf("%d", cat.ID)])
+}
+
 func TestGetCategory_Exists_Returns200(t *testing.T) {
 	router, db, _, _ := setupCategoryTestRouter(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -390,3 +413,83 @@ func TestCreateCategory_SuperAdmin_Returns201(t *testing.T) {
 
 	assert.Equal(t, http.StatusCreated, rr.Code)
 }
+
+func TestListCategories_Tree_ReturnsNestedCategories(t *testing.T) {
+	router, db, _, _ := setupCategoryTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupCategoryTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	clothing := createTestCategoryInDB(t, db, "Clothing", "")
+	men := &models.Category{Name: "Men", ParentID: &clothing.ID}
+	require.NoError(t, db.Create(men).Error)
+	require.NoError(t, db.Create(&models.Category{Name: "Shirts", ParentID: &men.ID}).Error)
+	createTestCategoryInDB(t, db, "Food", "")
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/categories?tree=true", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	roots, ok := response["data"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, roots, 2)
+
+	root := roots[0].(map[string]interface{})
+	assert.Equal(t, "Clothing", root["name"])
+	children := root["children"].([]interface{})
+	require.Len(t, children, 1)
+	child := children[0].(map[string]interface{})
+	assert.Equal(t, "Men", child["name"])
+	grandchildren := child["children"].([]interface{})
+	require.Len(t, grandchildren, 1)
+	assert.Equal(t, "Shirts", grandchildren[0].(map[string]interface{})["name"])
+}
+
+func TestUpdateCategory_DescendantAsParent_Returns400(t *testing.T) {
+	router, db, _, _ := setupCategoryTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupCategoryTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	parent := createTestCategoryInDB(t, db, "Clothing", "")
+	child := &models.Category{Name: "Men", ParentID: &parent.ID}
+	require.NoError(t, db.Create(child).Error)
+
+	body := fmt.Sprintf(`{"name":"Clothing","parentId":%d}`, child.ID)
+	req := testutil.AuthenticatedRequest(t, "PUT", fmt.Sprintf("/api/v1/categories/%d", parent.ID), strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "CATEGORY_CYCLE", response["code"])
+}
+
+func TestDeleteCategory_WithChildren_Returns409(t *testing.T) {
+	router, db, _, _ := setupCategoryTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupCategoryTestUserWithPermission(t, db, []string{"read", "delete"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	parent := createTestCategoryInDB(t, db, "Clothing", "")
+	require.NoError(t, db.Create(&models.Category{Name: "Men", ParentID: &parent.ID}).Error)
+
+	req := testutil.AuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/v1/categories/%d", parent.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	var count int64
+	db.Model(&models.Category{}).Where("id = ?", parent.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
@@ -3,11 +3,13 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/lib/pq"
+	"github.com/pointofsale/backend/middleware"
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/utils"
 	"github.com/redis/go-redis/v9"
@@ -263,17 +265,8 @@ func (h *PermissionHandler) UpdateRolePermissions(w http.ResponseWriter, r *http
 
 // invalidatePermissionCache removes cached permissions for all users with the given role
 func (h *PermissionHandler) invalidatePermissionCache(roleID uint) {
-	ctx := context.Background()
-
-	// Get all users with this role
-	var userRoles []struct {
-		UserID uint
-	}
-	h.db.Table("user_roles").Where("role_id = ?", roleID).Select("user_id").Find(&userRoles)
-
-	// Delete cache for each user
-	for _, ur := range userRoles {
-		h.redis.Del(ctx, "perms:"+strconv.FormatUint(uint64(ur.UserID), 10))
+	if err := middleware.InvalidateRolePermissionCache(context.Background(), h.db, h.redis, roleID); err != nil {
+		slog.Error("failed to invalidate permission cache", "role_id", roleID, "error", err)
 	}
 }
 
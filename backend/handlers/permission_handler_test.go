@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"github.com/alicebob/miniredis/v2"
 	"github.com/go-chi/chi/v5"
 	"github.com/lib/pq"
+	"github.com/pointofsale/backend/middleware"
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/testutil"
 	"github.com/redis/go-redis/v9"
@@ -375,3 +377,53 @@ func TestUpdateRolePermissions_InvalidJSON_Returns400(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
+
+// TestUpdateRolePermissions_RevokedAction_DeniedOnNextRequest verifies a revocation takes effect without waiting for the cache TTL
+func TestUpdateRolePermissions_RevokedAction_DeniedOnNextRequest(t *testing.T) {
+	router, db, rdb := setupPermissionTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	perm := testutil.CreateTestPermission(t, db, func(p *models.Permission) {
+		p.Module = "Master Data"
+		p.Feature = "Product"
+		p.Actions = pq.StringArray{"read", "create", "update", "delete"}
+	})
+	role := testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = "Manager"
+		r.IsSystem = false
+	})
+	require.NoError(t, db.Create(&models.RolePermission{
+		RoleID:       role.ID,
+		PermissionID: perm.ID,
+		Actions:      pq.StringArray{"read", "delete"},
+	}).Error)
+	user := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Roles = []models.Role{*role}
+	})
+
+	permMiddleware := middleware.NewPermissionMiddleware(db, rdb)
+	protected := permMiddleware.RequirePermission("Master Data", "Product", "delete")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	deleteProduct := func() int {
+		req := httptest.NewRequest("DELETE", "/api/v1/products/1", nil)
+		ctx := context.WithValue(req.Context(), middleware.UserIDKey, user.ID)
+		ctx = context.WithValue(ctx, middleware.IsSuperAdminKey, false)
+		rr := httptest.NewRecorder()
+		protected.ServeHTTP(rr, req.WithContext(ctx))
+		return rr.Code
+	}
+
+	// Allowed, and the permissions are now cached
+	require.Equal(t, http.StatusOK, deleteProduct())
+	require.Equal(t, int64(1), rdb.Exists(context.Background(), fmt.Sprintf("perms:%d", user.ID)).Val())
+
+	body := fmt.Sprintf(`{"permissions": [{"permissionId": %d, "actions": ["read"]}]}`, perm.ID)
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/roles/%d/permissions", role.ID), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	assert.Equal(t, http.StatusForbidden, deleteProduct())
+}
@@ -2,10 +2,15 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/pointofsale/backend/middleware"
 	"github.com/pointofsale/backend/repositories"
 	"github.com/pointofsale/backend/services"
 	"github.com/pointofsale/backend/utils"
@@ -41,15 +46,22 @@ func (h *POHandler) ListPOs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse optional filters
-	status := r.URL.Query().Get("status")
-	var supplierID uint
+	filter := repositories.POListFilter{Status: r.URL.Query().Get("status")}
 	if s := r.URL.Query().Get("supplierId"); s != "" {
 		if id, err := strconv.ParseUint(s, 10, 64); err == nil {
-			supplierID = uint(id)
+			filter.SupplierID = uint(id)
+		}
+	}
+	if s := r.URL.Query().Get("overdue"); s != "" {
+		overdue, err := strconv.ParseBool(s)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid overdue filter, expected true or false", "VALIDATION_ERROR")
+			return
 		}
+		filter.Overdue = overdue
 	}
 
-	pos, total, statusCounts, err := h.poService.ListPOs(params, status, supplierID)
+	pos, total, statusCounts, err := h.poService.ListPOs(params, filter)
 	if err != nil {
 		utils.Error(w, http.StatusInternalServerError, "Failed to fetch purchase orders", "INTERNAL_ERROR")
 		return
@@ -92,6 +104,37 @@ func (h *POHandler) GetPO(w http.ResponseWriter, r *http.Request) {
 	utils.Success(w, http.StatusOK, "", po)
 }
 
+// GetPOPDF handles GET /api/v1/purchase-orders/{id}/pdf
+func (h *POHandler) GetPOPDF(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid purchase order ID", "VALIDATION_ERROR")
+		return
+	}
+
+	pdf, err := h.poService.GeneratePDF(uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to generate purchase order PDF"
+		code := "INTERNAL_ERROR"
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			if serviceErr.Err == services.ErrNotFound {
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="purchase-order-%d.pdf"`, id))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(pdf)
+}
+
 // CreatePO handles POST /api/v1/purchase-orders
 func (h *POHandler) CreatePO(w http.ResponseWriter, r *http.Request) {
 	var input services.CreatePOInput
@@ -99,6 +142,7 @@ func (h *POHandler) CreatePO(w http.ResponseWriter, r *http.Request) {
 		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
 		return
 	}
+	input.CreatedBy = middleware.GetUserID(r.Context())
 
 	po, err := h.poService.CreatePO(input)
 	if err != nil {
@@ -204,14 +248,16 @@ func (h *POHandler) UpdatePOStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var body struct {
-		Status string `json:"status"`
+		Status        string  `json:"status"`
+		ExpectedDate  *string `json:"expectedDate"`
+		EmailSupplier bool    `json:"emailSupplier"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
 		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
 		return
 	}
 
-	po, err := h.poService.UpdatePOStatus(uint(id), body.Status)
+	po, err := h.poService.UpdatePOStatus(uint(id), body.Status, body.ExpectedDate, body.EmailSupplier)
 	if err != nil {
 		status := http.StatusInternalServerError
 		message := "Failed to update purchase order status"
@@ -235,7 +281,106 @@ func (h *POHandler) UpdatePOStatus(w http.ResponseWriter, r *http.Request) {
 	utils.Success(w, http.StatusOK, "Purchase order status updated successfully", po)
 }
 
-// ReceivePO handles POST /api/v1/purchase-orders/{id}/receive
+// EmailPO handles POST /api/v1/purchase-orders/{id}/email
+func (h *POHandler) EmailPO(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid purchase order ID", "VALIDATION_ERROR")
+		return
+	}
+
+	if err := h.poService.SendToSupplier(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to email purchase order"
+		code := "INTERNAL_ERROR"
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			switch serviceErr.Err {
+			case services.ErrValidation:
+				status = http.StatusBadRequest
+			case services.ErrNotFound:
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Purchase order emailed to supplier", nil)
+}
+
+// ApprovePO handles POST /api/v1/purchase-orders/{id}/approve
+func (h *POHandler) ApprovePO(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid purchase order ID", "VALIDATION_ERROR")
+		return
+	}
+
+	po, err := h.poService.Approve(uint(id), middleware.GetUserID(r.Context()))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to approve purchase order"
+		code := "INTERNAL_ERROR"
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			switch serviceErr.Err {
+			case services.ErrValidation:
+				status = http.StatusBadRequest
+			case services.ErrNotFound:
+				status = http.StatusNotFound
+			case services.ErrForbidden:
+				status = http.StatusForbidden
+			case services.ErrConflict:
+				status = http.StatusConflict
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Purchase order approved successfully", po)
+}
+
+// MarkPOPaid handles POST /api/v1/purchase-orders/{id}/pay
+func (h *POHandler) MarkPOPaid(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid purchase order ID", "VALIDATION_ERROR")
+		return
+	}
+
+	po, err := h.poService.MarkPaid(uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to mark purchase order as paid"
+		code := "INTERNAL_ERROR"
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			switch serviceErr.Err {
+			case services.ErrValidation:
+				status = http.StatusBadRequest
+			case services.ErrNotFound:
+				status = http.StatusNotFound
+			case services.ErrConflict:
+				status = http.StatusConflict
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Purchase order marked as paid", po)
+}
+
+// ReceivePO handles POST /api/v1/purchase-orders/{id}/receive. An optional
+// Receive-Token header makes resubmissions return the first result.
 func (h *POHandler) ReceivePO(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseUint(idStr, 10, 64)
@@ -250,7 +395,10 @@ func (h *POHandler) ReceivePO(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	po, err := h.poService.ReceivePO(uint(id), input)
+	input.ReceiveToken = r.Header.Get("Receive-Token")
+	input.ReceivedBy = middleware.GetUserID(r.Context())
+
+	po, err := h.poService.ReceivePO(r.Context(), uint(id), input)
 	if err != nil {
 		status := http.StatusInternalServerError
 		message := "Failed to receive purchase order"
@@ -265,6 +413,9 @@ func (h *POHandler) ReceivePO(w http.ResponseWriter, r *http.Request) {
 				status = http.StatusNotFound
 			case services.ErrForbidden:
 				status = http.StatusForbidden
+			case services.ErrBusy:
+				status = http.StatusServiceUnavailable
+				w.Header().Set("Retry-After", "1")
 			}
 		}
 		utils.Error(w, status, message, code)
@@ -274,6 +425,111 @@ func (h *POHandler) ReceivePO(w http.ResponseWriter, r *http.Request) {
 	utils.Success(w, http.StatusOK, "Purchase order received successfully", po)
 }
 
+// GetLastPOForSupplier handles GET /api/v1/purchase-orders/last?supplierId=
+func (h *POHandler) GetLastPOForSupplier(w http.ResponseWriter, r *http.Request) {
+	supplierID, err := strconv.ParseUint(r.URL.Query().Get("supplierId"), 10, 64)
+	if err != nil || supplierID == 0 {
+		utils.Error(w, http.StatusBadRequest, "supplierId is required", "VALIDATION_ERROR")
+		return
+	}
+
+	suggestion, err := h.poService.LastPOForSupplier(uint(supplierID))
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, "Failed to fetch last purchase order", "INTERNAL_ERROR")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", suggestion)
+}
+
+// GetNextPONumber handles GET /api/v1/purchase-orders/next-number
+func (h *POHandler) GetNextPONumber(w http.ResponseWriter, r *http.Request) {
+	next, err := h.poService.NextPONumber()
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, "Failed to generate PO number", "INTERNAL_ERROR")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", next)
+}
+
+// GetLowStockBySupplier handles GET /api/v1/purchase-orders/low-stock-by-supplier
+func (h *POHandler) GetLowStockBySupplier(w http.ResponseWriter, r *http.Request) {
+	result, err := h.poService.LowStockBySupplier()
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, "Failed to fetch low-stock items", "INTERNAL_ERROR")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", result)
+}
+
+// DraftFromLowStock handles POST /api/v1/purchase-orders/low-stock-by-supplier/draft
+func (h *POHandler) DraftFromLowStock(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		SupplierID uint                         `json:"supplierId"`
+		Items      []services.CreatePOItemInput `json:"items"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+
+	po, err := h.poService.DraftFromSuggestions(input.SupplierID, input.Items, middleware.GetUserID(r.Context()))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to create purchase order"
+		code := "INTERNAL_ERROR"
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			if serviceErr.Err == services.ErrValidation {
+				status = http.StatusBadRequest
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, "Draft purchase order created successfully", po)
+}
+
+// GetDiscrepancies handles GET /api/v1/purchase-orders/discrepancies
+// Optional query params: from, to (YYYY-MM-DD). Defaults to the last 30 days.
+func (h *POHandler) GetDiscrepancies(w http.ResponseWriter, r *http.Request) {
+	var err error
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		to, err = time.Parse("2006-01-02", toStr)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid 'to' date, expected YYYY-MM-DD", "VALIDATION_ERROR")
+			return
+		}
+	}
+
+	from := to.AddDate(0, 0, -29)
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		from, err = time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid 'from' date, expected YYYY-MM-DD", "VALIDATION_ERROR")
+			return
+		}
+	}
+
+	report, err := h.poService.Discrepancies(from, to)
+	if err != nil {
+		if serviceErr, ok := err.(*services.ServiceError); ok && serviceErr.Err == services.ErrValidation {
+			utils.Error(w, http.StatusBadRequest, serviceErr.Message, serviceErr.Code)
+			return
+		}
+		utils.Error(w, http.StatusInternalServerError, "Failed to fetch receiving discrepancies", "INTERNAL_ERROR")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", report)
+}
+
 // GetProductsForPO handles GET /api/v1/purchase-orders/products
 func (h *POHandler) GetProductsForPO(w http.ResponseWriter, r *http.Request) {
 	var supplierID uint
@@ -294,3 +550,127 @@ func (h *POHandler) GetProductsForPO(w http.ResponseWriter, r *http.Request) {
 		"data": products,
 	})
 }
+
+// UploadAttachment handles POST /api/v1/purchase-orders/{id}/attachments
+// The file is sent as multipart/form-data in the "file" field.
+func (h *POHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid purchase order ID", "VALIDATION_ERROR")
+		return
+	}
+
+	maxBytes := h.poService.AttachmentMaxBytes()
+	// Leave headroom for multipart boundaries and headers.
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+(1<<20))
+	if err := r.ParseMultipartForm(maxBytes); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.Error(w, http.StatusRequestEntityTooLarge, "File exceeds the maximum allowed size", "ATTACHMENT_TOO_LARGE")
+			return
+		}
+		utils.Error(w, http.StatusBadRequest, "Invalid multipart form", "VALIDATION_ERROR")
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "File is required", "VALIDATION_ERROR")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Failed to read file", "VALIDATION_ERROR")
+		return
+	}
+
+	attachment, err := h.poService.UploadAttachment(uint(id), header.Filename, data, middleware.GetUserID(r.Context()))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to upload attachment"
+		code := "INTERNAL_ERROR"
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			switch serviceErr.Err {
+			case services.ErrValidation:
+				status = http.StatusBadRequest
+			case services.ErrTooLarge:
+				status = http.StatusRequestEntityTooLarge
+			case services.ErrNotFound:
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, "Attachment uploaded successfully", attachment)
+}
+
+// ListAttachments handles GET /api/v1/purchase-orders/{id}/attachments
+func (h *POHandler) ListAttachments(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid purchase order ID", "VALIDATION_ERROR")
+		return
+	}
+
+	attachments, err := h.poService.ListAttachments(uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to list attachments"
+		code := "INTERNAL_ERROR"
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			if serviceErr.Err == services.ErrNotFound {
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]interface{}{
+		"data": attachments,
+	})
+}
+
+// DeleteAttachment handles DELETE /api/v1/purchase-orders/{id}/attachments/{attachmentId}
+func (h *POHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid purchase order ID", "VALIDATION_ERROR")
+		return
+	}
+	attachmentID, err := strconv.ParseUint(chi.URLParam(r, "attachmentId"), 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid attachment ID", "VALIDATION_ERROR")
+		return
+	}
+
+	err = h.poService.DeleteAttachment(uint(id), uint(attachmentID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to delete attachment"
+		code := "INTERNAL_ERROR"
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			if serviceErr.Err == services.ErrNotFound {
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]string{
+		"message": "Attachment deleted successfully",
+	})
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -44,7 +47,12 @@ func setupPOTestRouter(t *testing.T) (chi.Router, *gorm.DB, *redis.Client, *conf
 	poRepo := repositories.NewPORepository(db)
 	stockRepo := repositories.NewStockMovementRepository(db)
 	seqSvc := services.NewSequenceService(db)
-	poSvc := services.NewPOService(db, poRepo, stockRepo, seqSvc)
+	poSvc := services.NewPOService(db, poRepo, stockRepo, seqSvc, services.POConfig{
+		Storage:         &memoryFileStorage{},
+		Mailer:          &memoryMailer{},
+		Redis:           rdb,
+		ReceiveTokenTTL: time.Minute,
+	})
 	poHandler := NewPOHandler(poSvc)
 
 	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTAccessSecret, rdb, userRepo)
@@ -55,17 +63,47 @@ func setupPOTestRouter(t *testing.T) (chi.Router, *gorm.DB, *redis.Client, *conf
 		r.Use(authMiddleware.Authenticate)
 		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/", poHandler.ListPOs)
 		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/products", poHandler.GetProductsForPO)
+		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/last", poHandler.GetLastPOForSupplier)
+		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "create")).Get("/next-number", poHandler.GetNextPONumber)
+		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/discrepancies", poHandler.GetDiscrepancies)
+		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/low-stock-by-supplier", poHandler.GetLowStockBySupplier)
+		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "create")).Post("/low-stock-by-supplier/draft", poHandler.DraftFromLowStock)
 		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/{id}", poHandler.GetPO)
+		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/{id}/pdf", poHandler.GetPOPDF)
 		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "create")).Post("/", poHandler.CreatePO)
 		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Put("/{id}", poHandler.UpdatePO)
 		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "delete")).Delete("/{id}", poHandler.DeletePO)
 		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Patch("/{id}/status", poHandler.UpdatePOStatus)
+		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Post("/{id}/approve", poHandler.ApprovePO)
+		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Post("/{id}/email", poHandler.EmailPO)
+		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/{id}/attachments", poHandler.ListAttachments)
+		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Post("/{id}/attachments", poHandler.UploadAttachment)
+		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Delete("/{id}/attachments/{attachmentId}", poHandler.DeleteAttachment)
 		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Post("/{id}/receive", poHandler.ReceivePO)
+		r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Post("/{id}/pay", poHandler.MarkPOPaid)
 	})
 
 	return r, db, rdb, cfg
 }
 
+// memoryFileStorage is an in-memory stand-in for object storage.
+type memoryFileStorage struct{}
+
+func (m *memoryFileStorage) UploadImage(ctx context.Context, objectKey string, data []byte, contentType string) (string, error) {
+	return "http://files.local/" + objectKey, nil
+}
+
+func (m *memoryFileStorage) Delete(ctx context.Context, objectKey string) error {
+	return nil
+}
+
+// memoryMailer accepts every purchase order email without sending it.
+type memoryMailer struct{}
+
+func (m *memoryMailer) SendPurchaseOrder(supplierEmail, poNumber string, pdfBytes []byte) error {
+	return nil
+}
+
 func setupPOTestUserWithPermission(t *testing.T, db *gorm.DB, actions []string) *models.User {
 	t.Helper()
 
@@ -137,6 +175,46 @@ func TestListPOs_Returns200WithStatusCounts(t *testing.T) {
 	assert.Contains(t, response, "statusCounts")
 }
 
+func TestListPOs_OverdueFilter_ReturnsOverdueSentPOs(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	overdue := createDraftPO(t, db, supplier, product)
+	require.NoError(t, db.Model(overdue).Updates(map[string]interface{}{"status": "sent", "expected_date": "2020-01-01"}).Error)
+	onTime := createDraftPO(t, db, supplier, product)
+	require.NoError(t, db.Model(onTime).Updates(map[string]interface{}{"status": "sent", "expected_date": "2999-01-01"}).Error)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/purchase-orders?overdue=true", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	data := response["data"].([]interface{})
+	require.Len(t, data, 1)
+	po := data[0].(map[string]interface{})
+	assert.Equal(t, float64(overdue.ID), po["id"])
+	assert.Equal(t, true, po["overdue"])
+}
+
+func TestListPOs_InvalidOverdueFilter_Returns400(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/purchase-orders?overdue=soon", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
 func TestListPOs_FilterByStatus_Returns200(t *testing.T) {
 	router, db, _, _ := setupPOTestRouter(t)
 
@@ -193,6 +271,38 @@ func TestGetPO_NotFound_Returns404(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rr.Code)
 }
 
+func TestGetPOPDF_Returns200WithPDF(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	po := createDraftPO(t, db, supplier, product)
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/purchase-orders/%d/pdf", po.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/pdf", rr.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rr.Body.String(), "%PDF-"))
+}
+
+func TestGetPOPDF_NotFound_Returns404(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/purchase-orders/99999/pdf", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
 func TestCreatePO_ValidBody_Returns201(t *testing.T) {
 	router, db, _, _ := setupPOTestRouter(t)
 
@@ -229,6 +339,224 @@ func TestCreatePO_ValidBody_Returns201(t *testing.T) {
 	assert.Equal(t, "draft", data["status"])
 }
 
+func TestGetNextPONumber_StableUntilCreate(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+
+	peek := func() string {
+		req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/purchase-orders/next-number", nil, token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+		number, _ := data["number"].(string)
+		return number
+	}
+
+	next := peek()
+	require.NotEmpty(t, next)
+	assert.Equal(t, next, peek())
+
+	body := fmt.Sprintf(`{
+		"supplierId": %d,
+		"date": "2026-01-15",
+		"items": [
+			{"productId": %d, "variantId": "%s", "unitId": %d, "orderedQty": 5, "price": 10000}
+		]
+	}`, supplier.ID, product.ID, variant.ID, unit.ID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/purchase-orders", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.Equal(t, next, data["poNumber"])
+	assert.NotEqual(t, next, peek())
+}
+
+func TestGetNextPONumber_WithoutCreatePermission_Returns403(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/purchase-orders/next-number", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCreatePO_WithExpectedDate_AppearsInDetail(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+
+	body := fmt.Sprintf(`{
+		"supplierId": %d,
+		"date": "2026-01-15",
+		"expectedDate": "2026-01-20",
+		"items": [
+			{"productId": %d, "variantId": "%s", "unitId": %d, "orderedQty": 5, "price": 10000}
+		]
+	}`, supplier.ID, product.ID, variant.ID, unit.ID)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/purchase-orders", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	created := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+
+	getReq := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/purchase-orders/%d", int(created["id"].(float64))), nil, token)
+	getRR := httptest.NewRecorder()
+	router.ServeHTTP(getRR, getReq)
+
+	data := testutil.AssertSuccessResponse(t, getRR, http.StatusOK)
+	require.NotNil(t, data["expectedDate"])
+	assert.Contains(t, data["expectedDate"], "2026-01-20")
+}
+
+func TestCreatePO_ExpectedDateBeforeOrderDate_Returns400(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+
+	body := fmt.Sprintf(`{
+		"supplierId": %d,
+		"date": "2026-01-15",
+		"expectedDate": "2026-01-10",
+		"items": [
+			{"productId": %d, "variantId": "%s", "unitId": %d, "orderedQty": 5, "price": 10000}
+		]
+	}`, supplier.ID, product.ID, variant.ID, unit.ID)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/purchase-orders", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "expected date cannot be before the order date")
+}
+
+func TestCreatePO_NegativePrice_Returns400WithField(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+
+	body := fmt.Sprintf(`{
+		"supplierId": %d,
+		"date": "2026-01-15",
+		"items": [
+			{"productId": %d, "variantId": "%s", "unitId": %d, "orderedQty": 5, "price": 10000},
+			{"productId": %d, "variantId": "%s", "unitId": %d, "orderedQty": 2, "price": -500}
+		]
+	}`, supplier.ID, product.ID, variant.ID, unit.ID, product.ID, variant.ID, unit.ID)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/purchase-orders", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "items[1].price must not be negative")
+}
+
+func TestCreatePO_ZeroQuantity_Returns400WithField(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+
+	body := fmt.Sprintf(`{
+		"supplierId": %d,
+		"date": "2026-01-15",
+		"items": [
+			{"productId": %d, "variantId": "%s", "unitId": %d, "orderedQty": 0, "price": 10000}
+		]
+	}`, supplier.ID, product.ID, product.Variants[0].ID, product.Units[0].ID)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/purchase-orders", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "items[0].orderedQty must be greater than zero")
+}
+
+func TestCreatePO_SeveralInvalidReferences_Returns400ListingAll(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+
+	body := fmt.Sprintf(`{
+		"supplierId": %d,
+		"date": "2026-01-15",
+		"items": [
+			{"productId": 999999, "variantId": "%s", "unitId": %d, "orderedQty": 5, "price": 10000},
+			{"productId": %d, "variantId": "%s", "unitId": 999999, "orderedQty": 2, "price": 10000}
+		]
+	}`, supplier.ID, variant.ID, product.Units[0].ID, product.ID, variant.ID)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/purchase-orders", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "items[0]: product 999999 not found")
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "items[1]: unit 999999 not found")
+}
+
+func TestCreatePO_VariantFromOtherProduct_Returns400(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	other := testutil.CreateTestProduct(t, db)
+
+	body := fmt.Sprintf(`{
+		"supplierId": %d,
+		"date": "2026-01-15",
+		"items": [
+			{"productId": %d, "variantId": "%s", "unitId": %d, "orderedQty": 5, "price": 10000}
+		]
+	}`, supplier.ID, product.ID, other.Variants[0].ID, product.Units[0].ID)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/purchase-orders", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest,
+		fmt.Sprintf("items[0]: variant %s does not belong to %s", other.Variants[0].ID, product.Name))
+}
+
 func TestCreatePO_InvalidSupplier_Returns400(t *testing.T) {
 	router, db, _, _ := setupPOTestRouter(t)
 
@@ -406,7 +734,7 @@ func TestUpdatePOStatus_InvalidTransition_Returns400(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
 
-func TestReceivePO_ValidBody_Returns200_UpdatesStock(t *testing.T) {
+func TestUpdatePOStatus_SentToCancelled_Returns200_LeavesStock(t *testing.T) {
 	router, db, _, _ := setupPOTestRouter(t)
 
 	user := setupPOTestUserWithPermission(t, db, []string{"read", "create", "update"})
@@ -414,44 +742,314 @@ func TestReceivePO_ValidBody_Returns200_UpdatesStock(t *testing.T) {
 
 	supplier := testutil.CreateTestSupplier(t, db)
 	product := testutil.CreateTestProduct(t, db)
-	variant := product.Variants[0]
-	initialStock := variant.CurrentStock
-
 	po := createDraftPO(t, db, supplier, product)
-
-	// Move to sent status
 	require.NoError(t, db.Model(po).Update("status", "sent").Error)
 
-	// Get item ID
-	loadedPO := &models.PurchaseOrder{}
-	require.NoError(t, db.Preload("Items").First(loadedPO, po.ID).Error)
-	require.NotEmpty(t, loadedPO.Items)
-	itemID := loadedPO.Items[0].ID
-
-	body := fmt.Sprintf(`{
-		"receivedDate": "2026-01-20",
-		"paymentMethod": "cash",
-		"items": [
-			{
-				"itemId": "%s",
-				"receivedQty": 8,
-				"receivedPrice": 14000,
-				"isVerified": true
-			}
-		]
-	}`, itemID)
-
-	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/receive", po.ID), strings.NewReader(body), token)
+	body := `{"status": "cancelled"}`
+	req := testutil.AuthenticatedRequest(t, "PATCH", fmt.Sprintf("/api/v1/purchase-orders/%d/status", po.ID), strings.NewReader(body), token)
 	rr := httptest.NewRecorder()
 	router.ServeHTTP(rr, req)
 
-	assert.Equal(t, http.StatusOK, rr.Code)
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, "cancelled", data["status"])
+
+	var variant models.ProductVariant
+	require.NoError(t, db.First(&variant, "id = ?", product.Variants[0].ID).Error)
+	assert.Equal(t, product.Variants[0].CurrentStock, variant.CurrentStock)
+	var movements int64
+	require.NoError(t, db.Model(&models.StockMovement{}).Where("variant_id = ?", variant.ID).Count(&movements).Error)
+	assert.Equal(t, int64(0), movements)
+
+	// The cancelled PO is counted in its own bucket
+	req = testutil.AuthenticatedRequest(t, "GET", "/api/v1/purchase-orders", nil, token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	counts := response["statusCounts"].(map[string]interface{})
+	assert.Equal(t, float64(1), counts["cancelled"])
+	assert.Equal(t, float64(0), counts["sent"])
+}
+
+func TestUpdatePOStatus_ReceivedToCancelled_Returns400(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+
+	for _, current := range []string{"received", "completed"} {
+		po := createDraftPO(t, db, supplier, product)
+		require.NoError(t, db.Model(po).Update("status", current).Error)
+
+		body := `{"status": "cancelled"}`
+		req := testutil.AuthenticatedRequest(t, "PATCH", fmt.Sprintf("/api/v1/purchase-orders/%d/status", po.ID), strings.NewReader(body), token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+
+		testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "invalid status transition")
+	}
+}
+
+func TestUpdatePOStatus_EmailSupplierFails_StillReturns200(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	// The supplier has no email address, so emailing fails after the status change
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	po := createDraftPO(t, db, supplier, product)
+
+	body := `{"status": "sent", "emailSupplier": true}`
+	req := testutil.AuthenticatedRequest(t, "PATCH", fmt.Sprintf("/api/v1/purchase-orders/%d/status", po.ID), strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, "sent", data["status"])
+}
+
+func TestEmailPO_SentPO_Returns200(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db, func(s *models.Supplier) {
+		s.Email = "orders@supplier.test"
+	})
+	product := testutil.CreateTestProduct(t, db)
+	po := createDraftPO(t, db, supplier, product)
+	require.NoError(t, db.Model(po).Update("status", "sent").Error)
+
+	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/email", po.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestEmailPO_SupplierWithoutEmail_Returns400(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	po := createDraftPO(t, db, supplier, product)
+	require.NoError(t, db.Model(po).Update("status", "sent").Error)
+
+	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/email", po.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "no email")
+}
+
+func TestReceivePO_ValidBody_Returns200_UpdatesStock(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	initialStock := variant.CurrentStock
+
+	po := createDraftPO(t, db, supplier, product)
+
+	// Move to sent status
+	require.NoError(t, db.Model(po).Update("status", "sent").Error)
+
+	// Get item ID
+	loadedPO := &models.PurchaseOrder{}
+	require.NoError(t, db.Preload("Items").First(loadedPO, po.ID).Error)
+	require.NotEmpty(t, loadedPO.Items)
+	itemID := loadedPO.Items[0].ID
+
+	body := fmt.Sprintf(`{
+		"receivedDate": "2026-01-20",
+		"paymentMethod": "cash",
+		"items": [
+			{
+				"itemId": "%s",
+				"receivedQty": 8,
+				"receivedPrice": 14000,
+				"isVerified": true
+			}
+		]
+	}`, itemID)
+
+	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/receive", po.ID), strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
 
 	// Verify stock updated
 	var updatedVariant models.ProductVariant
 	require.NoError(t, db.First(&updatedVariant, "id = ?", variant.ID).Error)
 	// unit.ToBaseUnit = 1, so stockDelta = 8 * 1 = 8
 	assert.Equal(t, initialStock+8, updatedVariant.CurrentStock)
+
+	var movement models.StockMovement
+	require.NoError(t, db.Where("movement_type = ? AND reference_id = ?", "purchase_receive", po.ID).First(&movement).Error)
+	require.NotNil(t, movement.CreatedBy)
+	assert.Equal(t, user.ID, *movement.CreatedBy)
+}
+
+func TestReceivePO_TwoPartialDeliveries_CompletesReceipt(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	po := createDraftPO(t, db, supplier, product)
+	require.NoError(t, db.Model(po).Update("status", "sent").Error)
+
+	loadedPO := &models.PurchaseOrder{}
+	require.NoError(t, db.Preload("Items").First(loadedPO, po.ID).Error)
+	itemID := loadedPO.Items[0].ID
+
+	receive := func(date string, qty int) map[string]interface{} {
+		body := fmt.Sprintf(`{
+			"receivedDate": "%s",
+			"paymentMethod": "cash",
+			"items": [{"itemId": "%s", "receivedQty": %d, "receivedPrice": 15000, "isVerified": true}]
+		}`, date, itemID, qty)
+		req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/receive", po.ID), strings.NewReader(body), token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	}
+
+	// Ordered 10: the first delivery brings 4
+	data := receive("2026-01-20", 4)
+	assert.Equal(t, "partially_received", data["status"])
+	assert.Equal(t, float64(6*15000), data["outstanding"])
+
+	data = receive("2026-01-25", 6)
+	assert.Equal(t, "received", data["status"])
+	assert.Equal(t, float64(0), data["outstanding"])
+
+	var reloaded models.PurchaseOrder
+	require.NoError(t, db.Preload("Items").First(&reloaded, po.ID).Error)
+	require.Len(t, reloaded.Items, 1)
+	assert.Equal(t, 10, *reloaded.Items[0].ReceivedQty)
+
+	var updatedVariant models.ProductVariant
+	require.NoError(t, db.First(&updatedVariant, "id = ?", variant.ID).Error)
+	assert.Equal(t, variant.CurrentStock+10, updatedVariant.CurrentStock)
+}
+
+func TestReceivePO_TaxAndDiscount_StoresGrandTotal(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	po := createDraftPO(t, db, supplier, product)
+	require.NoError(t, db.Model(po).Update("status", "sent").Error)
+
+	loadedPO := &models.PurchaseOrder{}
+	require.NoError(t, db.Preload("Items").First(loadedPO, po.ID).Error)
+	itemID := loadedPO.Items[0].ID
+
+	body := fmt.Sprintf(`{
+		"receivedDate": "2026-01-20",
+		"paymentMethod": "cash",
+		"taxPercent": 11,
+		"discountAmount": 10000,
+		"items": [{"itemId": "%s", "receivedQty": 10, "receivedPrice": 15000, "isVerified": true}]
+	}`, itemID)
+	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/receive", po.ID), strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	// Subtotal 150000, less 10000 discount, plus 11% tax on 140000
+	req = testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/purchase-orders/%d", po.ID), nil, token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(150000), data["subtotal"])
+	assert.Equal(t, float64(11), data["taxPercent"])
+	assert.Equal(t, float64(10000), data["discountAmount"])
+	assert.Equal(t, float64(155400), data["grandTotal"])
+
+	var reloaded models.PurchaseOrder
+	require.NoError(t, db.First(&reloaded, po.ID).Error)
+	require.NotNil(t, reloaded.GrandTotal)
+	assert.Equal(t, 155400.0, *reloaded.GrandTotal)
+}
+
+func TestReceivePO_SameReceiveToken_AddsStockOnce(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	po := createDraftPO(t, db, supplier, product)
+	require.NoError(t, db.Model(po).Update("status", "sent").Error)
+
+	loadedPO := &models.PurchaseOrder{}
+	require.NoError(t, db.Preload("Items").First(loadedPO, po.ID).Error)
+	itemID := loadedPO.Items[0].ID
+
+	receive := func(receiveToken string) map[string]interface{} {
+		body := fmt.Sprintf(`{
+			"receivedDate": "2026-01-20",
+			"paymentMethod": "cash",
+			"items": [{"itemId": "%s", "receivedQty": 4, "receivedPrice": 15000, "isVerified": true}]
+		}`, itemID)
+		req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/receive", po.ID), strings.NewReader(body), token)
+		req.Header.Set("Receive-Token", receiveToken)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	}
+	stockAndMovements := func() (int, int64) {
+		var updatedVariant models.ProductVariant
+		require.NoError(t, db.First(&updatedVariant, "id = ?", variant.ID).Error)
+		var movements int64
+		require.NoError(t, db.Model(&models.StockMovement{}).
+			Where("reference_type = ? AND reference_id = ?", "purchase_order", po.ID).
+			Count(&movements).Error)
+		return updatedVariant.CurrentStock, movements
+	}
+
+	first := receive("delivery-1")
+	assert.Equal(t, "partially_received", first["status"])
+
+	// A resubmission returns the first result without receiving again
+	replay := receive("delivery-1")
+	assert.Equal(t, first, replay)
+	stock, movements := stockAndMovements()
+	assert.Equal(t, variant.CurrentStock+4, stock)
+	assert.Equal(t, int64(1), movements)
+
+	// A new token is a new delivery
+	receive("delivery-2")
+	stock, movements = stockAndMovements()
+	assert.Equal(t, variant.CurrentStock+8, stock)
+	assert.Equal(t, int64(2), movements)
 }
 
 func TestReceivePO_NonSentPO_Returns400(t *testing.T) {
@@ -506,6 +1104,116 @@ func TestReceivePO_BankTransferNoBankAccount_Returns400(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
 
+func TestGetDiscrepancies_ReceivedWithMismatch_ReturnsDeltas(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	po := createDraftPO(t, db, supplier, product)
+	require.NoError(t, db.Model(po).Update("status", "sent").Error)
+
+	loadedPO := &models.PurchaseOrder{}
+	require.NoError(t, db.Preload("Items").First(loadedPO, po.ID).Error)
+	itemID := loadedPO.Items[0].ID
+
+	// Ordered 10 @ 15000, received 8 @ 14000
+	body := fmt.Sprintf(`{
+		"receivedDate": "2026-01-20",
+		"paymentMethod": "cash",
+		"items": [{"itemId": "%s", "receivedQty": 8, "receivedPrice": 14000, "isVerified": true}]
+	}`, itemID)
+	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/receive", po.ID), strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	req = testutil.AuthenticatedRequest(t, "GET", "/api/v1/purchase-orders/discrepancies?from=2026-01-01&to=2026-01-31", nil, token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var resp struct {
+		Data services.ReceivingDiscrepancyReport `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Items, 1)
+	item := resp.Data.Items[0]
+	assert.Equal(t, po.ID, item.PurchaseOrderID)
+	assert.Equal(t, po.PONumber, item.PONumber)
+	assert.Equal(t, supplier.Name, item.SupplierName)
+	assert.Equal(t, 10, item.OrderedQty)
+	assert.Equal(t, 8, item.ReceivedQty)
+	assert.Equal(t, -2, item.QtyDelta)
+	assert.Equal(t, -1000.0, item.PriceDelta)
+	assert.Equal(t, -38000.0, item.ValueDelta)
+
+	// Outside the date range nothing is reported
+	req = testutil.AuthenticatedRequest(t, "GET", "/api/v1/purchase-orders/discrepancies?from=2026-02-01&to=2026-02-28", nil, token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	assert.Empty(t, resp.Data.Items)
+}
+
+func TestGetDiscrepancies_InvalidDate_Returns400(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/purchase-orders/discrepancies?from=01-01-2026", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestLowStockBySupplier_ThenDraft_CreatesDraftPO(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	require.NoError(t, db.Exec("INSERT INTO product_suppliers (product_id, supplier_id) VALUES (?, ?)", product.ID, supplier.ID).Error)
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", product.Variants[0].ID).
+		Updates(map[string]interface{}{"reorder_point": 10, "current_stock": 4}).Error)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/purchase-orders/low-stock-by-supplier", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var resp struct {
+		Data services.LowStockBySupplierResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Suppliers, 1)
+	group := resp.Data.Suppliers[0]
+	assert.Equal(t, supplier.ID, group.SupplierID)
+	require.Len(t, group.Items, 1)
+	assert.Equal(t, 16, group.Items[0].OrderedQty)
+
+	// The suggestions can be posted back as-is to create the draft
+	items, err := json.Marshal(group.Items)
+	require.NoError(t, err)
+	body := fmt.Sprintf(`{"supplierId": %d, "items": %s}`, supplier.ID, items)
+	req = testutil.AuthenticatedRequest(t, "POST", "/api/v1/purchase-orders/low-stock-by-supplier/draft", strings.NewReader(body), token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.Equal(t, "draft", data["status"])
+	assert.Equal(t, float64(supplier.ID), data["supplierId"])
+}
+
 func TestGetProductsForPO_ReturnsFilteredProducts(t *testing.T) {
 	router, db, _, _ := setupPOTestRouter(t)
 
@@ -526,3 +1234,217 @@ func TestGetProductsForPO_ReturnsFilteredProducts(t *testing.T) {
 	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
 	assert.Contains(t, response, "data")
 }
+
+func TestGetLastPOForSupplier_ReturnsLatestNonDraftItems(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	older := createDraftPO(t, db, supplier, testutil.CreateTestProduct(t, db))
+	require.NoError(t, db.Model(older).Updates(map[string]interface{}{"status": "received", "date": "2026-01-10"}).Error)
+	latest := createDraftPO(t, db, supplier, testutil.CreateTestProduct(t, db))
+	require.NoError(t, db.Model(latest).Updates(map[string]interface{}{"status": "sent", "date": "2026-02-10"}).Error)
+	// A newer draft is ignored
+	draft := createDraftPO(t, db, supplier, testutil.CreateTestProduct(t, db))
+	require.NoError(t, db.Model(draft).Update("date", "2026-03-01").Error)
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/purchase-orders/last?supplierId=%d", supplier.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(latest.ID), data["sourcePoId"])
+	items := data["items"].([]interface{})
+	require.Len(t, items, 1)
+	item := items[0].(map[string]interface{})
+	assert.Equal(t, latest.Items[0].VariantID, item["variantId"])
+	assert.Equal(t, float64(10), item["orderedQty"])
+}
+
+func TestGetLastPOForSupplier_NoPOs_ReturnsEmptyItems(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/purchase-orders/last?supplierId=%d", supplier.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Nil(t, data["sourcePoId"])
+	assert.Empty(t, data["items"])
+}
+
+func TestGetLastPOForSupplier_MissingSupplierID_Returns400(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/purchase-orders/last", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestApprovePO_SelfApproval_Returns403(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	po := createDraftPO(t, db, supplier, product)
+	require.NoError(t, db.Model(po).Update("created_by", user.ID).Error)
+
+	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/approve", po.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestApprovePO_DifferentUser_Returns200(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	creator := setupPOTestUserWithPermission(t, db, []string{"read", "create", "update"})
+	approver := setupPOTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, approver.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	po := createDraftPO(t, db, supplier, product)
+	require.NoError(t, db.Model(po).Update("created_by", creator.ID).Error)
+
+	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/approve", po.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(approver.ID), data["approvedBy"])
+}
+
+func TestMarkPOPaid_ReceivedPO_Returns200_AndRejectsSecondPayment(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	po := createDraftPO(t, db, supplier, product)
+	require.NoError(t, db.Model(po).Update("status", "received").Error)
+
+	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/pay", po.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.NotEmpty(t, data["paidAt"])
+
+	var stored models.PurchaseOrder
+	require.NoError(t, db.First(&stored, po.ID).Error)
+	assert.NotNil(t, stored.PaidAt)
+
+	req = testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/pay", po.ID), nil, token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestMarkPOPaid_DraftPO_Returns400(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	po := createDraftPO(t, db, supplier, product)
+
+	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/pay", po.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func newAttachmentUploadRequest(t *testing.T, poID uint, filename string, content []byte, token string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/purchase-orders/%d/attachments", poID), &body, token)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestPOAttachments_UploadListDelete(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	po := createDraftPO(t, db, supplier, product)
+
+	pdf := []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n%%EOF\n")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newAttachmentUploadRequest(t, po.ID, "quote.pdf", pdf, token))
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.Equal(t, "quote.pdf", data["filename"])
+	assert.Equal(t, "application/pdf", data["contentType"])
+	attachmentID := uint(data["id"].(float64))
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/purchase-orders/%d/attachments", po.ID), nil, token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var listResp struct {
+		Data []models.POAttachment `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &listResp))
+	require.Len(t, listResp.Data, 1)
+	assert.Equal(t, attachmentID, listResp.Data[0].ID)
+
+	req = testutil.AuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/v1/purchase-orders/%d/attachments/%d", po.ID, attachmentID), nil, token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var count int64
+	require.NoError(t, db.Model(&models.POAttachment{}).Where("purchase_order_id = ?", po.ID).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestPOAttachments_DisallowedType_Returns400(t *testing.T) {
+	router, db, _, _ := setupPOTestRouter(t)
+
+	user := setupPOTestUserWithPermission(t, db, []string{"read", "create", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	po := createDraftPO(t, db, supplier, product)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newAttachmentUploadRequest(t, po.ID, "notes.txt", []byte("plain text notes"), token))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "ATTACHMENT_TYPE_NOT_ALLOWED")
+}
@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/pointofsale/backend/repositories"
 	"github.com/pointofsale/backend/services"
 	"github.com/pointofsale/backend/utils"
@@ -80,6 +83,38 @@ func (h *ProductHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// productLowStockSortFields lists the sort fields accepted by the low-stock list.
+var productLowStockSortFields = []string{"stock", "name", "shortfall"}
+
+// ListLowStock handles GET /api/v1/products/low-stock.
+func (h *ProductHandler) ListLowStock(w http.ResponseWriter, r *http.Request) {
+	paginationParams, err := utils.ParsePaginationParams(r, productLowStockSortFields)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	params := repositories.PaginationParams{
+		Page:     paginationParams.Page,
+		PageSize: paginationParams.PageSize,
+		Search:   paginationParams.Search,
+		SortBy:   paginationParams.SortBy,
+		SortDir:  paginationParams.SortDir,
+	}
+
+	items, total, serviceErr := h.productService.LowStock(params)
+	if serviceErr != nil {
+		utils.Error(w, http.StatusInternalServerError, serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	meta := utils.CalculatePaginationMeta(params.Page, params.PageSize, int(total))
+	utils.JSON(w, http.StatusOK, utils.PaginatedResponse{
+		Data: items,
+		Meta: meta,
+	})
+}
+
 // GetProduct handles GET /api/v1/products/{id}.
 func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -102,6 +137,64 @@ func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 	utils.Success(w, http.StatusOK, "", product)
 }
 
+// GetProductFullView handles GET /api/v1/products/{id}/full.
+func (h *ProductHandler) GetProductFullView(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid product ID", "VALIDATION_ERROR")
+		return
+	}
+
+	view, serviceErr := h.productService.FullView(uint(id))
+	if serviceErr != nil {
+		status := http.StatusInternalServerError
+		if serviceErr.Err == services.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		utils.Error(w, status, serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", view)
+}
+
+// GetProductOverview handles GET /api/v1/products/{id}/overview.
+func (h *ProductHandler) GetProductOverview(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid product ID", "VALIDATION_ERROR")
+		return
+	}
+
+	overview, serviceErr := h.productService.FamilyOverview(uint(id))
+	if serviceErr != nil {
+		utils.Error(w, mapProductServiceErrorStatus(serviceErr), serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", overview)
+}
+
+// GetProductUnits handles GET /api/v1/products/{id}/units.
+func (h *ProductHandler) GetProductUnits(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid product ID", "VALIDATION_ERROR")
+		return
+	}
+
+	graph, serviceErr := h.productService.UnitGraph(uint(id))
+	if serviceErr != nil {
+		utils.Error(w, mapProductServiceErrorStatus(serviceErr), serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", graph)
+}
+
 // CreateProduct handles POST /api/v1/products.
 func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 	var input services.CreateProductInput
@@ -143,6 +236,24 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	utils.Success(w, http.StatusOK, "Product updated successfully", product)
 }
 
+// CloneProduct handles POST /api/v1/products/{id}/clone.
+func (h *ProductHandler) CloneProduct(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid product ID", "VALIDATION_ERROR")
+		return
+	}
+
+	product, serviceErr := h.productService.Clone(uint(id))
+	if serviceErr != nil {
+		utils.Error(w, mapProductServiceErrorStatus(serviceErr), serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, "Product cloned successfully", product)
+}
+
 // DeleteProduct handles DELETE /api/v1/products/{id}.
 func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -163,6 +274,162 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// DeleteCheck handles GET /api/v1/products/{id}/delete-check.
+func (h *ProductHandler) DeleteCheck(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid product ID", "VALIDATION_ERROR")
+		return
+	}
+
+	check, serviceErr := h.productService.DeleteCheck(uint(id))
+	if serviceErr != nil {
+		utils.Error(w, mapProductServiceErrorStatus(serviceErr), serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", check)
+}
+
+// GetVariantRackStock handles GET /api/v1/products/variants/{id}/rack-stock.
+func (h *ProductHandler) GetVariantRackStock(w http.ResponseWriter, r *http.Request) {
+	variantID := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(variantID); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid variant ID", "VALIDATION_ERROR")
+		return
+	}
+
+	stock, serviceErr := h.productService.VariantRackStock(variantID)
+	if serviceErr != nil {
+		utils.Error(w, mapProductServiceErrorStatus(serviceErr), serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", stock)
+}
+
+// UploadImage handles POST /api/v1/products/{id}/images.
+// The image is sent as multipart/form-data in the "file" field.
+func (h *ProductHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid product ID", "VALIDATION_ERROR")
+		return
+	}
+
+	maxBytes := h.productService.ImageMaxBytes()
+	// Leave headroom for multipart boundaries and headers.
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+(1<<20))
+	if err := r.ParseMultipartForm(maxBytes); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.Error(w, http.StatusRequestEntityTooLarge, "Image exceeds the maximum allowed size", "IMAGE_TOO_LARGE")
+			return
+		}
+		utils.Error(w, http.StatusBadRequest, "Invalid multipart form", "VALIDATION_ERROR")
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "File is required", "VALIDATION_ERROR")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Failed to read file", "VALIDATION_ERROR")
+		return
+	}
+
+	image, serviceErr := h.productService.UploadImage(uint(id), data)
+	if serviceErr != nil {
+		utils.Error(w, mapProductServiceErrorStatus(serviceErr), serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, "Image uploaded successfully", image)
+}
+
+// GetVariantReorderDetail handles GET /api/v1/products/variants/{id}/reorder.
+func (h *ProductHandler) GetVariantReorderDetail(w http.ResponseWriter, r *http.Request) {
+	variantID := chi.URLParam(r, "id")
+	if _, err := uuid.Parse(variantID); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid variant ID", "VALIDATION_ERROR")
+		return
+	}
+
+	detail, serviceErr := h.productService.ReorderDetail(variantID)
+	if serviceErr != nil {
+		utils.Error(w, mapProductServiceErrorStatus(serviceErr), serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", detail)
+}
+
+// BulkSetReorderPoints handles POST /api/v1/products/reorder-points/bulk.
+func (h *ProductHandler) BulkSetReorderPoints(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Items []services.ReorderPointInput `json:"items"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+
+	if serviceErr := h.productService.BulkSetReorderPoints(input.Items); serviceErr != nil {
+		utils.Error(w, mapProductServiceErrorStatus(serviceErr), serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Reorder points updated successfully", map[string]int{
+		"updated": len(input.Items),
+	})
+}
+
+// GenerateLabels handles POST /api/v1/products/labels
+func (h *ProductHandler) GenerateLabels(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		VariantIDs []string `json:"variantIds"`
+		Layout     string   `json:"layout"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+
+	pdf, serviceErr := h.productService.GenerateLabels(input.VariantIDs, input.Layout)
+	if serviceErr != nil {
+		utils.Error(w, mapProductServiceErrorStatus(serviceErr), serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", `inline; filename="labels.pdf"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(pdf)
+}
+
+// PreviewPriceChange handles POST /api/v1/products/price-change/preview
+func (h *ProductHandler) PreviewPriceChange(w http.ResponseWriter, r *http.Request) {
+	var input services.PriceChangePreviewInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+
+	preview, serviceErr := h.productService.PreviewPriceChange(input)
+	if serviceErr != nil {
+		utils.Error(w, mapProductServiceErrorStatus(serviceErr), serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", preview)
+}
+
 func mapProductServiceErrorStatus(serviceErr *services.ServiceError) int {
 	switch serviceErr.Err {
 	case services.ErrValidation:
@@ -171,6 +438,8 @@ func mapProductServiceErrorStatus(serviceErr *services.ServiceError) int {
 		return http.StatusNotFound
 	case services.ErrConflict:
 		return http.StatusConflict
+	case services.ErrTooLarge:
+		return http.StatusRequestEntityTooLarge
 	default:
 		return http.StatusInternalServerError
 	}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -17,6 +20,7 @@ import (
 	"github.com/pointofsale/backend/repositories"
 	"github.com/pointofsale/backend/services"
 	"github.com/pointofsale/backend/testutil"
+	"github.com/pointofsale/backend/utils"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -42,7 +46,8 @@ func setupProductTestRouter(t *testing.T) (chi.Router, *gorm.DB, *redis.Client,
 
 	userRepo := repositories.NewUserRepository(db)
 	productRepo := repositories.NewProductRepository(db)
-	productService := services.NewProductService(productRepo)
+	productService := services.NewProductService(productRepo, &memoryFileStorage{})
+	productService.SetSKUSequence(services.NewSequenceService(db))
 	productHandler := NewProductHandler(productService)
 	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTAccessSecret, rdb, userRepo)
 	permMiddleware := middleware.NewPermissionMiddleware(db, rdb)
@@ -51,8 +56,20 @@ func setupProductTestRouter(t *testing.T) (chi.Router, *gorm.DB, *redis.Client,
 	r.Route("/api/v1/products", func(r chi.Router) {
 		r.Use(authMiddleware.Authenticate)
 		r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/", productHandler.ListProducts)
+		r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/low-stock", productHandler.ListLowStock)
+		r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/variants/{id}/rack-stock", productHandler.GetVariantRackStock)
+		r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/variants/{id}/reorder", productHandler.GetVariantReorderDetail)
 		r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}", productHandler.GetProduct)
+		r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}/full", productHandler.GetProductFullView)
+		r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}/overview", productHandler.GetProductOverview)
+		r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}/units", productHandler.GetProductUnits)
+		r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}/delete-check", productHandler.DeleteCheck)
 		r.With(permMiddleware.RequirePermission("Master Data", "Product", "create")).Post("/", productHandler.CreateProduct)
+		r.With(permMiddleware.RequirePermission("Master Data", "Product", "create")).Post("/{id}/clone", productHandler.CloneProduct)
+		r.With(permMiddleware.RequirePermission("Master Data", "Product", "update")).Post("/reorder-points/bulk", productHandler.BulkSetReorderPoints)
+		r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Post("/labels", productHandler.GenerateLabels)
+		r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Post("/price-change/preview", productHandler.PreviewPriceChange)
+		r.With(permMiddleware.RequirePermission("Master Data", "Product", "update")).Post("/{id}/images", productHandler.UploadImage)
 		r.With(permMiddleware.RequirePermission("Master Data", "Product", "update")).Put("/{id}", productHandler.UpdateProduct)
 		r.With(permMiddleware.RequirePermission("Master Data", "Product", "delete")).Delete("/{id}", productHandler.DeleteProduct)
 	})
@@ -98,7 +115,7 @@ func minimalProductPayload(categoryID, supplierID, rackID uint) string {
 		"variants":[
 			{
 				"sku":"RC-001",
-				"barcode":"8901234567000",
+				"barcode":"8901234567005",
 				"attributes":[],
 				"pricingTiers":[{"minQty":1,"value":15000}],
 				"rackIds":[%d]
@@ -135,6 +152,147 @@ func TestCreateProduct_MinimalValid_Returns201(t *testing.T) {
 	assert.Equal(t, false, data["hasVariants"])
 }
 
+func variantSKUPayload(categoryID, supplierID, rackID uint, skus ...string) string {
+	variants := make([]string, len(skus))
+	for i, sku := range skus {
+		variants[i] = fmt.Sprintf(`{
+			"sku":%q,
+			"attributes":[{"attributeName":"Size","attributeValue":"S%d"}],
+			"pricingTiers":[{"minQty":1,"value":15000}],
+			"rackIds":[%d]
+		}`, sku, i, rackID)
+	}
+	return fmt.Sprintf(`{
+		"name":"Rice",
+		"categoryId":%d,
+		"priceSetting":"fixed",
+		"hasVariants":true,
+		"status":"active",
+		"supplierIds":[%d],
+		"units":[{"name":"Kg","isBase":true}],
+		"variants":[%s]
+	}`, categoryID, supplierID, strings.Join(variants, ","))
+}
+
+func createdVariantSKUs(t *testing.T, data map[string]interface{}) []string {
+	t.Helper()
+	variants, ok := data["variants"].([]interface{})
+	require.True(t, ok)
+	skus := make([]string, 0, len(variants))
+	for _, v := range variants {
+		sku, _ := v.(map[string]interface{})["sku"].(string)
+		skus = append(skus, sku)
+	}
+	return skus
+}
+
+func TestCreateProduct_EmptySKUs_AreGeneratedAndSuppliedKept(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	category := testutil.CreateTestCategory(t, db)
+	supplier := testutil.CreateTestSupplier(t, db)
+	rack := testutil.CreateTestRack(t, db)
+
+	body := variantSKUPayload(category.ID, supplier.ID, rack.ID, "RC-001", "", "")
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.ElementsMatch(t, []string{"RC-001", "RIC-000001", "RIC-000002"}, createdVariantSKUs(t, data))
+}
+
+func TestCreateProduct_GeneratedSKUCollision_SkipsTakenSKU(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	category := testutil.CreateTestCategory(t, db)
+	supplier := testutil.CreateTestSupplier(t, db)
+	rack := testutil.CreateTestRack(t, db)
+
+	// Another product already uses the first SKU the sequence hands out.
+	existing := testutil.CreateTestProduct(t, db)
+	require.NoError(t, db.Model(&models.ProductVariant{}).
+		Where("id = ?", existing.Variants[0].ID).
+		Update("sku", "RIC-000001").Error)
+
+	body := variantSKUPayload(category.ID, supplier.ID, rack.ID, "")
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.Equal(t, []string{"RIC-000002"}, createdVariantSKUs(t, data))
+}
+
+func TestCreateProduct_AutoBarcode_GeneratesValidEAN13(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	category := testutil.CreateTestCategory(t, db)
+	supplier := testutil.CreateTestSupplier(t, db)
+	rack := testutil.CreateTestRack(t, db)
+
+	body := fmt.Sprintf(`{
+		"name":"Rice",
+		"categoryId":%d,
+		"priceSetting":"fixed",
+		"hasVariants":true,
+		"status":"active",
+		"autoBarcode":true,
+		"supplierIds":[%d],
+		"units":[{"name":"Kg","isBase":true}],
+		"variants":[
+			{"barcode":"4006381333931","attributes":[{"attributeName":"Size","attributeValue":"S"}],"pricingTiers":[{"minQty":1,"value":15000}],"rackIds":[%d]},
+			{"attributes":[{"attributeName":"Size","attributeValue":"M"}],"pricingTiers":[{"minQty":1,"value":16000}],"rackIds":[%d]}
+		]
+	}`, category.ID, supplier.ID, rack.ID, rack.ID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	variants := data["variants"].([]interface{})
+	require.Len(t, variants, 2)
+	barcodes := make([]string, 0, len(variants))
+	for _, v := range variants {
+		barcode, _ := v.(map[string]interface{})["barcode"].(string)
+		assert.True(t, utils.ValidateBarcode(barcode), "invalid barcode %q", barcode)
+		barcodes = append(barcodes, barcode)
+	}
+	assert.Contains(t, barcodes, "4006381333931")
+	assert.NotEqual(t, barcodes[0], barcodes[1])
+}
+
+func TestCreateProduct_InvalidBarcodeCheckDigit_Returns400(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	category := testutil.CreateTestCategory(t, db)
+	supplier := testutil.CreateTestSupplier(t, db)
+	rack := testutil.CreateTestRack(t, db)
+
+	body := strings.Replace(minimalProductPayload(category.ID, supplier.ID, rack.ID), "8901234567005", "8901234567000", 1)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "barcode 8901234567000 must be a valid EAN-8, UPC-A or EAN-13 code")
+}
+
 func TestListProducts_Returns200WithVariantCount(t *testing.T) {
 	router, db, _, _ := setupProductTestRouter(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -173,6 +331,92 @@ func TestListProducts_Returns200WithVariantCount(t *testing.T) {
 	assert.Contains(t, first, "variantCount")
 }
 
+func TestListLowStock_ReturnsVariantsAtOrBelowReorderPoint(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	setReorderPoint := func(product *models.Product, reorderPoint int) {
+		require.NoError(t, db.Model(&models.ProductVariant{}).
+			Where("id = ?", product.Variants[0].ID).
+			Update("reorder_point", reorderPoint).Error)
+	}
+
+	low := testutil.CreateTestProduct(t, db)
+	setReorderPoint(low, 150)
+	require.NoError(t, db.Model(low).Association("Suppliers").Append(supplier))
+
+	ample := testutil.CreateTestProduct(t, db)
+	setReorderPoint(ample, 50)
+
+	testutil.CreateTestProduct(t, db) // no reorder point
+
+	inactive := testutil.CreateTestProduct(t, db, func(p *models.Product) { p.Status = "inactive" })
+	setReorderPoint(inactive, 150)
+
+	// A reorder point set through the product input counts too.
+	category := testutil.CreateTestCategory(t, db)
+	rack := testutil.CreateTestRack(t, db)
+	body := strings.Replace(minimalProductPayload(category.ID, supplier.ID, rack.ID), `"sku":"RC-001",`, `"sku":"RC-001","reorderPoint":5,`, 1)
+	createReq := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products", strings.NewReader(body), token)
+	createRR := httptest.NewRecorder()
+	router.ServeHTTP(createRR, createReq)
+	require.Equal(t, http.StatusCreated, createRR.Code)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/products/low-stock?sortBy=shortfall&sortDir=desc", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var response struct {
+		Data []struct {
+			VariantID    string `json:"variantId"`
+			ProductName  string `json:"productName"`
+			CurrentStock int    `json:"currentStock"`
+			ReorderPoint int    `json:"reorderPoint"`
+			Shortfall    int    `json:"shortfall"`
+			Suppliers    []struct {
+				ID uint `json:"id"`
+			} `json:"suppliers"`
+		} `json:"data"`
+		Meta utils.PaginationMeta `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	require.Len(t, response.Data, 2)
+	assert.Equal(t, 2, response.Meta.TotalItems)
+
+	first := response.Data[0]
+	assert.Equal(t, low.Variants[0].ID, first.VariantID)
+	assert.Equal(t, low.Name, first.ProductName)
+	assert.Equal(t, 100, first.CurrentStock)
+	assert.Equal(t, 150, first.ReorderPoint)
+	assert.Equal(t, 50, first.Shortfall)
+	require.Len(t, first.Suppliers, 1)
+	assert.Equal(t, supplier.ID, first.Suppliers[0].ID)
+
+	second := response.Data[1]
+	assert.Equal(t, "Rice", second.ProductName)
+	assert.Equal(t, 5, second.ReorderPoint)
+	assert.Equal(t, 5, second.Shortfall)
+}
+
+func TestListLowStock_InvalidSortField_Returns400(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/products/low-stock?sortBy=cost", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "invalid sort field: cost")
+}
+
 func TestGetProduct_ReturnsFullNestedData(t *testing.T) {
 	router, db, _, _ := setupProductTestRouter(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -209,6 +453,191 @@ func TestGetProduct_ReturnsFullNestedData(t *testing.T) {
 	assert.Contains(t, data, "suppliers")
 }
 
+func TestGetProduct_MarkupProduct_IncludesTierSellingPrice(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	markupType := services.MarkupPercentage
+	product := testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.PriceSetting = "markup"
+		p.MarkupType = &markupType
+	})
+	require.NoError(t, db.Model(&models.VariantPricingTier{}).
+		Where("variant_id = ?", product.Variants[0].ID).
+		Updates(map[string]interface{}{"value": 25, "selling_price": 10000}).Error)
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/products/%d", product.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	variant := data["variants"].([]interface{})[0].(map[string]interface{})
+	tier := variant["pricingTiers"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, float64(25), tier["value"])
+	assert.Equal(t, float64(10000), tier["sellingPrice"])
+}
+
+func TestGetProductFullView_IncludesSuppliersStockAndSalesStats(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	supplier := testutil.CreateTestSupplier(t, db)
+	require.NoError(t, db.Model(product).Association("Suppliers").Append(supplier))
+
+	createSale := func(date time.Time, qty int, total float64) {
+		sale := &models.SalesTransaction{
+			TransactionNumber: fmt.Sprintf("TRX-%d", date.UnixNano()),
+			Date:              date,
+			Subtotal:          total,
+			GrandTotal:        total,
+			TotalItems:        1,
+			PaymentMethod:     "cash",
+			Items: []models.SalesTransactionItem{{
+				ProductID:   product.ID,
+				VariantID:   variant.ID,
+				UnitID:      product.Units[0].ID,
+				ProductName: product.Name,
+				UnitName:    "Pcs",
+				Quantity:    qty,
+				BaseQty:     qty,
+				UnitPrice:   total / float64(qty),
+				TotalPrice:  total,
+			}},
+		}
+		require.NoError(t, db.Create(sale).Error)
+	}
+	createSale(time.Now().AddDate(0, 0, -2), 3, 30000)
+	createSale(time.Now().AddDate(0, 0, -1), 1, 10000)
+	createSale(time.Now().AddDate(0, 0, -45), 5, 50000) // outside the 30-day window
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/products/%d/full", product.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, product.Name, data["name"])
+	assert.Contains(t, data, "units")
+	assert.Contains(t, data, "variants")
+
+	suppliers, ok := data["suppliers"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, suppliers, 1)
+	assert.Equal(t, supplier.Name, suppliers[0].(map[string]interface{})["name"])
+
+	stock, ok := data["stock"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, stock, 1)
+	entry := stock[0].(map[string]interface{})
+	assert.Equal(t, variant.ID, entry["variantId"])
+	assert.Equal(t, float64(100), entry["currentStock"])
+	assert.Equal(t, float64(100), data["totalStock"])
+
+	sales, ok := data["sales"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, float64(4), sales["quantitySold"])
+	assert.Equal(t, float64(40000), sales["revenue"])
+	assert.Equal(t, float64(2), sales["transactions"])
+}
+
+func TestGetProductFullView_NotFound_Returns404(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/products/999999/full", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusNotFound, "Product not found")
+}
+
+func TestGetProductUnits_PcsDozenBoxChain_ReturnsRecreatedFactors(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	category := testutil.CreateTestCategory(t, db)
+	supplier := testutil.CreateTestSupplier(t, db)
+	rack := testutil.CreateTestRack(t, db)
+
+	// Units are listed out of order; recreateUnits resolves the chain
+	payload := fmt.Sprintf(`{
+		"name":"Eggs",
+		"categoryId":%d,
+		"priceSetting":"fixed",
+		"hasVariants":false,
+		"status":"active",
+		"supplierIds":[%d],
+		"units":[
+			{"name":"Box","conversionFactor":10,"convertsToName":"Dozen","isBase":false},
+			{"name":"Pcs","isBase":true},
+			{"name":"Dozen","conversionFactor":12,"convertsToName":"Pcs","isBase":false}
+		],
+		"variants":[
+			{"sku":"EGG-001","attributes":[],"pricingTiers":[{"minQty":1,"value":2000}],"rackIds":[%d]}
+		]
+	}`, category.ID, supplier.ID, rack.ID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products", strings.NewReader(payload), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	created := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	productID := uint(created["id"].(float64))
+
+	req = testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/products/%d/units", productID), nil, token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	units := data["units"].([]interface{})
+	require.Len(t, units, 3)
+
+	byName := make(map[string]map[string]interface{}, len(units))
+	for _, u := range units {
+		unit := u.(map[string]interface{})
+		byName[unit["name"].(string)] = unit
+	}
+
+	pcs, dozen, box := byName["Pcs"], byName["Dozen"], byName["Box"]
+	assert.Equal(t, pcs["id"], data["baseUnitId"])
+	assert.Equal(t, float64(1), pcs["toBaseUnit"])
+	assert.Nil(t, pcs["convertsToId"])
+
+	assert.Equal(t, float64(12), dozen["conversionFactor"])
+	assert.Equal(t, float64(12), dozen["toBaseUnit"])
+	assert.Equal(t, pcs["id"], dozen["convertsToId"])
+
+	assert.Equal(t, float64(10), box["conversionFactor"])
+	assert.Equal(t, float64(120), box["toBaseUnit"])
+	assert.Equal(t, dozen["id"], box["convertsToId"])
+	assert.Equal(t, []interface{}{"Box", "Dozen", "Pcs"}, box["chain"])
+}
+
+func TestGetProductUnits_NotFound_Returns404(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/products/999999/units", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusNotFound, "Product not found")
+}
+
 func TestUpdateProduct_UnitsWithStock_Returns409(t *testing.T) {
 	router, db, _, _ := setupProductTestRouter(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -249,7 +678,7 @@ func TestUpdateProduct_UnitsWithStock_Returns409(t *testing.T) {
 		"variants":[
 			{
 				"sku":"RC-001",
-				"barcode":"8901234567000",
+				"barcode":"8901234567005",
 				"attributes":[],
 				"pricingTiers":[{"minQty":1,"value":15000}],
 				"rackIds":[%d]
@@ -270,11 +699,11 @@ func TestUpdateProduct_UnitsWithStock_Returns409(t *testing.T) {
 	assert.Equal(t, http.StatusConflict, updateRR.Code)
 }
 
-func TestDeleteProduct_NoStock_Returns200(t *testing.T) {
+func TestUpdateProduct_WithoutCostPrice_KeepsStoredCost(t *testing.T) {
 	router, db, _, _ := setupProductTestRouter(t)
 	defer testutil.CleanupTestDB(t, db)
 
-	user := setupProductTestUserWithPermission(t, db, []string{"read", "create", "delete"})
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "create", "update"})
 	token := testutil.GenerateTestAccessToken(t, user.ID, false)
 
 	category := testutil.CreateTestCategory(t, db)
@@ -290,17 +719,737 @@ func TestDeleteProduct_NoStock_Returns200(t *testing.T) {
 	)
 	createRR := httptest.NewRecorder()
 	router.ServeHTTP(createRR, createReq)
-	require.Equal(t, http.StatusCreated, createRR.Code)
 	created := testutil.AssertSuccessResponse(t, createRR, http.StatusCreated)
 	productID := uint(created["id"].(float64))
 
-	deleteReq := testutil.AuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/v1/products/%d", productID), nil, token)
-	deleteRR := httptest.NewRecorder()
-	router.ServeHTTP(deleteRR, deleteReq)
+	var variant models.ProductVariant
+	require.NoError(t, db.Where("product_id = ?", productID).First(&variant).Error)
+	require.NoError(t, db.Model(&variant).Update("cost_price", 12000).Error)
 
-	assert.Equal(t, http.StatusOK, deleteRR.Code)
+	updateBody := fmt.Sprintf(`{
+		"name":"Rice",
+		"description":"Premium rice, updated",
+		"categoryId":%d,
+		"priceSetting":"fixed",
+		"hasVariants":false,
+		"status":"active",
+		"supplierIds":[%d],
+		"units":[
+			{"name":"Kg","isBase":true}
+		],
+		"variants":[
+			{
+				"id":"%s",
+				"sku":"RC-001",
+				"barcode":"8901234567005",
+				"attributes":[],
+				"pricingTiers":[{"minQty":1,"value":16000}],
+				"rackIds":[%d]
+			}
+		]
+	}`, category.ID, supplier.ID, variant.ID, rack.ID)
 
-	var body map[string]string
-	require.NoError(t, json.Unmarshal(deleteRR.Body.Bytes(), &body))
-	assert.Equal(t, "Product deleted successfully", body["message"])
+	updateReq := testutil.AuthenticatedRequest(
+		t,
+		"PUT",
+		fmt.Sprintf("/api/v1/products/%d", productID),
+		strings.NewReader(updateBody),
+		token,
+	)
+	updateRR := httptest.NewRecorder()
+	router.ServeHTTP(updateRR, updateReq)
+	testutil.AssertSuccessResponse(t, updateRR, http.StatusOK)
+
+	var updated models.ProductVariant
+	require.NoError(t, db.First(&updated, "id = ?", variant.ID).Error)
+	require.NotNil(t, updated.CostPrice)
+	assert.Equal(t, 12000.0, *updated.CostPrice)
+}
+
+func TestCloneProduct_CopiesVariantsWithFreshIdentifiersAndNoStock(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	category := testutil.CreateTestCategory(t, db)
+	supplier := testutil.CreateTestSupplier(t, db)
+	rack := testutil.CreateTestRack(t, db)
+
+	body := variantSKUPayload(category.ID, supplier.ID, rack.ID, "RC-001", "RC-002")
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	source := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	sourceID := uint(source["id"].(float64))
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("product_id = ?", sourceID).Update("current_stock", 25).Error)
+
+	req = testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/products/%d/clone", sourceID), nil, token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.NotEqual(t, float64(sourceID), data["id"])
+	assert.Equal(t, "Rice (Copy)", data["name"])
+	assert.Len(t, data["suppliers"], 1)
+
+	skus := createdVariantSKUs(t, data)
+	assert.ElementsMatch(t, []string{"RIC-000001", "RIC-000002"}, skus)
+
+	sourceVariantIDs := make(map[string]bool)
+	for _, v := range source["variants"].([]interface{}) {
+		sourceVariantIDs[v.(map[string]interface{})["id"].(string)] = true
+	}
+	variants := data["variants"].([]interface{})
+	require.Len(t, variants, 2)
+	for _, v := range variants {
+		variant := v.(map[string]interface{})
+		assert.False(t, sourceVariantIDs[variant["id"].(string)])
+		assert.Equal(t, float64(0), variant["currentStock"])
+		assert.True(t, utils.ValidateBarcode(variant["barcode"].(string)))
+		assert.Len(t, variant["pricingTiers"], 1)
+		assert.Len(t, variant["attributes"], 1)
+		assert.Len(t, variant["racks"], 1)
+	}
+
+	var sourceStock []int
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("product_id = ?", sourceID).Pluck("current_stock", &sourceStock).Error)
+	assert.Equal(t, []int{25, 25}, sourceStock)
+}
+
+func TestCloneProduct_NotFound_Returns404(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products/999999/clone", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestDeleteProduct_NoStock_Returns200(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "create", "delete"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	category := testutil.CreateTestCategory(t, db)
+	supplier := testutil.CreateTestSupplier(t, db)
+	rack := testutil.CreateTestRack(t, db)
+
+	createReq := testutil.AuthenticatedRequest(
+		t,
+		"POST",
+		"/api/v1/products",
+		strings.NewReader(minimalProductPayload(category.ID, supplier.ID, rack.ID)),
+		token,
+	)
+	createRR := httptest.NewRecorder()
+	router.ServeHTTP(createRR, createReq)
+	require.Equal(t, http.StatusCreated, createRR.Code)
+	created := testutil.AssertSuccessResponse(t, createRR, http.StatusCreated)
+	productID := uint(created["id"].(float64))
+
+	deleteReq := testutil.AuthenticatedRequest(t, "DELETE", fmt.Sprintf("/api/v1/products/%d", productID), nil, token)
+	deleteRR := httptest.NewRecorder()
+	router.ServeHTTP(deleteRR, deleteReq)
+
+	assert.Equal(t, http.StatusOK, deleteRR.Code)
+
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(deleteRR.Body.Bytes(), &body))
+	assert.Equal(t, "Product deleted successfully", body["message"])
+}
+
+func TestGetVariantRackStock_SumMatchesCurrentStock(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	front := testutil.CreateTestRack(t, db)
+	back := testutil.CreateTestRack(t, db)
+	require.NoError(t, db.Exec("INSERT INTO variant_racks (variant_id, rack_id, quantity) VALUES (?, ?, ?), (?, ?, ?)",
+		variant.ID, front.ID, 60, variant.ID, back.ID, 25).Error)
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/products/variants/%s/rack-stock", variant.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	racks := data["racks"].([]interface{})
+	assert.Len(t, racks, 2)
+
+	sum := 0
+	for _, rack := range racks {
+		sum += int(rack.(map[string]interface{})["quantity"].(float64))
+	}
+	assert.Equal(t, 85, sum)
+	assert.Equal(t, float64(85), data["allocated"])
+	assert.Equal(t, float64(15), data["unallocated"])
+	assert.Equal(t, variant.CurrentStock, sum+int(data["unallocated"].(float64)))
+}
+
+func TestGetVariantRackStock_NotFound_Returns404(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/products/variants/00000000-0000-0000-0000-000000000000/rack-stock", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetVariantRackStock_InvalidID_Returns400(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/products/variants/not-a-uuid/rack-stock", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestBulkSetReorderPoints_ValidBatch_Returns200(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	first := testutil.CreateTestProduct(t, db).Variants[0]
+	second := testutil.CreateTestProduct(t, db).Variants[0]
+
+	body := fmt.Sprintf(`{"items":[{"variantId":"%s","reorderPoint":20},{"variantId":"%s","reorderPoint":5}]}`, first.ID, second.ID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products/reorder-points/bulk", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(2), data["updated"])
+
+	var reloaded models.ProductVariant
+	require.NoError(t, db.First(&reloaded, "id = ?", first.ID).Error)
+	assert.Equal(t, 20, reloaded.ReorderPoint)
+	require.NoError(t, db.First(&reloaded, "id = ?", second.ID).Error)
+	assert.Equal(t, 5, reloaded.ReorderPoint)
+}
+
+func TestBulkSetReorderPoints_NegativeValue_AppliesNothing(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	first := testutil.CreateTestProduct(t, db).Variants[0]
+	second := testutil.CreateTestProduct(t, db).Variants[0]
+
+	body := fmt.Sprintf(`{"items":[{"variantId":"%s","reorderPoint":20},{"variantId":"%s","reorderPoint":-1}]}`, first.ID, second.ID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products/reorder-points/bulk", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var reloaded models.ProductVariant
+	require.NoError(t, db.First(&reloaded, "id = ?", first.ID).Error)
+	assert.Equal(t, 0, reloaded.ReorderPoint)
+}
+
+func TestBulkSetReorderPoints_UnknownVariant_RollsBack(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	first := testutil.CreateTestProduct(t, db).Variants[0]
+
+	body := fmt.Sprintf(`{"items":[{"variantId":"%s","reorderPoint":20},{"variantId":"00000000-0000-0000-0000-000000000000","reorderPoint":5}]}`, first.ID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products/reorder-points/bulk", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	var reloaded models.ProductVariant
+	require.NoError(t, db.First(&reloaded, "id = ?", first.ID).Error)
+	assert.Equal(t, 0, reloaded.ReorderPoint)
+}
+
+func TestDeleteCheck_DeletableProduct_ReportsAllClear(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("product_id = ?", product.ID).Update("current_stock", 0).Error)
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/products/%d/delete-check", product.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, true, data["canDelete"])
+	assert.Equal(t, false, data["hasStock"])
+	assert.Equal(t, float64(0), data["variantsWithStock"])
+	assert.Equal(t, float64(0), data["purchaseOrderCount"])
+	assert.Empty(t, data["blockers"])
+}
+
+func TestDeleteCheck_StockAndPOReferences_ReportsBothReasons(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	supplier := testutil.CreateTestSupplier(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+	po := &models.PurchaseOrder{
+		PONumber:   fmt.Sprintf("PO-DELCHK-%d", product.ID),
+		SupplierID: supplier.ID,
+		Date:       "2026-01-15",
+		Status:     "draft",
+		Items: []models.PurchaseOrderItem{
+			{
+				ProductID:    product.ID,
+				VariantID:    variant.ID,
+				UnitID:       unit.ID,
+				UnitName:     unit.Name,
+				ProductName:  product.Name,
+				VariantLabel: "Default",
+				OrderedQty:   10,
+				Price:        15000,
+			},
+		},
+	}
+	require.NoError(t, db.Create(po).Error)
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/products/%d/delete-check", product.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, false, data["canDelete"])
+	assert.Equal(t, true, data["hasStock"])
+	assert.Equal(t, float64(1), data["variantsWithStock"])
+	assert.Equal(t, float64(1), data["purchaseOrderCount"])
+
+	blockers := data["blockers"].([]interface{})
+	require.Len(t, blockers, 2)
+	assert.Equal(t, "PRODUCT_HAS_STOCK", blockers[0].(map[string]interface{})["code"])
+	assert.Equal(t, "PRODUCT_IN_USE", blockers[1].(map[string]interface{})["code"])
+}
+
+func TestDeleteCheck_NotFound_Returns404(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/products/999999/delete-check", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGenerateLabels_TwoVariantsOnePerPage_ReturnsTwoPagePDF(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	first := testutil.CreateTestProduct(t, db).Variants[0]
+	second := testutil.CreateTestProduct(t, db).Variants[0]
+
+	body := fmt.Sprintf(`{"variantIds":["%s","%s"],"layout":"1x1"}`, first.ID, second.ID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products/labels", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/pdf", rr.Header().Get("Content-Type"))
+	pdf := rr.Body.Bytes()
+	require.NotEmpty(t, pdf)
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+	assert.Len(t, regexp.MustCompile(`/Type /Page\b[^s]`).FindAll(pdf, -1), 2)
+}
+
+func TestGenerateLabels_InvalidLayout_Returns400(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	variant := testutil.CreateTestProduct(t, db).Variants[0]
+
+	body := fmt.Sprintf(`{"variantIds":["%s"],"layout":"big"}`, variant.ID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products/labels", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestPreviewPriceChange_PercentageIncrease_ReturnsProjectedMarginsWithoutSaving(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", variant.ID).Update("cost_price", 8000).Error)
+
+	body := fmt.Sprintf(`{"productIds":[%d],"changeType":"percentage","value":20}`, product.ID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products/price-change/preview", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(1), data["variantCount"])
+	tiers := data["tiers"].([]interface{})
+	require.Len(t, tiers, 1)
+	tier := tiers[0].(map[string]interface{})
+	assert.Equal(t, float64(10000), tier["currentPrice"])
+	assert.Equal(t, float64(12000), tier["proposedPrice"])
+	assert.Equal(t, float64(20), tier["currentMarginPercent"])
+	assert.Equal(t, 33.33, tier["proposedMarginPercent"])
+
+	var stored models.VariantPricingTier
+	require.NoError(t, db.Where("variant_id = ?", variant.ID).First(&stored).Error)
+	assert.Equal(t, float64(10000), stored.Value)
+}
+
+func TestPreviewPriceChange_InvalidChangeType_Returns400(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	variant := testutil.CreateTestProduct(t, db).Variants[0]
+
+	body := fmt.Sprintf(`{"variantIds":["%s"],"changeType":"double","value":2}`, variant.ID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/products/price-change/preview", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetVariantReorderDetail_ComputesVelocityAndSuggestedQty(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", variant.ID).
+		Updates(map[string]interface{}{"current_stock": 10, "reorder_point": 20}).Error)
+	supplier := testutil.CreateTestSupplier(t, db)
+	require.NoError(t, db.Model(product).Association("Suppliers").Append(supplier))
+	otherSupplier := testutil.CreateTestSupplier(t, db)
+
+	createSale := func(date time.Time, qty int) {
+		sale := &models.SalesTransaction{
+			TransactionNumber: fmt.Sprintf("TRX-%d", date.UnixNano()),
+			Date:              date,
+			Subtotal:          float64(qty) * 1000,
+			GrandTotal:        float64(qty) * 1000,
+			TotalItems:        1,
+			PaymentMethod:     "cash",
+			Items: []models.SalesTransactionItem{{
+				ProductID:   product.ID,
+				VariantID:   variant.ID,
+				UnitID:      product.Units[0].ID,
+				ProductName: product.Name,
+				UnitName:    "Pcs",
+				Quantity:    qty,
+				BaseQty:     qty,
+				UnitPrice:   1000,
+				TotalPrice:  float64(qty) * 1000,
+			}},
+		}
+		require.NoError(t, db.Create(sale).Error)
+	}
+	createSale(time.Now().AddDate(0, 0, -3), 30)
+	createSale(time.Now().AddDate(0, 0, -10), 15)
+	createSale(time.Now().AddDate(0, 0, -45), 90) // outside the 30-day window
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	createReceivedPO := func(supplierID uint, orderedDaysAgo, receivedDaysAgo int) {
+		received := today.AddDate(0, 0, -receivedDaysAgo)
+		po := &models.PurchaseOrder{
+			PONumber:     fmt.Sprintf("PO-%d-%d-%d", supplierID, orderedDaysAgo, receivedDaysAgo),
+			SupplierID:   supplierID,
+			Date:         today.AddDate(0, 0, -orderedDaysAgo).Format("2006-01-02"),
+			Status:       "received",
+			ReceivedDate: &received,
+		}
+		require.NoError(t, db.Create(po).Error)
+	}
+	createReceivedPO(supplier.ID, 10, 6)      // 4 days
+	createReceivedPO(supplier.ID, 20, 12)     // 8 days
+	createReceivedPO(otherSupplier.ID, 40, 0) // not a supplier of this product
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/products/variants/%s/reorder", variant.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(10), data["currentStock"])
+	assert.Equal(t, float64(20), data["reorderPoint"])
+	assert.Equal(t, true, data["belowReorderPoint"])
+	assert.Equal(t, float64(45), data["quantitySold"])
+	assert.Equal(t, 1.5, data["dailyVelocity"])
+	assert.Equal(t, float64(6), data["avgLeadTimeDays"])
+	assert.Equal(t, float64(2), data["leadTimeOrders"])
+	// 20 reorder point + ceil(1.5/day * (6 + 14) days) - 10 in stock
+	assert.Equal(t, float64(40), data["suggestedQty"])
+}
+
+func TestGetVariantReorderDetail_NoOrders_UsesDefaultLeadTime(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/products/variants/%s/reorder", variant.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Nil(t, data["avgLeadTimeDays"])
+	assert.Equal(t, float64(7), data["leadTimeDays"])
+	assert.Equal(t, float64(0), data["dailyVelocity"])
+	assert.Equal(t, float64(0), data["suggestedQty"])
+}
+
+func TestGetVariantReorderDetail_NotFound_Returns404(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/products/variants/00000000-0000-0000-0000-000000000000/reorder", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusNotFound, "Variant not found")
+}
+
+func TestGetProductOverview_ComputesDaysOfCoverAndReorderFlag(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	steady := product.Variants[0]
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", steady.ID).
+		Updates(map[string]interface{}{"current_stock": 30, "reorder_point": 10}).Error)
+	fast := &models.ProductVariant{ProductID: product.ID, SKU: "OVR-FAST", CurrentStock: 10, ReorderPoint: 5}
+	require.NoError(t, db.Create(fast).Error)
+	idle := &models.ProductVariant{ProductID: product.ID, SKU: "OVR-IDLE", CurrentStock: 3, ReorderPoint: 5}
+	require.NoError(t, db.Create(idle).Error)
+
+	createSale := func(variantID string, date time.Time, qty int) {
+		sale := &models.SalesTransaction{
+			TransactionNumber: fmt.Sprintf("TRX-%d", date.UnixNano()),
+			Date:              date,
+			Subtotal:          float64(qty) * 1000,
+			GrandTotal:        float64(qty) * 1000,
+			TotalItems:        1,
+			PaymentMethod:     "cash",
+			Items: []models.SalesTransactionItem{{
+				ProductID:   product.ID,
+				VariantID:   variantID,
+				UnitID:      product.Units[0].ID,
+				ProductName: product.Name,
+				UnitName:    "Pcs",
+				Quantity:    qty,
+				BaseQty:     qty,
+				UnitPrice:   1000,
+				TotalPrice:  float64(qty) * 1000,
+			}},
+		}
+		require.NoError(t, db.Create(sale).Error)
+	}
+	createSale(steady.ID, time.Now().AddDate(0, 0, -2), 60)
+	createSale(steady.ID, time.Now().AddDate(0, 0, -45), 90) // outside the 30-day window
+	createSale(fast.ID, time.Now().AddDate(0, 0, -5), 45)
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/products/%d/overview", product.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(30), data["windowDays"])
+	assert.Equal(t, float64(7), data["leadTimeDays"])
+	assert.Equal(t, float64(43), data["totalStock"])
+	assert.Equal(t, float64(105), data["totalQuantitySold"])
+
+	variants := data["variants"].([]interface{})
+	require.Len(t, variants, 3)
+	byID := make(map[string]map[string]interface{}, len(variants))
+	for _, v := range variants {
+		item := v.(map[string]interface{})
+		byID[item["variantId"].(string)] = item
+	}
+
+	// 60 sold over 30 days is 2 a day, so 30 in stock lasts 15 days
+	assert.Equal(t, float64(60), byID[steady.ID]["quantitySold"])
+	assert.Equal(t, float64(2), byID[steady.ID]["dailyVelocity"])
+	assert.Equal(t, float64(15), byID[steady.ID]["daysOfCover"])
+	assert.Equal(t, false, byID[steady.ID]["reorder"])
+
+	// 1.5 a day runs 10 in stock out in 6.67 days, within the 7-day lead time
+	assert.Equal(t, 1.5, byID[fast.ID]["dailyVelocity"])
+	assert.Equal(t, 6.67, byID[fast.ID]["daysOfCover"])
+	assert.Equal(t, true, byID[fast.ID]["reorder"])
+
+	// No sales: no days of cover, but below the reorder point
+	assert.Equal(t, float64(0), byID[idle.ID]["quantitySold"])
+	assert.Nil(t, byID[idle.ID]["daysOfCover"])
+	assert.Equal(t, true, byID[idle.ID]["reorder"])
+}
+
+func TestGetProductOverview_NotFound_Returns404(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/products/999999/overview", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func newProductImageUploadRequest(t *testing.T, productID uint, content []byte, token string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "photo")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/products/%d/images", productID), &body, token)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+var samplePNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+func TestUploadProductImage_PNG_AppendsWithNextSortOrder(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	require.NoError(t, db.Create(&models.ProductImage{ProductID: product.ID, ImageURL: "http://files.local/existing.jpg", SortOrder: 4}).Error)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newProductImageUploadRequest(t, product.ID, samplePNG, token))
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.Equal(t, float64(product.ID), data["productId"])
+	assert.Equal(t, float64(5), data["sortOrder"])
+	imageURL, _ := data["imageUrl"].(string)
+	assert.Regexp(t, fmt.Sprintf(`^http://files\.local/products/%d/.+\.png$`, product.ID), imageURL)
+
+	var count int64
+	db.Model(&models.ProductImage{}).Where("product_id = ?", product.ID).Count(&count)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestUploadProductImage_UnsupportedType_Returns400(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newProductImageUploadRequest(t, product.ID, []byte("%PDF-1.4\n%%EOF\n"), token))
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "Only JPEG, PNG and WebP images")
+}
+
+func TestUploadProductImage_Oversize_Returns413(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	oversize := append(append([]byte{}, samplePNG...), make([]byte, services.DefaultProductImageMaxBytes)...)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newProductImageUploadRequest(t, product.ID, oversize, token))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+	var count int64
+	db.Model(&models.ProductImage{}).Where("product_id = ?", product.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestUploadProductImage_ProductNotFound_Returns404(t *testing.T) {
+	router, db, _, _ := setupProductTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupProductTestUserWithPermission(t, db, []string{"read", "update"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newProductImageUploadRequest(t, 999999, samplePNG, token))
+
+	testutil.AssertErrorResponse(t, rr, http.StatusNotFound, "Product not found")
 }
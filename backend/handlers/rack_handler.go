@@ -158,3 +158,68 @@ func (h *RackHandler) DeleteRack(w http.ResponseWriter, r *http.Request) {
 
 	utils.Success(w, http.StatusOK, "Rack deleted successfully", nil)
 }
+
+// EvacuateRack handles POST /api/v1/racks/{id}/evacuate
+// Body: {"toRackId": 2}. Moves all stock placed on the rack to the given rack.
+func (h *RackHandler) EvacuateRack(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid rack ID", "VALIDATION_ERROR")
+		return
+	}
+
+	var input services.EvacuateRackInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+
+	result, serviceErr := h.rackService.EvacuateStock(uint(id), input.ToRackID)
+	if serviceErr != nil {
+		status := http.StatusInternalServerError
+		switch serviceErr.Err {
+		case services.ErrValidation:
+			status = http.StatusBadRequest
+		case services.ErrNotFound:
+			status = http.StatusNotFound
+		}
+		utils.Error(w, status, serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Rack stock evacuated successfully", result)
+}
+
+// ListRackStock handles GET /api/v1/racks/{id}/stock
+// Optional query params: page, pageSize, activeOnly=true to leave out
+// variants of inactive products.
+func (h *RackHandler) ListRackStock(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid rack ID", "VALIDATION_ERROR")
+		return
+	}
+
+	params, err := utils.ParsePaginationParams(r, nil)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+	activeOnly := r.URL.Query().Get("activeOnly") == "true"
+
+	items, total, serviceErr := h.rackService.ListStock(uint(id), params.Page, params.PageSize, activeOnly)
+	if serviceErr != nil {
+		status := http.StatusInternalServerError
+		if serviceErr.Err == services.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		utils.Error(w, status, serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	meta := utils.CalculatePaginationMeta(params.Page, params.PageSize, int(total))
+	utils.JSON(w, http.StatusOK, utils.PaginatedResponse{
+		Data: items,
+		Meta: meta,
+	})
+}
@@ -48,6 +48,8 @@ func setupRackTestRouter(t *testing.T) (chi.Router, *gorm.DB) {
 	r.Route("/api/v1/racks", func(r chi.Router) {
 		r.Get("/", rackHandler.ListRacks)
 		r.Get("/{id}", rackHandler.GetRack)
+		r.Get("/{id}/stock", rackHandler.ListRackStock)
+		r.Post("/{id}/evacuate", rackHandler.EvacuateRack)
 		r.Post("/", rackHandler.CreateRack)
 		r.Put("/{id}", rackHandler.UpdateRack)
 		r.Delete("/{id}", rackHandler.DeleteRack)
@@ -540,3 +542,119 @@ func TestGetRack_InvalidID_Returns400(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
+
+// TestListRackStock_ActiveOnly_ExcludesInactiveProducts verifies the stock
+// listing and its activeOnly filter
+func TestListRackStock_ActiveOnly_ExcludesInactiveProducts(t *testing.T) {
+	router, db := setupRackTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	rack := testutil.CreateTestRack(t, db)
+	active := testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.Name = "Active Product"
+	})
+	inactive := testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.Name = "Inactive Product"
+		p.Status = "inactive"
+	})
+	require.NoError(t, db.Exec("INSERT INTO variant_racks (variant_id, rack_id, quantity) VALUES (?, ?, ?), (?, ?, ?)",
+		active.Variants[0].ID, rack.ID, 30, inactive.Variants[0].ID, rack.ID, 5).Error)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/racks/%d/stock", rack.ID), nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	items := response["data"].([]interface{})
+	require.Len(t, items, 2)
+	first := items[0].(map[string]interface{})
+	assert.Equal(t, "Active Product", first["productName"])
+	assert.Equal(t, active.Variants[0].SKU, first["sku"])
+	assert.Equal(t, "Default", first["variantLabel"])
+	assert.Equal(t, float64(30), first["rackQuantity"])
+	assert.Equal(t, float64(100), first["currentStock"])
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/racks/%d/stock?activeOnly=true", rack.ID), nil)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	items = response["data"].([]interface{})
+	require.Len(t, items, 1)
+	assert.Equal(t, "Active Product", items[0].(map[string]interface{})["productName"])
+}
+
+// TestListRackStock_NotFound_Returns404 verifies 404 for non-existent rack
+func TestListRackStock_NotFound_Returns404(t *testing.T) {
+	router, db := setupRackTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	req := httptest.NewRequest("GET", "/api/v1/racks/99999/stock", nil)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+// TestEvacuateRack_MovesAllStock_LeavesSourceEmpty verifies every quantity is moved and merged
+func TestEvacuateRack_MovesAllStock_LeavesSourceEmpty(t *testing.T) {
+	router, db := setupRackTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	source := testutil.CreateTestRack(t, db)
+	target := testutil.CreateTestRack(t, db)
+	first := testutil.CreateTestProduct(t, db)
+	second := testutil.CreateTestProduct(t, db)
+	require.NoError(t, db.Exec("INSERT INTO variant_racks (variant_id, rack_id, quantity) VALUES (?, ?, ?), (?, ?, ?), (?, ?, ?)",
+		first.Variants[0].ID, source.ID, 30,
+		second.Variants[0].ID, source.ID, 5,
+		second.Variants[0].ID, target.ID, 7).Error)
+
+	body := fmt.Sprintf(`{"toRackId": %d}`, target.ID)
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/racks/%d/evacuate", source.ID), strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(2), data["variantsMoved"])
+	assert.Equal(t, float64(35), data["quantityMoved"])
+
+	var sourceRows int64
+	require.NoError(t, db.Table("variant_racks").Where("rack_id = ?", source.ID).Count(&sourceRows).Error)
+	assert.Zero(t, sourceRows)
+
+	quantity := func(variantID string) int {
+		var q int
+		require.NoError(t, db.Table("variant_racks").Select("quantity").
+			Where("variant_id = ? AND rack_id = ?", variantID, target.ID).Scan(&q).Error)
+		return q
+	}
+	assert.Equal(t, 30, quantity(first.Variants[0].ID))
+	assert.Equal(t, 12, quantity(second.Variants[0].ID))
+
+	// The emptied rack can now be deleted
+	req = httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/racks/%d", source.ID), nil)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, 12, quantity(second.Variants[0].ID))
+}
+
+// TestEvacuateRack_SameRack_Returns400 verifies a rack cannot be evacuated onto itself
+func TestEvacuateRack_SameRack_Returns400(t *testing.T) {
+	router, db := setupRackTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	rack := testutil.CreateTestRack(t, db)
+
+	body := fmt.Sprintf(`{"toRackId": %d}`, rack.ID)
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/racks/%d/evacuate", rack.ID), strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "Destination rack must differ")
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pointofsale/backend/services"
+	"github.com/pointofsale/backend/utils"
+)
+
+// ReportHandler handles HTTP requests for report endpoints.
+type ReportHandler struct {
+	reportService *services.ReportService
+}
+
+// NewReportHandler creates a new report handler instance.
+func NewReportHandler(reportService *services.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// SalesTimeSeries handles GET /api/v1/reports/sales/timeseries?from=&to=&interval=
+// Dates are YYYY-MM-DD; the range defaults to the last 30 days.
+func (h *ReportHandler) SalesTimeSeries(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := parseReportDateRange(w, r, h.reportService.Today())
+	if !ok {
+		return
+	}
+
+	series, err := h.reportService.SalesTimeSeries(from, to, r.URL.Query().Get("interval"))
+	if err != nil {
+		writeReportError(w, err, "Failed to load sales time series")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", series)
+}
+
+// HourlyHeatmap handles GET /api/v1/reports/heatmap?from=&to=
+// Dates are YYYY-MM-DD; the range defaults to the last 30 days.
+func (h *ReportHandler) HourlyHeatmap(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := parseReportDateRange(w, r, h.reportService.Today())
+	if !ok {
+		return
+	}
+
+	heatmap, err := h.reportService.HourlyHeatmap(from, to)
+	if err != nil {
+		writeReportError(w, err, "Failed to load sales heatmap")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", heatmap)
+}
+
+// ProfitSummary handles GET /api/v1/reports/profit?from=&to=&groupBy=
+// Dates are YYYY-MM-DD; the range defaults to the last 30 days and groupBy to day.
+func (h *ReportHandler) ProfitSummary(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := parseReportDateRange(w, r, h.reportService.Today())
+	if !ok {
+		return
+	}
+
+	summary, err := h.reportService.ProfitSummary(from, to, r.URL.Query().Get("groupBy"))
+	if err != nil {
+		writeReportError(w, err, "Failed to load profit summary")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", summary)
+}
+
+// CashierPerformance handles GET /api/v1/reports/cashiers?from=&to=
+// Dates are YYYY-MM-DD; the range defaults to the last 30 days.
+func (h *ReportHandler) CashierPerformance(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := parseReportDateRange(w, r, h.reportService.Today())
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.CashierPerformance(from, to)
+	if err != nil {
+		writeReportError(w, err, "Failed to load cashier performance")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", report)
+}
+
+// PermissionDenials handles GET /api/v1/reports/permission-denials?from=&to=&minDenials=
+// Dates are YYYY-MM-DD; the range defaults to the last 30 days and minDenials to 3.
+func (h *ReportHandler) PermissionDenials(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := parseReportDateRange(w, r, h.reportService.Today())
+	if !ok {
+		return
+	}
+
+	minDenials := 3
+	if v := r.URL.Query().Get("minDenials"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid 'minDenials', expected a number", "VALIDATION_ERROR")
+			return
+		}
+		minDenials = n
+	}
+
+	report, err := h.reportService.PermissionDenials(from, to, minDenials)
+	if err != nil {
+		writeReportError(w, err, "Failed to load permission denials")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", report)
+}
+
+// parseReportDateRange reads the from and to query dates, defaulting to the
+// 30 days ending today, the store's current date. It writes a 400 response on
+// invalid input.
+func parseReportDateRange(w http.ResponseWriter, r *http.Request, today time.Time) (time.Time, time.Time, bool) {
+	to := today
+	var err error
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		to, err = time.Parse("2006-01-02", toStr)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid 'to' date, expected YYYY-MM-DD", "VALIDATION_ERROR")
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	from := to.AddDate(0, 0, -29)
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		from, err = time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid 'from' date, expected YYYY-MM-DD", "VALIDATION_ERROR")
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	return from, to, true
+}
+
+// writeReportError maps a report service error to an HTTP response.
+func writeReportError(w http.ResponseWriter, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+	code := "INTERNAL_ERROR"
+
+	if serviceErr, ok := err.(*services.ServiceError); ok {
+		message = serviceErr.Message
+		code = serviceErr.Code
+		if serviceErr.Err == services.ErrValidation {
+			status = http.StatusBadRequest
+		}
+	}
+	utils.Error(w, status, message, code)
+}
@@ -0,0 +1,611 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/pointofsale/backend/config"
+	"github.com/pointofsale/backend/middleware"
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/services"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupReportTestRouter(t *testing.T) (chi.Router, *gorm.DB) {
+	t.Helper()
+
+	db := testutil.SetupTestDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	cfg := &config.Config{
+		JWTAccessSecret: testutil.TestJWTAccessSecret,
+	}
+
+	userRepo := repositories.NewUserRepository(db)
+	reportService := services.NewReportService(repositories.NewReportRepository(db))
+	reportHandler := NewReportHandler(reportService)
+
+	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTAccessSecret, rdb, userRepo)
+	permMiddleware := middleware.NewPermissionMiddleware(db, rdb)
+
+	r := chi.NewRouter()
+	r.Route("/api/v1/reports", func(r chi.Router) {
+		r.Use(authMiddleware.Authenticate)
+		r.With(permMiddleware.RequirePermission("Report", "Sales Report", "read")).Get("/sales/timeseries", reportHandler.SalesTimeSeries)
+		r.With(permMiddleware.RequirePermission("Report", "Sales Report", "read")).Get("/heatmap", reportHandler.HourlyHeatmap)
+		r.With(permMiddleware.RequirePermission("Report", "Sales Report", "read")).Get("/profit", reportHandler.ProfitSummary)
+		r.With(permMiddleware.RequirePermission("Report", "Sales Report", "read")).Get("/cashiers", reportHandler.CashierPerformance)
+		r.With(permMiddleware.RequirePermission("Settings", "Roles & Permissions", "read")).Get("/permission-denials", reportHandler.PermissionDenials)
+	})
+
+	return r, db
+}
+
+func setupReportTestUserWithPermission(t *testing.T, db *gorm.DB, actions []string) *models.User {
+	t.Helper()
+
+	perm := testutil.CreateTestPermission(t, db, func(p *models.Permission) {
+		p.Module = "Report"
+		p.Feature = "Sales Report"
+		p.Actions = actions
+	})
+
+	role := testutil.CreateTestRole(t, db)
+	rolePerm := &models.RolePermission{
+		RoleID:       role.ID,
+		PermissionID: perm.ID,
+		Actions:      actions,
+	}
+	require.NoError(t, db.Create(rolePerm).Error)
+
+	return testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Roles = []models.Role{*role}
+	})
+}
+
+func createReportSale(t *testing.T, db *gorm.DB, date time.Time, subtotal float64) {
+	t.Helper()
+	sale := &models.SalesTransaction{
+		TransactionNumber: fmt.Sprintf("TRX-%d", date.UnixNano()),
+		Date:              date,
+		Subtotal:          subtotal,
+		GrandTotal:        subtotal,
+		TotalItems:        1,
+		PaymentMethod:     "cash",
+	}
+	require.NoError(t, db.Create(sale).Error)
+}
+
+func createCashierSale(t *testing.T, db *gorm.DB, cashierID uint, date time.Time, subtotal, surcharge float64) {
+	t.Helper()
+	sale := &models.SalesTransaction{
+		TransactionNumber: fmt.Sprintf("TRX-%d-%d", cashierID, date.UnixNano()),
+		Date:              date,
+		Subtotal:          subtotal,
+		SurchargeAmount:   surcharge,
+		GrandTotal:        subtotal + surcharge,
+		TotalItems:        1,
+		PaymentMethod:     "cash",
+		CashierID:         &cashierID,
+	}
+	require.NoError(t, db.Create(sale).Error)
+}
+
+func TestHourlyHeatmap_SalesAtKnownTimes_PopulatesCells(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	user := setupReportTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	// 2026-03-02 is a Monday, 2026-03-06 a Friday
+	createReportSale(t, db, time.Date(2026, 3, 2, 10, 15, 0, 0, time.UTC), 10000)
+	createReportSale(t, db, time.Date(2026, 3, 2, 10, 50, 0, 0, time.UTC), 20000)
+	createReportSale(t, db, time.Date(2026, 3, 6, 19, 0, 0, 0, time.UTC), 7500)
+	// Outside the range
+	createReportSale(t, db, time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC), 99000)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/heatmap?from=2026-03-01&to=2026-03-07", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(3), data["transactions"])
+	assert.Equal(t, float64(37500), data["revenue"])
+
+	days := data["days"].([]interface{})
+	require.Len(t, days, 7)
+	cell := func(day, hour int) map[string]interface{} {
+		hours := days[day].(map[string]interface{})["hours"].([]interface{})
+		return hours[hour].(map[string]interface{})
+	}
+
+	assert.Equal(t, "Monday", days[1].(map[string]interface{})["day"])
+	assert.Equal(t, float64(2), cell(1, 10)["transactions"])
+	assert.Equal(t, float64(30000), cell(1, 10)["revenue"])
+	assert.Equal(t, float64(1), cell(5, 19)["transactions"])
+	assert.Equal(t, float64(7500), cell(5, 19)["revenue"])
+	assert.Equal(t, float64(0), cell(1, 11)["transactions"])
+}
+
+func TestHourlyHeatmap_NoPermission_Returns403(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	user := testutil.CreateTestUser(t, db)
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/heatmap", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestCashierPerformance_TwoCashiers_ReturnsEachAggregate(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	user := setupReportTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	alice := testutil.CreateTestUser(t, db, func(u *models.User) { u.Name = "Alice" })
+	bob := testutil.CreateTestUser(t, db, func(u *models.User) { u.Name = "Bob" })
+
+	createCashierSale(t, db, alice.ID, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), 10000, 0)
+	createCashierSale(t, db, alice.ID, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC), 20000, 200)
+	createCashierSale(t, db, bob.ID, time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), 50000, 0)
+	// Outside the range
+	createCashierSale(t, db, bob.ID, time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC), 99000, 0)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/cashiers?from=2026-03-01&to=2026-03-07", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	cashiers := data["cashiers"].([]interface{})
+	require.Len(t, cashiers, 2)
+
+	first := cashiers[0].(map[string]interface{})
+	assert.Equal(t, "Bob", first["cashierName"])
+	assert.Equal(t, float64(bob.ID), first["cashierId"])
+	assert.Equal(t, float64(1), first["transactions"])
+	assert.Equal(t, float64(50000), first["gross"])
+	assert.Equal(t, float64(50000), first["averageBasket"])
+
+	second := cashiers[1].(map[string]interface{})
+	assert.Equal(t, "Alice", second["cashierName"])
+	assert.Equal(t, float64(2), second["transactions"])
+	assert.Equal(t, float64(30200), second["gross"])
+	assert.Equal(t, float64(30000), second["net"])
+	assert.Equal(t, float64(200), second["surcharges"])
+	assert.Equal(t, float64(15100), second["averageBasket"])
+}
+
+func TestCashierPerformance_Discounts_SumsSaleAndLineDiscounts(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	user := setupReportTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	alice := testutil.CreateTestUser(t, db, func(u *models.User) { u.Name = "Alice" })
+	product := testutil.CreateTestProduct(t, db)
+	sale := &models.SalesTransaction{
+		TransactionNumber: "TRX-DISC-1",
+		Date:              time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		Subtotal:          18000,
+		Discount:          1000,
+		GrandTotal:        17000,
+		TotalItems:        1,
+		PaymentMethod:     "cash",
+		CashierID:         &alice.ID,
+		Items: []models.SalesTransactionItem{{
+			ProductID:       product.ID,
+			VariantID:       product.Variants[0].ID,
+			UnitID:          product.Units[0].ID,
+			ProductName:     product.Name,
+			UnitName:        product.Units[0].Name,
+			Quantity:        2,
+			BaseQty:         2,
+			UnitPrice:       9000,
+			TotalPrice:      18000,
+			DiscountPercent: 10,
+			DiscountAmount:  2000,
+		}},
+	}
+	require.NoError(t, db.Create(sale).Error)
+	createCashierSale(t, db, alice.ID, time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC), 5000, 0)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/cashiers?from=2026-03-01&to=2026-03-07", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	cashiers := data["cashiers"].([]interface{})
+	require.Len(t, cashiers, 1)
+	assert.Equal(t, float64(3000), cashiers[0].(map[string]interface{})["discounts"])
+}
+
+func createProfitSale(t *testing.T, db *gorm.DB, product *models.Product, date time.Time, qty int, unitPrice float64, unitCost *float64) models.SalesTransactionItem {
+	t.Helper()
+	total := float64(qty) * unitPrice
+	sale := &models.SalesTransaction{
+		TransactionNumber: fmt.Sprintf("TRX-P-%d", date.UnixNano()),
+		Date:              date,
+		Subtotal:          total,
+		GrandTotal:        total,
+		TotalItems:        qty,
+		PaymentMethod:     "cash",
+		Items: []models.SalesTransactionItem{{
+			ProductID:   product.ID,
+			VariantID:   product.Variants[0].ID,
+			UnitID:      product.Units[0].ID,
+			ProductName: product.Name,
+			UnitName:    product.Units[0].Name,
+			Quantity:    qty,
+			BaseQty:     qty,
+			UnitPrice:   unitPrice,
+			TotalPrice:  total,
+			UnitCost:    unitCost,
+		}},
+	}
+	require.NoError(t, db.Create(sale).Error)
+	return sale.Items[0]
+}
+
+func TestProfitSummary_ProductGroups_ReturnReducesProfit(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	user := setupReportTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	rice := testutil.CreateTestProduct(t, db)
+	soap := testutil.CreateTestProduct(t, db)
+	riceCost, soapCost := 7000.0, 2500.0
+
+	riceItem := createProfitSale(t, db, rice, time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC), 4, 10000, &riceCost)
+	createProfitSale(t, db, soap, time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC), 2, 4000, &soapCost)
+	createProfitSale(t, db, soap, time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC), 1, 4000, nil)
+	// Outside the range
+	createProfitSale(t, db, rice, time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC), 9, 10000, &riceCost)
+
+	// One rice returned within the range
+	ret := &models.SalesReturn{
+		ReturnNumber:  "RET-P-1",
+		TransactionID: riceItem.TransactionID,
+		Date:          time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC),
+		RefundMethod:  "cash",
+		RefundAmount:  10000,
+		TotalItems:    1,
+		Items: []models.SalesReturnItem{{
+			TransactionItemID: riceItem.ID,
+			VariantID:         riceItem.VariantID,
+			ProductName:       riceItem.ProductName,
+			UnitName:          riceItem.UnitName,
+			Quantity:          1,
+			BaseQty:           1,
+			UnitPrice:         10000,
+			RefundAmount:      10000,
+		}},
+	}
+	require.NoError(t, db.Create(ret).Error)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/profit?from=2026-03-01&to=2026-03-07&groupBy=product", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, "product", data["groupBy"])
+	groups := data["groups"].([]interface{})
+	require.Len(t, groups, 2)
+
+	first := groups[0].(map[string]interface{})
+	assert.Equal(t, rice.Name, first["label"])
+	assert.Equal(t, float64(30000), first["revenue"])
+	assert.Equal(t, float64(21000), first["cost"])
+	assert.Equal(t, float64(9000), first["profit"])
+	assert.Equal(t, float64(30), first["margin"])
+
+	second := groups[1].(map[string]interface{})
+	assert.Equal(t, soap.Name, second["label"])
+	assert.Equal(t, float64(12000), second["revenue"])
+	assert.Equal(t, float64(5000), second["cost"])
+	assert.Equal(t, float64(7000), second["profit"])
+
+	assert.Equal(t, float64(42000), data["revenue"])
+	assert.Equal(t, float64(16000), data["profit"])
+	assert.Equal(t, float64(1), data["uncostedLines"])
+}
+
+func TestProfitSummary_SaleDiscount_SharedAcrossLinesByValue(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	user := setupReportTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	rice := testutil.CreateTestProduct(t, db)
+	soap := testutil.CreateTestProduct(t, db)
+	riceCost, soapCost := 7000.0, 2500.0
+
+	// 40000 of rice and 10000 of soap with 5000 off the whole sale: rice
+	// carries 4000 of the discount and soap 1000
+	sale := &models.SalesTransaction{
+		TransactionNumber: "TRX-P-DISC",
+		Date:              time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+		Subtotal:          50000,
+		Discount:          5000,
+		GrandTotal:        45000,
+		TotalItems:        6,
+		PaymentMethod:     "cash",
+		Items: []models.SalesTransactionItem{
+			{
+				ProductID: rice.ID, VariantID: rice.Variants[0].ID, UnitID: rice.Units[0].ID,
+				ProductName: rice.Name, UnitName: rice.Units[0].Name,
+				Quantity: 4, BaseQty: 4, UnitPrice: 10000, TotalPrice: 40000, UnitCost: &riceCost,
+			},
+			{
+				ProductID: soap.ID, VariantID: soap.Variants[0].ID, UnitID: soap.Units[0].ID,
+				ProductName: soap.Name, UnitName: soap.Units[0].Name,
+				Quantity: 2, BaseQty: 2, UnitPrice: 5000, TotalPrice: 10000, UnitCost: &soapCost,
+			},
+		},
+	}
+	require.NoError(t, db.Create(sale).Error)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/profit?from=2026-03-01&to=2026-03-07&groupBy=product", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	groups := data["groups"].([]interface{})
+	require.Len(t, groups, 2)
+
+	first := groups[0].(map[string]interface{})
+	assert.Equal(t, rice.Name, first["label"])
+	assert.Equal(t, float64(36000), first["revenue"])
+	assert.Equal(t, float64(8000), first["profit"])
+
+	second := groups[1].(map[string]interface{})
+	assert.Equal(t, soap.Name, second["label"])
+	assert.Equal(t, float64(9000), second["revenue"])
+	assert.Equal(t, float64(4000), second["profit"])
+
+	assert.Equal(t, float64(45000), data["revenue"])
+}
+
+func TestProfitSummary_Tax_ExcludedFromSalesAndReturns(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	user := setupReportTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	rice := testutil.CreateTestProduct(t, db)
+	soap := testutil.CreateTestProduct(t, db)
+	riceCost, soapCost := 7000.0, 6000.0
+
+	// 10% tax on top of two rice at 10000, one of which is returned with
+	// its tax refunded
+	riceSale := &models.SalesTransaction{
+		TransactionNumber: "TRX-P-TAX-EX",
+		Date:              time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC),
+		Subtotal:          20000,
+		TaxPercent:        10,
+		TaxAmount:         2000,
+		GrandTotal:        22000,
+		TotalItems:        2,
+		PaymentMethod:     "cash",
+		Items: []models.SalesTransactionItem{{
+			ProductID: rice.ID, VariantID: rice.Variants[0].ID, UnitID: rice.Units[0].ID,
+			ProductName: rice.Name, UnitName: rice.Units[0].Name,
+			Quantity: 2, BaseQty: 2, UnitPrice: 10000, TotalPrice: 20000, UnitCost: &riceCost,
+		}},
+	}
+	require.NoError(t, db.Create(riceSale).Error)
+	riceItem := riceSale.Items[0]
+	ret := &models.SalesReturn{
+		ReturnNumber:  "RET-P-TAX",
+		TransactionID: riceSale.ID,
+		Date:          time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC),
+		RefundMethod:  "cash",
+		RefundAmount:  11000,
+		TotalItems:    1,
+		Items: []models.SalesReturnItem{{
+			TransactionItemID: riceItem.ID,
+			VariantID:         riceItem.VariantID,
+			ProductName:       riceItem.ProductName,
+			UnitName:          riceItem.UnitName,
+			Quantity:          1,
+			BaseQty:           1,
+			UnitPrice:         10000,
+			RefundAmount:      11000,
+		}},
+	}
+	require.NoError(t, db.Create(ret).Error)
+
+	// 10% tax included in a soap priced 11000
+	soapSale := &models.SalesTransaction{
+		TransactionNumber: "TRX-P-TAX-IN",
+		Date:              time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC),
+		Subtotal:          11000,
+		TaxPercent:        10,
+		TaxInclusive:      true,
+		TaxAmount:         1000,
+		GrandTotal:        11000,
+		TotalItems:        1,
+		PaymentMethod:     "cash",
+		Items: []models.SalesTransactionItem{{
+			ProductID: soap.ID, VariantID: soap.Variants[0].ID, UnitID: soap.Units[0].ID,
+			ProductName: soap.Name, UnitName: soap.Units[0].Name,
+			Quantity: 1, BaseQty: 1, UnitPrice: 11000, TotalPrice: 11000, UnitCost: &soapCost,
+		}},
+	}
+	require.NoError(t, db.Create(soapSale).Error)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/profit?from=2026-03-01&to=2026-03-07&groupBy=product", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	groups := data["groups"].([]interface{})
+	require.Len(t, groups, 2)
+
+	first := groups[0].(map[string]interface{})
+	assert.Equal(t, soap.Name, first["label"])
+	assert.Equal(t, float64(10000), first["revenue"])
+	assert.Equal(t, float64(4000), first["profit"])
+
+	second := groups[1].(map[string]interface{})
+	assert.Equal(t, rice.Name, second["label"])
+	assert.Equal(t, float64(10000), second["revenue"])
+	assert.Equal(t, float64(7000), second["cost"])
+	assert.Equal(t, float64(3000), second["profit"])
+
+	assert.Equal(t, float64(20000), data["revenue"])
+}
+
+func TestProfitSummary_InvalidGroupBy_Returns400(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	user := setupReportTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/profit?groupBy=week", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "groupBy must be one of")
+}
+
+func TestCashierPerformance_NoPermission_Returns403(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	user := testutil.CreateTestUser(t, db)
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/cashiers", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestSalesTimeSeries_DailyBuckets_ZeroFillsGaps(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	user := setupReportTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	createReportSale(t, db, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), 15000)
+	createReportSale(t, db, time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC), 5000)
+	createReportSale(t, db, time.Date(2026, 3, 3, 11, 0, 0, 0, time.UTC), 12000)
+	createReportSale(t, db, time.Date(2026, 3, 5, 11, 0, 0, 0, time.UTC), 99000)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/sales/timeseries?from=2026-03-01&to=2026-03-04&interval=day", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	buckets := data["buckets"].([]interface{})
+	require.Len(t, buckets, 4)
+
+	netSales := make([]float64, 0, len(buckets))
+	for _, bucket := range buckets {
+		netSales = append(netSales, bucket.(map[string]interface{})["netSales"].(float64))
+	}
+	assert.Equal(t, []float64{20000, 0, 12000, 0}, netSales)
+	assert.Equal(t, float64(32000), data["total"])
+}
+
+func TestSalesTimeSeries_InvalidInterval_Returns400(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	user := setupReportTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/sales/timeseries?interval=week", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestSalesTimeSeries_NoPermission_Returns403(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	user := testutil.CreateTestUser(t, db)
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/sales/timeseries", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func createPermissionDenial(t *testing.T, db *gorm.DB, userID uint, route, permission string, at time.Time) {
+	t.Helper()
+	entry := &models.AuditLog{
+		Event:      models.AuditEventPermissionDenied,
+		UserID:     &userID,
+		Method:     "DELETE",
+		Path:       route,
+		Route:      route,
+		Permission: permission,
+		CreatedAt:  at,
+	}
+	require.NoError(t, db.Create(entry).Error)
+}
+
+func TestPermissionDenials_FrequentDenials_ReturnsGroupsAboveThreshold(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+
+	rina := testutil.CreateTestUser(t, db, func(u *models.User) { u.Name = "Rina" })
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		createPermissionDenial(t, db, rina.ID, "/api/v1/users/{id}", "Settings:Users:delete", day.Add(time.Duration(i)*time.Minute))
+	}
+	// Below the threshold
+	createPermissionDenial(t, db, rina.ID, "/api/v1/roles", "Settings:Roles & Permissions:create", day)
+	// Outside the range
+	createPermissionDenial(t, db, rina.ID, "/api/v1/users/{id}", "Settings:Users:delete", time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC))
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/permission-denials?from=2026-03-01&to=2026-03-07&minDenials=2", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	denials := data["denials"].([]interface{})
+	require.Len(t, denials, 1)
+
+	first := denials[0].(map[string]interface{})
+	assert.Equal(t, "Rina", first["userName"])
+	assert.Equal(t, "/api/v1/users/{id}", first["route"])
+	assert.Equal(t, "Settings:Users:delete", first["permission"])
+	assert.Equal(t, float64(3), first["denials"])
+}
+
+func TestPermissionDenials_InvalidMinDenials_Returns400(t *testing.T) {
+	router, db := setupReportTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/permission-denials?minDenials=abc", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
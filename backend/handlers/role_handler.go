@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/pointofsale/backend/middleware"
 	"github.com/pointofsale/backend/services"
 	"github.com/pointofsale/backend/utils"
 )
@@ -117,6 +118,7 @@ func (h *RoleHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
 		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
 		return
 	}
+	input.ActorID = middleware.GetUserID(r.Context())
 
 	// Call service
 	role, serviceErr := h.roleService.UpdateRole(uint(id), input)
@@ -139,7 +141,8 @@ func (h *RoleHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
 	utils.Success(w, http.StatusOK, "Role updated successfully", role)
 }
 
-// DeleteRole deletes a role by ID
+// DeleteRole deletes a role by ID. With ?reassignTo={roleId}, users holding
+// the role are moved to that role instead of blocking the delete.
 func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
 	// Parse ID from URL
 	idStr := chi.URLParam(r, "id")
@@ -149,8 +152,18 @@ func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Call service
-	serviceErr := h.roleService.DeleteRole(uint(id))
+	// Users holding the role are moved to ?reassignTo= when given
+	var serviceErr *services.ServiceError
+	if reassignStr := r.URL.Query().Get("reassignTo"); reassignStr != "" {
+		fallbackID, err := strconv.ParseUint(reassignStr, 10, 32)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid 'reassignTo' role ID", "VALIDATION_ERROR")
+			return
+		}
+		serviceErr = h.roleService.DeleteRoleReassigning(uint(id), uint(fallbackID))
+	} else {
+		serviceErr = h.roleService.DeleteRole(uint(id))
+	}
 	if serviceErr != nil {
 		status := http.StatusInternalServerError
 		switch serviceErr.Err {
@@ -158,6 +171,10 @@ func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
 			status = http.StatusNotFound
 		case services.ErrForbidden:
 			status = http.StatusForbidden
+		case services.ErrConflict:
+			status = http.StatusConflict
+		case services.ErrValidation:
+			status = http.StatusBadRequest
 		}
 		utils.Error(w, status, serviceErr.Message, serviceErr.Code)
 		return
@@ -165,3 +182,40 @@ func (h *RoleHandler) DeleteRole(w http.ResponseWriter, r *http.Request) {
 
 	utils.Success(w, http.StatusOK, "Role deleted successfully", nil)
 }
+
+// CloneRole creates a new role with the same permissions as an existing one
+func (h *RoleHandler) CloneRole(w http.ResponseWriter, r *http.Request) {
+	// Parse ID from URL
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid role ID", "VALIDATION_ERROR")
+		return
+	}
+
+	var input struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+
+	// Call service
+	role, serviceErr := h.roleService.CloneRole(uint(id), input.Name)
+	if serviceErr != nil {
+		status := http.StatusInternalServerError
+		switch serviceErr.Err {
+		case services.ErrValidation:
+			status = http.StatusBadRequest
+		case services.ErrNotFound:
+			status = http.StatusNotFound
+		case services.ErrConflict:
+			status = http.StatusConflict
+		}
+		utils.Error(w, status, serviceErr.Message, serviceErr.Code)
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, "Role cloned successfully", role)
+}
@@ -26,7 +26,9 @@ func setupRoleTestRouter(t *testing.T) (chi.Router, *gorm.DB) {
 
 	// Initialize layers
 	roleRepo := repositories.NewRoleRepository(db)
-	roleService := services.NewRoleService(roleRepo)
+	roleService := services.NewRoleService(roleRepo, services.RoleConfig{
+		Audit: repositories.NewAuditLogRepository(db),
+	})
 	roleHandler := NewRoleHandler(roleService)
 
 	// Setup router
@@ -37,6 +39,7 @@ func setupRoleTestRouter(t *testing.T) (chi.Router, *gorm.DB) {
 		r.Post("/", roleHandler.CreateRole)
 		r.Put("/{id}", roleHandler.UpdateRole)
 		r.Delete("/{id}", roleHandler.DeleteRole)
+		r.Post("/{id}/clone", roleHandler.CloneRole)
 	})
 
 	return r, db
@@ -365,6 +368,32 @@ func TestUpdateRole_ValidBody_Returns200(t *testing.T) {
 	assert.Equal(t, "Updated description", data["description"])
 }
 
+// TestUpdateRole_ValidBody_RecordsAuditBeforeAndAfter verifies the change is audited
+func TestUpdateRole_ValidBody_RecordsAuditBeforeAndAfter(t *testing.T) {
+	router, db := setupRoleTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	role := testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = "OldName"
+	})
+
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/roles/%d", role.ID), strings.NewReader(`{"name": "NewName"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var entry models.AuditLog
+	require.NoError(t, db.Where("entity = ? AND entity_id = ?", "role", fmt.Sprint(role.ID)).First(&entry).Error)
+	assert.Equal(t, models.AuditEventRoleUpdated, entry.Event)
+
+	var before, after map[string]interface{}
+	require.NoError(t, json.Unmarshal(entry.Before, &before))
+	require.NoError(t, json.Unmarshal(entry.After, &after))
+	assert.Equal(t, "OldName", before["name"])
+	assert.Equal(t, "NewName", after["name"])
+}
+
 // TestUpdateRole_SystemRole_Returns403 verifies system role protection
 func TestUpdateRole_SystemRole_Returns403(t *testing.T) {
 	router, db := setupRoleTestRouter(t)
@@ -469,3 +498,146 @@ func TestDeleteRole_NotFound_Returns404(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, rr.Code)
 }
+
+// TestCloneRole_SystemRole_Returns201NonSystemCopy verifies cloning copies permissions but not the system flag
+func TestCloneRole_SystemRole_Returns201NonSystemCopy(t *testing.T) {
+	router, db := setupRoleTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	source := testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = "Super Admin"
+		r.IsSystem = true
+	})
+	perm := testutil.CreateTestPermission(t, db)
+	require.NoError(t, db.Create(&models.RolePermission{RoleID: source.ID, PermissionID: perm.ID, Actions: []string{"read", "update"}}).Error)
+
+	body := `{"name": "Deputy Admin"}`
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/roles/%d/clone", source.ID), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.Equal(t, "Deputy Admin", data["name"])
+	assert.Equal(t, false, data["isSystem"])
+
+	var cloned []models.RolePermission
+	require.NoError(t, db.Where("role_id = ?", uint(data["id"].(float64))).Find(&cloned).Error)
+	require.Len(t, cloned, 1)
+	assert.Equal(t, perm.ID, cloned[0].PermissionID)
+	assert.Equal(t, []string{"read", "update"}, []string(cloned[0].Actions))
+}
+
+// TestCloneRole_DuplicateName_Returns409 verifies the new name must be unique
+func TestCloneRole_DuplicateName_Returns409(t *testing.T) {
+	router, db := setupRoleTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	source := testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = "Cashier"
+	})
+	testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = "Warehouse"
+	})
+
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/roles/%d/clone", source.ID), strings.NewReader(`{"name": "Warehouse"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+// TestCloneRole_SourceNotFound_Returns404 verifies 404 for non-existent source role
+func TestCloneRole_SourceNotFound_Returns404(t *testing.T) {
+	router, db := setupRoleTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	req := httptest.NewRequest("POST", "/api/v1/roles/99999/clone", strings.NewReader(`{"name": "Copy"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+// TestDeleteRole_AssignedToUsers_Returns409 verifies roles in use cannot be deleted
+func TestDeleteRole_AssignedToUsers_Returns409(t *testing.T) {
+	router, db := setupRoleTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	role := testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = "InUse"
+	})
+	user := testutil.CreateTestUser(t, db)
+	db.Exec("INSERT INTO user_roles (user_id, role_id) VALUES (?, ?)", user.ID, role.ID)
+
+	req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/roles/%d", role.ID), nil)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(rr.Body.Bytes(), &response)
+
+	assert.Equal(t, "Cannot delete role assigned to 1 user(s)", response["error"])
+
+	var count int64
+	db.Model(&models.Role{}).Where("id = ?", role.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestDeleteRole_ReassignTo_MovesUsersAndDeletes verifies users are moved to the fallback role
+func TestDeleteRole_ReassignTo_MovesUsersAndDeletes(t *testing.T) {
+	router, db := setupRoleTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	role := testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = "Retiring"
+	})
+	fallback := testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = "Fallback"
+	})
+	user := testutil.CreateTestUser(t, db)
+	db.Exec("INSERT INTO user_roles (user_id, role_id) VALUES (?, ?)", user.ID, role.ID)
+
+	req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/roles/%d?reassignTo=%d", role.ID, fallback.ID), nil)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var count int64
+	db.Model(&models.Role{}).Where("id = ?", role.ID).Count(&count)
+	assert.Equal(t, int64(0), count)
+
+	db.Table("user_roles").Where("user_id = ? AND role_id = ?", user.ID, fallback.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+// TestDeleteRole_ReassignToMissingRole_Returns400 verifies the fallback role must exist
+func TestDeleteRole_ReassignToMissingRole_Returns400(t *testing.T) {
+	router, db := setupRoleTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	role := testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = "Retiring"
+	})
+
+	req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/roles/%d?reassignTo=99999", role.ID), nil)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var count int64
+	db.Model(&models.Role{}).Where("id = ?", role.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
@@ -2,10 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/pointofsale/backend/middleware"
 	"github.com/pointofsale/backend/repositories"
 	"github.com/pointofsale/backend/services"
 	"github.com/pointofsale/backend/utils"
@@ -50,6 +53,24 @@ func (h *SalesHandler) ProductSearch(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// PaymentMethods handles GET /api/v1/sales/payment-methods
+func (h *SalesHandler) PaymentMethods(w http.ResponseWriter, r *http.Request) {
+	utils.JSON(w, http.StatusOK, map[string]interface{}{
+		"data": h.salesService.PaymentMethods(),
+	})
+}
+
+// GetNextTransactionNumber handles GET /api/v1/sales/next-number
+func (h *SalesHandler) GetNextTransactionNumber(w http.ResponseWriter, r *http.Request) {
+	next, err := h.salesService.NextTransactionNumber()
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, "Failed to generate transaction number", "INTERNAL_ERROR")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", next)
+}
+
 // Checkout handles POST /api/v1/sales/checkout
 func (h *SalesHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 	var input services.CheckoutInput
@@ -58,6 +79,9 @@ func (h *SalesHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	input.CashierID = middleware.GetUserID(r.Context())
+	input.CashierIsSuperAdmin = middleware.GetIsSuperAdmin(r.Context())
+
 	result, err := h.salesService.Checkout(input)
 	if err != nil {
 		status := http.StatusInternalServerError
@@ -67,8 +91,16 @@ func (h *SalesHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 		if serviceErr, ok := err.(*services.ServiceError); ok {
 			message = serviceErr.Message
 			code = serviceErr.Code
-			if serviceErr.Err == services.ErrValidation {
+			switch serviceErr.Err {
+			case services.ErrValidation:
 				status = http.StatusBadRequest
+			case services.ErrForbidden:
+				status = http.StatusForbidden
+			case services.ErrConflict:
+				status = http.StatusConflict
+			case services.ErrBusy:
+				status = http.StatusServiceUnavailable
+				w.Header().Set("Retry-After", "1")
 			}
 		}
 		utils.Error(w, status, message, code)
@@ -79,6 +111,16 @@ func (h *SalesHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 }
 
 // ListTransactions handles GET /api/v1/sales/transactions
+//
+// Filters, all optional and combined with AND:
+//   - dateFrom, dateTo: transaction date range, YYYY-MM-DD, inclusive
+//   - paymentMethod: one or more of cash, card, qris, comma-separated or repeated
+//   - cashierId: the cashier who processed the sale
+//   - customer: part of the customer name, case-insensitive
+//   - minTotal, maxTotal: grand total range, inclusive
+//   - search: part of the transaction number
+//
+// meta.grandTotal is the sum of grand totals over every matching transaction.
 func (h *SalesHandler) ListTransactions(w http.ResponseWriter, r *http.Request) {
 	paginationParams, err := utils.ParsePaginationParams(r, salesSortFields)
 	if err != nil {
@@ -94,24 +136,87 @@ func (h *SalesHandler) ListTransactions(w http.ResponseWriter, r *http.Request)
 		SortDir:  paginationParams.SortDir,
 	}
 
-	dateFrom := r.URL.Query().Get("dateFrom")
-	dateTo := r.URL.Query().Get("dateTo")
-	paymentMethod := r.URL.Query().Get("paymentMethod")
+	filter, err := parseSalesListFilter(r)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
 
-	transactions, total, err := h.salesService.ListTransactions(params, dateFrom, dateTo, paymentMethod)
+	transactions, totals, err := h.salesService.ListTransactions(params, filter)
 	if err != nil {
+		if serviceErr, ok := err.(*services.ServiceError); ok && serviceErr.Err == services.ErrValidation {
+			utils.Error(w, http.StatusBadRequest, serviceErr.Message, serviceErr.Code)
+			return
+		}
 		utils.Error(w, http.StatusInternalServerError, "Failed to fetch transactions", "INTERNAL_ERROR")
 		return
 	}
 
-	meta := utils.CalculatePaginationMeta(params.Page, params.PageSize, int(total))
-
-	utils.JSON(w, http.StatusOK, utils.PaginatedResponse{
-		Data: transactions,
-		Meta: meta,
+	utils.JSON(w, http.StatusOK, map[string]interface{}{
+		"data": transactions,
+		"meta": transactionListMeta{
+			PaginationMeta: utils.CalculatePaginationMeta(params.Page, params.PageSize, int(totals.Count)),
+			GrandTotal:     totals.GrandTotal,
+		},
 	})
 }
 
+// transactionListMeta is the pagination meta of a transaction list plus the
+// grand total of all matching transactions.
+type transactionListMeta struct {
+	utils.PaginationMeta
+	GrandTotal float64 `json:"grandTotal"`
+}
+
+// parseSalesListFilter reads the transaction list filters from the query string.
+func parseSalesListFilter(r *http.Request) (repositories.SalesListFilter, error) {
+	query := r.URL.Query()
+	filter := repositories.SalesListFilter{
+		DateFrom: query.Get("dateFrom"),
+		DateTo:   query.Get("dateTo"),
+		Customer: strings.TrimSpace(query.Get("customer")),
+	}
+
+	for _, value := range query["paymentMethod"] {
+		for _, method := range strings.Split(value, ",") {
+			if method = strings.TrimSpace(method); method != "" {
+				filter.PaymentMethods = append(filter.PaymentMethods, method)
+			}
+		}
+	}
+
+	if cashierStr := query.Get("cashierId"); cashierStr != "" {
+		cashierID, err := strconv.ParseUint(cashierStr, 10, 32)
+		if err != nil {
+			return filter, fmt.Errorf("invalid 'cashierId'")
+		}
+		id := uint(cashierID)
+		filter.CashierID = &id
+	}
+
+	var err error
+	if filter.MinTotal, err = parseOptionalFloat(query.Get("minTotal"), "minTotal"); err != nil {
+		return filter, err
+	}
+	if filter.MaxTotal, err = parseOptionalFloat(query.Get("maxTotal"), "maxTotal"); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// parseOptionalFloat parses a query value, returning nil when it is empty.
+func parseOptionalFloat(value, name string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s'", name)
+	}
+	return &parsed, nil
+}
+
 // GetTransaction handles GET /api/v1/sales/transactions/:id
 func (h *SalesHandler) GetTransaction(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -140,3 +245,157 @@ func (h *SalesHandler) GetTransaction(w http.ResponseWriter, r *http.Request) {
 
 	utils.Success(w, http.StatusOK, "", tx)
 }
+
+// CreateReturn handles POST /api/v1/sales/transactions/:id/returns
+func (h *SalesHandler) CreateReturn(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid transaction ID", "VALIDATION_ERROR")
+		return
+	}
+
+	var input services.CreateReturnInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+	input.CreatedBy = middleware.GetUserID(r.Context())
+
+	result, err := h.salesService.CreateReturn(uint(id), input)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to process return"
+		code := "INTERNAL_ERROR"
+
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			switch serviceErr.Err {
+			case services.ErrValidation:
+				status = http.StatusBadRequest
+			case services.ErrNotFound:
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, "Return recorded successfully", result)
+}
+
+// ParkCart handles POST /api/v1/sales/parked
+func (h *SalesHandler) ParkCart(w http.ResponseWriter, r *http.Request) {
+	var input services.ParkCartInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+
+	input.CashierID = middleware.GetUserID(r.Context())
+
+	cart, err := h.salesService.ParkCart(input)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to park cart"
+		code := "INTERNAL_ERROR"
+
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			if serviceErr.Err == services.ErrValidation {
+				status = http.StatusBadRequest
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, "Cart parked successfully", cart)
+}
+
+// ListParkedCarts handles GET /api/v1/sales/parked
+func (h *SalesHandler) ListParkedCarts(w http.ResponseWriter, r *http.Request) {
+	paginationParams, err := utils.ParsePaginationParams(r, nil)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	params := repositories.PaginationParams{
+		Page:     paginationParams.Page,
+		PageSize: paginationParams.PageSize,
+		Search:   paginationParams.Search,
+	}
+
+	carts, total, err := h.salesService.ListParkedCarts(params)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, "Failed to fetch parked carts", "INTERNAL_ERROR")
+		return
+	}
+
+	meta := utils.CalculatePaginationMeta(params.Page, params.PageSize, int(total))
+
+	utils.JSON(w, http.StatusOK, utils.PaginatedResponse{
+		Data: carts,
+		Meta: meta,
+	})
+}
+
+// ResumeParkedCart handles GET /api/v1/sales/parked/:id
+func (h *SalesHandler) ResumeParkedCart(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid parked cart ID", "VALIDATION_ERROR")
+		return
+	}
+
+	cart, err := h.salesService.ResumeParkedCart(uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to fetch parked cart"
+		code := "INTERNAL_ERROR"
+
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			if serviceErr.Err == services.ErrNotFound {
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", cart)
+}
+
+// DeleteParkedCart handles DELETE /api/v1/sales/parked/:id
+func (h *SalesHandler) DeleteParkedCart(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid parked cart ID", "VALIDATION_ERROR")
+		return
+	}
+
+	if err := h.salesService.DeleteParkedCart(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to delete parked cart"
+		code := "INTERNAL_ERROR"
+
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			if serviceErr.Err == services.ErrNotFound {
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Parked cart deleted successfully", nil)
+}
@@ -53,9 +53,16 @@ func setupSalesTestRouter(t *testing.T) (chi.Router, *gorm.DB, *redis.Client, *c
 	r.Route("/api/v1/sales", func(r chi.Router) {
 		r.Use(authMiddleware.Authenticate)
 		r.With(permMiddleware.RequirePermission("Transaction", "Sale", "read")).Get("/products/search", salesHandler.ProductSearch)
+		r.With(permMiddleware.RequirePermission("Transaction", "Sale", "read")).Get("/payment-methods", salesHandler.PaymentMethods)
+		r.With(permMiddleware.RequirePermission("Transaction", "Sale", "create")).Get("/next-number", salesHandler.GetNextTransactionNumber)
 		r.With(permMiddleware.RequirePermission("Transaction", "Sale", "create")).Post("/checkout", salesHandler.Checkout)
 		r.With(permMiddleware.RequirePermission("Transaction", "Sale", "read")).Get("/transactions", salesHandler.ListTransactions)
 		r.With(permMiddleware.RequirePermission("Transaction", "Sale", "read")).Get("/transactions/{id}", salesHandler.GetTransaction)
+		r.With(permMiddleware.RequirePermission("Transaction", "Sale", "create")).Post("/transactions/{id}/returns", salesHandler.CreateReturn)
+		r.With(permMiddleware.RequirePermission("Transaction", "Sale", "create")).Post("/parked", salesHandler.ParkCart)
+		r.With(permMiddleware.RequirePermission("Transaction", "Sale", "read")).Get("/parked", salesHandler.ListParkedCarts)
+		r.With(permMiddleware.RequirePermission("Transaction", "Sale", "read")).Get("/parked/{id}", salesHandler.ResumeParkedCart)
+		r.With(permMiddleware.RequirePermission("Transaction", "Sale", "create")).Delete("/parked/{id}", salesHandler.DeleteParkedCart)
 	})
 
 	return r, db, rdb, cfg
@@ -311,6 +318,67 @@ func TestCheckout_InsufficientStock_Returns400(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
 
+func TestCheckout_NegativeQuantity_Returns400WithField(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+
+	body := fmt.Sprintf(`{
+		"paymentMethod": "cash",
+		"items": [
+			{"productId": %d, "variantId": "%s", "unitId": %d, "quantity": 1},
+			{"productId": %d, "variantId": "%s", "unitId": %d, "quantity": -2}
+		]
+	}`, product.ID, variant.ID, unit.ID, product.ID, variant.ID, unit.ID)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/sales/checkout", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "items[1].quantity must be greater than zero")
+
+	var unchanged models.ProductVariant
+	require.NoError(t, db.First(&unchanged, "id = ?", variant.ID).Error)
+	assert.Equal(t, variant.CurrentStock, unchanged.CurrentStock)
+}
+
+func TestCheckout_SeveralInvalidReferences_Returns400ListingAll(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	other := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+
+	body := fmt.Sprintf(`{
+		"paymentMethod": "cash",
+		"items": [
+			{"productId": %d, "variantId": "%s", "unitId": %d, "quantity": 1},
+			{"productId": 999999, "variantId": "%s", "unitId": %d, "quantity": 1}
+		]
+	}`, product.ID, variant.ID, other.Units[0].ID, variant.ID, product.Units[0].ID)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/sales/checkout", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, fmt.Sprintf("items[0]: unit %d does not belong to %s", other.Units[0].ID, product.Name))
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "items[1]: product 999999 not found")
+
+	var unchanged models.ProductVariant
+	require.NoError(t, db.First(&unchanged, "id = ?", variant.ID).Error)
+	assert.Equal(t, variant.CurrentStock, unchanged.CurrentStock)
+}
+
 func TestCheckout_NoAuth_Returns401(t *testing.T) {
 	router, db, _, _ := setupSalesTestRouter(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -399,6 +467,45 @@ func TestCheckout_VerifyStockMovementCreated(t *testing.T) {
 	assert.Equal(t, "sales", movements[0].MovementType)
 }
 
+func TestGetNextTransactionNumber_StableUntilCheckout(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+
+	peek := func() string {
+		req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/sales/next-number", nil, token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+		number, _ := data["number"].(string)
+		return number
+	}
+
+	next := peek()
+	require.NotEmpty(t, next)
+	assert.Equal(t, next, peek())
+
+	body := fmt.Sprintf(`{
+		"paymentMethod": "cash",
+		"items": [
+			{"productId": %d, "variantId": "%s", "unitId": %d, "quantity": 1}
+		]
+	}`, product.ID, variant.ID, unit.ID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/sales/checkout", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.Equal(t, next, data["transactionNumber"])
+	assert.NotEqual(t, next, peek())
+}
+
 func TestListTransactions_Returns200WithPagination(t *testing.T) {
 	router, db, _, _ := setupSalesTestRouter(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -510,6 +617,73 @@ func TestListTransactions_FilterByPaymentMethod_Works(t *testing.T) {
 	}
 }
 
+func TestListTransactions_CombinedFilters_ReturnsGrandTotal(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+
+	checkouts := []struct {
+		method   string
+		customer string
+	}{
+		{"cash", "Budi Santoso"},
+		{"card", "Budi Hartono"},
+		{"qris", "Siti"},
+	}
+	for _, c := range checkouts {
+		body := fmt.Sprintf(`{
+			"paymentMethod": "%s",
+			"customerName": "%s",
+			"items": [
+				{"productId": %d, "variantId": "%s", "unitId": %d, "quantity": 1}
+			]
+		}`, c.method, c.customer, product.ID, variant.ID, unit.ID)
+		checkReq := testutil.AuthenticatedRequest(t, "POST", "/api/v1/sales/checkout", strings.NewReader(body), token)
+		checkRR := httptest.NewRecorder()
+		router.ServeHTTP(checkRR, checkReq)
+		require.Equal(t, http.StatusCreated, checkRR.Code)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	url := fmt.Sprintf("/api/v1/sales/transactions?dateFrom=%s&dateTo=%s&paymentMethod=cash,card&customer=budi&cashierId=%d&minTotal=1", today, today, user.ID)
+	req := testutil.AuthenticatedRequest(t, "GET", url, nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	data := response["data"].([]interface{})
+	assert.Len(t, data, 2)
+	meta := response["meta"].(map[string]interface{})
+	assert.Equal(t, float64(2), meta["totalItems"])
+	var expected float64
+	for _, item := range data {
+		expected += item.(map[string]interface{})["grandTotal"].(float64)
+	}
+	assert.Equal(t, expected, meta["grandTotal"])
+}
+
+func TestListTransactions_InvalidPaymentMethod_Returns400(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/sales/transactions?paymentMethod=cash,cheque", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "Invalid payment method: cheque")
+}
+
 func TestGetTransaction_ReturnsReceiptData(t *testing.T) {
 	router, db, _, _ := setupSalesTestRouter(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -545,3 +719,247 @@ func TestGetTransaction_ReturnsReceiptData(t *testing.T) {
 	assert.NotNil(t, data["items"])
 	assert.Equal(t, "card", data["paymentMethod"])
 }
+
+func TestCreateReturn_PartialReturn_Returns201AndRestocks(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+
+	body := fmt.Sprintf(`{
+		"paymentMethod": "cash",
+		"items": [
+			{"productId": %d, "variantId": "%s", "unitId": %d, "quantity": 4}
+		]
+	}`, product.ID, variant.ID, unit.ID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/sales/checkout", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	sale := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	saleID := uint(sale["id"].(float64))
+	itemID := uint(sale["items"].([]interface{})[0].(map[string]interface{})["id"].(float64))
+
+	body = fmt.Sprintf(`{"refundMethod": "cash", "items": [{"transactionItemId": %d, "quantity": 1}]}`, itemID)
+	req = testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/sales/transactions/%d/returns", saleID), strings.NewReader(body), token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.NotEmpty(t, data["returnNumber"])
+	assert.Equal(t, float64(saleID), data["transactionId"])
+
+	var updatedVariant models.ProductVariant
+	require.NoError(t, db.First(&updatedVariant, "id = ?", variant.ID).Error)
+	assert.Equal(t, variant.CurrentStock-3, updatedVariant.CurrentStock)
+
+	var movement models.StockMovement
+	require.NoError(t, db.Where("movement_type = ? AND reference_id = ?", "sales_return", saleID).First(&movement).Error)
+	require.NotNil(t, movement.CreatedBy)
+	assert.Equal(t, user.ID, *movement.CreatedBy)
+
+	// Returning more than the remaining 3 is rejected
+	body = fmt.Sprintf(`{"refundMethod": "cash", "items": [{"transactionItemId": %d, "quantity": 4}]}`, itemID)
+	req = testutil.AuthenticatedRequest(t, "POST", fmt.Sprintf("/api/v1/sales/transactions/%d/returns", saleID), strings.NewReader(body), token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "RETURN_EXCEEDS_SOLD")
+}
+
+func TestCreateReturn_NegativeQuantity_Returns400WithField(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	body := `{"refundMethod": "cash", "items": [{"transactionItemId": 1, "quantity": -1}]}`
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/sales/transactions/1/returns", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "items[0].quantity must be greater than zero")
+}
+
+func TestCreateReturn_UnknownTransaction_Returns404(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	body := `{"refundMethod": "cash", "items": [{"transactionItemId": 1, "quantity": 1}]}`
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/sales/transactions/999999/returns", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestPaymentMethods_Returns200(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/sales/payment-methods", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	methods := response["data"].([]interface{})
+	require.Len(t, methods, 3)
+	assert.Equal(t, "cash", methods[0].(map[string]interface{})["method"])
+}
+
+func TestParkCart_ValidBody_Returns201(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	body := fmt.Sprintf(`{
+		"label": "Table 4",
+		"customerName": "Budi",
+		"items": [
+			{"productId": %d, "variantId": "%s", "unitId": %d, "quantity": 2}
+		]
+	}`, product.ID, product.Variants[0].ID, product.Units[0].ID)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/sales/parked", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.Equal(t, "Table 4", data["label"])
+	assert.Equal(t, float64(user.ID), data["cashierId"])
+	items, ok := data["items"].([]interface{})
+	require.True(t, ok)
+	assert.Len(t, items, 1)
+}
+
+func TestParkCart_NegativeQuantity_Returns400WithField(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	body := fmt.Sprintf(`{
+		"label": "Table 4",
+		"items": [
+			{"productId": %d, "variantId": "%s", "unitId": %d, "quantity": -1}
+		]
+	}`, product.ID, product.Variants[0].ID, product.Units[0].ID)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/sales/parked", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "items[0].quantity must be greater than zero")
+}
+
+func TestListParkedCarts_Returns200WithPagination(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	body := fmt.Sprintf(`{"label": "Queue", "items": [{"productId": %d, "variantId": "%s", "unitId": %d, "quantity": 1}]}`,
+		product.ID, product.Variants[0].ID, product.Units[0].ID)
+	parkReq := testutil.AuthenticatedRequest(t, "POST", "/api/v1/sales/parked", strings.NewReader(body), token)
+	parkRR := httptest.NewRecorder()
+	router.ServeHTTP(parkRR, parkReq)
+	require.Equal(t, http.StatusCreated, parkRR.Code)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/sales/parked?page=1&pageSize=10", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+	data, ok := resp["data"].([]interface{})
+	require.True(t, ok)
+	assert.Len(t, data, 1)
+	assert.NotNil(t, resp["meta"])
+}
+
+func TestResumeParkedCart_ItemOutOfStock_Returns200WithIssue(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read", "create"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	product := testutil.CreateTestProduct(t, db)
+	cart := &models.ParkedCart{
+		TotalItems: 1,
+		Items: []models.ParkedCartItem{{
+			ProductID:    product.ID,
+			VariantID:    product.Variants[0].ID,
+			UnitID:       product.Units[0].ID,
+			Quantity:     2,
+			ProductName:  product.Name,
+			VariantLabel: "Default",
+			UnitName:     product.Units[0].Name,
+		}},
+	}
+	require.NoError(t, db.Create(cart).Error)
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", product.Variants[0].ID).Update("current_stock", 0).Error)
+
+	req := testutil.AuthenticatedRequest(t, "GET", fmt.Sprintf("/api/v1/sales/parked/%d", cart.ID), nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, false, data["allAvailable"])
+	items, ok := data["items"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, items, 1)
+	assert.Equal(t, "OUT_OF_STOCK", items[0].(map[string]interface{})["issue"])
+	assert.Empty(t, data["checkoutItems"])
+}
+
+func TestResumeParkedCart_NotFound_Returns404(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/sales/parked/999999", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestDeleteParkedCart_ReadOnlyUser_Returns403(t *testing.T) {
+	router, db, _, _ := setupSalesTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := setupSalesTestUserWithPermission(t, db, []string{"read"})
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "DELETE", "/api/v1/sales/parked/1", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/pointofsale/backend/middleware"
+	"github.com/pointofsale/backend/services"
+	"github.com/pointofsale/backend/utils"
+)
+
+// StockAdjustmentHandler handles stock adjustment HTTP requests.
+type StockAdjustmentHandler struct {
+	adjustmentService *services.StockAdjustmentService
+}
+
+// NewStockAdjustmentHandler creates a new stock adjustment handler instance.
+func NewStockAdjustmentHandler(adjustmentService *services.StockAdjustmentService) *StockAdjustmentHandler {
+	return &StockAdjustmentHandler{adjustmentService: adjustmentService}
+}
+
+// CreateAdjustment handles POST /api/v1/stock-adjustments
+func (h *StockAdjustmentHandler) CreateAdjustment(w http.ResponseWriter, r *http.Request) {
+	var input services.StockAdjustmentInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+	input.CreatedBy = middleware.GetUserID(r.Context())
+
+	adjustment, err := h.adjustmentService.Create(input)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to adjust stock"
+		code := "INTERNAL_ERROR"
+
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			switch serviceErr.Err {
+			case services.ErrValidation:
+				status = http.StatusBadRequest
+			case services.ErrNotFound:
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, "Stock adjusted successfully", adjustment)
+}
+
+// ListAdjustments handles GET /api/v1/stock-adjustments?page=&pageSize=&variantId=
+func (h *StockAdjustmentHandler) ListAdjustments(w http.ResponseWriter, r *http.Request) {
+	params, err := utils.ParsePaginationParams(r, nil)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+
+	variantID := r.URL.Query().Get("variantId")
+	if variantID != "" {
+		if _, err := uuid.Parse(variantID); err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid variant ID", "VALIDATION_ERROR")
+			return
+		}
+	}
+
+	items, total, err := h.adjustmentService.List(params.Page, params.PageSize, variantID)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, "Failed to list stock adjustments", "INTERNAL_ERROR")
+		return
+	}
+
+	meta := utils.CalculatePaginationMeta(params.Page, params.PageSize, int(total))
+	utils.JSON(w, http.StatusOK, utils.PaginatedResponse{
+		Data: items,
+		Meta: meta,
+	})
+}
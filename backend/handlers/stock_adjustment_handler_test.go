@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/pointofsale/backend/config"
+	"github.com/pointofsale/backend/middleware"
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/services"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupStockAdjustmentTestRouter(t *testing.T) (chi.Router, *gorm.DB) {
+	t.Helper()
+
+	db := testutil.SetupTestDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	cfg := &config.Config{
+		JWTAccessSecret: testutil.TestJWTAccessSecret,
+	}
+
+	userRepo := repositories.NewUserRepository(db)
+	adjustmentService := services.NewStockAdjustmentService(db, repositories.NewStockAdjustmentRepository(db))
+	adjustmentHandler := NewStockAdjustmentHandler(adjustmentService)
+
+	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTAccessSecret, rdb, userRepo)
+	permMiddleware := middleware.NewPermissionMiddleware(db, rdb)
+
+	r := chi.NewRouter()
+	r.Route("/api/v1/stock-adjustments", func(r chi.Router) {
+		r.Use(authMiddleware.Authenticate)
+		r.With(permMiddleware.RequirePermission("Transaction", "Stock Adjustment", "read")).Get("/", adjustmentHandler.ListAdjustments)
+		r.With(permMiddleware.RequirePermission("Transaction", "Stock Adjustment", "create")).Post("/", adjustmentHandler.CreateAdjustment)
+	})
+
+	return r, db
+}
+
+func TestCreateStockAdjustment_Delta_UpdatesStockAndRecordsMovement(t *testing.T) {
+	router, db := setupStockAdjustmentTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+	variantID := testutil.CreateTestProduct(t, db).Variants[0].ID
+
+	body := fmt.Sprintf(`{"variantId": %q, "delta": -3, "reason": "Damaged in storage"}`, variantID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/stock-adjustments", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.Equal(t, float64(100), data["previousStock"])
+	assert.Equal(t, float64(97), data["newStock"])
+	assert.Equal(t, float64(-3), data["quantity"])
+
+	var variant models.ProductVariant
+	require.NoError(t, db.First(&variant, "id = ?", variantID).Error)
+	assert.Equal(t, 97, variant.CurrentStock)
+
+	var movement models.StockMovement
+	require.NoError(t, db.Where("variant_id = ?", variantID).First(&movement).Error)
+	assert.Equal(t, "adjustment", movement.MovementType)
+	assert.Equal(t, "stock_adjustment", movement.ReferenceType)
+	assert.Equal(t, -3, movement.Quantity)
+	require.NotNil(t, movement.ReferenceID)
+	assert.Equal(t, uint(data["id"].(float64)), *movement.ReferenceID)
+	require.NotNil(t, movement.CreatedBy)
+	assert.Equal(t, admin.ID, *movement.CreatedBy)
+}
+
+func TestCreateStockAdjustment_Count_SetsStock(t *testing.T) {
+	router, db := setupStockAdjustmentTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+	variantID := testutil.CreateTestProduct(t, db).Variants[0].ID
+
+	body := fmt.Sprintf(`{"variantId": %q, "count": 112, "reason": "Stock count"}`, variantID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/stock-adjustments", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	assert.Equal(t, float64(12), data["quantity"])
+
+	var variant models.ProductVariant
+	require.NoError(t, db.First(&variant, "id = ?", variantID).Error)
+	assert.Equal(t, 112, variant.CurrentStock)
+}
+
+func TestCreateStockAdjustment_NegativeResult_Returns400(t *testing.T) {
+	router, db := setupStockAdjustmentTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+	variantID := testutil.CreateTestProduct(t, db).Variants[0].ID
+
+	body := fmt.Sprintf(`{"variantId": %q, "delta": -101, "reason": "Shrinkage"}`, variantID)
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/stock-adjustments", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusBadRequest, "Adjustment would leave stock at -1")
+
+	var variant models.ProductVariant
+	require.NoError(t, db.First(&variant, "id = ?", variantID).Error)
+	assert.Equal(t, 100, variant.CurrentStock)
+
+	var count int64
+	require.NoError(t, db.Model(&models.StockMovement{}).Where("variant_id = ?", variantID).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestCreateStockAdjustment_UnknownVariant_Returns404(t *testing.T) {
+	router, db := setupStockAdjustmentTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+
+	body := `{"variantId": "6f1c2b8e-4a7d-4c1e-9b3a-2d5e8f0a1b2c", "delta": 5, "reason": "Found"}`
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/stock-adjustments", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	testutil.AssertErrorResponse(t, rr, http.StatusNotFound, "Variant not found")
+}
+
+func TestListStockAdjustments_FilterByVariant_ReturnsNewestFirst(t *testing.T) {
+	router, db := setupStockAdjustmentTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+	product := testutil.CreateTestProduct(t, db)
+	variantID := product.Variants[0].ID
+	otherID := testutil.CreateTestProduct(t, db).Variants[0].ID
+
+	for _, body := range []string{
+		fmt.Sprintf(`{"variantId": %q, "delta": 5, "reason": "Found in back room"}`, variantID),
+		fmt.Sprintf(`{"variantId": %q, "delta": -2, "reason": "Damaged"}`, variantID),
+		fmt.Sprintf(`{"variantId": %q, "delta": 1, "reason": "Other"}`, otherID),
+	} {
+		req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/stock-adjustments", strings.NewReader(body), token)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	}
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/stock-adjustments?variantId="+variantID, nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	response := testutil.AssertJSONResponse(t, rr)
+	items := response["data"].([]interface{})
+	require.Len(t, items, 2)
+	first := items[0].(map[string]interface{})
+	assert.Equal(t, "Damaged", first["reason"])
+	assert.Equal(t, product.Name, first["productName"])
+	assert.Equal(t, "Default", first["variantLabel"])
+	assert.Equal(t, float64(105), first["previousStock"])
+	assert.Equal(t, float64(103), first["newStock"])
+}
+
+func TestCreateStockAdjustment_NoPermission_Returns403(t *testing.T) {
+	router, db := setupStockAdjustmentTestRouter(t)
+
+	user := testutil.CreateTestUser(t, db)
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/stock-adjustments", strings.NewReader(`{}`), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/services"
+	"github.com/pointofsale/backend/utils"
+)
+
+// stockLedgerSortFields lists the sort fields accepted by the variant ledger.
+var stockLedgerSortFields = []string{"created_at"}
+
+// StockHandler handles stock-related HTTP requests.
+type StockHandler struct {
+	stockService *services.StockMovementService
+}
+
+// NewStockHandler creates a new stock handler instance.
+func NewStockHandler(stockService *services.StockMovementService) *StockHandler {
+	return &StockHandler{stockService: stockService}
+}
+
+// GetFeed handles GET /api/v1/stock/feed?limit=
+func (h *StockHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed < 1 {
+			utils.Error(w, http.StatusBadRequest, "limit must be a positive integer", "VALIDATION_ERROR")
+			return
+		}
+		limit = parsed
+	}
+
+	items, err := h.stockService.RecentFeed(limit)
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, "Failed to load stock activity", "INTERNAL_ERROR")
+		return
+	}
+
+	utils.JSON(w, http.StatusOK, map[string]interface{}{
+		"data": items,
+	})
+}
+
+// GetVariantLedger handles GET /api/v1/products/{id}/variants/{variantId}/movements
+func (h *StockHandler) GetVariantLedger(w http.ResponseWriter, r *http.Request) {
+	productID, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 32)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid product ID", "VALIDATION_ERROR")
+		return
+	}
+	variantID := chi.URLParam(r, "variantId")
+	if _, err := uuid.Parse(variantID); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid variant ID", "VALIDATION_ERROR")
+		return
+	}
+
+	paginationParams, err := utils.ParsePaginationParams(r, stockLedgerSortFields)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, err.Error(), "VALIDATION_ERROR")
+		return
+	}
+	params := repositories.PaginationParams{
+		Page:     paginationParams.Page,
+		PageSize: paginationParams.PageSize,
+		SortBy:   paginationParams.SortBy,
+		SortDir:  paginationParams.SortDir,
+	}
+
+	entries, total, err := h.stockService.VariantLedger(uint(productID), variantID, params)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to load stock movements"
+		code := "INTERNAL_ERROR"
+
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			if serviceErr.Err == services.ErrNotFound {
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	meta := utils.CalculatePaginationMeta(params.Page, params.PageSize, int(total))
+	utils.JSON(w, http.StatusOK, utils.PaginatedResponse{
+		Data: entries,
+		Meta: meta,
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/pointofsale/backend/services"
+	"github.com/pointofsale/backend/utils"
+)
+
+// StockSnapshotHandler handles HTTP requests for stock snapshot endpoints.
+type StockSnapshotHandler struct {
+	snapshotService *services.StockSnapshotService
+}
+
+// NewStockSnapshotHandler creates a new stock snapshot handler instance.
+func NewStockSnapshotHandler(snapshotService *services.StockSnapshotService) *StockSnapshotHandler {
+	return &StockSnapshotHandler{snapshotService: snapshotService}
+}
+
+// GetSnapshot handles GET /api/v1/reports/stock-snapshot?date=
+// The date is YYYY-MM-DD and defaults to today in the store's timezone.
+func (h *StockSnapshotHandler) GetSnapshot(w http.ResponseWriter, r *http.Request) {
+	date := h.snapshotService.Today()
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		parsed, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid 'date', expected YYYY-MM-DD", "VALIDATION_ERROR")
+			return
+		}
+		date = parsed
+	}
+
+	report, err := h.snapshotService.GetSnapshot(date)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to load stock snapshot"
+		code := "INTERNAL_ERROR"
+
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			switch serviceErr.Err {
+			case services.ErrValidation:
+				status = http.StatusBadRequest
+			case services.ErrNotFound:
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", report)
+}
+
+// Capture handles POST /api/v1/reports/stock-snapshot
+// It snapshots current stock under today's date without waiting for the daily job.
+func (h *StockSnapshotHandler) Capture(w http.ResponseWriter, r *http.Request) {
+	capture, err := h.snapshotService.Capture()
+	if err != nil {
+		utils.Error(w, http.StatusInternalServerError, "Failed to capture stock snapshot", "INTERNAL_ERROR")
+		return
+	}
+
+	utils.Success(w, http.StatusCreated, "Stock snapshot captured", capture)
+}
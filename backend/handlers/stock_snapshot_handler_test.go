@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-chi/chi/v5"
+	"github.com/pointofsale/backend/config"
+	"github.com/pointofsale/backend/middleware"
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/services"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupStockSnapshotTestRouter(t *testing.T) (chi.Router, *gorm.DB) {
+	t.Helper()
+
+	db := testutil.SetupTestDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	cfg := &config.Config{
+		JWTAccessSecret: testutil.TestJWTAccessSecret,
+	}
+
+	userRepo := repositories.NewUserRepository(db)
+	snapshotService := services.NewStockSnapshotService(repositories.NewStockSnapshotRepository(db))
+	snapshotHandler := NewStockSnapshotHandler(snapshotService)
+
+	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTAccessSecret, rdb, userRepo)
+	permMiddleware := middleware.NewPermissionMiddleware(db, rdb)
+
+	r := chi.NewRouter()
+	r.Route("/api/v1/reports", func(r chi.Router) {
+		r.Use(authMiddleware.Authenticate)
+		r.With(permMiddleware.RequirePermission("Report", "Stock Report", "read")).Get("/stock-snapshot", snapshotHandler.GetSnapshot)
+		r.With(permMiddleware.RequirePermission("Report", "Stock Report", "create")).Post("/stock-snapshot", snapshotHandler.Capture)
+	})
+
+	return r, db
+}
+
+func TestStockSnapshot_CaptureThenQuery_ReturnsFrozenValues(t *testing.T) {
+	router, db := setupStockSnapshotTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+	product := testutil.CreateTestProduct(t, db)
+	variantID := product.Variants[0].ID
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/reports/stock-snapshot", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	capture := testutil.AssertSuccessResponse(t, rr, http.StatusCreated)
+	today := time.Now().UTC().Format("2006-01-02")
+	assert.Equal(t, today, capture["date"])
+
+	// Stock changes after the snapshot was taken
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", variantID).Update("current_stock", 5).Error)
+
+	req = testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/stock-snapshot?date="+today, nil, token)
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, today, data["date"])
+	items := data["items"].([]interface{})
+	var found map[string]interface{}
+	for _, item := range items {
+		row := item.(map[string]interface{})
+		if row["variantId"] == variantID {
+			found = row
+		}
+	}
+	require.NotNil(t, found)
+	assert.Equal(t, float64(100), found["quantity"])
+}
+
+func TestStockSnapshot_NoSnapshotForDate_Returns404(t *testing.T) {
+	router, db := setupStockSnapshotTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/stock-snapshot?date=2001-01-01", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestStockSnapshot_InvalidDate_Returns400(t *testing.T) {
+	router, db := setupStockSnapshotTestRouter(t)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, true)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/reports/stock-snapshot?date=01-03-2026", nil, token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
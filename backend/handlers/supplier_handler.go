@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/pointofsale/backend/repositories"
@@ -197,3 +198,126 @@ func (h *SupplierHandler) DeleteSupplier(w http.ResponseWriter, r *http.Request)
 
 	utils.Success(w, http.StatusOK, "Supplier deleted successfully", nil)
 }
+
+// ImportSuppliers handles POST /api/v1/suppliers/import
+// The CSV is sent as multipart/form-data in the "file" field.
+func (h *SupplierHandler) ImportSuppliers(w http.ResponseWriter, r *http.Request) {
+	file, ok := readCSVUpload(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	result, err := h.supplierService.ImportCSV(file)
+	if err != nil {
+		writeImportError(w, err, "Failed to import suppliers")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Supplier import completed", result)
+}
+
+// GetSupplierPayables handles GET /api/v1/suppliers/{id}/payables
+func (h *SupplierHandler) GetSupplierPayables(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid supplier ID", "VALIDATION_ERROR")
+		return
+	}
+
+	payables, err := h.supplierService.OutstandingPayables(uint(id))
+	if err != nil {
+		writeSupplierPeriodError(w, err, "Failed to compute supplier payables")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", payables)
+}
+
+// GetSupplierPerformance handles GET /api/v1/suppliers/{id}/performance
+// Optional query params: from, to (YYYY-MM-DD). Defaults to the last 90 days.
+func (h *SupplierHandler) GetSupplierPerformance(w http.ResponseWriter, r *http.Request) {
+	id, from, to, ok := parseSupplierPeriod(w, r)
+	if !ok {
+		return
+	}
+
+	performance, err := h.supplierService.Performance(id, from, to)
+	if err != nil {
+		writeSupplierPeriodError(w, err, "Failed to compute supplier performance")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", performance)
+}
+
+// GetSupplierMetrics handles GET /api/v1/suppliers/{id}/metrics
+// Optional query params: from, to (YYYY-MM-DD). Defaults to the last 90 days.
+func (h *SupplierHandler) GetSupplierMetrics(w http.ResponseWriter, r *http.Request) {
+	id, from, to, ok := parseSupplierPeriod(w, r)
+	if !ok {
+		return
+	}
+
+	metrics, err := h.supplierService.Metrics(id, from, to)
+	if err != nil {
+		writeSupplierPeriodError(w, err, "Failed to compute supplier metrics")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "", metrics)
+}
+
+// parseSupplierPeriod reads the supplier ID and the from and to query dates,
+// defaulting to the 90 days ending today (UTC). It writes a 400 response on
+// invalid input.
+func parseSupplierPeriod(w http.ResponseWriter, r *http.Request) (uint, time.Time, time.Time, bool) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid supplier ID", "VALIDATION_ERROR")
+		return 0, time.Time{}, time.Time{}, false
+	}
+
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		to, err = time.Parse("2006-01-02", toStr)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid 'to' date, expected YYYY-MM-DD", "VALIDATION_ERROR")
+			return 0, time.Time{}, time.Time{}, false
+		}
+	}
+
+	from := to.AddDate(0, 0, -90)
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		from, err = time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			utils.Error(w, http.StatusBadRequest, "Invalid 'from' date, expected YYYY-MM-DD", "VALIDATION_ERROR")
+			return 0, time.Time{}, time.Time{}, false
+		}
+	}
+
+	return uint(id), from, to, true
+}
+
+// writeSupplierPeriodError maps a supplier performance, metrics or payables
+// error to an HTTP response.
+func writeSupplierPeriodError(w http.ResponseWriter, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+	code := "INTERNAL_ERROR"
+
+	if serviceErr, ok := err.(*services.ServiceError); ok {
+		message = serviceErr.Message
+		code = serviceErr.Code
+		switch serviceErr.Err {
+		case services.ErrValidation:
+			status = http.StatusBadRequest
+		case services.ErrNotFound:
+			status = http.StatusNotFound
+		}
+	}
+	utils.Error(w, status, message, code)
+}
@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/pointofsale/backend/models"
@@ -19,14 +22,14 @@ import (
 )
 
 // setupSupplierTestRouter creates a test router with supplier endpoints
-func setupSupplierTestRouter(t *testing.T) (chi.Router, *gorm.DB) {
+func setupSupplierTestRouter(t *testing.T, cfg ...services.SupplierConfig) (chi.Router, *gorm.DB) {
 	t.Helper()
 
 	db := testutil.SetupTestDB(t)
 
 	// Initialize layers
 	supplierRepo := repositories.NewSupplierRepository(db)
-	supplierService := services.NewSupplierService(supplierRepo)
+	supplierService := services.NewSupplierService(supplierRepo, cfg...)
 	supplierHandler := NewSupplierHandler(supplierService)
 
 	// Setup router
@@ -34,7 +37,11 @@ func setupSupplierTestRouter(t *testing.T) (chi.Router, *gorm.DB) {
 	r.Route("/api/v1/suppliers", func(r chi.Router) {
 		r.Get("/", supplierHandler.ListSuppliers)
 		r.Get("/{id}", supplierHandler.GetSupplier)
+		r.Get("/{id}/performance", supplierHandler.GetSupplierPerformance)
+		r.Get("/{id}/metrics", supplierHandler.GetSupplierMetrics)
+		r.Get("/{id}/payables", supplierHandler.GetSupplierPayables)
 		r.Post("/", supplierHandler.CreateSupplier)
+		r.Post("/import", supplierHandler.ImportSuppliers)
 		r.Put("/{id}", supplierHandler.UpdateSupplier)
 		r.Delete("/{id}", supplierHandler.DeleteSupplier)
 	})
@@ -315,6 +322,61 @@ func TestUpdateSupplier_NotFound_Returns404(t *testing.T) {
 	assert.Equal(t, http.StatusNotFound, rr.Code)
 }
 
+func newSupplierImportRequest(t *testing.T, content string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "suppliers.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/api/v1/suppliers/import", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestImportSuppliers_ValidCSV_ReturnsRowSummary(t *testing.T) {
+	router, db := setupSupplierTestRouter(t)
+	testutil.CreateTestSupplier(t, db, func(s *models.Supplier) {
+		s.Name = "PT Existing"
+	})
+
+	csvData := "name,address,account_name,account_number\n" +
+		"PT Sumber Makmur,Jakarta,BCA;Mandiri,1234567890;0987654321\n" +
+		"pt existing,Bandung,,\n" +
+		"Bro\"ken,Jakarta,,\n"
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newSupplierImportRequest(t, csvData))
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(1), data["created"])
+	assert.Equal(t, float64(1), data["skipped"])
+	assert.Equal(t, float64(1), data["failed"])
+
+	rows := data["rows"].([]interface{})
+	require.Len(t, rows, 3)
+	assert.Equal(t, float64(4), rows[2].(map[string]interface{})["row"])
+
+	var imported models.Supplier
+	require.NoError(t, db.Preload("BankAccounts").Where("name = ?", "PT Sumber Makmur").First(&imported).Error)
+	assert.Len(t, imported.BankAccounts, 2)
+}
+
+func TestImportSuppliers_MissingFile_Returns400(t *testing.T) {
+	router, _ := setupSupplierTestRouter(t)
+
+	req := httptest.NewRequest("POST", "/api/v1/suppliers/import", strings.NewReader(""))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
 func TestDeleteSupplier_NoReferences_Returns200(t *testing.T) {
 	router, db := setupSupplierTestRouter(t)
 
@@ -437,3 +499,264 @@ func TestCreateSupplier_NoBankAccounts_Returns201(t *testing.T) {
 	data := response["data"].(map[string]interface{})
 	assert.Equal(t, "Simple Supplier", data["name"])
 }
+
+// createReceivedPO seeds a received PO with one line item for performance tests
+func createReceivedPO(t *testing.T, db *gorm.DB, supplier *models.Supplier, product *models.Product, number, date, expected string, received time.Time, orderedQty, receivedQty int, price float64) {
+	t.Helper()
+	variant := product.Variants[0]
+	unit := product.Units[0]
+	subtotal := float64(receivedQty) * price
+	po := &models.PurchaseOrder{
+		PONumber:     number,
+		SupplierID:   supplier.ID,
+		Date:         date,
+		ExpectedDate: &expected,
+		Status:       "received",
+		ReceivedDate: &received,
+		Subtotal:     &subtotal,
+		Items: []models.PurchaseOrderItem{
+			{
+				ProductID:     product.ID,
+				VariantID:     variant.ID,
+				UnitID:        unit.ID,
+				UnitName:      unit.Name,
+				ProductName:   product.Name,
+				VariantLabel:  "Default",
+				OrderedQty:    orderedQty,
+				Price:         price,
+				ReceivedQty:   &receivedQty,
+				ReceivedPrice: &price,
+				IsVerified:    true,
+			},
+		},
+	}
+	require.NoError(t, db.Create(po).Error)
+}
+
+func TestGetSupplierPerformance_Returns200WithMetrics(t *testing.T) {
+	router, db := setupSupplierTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+
+	// On time: ordered 1 Feb, expected 5 Feb, received 4 Feb (3 days lead)
+	createReceivedPO(t, db, supplier, product, "PO-PERF-0001", "2026-02-01", "2026-02-05",
+		time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC), 10, 10, 1000)
+	// Late: ordered 10 Feb, expected 12 Feb, received 15 Feb (5 days lead)
+	createReceivedPO(t, db, supplier, product, "PO-PERF-0002", "2026-02-10", "2026-02-12",
+		time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), 10, 6, 1000)
+	// Outside the requested period
+	createReceivedPO(t, db, supplier, product, "PO-PERF-0003", "2026-04-01", "2026-04-02",
+		time.Date(2026, 4, 3, 0, 0, 0, 0, time.UTC), 10, 10, 1000)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/suppliers/%d/performance?from=2026-02-01&to=2026-02-28", supplier.ID), nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(2), data["receivedOrders"])
+	assert.Equal(t, float64(1), data["onTimeOrders"])
+	assert.Equal(t, 0.5, data["onTimeRate"])
+	assert.Equal(t, float64(4), data["avgLeadTimeDays"])
+	assert.Equal(t, 0.8, data["fillRate"])
+	assert.Equal(t, float64(16000), data["totalSpend"])
+}
+
+func TestGetSupplierPayables_OnlyUnpaidReceivedOrdersContribute(t *testing.T) {
+	router, db := setupSupplierTestRouter(t, services.SupplierConfig{PaymentTermDays: 14})
+	defer testutil.CleanupTestDB(t, db)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+
+	// Unpaid: 10 x 1000 and 6 x 1000
+	createReceivedPO(t, db, supplier, product, "PO-PAY-0001", "2026-02-01", "2026-02-05",
+		time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC), 10, 10, 1000)
+	createReceivedPO(t, db, supplier, product, "PO-PAY-0002", "2026-02-10", "2026-02-12",
+		time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), 10, 6, 1000)
+	// Paid: does not contribute
+	createReceivedPO(t, db, supplier, product, "PO-PAY-0003", "2026-02-11", "2026-02-13",
+		time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC), 10, 10, 5000)
+	paidAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, db.Model(&models.PurchaseOrder{}).Where("po_number = ?", "PO-PAY-0003").Update("paid_at", paidAt).Error)
+	// Not received yet: nothing is owed
+	draft := &models.PurchaseOrder{PONumber: "PO-PAY-0004", SupplierID: supplier.ID, Date: "2026-02-20", Status: "sent"}
+	require.NoError(t, db.Create(draft).Error)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/suppliers/%d/payables", supplier.ID), nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(16000), data["totalOutstanding"])
+	orders := data["orders"].([]interface{})
+	require.Len(t, orders, 2)
+	first := orders[0].(map[string]interface{})
+	assert.Equal(t, "PO-PAY-0001", first["poNumber"])
+	assert.Equal(t, float64(10000), first["amount"])
+	assert.Equal(t, "2026-02-18", first["dueDate"])
+	second := orders[1].(map[string]interface{})
+	assert.Equal(t, "PO-PAY-0002", second["poNumber"])
+	assert.Equal(t, "2026-03-01", second["dueDate"])
+}
+
+func TestGetSupplierPayables_NotFound_Returns404(t *testing.T) {
+	router, db := setupSupplierTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	req := httptest.NewRequest("GET", "/api/v1/suppliers/999999/payables", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetSupplierPerformance_InvalidDate_Returns400(t *testing.T) {
+	router, db := setupSupplierTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/suppliers/%d/performance?from=02-2026", supplier.ID), nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetSupplierPerformance_NotFound_Returns404(t *testing.T) {
+	router, db := setupSupplierTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	req := httptest.NewRequest("GET", "/api/v1/suppliers/999999/performance", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetSupplierMetrics_Returns200WithMetrics(t *testing.T) {
+	router, db := setupSupplierTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+
+	// Ordered 1 Feb, received 4 Feb (3 days), complete
+	createReceivedPO(t, db, supplier, product, "PO-MET-0001", "2026-02-01", "2026-02-05",
+		time.Date(2026, 2, 4, 0, 0, 0, 0, time.UTC), 10, 10, 1000)
+	// Ordered 10 Feb, received 15 Feb (5 days), 4 short
+	createReceivedPO(t, db, supplier, product, "PO-MET-0002", "2026-02-10", "2026-02-12",
+		time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), 10, 6, 1000)
+	// Dated outside the requested period
+	createReceivedPO(t, db, supplier, product, "PO-MET-0003", "2026-04-01", "2026-04-02",
+		time.Date(2026, 4, 3, 0, 0, 0, 0, time.UTC), 10, 10, 1000)
+	// Still open
+	require.NoError(t, db.Create(&models.PurchaseOrder{
+		PONumber:   "PO-MET-0004",
+		SupplierID: supplier.ID,
+		Date:       "2026-02-20",
+		Status:     "sent",
+	}).Error)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/suppliers/%d/metrics?from=2026-02-01&to=2026-02-28", supplier.ID), nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(3), data["totalOrders"])
+	assert.Equal(t, float64(2), data["receivedOrders"])
+	assert.Equal(t, float64(16000), data["totalReceivedValue"])
+	assert.Equal(t, float64(4), data["avgTurnaroundDays"])
+	assert.Equal(t, float64(1), data["discrepancyItems"])
+}
+
+func TestGetSupplierMetrics_NoOrders_ReturnsZeros(t *testing.T) {
+	router, db := setupSupplierTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/suppliers/%d/metrics", supplier.ID), nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(0), data["totalOrders"])
+	assert.Equal(t, float64(0), data["totalReceivedValue"])
+	assert.Equal(t, float64(0), data["avgTurnaroundDays"])
+	assert.Equal(t, float64(0), data["discrepancyItems"])
+}
+
+func TestGetSupplierMetrics_NotFound_Returns404(t *testing.T) {
+	router, db := setupSupplierTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	req := httptest.NewRequest("GET", "/api/v1/suppliers/999999/metrics", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestUpdateSupplier_DuplicateBankAccountNumber_Returns409(t *testing.T) {
+	router, db := setupSupplierTestRouter(t)
+
+	supplier := &models.Supplier{
+		Name:    "PT Sumber Makmur",
+		Address: "Jakarta",
+		Active:  true,
+		BankAccounts: []models.SupplierBankAccount{
+			{AccountName: "BCA", AccountNumber: "1234567890"},
+		},
+	}
+	require.NoError(t, db.Create(supplier).Error)
+
+	body := `{
+		"bankAccounts": [
+			{"accountName": "BCA", "accountNumber": "1234567890"},
+			{"accountName": "BCA Copy", "accountNumber": " 1234567890 "}
+		]
+	}`
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/suppliers/%d", supplier.ID), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Equal(t, "DUPLICATE_BANK_ACCOUNT", response["code"])
+
+	// The existing accounts are untouched
+	var count int64
+	db.Model(&models.SupplierBankAccount{}).Where("supplier_id = ?", supplier.ID).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestCreateSupplier_BankAccountNumberOfOtherSupplier_Returns201(t *testing.T) {
+	router, db := setupSupplierTestRouter(t)
+
+	require.NoError(t, db.Create(&models.Supplier{
+		Name:    "PT Sumber Makmur",
+		Address: "Jakarta",
+		Active:  true,
+		BankAccounts: []models.SupplierBankAccount{
+			{AccountName: "BCA", AccountNumber: "1234567890"},
+		},
+	}).Error)
+
+	body := `{
+		"name": "CV Maju Jaya",
+		"address": "Bandung",
+		"bankAccounts": [{"accountName": "BCA", "accountNumber": "1234567890"}]
+	}`
+	req := httptest.NewRequest("POST", "/api/v1/suppliers", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+}
@@ -2,8 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/pointofsale/backend/middleware"
@@ -159,6 +163,7 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
 		return
 	}
+	input.ActorID = middleware.GetUserID(r.Context())
 
 	// Update user
 	user, err := h.userService.UpdateUser(uint(id), input)
@@ -263,6 +268,44 @@ func (h *UserHandler) ApproveUser(w http.ResponseWriter, r *http.Request) {
 	utils.Success(w, http.StatusOK, "User approved successfully", user)
 }
 
+// ResendCredentials handles POST /api/v1/users/{id}/resend-credentials
+func (h *UserHandler) ResendCredentials(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid user ID", "VALIDATION_ERROR")
+		return
+	}
+
+	tempPassword, err := h.userService.ResendCredentials(uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to resend credentials"
+		code := "INTERNAL_ERROR"
+
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			switch serviceErr.Err {
+			case services.ErrValidation:
+				status = http.StatusBadRequest
+			case services.ErrNotFound:
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	// Email is disabled, so the admin passes the password on
+	if tempPassword != "" {
+		utils.Success(w, http.StatusOK, "Credentials reset successfully", map[string]string{"tempPassword": tempPassword})
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Credentials email sent successfully", nil)
+}
+
 // RejectUser handles DELETE /api/v1/users/{id}/reject
 func (h *UserHandler) RejectUser(w http.ResponseWriter, r *http.Request) {
 	// Parse ID
@@ -297,6 +340,190 @@ func (h *UserHandler) RejectUser(w http.ResponseWriter, r *http.Request) {
 	utils.Success(w, http.StatusOK, "User registration rejected", nil)
 }
 
+// ChangePassword handles PATCH /api/v1/users/me/password
+func (h *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		utils.Error(w, http.StatusUnauthorized, "User not authenticated", "UNAUTHORIZED")
+		return
+	}
+
+	var input struct {
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
+		ConfirmPassword string `json:"confirmPassword"`
+		RefreshToken    string `json:"refreshToken"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		utils.Error(w, http.StatusBadRequest, "Invalid request body", "VALIDATION_ERROR")
+		return
+	}
+
+	err := h.userService.ChangePassword(userID, input.CurrentPassword, input.NewPassword, input.ConfirmPassword, input.RefreshToken)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to change password"
+		code := "INTERNAL_ERROR"
+
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			switch serviceErr.Err {
+			case services.ErrValidation:
+				status = http.StatusBadRequest
+			case services.ErrUnauthorized:
+				status = http.StatusUnauthorized
+			case services.ErrNotFound:
+				status = http.StatusNotFound
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "Password changed successfully", nil)
+}
+
+// ExportAccessReport handles GET /api/v1/users/access-report/export
+func (h *UserHandler) ExportAccessReport(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = services.AccessReportFormatCSV
+	}
+
+	data, err := h.userService.ExportAccessReport(format)
+	if err != nil {
+		status := http.StatusInternalServerError
+		message := "Failed to export access report"
+		code := "INTERNAL_ERROR"
+
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+			if serviceErr.Err == services.ErrValidation {
+				status = http.StatusBadRequest
+			}
+		}
+		utils.Error(w, status, message, code)
+		return
+	}
+
+	contentType := "text/csv"
+	if format == services.AccessReportFormatXLSX {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="access-report.`+format+`"`)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(data)
+}
+
+// ExportUsers handles GET /api/v1/users/export
+// It accepts the same search, sortBy, sortDir and status filters as ListUsers.
+func (h *UserHandler) ExportUsers(w http.ResponseWriter, r *http.Request) {
+	params := repositories.PaginationParams{
+		Search:  r.URL.Query().Get("search"),
+		SortBy:  r.URL.Query().Get("sortBy"),
+		SortDir: r.URL.Query().Get("sortDir"),
+	}
+	filename := fmt.Sprintf("users-%s.csv", time.Now().Format("20060102-150405"))
+	out := &attachmentWriter{w: w, contentType: "text/csv", filename: filename}
+
+	if err := h.userService.Export(out, params, r.URL.Query().Get("status")); err != nil {
+		// Once rows are streamed the status is sent; the client sees a truncated file.
+		if out.started {
+			return
+		}
+		message := "Failed to export users"
+		code := "INTERNAL_ERROR"
+		if serviceErr, ok := err.(*services.ServiceError); ok {
+			message = serviceErr.Message
+			code = serviceErr.Code
+		}
+		utils.Error(w, http.StatusInternalServerError, message, code)
+	}
+}
+
+// attachmentWriter sends download headers on the first write, so an error
+// before any data is produced can still be answered with a JSON error.
+type attachmentWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (a *attachmentWriter) Write(p []byte) (int, error) {
+	if !a.started {
+		a.started = true
+		a.w.Header().Set("Content-Type", a.contentType)
+		a.w.Header().Set("Content-Disposition", `attachment; filename="`+a.filename+`"`)
+		a.w.WriteHeader(http.StatusOK)
+	}
+	return a.w.Write(p)
+}
+
+// csvImportMaxBytes caps the size of an uploaded import CSV.
+const csvImportMaxBytes = 1 << 20
+
+// ImportUsers handles POST /api/v1/users/import
+// The CSV is sent as multipart/form-data in the "file" field.
+func (h *UserHandler) ImportUsers(w http.ResponseWriter, r *http.Request) {
+	file, ok := readCSVUpload(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	result, err := h.userService.BulkImport(file)
+	if err != nil {
+		writeImportError(w, err, "Failed to import users")
+		return
+	}
+
+	utils.Success(w, http.StatusOK, "User import completed", result)
+}
+
+// readCSVUpload returns the CSV uploaded in the multipart "file" field. It
+// writes the error response and returns false when the upload is missing or
+// too large.
+func readCSVUpload(w http.ResponseWriter, r *http.Request) (multipart.File, bool) {
+	// Leave headroom for multipart boundaries and headers.
+	r.Body = http.MaxBytesReader(w, r.Body, csvImportMaxBytes+(1<<20))
+	if err := r.ParseMultipartForm(csvImportMaxBytes); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.Error(w, http.StatusBadRequest, "File exceeds the maximum allowed size", "FILE_TOO_LARGE")
+			return nil, false
+		}
+		utils.Error(w, http.StatusBadRequest, "Invalid multipart form", "VALIDATION_ERROR")
+		return nil, false
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		utils.Error(w, http.StatusBadRequest, "File is required", "VALIDATION_ERROR")
+		return nil, false
+	}
+	return file, true
+}
+
+// writeImportError writes the response for a CSV import that could not run.
+func writeImportError(w http.ResponseWriter, err error, fallback string) {
+	status := http.StatusInternalServerError
+	message := fallback
+	code := "INTERNAL_ERROR"
+
+	if serviceErr, ok := err.(*services.ServiceError); ok {
+		message = serviceErr.Message
+		code = serviceErr.Code
+		if serviceErr.Err == services.ErrValidation {
+			status = http.StatusBadRequest
+		}
+	}
+	utils.Error(w, status, message, code)
+}
+
 // UploadProfilePicture handles POST /api/v1/users/{id}/profile-picture
 func (h *UserHandler) UploadProfilePicture(w http.ResponseWriter, r *http.Request) {
 	// TODO: Implement file upload handling
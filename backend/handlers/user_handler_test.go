@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -47,7 +49,8 @@ func setupUserTestRouter(t *testing.T) (chi.Router, *gorm.DB, *redis.Client, *co
 
 	// Initialize layers
 	userRepo := repositories.NewUserRepository(db)
-	userService := services.NewUserService(userRepo, rdb, cfg, nil) // nil email service for tests
+	userService := services.NewUserService(userRepo, rdb, cfg, services.NoopEmailService{})
+	userService.SetAuditRecorder(repositories.NewAuditLogRepository(db))
 	userHandler := NewUserHandler(userService)
 	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTAccessSecret, rdb, userRepo)
 
@@ -60,11 +63,16 @@ func setupUserTestRouter(t *testing.T) (chi.Router, *gorm.DB, *redis.Client, *co
 	r.Route("/api/v1/users", func(r chi.Router) {
 		r.Use(authMiddleware.Authenticate)
 		r.With(permMiddleware.RequirePermission("Settings", "Users", "read")).Get("/", userHandler.ListUsers)
+		r.Patch("/me/password", userHandler.ChangePassword)
+		r.With(permMiddleware.RequirePermission("Settings", "Roles & Permissions", "read")).Get("/access-report/export", userHandler.ExportAccessReport)
+		r.With(permMiddleware.RequirePermission("Settings", "Users", "read")).Get("/export", userHandler.ExportUsers)
 		r.With(permMiddleware.RequirePermission("Settings", "Users", "read")).Get("/{id}", userHandler.GetUser)
 		r.With(permMiddleware.RequirePermission("Settings", "Users", "create")).Post("/", userHandler.CreateUser)
+		r.With(permMiddleware.RequirePermission("Settings", "Users", "create")).Post("/import", userHandler.ImportUsers)
 		r.With(permMiddleware.RequirePermission("Settings", "Users", "update")).Put("/{id}", userHandler.UpdateUser)
 		r.With(permMiddleware.RequirePermission("Settings", "Users", "delete")).Delete("/{id}", userHandler.DeleteUser)
 		r.With(permMiddleware.RequirePermission("Settings", "Users", "update")).Patch("/{id}/approve", userHandler.ApproveUser)
+		r.With(permMiddleware.RequirePermission("Settings", "Users", "update")).Post("/{id}/resend-credentials", userHandler.ResendCredentials)
 		r.With(permMiddleware.RequirePermission("Settings", "Users", "delete")).Delete("/{id}/reject", userHandler.RejectUser)
 		r.With(permMiddleware.RequirePermission("Settings", "Users", "update")).Post("/{id}/profile-picture", userHandler.UploadProfilePicture)
 	})
@@ -290,6 +298,79 @@ func TestCreateUser_ValidBody_Returns201(t *testing.T) {
 	assert.Equal(t, "active", userData["status"])
 }
 
+func newUserImportRequest(t *testing.T, content, token string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "users.csv")
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/users/import", &body, token)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestImportUsers_ValidCSV_ReturnsRowSummary(t *testing.T) {
+	router, db, _, _ := setupUserTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, admin.IsSuperAdmin)
+	role := testutil.CreateTestRole(t, db)
+	testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Email = "existing@example.com"
+	})
+
+	csvData := "name,email,phone,roles\n" +
+		"Import One,import1@example.com,0811," + role.Name + "\n" +
+		"Existing,existing@example.com,,\n" +
+		"Bad,not-an-email,,\n"
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newUserImportRequest(t, csvData, token))
+
+	data := testutil.AssertSuccessResponse(t, rr, http.StatusOK)
+	assert.Equal(t, float64(1), data["created"])
+	assert.Equal(t, float64(1), data["skipped"])
+	assert.Equal(t, float64(1), data["failed"])
+
+	var imported models.User
+	require.NoError(t, db.Preload("Roles").Where("email = ?", "import1@example.com").First(&imported).Error)
+	assert.Equal(t, "active", imported.Status)
+	require.Len(t, imported.Roles, 1)
+	assert.Equal(t, role.ID, imported.Roles[0].ID)
+}
+
+func TestImportUsers_MissingFile_Returns400(t *testing.T) {
+	router, db, _, _ := setupUserTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, admin.IsSuperAdmin)
+
+	req := testutil.AuthenticatedRequest(t, "POST", "/api/v1/users/import", strings.NewReader("{}"), token)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestImportUsers_NoPermission_Returns403(t *testing.T) {
+	router, db, _, _ := setupUserTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := testutil.CreateTestUser(t, db)
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newUserImportRequest(t, "name,email\n", token))
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
 func TestCreateUser_DuplicateEmail_Returns409(t *testing.T) {
 	router, db, _, _ := setupUserTestRouter(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -374,6 +455,37 @@ func TestUpdateUser_ValidBody_Returns200(t *testing.T) {
 	assert.Equal(t, "Updated Name", userData["name"])
 }
 
+func TestUpdateUser_ValidBody_RecordsAuditBeforeAndAfter(t *testing.T) {
+	router, db, _, _ := setupUserTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	user := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Email = "old@example.com"
+	})
+	token := testutil.GenerateTestAccessToken(t, admin.ID, admin.IsSuperAdmin)
+
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/users/%d", user.ID), strings.NewReader(`{"email": "new@example.com"}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var entry models.AuditLog
+	require.NoError(t, db.Where("entity = ? AND entity_id = ?", "user", fmt.Sprint(user.ID)).First(&entry).Error)
+	assert.Equal(t, models.AuditEventUserUpdated, entry.Event)
+	require.NotNil(t, entry.UserID)
+	assert.Equal(t, admin.ID, *entry.UserID)
+
+	var before, after map[string]interface{}
+	require.NoError(t, json.Unmarshal(entry.Before, &before))
+	require.NoError(t, json.Unmarshal(entry.After, &after))
+	assert.Equal(t, "old@example.com", before["email"])
+	assert.Equal(t, "new@example.com", after["email"])
+	assert.NotContains(t, string(entry.After), "passwordHash")
+}
+
 func TestUpdateUser_SuperAdminStatusChange_Returns403(t *testing.T) {
 	router, db, _, _ := setupUserTestRouter(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -489,6 +601,7 @@ func TestApproveUser_Pending_Returns200(t *testing.T) {
 	pendingUser := testutil.CreateTestUser(t, db, func(u *models.User) {
 		u.Name = "Pending User"
 		u.Status = "pending"
+		u.EmailVerified = true
 	})
 
 	token := testutil.GenerateTestAccessToken(t, admin.ID, admin.IsSuperAdmin)
@@ -553,6 +666,166 @@ func TestRejectUser_Pending_Returns200(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
 
+// Test ChangePassword
+func TestChangePassword_ValidBody_Returns200(t *testing.T) {
+	router, db, _, _ := setupUserTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := testutil.CreateTestUser(t, db)
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	body := `{"currentPassword": "Password@123", "newPassword": "NewPassword@456", "confirmPassword": "NewPassword@456"}`
+	req := testutil.AuthenticatedRequest(t, "PATCH", "/api/v1/users/me/password", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var updated models.User
+	require.NoError(t, db.First(&updated, user.ID).Error)
+	assert.NotEqual(t, user.PasswordHash, updated.PasswordHash)
+}
+
+func TestChangePassword_WrongCurrentPassword_Returns401(t *testing.T) {
+	router, db, _, _ := setupUserTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := testutil.CreateTestUser(t, db)
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	body := `{"currentPassword": "Wrong@123", "newPassword": "NewPassword@456", "confirmPassword": "NewPassword@456"}`
+	req := testutil.AuthenticatedRequest(t, "PATCH", "/api/v1/users/me/password", strings.NewReader(body), token)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), "INVALID_CURRENT_PASSWORD")
+}
+
+// Test ResendCredentials
+func TestResendCredentials_ActiveUser_Returns200AndChangesPassword(t *testing.T) {
+	router, db, _, _ := setupUserTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	activeUser := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Status = "active"
+	})
+	originalHash := activeUser.PasswordHash
+
+	token := testutil.GenerateTestAccessToken(t, admin.ID, admin.IsSuperAdmin)
+
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/users/%d/resend-credentials", activeUser.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var updated models.User
+	require.NoError(t, db.First(&updated, activeUser.ID).Error)
+	assert.NotEqual(t, originalHash, updated.PasswordHash)
+}
+
+func TestResendCredentials_PendingUser_Returns400(t *testing.T) {
+	router, db, _, _ := setupUserTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	pendingUser := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Status = "pending"
+	})
+
+	token := testutil.GenerateTestAccessToken(t, admin.ID, admin.IsSuperAdmin)
+
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/users/%d/resend-credentials", pendingUser.ID), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+// Test ExportAccessReport
+func TestExportAccessReport_CSV_Returns200WithAttachment(t *testing.T) {
+	router, db, _, _ := setupUserTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, admin.IsSuperAdmin)
+
+	req := httptest.NewRequest("GET", "/api/v1/users/access-report/export", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+	assert.Contains(t, rr.Header().Get("Content-Disposition"), "access-report.csv")
+	assert.True(t, strings.HasPrefix(rr.Body.String(), "User ID,Name,Email,Status,Module,Feature,Action"))
+	assert.Contains(t, rr.Body.String(), admin.Email+",active,Settings,Roles & Permissions,read")
+}
+
+func TestExportAccessReport_InvalidFormat_Returns400(t *testing.T) {
+	router, db, _, _ := setupUserTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, admin.IsSuperAdmin)
+
+	req := httptest.NewRequest("GET", "/api/v1/users/access-report/export?format=pdf", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+// Test ExportUsers
+func TestExportUsers_StatusFilter_Returns200WithTimestampedAttachment(t *testing.T) {
+	router, db, _, _ := setupUserTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	admin := testutil.CreateTestSuperAdmin(t, db)
+	token := testutil.GenerateTestAccessToken(t, admin.ID, admin.IsSuperAdmin)
+	pending := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Email = "pending-export@example.com"
+		u.Status = "pending"
+	})
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/users/export?status=active", nil, token)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+	assert.Regexp(t, `^attachment; filename="users-\d{8}-\d{6}\.csv"$`, rr.Header().Get("Content-Disposition"))
+	assert.True(t, strings.HasPrefix(rr.Body.String(), "id,name,email,phone,status,roles,createdAt\n"))
+	assert.Contains(t, rr.Body.String(), admin.Email)
+	assert.NotContains(t, rr.Body.String(), pending.Email)
+}
+
+func TestExportUsers_WithoutPermission_Returns403(t *testing.T) {
+	router, db, _, _ := setupUserTestRouter(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	user := testutil.CreateTestUser(t, db)
+	token := testutil.GenerateTestAccessToken(t, user.ID, false)
+
+	req := testutil.AuthenticatedRequest(t, "GET", "/api/v1/users/export", nil, token)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
 // Test UploadProfilePicture (placeholder - file upload needs multipart handling)
 func TestUploadProfilePicture_ValidImage_Returns200(t *testing.T) {
 	// TODO: Implement multipart file upload test
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/lib/pq"
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/utils"
@@ -14,18 +16,29 @@ import (
 	"gorm.io/gorm"
 )
 
+// PermissionConfig holds tunable behaviour for PermissionMiddleware.
+type PermissionConfig struct {
+	// AuditDenials records every permission-denied request in the audit log.
+	AuditDenials bool
+}
+
 // PermissionMiddleware handles permission-based authorization
 type PermissionMiddleware struct {
 	db    *gorm.DB
 	redis *redis.Client
+	cfg   PermissionConfig
 }
 
 // NewPermissionMiddleware creates a new permission middleware instance
-func NewPermissionMiddleware(db *gorm.DB, rdb *redis.Client) *PermissionMiddleware {
-	return &PermissionMiddleware{
+func NewPermissionMiddleware(db *gorm.DB, rdb *redis.Client, cfg ...PermissionConfig) *PermissionMiddleware {
+	pm := &PermissionMiddleware{
 		db:    db,
 		redis: rdb,
 	}
+	if len(cfg) > 0 {
+		pm.cfg = cfg[0]
+	}
+	return pm
 }
 
 // permissionCache holds cached user permissions
@@ -40,6 +53,13 @@ type permissionEntry struct {
 	Actions []string `json:"actions"`
 }
 
+// Permission cache keys come from utils.PermissionCacheKey and hold the
+// JSON-encoded permissionCache of every action the user's roles grant, merged
+// across roles. Entries expire after permissionCacheTTL, but anything that
+// changes what a user may do must delete the affected keys so the change
+// applies on the next request: the permission handler does so for role
+// permission edits with InvalidateRolePermissionCache, and the user and role
+// services for role assignment changes and role deletion.
 const permissionCacheTTL = 5 * time.Minute
 
 // RequirePermission returns middleware that checks if the user has the specified permission.
@@ -66,11 +86,13 @@ func (pm *PermissionMiddleware) RequirePermission(module, feature, action string
 			hasPermission, err := pm.checkPermission(r.Context(), userID, module, feature, action)
 			if err != nil {
 				// Log error but return generic forbidden message to user
+				pm.recordDenial(r, userID, module, feature, action)
 				utils.Error(w, http.StatusForbidden, "You don't have permission to perform this action", "FORBIDDEN")
 				return
 			}
 
 			if !hasPermission {
+				pm.recordDenial(r, userID, module, feature, action)
 				utils.Error(w, http.StatusForbidden, "You don't have permission to perform this action", "FORBIDDEN")
 				return
 			}
@@ -80,6 +102,33 @@ func (pm *PermissionMiddleware) RequirePermission(module, feature, action string
 	}
 }
 
+// recordDenial writes a permission-denied event to the audit log when
+// auditing is enabled. Failures are logged and never block the response.
+func (pm *PermissionMiddleware) recordDenial(r *http.Request, userID uint, module, feature, action string) {
+	if !pm.cfg.AuditDenials {
+		return
+	}
+
+	route := r.URL.Path
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			route = pattern
+		}
+	}
+
+	entry := &models.AuditLog{
+		Event:      models.AuditEventPermissionDenied,
+		UserID:     &userID,
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Route:      route,
+		Permission: fmt.Sprintf("%s:%s:%s", module, feature, action),
+	}
+	if err := pm.db.WithContext(r.Context()).Create(entry).Error; err != nil {
+		slog.Error("failed to audit permission denial", "user_id", userID, "path", r.URL.Path, "error", err)
+	}
+}
+
 // checkPermission checks if a user has a specific permission action
 func (pm *PermissionMiddleware) checkPermission(ctx context.Context, userID uint, module, feature, action string) (bool, error) {
 	// Try to get permissions from cache
@@ -104,7 +153,7 @@ func (pm *PermissionMiddleware) checkPermission(ctx context.Context, userID uint
 
 // getPermissionsFromCache retrieves cached permissions from Redis
 func (pm *PermissionMiddleware) getPermissionsFromCache(ctx context.Context, userID uint) (*permissionCache, error) {
-	cacheKey := buildPermissionCacheKey(userID)
+	cacheKey := utils.PermissionCacheKey(userID)
 	data, err := pm.redis.Get(ctx, cacheKey).Result()
 	if err != nil {
 		return nil, err
@@ -172,7 +221,7 @@ func (pm *PermissionMiddleware) loadPermissionsFromDB(ctx context.Context, userI
 
 // cachePermissions stores permissions in Redis
 func (pm *PermissionMiddleware) cachePermissions(ctx context.Context, userID uint, cache *permissionCache) error {
-	cacheKey := buildPermissionCacheKey(userID)
+	cacheKey := utils.PermissionCacheKey(userID)
 	data, err := json.Marshal(cache)
 	if err != nil {
 		return err
@@ -193,13 +242,26 @@ func (pm *PermissionMiddleware) hasPermissionInCache(cache *permissionCache, mod
 
 // InvalidatePermissionCache invalidates the permission cache for a user
 func InvalidatePermissionCache(ctx context.Context, rdb *redis.Client, userID uint) error {
-	cacheKey := buildPermissionCacheKey(userID)
+	cacheKey := utils.PermissionCacheKey(userID)
 	return rdb.Del(ctx, cacheKey).Err()
 }
 
-// buildPermissionCacheKey builds the Redis key for user permissions
-func buildPermissionCacheKey(userID uint) string {
-	return fmt.Sprintf("perms:%d", userID)
+// InvalidateRolePermissionCache invalidates the permission cache of every
+// user assigned the role
+func InvalidateRolePermissionCache(ctx context.Context, db *gorm.DB, rdb *redis.Client, roleID uint) error {
+	var userIDs []uint
+	if err := db.Table("user_roles").Where("role_id = ?", roleID).Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = utils.PermissionCacheKey(userID)
+	}
+	return rdb.Del(ctx, keys...).Err()
 }
 
 // mergeActions merges two action slices, removing duplicates
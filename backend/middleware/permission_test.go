@@ -7,9 +7,11 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/lib/pq"
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/testutil"
+	"github.com/pointofsale/backend/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -262,7 +264,7 @@ func TestRequirePermission_CachedPermissions_UsesCache(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rr1.Code)
 
 	// Verify cache was set
-	cacheKey := buildPermissionCacheKey(user.ID)
+	cacheKey := utils.PermissionCacheKey(user.ID)
 	cachedData := rdb.Get(context.Background(), cacheKey).Val()
 	assert.NotEmpty(t, cachedData, "permissions should be cached in Redis")
 
@@ -361,3 +363,157 @@ func TestRequirePermission_NoUserInContext_Returns401(t *testing.T) {
 	// Assert: Should return 401 unauthorized
 	assert.Equal(t, http.StatusUnauthorized, rr.Code)
 }
+
+func TestRequirePermission_DenialAuditEnabled_WritesAuditEntry(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	rdb := testutil.SetupTestRedis(t)
+	defer testutil.CleanupTestRedis(t, rdb)
+
+	// User without any roles
+	user := testutil.CreateTestUser(t, db)
+
+	permMiddleware := NewPermissionMiddleware(db, rdb, PermissionConfig{AuditDenials: true})
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// Route through chi so the matched pattern is recorded
+	router := chi.NewRouter()
+	router.With(permMiddleware.RequirePermission("Settings", "Users", "delete")).Delete("/users/{id}", testHandler)
+
+	req := httptest.NewRequest("DELETE", "/users/42", nil)
+	ctx := context.WithValue(req.Context(), UserIDKey, user.ID)
+	ctx = context.WithValue(ctx, IsSuperAdminKey, false)
+	req = req.WithContext(ctx)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	var entries []models.AuditLog
+	require.NoError(t, db.Where("event = ?", models.AuditEventPermissionDenied).Find(&entries).Error)
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].UserID)
+	assert.Equal(t, user.ID, *entries[0].UserID)
+	assert.Equal(t, "DELETE", entries[0].Method)
+	assert.Equal(t, "/users/42", entries[0].Path)
+	assert.Equal(t, "/users/{id}", entries[0].Route)
+	assert.Equal(t, "Settings:Users:delete", entries[0].Permission)
+	assert.False(t, entries[0].CreatedAt.IsZero())
+}
+
+func TestRequirePermission_DenialAuditDisabled_WritesNothing(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	rdb := testutil.SetupTestRedis(t)
+	defer testutil.CleanupTestRedis(t, rdb)
+
+	user := testutil.CreateTestUser(t, db)
+
+	permMiddleware := NewPermissionMiddleware(db, rdb)
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := permMiddleware.RequirePermission("Settings", "Users", "delete")(testHandler)
+
+	req := httptest.NewRequest("DELETE", "/test", nil)
+	ctx := context.WithValue(req.Context(), UserIDKey, user.ID)
+	ctx = context.WithValue(ctx, IsSuperAdminKey, false)
+	req = req.WithContext(ctx)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	var count int64
+	require.NoError(t, db.Model(&models.AuditLog{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestRequirePermission_DenialAuditEnabled_AllowedRequestWritesNothing(t *testing.T) {
+	// Setup
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	rdb := testutil.SetupTestRedis(t)
+	defer testutil.CleanupTestRedis(t, rdb)
+
+	superAdmin := testutil.CreateTestSuperAdmin(t, db)
+
+	permMiddleware := NewPermissionMiddleware(db, rdb, PermissionConfig{AuditDenials: true})
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := permMiddleware.RequirePermission("Settings", "Users", "delete")(testHandler)
+
+	req := httptest.NewRequest("DELETE", "/test", nil)
+	ctx := context.WithValue(req.Context(), UserIDKey, superAdmin.ID)
+	ctx = context.WithValue(ctx, IsSuperAdminKey, true)
+	req = req.WithContext(ctx)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var count int64
+	require.NoError(t, db.Model(&models.AuditLog{}).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestInvalidateRolePermissionCache_RevokedAction_DeniedOnNextRequest(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+	rdb := testutil.SetupTestRedis(t)
+	defer testutil.CleanupTestRedis(t, rdb)
+
+	permission := testutil.CreateTestPermission(t, db, func(p *models.Permission) {
+		p.Module = "Reports"
+		p.Feature = "Sales"
+		p.Actions = pq.StringArray{"read", "export"}
+	})
+	role := testutil.CreateTestRole(t, db)
+	rolePermission := &models.RolePermission{
+		RoleID:       role.ID,
+		PermissionID: permission.ID,
+		Actions:      pq.StringArray{"read", "export"},
+	}
+	require.NoError(t, db.Create(rolePermission).Error)
+
+	user := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Roles = []models.Role{*role}
+	})
+	otherUser := testutil.CreateTestUser(t, db)
+
+	permMiddleware := NewPermissionMiddleware(db, rdb)
+	handler := permMiddleware.RequirePermission("Reports", "Sales", "export")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	serve := func() int {
+		req := httptest.NewRequest("POST", "/test", nil)
+		ctx := context.WithValue(req.Context(), UserIDKey, user.ID)
+		ctx = context.WithValue(ctx, IsSuperAdminKey, false)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req.WithContext(ctx))
+		return rr.Code
+	}
+
+	// First request is allowed and caches the permissions
+	assert.Equal(t, http.StatusOK, serve())
+	require.Equal(t, int64(1), rdb.Exists(context.Background(), utils.PermissionCacheKey(user.ID)).Val())
+	otherKey := utils.PermissionCacheKey(otherUser.ID)
+	require.NoError(t, rdb.Set(context.Background(), otherKey, "{}", permissionCacheTTL).Err())
+
+	// Revoke "export" and invalidate by role
+	rolePermission.Actions = pq.StringArray{"read"}
+	require.NoError(t, db.Save(rolePermission).Error)
+	require.NoError(t, InvalidateRolePermissionCache(context.Background(), db, rdb, role.ID))
+
+	assert.Equal(t, http.StatusForbidden, serve())
+	// Users without the role keep their cache
+	assert.Equal(t, int64(1), rdb.Exists(context.Background(), otherKey).Val())
+}
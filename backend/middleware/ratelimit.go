@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pointofsale/backend/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript refills the bucket for the time elapsed since the last
+// request, then takes one token if available. It returns {allowed, waitMs}.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local refill_per_ms = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+if now > ts then
+	tokens = math.min(capacity, tokens + (now - ts) * refill_per_ms)
+	ts = now
+end
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / refill_per_ms)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(ts))
+redis.call('PEXPIRE', KEYS[1], ttl)
+return {allowed, wait}
+`)
+
+// RateLimiter throttles requests with Redis-backed token buckets, so limits
+// hold across every API instance sharing the same Redis.
+type RateLimiter struct {
+	redis *redis.Client
+	now   func() time.Time
+}
+
+// NewRateLimiter creates a new rate limiter instance
+func NewRateLimiter(rdb *redis.Client) *RateLimiter {
+	return &RateLimiter{
+		redis: rdb,
+		now:   time.Now,
+	}
+}
+
+// Limit returns middleware allowing bursts of up to requests state-changing
+// requests per client, refilled evenly over window. Clients are identified by
+// user ID when authenticated and by IP address otherwise; each group has its
+// own buckets. GET, HEAD and OPTIONS requests are never throttled.
+// A non-positive requests or window disables the limit.
+func (rl *RateLimiter) Limit(group string, requests int, window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if requests <= 0 || window <= 0 {
+			return next
+		}
+		refillPerMs := float64(requests) / float64(window.Milliseconds())
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := fmt.Sprintf("ratelimit:%s:%s", group, rateLimitSubject(r))
+			res, err := tokenBucketScript.Run(context.Background(), rl.redis, []string{key},
+				requests,
+				strconv.FormatFloat(refillPerMs, 'f', -1, 64),
+				rl.now().UnixMilli(),
+				window.Milliseconds(),
+			).Int64Slice()
+			if err != nil || len(res) != 2 {
+				// Fail open: an unavailable Redis should not take the API down
+				slog.Error("rate limit check failed", "group", group, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if res[0] != 1 {
+				retryAfter := int(math.Ceil(float64(res[1]) / 1000))
+				if retryAfter < 1 {
+					retryAfter = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				utils.Error(w, http.StatusTooManyRequests, "Too many requests, please try again later", "RATE_LIMITED")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitSubject identifies the client a request is counted against.
+func rateLimitSubject(r *http.Request) string {
+	if userID := GetUserID(r.Context()); userID != 0 {
+		return fmt.Sprintf("user:%d", userID)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupTestRateLimiter creates a rate limiter backed by miniredis with a controllable clock
+func setupTestRateLimiter(t *testing.T) (*RateLimiter, *time.Time) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{
+		Addr: mr.Addr(),
+	})
+
+	now := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(rdb)
+	limiter.now = func() time.Time { return now }
+	return limiter, &now
+}
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func sendRateLimited(handler http.Handler, method, remoteAddr string, userID uint) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/v1/sales/checkout", nil)
+	req.RemoteAddr = remoteAddr
+	if userID != 0 {
+		req = req.WithContext(context.WithValue(req.Context(), UserIDKey, userID))
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestRateLimit_BurstBeyondLimit_Returns429WithRetryAfter(t *testing.T) {
+	limiter, _ := setupTestRateLimiter(t)
+	handler := limiter.Limit("sales", 3, time.Minute)(okHandler())
+
+	for i := 0; i < 3; i++ {
+		rr := sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 0)
+		assert.Equal(t, http.StatusOK, rr.Code, "request %d should be allowed", i+1)
+	}
+
+	rr := sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 0)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "20", rr.Header().Get("Retry-After"))
+	assert.Contains(t, rr.Body.String(), "RATE_LIMITED")
+}
+
+func TestRateLimit_WindowReplenishesTokens(t *testing.T) {
+	limiter, now := setupTestRateLimiter(t)
+	handler := limiter.Limit("sales", 2, time.Minute)(okHandler())
+
+	sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 0)
+	sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 0)
+	rr := sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 0)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+	// Half the window refills one token
+	*now = now.Add(30 * time.Second)
+	rr = sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 0)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	rr = sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 0)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+	// A full window refills the bucket to capacity
+	*now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		rr = sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 0)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
+}
+
+func TestRateLimit_SeparateBucketsPerUserAndIP(t *testing.T) {
+	limiter, _ := setupTestRateLimiter(t)
+	handler := limiter.Limit("sales", 1, time.Minute)(okHandler())
+
+	assert.Equal(t, http.StatusOK, sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 1).Code)
+	assert.Equal(t, http.StatusTooManyRequests, sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 1).Code)
+
+	// Another user on the same IP and an anonymous client have their own buckets
+	assert.Equal(t, http.StatusOK, sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 2).Code)
+	assert.Equal(t, http.StatusOK, sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 0).Code)
+	assert.Equal(t, http.StatusOK, sendRateLimited(handler, http.MethodPost, "10.0.0.2:5000", 0).Code)
+}
+
+func TestRateLimit_SeparateBucketsPerGroup(t *testing.T) {
+	limiter, _ := setupTestRateLimiter(t)
+	sales := limiter.Limit("sales", 1, time.Minute)(okHandler())
+	products := limiter.Limit("products", 1, time.Minute)(okHandler())
+
+	assert.Equal(t, http.StatusOK, sendRateLimited(sales, http.MethodPost, "10.0.0.1:5000", 1).Code)
+	assert.Equal(t, http.StatusOK, sendRateLimited(products, http.MethodPost, "10.0.0.1:5000", 1).Code)
+}
+
+func TestRateLimit_ReadRequests_NotThrottled(t *testing.T) {
+	limiter, _ := setupTestRateLimiter(t)
+	handler := limiter.Limit("sales", 1, time.Minute)(okHandler())
+
+	for i := 0; i < 5; i++ {
+		rr := sendRateLimited(handler, http.MethodGet, "10.0.0.1:5000", 0)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	}
+}
+
+func TestRateLimit_RedisUnavailable_FailsOpen(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{
+		Addr:       mr.Addr(),
+		MaxRetries: -1,
+	})
+	limiter := NewRateLimiter(rdb)
+	handler := limiter.Limit("sales", 1, time.Minute)(okHandler())
+	mr.Close()
+
+	rr := sendRateLimited(handler, http.MethodPost, "10.0.0.1:5000", 0)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
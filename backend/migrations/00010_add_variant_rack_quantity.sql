@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE variant_racks ADD COLUMN quantity INTEGER NOT NULL DEFAULT 0 CHECK (quantity >= 0);
+
+-- +goose Down
+ALTER TABLE variant_racks DROP COLUMN IF EXISTS quantity;
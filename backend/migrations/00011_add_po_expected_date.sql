@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE purchase_orders ADD COLUMN expected_date DATE;
+
+-- +goose Down
+ALTER TABLE purchase_orders DROP COLUMN IF EXISTS expected_date;
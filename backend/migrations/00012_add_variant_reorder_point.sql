@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE product_variants ADD COLUMN reorder_point INTEGER NOT NULL DEFAULT 0 CHECK (reorder_point >= 0);
+
+-- +goose Down
+ALTER TABLE product_variants DROP COLUMN IF EXISTS reorder_point;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE stock_movements ADD COLUMN created_by BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_stock_movements_created_at ON stock_movements(created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_stock_movements_created_at;
+ALTER TABLE stock_movements DROP COLUMN IF EXISTS created_by;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE purchase_orders
+    ADD COLUMN created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN approved_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN approved_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE purchase_orders
+    DROP COLUMN IF EXISTS approved_at,
+    DROP COLUMN IF EXISTS approved_by,
+    DROP COLUMN IF EXISTS created_by;
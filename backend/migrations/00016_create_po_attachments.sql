@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE po_attachments (
+    id                 BIGSERIAL PRIMARY KEY,
+    purchase_order_id  BIGINT NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    file_url           TEXT NOT NULL,
+    filename           VARCHAR(255) NOT NULL,
+    content_type       VARCHAR(100) NOT NULL,
+    size_bytes         BIGINT NOT NULL DEFAULT 0,
+    uploaded_by        BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_po_attachments_purchase_order_id ON po_attachments(purchase_order_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS po_attachments;
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE password_histories (
+    id             BIGSERIAL PRIMARY KEY,
+    user_id        BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash  TEXT NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_password_histories_user_id ON password_histories(user_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS password_histories;
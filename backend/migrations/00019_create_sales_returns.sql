@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TABLE sales_returns (
+    id              BIGSERIAL PRIMARY KEY,
+    return_number   VARCHAR(30) NOT NULL UNIQUE,
+    transaction_id  BIGINT NOT NULL REFERENCES sales_transactions(id),
+    date            TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    refund_method   VARCHAR(20) NOT NULL,
+    refund_amount   DECIMAL(15,2) NOT NULL,
+    total_items     INTEGER NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_sales_returns_transaction_id ON sales_returns(transaction_id);
+
+CREATE TABLE sales_return_items (
+    id                   BIGSERIAL PRIMARY KEY,
+    return_id            BIGINT NOT NULL REFERENCES sales_returns(id) ON DELETE CASCADE,
+    transaction_item_id  BIGINT NOT NULL REFERENCES sales_transaction_items(id),
+    variant_id           UUID NOT NULL REFERENCES product_variants(id),
+    product_name         VARCHAR(255) NOT NULL,
+    variant_label        VARCHAR(255) NOT NULL,
+    unit_name            VARCHAR(100) NOT NULL,
+    quantity             INTEGER NOT NULL CHECK (quantity > 0),
+    base_qty             INTEGER NOT NULL,
+    unit_price           DECIMAL(15,2) NOT NULL,
+    refund_amount        DECIMAL(15,2) NOT NULL
+);
+
+CREATE INDEX idx_sales_return_items_return_id ON sales_return_items(return_id);
+CREATE INDEX idx_sales_return_items_transaction_item_id ON sales_return_items(transaction_item_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS sales_return_items;
+DROP TABLE IF EXISTS sales_returns;
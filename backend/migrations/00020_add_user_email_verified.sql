@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT false;
+-- Accounts that existed before verification was introduced are grandfathered in
+UPDATE users SET email_verified = true;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified;
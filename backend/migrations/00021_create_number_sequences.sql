@@ -0,0 +1,12 @@
+-- +goose Up
+-- Per-year counters for document numbers reserved inside the transaction that
+-- inserts the document, so a rollback releases the number.
+CREATE TABLE number_sequences (
+    name       VARCHAR(50) NOT NULL,
+    year       INT NOT NULL,
+    last_value INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (name, year)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS number_sequences;
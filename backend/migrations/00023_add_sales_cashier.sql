@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE sales_transactions ADD COLUMN cashier_id BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_sales_transactions_cashier ON sales_transactions(cashier_id, date);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_sales_transactions_cashier;
+ALTER TABLE sales_transactions DROP COLUMN IF EXISTS cashier_id;
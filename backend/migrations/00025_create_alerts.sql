@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE alerts (
+    id               BIGSERIAL PRIMARY KEY,
+    type             VARCHAR(50) NOT NULL,
+    role             VARCHAR(100) NOT NULL,
+    title            VARCHAR(255) NOT NULL,
+    message          TEXT NOT NULL,
+    dedup_key        VARCHAR(255) NOT NULL,
+    acknowledged_at  TIMESTAMPTZ,
+    acknowledged_by  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A condition keeps at most one open alert; once acknowledged it may alert again
+CREATE UNIQUE INDEX idx_alerts_open_dedup_key ON alerts(dedup_key) WHERE acknowledged_at IS NULL;
+CREATE INDEX idx_alerts_role_open ON alerts(role) WHERE acknowledged_at IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS alerts;
@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ;
+CREATE INDEX idx_users_deleted_at ON users(deleted_at);
+
+-- Emails only need to be unique among live users so a deleted user's email can be reused
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX idx_users_email_live ON users(email) WHERE deleted_at IS NULL;
+
+-- +goose Down
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_users_email_live;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+DROP INDEX IF EXISTS idx_users_deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE parked_carts (
+    id             BIGSERIAL PRIMARY KEY,
+    label          VARCHAR(100) NOT NULL DEFAULT '',
+    customer_name  VARCHAR(255) NOT NULL DEFAULT '',
+    cashier_id     BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    total_items    INTEGER NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_parked_carts_created_at ON parked_carts(created_at);
+
+CREATE TABLE parked_cart_items (
+    id             BIGSERIAL PRIMARY KEY,
+    cart_id        BIGINT NOT NULL REFERENCES parked_carts(id) ON DELETE CASCADE,
+    product_id     BIGINT NOT NULL,
+    variant_id     UUID NOT NULL,
+    unit_id        BIGINT NOT NULL,
+    quantity       INTEGER NOT NULL CHECK (quantity > 0),
+    product_name   VARCHAR(255) NOT NULL,
+    variant_label  VARCHAR(255) NOT NULL,
+    unit_name      VARCHAR(100) NOT NULL
+);
+
+CREATE INDEX idx_parked_cart_items_cart_id ON parked_cart_items(cart_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS parked_cart_items;
+DROP TABLE IF EXISTS parked_carts;
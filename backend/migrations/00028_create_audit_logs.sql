@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE audit_logs (
+    id          BIGSERIAL PRIMARY KEY,
+    event       VARCHAR(50) NOT NULL,
+    user_id     BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    method      VARCHAR(10) NOT NULL DEFAULT '',
+    path        VARCHAR(500) NOT NULL DEFAULT '',
+    route       VARCHAR(255) NOT NULL DEFAULT '',
+    permission  VARCHAR(255) NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_logs_event_created_at ON audit_logs(event, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS audit_logs;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Snapshots copy product details so they stay readable after a variant is removed
+CREATE TABLE stock_snapshots (
+    id             BIGSERIAL PRIMARY KEY,
+    snapshot_date  DATE NOT NULL,
+    variant_id     UUID NOT NULL,
+    product_id     BIGINT NOT NULL,
+    product_name   VARCHAR(255) NOT NULL,
+    variant_label  VARCHAR(255) NOT NULL,
+    sku            VARCHAR(100) NOT NULL DEFAULT '',
+    quantity       INTEGER NOT NULL,
+    cost_price     DECIMAL(15,2),
+    valuation      DECIMAL(18,2) NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (snapshot_date, variant_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS stock_snapshots;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE categories ADD COLUMN parent_id BIGINT REFERENCES categories(id) ON DELETE RESTRICT;
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_categories_parent_id;
+ALTER TABLE categories DROP COLUMN IF EXISTS parent_id;
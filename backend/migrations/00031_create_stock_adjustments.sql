@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE stock_adjustments (
+    id              BIGSERIAL PRIMARY KEY,
+    variant_id      UUID NOT NULL REFERENCES product_variants(id),
+    previous_stock  INTEGER NOT NULL,
+    new_stock       INTEGER NOT NULL CHECK (new_stock >= 0),
+    quantity        INTEGER NOT NULL,
+    reason          TEXT NOT NULL,
+    created_by      BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_stock_adjustments_variant_id ON stock_adjustments(variant_id);
+CREATE INDEX idx_stock_adjustments_created_at ON stock_adjustments(created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS stock_adjustments;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE sales_transaction_items ADD COLUMN discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE sales_transaction_items ADD COLUMN discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE sales_transaction_items DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE sales_transaction_items DROP COLUMN IF EXISTS discount_percent;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE sales_transactions ADD COLUMN customer_name VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE sales_transactions DROP COLUMN IF EXISTS customer_name;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE sales_transaction_items ADD COLUMN unit_cost DECIMAL(15,2);
+
+-- +goose Down
+ALTER TABLE sales_transaction_items DROP COLUMN IF EXISTS unit_cost;
@@ -0,0 +1,26 @@
+-- +goose Up
+ALTER TABLE variant_pricing_tiers ADD COLUMN selling_price DECIMAL(15,2);
+
+-- Price the tiers of markup-priced variants already received from the
+-- received price of their latest receipt.
+UPDATE variant_pricing_tiers vpt
+SET selling_price = ROUND(CASE p.markup_type
+        WHEN 'percentage' THEN lc.unit_cost * (1 + vpt.value / 100)
+        ELSE lc.unit_cost + vpt.value
+    END, 2)
+FROM (
+    SELECT DISTINCT ON (poi.variant_id) poi.variant_id, poi.received_price / pu.to_base_unit AS unit_cost
+    FROM purchase_order_items poi
+    JOIN purchase_orders po ON po.id = poi.purchase_order_id
+    JOIN product_units pu ON pu.id = poi.unit_id
+    WHERE poi.received_qty > 0 AND poi.received_price IS NOT NULL AND pu.to_base_unit > 0
+    ORDER BY poi.variant_id, po.received_date DESC NULLS LAST, po.id DESC
+) lc, product_variants pv, products p
+WHERE lc.variant_id = vpt.variant_id
+  AND pv.id = vpt.variant_id
+  AND p.id = pv.product_id
+  AND p.price_setting = 'markup'
+  AND p.markup_type IN ('percentage', 'fixed_amount');
+
+-- +goose Down
+ALTER TABLE variant_pricing_tiers DROP COLUMN IF EXISTS selling_price;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE purchase_orders ADD COLUMN paid_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE purchase_orders DROP COLUMN IF EXISTS paid_at;
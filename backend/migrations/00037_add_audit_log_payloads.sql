@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE audit_logs
+    ADD COLUMN entity    VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN entity_id VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN before    JSONB,
+    ADD COLUMN after     JSONB;
+
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+CREATE INDEX idx_audit_logs_user_id ON audit_logs(user_id);
+CREATE INDEX idx_audit_logs_entity ON audit_logs(entity, entity_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_audit_logs_entity;
+DROP INDEX IF EXISTS idx_audit_logs_user_id;
+DROP INDEX IF EXISTS idx_audit_logs_created_at;
+
+ALTER TABLE audit_logs
+    DROP COLUMN IF EXISTS after,
+    DROP COLUMN IF EXISTS before,
+    DROP COLUMN IF EXISTS entity_id,
+    DROP COLUMN IF EXISTS entity;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE purchase_orders
+    ADD COLUMN tax_percent     DECIMAL(5,2) NOT NULL DEFAULT 0,
+    ADD COLUMN discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    ADD COLUMN grand_total     DECIMAL(15,2);
+
+UPDATE purchase_orders SET grand_total = subtotal WHERE subtotal IS NOT NULL;
+
+-- +goose Down
+ALTER TABLE purchase_orders
+    DROP COLUMN IF EXISTS grand_total,
+    DROP COLUMN IF EXISTS discount_amount,
+    DROP COLUMN IF EXISTS tax_percent;
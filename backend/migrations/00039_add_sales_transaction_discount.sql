@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE sales_transactions ADD COLUMN discount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE sales_transactions DROP COLUMN IF EXISTS discount;
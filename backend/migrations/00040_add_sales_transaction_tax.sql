@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE sales_transactions
+    ADD COLUMN tax_percent   DECIMAL(5,2) NOT NULL DEFAULT 0,
+    ADD COLUMN tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN tax_amount    DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE sales_transactions
+    DROP COLUMN IF EXISTS tax_amount,
+    DROP COLUMN IF EXISTS tax_inclusive,
+    DROP COLUMN IF EXISTS tax_percent;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- An alert now blocks its condition from alerting again until a check finds
+-- the condition cleared, so acknowledging it keeps it quiet
+ALTER TABLE alerts ADD COLUMN cleared_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_alerts_open_dedup_key;
+CREATE UNIQUE INDEX idx_alerts_active_dedup_key ON alerts(dedup_key) WHERE cleared_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_alerts_active_dedup_key;
+CREATE UNIQUE INDEX idx_alerts_open_dedup_key ON alerts(dedup_key) WHERE acknowledged_at IS NULL;
+ALTER TABLE alerts DROP COLUMN IF EXISTS cleared_at;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE parked_carts ADD COLUMN discount DECIMAL(15,2) NOT NULL DEFAULT 0;
+ALTER TABLE parked_cart_items ADD COLUMN discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE parked_cart_items ADD COLUMN discount_amount DECIMAL(15,2) NOT NULL DEFAULT 0;
+
+-- +goose Down
+ALTER TABLE parked_cart_items DROP COLUMN IF EXISTS discount_amount;
+ALTER TABLE parked_cart_items DROP COLUMN IF EXISTS discount_percent;
+ALTER TABLE parked_carts DROP COLUMN IF EXISTS discount;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- The storage key lets an attachment's file be removed with its record.
+-- Attachments uploaded before this column existed keep an empty key.
+ALTER TABLE po_attachments ADD COLUMN object_key TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE po_attachments DROP COLUMN IF EXISTS object_key;
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Alert is an actionable notice raised by a background check and addressed to
+// every user holding Role. It stays open until one of them acknowledges it,
+// and the condition does not alert again until a check finds it cleared.
+type Alert struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	Type           string     `json:"type"`
+	Role           string     `json:"role"`
+	Title          string     `json:"title"`
+	Message        string     `json:"message"`
+	DedupKey       string     `json:"-" gorm:"column:dedup_key"`
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty" gorm:"column:acknowledged_at"`
+	AcknowledgedBy *uint      `json:"acknowledgedBy,omitempty" gorm:"column:acknowledged_by"`
+	ClearedAt      *time.Time `json:"-" gorm:"column:cleared_at"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Audit log events
+const (
+	AuditEventPermissionDenied = "permission_denied"
+	AuditEventUserUpdated      = "user_updated"
+	AuditEventRoleUpdated      = "role_updated"
+)
+
+// AuditLog records a security-relevant event. Route is the matched route
+// pattern, Path the requested URL path, and Permission the required
+// permission as module:feature:action. Events that change a record name it
+// with Entity and EntityID and keep its JSON state in Before and After.
+type AuditLog struct {
+	ID         uint        `json:"id" gorm:"primaryKey"`
+	Event      string      `json:"event"`
+	UserID     *uint       `json:"userId" gorm:"column:user_id"`
+	Method     string      `json:"method"`
+	Path       string      `json:"path"`
+	Route      string      `json:"route"`
+	Permission string      `json:"permission"`
+	Entity     string      `json:"entity"`
+	EntityID   string      `json:"entityId" gorm:"column:entity_id"`
+	Before     JSONPayload `json:"before" gorm:"type:jsonb"`
+	After      JSONPayload `json:"after" gorm:"type:jsonb"`
+	CreatedAt  time.Time   `json:"createdAt"`
+}
+
+// JSONPayload is a raw JSON document stored in a JSONB column. An empty
+// payload is stored as NULL and rendered as null.
+type JSONPayload []byte
+
+// MarshalJSON returns the payload as is, or null when it is empty.
+func (p JSONPayload) MarshalJSON() ([]byte, error) {
+	if len(p) == 0 {
+		return []byte("null"), nil
+	}
+	return p, nil
+}
+
+// UnmarshalJSON keeps a copy of the raw JSON document.
+func (p *JSONPayload) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*p = nil
+		return nil
+	}
+	*p = append((*p)[:0], data...)
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (p JSONPayload) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	if !json.Valid(p) {
+		return nil, fmt.Errorf("invalid JSON payload")
+	}
+	return string(p), nil
+}
+
+// Scan implements sql.Scanner.
+func (p *JSONPayload) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+	case []byte:
+		*p = append((*p)[:0], v...)
+	case string:
+		*p = JSONPayload(v)
+	default:
+		return fmt.Errorf("cannot scan %T into JSONPayload", value)
+	}
+	return nil
+}
@@ -3,9 +3,11 @@ package models
 import "time"
 
 type Category struct {
-	ID          uint      `json:"id" gorm:"primaryKey"`
-	Name        string    `json:"name"`
-	Description string    `json:"description,omitempty"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	ParentID    *uint      `json:"parentId"`
+	Children    []Category `json:"children,omitempty" gorm:"foreignKey:ParentID"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
 }
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// ParkedCart is a cart set aside at a terminal to be resumed later. Items
+// keep product, variant and unit names as they were when the cart was parked.
+// Discount is the sale discount and items keep their line discounts.
+type ParkedCart struct {
+	ID           uint             `json:"id" gorm:"primaryKey"`
+	Label        string           `json:"label"`
+	CustomerName string           `json:"customerName" gorm:"column:customer_name"`
+	CashierID    *uint            `json:"cashierId,omitempty" gorm:"column:cashier_id"`
+	Discount     float64          `json:"discount" gorm:"column:discount;default:0"`
+	TotalItems   int              `json:"totalItems" gorm:"column:total_items"`
+	Items        []ParkedCartItem `json:"items,omitempty" gorm:"foreignKey:CartID"`
+	CreatedAt    time.Time        `json:"createdAt"`
+	UpdatedAt    time.Time        `json:"updatedAt"`
+}
+
+type ParkedCartItem struct {
+	ID              uint    `json:"id" gorm:"primaryKey"`
+	CartID          uint    `json:"cartId" gorm:"column:cart_id"`
+	ProductID       uint    `json:"productId" gorm:"column:product_id"`
+	VariantID       string  `json:"variantId" gorm:"column:variant_id;type:uuid"`
+	UnitID          uint    `json:"unitId" gorm:"column:unit_id"`
+	Quantity        int     `json:"quantity"`
+	ProductName     string  `json:"productName" gorm:"column:product_name"`
+	VariantLabel    string  `json:"variantLabel" gorm:"column:variant_label"`
+	UnitName        string  `json:"unitName" gorm:"column:unit_name"`
+	DiscountPercent float64 `json:"discountPercent" gorm:"column:discount_percent;default:0"`
+	DiscountAmount  float64 `json:"discountAmount" gorm:"column:discount_amount;default:0"`
+}
@@ -16,6 +16,7 @@ type Product struct {
 	Suppliers    []Supplier       `json:"suppliers,omitempty" gorm:"many2many:product_suppliers;"`
 	Units        []ProductUnit    `json:"units,omitempty" gorm:"foreignKey:ProductID"`
 	Variants     []ProductVariant `json:"variants,omitempty" gorm:"foreignKey:ProductID"`
+	Warnings     []string         `json:"warnings,omitempty" gorm:"-"`
 	CreatedAt    time.Time        `json:"createdAt"`
 	UpdatedAt    time.Time        `json:"updatedAt"`
 }
@@ -43,6 +44,8 @@ type ProductVariant struct {
 	SKU          string               `json:"sku,omitempty"`
 	Barcode      string               `json:"barcode,omitempty"`
 	CurrentStock int                  `json:"currentStock" gorm:"column:current_stock;default:0"`
+	ReorderPoint int                  `json:"reorderPoint" gorm:"column:reorder_point;default:0"`
+	CostPrice    *float64             `json:"costPrice,omitempty" gorm:"column:cost_price"`
 	Attributes   []VariantAttribute   `json:"attributes" gorm:"foreignKey:VariantID"`
 	Images       []VariantImage       `json:"images" gorm:"foreignKey:VariantID"`
 	PricingTiers []VariantPricingTier `json:"pricingTiers" gorm:"foreignKey:VariantID"`
@@ -65,9 +68,14 @@ type VariantImage struct {
 	SortOrder int    `json:"sortOrder" gorm:"column:sort_order;default:0"`
 }
 
+// VariantPricingTier is the price of a variant from MinQty base units up.
+// Value is the selling price for fixed-price products and the markup for
+// markup-priced ones; SellingPrice then holds the price derived from the last
+// received cost, nil until the variant is first received.
 type VariantPricingTier struct {
-	ID        uint    `json:"id" gorm:"primaryKey"`
-	VariantID string  `json:"variantId" gorm:"column:variant_id;type:uuid"`
-	MinQty    int     `json:"minQty" gorm:"column:min_qty"`
-	Value     float64 `json:"value"`
+	ID           uint     `json:"id" gorm:"primaryKey"`
+	VariantID    string   `json:"variantId" gorm:"column:variant_id;type:uuid"`
+	MinQty       int      `json:"minQty" gorm:"column:min_qty"`
+	Value        float64  `json:"value"`
+	SellingPrice *float64 `json:"sellingPrice,omitempty" gorm:"column:selling_price"`
 }
@@ -8,6 +8,7 @@ type PurchaseOrder struct {
 	SupplierID            uint                `json:"supplierId" gorm:"column:supplier_id"`
 	Supplier              *Supplier           `json:"supplier,omitempty" gorm:"foreignKey:SupplierID"`
 	Date                  string              `json:"date" gorm:"type:date"`
+	ExpectedDate          *string             `json:"expectedDate,omitempty" gorm:"column:expected_date;type:date"`
 	Status                string              `json:"status" gorm:"default:draft"`
 	Notes                 string              `json:"notes,omitempty"`
 	ReceivedDate          *time.Time          `json:"receivedDate,omitempty" gorm:"column:received_date"`
@@ -15,7 +16,18 @@ type PurchaseOrder struct {
 	SupplierBankAccountID *string             `json:"supplierBankAccountId,omitempty" gorm:"column:supplier_bank_account_id;type:uuid"`
 	Subtotal              *float64            `json:"subtotal,omitempty"`
 	TotalItems            *int                `json:"totalItems,omitempty" gorm:"column:total_items"`
+	TaxPercent            float64             `json:"taxPercent" gorm:"column:tax_percent;default:0"`
+	DiscountAmount        float64             `json:"discountAmount" gorm:"column:discount_amount;default:0"`
+	GrandTotal            *float64            `json:"grandTotal,omitempty" gorm:"column:grand_total"`
 	Items                 []PurchaseOrderItem `json:"items,omitempty" gorm:"foreignKey:PurchaseOrderID"`
+	CreatedBy             *uint               `json:"createdBy,omitempty" gorm:"column:created_by"`
+	ApprovedBy            *uint               `json:"approvedBy,omitempty" gorm:"column:approved_by"`
+	ApprovedAt            *time.Time          `json:"approvedAt,omitempty" gorm:"column:approved_at"`
+	PaidAt                *time.Time          `json:"paidAt,omitempty" gorm:"column:paid_at"`
+	OrderedTotal          *float64            `json:"orderedTotal,omitempty" gorm:"-"`
+	ReceivedTotal         *float64            `json:"receivedTotal,omitempty" gorm:"-"`
+	Outstanding           *float64            `json:"outstanding,omitempty" gorm:"-"`
+	Overdue               bool                `json:"overdue" gorm:"-"`
 	CreatedAt             time.Time           `json:"createdAt"`
 	UpdatedAt             time.Time           `json:"updatedAt"`
 }
@@ -37,3 +49,15 @@ type PurchaseOrderItem struct {
 	ReceivedPrice   *float64 `json:"receivedPrice,omitempty" gorm:"column:received_price"`
 	IsVerified      bool     `json:"isVerified" gorm:"column:is_verified;default:false"`
 }
+
+type POAttachment struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	PurchaseOrderID uint      `json:"purchaseOrderId" gorm:"column:purchase_order_id"`
+	FileURL         string    `json:"fileUrl" gorm:"column:file_url"`
+	ObjectKey       string    `json:"-" gorm:"column:object_key"`
+	Filename        string    `json:"filename"`
+	ContentType     string    `json:"contentType" gorm:"column:content_type"`
+	SizeBytes       int64     `json:"sizeBytes" gorm:"column:size_bytes"`
+	UploadedBy      *uint     `json:"uploadedBy,omitempty" gorm:"column:uploaded_by"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
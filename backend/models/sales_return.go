@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type SalesReturn struct {
+	ID            uint              `json:"id" gorm:"primaryKey"`
+	ReturnNumber  string            `json:"returnNumber" gorm:"column:return_number;uniqueIndex"`
+	TransactionID uint              `json:"transactionId" gorm:"column:transaction_id"`
+	Date          time.Time         `json:"date"`
+	RefundMethod  string            `json:"refundMethod" gorm:"column:refund_method"`
+	RefundAmount  float64           `json:"refundAmount" gorm:"column:refund_amount"`
+	TotalItems    int               `json:"totalItems" gorm:"column:total_items"`
+	Items         []SalesReturnItem `json:"items,omitempty" gorm:"foreignKey:ReturnID"`
+	CreatedAt     time.Time         `json:"createdAt"`
+}
+
+type SalesReturnItem struct {
+	ID                uint    `json:"id" gorm:"primaryKey"`
+	ReturnID          uint    `json:"returnId" gorm:"column:return_id"`
+	TransactionItemID uint    `json:"transactionItemId" gorm:"column:transaction_item_id"`
+	VariantID         string  `json:"variantId" gorm:"column:variant_id;type:uuid"`
+	ProductName       string  `json:"productName" gorm:"column:product_name"`
+	VariantLabel      string  `json:"variantLabel" gorm:"column:variant_label"`
+	UnitName          string  `json:"unitName" gorm:"column:unit_name"`
+	Quantity          int     `json:"quantity"`
+	BaseQty           int     `json:"baseQty" gorm:"column:base_qty"`
+	UnitPrice         float64 `json:"unitPrice" gorm:"column:unit_price"`
+	RefundAmount      float64 `json:"refundAmount" gorm:"column:refund_amount"`
+}
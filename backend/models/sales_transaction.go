@@ -7,25 +7,38 @@ type SalesTransaction struct {
 	TransactionNumber string                   `json:"transactionNumber" gorm:"column:transaction_number;uniqueIndex"`
 	Date              time.Time                `json:"date"`
 	Subtotal          float64                  `json:"subtotal"`
+	Discount          float64                  `json:"discount" gorm:"column:discount;default:0"`
+	TaxPercent        float64                  `json:"taxPercent" gorm:"column:tax_percent;default:0"`
+	TaxInclusive      bool                     `json:"taxInclusive" gorm:"column:tax_inclusive;default:false"`
+	TaxAmount         float64                  `json:"taxAmount" gorm:"column:tax_amount;default:0"`
+	SurchargeAmount   float64                  `json:"surchargeAmount" gorm:"column:surcharge_amount;default:0"`
 	GrandTotal        float64                  `json:"grandTotal" gorm:"column:grand_total"`
 	TotalItems        int                      `json:"totalItems" gorm:"column:total_items"`
 	PaymentMethod     string                   `json:"paymentMethod" gorm:"column:payment_method"`
+	CashierID         *uint                    `json:"cashierId,omitempty" gorm:"column:cashier_id"`
+	CustomerName      string                   `json:"customerName,omitempty" gorm:"column:customer_name"`
 	Items             []SalesTransactionItem   `json:"items,omitempty" gorm:"foreignKey:TransactionID"`
 	CreatedAt         time.Time                `json:"createdAt"`
 }
 
 type SalesTransactionItem struct {
-	ID            uint    `json:"id" gorm:"primaryKey"`
-	TransactionID uint    `json:"transactionId" gorm:"column:transaction_id"`
-	ProductID     uint    `json:"productId" gorm:"column:product_id"`
-	VariantID     string  `json:"variantId" gorm:"column:variant_id;type:uuid"`
-	UnitID        uint    `json:"unitId" gorm:"column:unit_id"`
-	ProductName   string  `json:"productName" gorm:"column:product_name"`
-	VariantLabel  string  `json:"variantLabel" gorm:"column:variant_label"`
-	SKU           string  `json:"sku,omitempty"`
-	UnitName      string  `json:"unitName" gorm:"column:unit_name"`
-	Quantity      int     `json:"quantity"`
-	BaseQty       int     `json:"baseQty" gorm:"column:base_qty"`
-	UnitPrice     float64 `json:"unitPrice" gorm:"column:unit_price"`
-	TotalPrice    float64 `json:"totalPrice" gorm:"column:total_price"`
+	ID              uint    `json:"id" gorm:"primaryKey"`
+	TransactionID   uint    `json:"transactionId" gorm:"column:transaction_id"`
+	ProductID       uint    `json:"productId" gorm:"column:product_id"`
+	VariantID       string  `json:"variantId" gorm:"column:variant_id;type:uuid"`
+	UnitID          uint    `json:"unitId" gorm:"column:unit_id"`
+	ProductName     string  `json:"productName" gorm:"column:product_name"`
+	VariantLabel    string  `json:"variantLabel" gorm:"column:variant_label"`
+	SKU             string  `json:"sku,omitempty"`
+	UnitName        string  `json:"unitName" gorm:"column:unit_name"`
+	Quantity        int     `json:"quantity"`
+	BaseQty         int     `json:"baseQty" gorm:"column:base_qty"`
+	UnitPrice       float64 `json:"unitPrice" gorm:"column:unit_price"`
+	TotalPrice      float64 `json:"totalPrice" gorm:"column:total_price"`
+	DiscountPercent float64 `json:"discountPercent" gorm:"column:discount_percent;default:0"`
+	DiscountAmount  float64 `json:"discountAmount" gorm:"column:discount_amount;default:0"`
+
+	// UnitCost is the variant's cost price per base unit when the item was
+	// sold, nil when the variant had none. It is kept off receipts.
+	UnitCost *float64 `json:"-" gorm:"column:unit_cost"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// StockAdjustment is a manual correction of a variant's stock, such as after
+// a stock count or for damaged goods. Quantity is the signed change applied.
+type StockAdjustment struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	VariantID     string    `json:"variantId" gorm:"column:variant_id;type:uuid"`
+	PreviousStock int       `json:"previousStock" gorm:"column:previous_stock"`
+	NewStock      int       `json:"newStock" gorm:"column:new_stock"`
+	Quantity      int       `json:"quantity"`
+	Reason        string    `json:"reason"`
+	CreatedBy     *uint     `json:"createdBy,omitempty" gorm:"column:created_by"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
@@ -10,5 +10,6 @@ type StockMovement struct {
 	ReferenceType string    `json:"referenceType,omitempty" gorm:"column:reference_type"`
 	ReferenceID   *uint     `json:"referenceId,omitempty" gorm:"column:reference_id"`
 	Notes         string    `json:"notes,omitempty"`
+	CreatedBy     *uint     `json:"createdBy,omitempty" gorm:"column:created_by"`
 	CreatedAt     time.Time `json:"createdAt"`
 }
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// StockSnapshot is a variant's stock level frozen at the end of a day.
+// Valuation is quantity times cost price, zero when the cost is unknown.
+type StockSnapshot struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	SnapshotDate string    `json:"snapshotDate" gorm:"column:snapshot_date;type:date"`
+	VariantID    string    `json:"variantId" gorm:"column:variant_id;type:uuid"`
+	ProductID    uint      `json:"productId" gorm:"column:product_id"`
+	ProductName  string    `json:"productName" gorm:"column:product_name"`
+	VariantLabel string    `json:"variantLabel" gorm:"column:variant_label"`
+	SKU          string    `json:"sku" gorm:"column:sku"`
+	Quantity     int       `json:"quantity"`
+	CostPrice    *float64  `json:"costPrice" gorm:"column:cost_price"`
+	Valuation    float64   `json:"valuation"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
@@ -2,19 +2,37 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type User struct {
-	ID             uint      `json:"id" gorm:"primaryKey"`
-	Name           string    `json:"name" gorm:"not null"`
-	Email          string    `json:"email" gorm:"uniqueIndex;not null"`
-	Phone          string    `json:"phone,omitempty"`
-	Address        string    `json:"address,omitempty"`
-	PasswordHash   string    `json:"-" gorm:"column:password_hash;not null"`
-	ProfilePicture *string   `json:"profilePicture,omitempty" gorm:"column:profile_picture"`
-	Status         string    `json:"status" gorm:"default:active;not null"`
-	IsSuperAdmin   bool      `json:"isSuperAdmin" gorm:"column:is_super_admin;default:false"`
-	CreatedAt      time.Time `json:"createdAt"`
-	UpdatedAt      time.Time `json:"updatedAt"`
-	Roles          []Role    `json:"roles,omitempty" gorm:"many2many:user_roles;"`
+	ID               uint           `json:"id" gorm:"primaryKey"`
+	Name             string         `json:"name" gorm:"not null"`
+	Email            string         `json:"email" gorm:"not null"`
+	Phone            string         `json:"phone,omitempty"`
+	Address          string         `json:"address,omitempty"`
+	PasswordHash     string         `json:"-" gorm:"column:password_hash;not null"`
+	ProfilePicture   *string        `json:"profilePicture,omitempty" gorm:"column:profile_picture"`
+	Status           string         `json:"status" gorm:"default:active;not null"`
+	IsSuperAdmin     bool           `json:"isSuperAdmin" gorm:"column:is_super_admin;default:false"`
+	EmailVerified    bool           `json:"emailVerified" gorm:"column:email_verified;default:false"`
+	TwoFactorSecret  string         `json:"-" gorm:"column:two_factor_secret"`
+	TwoFactorEnabled bool           `json:"twoFactorEnabled" gorm:"column:two_factor_enabled;default:false"`
+	LastLoginAt      *time.Time     `json:"lastLoginAt,omitempty" gorm:"column:last_login_at"`
+	CreatedAt        time.Time      `json:"createdAt"`
+	UpdatedAt        time.Time      `json:"updatedAt"`
+	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
+	Roles            []Role         `json:"roles,omitempty" gorm:"many2many:user_roles;"`
+	// TempPassword is returned to the admin who created the user when email
+	// is disabled and the credentials cannot be sent.
+	TempPassword string `json:"tempPassword,omitempty" gorm:"-"`
+}
+
+// PasswordHistory keeps a password hash a user has replaced, to prevent reuse.
+type PasswordHistory struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	UserID       uint      `json:"userId" gorm:"column:user_id;not null"`
+	PasswordHash string    `json:"-" gorm:"column:password_hash;not null"`
+	CreatedAt    time.Time `json:"createdAt"`
 }
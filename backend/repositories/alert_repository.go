@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LowStockVariant is a variant at or below its reorder point.
+type LowStockVariant struct {
+	VariantID    string `json:"variantId"`
+	ProductName  string `json:"productName"`
+	SKU          string `json:"sku"`
+	CurrentStock int    `json:"currentStock"`
+	ReorderPoint int    `json:"reorderPoint"`
+}
+
+// OverduePurchaseOrder is a sent PO whose expected delivery date has passed.
+type OverduePurchaseOrder struct {
+	ID           uint   `json:"id"`
+	PONumber     string `json:"poNumber"`
+	SupplierName string `json:"supplierName"`
+	ExpectedDate string `json:"expectedDate"`
+}
+
+// RackAllocationDrift is a variant whose rack quantities add up to more than
+// its current stock.
+type RackAllocationDrift struct {
+	VariantID    string `json:"variantId"`
+	ProductName  string `json:"productName"`
+	SKU          string `json:"sku"`
+	CurrentStock int    `json:"currentStock"`
+	Allocated    int    `json:"allocated"`
+}
+
+// AlertRepository defines the interface for alert data operations.
+type AlertRepository interface {
+	CreateIfAbsent(alert *models.Alert) (bool, error)
+	ListOpen(roles []string, allRoles bool) ([]models.Alert, error)
+	FindByID(id uint) (*models.Alert, error)
+	Acknowledge(id, userID uint, at time.Time) (bool, error)
+	ClearResolved(alertType string, activeKeys []string, at time.Time) error
+	UserRoleNames(userID uint) ([]string, error)
+	ListLowStockVariants() ([]LowStockVariant, error)
+	ListOverduePurchaseOrders(today string) ([]OverduePurchaseOrder, error)
+	ListRackAllocationDrift() ([]RackAllocationDrift, error)
+}
+
+// AlertRepositoryImpl implements AlertRepository.
+type AlertRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAlertRepository creates a new alert repository instance.
+func NewAlertRepository(db *gorm.DB) *AlertRepositoryImpl {
+	return &AlertRepositoryImpl{db: db}
+}
+
+// CreateIfAbsent inserts the alert unless an uncleared alert with the same
+// dedup key exists, whether or not it was acknowledged. It reports whether a
+// row was inserted.
+func (r *AlertRepositoryImpl) CreateIfAbsent(alert *models.Alert) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(alert)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListOpen returns unacknowledged alerts addressed to any of the roles, or to
+// every role when allRoles is set, newest first.
+func (r *AlertRepositoryImpl) ListOpen(roles []string, allRoles bool) ([]models.Alert, error) {
+	alerts := make([]models.Alert, 0)
+	query := r.db.Where("acknowledged_at IS NULL")
+	if !allRoles {
+		if len(roles) == 0 {
+			return alerts, nil
+		}
+		query = query.Where("role IN ?", roles)
+	}
+	if err := query.Order("created_at DESC, id DESC").Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// FindByID loads a single alert.
+func (r *AlertRepositoryImpl) FindByID(id uint) (*models.Alert, error) {
+	var alert models.Alert
+	if err := r.db.First(&alert, id).Error; err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// Acknowledge marks an open alert as acknowledged. It reports false when the
+// alert was already acknowledged.
+func (r *AlertRepositoryImpl) Acknowledge(id, userID uint, at time.Time) (bool, error) {
+	result := r.db.Model(&models.Alert{}).
+		Where("id = ? AND acknowledged_at IS NULL", id).
+		Updates(map[string]interface{}{"acknowledged_at": at, "acknowledged_by": userID})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ClearResolved marks acknowledged alerts of the type whose dedup key is not
+// among activeKeys as cleared, so their condition may alert again when it
+// recurs. Open alerts stay until acknowledged.
+func (r *AlertRepositoryImpl) ClearResolved(alertType string, activeKeys []string, at time.Time) error {
+	query := r.db.Model(&models.Alert{}).
+		Where("type = ? AND cleared_at IS NULL AND acknowledged_at IS NOT NULL", alertType)
+	if len(activeKeys) > 0 {
+		query = query.Where("dedup_key NOT IN ?", activeKeys)
+	}
+	return query.Update("cleared_at", at).Error
+}
+
+// UserRoleNames returns the names of the roles assigned to a user.
+func (r *AlertRepositoryImpl) UserRoleNames(userID uint) ([]string, error) {
+	names := make([]string, 0)
+	err := r.db.Table("roles").
+		Joins("JOIN user_roles ur ON ur.role_id = roles.id").
+		Where("ur.user_id = ?", userID).
+		Pluck("roles.name", &names).Error
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// ListLowStockVariants returns variants of active products at or below a
+// positive reorder point.
+func (r *AlertRepositoryImpl) ListLowStockVariants() ([]LowStockVariant, error) {
+	rows := make([]LowStockVariant, 0)
+	err := r.db.Table("product_variants pv").
+		Select("pv.id AS variant_id, p.name AS product_name, pv.sku, pv.current_stock, pv.reorder_point").
+		Joins("JOIN products p ON p.id = pv.product_id").
+		Where("p.status = ?", "active").
+		Where("pv.reorder_point > 0 AND pv.current_stock <= pv.reorder_point").
+		Order("p.name ASC, pv.created_at ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListOverduePurchaseOrders returns sent or partially received POs expected
+// before today (YYYY-MM-DD).
+func (r *AlertRepositoryImpl) ListOverduePurchaseOrders(today string) ([]OverduePurchaseOrder, error) {
+	rows := make([]OverduePurchaseOrder, 0)
+	err := r.db.Table("purchase_orders po").
+		Select("po.id, po.po_number, s.name AS supplier_name, TO_CHAR(po.expected_date, 'YYYY-MM-DD') AS expected_date").
+		Joins("JOIN suppliers s ON s.id = po.supplier_id").
+		Where("po.status IN ?", []string{"sent", "partially_received"}).
+		Where("po.expected_date IS NOT NULL AND po.expected_date < ?", today).
+		Order("po.expected_date ASC, po.id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListRackAllocationDrift returns variants whose rack quantities exceed their
+// current stock.
+func (r *AlertRepositoryImpl) ListRackAllocationDrift() ([]RackAllocationDrift, error) {
+	rows := make([]RackAllocationDrift, 0)
+	err := r.db.Table("product_variants pv").
+		Select("pv.id AS variant_id, p.name AS product_name, pv.sku, pv.current_stock, SUM(vr.quantity) AS allocated").
+		Joins("JOIN products p ON p.id = pv.product_id").
+		Joins("JOIN variant_racks vr ON vr.variant_id = pv.id").
+		Group("pv.id, p.name, pv.sku, pv.current_stock").
+		Having("SUM(vr.quantity) > pv.current_stock").
+		Order("p.name ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
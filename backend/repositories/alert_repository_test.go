@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertCreateIfAbsent_Duplicate_IsSkippedUntilCleared(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewAlertRepository(db)
+	user := testutil.CreateTestUser(t, db)
+
+	newAlert := func() *models.Alert {
+		return &models.Alert{Type: "low_stock", Role: "Warehouse", Title: "Low stock", Message: "Low", DedupKey: "low_stock:v1"}
+	}
+
+	first := newAlert()
+	created, err := repo.CreateIfAbsent(first)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = repo.CreateIfAbsent(newAlert())
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	acknowledged, err := repo.Acknowledge(first.ID, user.ID, time.Now())
+	require.NoError(t, err)
+	assert.True(t, acknowledged)
+
+	// The acknowledged alert still holds off its condition
+	created, err = repo.CreateIfAbsent(newAlert())
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	// Still active: nothing is cleared
+	require.NoError(t, repo.ClearResolved("low_stock", []string{"low_stock:v1"}, time.Now()))
+	created, err = repo.CreateIfAbsent(newAlert())
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	require.NoError(t, repo.ClearResolved("low_stock", nil, time.Now()))
+	created, err = repo.CreateIfAbsent(newAlert())
+	require.NoError(t, err)
+	assert.True(t, created)
+}
+
+func TestAlertClearResolved_OpenAlert_IsKept(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewAlertRepository(db)
+
+	alert := &models.Alert{Type: "low_stock", Role: "Warehouse", Title: "Low stock", Message: "Low", DedupKey: "low_stock:v1"}
+	_, err := repo.CreateIfAbsent(alert)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.ClearResolved("low_stock", nil, time.Now()))
+
+	stored, err := repo.FindByID(alert.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.ClearedAt)
+}
+
+func TestAlertListOpen_FiltersByRole(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewAlertRepository(db)
+
+	for _, role := range []string{"Warehouse", "Manager"} {
+		_, err := repo.CreateIfAbsent(&models.Alert{Type: "low_stock", Role: role, Title: "t", Message: "m", DedupKey: "k:" + role})
+		require.NoError(t, err)
+	}
+
+	alerts, err := repo.ListOpen([]string{"Warehouse"}, false)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "Warehouse", alerts[0].Role)
+
+	all, err := repo.ListOpen(nil, true)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
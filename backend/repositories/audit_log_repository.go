@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter narrows an audit log search. Empty fields do not filter;
+// the created time range is [From, To).
+type AuditLogFilter struct {
+	// Search matches, case-insensitively, anywhere in the before or after
+	// payload, the entity ID, the path or the permission.
+	Search  string
+	ActorID *uint
+	Action  string
+	Entity  string
+	From    time.Time
+	To      time.Time
+}
+
+// likeEscaper escapes the LIKE wildcards in a search term so they match
+// literally. Backslash is the default LIKE escape character in Postgres.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// AuditLogListItem is an audit log entry with the acting user's name and email.
+type AuditLogListItem struct {
+	models.AuditLog
+	ActorName  string `json:"actorName"`
+	ActorEmail string `json:"actorEmail"`
+}
+
+// AuditLogRepository defines the interface for audit log data access
+type AuditLogRepository interface {
+	Create(entry *models.AuditLog) error
+	Search(page, pageSize int, filter AuditLogFilter) ([]AuditLogListItem, int64, error)
+}
+
+// AuditLogRepositoryImpl implements AuditLogRepository
+type AuditLogRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a new audit log repository instance
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepositoryImpl {
+	return &AuditLogRepositoryImpl{db: db}
+}
+
+// Create stores an audit log entry
+func (r *AuditLogRepositoryImpl) Create(entry *models.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// Search returns a page of audit log entries matching filter, newest first.
+func (r *AuditLogRepositoryImpl) Search(page, pageSize int, filter AuditLogFilter) ([]AuditLogListItem, int64, error) {
+	items := make([]AuditLogListItem, 0)
+	var total int64
+
+	query := r.db.Table("audit_logs AS al").
+		Joins("LEFT JOIN users u ON u.id = al.user_id")
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Search) + "%"
+		query = query.Where(`(COALESCE(al.before::text, '') ILIKE ? OR COALESCE(al.after::text, '') ILIKE ?
+			OR al.entity_id ILIKE ? OR al.path ILIKE ? OR al.permission ILIKE ?)`,
+			pattern, pattern, pattern, pattern, pattern)
+	}
+	if filter.ActorID != nil {
+		query = query.Where("al.user_id = ?", *filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("al.event = ?", filter.Action)
+	}
+	if filter.Entity != "" {
+		query = query.Where("al.entity = ?", filter.Entity)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("al.created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("al.created_at < ?", filter.To)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Select("al.*, COALESCE(u.name, '') AS actor_name, COALESCE(u.email, '') AS actor_email").
+		Order("al.created_at DESC, al.id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogSearch_MatchesAfterPayloadValue(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewAuditLogRepository(db)
+	user := testutil.CreateTestUser(t, db)
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	set := &models.AuditLog{
+		Event:     "user_updated",
+		UserID:    &user.ID,
+		Entity:    "user",
+		EntityID:  "42",
+		Before:    models.JSONPayload(`{"email": null}`),
+		After:     models.JSONPayload(`{"email": "Rina@Example.com"}`),
+		CreatedAt: at,
+	}
+	require.NoError(t, db.Create(set).Error)
+	other := &models.AuditLog{
+		Event:     "user_updated",
+		UserID:    &user.ID,
+		Entity:    "user",
+		EntityID:  "43",
+		After:     models.JSONPayload(`{"name": "Budi"}`),
+		CreatedAt: at,
+	}
+	require.NoError(t, db.Create(other).Error)
+
+	items, total, err := repo.Search(1, 10, AuditLogFilter{Search: "rina@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, items, 1)
+	assert.Equal(t, set.ID, items[0].ID)
+	assert.JSONEq(t, `{"email": "Rina@Example.com"}`, string(items[0].After))
+	assert.Equal(t, user.Name, items[0].ActorName)
+	assert.Equal(t, user.Email, items[0].ActorEmail)
+
+	items, total, err = repo.Search(1, 10, AuditLogFilter{From: at, To: at.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, items, 2)
+}
+
+func TestAuditLogSearch_WildcardsMatchLiterally(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewAuditLogRepository(db)
+	user := testutil.CreateTestUser(t, db)
+
+	discount := &models.AuditLog{
+		Event:    "role_updated",
+		UserID:   &user.ID,
+		Entity:   "role",
+		EntityID: "7",
+		After:    models.JSONPayload(`{"description": "Up to 10% off_peak"}`),
+	}
+	require.NoError(t, db.Create(discount).Error)
+	plain := &models.AuditLog{
+		Event:    "role_updated",
+		UserID:   &user.ID,
+		Entity:   "role",
+		EntityID: "8",
+		After:    models.JSONPayload(`{"description": "Up to 10 days off peak"}`),
+	}
+	require.NoError(t, db.Create(plain).Error)
+
+	items, total, err := repo.Search(1, 10, AuditLogFilter{Search: "10% off_peak"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, items, 1)
+	assert.Equal(t, discount.ID, items[0].ID)
+
+	_, total, err = repo.Search(1, 10, AuditLogFilter{Search: "%"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
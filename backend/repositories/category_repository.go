@@ -3,16 +3,19 @@ package repositories
 import (
 	"github.com/pointofsale/backend/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CategoryRepository defines the interface for category data operations
 type CategoryRepository interface {
 	Create(category *models.Category) error
 	List(params PaginationParams) ([]models.Category, int64, error)
+	ListAll() ([]models.Category, error)
 	GetByID(id uint) (*models.Category, error)
 	Update(category *models.Category) error
 	Delete(id uint) error
 	CountProductsByCategory(categoryID uint) (int64, error)
+	CountActiveProductsByCategory() (map[uint]int64, error)
 }
 
 // CategoryRepositoryImpl implements CategoryRepository interface
@@ -64,19 +67,31 @@ func (r *CategoryRepositoryImpl) List(params PaginationParams) ([]models.Categor
 	return categories, total, nil
 }
 
-// GetByID finds a category by ID
+// ListAll returns every category ordered by name, without pagination.
+func (r *CategoryRepositoryImpl) ListAll() ([]models.Category, error) {
+	var categories []models.Category
+	if err := r.db.Order("name ASC, id ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// GetByID finds a category by ID with its direct children
 func (r *CategoryRepositoryImpl) GetByID(id uint) (*models.Category, error) {
 	var category models.Category
-	err := r.db.First(&category, id).Error
+	err := r.db.Preload("Children", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name ASC, id ASC")
+	}).First(&category, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &category, nil
 }
 
-// Update saves changes to an existing category
+// Update saves changes to an existing category. Loaded children are not
+// written back.
 func (r *CategoryRepositoryImpl) Update(category *models.Category) error {
-	return r.db.Save(category).Error
+	return r.db.Omit(clause.Associations).Save(category).Error
 }
 
 // Delete removes a category from the database
@@ -98,3 +113,26 @@ func (r *CategoryRepositoryImpl) CountProductsByCategory(categoryID uint) (int64
 	}
 	return count, nil
 }
+
+// CountActiveProductsByCategory returns the number of active products in each category.
+// Categories without active products are omitted.
+func (r *CategoryRepositoryImpl) CountActiveProductsByCategory() (map[uint]int64, error) {
+	var rows []struct {
+		CategoryID uint
+		Count      int64
+	}
+	err := r.db.Model(&models.Product{}).
+		Select("category_id, COUNT(*) AS count").
+		Where("status = ?", "active").
+		Group("category_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
@@ -246,3 +246,92 @@ func TestCountProductsByCategory_NoProducts_ReturnsZero(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, int64(0), count)
 }
+
+func TestCountActiveProductsByCategory_CountsOnlyActiveProducts(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewCategoryRepository(db)
+
+	food := testutil.CreateTestCategory(t, db)
+	drinks := testutil.CreateTestCategory(t, db)
+	empty := testutil.CreateTestCategory(t, db)
+
+	testutil.CreateTestProduct(t, db, func(p *models.Product) { p.CategoryID = food.ID })
+	testutil.CreateTestProduct(t, db, func(p *models.Product) { p.CategoryID = food.ID })
+	testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.CategoryID = food.ID
+		p.Status = "inactive"
+	})
+	testutil.CreateTestProduct(t, db, func(p *models.Product) { p.CategoryID = drinks.ID })
+	testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.CategoryID = empty.ID
+		p.Status = "inactive"
+	})
+
+	counts, err := repo.CountActiveProductsByCategory()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), counts[food.ID])
+	assert.Equal(t, int64(1), counts[drinks.ID])
+	_, hasEmpty := counts[empty.ID]
+	assert.False(t, hasEmpty, "category with only inactive products should be omitted")
+}
+
+func TestGetCategory_WithChildren_PreloadsChildren(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewCategoryRepository(db)
+
+	parent := &models.Category{Name: "Clothing"}
+	require.NoError(t, db.Create(parent).Error)
+	require.NoError(t, db.Create(&models.Category{Name: "Women", ParentID: &parent.ID}).Error)
+	require.NoError(t, db.Create(&models.Category{Name: "Men", ParentID: &parent.ID}).Error)
+
+	found, err := repo.GetByID(parent.ID)
+	require.NoError(t, err)
+	require.Len(t, found.Children, 2)
+	assert.Equal(t, "Men", found.Children[0].Name)
+	assert.Equal(t, "Women", found.Children[1].Name)
+}
+
+func TestUpdateCategory_WithLoadedChildren_LeavesChildrenUntouched(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewCategoryRepository(db)
+
+	parent := &models.Category{Name: "Clothing"}
+	require.NoError(t, db.Create(parent).Error)
+	child := &models.Category{Name: "Men", ParentID: &parent.ID}
+	require.NoError(t, db.Create(child).Error)
+
+	loaded, err := repo.GetByID(parent.ID)
+	require.NoError(t, err)
+	loaded.Name = "Apparel"
+	loaded.Children[0].Name = "Changed"
+	require.NoError(t, repo.Update(loaded))
+
+	var reloaded models.Category
+	require.NoError(t, db.First(&reloaded, child.ID).Error)
+	assert.Equal(t, "Men", reloaded.Name)
+}
+
+func TestListAllCategories_ReturnsEveryCategoryByName(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewCategoryRepository(db)
+
+	parent := &models.Category{Name: "Clothing"}
+	require.NoError(t, db.Create(parent).Error)
+	require.NoError(t, db.Create(&models.Category{Name: "Accessories", ParentID: &parent.ID}).Error)
+	require.NoError(t, db.Create(&models.Category{Name: "Books"}).Error)
+
+	categories, err := repo.ListAll()
+	require.NoError(t, err)
+	require.Len(t, categories, 3)
+	assert.Equal(t, "Accessories", categories[0].Name)
+	assert.Equal(t, "Books", categories[1].Name)
+	assert.Equal(t, "Clothing", categories[2].Name)
+}
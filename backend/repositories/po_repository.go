@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"time"
+
 	"github.com/pointofsale/backend/models"
 	"gorm.io/gorm"
 )
@@ -9,12 +11,19 @@ import (
 type PORepository interface {
 	Create(po *models.PurchaseOrder) error
 	GetByID(id uint) (*models.PurchaseOrder, error)
-	List(params PaginationParams, status string, supplierID uint) ([]models.PurchaseOrder, int64, error)
+	List(params PaginationParams, filter POListFilter) ([]models.PurchaseOrder, int64, error)
 	StatusCounts() (map[string]int64, error)
 	Update(po *models.PurchaseOrder) error
 	Delete(id uint) error
 	ReplaceItems(poID uint, items []models.PurchaseOrderItem) error
 	GetProductsForPO(supplierID uint, search string) ([]models.Product, error)
+	GetLatestForSupplier(supplierID uint) (*models.PurchaseOrder, error)
+	ListReceived(from, to time.Time) ([]models.PurchaseOrder, error)
+	ListLowStockProducts() ([]models.Product, error)
+	CreateAttachment(attachment *models.POAttachment) error
+	ListAttachments(poID uint) ([]models.POAttachment, error)
+	GetAttachment(poID, attachmentID uint) (*models.POAttachment, error)
+	DeleteAttachment(id uint) error
 }
 
 // PORepositoryImpl implements PORepository.
@@ -45,8 +54,51 @@ func (r *PORepositoryImpl) GetByID(id uint) (*models.PurchaseOrder, error) {
 	return &po, nil
 }
 
+// GetLatestForSupplier loads the supplier's most recent non-draft purchase order
+// with its items. Returns gorm.ErrRecordNotFound when there is none.
+func (r *PORepositoryImpl) GetLatestForSupplier(supplierID uint) (*models.PurchaseOrder, error) {
+	var po models.PurchaseOrder
+	err := r.db.
+		Preload("Items").
+		Where("supplier_id = ? AND status <> ?", supplierID, "draft").
+		Order("date DESC, id DESC").
+		First(&po).Error
+	if err != nil {
+		return nil, err
+	}
+	return &po, nil
+}
+
+// ListReceived returns received or completed purchase orders, with supplier
+// and items, whose received date falls within [from, to].
+func (r *PORepositoryImpl) ListReceived(from, to time.Time) ([]models.PurchaseOrder, error) {
+	var pos []models.PurchaseOrder
+	err := r.db.
+		Preload("Supplier").
+		Preload("Items").
+		Where("status IN ?", []string{"received", "completed"}).
+		Where("received_date >= ? AND received_date < ?", from, to.AddDate(0, 0, 1)).
+		Order("received_date ASC, id ASC").
+		Find(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+	return pos, nil
+}
+
+// POListFilter narrows a purchase order list. Empty fields do not filter;
+// all set fields must match.
+type POListFilter struct {
+	Status     string
+	SupplierID uint
+	// Overdue keeps only sent or partially received POs whose expected date is before Today
+	// (YYYY-MM-DD).
+	Overdue bool
+	Today   string
+}
+
 // List returns paginated purchase orders with optional filters.
-func (r *PORepositoryImpl) List(params PaginationParams, status string, supplierID uint) ([]models.PurchaseOrder, int64, error) {
+func (r *PORepositoryImpl) List(params PaginationParams, filter POListFilter) ([]models.PurchaseOrder, int64, error) {
 	var pos []models.PurchaseOrder
 	var total int64
 
@@ -60,12 +112,16 @@ func (r *PORepositoryImpl) List(params PaginationParams, status string, supplier
 		)
 	}
 
-	if status != "" {
-		query = query.Where("status = ?", status)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
 	}
 
-	if supplierID > 0 {
-		query = query.Where("supplier_id = ?", supplierID)
+	if filter.SupplierID > 0 {
+		query = query.Where("supplier_id = ?", filter.SupplierID)
+	}
+
+	if filter.Overdue {
+		query = query.Where("status IN ? AND expected_date IS NOT NULL AND expected_date < ?", []string{"sent", "partially_received"}, filter.Today)
 	}
 
 	if err := query.Count(&total).Error; err != nil {
@@ -129,6 +185,19 @@ func (r *PORepositoryImpl) Update(po *models.PurchaseOrder) error {
 	return r.db.Save(po).Error
 }
 
+// MarkPaid sets paid_at on a received or completed PO that is not paid yet.
+// Only that column is written, so a concurrent receive is never overwritten.
+// It reports whether the PO was updated.
+func (r *PORepositoryImpl) MarkPaid(id uint, paidAt time.Time) (bool, error) {
+	result := r.db.Model(&models.PurchaseOrder{}).
+		Where("id = ? AND paid_at IS NULL AND status IN ?", id, []string{"received", "completed"}).
+		Update("paid_at", paidAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // Delete removes a purchase order from the database.
 func (r *PORepositoryImpl) Delete(id uint) error {
 	result := r.db.Delete(&models.PurchaseOrder{}, id)
@@ -185,3 +254,63 @@ func (r *PORepositoryImpl) GetProductsForPO(supplierID uint, search string) ([]m
 
 	return products, nil
 }
+
+// lowStockCondition matches variants with a reorder point whose stock has
+// fallen to or below it.
+const lowStockCondition = "reorder_point > 0 AND current_stock <= reorder_point"
+
+// ListLowStockProducts loads active products that have at least one low-stock
+// variant. Only the low-stock variants and active suppliers are preloaded.
+func (r *PORepositoryImpl) ListLowStockProducts() ([]models.Product, error) {
+	var products []models.Product
+	err := r.db.Model(&models.Product{}).
+		Where("products.status = ?", "active").
+		Where("EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND pv.reorder_point > 0 AND pv.current_stock <= pv.reorder_point)").
+		Preload("Suppliers", "active = ?", true).
+		Preload("Units").
+		Preload("Variants", lowStockCondition).
+		Preload("Variants.Attributes").
+		Order("products.name ASC").
+		Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// CreateAttachment persists a new PO attachment record.
+func (r *PORepositoryImpl) CreateAttachment(attachment *models.POAttachment) error {
+	return r.db.Create(attachment).Error
+}
+
+// ListAttachments returns a PO's attachments, oldest first.
+func (r *PORepositoryImpl) ListAttachments(poID uint) ([]models.POAttachment, error) {
+	attachments := make([]models.POAttachment, 0)
+	err := r.db.Where("purchase_order_id = ?", poID).Order("created_at ASC, id ASC").Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// GetAttachment loads an attachment belonging to the given PO.
+func (r *PORepositoryImpl) GetAttachment(poID, attachmentID uint) (*models.POAttachment, error) {
+	var attachment models.POAttachment
+	err := r.db.Where("purchase_order_id = ?", poID).First(&attachment, attachmentID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// DeleteAttachment removes an attachment record.
+func (r *PORepositoryImpl) DeleteAttachment(id uint) error {
+	result := r.db.Delete(&models.POAttachment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -111,7 +111,7 @@ func TestListPOs_FilterByStatus_Works(t *testing.T) {
 
 	params := PaginationParams{Page: 1, PageSize: 10, SortBy: "date", SortDir: "asc"}
 
-	drafts, total, err := repo.List(params, "draft", 0)
+	drafts, total, err := repo.List(params, POListFilter{Status: "draft"})
 	require.NoError(t, err)
 	assert.Equal(t, int64(1), total)
 	assert.Equal(t, "draft", drafts[0].Status)
@@ -146,12 +146,36 @@ func TestListPOs_FilterBySupplier_Works(t *testing.T) {
 
 	params := PaginationParams{Page: 1, PageSize: 10, SortBy: "date", SortDir: "asc"}
 
-	results, total, err := repo.List(params, "", supplier1.ID)
+	results, total, err := repo.List(params, POListFilter{SupplierID: supplier1.ID})
 	require.NoError(t, err)
 	assert.Equal(t, int64(1), total)
 	assert.Equal(t, supplier1.ID, results[0].SupplierID)
 }
 
+func TestListPOs_FilterOverdue_ReturnsSentPOsExpectedBeforeToday(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewPORepository(db)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	past, future := "2026-01-20", "2026-02-20"
+
+	overdue := &models.PurchaseOrder{PONumber: "PO-2026-0021", SupplierID: supplier.ID, Date: "2026-01-10", ExpectedDate: &past, Status: "sent"}
+	notDue := &models.PurchaseOrder{PONumber: "PO-2026-0022", SupplierID: supplier.ID, Date: "2026-01-10", ExpectedDate: &future, Status: "sent"}
+	draft := &models.PurchaseOrder{PONumber: "PO-2026-0023", SupplierID: supplier.ID, Date: "2026-01-10", ExpectedDate: &past, Status: "draft"}
+	noDate := &models.PurchaseOrder{PONumber: "PO-2026-0024", SupplierID: supplier.ID, Date: "2026-01-10", Status: "sent"}
+	for _, po := range []*models.PurchaseOrder{overdue, notDue, draft, noDate} {
+		require.NoError(t, db.Create(po).Error)
+	}
+
+	params := PaginationParams{Page: 1, PageSize: 10, SortBy: "date", SortDir: "asc"}
+
+	results, total, err := repo.List(params, POListFilter{Overdue: true, Today: "2026-02-01"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, results, 1)
+	assert.Equal(t, "PO-2026-0021", results[0].PONumber)
+}
+
 func TestListPOs_SearchByPONumberOrSupplier_Works(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	repo := NewPORepository(db)
@@ -181,14 +205,14 @@ func TestListPOs_SearchByPONumberOrSupplier_Works(t *testing.T) {
 
 	// Search by PO number
 	params := PaginationParams{Page: 1, PageSize: 10, Search: "0021", SortBy: "date", SortDir: "asc"}
-	results, total, err := repo.List(params, "", 0)
+	results, total, err := repo.List(params, POListFilter{})
 	require.NoError(t, err)
 	assert.Equal(t, int64(1), total)
 	assert.Equal(t, "PO-2026-0021", results[0].PONumber)
 
 	// Search by supplier name
 	params2 := PaginationParams{Page: 1, PageSize: 10, Search: "ACME", SortBy: "date", SortDir: "asc"}
-	results2, total2, err := repo.List(params2, "", 0)
+	results2, total2, err := repo.List(params2, POListFilter{})
 	require.NoError(t, err)
 	assert.Equal(t, int64(2), total2)
 	_ = results2
@@ -306,3 +330,32 @@ func TestDeletePO_DraftOnly_Succeeds(t *testing.T) {
 	_, err = repo.GetByID(po.ID)
 	assert.Error(t, err)
 }
+
+func TestListLowStockProducts_OnlyLowStockVariantsAndActiveSuppliers(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewPORepository(db)
+
+	active := testutil.CreateTestSupplier(t, db)
+	inactive := testutil.CreateTestSupplier(t, db, func(s *models.Supplier) {
+		s.Active = false
+	})
+	low := testutil.CreateTestProduct(t, db)
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", low.Variants[0].ID).
+		Updates(map[string]interface{}{"reorder_point": 10, "current_stock": 4}).Error)
+	require.NoError(t, db.Model(low).Association("Suppliers").Append([]models.Supplier{*active, *inactive}))
+
+	stocked := testutil.CreateTestProduct(t, db)
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", stocked.Variants[0].ID).
+		Update("reorder_point", 10).Error)
+
+	products, err := repo.ListLowStockProducts()
+	require.NoError(t, err)
+
+	require.Len(t, products, 1)
+	assert.Equal(t, low.ID, products[0].ID)
+	require.Len(t, products[0].Variants, 1)
+	assert.Equal(t, 4, products[0].Variants[0].CurrentStock)
+	require.Len(t, products[0].Suppliers, 1)
+	assert.Equal(t, active.ID, products[0].Suppliers[0].ID)
+	assert.NotEmpty(t, products[0].Units)
+}
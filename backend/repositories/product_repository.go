@@ -33,6 +33,48 @@ type ProductListItem struct {
 	CreatedAt    time.Time             `json:"createdAt"`
 }
 
+// VariantRackQuantity is the quantity of a variant stored on a single rack.
+type VariantRackQuantity struct {
+	RackID   uint   `json:"rackId"`
+	RackName string `json:"rackName"`
+	RackCode string `json:"rackCode"`
+	Quantity int    `json:"quantity"`
+}
+
+// LabelVariant is a variant with the product name needed to print its shelf
+// label and the product's price setting needed to read its tier prices.
+type LabelVariant struct {
+	models.ProductVariant
+	ProductName  string
+	PriceSetting string
+}
+
+// ProductSalesStats aggregates a product's sales over a period.
+type ProductSalesStats struct {
+	QuantitySold int64   `json:"quantitySold"`
+	Revenue      float64 `json:"revenue"`
+	Transactions int64   `json:"transactions"`
+}
+
+// VariantQuantitySold is the base quantity of one variant sold over a period.
+type VariantQuantitySold struct {
+	VariantID    string
+	QuantitySold int64
+}
+
+// ProductLowStockItem is a variant at or below its reorder point, with the
+// product and supplier details needed to reorder it.
+type ProductLowStockItem struct {
+	VariantID    string            `json:"variantId"`
+	ProductID    uint              `json:"productId"`
+	ProductName  string            `json:"productName"`
+	SKU          string            `json:"sku"`
+	CurrentStock int               `json:"currentStock"`
+	ReorderPoint int               `json:"reorderPoint"`
+	Shortfall    int               `json:"shortfall"`
+	Suppliers    []models.Supplier `json:"suppliers" gorm:"-"`
+}
+
 // ProductRepository defines the interface for product data operations.
 type ProductRepository interface {
 	GetDB() *gorm.DB
@@ -45,6 +87,16 @@ type ProductRepository interface {
 	BarcodeExistsForOtherProducts(barcode string, excludeProductID uint) (bool, error)
 	CountVariantsWithStock(productID uint) (int64, error)
 	CountPurchaseOrderReferences(productID uint) (int64, error)
+	GetVariantByID(variantID string) (*models.ProductVariant, error)
+	ListVariantRackQuantities(variantID string) ([]VariantRackQuantity, error)
+	ListVariantsForLabels(variantIDs []string) ([]LabelVariant, error)
+	ListVariantIDsForProducts(productIDs []uint) ([]string, error)
+	SalesStatsSince(productID uint, since time.Time) (ProductSalesStats, error)
+	VariantQuantitySoldSince(variantID string, since time.Time) (int64, error)
+	ListVariantQuantitiesSoldSince(productID uint, since time.Time) ([]VariantQuantitySold, error)
+	ListReceivedSupplierOrders(productID uint, limit int) ([]models.PurchaseOrder, error)
+	ListLowStockVariants(params PaginationParams) ([]ProductLowStockItem, int64, error)
+	AppendImage(image *models.ProductImage, maxImages int) (bool, error)
 	Delete(id uint) error
 }
 
@@ -313,6 +365,187 @@ func (r *ProductRepositoryImpl) CountPurchaseOrderReferences(productID uint) (in
 	return 0, nil
 }
 
+// GetVariantByID loads a single variant without nested relations.
+func (r *ProductRepositoryImpl) GetVariantByID(variantID string) (*models.ProductVariant, error) {
+	var variant models.ProductVariant
+	if err := r.db.Where("id = ?", variantID).First(&variant).Error; err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// ListVariantRackQuantities returns the per-rack quantities of a variant ordered by rack code.
+func (r *ProductRepositoryImpl) ListVariantRackQuantities(variantID string) ([]VariantRackQuantity, error) {
+	rows := make([]VariantRackQuantity, 0)
+	err := r.db.Table("variant_racks vr").
+		Select("r.id AS rack_id, r.name AS rack_name, r.code AS rack_code, vr.quantity").
+		Joins("JOIN racks r ON r.id = vr.rack_id").
+		Where("vr.variant_id = ?", variantID).
+		Order("r.code ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListVariantsForLabels loads variants with attributes, pricing tiers and product name.
+// Results follow the order of variantIDs; unknown IDs are skipped.
+func (r *ProductRepositoryImpl) ListVariantsForLabels(variantIDs []string) ([]LabelVariant, error) {
+	var variants []models.ProductVariant
+	err := r.db.Where("id IN ?", variantIDs).
+		Preload("Attributes", func(db *gorm.DB) *gorm.DB {
+			return db.Order("id ASC")
+		}).
+		Preload("PricingTiers", func(db *gorm.DB) *gorm.DB {
+			return db.Order("min_qty ASC")
+		}).
+		Find(&variants).Error
+	if err != nil {
+		return nil, err
+	}
+
+	productIDs := make([]uint, 0, len(variants))
+	for _, variant := range variants {
+		productIDs = append(productIDs, variant.ProductID)
+	}
+	var products []models.Product
+	if len(productIDs) > 0 {
+		if err := r.db.Select("id", "name", "price_setting").Where("id IN ?", productIDs).Find(&products).Error; err != nil {
+			return nil, err
+		}
+	}
+	productsByID := make(map[uint]models.Product, len(products))
+	for _, product := range products {
+		productsByID[product.ID] = product
+	}
+
+	byID := make(map[string]models.ProductVariant, len(variants))
+	for _, variant := range variants {
+		byID[variant.ID] = variant
+	}
+	result := make([]LabelVariant, 0, len(variants))
+	for _, id := range variantIDs {
+		variant, ok := byID[id]
+		if !ok {
+			continue
+		}
+		product := productsByID[variant.ProductID]
+		result = append(result, LabelVariant{ProductVariant: variant, ProductName: product.Name, PriceSetting: product.PriceSetting})
+	}
+	return result, nil
+}
+
+// ListVariantIDsForProducts returns the IDs of every variant of the given
+// products, ordered by product then variant creation.
+func (r *ProductRepositoryImpl) ListVariantIDsForProducts(productIDs []uint) ([]string, error) {
+	ids := make([]string, 0)
+	err := r.db.Model(&models.ProductVariant{}).
+		Where("product_id IN ?", productIDs).
+		Order("product_id ASC, created_at ASC").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SalesStatsSince sums the base quantity, revenue and number of transactions
+// for a product's sales dated on or after since.
+func (r *ProductRepositoryImpl) SalesStatsSince(productID uint, since time.Time) (ProductSalesStats, error) {
+	var stats ProductSalesStats
+	err := r.db.Table("sales_transaction_items AS sti").
+		Select("COALESCE(SUM(sti.base_qty), 0) AS quantity_sold, "+
+			"COALESCE(SUM(sti.total_price), 0) AS revenue, "+
+			"COUNT(DISTINCT sti.transaction_id) AS transactions").
+		Joins("JOIN sales_transactions st ON st.id = sti.transaction_id").
+		Where("sti.product_id = ? AND st.date >= ?", productID, since).
+		Scan(&stats).Error
+	return stats, err
+}
+
+// VariantQuantitySoldSince sums the base quantity of a variant sold on or after since.
+func (r *ProductRepositoryImpl) VariantQuantitySoldSince(variantID string, since time.Time) (int64, error) {
+	var quantity int64
+	err := r.db.Table("sales_transaction_items AS sti").
+		Select("COALESCE(SUM(sti.base_qty), 0)").
+		Joins("JOIN sales_transactions st ON st.id = sti.transaction_id").
+		Where("sti.variant_id = ? AND st.date >= ?", variantID, since).
+		Scan(&quantity).Error
+	return quantity, err
+}
+
+// ListVariantQuantitiesSoldSince sums the base quantity sold on or after since
+// for each variant of a product. Variants without sales are left out.
+func (r *ProductRepositoryImpl) ListVariantQuantitiesSoldSince(productID uint, since time.Time) ([]VariantQuantitySold, error) {
+	rows := make([]VariantQuantitySold, 0)
+	err := r.db.Table("sales_transaction_items AS sti").
+		Select("sti.variant_id, COALESCE(SUM(sti.base_qty), 0) AS quantity_sold").
+		Joins("JOIN sales_transactions st ON st.id = sti.transaction_id").
+		Where("sti.product_id = ? AND st.date >= ?", productID, since).
+		Group("sti.variant_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ListReceivedSupplierOrders returns up to limit of the most recently received
+// purchase orders, without items, from the suppliers linked to a product.
+func (r *ProductRepositoryImpl) ListReceivedSupplierOrders(productID uint, limit int) ([]models.PurchaseOrder, error) {
+	var orders []models.PurchaseOrder
+	err := r.db.
+		Where("supplier_id IN (SELECT supplier_id FROM product_suppliers WHERE product_id = ?)", productID).
+		Where("status IN ?", []string{"received", "completed"}).
+		Where("received_date IS NOT NULL").
+		Order("received_date DESC, id DESC").
+		Limit(limit).
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// AppendImage adds an image after the product's existing images, setting its
+// sort order to one past the highest in use. It returns false without adding
+// the image when the product already has maxImages images; a zero maxImages
+// is unbounded.
+func (r *ProductRepositoryImpl) AppendImage(image *models.ProductImage, maxImages int) (bool, error) {
+	appended := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the product row so concurrent uploads get distinct sort orders
+		// and cannot both take the last free slot
+		if err := tx.Exec("SELECT id FROM products WHERE id = ? FOR UPDATE", image.ProductID).Error; err != nil {
+			return err
+		}
+		if maxImages > 0 {
+			var count int64
+			if err := tx.Model(&models.ProductImage{}).Where("product_id = ?", image.ProductID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count >= int64(maxImages) {
+				return nil
+			}
+		}
+		var next int
+		if err := tx.Model(&models.ProductImage{}).
+			Select("COALESCE(MAX(sort_order) + 1, 0)").
+			Where("product_id = ?", image.ProductID).
+			Scan(&next).Error; err != nil {
+			return err
+		}
+		image.SortOrder = next
+		if err := tx.Create(image).Error; err != nil {
+			return err
+		}
+		appended = true
+		return nil
+	})
+	return appended, err
+}
+
 func (r *ProductRepositoryImpl) Delete(id uint) error {
 	result := r.db.Delete(&models.Product{}, id)
 	if result.Error != nil {
@@ -323,3 +556,75 @@ func (r *ProductRepositoryImpl) Delete(id uint) error {
 	}
 	return nil
 }
+
+// ListLowStockVariants returns a page of variants of active products whose
+// stock is at or below a positive reorder point. Search matches the product
+// name or SKU. Each item carries the product's active suppliers.
+func (r *ProductRepositoryImpl) ListLowStockVariants(params PaginationParams) ([]ProductLowStockItem, int64, error) {
+	query := r.db.Table("product_variants pv").
+		Joins("JOIN products p ON p.id = pv.product_id").
+		Where("p.status = ?", "active").
+		Where("pv.reorder_point > 0 AND pv.current_stock <= pv.reorder_point")
+
+	if params.Search != "" {
+		search := "%" + params.Search + "%"
+		query = query.Where("(p.name ILIKE ? OR pv.sku ILIKE ?)", search, search)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sortBy := "pv.current_stock"
+	switch params.SortBy {
+	case "name":
+		sortBy = "p.name"
+	case "shortfall":
+		sortBy = "pv.reorder_point - pv.current_stock"
+	}
+	sortDir := "ASC"
+	if params.SortDir == "desc" {
+		sortDir = "DESC"
+	}
+
+	items := make([]ProductLowStockItem, 0)
+	offset := (params.Page - 1) * params.PageSize
+	err := query.
+		Select("pv.id AS variant_id, p.id AS product_id, p.name AS product_name, pv.sku, pv.current_stock, pv.reorder_point, " +
+			"pv.reorder_point - pv.current_stock AS shortfall").
+		Order(sortBy + " " + sortDir + ", p.name ASC, pv.id ASC").
+		Offset(offset).
+		Limit(params.PageSize).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(items) == 0 {
+		return items, total, nil
+	}
+
+	productIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	var products []models.Product
+	if err := r.db.Select("id").
+		Preload("Suppliers", "active = ?", true).
+		Where("id IN ?", productIDs).
+		Find(&products).Error; err != nil {
+		return nil, 0, err
+	}
+	suppliersByProduct := make(map[uint][]models.Supplier, len(products))
+	for _, product := range products {
+		suppliersByProduct[product.ID] = product.Suppliers
+	}
+	for i := range items {
+		items[i].Suppliers = suppliersByProduct[items[i].ProductID]
+		if items[i].Suppliers == nil {
+			items[i].Suppliers = []models.Supplier{}
+		}
+	}
+
+	return items, total, nil
+}
@@ -138,3 +138,102 @@ func (r *RackRepositoryImpl) CleanupVariantRacks(rackID uint) error {
 	}
 	return nil
 }
+
+// RackEvacuation counts the variants and the total quantity moved off a rack.
+type RackEvacuation struct {
+	Variants int `gorm:"column:variants"`
+	Quantity int `gorm:"column:quantity"`
+}
+
+// EvacuateStock moves every variant on fromRackID, with its quantity, to
+// toRackID in one transaction. Variants already on toRackID get the moved
+// quantity added to theirs. The source rack is left with no variants.
+func (r *RackRepositoryImpl) EvacuateStock(fromRackID, toRackID uint) (RackEvacuation, error) {
+	var moved RackEvacuation
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the source rows so concurrent placements wait for the move
+		if err := tx.Exec("SELECT 1 FROM variant_racks WHERE rack_id = ? FOR UPDATE", fromRackID).Error; err != nil {
+			return err
+		}
+		if err := tx.Table("variant_racks").
+			Select("COUNT(*) AS variants, COALESCE(SUM(quantity), 0) AS quantity").
+			Where("rack_id = ?", fromRackID).
+			Scan(&moved).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(`INSERT INTO variant_racks (variant_id, rack_id, quantity)
+			SELECT variant_id, ?, quantity FROM variant_racks WHERE rack_id = ?
+			ON CONFLICT (variant_id, rack_id) DO UPDATE SET quantity = variant_racks.quantity + EXCLUDED.quantity`,
+			toRackID, fromRackID).Error; err != nil {
+			return err
+		}
+		return tx.Exec("DELETE FROM variant_racks WHERE rack_id = ?", fromRackID).Error
+	})
+	if err != nil {
+		return RackEvacuation{}, err
+	}
+	return moved, nil
+}
+
+// RackStockRow is a variant assigned to a rack, with its product and the
+// quantity placed on the rack. Attributes are ordered by ID.
+type RackStockRow struct {
+	VariantID     string                    `gorm:"column:variant_id"`
+	ProductID     uint                      `gorm:"column:product_id"`
+	ProductName   string                    `gorm:"column:product_name"`
+	ProductStatus string                    `gorm:"column:product_status"`
+	SKU           string                    `gorm:"column:sku"`
+	RackQuantity  int                       `gorm:"column:rack_quantity"`
+	CurrentStock  int                       `gorm:"column:current_stock"`
+	Attributes    []models.VariantAttribute `gorm:"-"`
+}
+
+// ListStock returns the variants assigned to a rack ordered by product name
+// and SKU. When activeOnly is set, variants of inactive products are left out.
+func (r *RackRepositoryImpl) ListStock(rackID uint, page, pageSize int, activeOnly bool) ([]RackStockRow, int64, error) {
+	rows := make([]RackStockRow, 0)
+	var total int64
+
+	query := r.db.Table("variant_racks vr").
+		Joins("JOIN product_variants pv ON pv.id = vr.variant_id").
+		Joins("JOIN products p ON p.id = pv.product_id").
+		Where("vr.rack_id = ?", rackID)
+	if activeOnly {
+		query = query.Where("p.status = ?", "active")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Select("pv.id AS variant_id, p.id AS product_id, p.name AS product_name, p.status AS product_status, pv.sku, vr.quantity AS rack_quantity, pv.current_stock").
+		Order("p.name ASC, pv.sku ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(rows) == 0 {
+		return rows, total, nil
+	}
+
+	variantIDs := make([]string, len(rows))
+	for i, row := range rows {
+		variantIDs[i] = row.VariantID
+	}
+	var attributes []models.VariantAttribute
+	if err := r.db.Where("variant_id IN ?", variantIDs).Order("id ASC").Find(&attributes).Error; err != nil {
+		return nil, 0, err
+	}
+	byVariant := make(map[string][]models.VariantAttribute, len(rows))
+	for _, attr := range attributes {
+		byVariant[attr.VariantID] = append(byVariant[attr.VariantID], attr)
+	}
+	for i := range rows {
+		rows[i].Attributes = byVariant[rows[i].VariantID]
+	}
+
+	return rows, total, nil
+}
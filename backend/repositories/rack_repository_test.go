@@ -317,3 +317,46 @@ func TestDeleteRack_NotFound_ReturnsError(t *testing.T) {
 	err := repo.Delete(99999)
 	assert.Error(t, err)
 }
+
+// TestEvacuateStock_MovesQuantitiesAndEmptiesSource verifies quantities are merged onto the target rack
+func TestEvacuateStock_MovesQuantitiesAndEmptiesSource(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewRackRepository(db)
+	source := testutil.CreateTestRack(t, db)
+	target := testutil.CreateTestRack(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	variantID := product.Variants[0].ID
+	require.NoError(t, db.Exec("INSERT INTO variant_racks (variant_id, rack_id, quantity) VALUES (?, ?, ?), (?, ?, ?)",
+		variantID, source.ID, 20, variantID, target.ID, 3).Error)
+
+	moved, err := repo.EvacuateStock(source.ID, target.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved.Variants)
+	assert.Equal(t, 20, moved.Quantity)
+
+	var rows []struct {
+		RackID   uint
+		Quantity int
+	}
+	require.NoError(t, db.Table("variant_racks").Select("rack_id, quantity").Where("variant_id = ?", variantID).Scan(&rows).Error)
+	require.Len(t, rows, 1)
+	assert.Equal(t, target.ID, rows[0].RackID)
+	assert.Equal(t, 23, rows[0].Quantity)
+}
+
+// TestEvacuateStock_EmptyRack_MovesNothing verifies an empty rack evacuates cleanly
+func TestEvacuateStock_EmptyRack_MovesNothing(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewRackRepository(db)
+	source := testutil.CreateTestRack(t, db)
+	target := testutil.CreateTestRack(t, db)
+
+	moved, err := repo.EvacuateStock(source.ID, target.ID)
+	require.NoError(t, err)
+	assert.Zero(t, moved.Variants)
+	assert.Zero(t, moved.Quantity)
+}
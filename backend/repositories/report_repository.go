@@ -0,0 +1,182 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"gorm.io/gorm"
+)
+
+// SaleAmount is the date and net amount of a single sales transaction.
+type SaleAmount struct {
+	Date      time.Time
+	NetAmount float64
+}
+
+// CashierSales is the sales totals rung up by one cashier. CashierID is nil
+// for sales recorded before cashier attribution.
+type CashierSales struct {
+	CashierID    *uint
+	CashierName  string
+	Transactions int
+	Gross        float64
+	Net          float64
+	Surcharges   float64
+	Discounts    float64
+}
+
+// PermissionDenialCount is how often one user was denied one permission on
+// one route. UserID is nil when the user has since been removed.
+type PermissionDenialCount struct {
+	UserID       *uint
+	UserName     string
+	UserEmail    string
+	Method       string
+	Route        string
+	Permission   string
+	Denials      int
+	LastDeniedAt time.Time
+}
+
+// ProfitLine is the revenue and cost of one sold or returned line item.
+// Returned lines carry negative amounts dated on the return. Costed is false
+// when the item was sold without a known cost price, in which case Cost is 0.
+type ProfitLine struct {
+	Date         time.Time
+	ProductID    uint
+	ProductName  string
+	CategoryID   uint
+	CategoryName string
+	Revenue      float64
+	Cost         float64
+	Costed       bool
+}
+
+// ReportRepository defines the interface for reporting queries.
+type ReportRepository interface {
+	ListSalesBetween(from, to time.Time) ([]SaleAmount, error)
+	ProfitLinesBetween(from, to time.Time) ([]ProfitLine, error)
+	SalesByCashierBetween(from, to time.Time) ([]CashierSales, error)
+	PermissionDenialsBetween(from, to time.Time, minDenials int) ([]PermissionDenialCount, error)
+}
+
+// ReportRepositoryImpl implements ReportRepository.
+type ReportRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewReportRepository creates a new report repository instance.
+func NewReportRepository(db *gorm.DB) *ReportRepositoryImpl {
+	return &ReportRepositoryImpl{db: db}
+}
+
+// netSalesSQL is a sale's subtotal less its discount and any tax included in
+// its prices, so revenue never counts tax in either tax mode.
+const netSalesSQL = "st.subtotal - st.discount - CASE WHEN st.tax_inclusive THEN st.tax_amount ELSE 0 END"
+
+// exTaxFactorSQL scales an amount of a sale to exclude tax included in its
+// prices. Tax added on top of the prices is never part of a line amount.
+const exTaxFactorSQL = "CASE WHEN st.tax_inclusive THEN 100 / (100 + st.tax_percent) ELSE 1 END"
+
+// ListSalesBetween returns sales dated within [from, to), oldest first.
+// The net amount is the subtotal less the sale discount, excluding tax and
+// payment surcharges.
+func (r *ReportRepositoryImpl) ListSalesBetween(from, to time.Time) ([]SaleAmount, error) {
+	rows := make([]SaleAmount, 0)
+	err := r.db.Table("sales_transactions AS st").
+		Select("st.date, "+netSalesSQL+" AS net_amount").
+		Where("st.date >= ? AND st.date < ?", from, to).
+		Order("st.date ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// ProfitLinesBetween returns the line items sold within [from, to) and the
+// returned items refunded within [from, to), oldest first. Revenue is the
+// discounted line total less its share of the sale discount, which is split
+// across lines in proportion to their value, excluding tax; returns are
+// negated the same way from the returned quantity rather than the refund,
+// which includes tax added on top of the prices. Cost is the base quantity
+// times the unit cost recorded at sale.
+func (r *ReportRepositoryImpl) ProfitLinesBetween(from, to time.Time) ([]ProfitLine, error) {
+	rows := make([]ProfitLine, 0)
+	err := r.db.Raw(`
+		SELECT st.date, sti.product_id, sti.product_name,
+			COALESCE(p.category_id, 0) AS category_id, COALESCE(c.name, '') AS category_name,
+			(sti.total_price - CASE WHEN st.subtotal > 0
+				THEN sti.total_price * st.discount / st.subtotal ELSE 0 END) * `+exTaxFactorSQL+` AS revenue,
+			sti.base_qty * COALESCE(sti.unit_cost, 0) AS cost,
+			sti.unit_cost IS NOT NULL AS costed
+		FROM sales_transaction_items sti
+		JOIN sales_transactions st ON st.id = sti.transaction_id
+		LEFT JOIN products p ON p.id = sti.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE st.date >= ? AND st.date < ?
+		UNION ALL
+		SELECT sr.date, sti.product_id, sti.product_name,
+			COALESCE(p.category_id, 0), COALESCE(c.name, ''),
+			-(sri.quantity * sti.unit_price - CASE WHEN st.subtotal > 0
+				THEN sri.quantity * sti.unit_price * st.discount / st.subtotal ELSE 0 END) * `+exTaxFactorSQL+`,
+			-(sri.base_qty * COALESCE(sti.unit_cost, 0)),
+			sti.unit_cost IS NOT NULL
+		FROM sales_return_items sri
+		JOIN sales_returns sr ON sr.id = sri.return_id
+		JOIN sales_transaction_items sti ON sti.id = sri.transaction_item_id
+		JOIN sales_transactions st ON st.id = sti.transaction_id
+		LEFT JOIN products p ON p.id = sti.product_id
+		LEFT JOIN categories c ON c.id = p.category_id
+		WHERE sr.date >= ? AND sr.date < ?
+		ORDER BY date ASC`, from, to, from, to).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// SalesByCashierBetween totals sales dated within [from, to) per cashier.
+// Gross is the amount charged including tax and surcharges; net excludes
+// both. Discounts is the sale discounts plus the amounts taken off each line.
+func (r *ReportRepositoryImpl) SalesByCashierBetween(from, to time.Time) ([]CashierSales, error) {
+	rows := make([]CashierSales, 0)
+	err := r.db.Table("sales_transactions AS st").
+		Select(`st.cashier_id, COALESCE(u.name, '') AS cashier_name,
+			COUNT(*) AS transactions,
+			COALESCE(SUM(st.grand_total), 0) AS gross,
+			COALESCE(SUM(`+netSalesSQL+`), 0) AS net,
+			COALESCE(SUM(st.surcharge_amount), 0) AS surcharges,
+			COALESCE(SUM(st.discount + COALESCE(
+				(SELECT SUM(sti.discount_amount) FROM sales_transaction_items sti WHERE sti.transaction_id = st.id), 0)), 0) AS discounts`).
+		Joins("LEFT JOIN users u ON u.id = st.cashier_id").
+		Where("st.date >= ? AND st.date < ?", from, to).
+		Group("st.cashier_id, u.name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// PermissionDenialsBetween counts audited permission denials within
+// [from, to) per user, route and permission, keeping groups with at least
+// minDenials denials, most frequent first.
+func (r *ReportRepositoryImpl) PermissionDenialsBetween(from, to time.Time, minDenials int) ([]PermissionDenialCount, error) {
+	rows := make([]PermissionDenialCount, 0)
+	err := r.db.Table("audit_logs AS al").
+		Select(`al.user_id, COALESCE(u.name, '') AS user_name, COALESCE(u.email, '') AS user_email,
+			al.method, al.route, al.permission,
+			COUNT(*) AS denials, MAX(al.created_at) AS last_denied_at`).
+		Joins("LEFT JOIN users u ON u.id = al.user_id").
+		Where("al.event = ? AND al.created_at >= ? AND al.created_at < ?", models.AuditEventPermissionDenied, from, to).
+		Group("al.user_id, u.name, u.email, al.method, al.route, al.permission").
+		Having("COUNT(*) >= ?", minDenials).
+		Order("denials DESC, last_denied_at DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
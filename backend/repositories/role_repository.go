@@ -16,6 +16,9 @@ type RoleRepository interface {
 	Create(role *models.Role) error
 	Update(role *models.Role) error
 	Delete(id uint) error
+	CloneWithPermissions(sourceID uint, role *models.Role) error
+	CountUsersWithRole(roleID uint) (int64, error)
+	ReassignUsersAndDelete(id, fallbackID uint) ([]uint, error)
 }
 
 // RoleWithCount adds userCount to role data
@@ -133,3 +136,73 @@ func (r *RoleRepositoryImpl) Delete(id uint) error {
 	}
 	return nil
 }
+
+// CountUsersWithRole counts live users assigned the role. Soft-deleted users
+// keep their assignments but are not counted.
+func (r *RoleRepositoryImpl) CountUsersWithRole(roleID uint) (int64, error) {
+	var count int64
+	err := r.db.Table("user_roles").
+		Joins("JOIN users ON users.id = user_roles.user_id").
+		Where("user_roles.role_id = ? AND users.deleted_at IS NULL", roleID).
+		Count(&count).Error
+	return count, err
+}
+
+// ReassignUsersAndDelete assigns the fallback role to every user holding the
+// role, then deletes the role, in one transaction. Users who already hold the
+// fallback keep a single assignment. It returns the IDs of the affected users.
+func (r *RoleRepositoryImpl) ReassignUsersAndDelete(id, fallbackID uint) ([]uint, error) {
+	var userIDs []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table("user_roles").Where("role_id = ?", id).Pluck("user_id", &userIDs).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`INSERT INTO user_roles (user_id, role_id)
+			SELECT user_id, ? FROM user_roles WHERE role_id = ?
+			ON CONFLICT DO NOTHING`, fallbackID, id).Error; err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.Role{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// CloneWithPermissions creates role and copies every role permission of the
+// source role onto it in one transaction
+func (r *RoleRepositoryImpl) CloneWithPermissions(sourceID uint, role *models.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(role).Error; err != nil {
+			return err
+		}
+
+		var sourcePermissions []models.RolePermission
+		if err := tx.Where("role_id = ?", sourceID).Find(&sourcePermissions).Error; err != nil {
+			return err
+		}
+		if len(sourcePermissions) == 0 {
+			return nil
+		}
+
+		cloned := make([]models.RolePermission, 0, len(sourcePermissions))
+		for _, rp := range sourcePermissions {
+			cloned = append(cloned, models.RolePermission{
+				RoleID:       role.ID,
+				PermissionID: rp.PermissionID,
+				Actions:      rp.Actions,
+			})
+		}
+		return tx.Create(&cloned).Error
+	})
+}
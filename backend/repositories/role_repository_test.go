@@ -387,3 +387,66 @@ func TestDelete_NotFound_ReturnsError(t *testing.T) {
 	err := repo.Delete(99999)
 	assert.Error(t, err)
 }
+
+// TestCloneWithPermissions_CopiesRolePermissions verifies the clone gets the source's permissions
+func TestCloneWithPermissions_CopiesRolePermissions(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewRoleRepository(db)
+
+	source := testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = "Cashier"
+	})
+	perm1 := testutil.CreateTestPermission(t, db)
+	perm2 := testutil.CreateTestPermission(t, db)
+	require.NoError(t, db.Create(&models.RolePermission{RoleID: source.ID, PermissionID: perm1.ID, Actions: []string{"read"}}).Error)
+	require.NoError(t, db.Create(&models.RolePermission{RoleID: source.ID, PermissionID: perm2.ID, Actions: []string{"read", "create"}}).Error)
+
+	clone := &models.Role{Name: "Senior Cashier"}
+	require.NoError(t, repo.CloneWithPermissions(source.ID, clone))
+	assert.NotZero(t, clone.ID)
+
+	var cloned []models.RolePermission
+	require.NoError(t, db.Where("role_id = ?", clone.ID).Order("permission_id ASC").Find(&cloned).Error)
+	require.Len(t, cloned, 2)
+	assert.Equal(t, perm1.ID, cloned[0].PermissionID)
+	assert.Equal(t, []string{"read"}, []string(cloned[0].Actions))
+	assert.Equal(t, perm2.ID, cloned[1].PermissionID)
+	assert.Equal(t, []string{"read", "create"}, []string(cloned[1].Actions))
+
+	// The source keeps its own rows
+	var sourceCount int64
+	require.NoError(t, db.Model(&models.RolePermission{}).Where("role_id = ?", source.ID).Count(&sourceCount).Error)
+	assert.Equal(t, int64(2), sourceCount)
+}
+
+// TestCountUsersWithRole_CountsLiveUsersOnly verifies soft-deleted users are not counted
+func TestCountUsersWithRole_CountsLiveUsersOnly(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewRoleRepository(db)
+
+	role := testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = "Cashier"
+	})
+	unused := testutil.CreateTestRole(t, db, func(r *models.Role) {
+		r.Name = "Unused"
+	})
+	testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Roles = []models.Role{*role}
+	})
+	deleted := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Roles = []models.Role{*role}
+	})
+	require.NoError(t, db.Delete(deleted).Error)
+
+	count, err := repo.CountUsersWithRole(role.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, err = repo.CountUsersWithRole(unused.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
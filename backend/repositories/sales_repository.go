@@ -11,7 +11,34 @@ import (
 type SalesRepository interface {
 	Create(tx *models.SalesTransaction) error
 	GetByID(id uint) (*models.SalesTransaction, error)
-	List(params PaginationParams, dateFrom, dateTo string, paymentMethod string) ([]models.SalesTransaction, int64, error)
+	List(params PaginationParams, filter SalesListFilter) ([]models.SalesTransaction, SalesListTotals, error)
+	CreateParkedCart(cart *models.ParkedCart) error
+	GetParkedCart(id uint) (*models.ParkedCart, error)
+	ListParkedCarts(params PaginationParams) ([]models.ParkedCart, int64, error)
+	DeleteParkedCart(id uint) (bool, error)
+}
+
+// SalesListFilter narrows a sales transaction list. Empty fields do not
+// filter; all set fields must match.
+type SalesListFilter struct {
+	// DateFrom and DateTo bound the transaction date (YYYY-MM-DD, inclusive).
+	DateFrom string
+	DateTo   string
+	// PaymentMethods matches any of the listed methods.
+	PaymentMethods []string
+	CashierID      *uint
+	// Customer matches part of the customer name, case-insensitively.
+	Customer string
+	// MinTotal and MaxTotal bound the grand total (inclusive).
+	MinTotal *float64
+	MaxTotal *float64
+}
+
+// SalesListTotals summarises every transaction matching a list filter, not
+// just the returned page.
+type SalesListTotals struct {
+	Count      int64
+	GrandTotal float64
 }
 
 // SalesRepositoryImpl implements SalesRepository.
@@ -41,10 +68,11 @@ func (r *SalesRepositoryImpl) GetByID(id uint) (*models.SalesTransaction, error)
 	return &tx, nil
 }
 
-// List returns paginated sales transactions with optional filters.
-func (r *SalesRepositoryImpl) List(params PaginationParams, dateFrom, dateTo string, paymentMethod string) ([]models.SalesTransaction, int64, error) {
+// List returns paginated sales transactions matching the filter and the
+// search term (transaction number), with totals over all matches.
+func (r *SalesRepositoryImpl) List(params PaginationParams, filter SalesListFilter) ([]models.SalesTransaction, SalesListTotals, error) {
 	var transactions []models.SalesTransaction
-	var total int64
+	var totals SalesListTotals
 
 	query := r.db.Model(&models.SalesTransaction{})
 
@@ -55,26 +83,39 @@ func (r *SalesRepositoryImpl) List(params PaginationParams, dateFrom, dateTo str
 	}
 
 	// Filter by date range
-	if dateFrom != "" {
-		if t, err := time.Parse("2006-01-02", dateFrom); err == nil {
+	if filter.DateFrom != "" {
+		if t, err := time.Parse("2006-01-02", filter.DateFrom); err == nil {
 			query = query.Where("date >= ?", t)
 		}
 	}
-	if dateTo != "" {
-		if t, err := time.Parse("2006-01-02", dateTo); err == nil {
+	if filter.DateTo != "" {
+		if t, err := time.Parse("2006-01-02", filter.DateTo); err == nil {
 			// Include the entire end day
 			query = query.Where("date < ?", t.AddDate(0, 0, 1))
 		}
 	}
 
-	// Filter by payment method
-	if paymentMethod != "" {
-		query = query.Where("payment_method = ?", paymentMethod)
+	if len(filter.PaymentMethods) > 0 {
+		query = query.Where("payment_method IN ?", filter.PaymentMethods)
+	}
+	if filter.CashierID != nil {
+		query = query.Where("cashier_id = ?", *filter.CashierID)
+	}
+	if filter.Customer != "" {
+		query = query.Where("customer_name ILIKE ?", "%"+filter.Customer+"%")
+	}
+	if filter.MinTotal != nil {
+		query = query.Where("grand_total >= ?", *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		query = query.Where("grand_total <= ?", *filter.MaxTotal)
 	}
 
-	// Get total count
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+	// Count and sum every match before paginating
+	if err := query.Session(&gorm.Session{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(grand_total), 0) AS grand_total").
+		Scan(&totals).Error; err != nil {
+		return nil, SalesListTotals{}, err
 	}
 
 	// Apply sort (default: date desc)
@@ -100,8 +141,62 @@ func (r *SalesRepositoryImpl) List(params PaginationParams, dateFrom, dateTo str
 		Offset(offset).
 		Limit(params.PageSize).
 		Find(&transactions).Error; err != nil {
+		return nil, SalesListTotals{}, err
+	}
+
+	return transactions, totals, nil
+}
+
+// CreateParkedCart persists a parked cart with its items.
+func (r *SalesRepositoryImpl) CreateParkedCart(cart *models.ParkedCart) error {
+	return r.db.Create(cart).Error
+}
+
+// GetParkedCart loads a parked cart by ID with its items in the order they
+// were added.
+func (r *SalesRepositoryImpl) GetParkedCart(id uint) (*models.ParkedCart, error) {
+	var cart models.ParkedCart
+	err := r.db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).First(&cart, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// ListParkedCarts returns paginated parked carts, newest first. Search
+// matches the label or customer name.
+func (r *SalesRepositoryImpl) ListParkedCarts(params PaginationParams) ([]models.ParkedCart, int64, error) {
+	var carts []models.ParkedCart
+	var total int64
+
+	query := r.db.Model(&models.ParkedCart{})
+	if params.Search != "" {
+		searchPattern := "%" + params.Search + "%"
+		query = query.Where("label ILIKE ? OR customer_name ILIKE ?", searchPattern, searchPattern)
+	}
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	return transactions, total, nil
+	offset := (params.Page - 1) * params.PageSize
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(params.PageSize).
+		Find(&carts).Error; err != nil {
+		return nil, 0, err
+	}
+	return carts, total, nil
+}
+
+// DeleteParkedCart deletes a parked cart and its items. It reports whether
+// the cart existed.
+func (r *SalesRepositoryImpl) DeleteParkedCart(id uint) (bool, error) {
+	result := r.db.Delete(&models.ParkedCart{}, id)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
 }
@@ -10,7 +10,6 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-
 func TestCreateSalesTransaction_Valid_CreatesWithItems(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	repo := NewSalesRepository(db)
@@ -118,9 +117,9 @@ func TestListSalesTransactions_Pagination_Works(t *testing.T) {
 	}
 
 	params := PaginationParams{Page: 1, PageSize: 2, SortBy: "date", SortDir: "desc"}
-	list, total, err := repo.List(params, "", "", "")
+	list, totals, err := repo.List(params, SalesListFilter{})
 	require.NoError(t, err)
-	assert.Equal(t, int64(3), total)
+	assert.Equal(t, int64(3), totals.Count)
 	assert.Len(t, list, 2)
 }
 
@@ -153,9 +152,9 @@ func TestListSalesTransactions_FilterByDate_Works(t *testing.T) {
 
 	dateFrom := today.Format("2006-01-02")
 	params := PaginationParams{Page: 1, PageSize: 10, SortBy: "date", SortDir: "desc"}
-	list, total, err := repo.List(params, dateFrom, "", "")
+	list, totals, err := repo.List(params, SalesListFilter{DateFrom: dateFrom})
 	require.NoError(t, err)
-	assert.Equal(t, int64(1), total)
+	assert.Equal(t, int64(1), totals.Count)
 	assert.Len(t, list, 1)
 	assert.Equal(t, "TRX-2026-TODAY1", list[0].TransactionNumber)
 }
@@ -185,9 +184,9 @@ func TestListSalesTransactions_FilterByPaymentMethod_Works(t *testing.T) {
 	require.NoError(t, repo.Create(txCard))
 
 	params := PaginationParams{Page: 1, PageSize: 10, SortBy: "date", SortDir: "desc"}
-	list, total, err := repo.List(params, "", "", "card")
+	list, totals, err := repo.List(params, SalesListFilter{PaymentMethods: []string{"card"}})
 	require.NoError(t, err)
-	assert.Equal(t, int64(1), total)
+	assert.Equal(t, int64(1), totals.Count)
 	assert.Len(t, list, 1)
 	assert.Equal(t, "card", list[0].PaymentMethod)
 }
@@ -217,9 +216,96 @@ func TestListSalesTransactions_SearchByNumber_Works(t *testing.T) {
 	require.NoError(t, repo.Create(tx2))
 
 	params := PaginationParams{Page: 1, PageSize: 10, Search: "SRCH01", SortBy: "date", SortDir: "desc"}
-	list, total, err := repo.List(params, "", "", "")
+	list, totals, err := repo.List(params, SalesListFilter{})
 	require.NoError(t, err)
-	assert.Equal(t, int64(1), total)
+	assert.Equal(t, int64(1), totals.Count)
 	assert.Len(t, list, 1)
 	assert.Contains(t, list[0].TransactionNumber, "SRCH01")
 }
+
+func TestListSalesTransactions_CombinedFilters_ReturnsPageAndGrandTotal(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewSalesRepository(db)
+
+	cashier := testutil.CreateTestUser(t, db)
+	other := testutil.CreateTestUser(t, db)
+	today := time.Now()
+
+	seeds := []struct {
+		number   string
+		date     time.Time
+		method   string
+		cashier  uint
+		customer string
+		total    float64
+	}{
+		{"TRX-2026-CMB001", today, "card", cashier.ID, "Budi Santoso", 50000},
+		{"TRX-2026-CMB002", today, "qris", cashier.ID, "Budi Hartono", 75000},
+		{"TRX-2026-CMB003", today, "card", cashier.ID, "Budi Santoso", 120000},          // above maxTotal
+		{"TRX-2026-CMB004", today, "cash", cashier.ID, "Budi Santoso", 60000},           // cash
+		{"TRX-2026-CMB005", today, "card", other.ID, "Budi Santoso", 55000},             // other cashier
+		{"TRX-2026-CMB006", today, "card", cashier.ID, "Siti", 65000},                   // other customer
+		{"TRX-2026-CMB007", today.AddDate(0, 0, -3), "card", cashier.ID, "Budi", 45000}, // before dateFrom
+		{"TRX-2026-CMB008", today, "qris", cashier.ID, "budi", 20000},                   // below minTotal
+	}
+	for _, seed := range seeds {
+		cashierID := seed.cashier
+		require.NoError(t, repo.Create(&models.SalesTransaction{
+			TransactionNumber: seed.number,
+			Date:              seed.date,
+			Subtotal:          seed.total,
+			GrandTotal:        seed.total,
+			TotalItems:        1,
+			PaymentMethod:     seed.method,
+			CashierID:         &cashierID,
+			CustomerName:      seed.customer,
+		}))
+	}
+
+	minTotal, maxTotal := 30000.0, 100000.0
+	filter := SalesListFilter{
+		DateFrom:       today.AddDate(0, 0, -1).Format("2006-01-02"),
+		DateTo:         today.Format("2006-01-02"),
+		PaymentMethods: []string{"card", "qris"},
+		CashierID:      &cashier.ID,
+		Customer:       "budi",
+		MinTotal:       &minTotal,
+		MaxTotal:       &maxTotal,
+	}
+
+	params := PaginationParams{Page: 1, PageSize: 1, SortBy: "grand_total", SortDir: "desc"}
+	list, totals, err := repo.List(params, filter)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), totals.Count)
+	assert.Equal(t, 125000.0, totals.GrandTotal)
+	require.Len(t, list, 1)
+	assert.Equal(t, "TRX-2026-CMB002", list[0].TransactionNumber)
+
+	params.Page = 2
+	list, totals, err = repo.List(params, filter)
+	require.NoError(t, err)
+	assert.Equal(t, 125000.0, totals.GrandTotal)
+	require.Len(t, list, 1)
+	assert.Equal(t, "TRX-2026-CMB001", list[0].TransactionNumber)
+}
+
+func TestListSalesTransactions_NoMatches_ReturnsZeroTotals(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewSalesRepository(db)
+
+	require.NoError(t, repo.Create(&models.SalesTransaction{
+		TransactionNumber: "TRX-2026-NONE01",
+		Date:              time.Now(),
+		Subtotal:          10000,
+		GrandTotal:        10000,
+		TotalItems:        1,
+		PaymentMethod:     "cash",
+	}))
+
+	params := PaginationParams{Page: 1, PageSize: 10, SortBy: "date", SortDir: "desc"}
+	list, totals, err := repo.List(params, SalesListFilter{Customer: "nobody"})
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	assert.Equal(t, int64(0), totals.Count)
+	assert.Equal(t, 0.0, totals.GrandTotal)
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StockAdjustmentListItem is a stock adjustment enriched with product, variant
+// and actor context.
+type StockAdjustmentListItem struct {
+	ID            uint      `json:"id"`
+	VariantID     string    `json:"variantId"`
+	ProductID     uint      `json:"productId"`
+	ProductName   string    `json:"productName"`
+	SKU           string    `json:"sku,omitempty"`
+	VariantLabel  string    `json:"variantLabel"`
+	PreviousStock int       `json:"previousStock"`
+	NewStock      int       `json:"newStock"`
+	Quantity      int       `json:"quantity"`
+	Reason        string    `json:"reason"`
+	ActorID       *uint     `json:"actorId,omitempty"`
+	ActorName     *string   `json:"actorName,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// StockAdjustmentRepository defines the interface for stock adjustment queries
+type StockAdjustmentRepository interface {
+	List(page, pageSize int, variantID string) ([]StockAdjustmentListItem, int64, error)
+}
+
+// StockAdjustmentRepositoryImpl implements StockAdjustmentRepository
+type StockAdjustmentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewStockAdjustmentRepository creates a new stock adjustment repository instance
+func NewStockAdjustmentRepository(db *gorm.DB) *StockAdjustmentRepositoryImpl {
+	return &StockAdjustmentRepositoryImpl{db: db}
+}
+
+// List returns a page of stock adjustments, newest first, optionally limited
+// to one variant.
+func (r *StockAdjustmentRepositoryImpl) List(page, pageSize int, variantID string) ([]StockAdjustmentListItem, int64, error) {
+	items := make([]StockAdjustmentListItem, 0)
+	var total int64
+
+	query := r.db.Table("stock_adjustments sa").
+		Joins("JOIN product_variants pv ON pv.id = sa.variant_id").
+		Joins("JOIN products p ON p.id = pv.product_id").
+		Joins("LEFT JOIN users u ON u.id = sa.created_by")
+	if variantID != "" {
+		query = query.Where("sa.variant_id = ?", variantID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Select(`sa.id, sa.variant_id, p.id AS product_id, p.name AS product_name, pv.sku,
+			COALESCE((SELECT string_agg(va.attribute_value, ' / ' ORDER BY va.id)
+				FROM variant_attributes va WHERE va.variant_id = pv.id), 'Default') AS variant_label,
+			sa.previous_stock, sa.new_stock, sa.quantity, sa.reason, sa.created_at,
+			u.id AS actor_id, u.name AS actor_name`).
+		Order("sa.created_at DESC, sa.id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
@@ -1,15 +1,53 @@
 package repositories
 
 import (
+	"time"
+
 	"github.com/pointofsale/backend/models"
 	"gorm.io/gorm"
 )
 
+// StockMovementFeedItem is a stock movement enriched with product, variant, and actor context.
+type StockMovementFeedItem struct {
+	ID            uint      `json:"id"`
+	VariantID     string    `json:"variantId"`
+	MovementType  string    `json:"movementType"`
+	Quantity      int       `json:"quantity"`
+	ReferenceType string    `json:"referenceType,omitempty"`
+	ReferenceID   *uint     `json:"referenceId,omitempty"`
+	Notes         string    `json:"notes,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	ProductID     uint      `json:"productId"`
+	ProductName   string    `json:"productName"`
+	SKU           string    `json:"sku,omitempty"`
+	VariantLabel  string    `json:"variantLabel"`
+	ActorID       *uint     `json:"actorId,omitempty"`
+	ActorName     *string   `json:"actorName,omitempty"`
+}
+
+// StockLedgerEntry is a stock movement with the variant's running balance
+// after it was applied. The balance is anchored to the variant's current
+// stock, so stock the variant had before its first movement is included.
+type StockLedgerEntry struct {
+	ID            uint      `json:"id"`
+	MovementType  string    `json:"movementType"`
+	Quantity      int       `json:"quantity"`
+	ReferenceType string    `json:"referenceType,omitempty"`
+	ReferenceID   *uint     `json:"referenceId,omitempty"`
+	Notes         string    `json:"notes,omitempty"`
+	CreatedBy     *uint     `json:"createdBy,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Balance       int       `json:"balance"`
+}
+
 // StockMovementRepository defines the interface for stock movement data operations
 type StockMovementRepository interface {
 	Create(movement *models.StockMovement) error
 	GetByVariant(variantID string) ([]models.StockMovement, error)
 	GetByReference(referenceType string, referenceID uint) ([]models.StockMovement, error)
+	RecentFeed(limit int) ([]StockMovementFeedItem, error)
+	ListByVariant(variantID string, params PaginationParams) ([]StockLedgerEntry, int64, error)
+	FindVariant(variantID string) (*models.ProductVariant, error)
 }
 
 // StockMovementRepositoryImpl implements StockMovementRepository
@@ -52,3 +90,63 @@ func (r *StockMovementRepositoryImpl) GetByReference(referenceType string, refer
 	}
 	return movements, nil
 }
+
+// RecentFeed returns the latest stock movements across all variants, newest first.
+func (r *StockMovementRepositoryImpl) RecentFeed(limit int) ([]StockMovementFeedItem, error) {
+	items := make([]StockMovementFeedItem, 0, limit)
+	err := r.db.Table("stock_movements sm").
+		Select(`sm.id, sm.variant_id, sm.movement_type, sm.quantity, sm.reference_type, sm.reference_id,
+			sm.notes, sm.created_at, p.id AS product_id, p.name AS product_name, pv.sku,
+			COALESCE((SELECT string_agg(va.attribute_value, ' / ' ORDER BY va.id)
+				FROM variant_attributes va WHERE va.variant_id = pv.id), 'Default') AS variant_label,
+			u.id AS actor_id, u.name AS actor_name`).
+		Joins("JOIN product_variants pv ON pv.id = sm.variant_id").
+		Joins("JOIN products p ON p.id = pv.product_id").
+		Joins("LEFT JOIN users u ON u.id = sm.created_by").
+		Order("sm.created_at DESC, sm.id DESC").
+		Limit(limit).
+		Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListByVariant returns a page of a variant's stock movements with the running
+// balance after each one: current stock less every later movement. The
+// balance is always worked out chronologically; params.SortDir only controls
+// the order the page is returned in.
+func (r *StockMovementRepositoryImpl) ListByVariant(variantID string, params PaginationParams) ([]StockLedgerEntry, int64, error) {
+	var total int64
+	if err := r.db.Model(&models.StockMovement{}).Where("variant_id = ?", variantID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	ledger := r.db.Table("stock_movements sm").
+		Select(`sm.id, sm.movement_type, sm.quantity, sm.reference_type, sm.reference_id, sm.notes,
+			sm.created_by, sm.created_at,
+			pv.current_stock - SUM(sm.quantity) OVER ()
+				+ SUM(sm.quantity) OVER (ORDER BY sm.created_at ASC, sm.id ASC) AS balance`).
+		Joins("JOIN product_variants pv ON pv.id = sm.variant_id").
+		Where("sm.variant_id = ?", variantID)
+
+	entries := make([]StockLedgerEntry, 0, params.PageSize)
+	err := r.db.Table("(?) AS ledger", ledger).
+		Order(params.SortBy + " " + params.SortDir + ", id " + params.SortDir).
+		Offset((params.Page - 1) * params.PageSize).
+		Limit(params.PageSize).
+		Scan(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// FindVariant loads a variant without nested relations.
+func (r *StockMovementRepositoryImpl) FindVariant(variantID string) (*models.ProductVariant, error) {
+	var variant models.ProductVariant
+	if err := r.db.Where("id = ?", variantID).First(&variant).Error; err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
@@ -1,8 +1,11 @@
 package repositories
 
 import (
+	"time"
+
 	"testing"
 
+	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -98,3 +101,70 @@ func TestGetStockMovementsByReference_ReturnsMatching(t *testing.T) {
 		assert.Equal(t, poID, *m.ReferenceID)
 	}
 }
+
+func TestRecentFeed_ReturnsNewestFirstWithLimit(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewStockMovementRepository(db)
+
+	product := testutil.CreateTestProduct(t, db)
+	otherProduct := testutil.CreateTestProduct(t, db)
+
+	base := time.Now().Add(-time.Hour)
+	seeds := []*models.StockMovement{
+		testutil.NewStockMovement(product.Variants[0].ID, "purchase_receive", 20, "purchase_order", nil, ""),
+		testutil.NewStockMovement(otherProduct.Variants[0].ID, "sales", -3, "sales_transaction", nil, ""),
+		testutil.NewStockMovement(product.Variants[0].ID, "adjustment", -1, "manual", nil, ""),
+		testutil.NewStockMovement(otherProduct.Variants[0].ID, "purchase_receive", 10, "purchase_order", nil, ""),
+	}
+	for i, movement := range seeds {
+		movement.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.Create(movement))
+	}
+
+	items, err := repo.RecentFeed(3)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	assert.Equal(t, seeds[3].ID, items[0].ID)
+	assert.Equal(t, seeds[2].ID, items[1].ID)
+	assert.Equal(t, seeds[1].ID, items[2].ID)
+	assert.Equal(t, otherProduct.Name, items[0].ProductName)
+	assert.Equal(t, "adjustment", items[1].MovementType)
+}
+
+func TestListStockMovementsByVariant_ComputesRunningBalance(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewStockMovementRepository(db)
+
+	product := testutil.CreateTestProduct(t, db)
+	variantID := product.Variants[0].ID
+	otherProduct := testutil.CreateTestProduct(t, db)
+	// 10 units were seeded before the ledger started
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", variantID).Update("current_stock", 25).Error)
+
+	base := time.Now().Add(-time.Hour)
+	seeds := []*models.StockMovement{
+		testutil.NewStockMovement(variantID, "purchase_receive", 20, "purchase_order", nil, ""),
+		testutil.NewStockMovement(variantID, "sales", -3, "sales_transaction", nil, ""),
+		testutil.NewStockMovement(otherProduct.Variants[0].ID, "purchase_receive", 50, "purchase_order", nil, ""),
+		testutil.NewStockMovement(variantID, "adjustment", -2, "stock_adjustment", nil, ""),
+	}
+	for i, movement := range seeds {
+		movement.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.Create(movement))
+	}
+
+	entries, total, err := repo.ListByVariant(variantID, PaginationParams{Page: 1, PageSize: 10, SortBy: "created_at", SortDir: "asc"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []int{30, 27, 25}, []int{entries[0].Balance, entries[1].Balance, entries[2].Balance})
+
+	// Newest first keeps balances accumulated chronologically
+	entries, _, err = repo.ListByVariant(variantID, PaginationParams{Page: 1, PageSize: 2, SortBy: "created_at", SortDir: "desc"})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, seeds[3].ID, entries[0].ID)
+	assert.Equal(t, 25, entries[0].Balance)
+	assert.Equal(t, 27, entries[1].Balance)
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"github.com/pointofsale/backend/models"
+	"gorm.io/gorm"
+)
+
+// StockSnapshotRepository defines the interface for stock snapshot data operations.
+type StockSnapshotRepository interface {
+	Capture(date string) (int64, error)
+	ListByDate(date string) ([]models.StockSnapshot, error)
+}
+
+// StockSnapshotRepositoryImpl implements StockSnapshotRepository.
+type StockSnapshotRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewStockSnapshotRepository creates a new stock snapshot repository instance.
+func NewStockSnapshotRepository(db *gorm.DB) *StockSnapshotRepositoryImpl {
+	return &StockSnapshotRepositoryImpl{db: db}
+}
+
+// Capture records the current stock of every variant under date (YYYY-MM-DD)
+// in a single statement, so all rows reflect the same instant. Capturing a
+// date again replaces its rows; it reports the number of variants written.
+func (r *StockSnapshotRepositoryImpl) Capture(date string) (int64, error) {
+	result := r.db.Exec(`
+		INSERT INTO stock_snapshots
+			(snapshot_date, variant_id, product_id, product_name, variant_label, sku, quantity, cost_price, valuation, created_at)
+		SELECT ?::date, pv.id, p.id, p.name,
+			COALESCE((SELECT string_agg(va.attribute_value, ' / ' ORDER BY va.id)
+				FROM variant_attributes va WHERE va.variant_id = pv.id), 'Default'),
+			COALESCE(pv.sku, ''), pv.current_stock, pv.cost_price,
+			ROUND(pv.current_stock * COALESCE(pv.cost_price, 0), 2), NOW()
+		FROM product_variants pv
+		JOIN products p ON p.id = pv.product_id
+		ON CONFLICT (snapshot_date, variant_id) DO UPDATE SET
+			product_id = EXCLUDED.product_id,
+			product_name = EXCLUDED.product_name,
+			variant_label = EXCLUDED.variant_label,
+			sku = EXCLUDED.sku,
+			quantity = EXCLUDED.quantity,
+			cost_price = EXCLUDED.cost_price,
+			valuation = EXCLUDED.valuation,
+			created_at = EXCLUDED.created_at`, date)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+// ListByDate returns the snapshot rows of date (YYYY-MM-DD) ordered by
+// product name and variant label.
+func (r *StockSnapshotRepositoryImpl) ListByDate(date string) ([]models.StockSnapshot, error) {
+	rows := make([]models.StockSnapshot, 0)
+	err := r.db.Model(&models.StockSnapshot{}).
+		Select(`id, TO_CHAR(snapshot_date, 'YYYY-MM-DD') AS snapshot_date, variant_id, product_id,
+			product_name, variant_label, sku, quantity, cost_price, valuation, created_at`).
+		Where("snapshot_date = ?", date).
+		Order("product_name ASC, variant_label ASC, variant_id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
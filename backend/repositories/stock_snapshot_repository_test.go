@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStockSnapshotCapture_RecordsEachVariant(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewStockSnapshotRepository(db)
+
+	costed := testutil.CreateTestProduct(t, db)
+	cost := 2500.0
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", costed.Variants[0].ID).Update("cost_price", cost).Error)
+	uncosted := testutil.CreateTestProduct(t, db)
+
+	var variantCount int64
+	require.NoError(t, db.Model(&models.ProductVariant{}).Count(&variantCount).Error)
+
+	written, err := repo.Capture("2026-03-01")
+	require.NoError(t, err)
+	assert.Equal(t, variantCount, written)
+
+	rows, err := repo.ListByDate("2026-03-01")
+	require.NoError(t, err)
+	assert.Len(t, rows, int(variantCount))
+
+	byVariant := make(map[string]models.StockSnapshot, len(rows))
+	for _, row := range rows {
+		byVariant[row.VariantID] = row
+	}
+	costedRow := byVariant[costed.Variants[0].ID]
+	assert.Equal(t, "2026-03-01", costedRow.SnapshotDate)
+	assert.Equal(t, costed.Name, costedRow.ProductName)
+	assert.Equal(t, 100, costedRow.Quantity)
+	require.NotNil(t, costedRow.CostPrice)
+	assert.InDelta(t, 250000, costedRow.Valuation, 0.001)
+
+	uncostedRow := byVariant[uncosted.Variants[0].ID]
+	assert.Nil(t, uncostedRow.CostPrice)
+	assert.Zero(t, uncostedRow.Valuation)
+}
+
+func TestStockSnapshotListByDate_PastDate_KeepsFrozenValues(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewStockSnapshotRepository(db)
+	product := testutil.CreateTestProduct(t, db)
+	variantID := product.Variants[0].ID
+
+	_, err := repo.Capture("2026-03-01")
+	require.NoError(t, err)
+
+	// Stock moves after the snapshot and a later day is captured
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", variantID).Update("current_stock", 40).Error)
+	_, err = repo.Capture("2026-03-02")
+	require.NoError(t, err)
+
+	past, err := repo.ListByDate("2026-03-01")
+	require.NoError(t, err)
+	later, err := repo.ListByDate("2026-03-02")
+	require.NoError(t, err)
+
+	assert.Equal(t, 100, findSnapshot(t, past, variantID).Quantity)
+	assert.Equal(t, 40, findSnapshot(t, later, variantID).Quantity)
+}
+
+func TestStockSnapshotCapture_SameDateTwice_ReplacesRows(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewStockSnapshotRepository(db)
+	product := testutil.CreateTestProduct(t, db)
+	variantID := product.Variants[0].ID
+
+	_, err := repo.Capture("2026-03-01")
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", variantID).Update("current_stock", 60).Error)
+	_, err = repo.Capture("2026-03-01")
+	require.NoError(t, err)
+
+	rows, err := repo.ListByDate("2026-03-01")
+	require.NoError(t, err)
+
+	var count int
+	for _, row := range rows {
+		if row.VariantID == variantID {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 60, findSnapshot(t, rows, variantID).Quantity)
+}
+
+func findSnapshot(t *testing.T, rows []models.StockSnapshot, variantID string) models.StockSnapshot {
+	t.Helper()
+	for _, row := range rows {
+		if row.VariantID == variantID {
+			return row
+		}
+	}
+	t.Fatalf("no snapshot for variant %s", variantID)
+	return models.StockSnapshot{}
+}
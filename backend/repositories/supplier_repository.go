@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"strings"
+	"time"
 
 	"github.com/pointofsale/backend/models"
 	"gorm.io/gorm"
@@ -16,6 +17,11 @@ type SupplierRepository interface {
 	Delete(id uint) error
 	CountPurchaseOrdersBySupplierID(supplierID uint) (int64, error)
 	CleanupProductSuppliers(supplierID uint) error
+	ListReceivedPurchaseOrders(supplierID uint, from, to time.Time) ([]models.PurchaseOrder, error)
+	ListPurchaseOrders(supplierID uint, from, to time.Time) ([]models.PurchaseOrder, error)
+	ListUnpaidPurchaseOrders(supplierID uint) ([]models.PurchaseOrder, error)
+	EmailExists(email string, excludeID uint) (bool, error)
+	NameExists(name string) (bool, error)
 }
 
 // SupplierRepositoryImpl implements SupplierRepository interface
@@ -174,6 +180,66 @@ func (r *SupplierRepositoryImpl) CleanupProductSuppliers(supplierID uint) error
 	return err
 }
 
+// ListReceivedPurchaseOrders returns the supplier's received or completed purchase
+// orders, with items, whose received date falls within [from, to].
+func (r *SupplierRepositoryImpl) ListReceivedPurchaseOrders(supplierID uint, from, to time.Time) ([]models.PurchaseOrder, error) {
+	var orders []models.PurchaseOrder
+	err := r.db.Preload("Items").
+		Where("supplier_id = ?", supplierID).
+		Where("status IN ?", []string{"received", "completed"}).
+		Where("received_date >= ? AND received_date < ?", from, to.AddDate(0, 0, 1)).
+		Order("received_date ASC").
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// ListUnpaidPurchaseOrders returns the supplier's purchase orders, with items,
+// that have had goods received but are not yet paid, oldest receipt first.
+func (r *SupplierRepositoryImpl) ListUnpaidPurchaseOrders(supplierID uint) ([]models.PurchaseOrder, error) {
+	var orders []models.PurchaseOrder
+	err := r.db.Preload("Items").
+		Where("supplier_id = ?", supplierID).
+		Where("status IN ?", []string{"partially_received", "received", "completed"}).
+		Where("paid_at IS NULL").
+		Order("received_date ASC, id ASC").
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// ListPurchaseOrders returns the supplier's purchase orders of any status,
+// with items, whose PO date falls within [from, to].
+func (r *SupplierRepositoryImpl) ListPurchaseOrders(supplierID uint, from, to time.Time) ([]models.PurchaseOrder, error) {
+	var orders []models.PurchaseOrder
+	err := r.db.Preload("Items").
+		Where("supplier_id = ?", supplierID).
+		Where("date >= ? AND date <= ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("date ASC, id ASC").
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// EmailExists reports whether another supplier uses the email, ignoring case.
+// Pass excludeID 0 when creating a supplier.
+func (r *SupplierRepositoryImpl) EmailExists(email string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Supplier{}).
+		Where("LOWER(email) = LOWER(?) AND id <> ?", email, excludeID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // isTableNotExistsError checks if the error is a "relation does not exist" PostgreSQL error
 func isTableNotExistsError(err error) bool {
 	if err == nil {
@@ -181,3 +247,15 @@ func isTableNotExistsError(err error) bool {
 	}
 	return strings.Contains(err.Error(), "42P01") || strings.Contains(err.Error(), "does not exist")
 }
+
+// NameExists reports whether a supplier with the name exists, ignoring case.
+func (r *SupplierRepositoryImpl) NameExists(name string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Supplier{}).
+		Where("LOWER(name) = LOWER(?)", name).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -226,3 +226,19 @@ func TestDeleteSupplier_CascadesBankAccounts(t *testing.T) {
 	db.Model(&models.SupplierBankAccount{}).Where("supplier_id = ?", supplier.ID).Count(&count)
 	assert.Equal(t, int64(0), count)
 }
+
+func TestSupplierEmailExists_CaseInsensitiveAndExcludesSelf(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	repo := NewSupplierRepository(db)
+
+	supplier := &models.Supplier{Name: "PT Sumber Makmur", Address: "Jakarta", Email: "Order@SumberMakmur.co.id", Active: true}
+	require.NoError(t, repo.Create(supplier))
+
+	exists, err := repo.EmailExists("order@sumbermakmur.co.id", 0)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repo.EmailExists("order@sumbermakmur.co.id", supplier.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
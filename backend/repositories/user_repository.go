@@ -1,6 +1,9 @@
 package repositories
 
 import (
+	"strings"
+	"time"
+
 	"github.com/pointofsale/backend/models"
 	"gorm.io/gorm"
 )
@@ -21,11 +24,17 @@ type UserRepository interface {
 	FindByID(id uint) (*models.User, error)
 	Update(user *models.User) error
 	FindByIDWithPermissions(id uint) (*models.User, []models.RolePermission, error)
+	ListWithPermissions() ([]models.User, []models.RolePermission, error)
 	// NEW for Stage 3:
 	List(params PaginationParams, status string) ([]models.User, int64, error)
 	Delete(id uint) error
 	SyncRoles(userID uint, roleIDs []uint) error
 	FindByEmailExcluding(email string, excludeID uint) (*models.User, error)
+	RecentPasswordHashes(userID uint, limit int) ([]string, error)
+	AddPasswordHistory(userID uint, passwordHash string, keep int) error
+	UpdateLastLogin(userID uint, at time.Time) error
+	FindRolesByNames(names []string) ([]models.Role, error)
+	RestoreUser(id uint) error
 }
 
 // UserRepositoryImpl implements UserRepository interface
@@ -102,6 +111,25 @@ func (r *UserRepositoryImpl) FindByIDWithPermissions(id uint) (*models.User, []m
 	return &user, rolePermissions, nil
 }
 
+// ListWithPermissions returns all users with their roles, plus the role
+// permissions of every role assigned to them
+func (r *UserRepositoryImpl) ListWithPermissions() ([]models.User, []models.RolePermission, error) {
+	var users []models.User
+	if err := r.db.Preload("Roles").Order("id ASC").Find(&users).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var rolePermissions []models.RolePermission
+	err := r.db.Where("role_id IN (SELECT role_id FROM user_roles)").
+		Preload("Permission").
+		Find(&rolePermissions).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return users, rolePermissions, nil
+}
+
 // List returns paginated users with optional search and status filter
 func (r *UserRepositoryImpl) List(params PaginationParams, status string) ([]models.User, int64, error) {
 	var users []models.User
@@ -110,10 +138,10 @@ func (r *UserRepositoryImpl) List(params PaginationParams, status string) ([]mod
 	// Build base query
 	query := r.db.Model(&models.User{})
 
-	// Apply search filter (name OR email, case-insensitive partial match)
+	// Apply search filter (name OR email OR phone, case-insensitive partial match)
 	if params.Search != "" {
 		searchPattern := "%" + params.Search + "%"
-		query = query.Where("LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?)", searchPattern, searchPattern)
+		query = query.Where("LOWER(name) LIKE LOWER(?) OR LOWER(email) LIKE LOWER(?) OR LOWER(phone) LIKE LOWER(?)", searchPattern, searchPattern, searchPattern)
 	}
 
 	// Apply status filter
@@ -127,8 +155,7 @@ func (r *UserRepositoryImpl) List(params PaginationParams, status string) ([]mod
 	}
 
 	// Apply sorting
-	orderClause := params.SortBy + " " + params.SortDir
-	query = query.Order(orderClause)
+	query = query.Order(userOrderClause(params))
 
 	// Apply pagination
 	offset := (params.Page - 1) * params.PageSize
@@ -145,11 +172,70 @@ func (r *UserRepositoryImpl) List(params PaginationParams, status string) ([]mod
 	return users, total, nil
 }
 
-// Delete removes a user from the database
+// userOrderClause maps the requested sort field to a column, falling back to
+// id. Ties are broken by id so that pages do not overlap or skip users. Users
+// who never logged in sort last when ordering by last login.
+func userOrderClause(params PaginationParams) string {
+	sortDir := "asc"
+	if strings.ToLower(params.SortDir) == "desc" {
+		sortDir = "desc"
+	}
+
+	switch params.SortBy {
+	case "name", "email", "status":
+		return params.SortBy + " " + sortDir + ", id " + sortDir
+	case "createdAt", "created_at":
+		return "created_at " + sortDir + ", id " + sortDir
+	case "lastLoginAt", "last_login_at":
+		return "last_login_at " + sortDir + " NULLS LAST, id " + sortDir
+	default:
+		return "id " + sortDir
+	}
+}
+
+// FindRolesByNames returns the roles whose names match, ignoring case
+func (r *UserRepositoryImpl) FindRolesByNames(names []string) ([]models.Role, error) {
+	var roles []models.Role
+	if len(names) == 0 {
+		return roles, nil
+	}
+	lowered := make([]string, 0, len(names))
+	for _, name := range names {
+		lowered = append(lowered, strings.ToLower(name))
+	}
+	if err := r.db.Where("LOWER(name) IN ?", lowered).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// UpdateLastLogin records when the user last logged in successfully
+func (r *UserRepositoryImpl) UpdateLastLogin(userID uint, at time.Time) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).UpdateColumn("last_login_at", at).Error
+}
+
+// Delete soft-deletes a user. The row and its role assignments are kept for
+// audit history, but the user no longer appears in lookups or lists.
 func (r *UserRepositoryImpl) Delete(id uint) error {
 	return r.db.Delete(&models.User{}, id).Error
 }
 
+// RestoreUser brings back a soft-deleted user with their roles intact. It
+// returns gorm.ErrRecordNotFound when no deleted user has the ID, and fails
+// if a live user has since taken the email.
+func (r *UserRepositoryImpl) RestoreUser(id uint) error {
+	result := r.db.Unscoped().Model(&models.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 // SyncRoles replaces a user's roles with a new set
 func (r *UserRepositoryImpl) SyncRoles(userID uint, roleIDs []uint) error {
 	// Find user
@@ -178,7 +264,8 @@ func (r *UserRepositoryImpl) SyncRoles(userID uint, roleIDs []uint) error {
 	return r.db.Model(&user).Association("Roles").Append(roles)
 }
 
-// FindByEmailExcluding finds a user by email (case-insensitive), excluding a specific user ID
+// FindByEmailExcluding finds a user by email (case-insensitive), excluding a specific user ID.
+// Soft-deleted users are ignored, so their emails do not conflict.
 func (r *UserRepositoryImpl) FindByEmailExcluding(email string, excludeID uint) (*models.User, error) {
 	var user models.User
 	err := r.db.Where("LOWER(email) = LOWER(?) AND id != ?", email, excludeID).
@@ -189,3 +276,31 @@ func (r *UserRepositoryImpl) FindByEmailExcluding(email string, excludeID uint)
 	}
 	return &user, nil
 }
+
+// RecentPasswordHashes returns up to limit of the user's previous password hashes, newest first
+func (r *UserRepositoryImpl) RecentPasswordHashes(userID uint, limit int) ([]string, error) {
+	var hashes []string
+	err := r.db.Model(&models.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Pluck("password_hash", &hashes).Error
+	return hashes, err
+}
+
+// AddPasswordHistory records a replaced password hash and prunes all but the newest keep entries
+func (r *UserRepositoryImpl) AddPasswordHistory(userID uint, passwordHash string, keep int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		entry := &models.PasswordHistory{UserID: userID, PasswordHash: passwordHash}
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		keepIDs := tx.Model(&models.PasswordHistory{}).
+			Select("id").
+			Where("user_id = ?", userID).
+			Order("created_at DESC, id DESC").
+			Limit(keep)
+		return tx.Where("user_id = ? AND id NOT IN (?)", userID, keepIDs).
+			Delete(&models.PasswordHistory{}).Error
+	})
+}
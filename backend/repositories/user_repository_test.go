@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/testutil"
@@ -414,6 +415,82 @@ func TestListUsers_SearchByEmail_FiltersCorrectly(t *testing.T) {
 	assert.Len(t, users, 2)
 }
 
+func TestListUsers_SearchByPartialPhone_FiltersCorrectly(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewUserRepository(db)
+
+	testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Name = "Alice"
+		u.Email = "alice@example.com"
+		u.Phone = "+62 812-3456-7890"
+	})
+	testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Name = "Bob"
+		u.Email = "bob@example.com"
+		u.Phone = "+62 813-0000-1111"
+	})
+	testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Name = "Charlie"
+		u.Email = "charlie@example.com"
+	})
+
+	// Search for a fragment of Alice's number
+	params := PaginationParams{
+		Page:     1,
+		PageSize: 10,
+		Search:   "3456",
+		SortBy:   "id",
+		SortDir:  "asc",
+	}
+
+	users, total, err := repo.List(params, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total, "should find 1 user with '3456' in phone")
+	require.Len(t, users, 1)
+	assert.Equal(t, "Alice", users[0].Name)
+}
+
+func TestListUsers_SearchMatchesNameEmailAndPhone(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewUserRepository(db)
+
+	testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Name = "Kasir Satu"
+		u.Email = "one@example.com"
+	})
+	testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Name = "Bob"
+		u.Email = "kasir@example.com"
+	})
+	testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Name = "Charlie"
+		u.Email = "charlie@example.com"
+		u.Phone = "0812-KASIR"
+	})
+	testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Name = "Dave"
+		u.Email = "dave@example.com"
+		u.Phone = "0812-0000"
+	})
+
+	params := PaginationParams{
+		Page:     1,
+		PageSize: 10,
+		Search:   "kasir",
+		SortBy:   "id",
+		SortDir:  "asc",
+	}
+
+	users, total, err := repo.List(params, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total, "should match by name, email and phone")
+	assert.Len(t, users, 3)
+}
+
 func TestListUsers_FilterByStatus_ReturnsMatchingOnly(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -492,6 +569,105 @@ func TestListUsers_SortByName_ReturnsOrdered(t *testing.T) {
 	assert.Equal(t, "Alice", users[2].Name)
 }
 
+func TestListUsers_SortByLastLoginAt_NeverLoggedInLast(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewUserRepository(db)
+
+	never := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Name = "Never"
+	})
+	earlier := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Name = "Earlier"
+	})
+	recent := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Name = "Recent"
+	})
+	now := time.Now()
+	require.NoError(t, repo.UpdateLastLogin(earlier.ID, now.Add(-time.Hour)))
+	require.NoError(t, repo.UpdateLastLogin(recent.ID, now))
+
+	params := PaginationParams{
+		Page:     1,
+		PageSize: 10,
+		SortBy:   "lastLoginAt",
+		SortDir:  "desc",
+	}
+
+	users, _, err := repo.List(params, "")
+	require.NoError(t, err)
+	require.Len(t, users, 3)
+	assert.Equal(t, recent.ID, users[0].ID)
+	assert.Equal(t, earlier.ID, users[1].ID)
+	assert.Equal(t, never.ID, users[2].ID)
+	assert.NotNil(t, users[0].LastLoginAt)
+	assert.Nil(t, users[2].LastLoginAt)
+}
+
+func TestListUsers_UnknownSortField_FallsBackToID(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewUserRepository(db)
+
+	first := testutil.CreateTestUser(t, db)
+	testutil.CreateTestUser(t, db)
+
+	params := PaginationParams{
+		Page:     1,
+		PageSize: 10,
+		SortBy:   "password_hash; DROP TABLE users",
+		SortDir:  "asc",
+	}
+
+	users, _, err := repo.List(params, "")
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, first.ID, users[0].ID)
+}
+
+func TestListUsers_SharedSortValue_PagesByID(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewUserRepository(db)
+
+	var ids []uint
+	for i := 0; i < 4; i++ {
+		user := testutil.CreateTestUser(t, db, func(u *models.User) {
+			u.Status = "active"
+		})
+		// Ties on status follow id in the requested direction
+		ids = append([]uint{user.ID}, ids...)
+	}
+
+	var paged []uint
+	for page := 1; page <= 2; page++ {
+		users, _, err := repo.List(PaginationParams{Page: page, PageSize: 2, SortBy: "status", SortDir: "desc"}, "")
+		require.NoError(t, err)
+		for _, u := range users {
+			paged = append(paged, u.ID)
+		}
+	}
+	assert.Equal(t, ids, paged)
+}
+
+func TestFindRolesByNames_IgnoresCase(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewUserRepository(db)
+	cashier := testutil.CreateTestRole(t, db, func(r *models.Role) { r.Name = "Import Cashier" })
+	testutil.CreateTestRole(t, db, func(r *models.Role) { r.Name = "Import Manager" })
+
+	roles, err := repo.FindRolesByNames([]string{"import cashier", "Missing"})
+
+	require.NoError(t, err)
+	require.Len(t, roles, 1)
+	assert.Equal(t, cashier.ID, roles[0].ID)
+}
+
 func TestListUsers_CombinedSearchAndFilter_Works(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer testutil.CleanupTestDB(t, db)
@@ -592,7 +768,7 @@ func TestDeleteUser_Exists_RemovesUser(t *testing.T) {
 	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
 }
 
-func TestDeleteUser_CascadesUserRoles(t *testing.T) {
+func TestDeleteUser_SoftDeletes_KeepsRowAndUserRoles(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	defer testutil.CleanupTestDB(t, db)
 
@@ -610,20 +786,102 @@ func TestDeleteUser_CascadesUserRoles(t *testing.T) {
 	err := db.Model(&user).Association("Roles").Append(role)
 	require.NoError(t, err)
 
-	// Verify user has role
-	foundUser, err := repo.FindByID(user.ID)
-	require.NoError(t, err)
-	assert.Len(t, foundUser.Roles, 1)
-
 	// Delete user
 	err = repo.Delete(user.ID)
 	require.NoError(t, err)
 
-	// Verify user_roles junction table entry is also deleted (cascade)
+	// The row is kept with deleted_at set
+	var deleted models.User
+	require.NoError(t, db.Unscoped().First(&deleted, user.ID).Error)
+	assert.True(t, deleted.DeletedAt.Valid)
+
+	// Role assignments are kept for audit history
 	var count int64
 	err = db.Table("user_roles").Where("user_id = ?", user.ID).Count(&count).Error
 	require.NoError(t, err)
-	assert.Equal(t, int64(0), count, "user_roles entries should be deleted via cascade")
+	assert.Equal(t, int64(1), count)
+
+	// Lookups and lists no longer see the user
+	_, err = repo.FindByEmail(user.Email)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	users, _, err := repo.List(PaginationParams{Page: 1, PageSize: 100, Search: user.Email}, "")
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func TestDeleteUser_SoftDeletedEmail_CanBeReusedByNewRegistration(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewUserRepository(db)
+
+	original := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Email = "reuse@example.com"
+	})
+	require.NoError(t, repo.Delete(original.ID))
+
+	// No live user holds the email any more
+	_, err := repo.FindByEmailExcluding("reuse@example.com", 0)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	newcomer := &models.User{
+		Name:         "New Registrant",
+		Email:        "reuse@example.com",
+		PasswordHash: "hash",
+		Status:       "pending",
+	}
+	require.NoError(t, repo.Create(newcomer))
+
+	found, err := repo.FindByEmail("reuse@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, newcomer.ID, found.ID)
+	assert.NotEqual(t, original.ID, found.ID)
+}
+
+func TestRestoreUser_SoftDeleted_IsFoundAgain(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewUserRepository(db)
+
+	user := testutil.CreateTestUser(t, db)
+	require.NoError(t, repo.Delete(user.ID))
+
+	require.NoError(t, repo.RestoreUser(user.ID))
+
+	found, err := repo.FindByID(user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.Email, found.Email)
+}
+
+func TestRestoreUser_NotDeleted_ReturnsNotFound(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewUserRepository(db)
+	user := testutil.CreateTestUser(t, db)
+
+	err := repo.RestoreUser(user.ID)
+
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestRestoreUser_EmailTakenByLiveUser_ReturnsError(t *testing.T) {
+	db := testutil.SetupTestDBNoTx(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewUserRepository(db)
+	user := testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Email = "taken@example.com"
+	})
+	require.NoError(t, repo.Delete(user.ID))
+	testutil.CreateTestUser(t, db, func(u *models.User) {
+		u.Email = "taken@example.com"
+	})
+
+	err := repo.RestoreUser(user.ID)
+
+	assert.Error(t, err)
 }
 
 func TestSyncRoles_ReplacesExistingRoles(t *testing.T) {
@@ -761,3 +1019,23 @@ func TestFindByEmailExcluding_CaseInsensitive_Works(t *testing.T) {
 	require.NotNil(t, found)
 	assert.Equal(t, user1.ID, found.ID)
 }
+
+func TestAddPasswordHistory_KeepsNewestEntries(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	defer testutil.CleanupTestDB(t, db)
+
+	repo := NewUserRepository(db)
+	user := testutil.CreateTestUser(t, db)
+
+	for _, hash := range []string{"hash-1", "hash-2", "hash-3", "hash-4"} {
+		require.NoError(t, repo.AddPasswordHistory(user.ID, hash, 3))
+	}
+
+	hashes, err := repo.RecentPasswordHashes(user.ID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hash-4", "hash-3", "hash-2"}, hashes)
+
+	hashes, err = repo.RecentPasswordHashes(user.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"hash-4"}, hashes)
+}
@@ -11,6 +11,19 @@ import (
 	"github.com/pointofsale/backend/middleware"
 )
 
+// corsHandler allows the frontend origin to call the API, including the
+// request headers the handlers read.
+func corsHandler(frontendURL string) func(http.Handler) http.Handler {
+	return cors.Handler(cors.Options{
+		AllowedOrigins:   []string{frontendURL},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID", "Receive-Token"},
+		ExposedHeaders:   []string{"X-Request-ID", "Retry-After"},
+		AllowCredentials: true,
+		MaxAge:           300,
+	})
+}
+
 func Setup(
 	r chi.Router,
 	healthHandler *handlers.HealthHandler,
@@ -24,22 +37,31 @@ func Setup(
 	productHandler *handlers.ProductHandler,
 	poHandler *handlers.POHandler,
 	salesHandler *handlers.SalesHandler,
+	stockHandler *handlers.StockHandler,
+	reportHandler *handlers.ReportHandler,
+	alertHandler *handlers.AlertHandler,
+	stockSnapshotHandler *handlers.StockSnapshotHandler,
+	stockAdjustmentHandler *handlers.StockAdjustmentHandler,
+	auditLogHandler *handlers.AuditLogHandler,
 	authMiddleware *middleware.AuthMiddleware,
 	permMiddleware *middleware.PermissionMiddleware,
+	rateLimiter *middleware.RateLimiter,
 	cfg *config.Config,
 ) {
+	// rateLimit applies the configured limit of a route group
+	rateLimit := func(group string) func(http.Handler) http.Handler {
+		limit, ok := cfg.RateLimits[group]
+		if !cfg.RateLimitEnabled || !ok {
+			return func(next http.Handler) http.Handler { return next }
+		}
+		return rateLimiter.Limit(group, limit.Requests, limit.Window)
+	}
+
 	// Global middleware
 	r.Use(chiMiddleware.Recoverer)
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Logger)
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{cfg.FrontendURL},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
-		ExposedHeaders:   []string{"X-Request-ID"},
-		AllowCredentials: true,
-		MaxAge:           300,
-	}))
+	r.Use(corsHandler(cfg.FrontendURL))
 
 	// Health check (no auth required)
 	r.Get("/health", healthHandler.Health)
@@ -57,12 +79,19 @@ func Setup(
 			r.Post("/refresh", authHandler.Refresh)
 			r.Post("/forgot-password", authHandler.ForgotPassword)
 			r.Post("/reset-password", authHandler.ResetPassword)
+			r.Post("/verify-email", authHandler.VerifyEmail)
+			r.Post("/resend-verification", authHandler.ResendVerification)
+			r.Post("/2fa/verify", authHandler.VerifyTwoFactor)
 
 			// Protected auth routes
 			r.Group(func(r chi.Router) {
 				r.Use(authMiddleware.Authenticate)
 				r.Post("/logout", authHandler.Logout)
+				r.Post("/logout-all", authHandler.LogoutAll)
+				r.Get("/sessions", authHandler.ListSessions)
 				r.Get("/me", authHandler.GetMe)
+				r.Post("/2fa/enable", authHandler.EnableTwoFactor)
+				r.Post("/2fa/confirm", authHandler.ConfirmTwoFactor)
 			})
 		})
 
@@ -73,11 +102,17 @@ func Setup(
 			// User management
 			r.Route("/users", func(r chi.Router) {
 				r.With(permMiddleware.RequirePermission("Settings", "Users", "read")).Get("/", userHandler.ListUsers)
+				// Any authenticated user can change their own password
+				r.Patch("/me/password", userHandler.ChangePassword)
+				r.With(permMiddleware.RequirePermission("Settings", "Roles & Permissions", "read")).Get("/access-report/export", userHandler.ExportAccessReport)
+				r.With(permMiddleware.RequirePermission("Settings", "Users", "read")).Get("/export", userHandler.ExportUsers)
 				r.With(permMiddleware.RequirePermission("Settings", "Users", "read")).Get("/{id}", userHandler.GetUser)
 				r.With(permMiddleware.RequirePermission("Settings", "Users", "create")).Post("/", userHandler.CreateUser)
+				r.With(permMiddleware.RequirePermission("Settings", "Users", "create")).Post("/import", userHandler.ImportUsers)
 				r.With(permMiddleware.RequirePermission("Settings", "Users", "update")).Put("/{id}", userHandler.UpdateUser)
 				r.With(permMiddleware.RequirePermission("Settings", "Users", "delete")).Delete("/{id}", userHandler.DeleteUser)
 				r.With(permMiddleware.RequirePermission("Settings", "Users", "update")).Patch("/{id}/approve", userHandler.ApproveUser)
+				r.With(permMiddleware.RequirePermission("Settings", "Users", "update")).Post("/{id}/resend-credentials", userHandler.ResendCredentials)
 				r.With(permMiddleware.RequirePermission("Settings", "Users", "delete")).Delete("/{id}/reject", userHandler.RejectUser)
 				r.With(permMiddleware.RequirePermission("Settings", "Users", "update")).Post("/{id}/profile-picture", userHandler.UploadProfilePicture)
 			})
@@ -89,6 +124,7 @@ func Setup(
 				r.With(permMiddleware.RequirePermission("Settings", "Roles & Permissions", "create")).Post("/", roleHandler.CreateRole)
 				r.With(permMiddleware.RequirePermission("Settings", "Roles & Permissions", "update")).Put("/{id}", roleHandler.UpdateRole)
 				r.With(permMiddleware.RequirePermission("Settings", "Roles & Permissions", "delete")).Delete("/{id}", roleHandler.DeleteRole)
+				r.With(permMiddleware.RequirePermission("Settings", "Roles & Permissions", "create")).Post("/{id}/clone", roleHandler.CloneRole)
 
 				// Role permissions
 				r.With(permMiddleware.RequirePermission("Settings", "Roles & Permissions", "read")).Get("/{id}/permissions", permissionHandler.GetRolePermissions)
@@ -101,6 +137,7 @@ func Setup(
 			// Master Data - Categories
 			r.Route("/categories", func(r chi.Router) {
 				r.With(permMiddleware.RequirePermission("Master Data", "Category", "read")).Get("/", categoryHandler.ListCategories)
+				r.With(permMiddleware.RequirePermission("Master Data", "Category", "read")).Get("/product-counts", categoryHandler.GetProductCounts)
 				r.With(permMiddleware.RequirePermission("Master Data", "Category", "read")).Get("/{id}", categoryHandler.GetCategory)
 				r.With(permMiddleware.RequirePermission("Master Data", "Category", "create")).Post("/", categoryHandler.CreateCategory)
 				r.With(permMiddleware.RequirePermission("Master Data", "Category", "update")).Put("/{id}", categoryHandler.UpdateCategory)
@@ -111,7 +148,11 @@ func Setup(
 			r.Route("/suppliers", func(r chi.Router) {
 				r.With(permMiddleware.RequirePermission("Master Data", "Supplier", "read")).Get("/", supplierHandler.ListSuppliers)
 				r.With(permMiddleware.RequirePermission("Master Data", "Supplier", "read")).Get("/{id}", supplierHandler.GetSupplier)
+				r.With(permMiddleware.RequirePermission("Master Data", "Supplier", "read")).Get("/{id}/performance", supplierHandler.GetSupplierPerformance)
+				r.With(permMiddleware.RequirePermission("Master Data", "Supplier", "read")).Get("/{id}/metrics", supplierHandler.GetSupplierMetrics)
+				r.With(permMiddleware.RequirePermission("Master Data", "Supplier", "read")).Get("/{id}/payables", supplierHandler.GetSupplierPayables)
 				r.With(permMiddleware.RequirePermission("Master Data", "Supplier", "create")).Post("/", supplierHandler.CreateSupplier)
+				r.With(permMiddleware.RequirePermission("Master Data", "Supplier", "create")).Post("/import", supplierHandler.ImportSuppliers)
 				r.With(permMiddleware.RequirePermission("Master Data", "Supplier", "update")).Put("/{id}", supplierHandler.UpdateSupplier)
 				r.With(permMiddleware.RequirePermission("Master Data", "Supplier", "delete")).Delete("/{id}", supplierHandler.DeleteSupplier)
 			})
@@ -120,39 +161,107 @@ func Setup(
 			r.Route("/racks", func(r chi.Router) {
 				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/", rackHandler.ListRacks)
 				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}", rackHandler.GetRack)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}/stock", rackHandler.ListRackStock)
 				r.With(permMiddleware.RequirePermission("Master Data", "Product", "create")).Post("/", rackHandler.CreateRack)
 				r.With(permMiddleware.RequirePermission("Master Data", "Product", "update")).Put("/{id}", rackHandler.UpdateRack)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "update")).Post("/{id}/evacuate", rackHandler.EvacuateRack)
 				r.With(permMiddleware.RequirePermission("Master Data", "Product", "delete")).Delete("/{id}", rackHandler.DeleteRack)
 			})
 
 			// Master Data - Products
 			r.Route("/products", func(r chi.Router) {
+				r.Use(rateLimit("products"))
 				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/", productHandler.ListProducts)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/low-stock", productHandler.ListLowStock)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/variants/{id}/rack-stock", productHandler.GetVariantRackStock)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/variants/{id}/reorder", productHandler.GetVariantReorderDetail)
 				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}", productHandler.GetProduct)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}/full", productHandler.GetProductFullView)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}/overview", productHandler.GetProductOverview)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}/units", productHandler.GetProductUnits)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}/delete-check", productHandler.DeleteCheck)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/{id}/variants/{variantId}/movements", stockHandler.GetVariantLedger)
 				r.With(permMiddleware.RequirePermission("Master Data", "Product", "create")).Post("/", productHandler.CreateProduct)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "create")).Post("/{id}/clone", productHandler.CloneProduct)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "update")).Post("/reorder-points/bulk", productHandler.BulkSetReorderPoints)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Post("/labels", productHandler.GenerateLabels)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Post("/price-change/preview", productHandler.PreviewPriceChange)
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "update")).Post("/{id}/images", productHandler.UploadImage)
 				r.With(permMiddleware.RequirePermission("Master Data", "Product", "update")).Put("/{id}", productHandler.UpdateProduct)
 				r.With(permMiddleware.RequirePermission("Master Data", "Product", "delete")).Delete("/{id}", productHandler.DeleteProduct)
 			})
 
+			// Stock
+			r.Route("/stock", func(r chi.Router) {
+				r.With(permMiddleware.RequirePermission("Master Data", "Product", "read")).Get("/feed", stockHandler.GetFeed)
+			})
+
+			// Transaction - Stock Adjustments
+			r.Route("/stock-adjustments", func(r chi.Router) {
+				r.With(permMiddleware.RequirePermission("Transaction", "Stock Adjustment", "read")).Get("/", stockAdjustmentHandler.ListAdjustments)
+				r.With(permMiddleware.RequirePermission("Transaction", "Stock Adjustment", "create")).Post("/", stockAdjustmentHandler.CreateAdjustment)
+			})
+
+			// Alerts are addressed to roles, so any authenticated user may list theirs
+			r.Route("/alerts", func(r chi.Router) {
+				r.Get("/", alertHandler.ListAlerts)
+				r.Post("/{id}/ack", alertHandler.AcknowledgeAlert)
+			})
+
 			// Transaction - Purchase Orders
 			r.Route("/purchase-orders", func(r chi.Router) {
+				r.Use(rateLimit("purchase-orders"))
 				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/", poHandler.ListPOs)
 				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/products", poHandler.GetProductsForPO)
+				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/last", poHandler.GetLastPOForSupplier)
+				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "create")).Get("/next-number", poHandler.GetNextPONumber)
+				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/discrepancies", poHandler.GetDiscrepancies)
+				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/low-stock-by-supplier", poHandler.GetLowStockBySupplier)
+				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "create")).Post("/low-stock-by-supplier/draft", poHandler.DraftFromLowStock)
 				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/{id}", poHandler.GetPO)
+				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/{id}/pdf", poHandler.GetPOPDF)
 				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "create")).Post("/", poHandler.CreatePO)
 				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Put("/{id}", poHandler.UpdatePO)
 				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "delete")).Delete("/{id}", poHandler.DeletePO)
 				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Patch("/{id}/status", poHandler.UpdatePOStatus)
+				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Post("/{id}/approve", poHandler.ApprovePO)
+				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Post("/{id}/email", poHandler.EmailPO)
+				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "read")).Get("/{id}/attachments", poHandler.ListAttachments)
+				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Post("/{id}/attachments", poHandler.UploadAttachment)
+				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Delete("/{id}/attachments/{attachmentId}", poHandler.DeleteAttachment)
 				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Post("/{id}/receive", poHandler.ReceivePO)
+				r.With(permMiddleware.RequirePermission("Transaction", "Purchase Order", "update")).Post("/{id}/pay", poHandler.MarkPOPaid)
 			})
 
 			// Transaction - Sales
 			r.Route("/sales", func(r chi.Router) {
+				r.Use(rateLimit("sales"))
 				r.With(permMiddleware.RequirePermission("Transaction", "Sale", "read")).Get("/products/search", salesHandler.ProductSearch)
+				r.With(permMiddleware.RequirePermission("Transaction", "Sale", "read")).Get("/payment-methods", salesHandler.PaymentMethods)
+				r.With(permMiddleware.RequirePermission("Transaction", "Sale", "create")).Get("/next-number", salesHandler.GetNextTransactionNumber)
 				r.With(permMiddleware.RequirePermission("Transaction", "Sale", "create")).Post("/checkout", salesHandler.Checkout)
 				r.With(permMiddleware.RequirePermission("Transaction", "Sale", "read")).Get("/transactions", salesHandler.ListTransactions)
 				r.With(permMiddleware.RequirePermission("Transaction", "Sale", "read")).Get("/transactions/{id}", salesHandler.GetTransaction)
+				r.With(permMiddleware.RequirePermission("Transaction", "Sale", "create")).Post("/transactions/{id}/returns", salesHandler.CreateReturn)
+				r.With(permMiddleware.RequirePermission("Transaction", "Sale", "create")).Post("/parked", salesHandler.ParkCart)
+				r.With(permMiddleware.RequirePermission("Transaction", "Sale", "read")).Get("/parked", salesHandler.ListParkedCarts)
+				r.With(permMiddleware.RequirePermission("Transaction", "Sale", "read")).Get("/parked/{id}", salesHandler.ResumeParkedCart)
+				r.With(permMiddleware.RequirePermission("Transaction", "Sale", "create")).Delete("/parked/{id}", salesHandler.DeleteParkedCart)
+			})
+
+			// Reports
+			r.Route("/reports", func(r chi.Router) {
+				r.With(permMiddleware.RequirePermission("Report", "Sales Report", "read")).Get("/sales/timeseries", reportHandler.SalesTimeSeries)
+				r.With(permMiddleware.RequirePermission("Report", "Sales Report", "read")).Get("/heatmap", reportHandler.HourlyHeatmap)
+				r.With(permMiddleware.RequirePermission("Report", "Sales Report", "read")).Get("/profit", reportHandler.ProfitSummary)
+				r.With(permMiddleware.RequirePermission("Report", "Sales Report", "read")).Get("/cashiers", reportHandler.CashierPerformance)
+				r.With(permMiddleware.RequirePermission("Settings", "Roles & Permissions", "read")).Get("/permission-denials", reportHandler.PermissionDenials)
+				r.With(permMiddleware.RequirePermission("Report", "Stock Report", "read")).Get("/stock-snapshot", stockSnapshotHandler.GetSnapshot)
+				r.With(permMiddleware.RequirePermission("Report", "Stock Report", "create")).Post("/stock-snapshot", stockSnapshotHandler.Capture)
 			})
+
+			// Audit logs
+			r.With(permMiddleware.RequirePermission("Settings", "Roles & Permissions", "read")).Get("/audit-logs", auditLogHandler.ListAuditLogs)
 		})
 	})
 }
@@ -79,6 +79,8 @@ func seedPermissions(db *gorm.DB) error {
 		{Module: "Transaction", Feature: "Purchase Order", Actions: pq.StringArray{"create", "read", "update", "delete", "send", "receive"}},
 		{Module: "Transaction", Feature: "Sale", Actions: pq.StringArray{"create", "read", "update", "delete"}},
 		{Module: "Transaction", Feature: "Stock Adjustment", Actions: pq.StringArray{"create", "read", "update", "delete"}},
+		{Module: "Report", Feature: "Sales Report", Actions: pq.StringArray{"read", "export"}},
+		{Module: "Report", Feature: "Stock Report", Actions: pq.StringArray{"read", "create"}},
 		{Module: "Settings", Feature: "Users", Actions: pq.StringArray{"create", "read", "update", "delete"}},
 		{Module: "Settings", Feature: "Roles & Permissions", Actions: pq.StringArray{"create", "read", "update", "delete"}},
 	}
@@ -554,7 +556,7 @@ func seedProducts(db *gorm.DB) error {
 				},
 				{
 					SKU:     "TS-B-M",
-					Barcode: "8901234567891",
+					Barcode: "8901234567883",
 					Attributes: []services.CreateVariantAttributeInput{
 						{AttributeName: "Color", AttributeValue: "Blue"},
 						{AttributeName: "Size", AttributeValue: "M"},
@@ -583,7 +585,7 @@ func seedProducts(db *gorm.DB) error {
 			Variants: []services.CreateProductVariantInput{
 				{
 					SKU:     "RC-001",
-					Barcode: "8901234567800",
+					Barcode: "8901234567807",
 					PricingTiers: []services.CreateVariantPricingTierInput{
 						{MinQty: 1, Value: 15000},
 						{MinQty: 50, Value: 14000},
@@ -608,7 +610,7 @@ func seedProducts(db *gorm.DB) error {
 			Variants: []services.CreateProductVariantInput{
 				{
 					SKU:     "NB-001",
-					Barcode: "8901234567700",
+					Barcode: "8901234567708",
 					PricingTiers: []services.CreateVariantPricingTierInput{
 						{MinQty: 1, Value: 25},
 					},
@@ -629,7 +631,7 @@ func seedProducts(db *gorm.DB) error {
 			Variants: []services.CreateProductVariantInput{
 				{
 					SKU:     "CO-001",
-					Barcode: "8901234567600",
+					Barcode: "8901234567609",
 					PricingTiers: []services.CreateVariantPricingTierInput{
 						{MinQty: 1, Value: 28000},
 					},
@@ -835,6 +837,11 @@ func seedPurchaseOrders(db *gorm.DB) error {
 		}
 		slog.Info("created stock movements for received POs")
 
+		// The notebook is markup-priced, so it sells once priced from its receipts
+		if err := services.RepriceMarkupTiers(tx, nbVariant.ID); err != nil {
+			return err
+		}
+
 		return nil
 	})
 }
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"gorm.io/gorm"
+)
+
+// Alert types raised by the background checks
+const (
+	AlertTypeLowStock   = "low_stock"
+	AlertTypeOverduePO  = "overdue_po"
+	AlertTypeStockDrift = "stock_drift"
+)
+
+// alertRoleWarehouse is the role the inventory and purchasing alerts are
+// addressed to.
+const alertRoleWarehouse = "Warehouse"
+
+// AlertServiceRepository defines repository methods needed by AlertService.
+type AlertServiceRepository interface {
+	CreateIfAbsent(alert *models.Alert) (bool, error)
+	ListOpen(roles []string, allRoles bool) ([]models.Alert, error)
+	FindByID(id uint) (*models.Alert, error)
+	Acknowledge(id, userID uint, at time.Time) (bool, error)
+	ClearResolved(alertType string, activeKeys []string, at time.Time) error
+	UserRoleNames(userID uint) ([]string, error)
+	ListLowStockVariants() ([]repositories.LowStockVariant, error)
+	ListOverduePurchaseOrders(today string) ([]repositories.OverduePurchaseOrder, error)
+	ListRackAllocationDrift() ([]repositories.RackAllocationDrift, error)
+}
+
+// AlertService raises alerts from periodic checks and lets users acknowledge
+// the ones addressed to their roles.
+type AlertService struct {
+	repo AlertServiceRepository
+	cfg  AlertConfig
+	now  func() time.Time
+}
+
+// AlertConfig holds tunable behaviour for AlertService.
+type AlertConfig struct {
+	// Location is the store's timezone, which decides the date a purchase
+	// order becomes overdue. Nil means UTC.
+	Location *time.Location
+}
+
+// NewAlertService creates a new alert service instance.
+func NewAlertService(repo AlertServiceRepository, cfg ...AlertConfig) *AlertService {
+	var alertCfg AlertConfig
+	if len(cfg) > 0 {
+		alertCfg = cfg[0]
+	}
+	return &AlertService{repo: repo, cfg: alertCfg, now: time.Now}
+}
+
+// location returns the store's timezone, UTC when none is configured.
+func (s *AlertService) location() *time.Location {
+	if s.cfg.Location == nil {
+		return time.UTC
+	}
+	return s.cfg.Location
+}
+
+// ListOpen returns the unacknowledged alerts addressed to the user's roles.
+// Super admins see every open alert.
+func (s *AlertService) ListOpen(userID uint, isSuperAdmin bool) ([]models.Alert, error) {
+	roles, err := s.repo.UserRoleNames(userID)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to load user roles", Code: "INTERNAL_ERROR"}
+	}
+	alerts, err := s.repo.ListOpen(roles, isSuperAdmin)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to load alerts", Code: "INTERNAL_ERROR"}
+	}
+	return alerts, nil
+}
+
+// Acknowledge closes an open alert on behalf of everyone it was addressed to.
+// Alerts for roles the user does not hold are reported as not found.
+func (s *AlertService) Acknowledge(id, userID uint, isSuperAdmin bool) error {
+	alert, err := s.repo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errAlertNotFound()
+		}
+		return &ServiceError{Err: err, Message: "Failed to load alert", Code: "INTERNAL_ERROR"}
+	}
+
+	if !isSuperAdmin {
+		roles, err := s.repo.UserRoleNames(userID)
+		if err != nil {
+			return &ServiceError{Err: err, Message: "Failed to load user roles", Code: "INTERNAL_ERROR"}
+		}
+		addressed := false
+		for _, role := range roles {
+			if role == alert.Role {
+				addressed = true
+				break
+			}
+		}
+		if !addressed {
+			return errAlertNotFound()
+		}
+	}
+
+	if alert.AcknowledgedAt != nil {
+		return &ServiceError{Err: ErrConflict, Message: "Alert is already acknowledged", Code: "ALERT_ACKNOWLEDGED"}
+	}
+	acknowledged, err := s.repo.Acknowledge(id, userID, s.now())
+	if err != nil {
+		return &ServiceError{Err: err, Message: "Failed to acknowledge alert", Code: "INTERNAL_ERROR"}
+	}
+	if !acknowledged {
+		return &ServiceError{Err: ErrConflict, Message: "Alert is already acknowledged", Code: "ALERT_ACKNOWLEDGED"}
+	}
+	return nil
+}
+
+func errAlertNotFound() *ServiceError {
+	return &ServiceError{Err: ErrNotFound, Message: "Alert not found", Code: "ALERT_NOT_FOUND"}
+}
+
+// raise stores the alert unless the same condition already has an alert that
+// has not been cleared.
+func (s *AlertService) raise(alert *models.Alert) (bool, error) {
+	return s.repo.CreateIfAbsent(alert)
+}
+
+// clearResolved lets conditions of alertType that no longer hold alert again
+// once their acknowledged alert is cleared.
+func (s *AlertService) clearResolved(alertType string, activeKeys []string) error {
+	return s.repo.ClearResolved(alertType, activeKeys, s.now())
+}
+
+// CheckLowStock raises a Warehouse alert for each variant at or below its
+// reorder point. It returns the number of new alerts.
+func (s *AlertService) CheckLowStock() (int, error) {
+	variants, err := s.repo.ListLowStockVariants()
+	if err != nil {
+		return 0, err
+	}
+	created := 0
+	keys := make([]string, 0, len(variants))
+	for _, v := range variants {
+		name := v.ProductName
+		if v.SKU != "" {
+			name = fmt.Sprintf("%s (%s)", v.ProductName, v.SKU)
+		}
+		key := AlertTypeLowStock + ":" + v.VariantID
+		keys = append(keys, key)
+		ok, err := s.raise(&models.Alert{
+			Type:     AlertTypeLowStock,
+			Role:     alertRoleWarehouse,
+			Title:    "Low stock: " + name,
+			Message:  fmt.Sprintf("%s has %d in stock, at or below its reorder point of %d.", name, v.CurrentStock, v.ReorderPoint),
+			DedupKey: key,
+		})
+		if err != nil {
+			return created, err
+		}
+		if ok {
+			created++
+		}
+	}
+	return created, s.clearResolved(AlertTypeLowStock, keys)
+}
+
+// CheckOverduePurchaseOrders raises a Warehouse alert for each sent or
+// partially received PO whose expected date has passed in the store's
+// timezone. It returns the number of new alerts.
+func (s *AlertService) CheckOverduePurchaseOrders() (int, error) {
+	orders, err := s.repo.ListOverduePurchaseOrders(s.now().In(s.location()).Format("2006-01-02"))
+	if err != nil {
+		return 0, err
+	}
+	created := 0
+	keys := make([]string, 0, len(orders))
+	for _, po := range orders {
+		key := fmt.Sprintf("%s:%d", AlertTypeOverduePO, po.ID)
+		keys = append(keys, key)
+		ok, err := s.raise(&models.Alert{
+			Type:     AlertTypeOverduePO,
+			Role:     alertRoleWarehouse,
+			Title:    "Overdue purchase order " + po.PONumber,
+			Message:  fmt.Sprintf("%s from %s was expected on %s and has not been received.", po.PONumber, po.SupplierName, po.ExpectedDate),
+			DedupKey: key,
+		})
+		if err != nil {
+			return created, err
+		}
+		if ok {
+			created++
+		}
+	}
+	return created, s.clearResolved(AlertTypeOverduePO, keys)
+}
+
+// CheckStockDrift raises a Warehouse alert for each variant whose rack
+// quantities add up to more than its current stock. It returns the number of
+// new alerts.
+func (s *AlertService) CheckStockDrift() (int, error) {
+	variants, err := s.repo.ListRackAllocationDrift()
+	if err != nil {
+		return 0, err
+	}
+	created := 0
+	keys := make([]string, 0, len(variants))
+	for _, v := range variants {
+		name := v.ProductName
+		if v.SKU != "" {
+			name = fmt.Sprintf("%s (%s)", v.ProductName, v.SKU)
+		}
+		key := AlertTypeStockDrift + ":" + v.VariantID
+		keys = append(keys, key)
+		ok, err := s.raise(&models.Alert{
+			Type:     AlertTypeStockDrift,
+			Role:     alertRoleWarehouse,
+			Title:    "Rack quantities out of sync: " + name,
+			Message:  fmt.Sprintf("Racks hold %d of %s but current stock is %d.", v.Allocated, name, v.CurrentStock),
+			DedupKey: key,
+		})
+		if err != nil {
+			return created, err
+		}
+		if ok {
+			created++
+		}
+	}
+	return created, s.clearResolved(AlertTypeStockDrift, keys)
+}
+
+// RunChecks runs every alert check once, logging failures.
+func (s *AlertService) RunChecks() {
+	checks := []struct {
+		name string
+		run  func() (int, error)
+	}{
+		{"low_stock", s.CheckLowStock},
+		{"overdue_po", s.CheckOverduePurchaseOrders},
+		{"stock_drift", s.CheckStockDrift},
+	}
+	for _, check := range checks {
+		created, err := check.run()
+		if err != nil {
+			slog.Error("alert check failed", "check", check.name, "error", err)
+			continue
+		}
+		if created > 0 {
+			slog.Info("alerts raised", "check", check.name, "count", created)
+		}
+	}
+}
+
+// Start runs the alert checks immediately and then every interval until ctx
+// is cancelled.
+func (s *AlertService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		s.RunChecks()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.RunChecks()
+			}
+		}
+	}()
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// mockAlertRepo keeps alerts in memory and enforces one uncleared alert per
+// dedup key.
+type mockAlertRepo struct {
+	alerts    []models.Alert
+	userRoles map[uint][]string
+	lowStock  []repositories.LowStockVariant
+	overdue   []repositories.OverduePurchaseOrder
+	drift     []repositories.RackAllocationDrift
+	today     string
+}
+
+func (m *mockAlertRepo) CreateIfAbsent(alert *models.Alert) (bool, error) {
+	for _, existing := range m.alerts {
+		if existing.DedupKey == alert.DedupKey && existing.ClearedAt == nil {
+			return false, nil
+		}
+	}
+	alert.ID = uint(len(m.alerts) + 1)
+	m.alerts = append(m.alerts, *alert)
+	return true, nil
+}
+
+func (m *mockAlertRepo) ListOpen(roles []string, allRoles bool) ([]models.Alert, error) {
+	open := make([]models.Alert, 0)
+	for _, alert := range m.alerts {
+		if alert.AcknowledgedAt != nil {
+			continue
+		}
+		addressed := allRoles
+		for _, role := range roles {
+			addressed = addressed || role == alert.Role
+		}
+		if addressed {
+			open = append(open, alert)
+		}
+	}
+	return open, nil
+}
+
+func (m *mockAlertRepo) FindByID(id uint) (*models.Alert, error) {
+	for i := range m.alerts {
+		if m.alerts[i].ID == id {
+			alert := m.alerts[i]
+			return &alert, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockAlertRepo) Acknowledge(id, userID uint, at time.Time) (bool, error) {
+	for i := range m.alerts {
+		if m.alerts[i].ID == id && m.alerts[i].AcknowledgedAt == nil {
+			m.alerts[i].AcknowledgedAt = &at
+			m.alerts[i].AcknowledgedBy = &userID
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockAlertRepo) ClearResolved(alertType string, activeKeys []string, at time.Time) error {
+	active := make(map[string]bool, len(activeKeys))
+	for _, key := range activeKeys {
+		active[key] = true
+	}
+	for i := range m.alerts {
+		alert := &m.alerts[i]
+		if alert.Type == alertType && alert.ClearedAt == nil && alert.AcknowledgedAt != nil && !active[alert.DedupKey] {
+			alert.ClearedAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *mockAlertRepo) UserRoleNames(userID uint) ([]string, error) {
+	return m.userRoles[userID], nil
+}
+
+func (m *mockAlertRepo) ListLowStockVariants() ([]repositories.LowStockVariant, error) {
+	return m.lowStock, nil
+}
+
+func (m *mockAlertRepo) ListOverduePurchaseOrders(today string) ([]repositories.OverduePurchaseOrder, error) {
+	m.today = today
+	return m.overdue, nil
+}
+
+func (m *mockAlertRepo) ListRackAllocationDrift() ([]repositories.RackAllocationDrift, error) {
+	return m.drift, nil
+}
+
+const (
+	alertWarehouseUser = uint(1)
+	alertCashierUser   = uint(2)
+)
+
+func newAlertTestService() (*AlertService, *mockAlertRepo) {
+	repo := &mockAlertRepo{
+		userRoles: map[uint][]string{
+			alertWarehouseUser: {"Warehouse"},
+			alertCashierUser:   {"Cashier"},
+		},
+		lowStock: []repositories.LowStockVariant{
+			{VariantID: "variant-a", ProductName: "Coffee", SKU: "COF-1", CurrentStock: 2, ReorderPoint: 5},
+		},
+	}
+	return NewAlertService(repo), repo
+}
+
+func TestCheckLowStock_CreatesAlertVisibleToWarehouseUsers(t *testing.T) {
+	service, _ := newAlertTestService()
+
+	created, err := service.CheckLowStock()
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+
+	alerts, err := service.ListOpen(alertWarehouseUser, false)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, AlertTypeLowStock, alerts[0].Type)
+	assert.Equal(t, "Low stock: Coffee (COF-1)", alerts[0].Title)
+	assert.Contains(t, alerts[0].Message, "has 2 in stock")
+
+	cashierAlerts, err := service.ListOpen(alertCashierUser, false)
+	require.NoError(t, err)
+	assert.Empty(t, cashierAlerts)
+}
+
+func TestCheckLowStock_RepeatedRun_DoesNotDuplicateOpenAlert(t *testing.T) {
+	service, repo := newAlertTestService()
+
+	_, err := service.CheckLowStock()
+	require.NoError(t, err)
+	created, err := service.CheckLowStock()
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+	assert.Len(t, repo.alerts, 1)
+}
+
+func TestAcknowledge_RemovesAlertFromOpenList(t *testing.T) {
+	service, repo := newAlertTestService()
+	_, err := service.CheckLowStock()
+	require.NoError(t, err)
+
+	err = service.Acknowledge(repo.alerts[0].ID, alertWarehouseUser, false)
+	require.NoError(t, err)
+
+	alerts, err := service.ListOpen(alertWarehouseUser, false)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+	require.NotNil(t, repo.alerts[0].AcknowledgedBy)
+	assert.Equal(t, alertWarehouseUser, *repo.alerts[0].AcknowledgedBy)
+
+	// A condition that persists stays acknowledged
+	created, err := service.CheckLowStock()
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+	alerts, err = service.ListOpen(alertWarehouseUser, false)
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+}
+
+func TestCheckLowStock_AcknowledgedConditionClearsAndRecurs_RaisesAgain(t *testing.T) {
+	service, repo := newAlertTestService()
+	lowStock := repo.lowStock
+	_, err := service.CheckLowStock()
+	require.NoError(t, err)
+	require.NoError(t, service.Acknowledge(repo.alerts[0].ID, alertWarehouseUser, false))
+
+	repo.lowStock = nil
+	created, err := service.CheckLowStock()
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+	require.NotNil(t, repo.alerts[0].ClearedAt)
+
+	repo.lowStock = lowStock
+	created, err = service.CheckLowStock()
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	alerts, err := service.ListOpen(alertWarehouseUser, false)
+	require.NoError(t, err)
+	assert.Len(t, alerts, 1)
+}
+
+func TestAcknowledge_AlertForOtherRole_ReturnsNotFound(t *testing.T) {
+	service, repo := newAlertTestService()
+	_, err := service.CheckLowStock()
+	require.NoError(t, err)
+
+	err = service.Acknowledge(repo.alerts[0].ID, alertCashierUser, false)
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrNotFound, svcErr.Err)
+	assert.Nil(t, repo.alerts[0].AcknowledgedAt)
+}
+
+func TestAcknowledge_AlreadyAcknowledged_ReturnsConflict(t *testing.T) {
+	service, repo := newAlertTestService()
+	_, err := service.CheckLowStock()
+	require.NoError(t, err)
+	require.NoError(t, service.Acknowledge(repo.alerts[0].ID, alertWarehouseUser, false))
+
+	err = service.Acknowledge(repo.alerts[0].ID, alertWarehouseUser, false)
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrConflict, svcErr.Err)
+}
+
+func TestCheckOverduePurchaseOrders_UsesTodayAndCreatesAlert(t *testing.T) {
+	service, repo := newAlertTestService()
+	service.now = func() time.Time { return time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC) }
+	repo.overdue = []repositories.OverduePurchaseOrder{
+		{ID: 7, PONumber: "PO-2024-0007", SupplierName: "Acme", ExpectedDate: "2024-06-01"},
+	}
+
+	created, err := service.CheckOverduePurchaseOrders()
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, "2024-06-10", repo.today)
+	assert.Equal(t, "overdue_po:7", repo.alerts[0].DedupKey)
+	assert.Equal(t, "Warehouse", repo.alerts[0].Role)
+}
+
+func TestCheckOverduePurchaseOrders_UsesStoreTimezoneDate(t *testing.T) {
+	repo := &mockAlertRepo{}
+	service := NewAlertService(repo, AlertConfig{Location: time.FixedZone("WIB", 7*60*60)})
+	// 20:00 UTC on 10 June is already 11 June in the store
+	service.now = func() time.Time { return time.Date(2024, 6, 10, 20, 0, 0, 0, time.UTC) }
+
+	_, err := service.CheckOverduePurchaseOrders()
+
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06-11", repo.today)
+}
+
+func TestCheckStockDrift_CreatesAlert(t *testing.T) {
+	service, repo := newAlertTestService()
+	repo.drift = []repositories.RackAllocationDrift{
+		{VariantID: "variant-b", ProductName: "Tea", CurrentStock: 3, Allocated: 8},
+	}
+
+	created, err := service.CheckStockDrift()
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, "Racks hold 8 of Tea but current stock is 3.", repo.alerts[0].Message)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/pointofsale/backend/utils"
+)
+
+// The checks below keep prices and quantities on PO, product and sales inputs
+// from going negative. Messages name the offending field, e.g.
+// "items[2].price must not be negative", so clients can point at the input.
+
+// firstViolation returns the first non-empty validation message.
+func firstViolation(messages ...string) string {
+	for _, message := range messages {
+		if message != "" {
+			return message
+		}
+	}
+	return ""
+}
+
+// fieldError wraps a field validation message as a validation error.
+func fieldError(message string) *ServiceError {
+	return &ServiceError{Err: ErrValidation, Message: message, Code: "VALIDATION_ERROR"}
+}
+
+// validateCartQuantities checks that every checkout or parked cart line asks
+// for a positive quantity.
+func validateCartQuantities(items []CheckoutItemInput) *ServiceError {
+	for i, item := range items {
+		if msg := utils.ValidatePositive(float64(item.Quantity), fmt.Sprintf("items[%d].quantity", i)); msg != "" {
+			return fieldError(msg)
+		}
+	}
+	return nil
+}
+
+// validateCartDiscounts checks the line discounts of checkout or parked cart
+// lines and the sale discount. A line may take a percentage or an amount off,
+// not both.
+func validateCartDiscounts(items []CheckoutItemInput, discount float64) *ServiceError {
+	for i, item := range items {
+		if item.DiscountPercent < 0 || item.DiscountPercent > 100 {
+			return fieldError(fmt.Sprintf("items[%d].discountPercent must be between 0 and 100", i))
+		}
+		if msg := utils.ValidateNonNegative(item.DiscountAmount, fmt.Sprintf("items[%d].discountAmount", i)); msg != "" {
+			return fieldError(msg)
+		}
+		if item.DiscountPercent > 0 && item.DiscountAmount > 0 {
+			return fieldError(fmt.Sprintf("items[%d] can have either discountPercent or discountAmount, not both", i))
+		}
+	}
+	if msg := utils.ValidateNonNegative(discount, "discount"); msg != "" {
+		return fieldError(msg)
+	}
+	return nil
+}
+
+// validatePOItemAmounts checks PO lines for a positive ordered quantity and a
+// non-negative price.
+func validatePOItemAmounts(items []CreatePOItemInput) *ServiceError {
+	for i, item := range items {
+		field := fmt.Sprintf("items[%d]", i)
+		if msg := firstViolation(
+			utils.ValidatePositive(float64(item.OrderedQty), field+".orderedQty"),
+			utils.ValidateNonNegative(item.Price, field+".price"),
+		); msg != "" {
+			return fieldError(msg)
+		}
+	}
+	return nil
+}
+
+// validateReceiveItemAmounts checks received PO lines for a non-negative
+// quantity and price.
+func validateReceiveItemAmounts(items []ReceivePOItemInput) *ServiceError {
+	for i, item := range items {
+		field := fmt.Sprintf("items[%d]", i)
+		if msg := firstViolation(
+			utils.ValidateNonNegative(float64(item.ReceivedQty), field+".receivedQty"),
+			utils.ValidateNonNegative(item.ReceivedPrice, field+".receivedPrice"),
+		); msg != "" {
+			return fieldError(msg)
+		}
+	}
+	return nil
+}
+
+// validateReceiveAdjustments checks that a PO tax percentage is between 0 and
+// 100 and that a discount is not negative. Nil values are not checked.
+func validateReceiveAdjustments(taxPercent, discount *float64) *ServiceError {
+	if taxPercent != nil && (*taxPercent < 0 || *taxPercent > 100) {
+		return fieldError("taxPercent must be between 0 and 100")
+	}
+	if discount != nil {
+		if msg := utils.ValidateNonNegative(*discount, "discountAmount"); msg != "" {
+			return fieldError(msg)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePOItemAmounts_RejectsNegativeAndZeroValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []CreatePOItemInput
+		want  string
+	}{
+		{"negative price", []CreatePOItemInput{{OrderedQty: 1, Price: 100}, {OrderedQty: 1, Price: -1}}, "items[1].price must not be negative"},
+		{"zero quantity", []CreatePOItemInput{{OrderedQty: 0, Price: 100}}, "items[0].orderedQty must be greater than zero"},
+		{"negative quantity", []CreatePOItemInput{{OrderedQty: -3, Price: 100}}, "items[0].orderedQty must be greater than zero"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svcErr := validatePOItemAmounts(tt.items)
+			require.NotNil(t, svcErr)
+			assert.Equal(t, ErrValidation, svcErr.Err)
+			assert.Equal(t, "VALIDATION_ERROR", svcErr.Code)
+			assert.Equal(t, tt.want, svcErr.Message)
+		})
+	}
+}
+
+func TestValidateReceiveItemAmounts_AllowsZeroRejectsNegative(t *testing.T) {
+	assert.Nil(t, validateReceiveItemAmounts([]ReceivePOItemInput{{ReceivedQty: 0, ReceivedPrice: 0}}))
+
+	svcErr := validateReceiveItemAmounts([]ReceivePOItemInput{{ReceivedQty: 2, ReceivedPrice: 10}, {ReceivedQty: -1, ReceivedPrice: 10}})
+	require.NotNil(t, svcErr)
+	assert.Equal(t, "items[1].receivedQty must not be negative", svcErr.Message)
+
+	svcErr = validateReceiveItemAmounts([]ReceivePOItemInput{{ReceivedQty: 1, ReceivedPrice: -10}})
+	require.NotNil(t, svcErr)
+	assert.Equal(t, "items[0].receivedPrice must not be negative", svcErr.Message)
+}
+
+func TestValidateCartQuantities_RejectsNonPositive(t *testing.T) {
+	assert.Nil(t, validateCartQuantities([]CheckoutItemInput{{Quantity: 1}, {Quantity: 3}}))
+
+	svcErr := validateCartQuantities([]CheckoutItemInput{{Quantity: 1}, {Quantity: 0}})
+	require.NotNil(t, svcErr)
+	assert.Equal(t, "items[1].quantity must be greater than zero", svcErr.Message)
+}
+
+func TestValidateReceiveAdjustments(t *testing.T) {
+	zero, eleven, over, negative := 0.0, 11.0, 100.5, -1.0
+	assert.Nil(t, validateReceiveAdjustments(nil, nil))
+	assert.Nil(t, validateReceiveAdjustments(&zero, &zero))
+	assert.Nil(t, validateReceiveAdjustments(&eleven, &eleven))
+
+	svcErr := validateReceiveAdjustments(&over, nil)
+	require.NotNil(t, svcErr)
+	assert.Equal(t, "taxPercent must be between 0 and 100", svcErr.Message)
+
+	svcErr = validateReceiveAdjustments(&negative, nil)
+	require.NotNil(t, svcErr)
+	assert.Equal(t, "taxPercent must be between 0 and 100", svcErr.Message)
+
+	svcErr = validateReceiveAdjustments(nil, &negative)
+	require.NotNil(t, svcErr)
+	assert.Equal(t, "discountAmount must not be negative", svcErr.Message)
+}
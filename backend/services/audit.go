@@ -0,0 +1,48 @@
+package services
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strconv"
+
+	"github.com/pointofsale/backend/models"
+)
+
+// AuditRecorder stores audit log entries. The audit log repository
+// implements it.
+type AuditRecorder interface {
+	Create(entry *models.AuditLog) error
+}
+
+// auditSnapshot returns the JSON state of v for an audit entry, or nil when
+// it cannot be encoded.
+func auditSnapshot(v interface{}) models.JSONPayload {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// recordChange writes an audit entry for a change to an entity, with its
+// state before and after. A nil recorder records nothing. Failures are logged
+// and never fail the change.
+func recordChange(recorder AuditRecorder, event string, actorID uint, entity string, entityID uint, before, after models.JSONPayload) {
+	if recorder == nil {
+		return
+	}
+
+	entry := &models.AuditLog{
+		Event:    event,
+		Entity:   entity,
+		EntityID: strconv.FormatUint(uint64(entityID), 10),
+		Before:   before,
+		After:    after,
+	}
+	if actorID != 0 {
+		entry.UserID = &actorID
+	}
+	if err := recorder.Create(entry); err != nil {
+		slog.Error("failed to audit change", "event", event, "entity", entity, "entity_id", entityID, "error", err)
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pointofsale/backend/repositories"
+)
+
+// AuditLogServiceRepository defines repository methods needed by AuditLogService.
+type AuditLogServiceRepository interface {
+	Search(page, pageSize int, filter repositories.AuditLogFilter) ([]repositories.AuditLogListItem, int64, error)
+}
+
+// AuditLogSearchInput holds the audit log search criteria. From and To are
+// dates (inclusive, UTC); a zero date leaves that end of the range open.
+type AuditLogSearchInput struct {
+	Search  string
+	ActorID *uint
+	Action  string
+	Entity  string
+	From    time.Time
+	To      time.Time
+}
+
+// AuditLogService handles audit log queries.
+type AuditLogService struct {
+	repo AuditLogServiceRepository
+}
+
+// NewAuditLogService creates a new audit log service instance.
+func NewAuditLogService(repo AuditLogServiceRepository) *AuditLogService {
+	return &AuditLogService{repo: repo}
+}
+
+// Search returns a page of audit log entries matching input, newest first.
+// The search text matches values inside the before and after payloads, so
+// searching for a value that was set finds the entry that changed it.
+func (s *AuditLogService) Search(page, pageSize int, input AuditLogSearchInput) ([]repositories.AuditLogListItem, int64, error) {
+	filter := repositories.AuditLogFilter{
+		Search:  strings.TrimSpace(input.Search),
+		ActorID: input.ActorID,
+		Action:  strings.TrimSpace(input.Action),
+		Entity:  strings.TrimSpace(input.Entity),
+	}
+	if !input.From.IsZero() {
+		filter.From = truncateToDate(input.From)
+	}
+	if !input.To.IsZero() {
+		filter.To = truncateToDate(input.To).AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, 0, &ServiceError{
+			Err:     ErrValidation,
+			Message: "'from' must not be after 'to'",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	items, total, err := s.repo.Search(page, pageSize, filter)
+	if err != nil {
+		return nil, 0, &ServiceError{
+			Err:     err,
+			Message: "Failed to list audit logs",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	return items, total, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pointofsale/backend/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAuditLogRepo struct {
+	searchFn func(int, int, repositories.AuditLogFilter) ([]repositories.AuditLogListItem, int64, error)
+}
+
+func (m *mockAuditLogRepo) Search(page, pageSize int, filter repositories.AuditLogFilter) ([]repositories.AuditLogListItem, int64, error) {
+	if m.searchFn != nil {
+		return m.searchFn(page, pageSize, filter)
+	}
+	return nil, 0, nil
+}
+
+func TestAuditLogSearch_DateRange_IncludesWholeToDay(t *testing.T) {
+	var got repositories.AuditLogFilter
+	repo := &mockAuditLogRepo{searchFn: func(page, pageSize int, filter repositories.AuditLogFilter) ([]repositories.AuditLogListItem, int64, error) {
+		got = filter
+		return nil, 0, nil
+	}}
+	actorID := uint(5)
+
+	_, _, err := NewAuditLogService(repo).Search(1, 10, AuditLogSearchInput{
+		Search:  "  rina@example.com ",
+		ActorID: &actorID,
+		Action:  "user_updated",
+		From:    time.Date(2026, 3, 1, 15, 0, 0, 0, time.UTC),
+		To:      time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "rina@example.com", got.Search)
+	assert.Equal(t, &actorID, got.ActorID)
+	assert.Equal(t, "user_updated", got.Action)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), got.From)
+	assert.Equal(t, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), got.To)
+}
+
+func TestAuditLogSearch_OpenRange_LeavesBoundsZero(t *testing.T) {
+	var got repositories.AuditLogFilter
+	repo := &mockAuditLogRepo{searchFn: func(page, pageSize int, filter repositories.AuditLogFilter) ([]repositories.AuditLogListItem, int64, error) {
+		got = filter
+		return nil, 0, nil
+	}}
+
+	_, _, err := NewAuditLogService(repo).Search(1, 10, AuditLogSearchInput{})
+
+	require.NoError(t, err)
+	assert.True(t, got.From.IsZero())
+	assert.True(t, got.To.IsZero())
+}
+
+func TestAuditLogSearch_FromAfterTo_ReturnsValidation(t *testing.T) {
+	_, _, err := NewAuditLogService(&mockAuditLogRepo{}).Search(1, 10, AuditLogSearchInput{
+		From: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+}
@@ -20,6 +20,9 @@ type UserRepository interface {
 	FindByID(id uint) (*models.User, error)
 	Update(user *models.User) error
 	FindByIDWithPermissions(id uint) (*models.User, []models.RolePermission, error)
+	RecentPasswordHashes(userID uint, limit int) ([]string, error)
+	AddPasswordHistory(userID uint, passwordHash string, keep int) error
+	UpdateLastLogin(userID uint, at time.Time) error
 }
 
 // EmailService defines the interface for email operations
@@ -27,6 +30,7 @@ type EmailService interface {
 	SendWelcomeEmail(toEmail, userName string) error
 	SendPasswordResetEmail(toEmail, userName, resetLink string) error
 	SendAccountApprovedEmail(toEmail, userName string) error
+	SendVerificationEmail(toEmail, userName, verifyLink string) error
 }
 
 // Input DTOs
@@ -38,8 +42,9 @@ type RegisterInput struct {
 }
 
 type LoginInput struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	UserAgent string `json:"-"`
 }
 
 type ResetPasswordInput struct {
@@ -55,9 +60,13 @@ type TokenPair struct {
 	ExpiresAt    time.Time `json:"expiresAt"`
 }
 
+// LoginResponse carries either the issued tokens or, for users with
+// two-factor enabled, the token needed to complete the login.
 type LoginResponse struct {
-	User *models.User `json:"user"`
-	TokenPair
+	User *models.User `json:"user,omitempty"`
+	*TokenPair
+	TwoFactorRequired bool   `json:"twoFactorRequired,omitempty"`
+	TwoFactorToken    string `json:"twoFactorToken,omitempty"`
 }
 
 type PermissionDTO struct {
@@ -78,6 +87,8 @@ var (
 	ErrUnauthorized = errors.New("unauthorized")
 	ErrForbidden    = errors.New("forbidden")
 	ErrNotFound     = errors.New("not found")
+	ErrBusy         = errors.New("busy")
+	ErrTooLarge     = errors.New("too large")
 )
 
 type ServiceError struct {
@@ -96,17 +107,29 @@ type AuthService struct {
 	redis        *redis.Client
 	config       *config.Config
 	emailService EmailService
+	pwPolicy     utils.PasswordPolicy
 }
 
+// NewAuthService creates a new auth service instance. A nil emailSvc sends no
+// email.
 func NewAuthService(userRepo UserRepository, rdb *redis.Client, cfg *config.Config, emailSvc EmailService) *AuthService {
+	if emailSvc == nil {
+		emailSvc = NoopEmailService{}
+	}
 	return &AuthService{
 		userRepo:     userRepo,
 		redis:        rdb,
 		config:       cfg,
 		emailService: emailSvc,
+		pwPolicy:     utils.DefaultPasswordPolicy(),
 	}
 }
 
+// SetPasswordPolicy sets the rules new passwords must satisfy.
+func (s *AuthService) SetPasswordPolicy(policy utils.PasswordPolicy) {
+	s.pwPolicy = policy
+}
+
 // Register creates a new user account with pending status
 func (s *AuthService) Register(input RegisterInput) (*models.User, *ServiceError) {
 	// Validate name
@@ -149,7 +172,7 @@ func (s *AuthService) Register(input RegisterInput) (*models.User, *ServiceError
 			Code:    "VALIDATION_ERROR",
 		}
 	}
-	if passwordErrors := utils.ValidatePassword(input.Password); len(passwordErrors) > 0 {
+	if passwordErrors := s.pwPolicy.Validate(input.Password); len(passwordErrors) > 0 {
 		return nil, &ServiceError{
 			Err:     ErrValidation,
 			Message: strings.Join(passwordErrors, "; "),
@@ -205,9 +228,12 @@ func (s *AuthService) Register(input RegisterInput) (*models.User, *ServiceError
 	}
 
 	// Send welcome email (non-blocking, don't fail if email fails)
-	if s.emailService != nil {
-		_ = s.emailService.SendWelcomeEmail(user.Email, user.Name)
-	}
+	_ = s.emailService.SendWelcomeEmail(user.Email, user.Name)
+
+	// The account cannot be approved until the email address is verified. The
+	// user already exists, so a failure here is left to ResendVerification
+	// rather than failing a registration that cannot be retried.
+	_ = s.sendVerificationEmail(context.Background(), user)
 
 	return user, nil
 }
@@ -230,10 +256,19 @@ func (s *AuthService) Login(input LoginInput) (*LoginResponse, *ServiceError) {
 		}
 	}
 
-	// Find user (case-insensitive email)
+	// Reject locked accounts before checking credentials
 	normalizedEmail := strings.ToLower(input.Email)
+	ctx := context.Background()
+	attemptsKey := loginAttemptsKey(normalizedEmail)
+	if svcErr := s.checkLoginLocked(ctx, attemptsKey); svcErr != nil {
+		return nil, svcErr
+	}
+
+	// Find user (case-insensitive email)
 	user, err := s.userRepo.FindByEmail(normalizedEmail)
 	if err != nil {
+		// Count unknown emails too so lockouts don't reveal which accounts exist
+		s.recordFailedLogin(ctx, attemptsKey)
 		return nil, &ServiceError{
 			Err:     ErrUnauthorized,
 			Message: "Invalid email or password",
@@ -244,6 +279,7 @@ func (s *AuthService) Login(input LoginInput) (*LoginResponse, *ServiceError) {
 	// Verify password
 	valid, err := utils.VerifyPassword(user.PasswordHash, input.Password)
 	if err != nil || !valid {
+		s.recordFailedLogin(ctx, attemptsKey)
 		return nil, &ServiceError{
 			Err:     ErrUnauthorized,
 			Message: "Invalid email or password",
@@ -267,6 +303,24 @@ func (s *AuthService) Login(input LoginInput) (*LoginResponse, *ServiceError) {
 		}
 	}
 
+	// Users with two-factor enabled must confirm a code before receiving tokens
+	if user.TwoFactorEnabled {
+		return s.startTwoFactorChallenge(ctx, user)
+	}
+
+	return s.issueLoginTokens(ctx, user, input.UserAgent)
+}
+
+// issueLoginTokens completes a login by issuing and storing a new token pair.
+func (s *AuthService) issueLoginTokens(ctx context.Context, user *models.User, userAgent string) (*LoginResponse, *ServiceError) {
+	s.redis.Del(ctx, loginAttemptsKey(user.Email))
+
+	// A failed timestamp write should not block the login
+	now := time.Now()
+	if err := s.userRepo.UpdateLastLogin(user.ID, now); err == nil {
+		user.LastLoginAt = &now
+	}
+
 	// Generate tokens
 	accessToken, err := utils.GenerateAccessToken(
 		user.ID,
@@ -299,8 +353,11 @@ func (s *AuthService) Login(input LoginInput) (*LoginResponse, *ServiceError) {
 	// Store refresh token in Redis
 	refreshClaims, err := utils.ValidateToken(refreshToken, s.config.JWTRefreshSecret)
 	if err == nil && refreshClaims != nil {
-		ctx := context.Background()
-		s.redis.Set(ctx, "refresh:"+refreshClaims.ID, fmt.Sprintf("%d", user.ID), s.config.JWTRefreshExpiry)
+		// In single-session mode a new login signs out every other device
+		if s.config.SingleSession {
+			revokeRefreshTokens(ctx, s.redis, user.ID, "")
+		}
+		s.storeRefreshToken(ctx, refreshClaims.ID, user.ID, userAgent)
 	}
 
 	// Get expiry time from access token
@@ -312,7 +369,7 @@ func (s *AuthService) Login(input LoginInput) (*LoginResponse, *ServiceError) {
 
 	return &LoginResponse{
 		User: user,
-		TokenPair: TokenPair{
+		TokenPair: &TokenPair{
 			AccessToken:  accessToken,
 			RefreshToken: refreshToken,
 			ExpiresAt:    expiresAt,
@@ -320,8 +377,41 @@ func (s *AuthService) Login(input LoginInput) (*LoginResponse, *ServiceError) {
 	}, nil
 }
 
+// loginAttemptsKey is the Redis key counting failed logins for an email.
+func loginAttemptsKey(email string) string {
+	return "login_attempts:" + strings.ToLower(email)
+}
+
+// checkLoginLocked rejects logins for an email with too many recent failures.
+func (s *AuthService) checkLoginLocked(ctx context.Context, attemptsKey string) *ServiceError {
+	if s.config.LoginMaxAttempts <= 0 {
+		return nil
+	}
+	attempts, _ := s.redis.Get(ctx, attemptsKey).Int()
+	if attempts >= s.config.LoginMaxAttempts {
+		return &ServiceError{
+			Err:     ErrForbidden,
+			Message: "Too many failed login attempts. Please try again later",
+			Code:    "ACCOUNT_LOCKED",
+		}
+	}
+	return nil
+}
+
+// recordFailedLogin counts a failed login attempt. The count expires one
+// lockout window after the first failure.
+func (s *AuthService) recordFailedLogin(ctx context.Context, attemptsKey string) {
+	if s.config.LoginMaxAttempts <= 0 {
+		return
+	}
+	attempts, err := s.redis.Incr(ctx, attemptsKey).Result()
+	if err == nil && attempts == 1 {
+		s.redis.Expire(ctx, attemptsKey, s.config.LoginLockoutWindow)
+	}
+}
+
 // RefreshToken generates a new token pair from a valid refresh token
-func (s *AuthService) RefreshToken(refreshToken string) (*TokenPair, *ServiceError) {
+func (s *AuthService) RefreshToken(refreshToken, userAgent string) (*TokenPair, *ServiceError) {
 	// Validate refresh token
 	claims, err := utils.ValidateToken(refreshToken, s.config.JWTRefreshSecret)
 	if err != nil {
@@ -336,10 +426,22 @@ func (s *AuthService) RefreshToken(refreshToken string) (*TokenPair, *ServiceErr
 	ctx := context.Background()
 	exists := s.redis.Exists(ctx, "refresh:"+claims.ID).Val()
 	if exists == 0 {
+		// Tokens signed out or revoked are blacklisted and merely rejected
+		if s.redis.Exists(ctx, "blacklist:"+claims.ID).Val() > 0 {
+			return nil, &ServiceError{
+				Err:     ErrUnauthorized,
+				Message: "Refresh token has been revoked",
+				Code:    "TOKEN_REVOKED",
+			}
+		}
+
+		// Any other valid token missing from Redis was already rotated, so it
+		// is being replayed, possibly by a thief. Sign the user out everywhere.
+		s.LogoutAll(claims.UserID)
 		return nil, &ServiceError{
 			Err:     ErrUnauthorized,
-			Message: "Refresh token has been revoked",
-			Code:    "TOKEN_REVOKED",
+			Message: "Refresh token reuse detected, all sessions have been revoked",
+			Code:    "TOKEN_REUSE_DETECTED",
 		}
 	}
 
@@ -396,7 +498,7 @@ func (s *AuthService) RefreshToken(refreshToken string) (*TokenPair, *ServiceErr
 	// Store new refresh token
 	newRefreshClaims, err := utils.ValidateToken(newRefreshToken, s.config.JWTRefreshSecret)
 	if err == nil && newRefreshClaims != nil {
-		s.redis.Set(ctx, "refresh:"+newRefreshClaims.ID, fmt.Sprintf("%d", user.ID), s.config.JWTRefreshExpiry)
+		s.storeRefreshToken(ctx, newRefreshClaims.ID, user.ID, userAgent)
 	}
 
 	// Get expiry time
@@ -443,6 +545,13 @@ func (s *AuthService) Logout(accessToken, refreshToken string) *ServiceError {
 	return nil
 }
 
+// LogoutAll revokes every refresh token of the user, signing them out of all
+// devices. It returns the number of sessions revoked.
+func (s *AuthService) LogoutAll(userID uint) (int, *ServiceError) {
+	ctx := context.Background()
+	return revokeRefreshTokens(ctx, s.redis, userID, ""), nil
+}
+
 // ForgotPassword initiates the password reset process
 func (s *AuthService) ForgotPassword(email string) *ServiceError {
 	// Find user (case-insensitive)
@@ -464,10 +573,8 @@ func (s *AuthService) ForgotPassword(email string) *ServiceError {
 		s.redis.Set(ctx, "reset:"+resetToken, fmt.Sprintf("%d", user.ID), time.Hour)
 
 		// Send password reset email
-		if s.emailService != nil {
-			resetLink := fmt.Sprintf("%s/reset-password?token=%s", s.config.FrontendURL, resetToken)
-			_ = s.emailService.SendPasswordResetEmail(user.Email, user.Name, resetLink)
-		}
+		resetLink := fmt.Sprintf("%s/reset-password?token=%s", s.config.FrontendURL, resetToken)
+		_ = s.emailService.SendPasswordResetEmail(user.Email, user.Name, resetLink)
 	}
 
 	return nil
@@ -476,7 +583,7 @@ func (s *AuthService) ForgotPassword(email string) *ServiceError {
 // ResetPassword changes user password using a reset token
 func (s *AuthService) ResetPassword(input ResetPasswordInput) *ServiceError {
 	// Validate password
-	if passwordErrors := utils.ValidatePassword(input.Password); len(passwordErrors) > 0 {
+	if passwordErrors := s.pwPolicy.Validate(input.Password); len(passwordErrors) > 0 {
 		return &ServiceError{
 			Err:     ErrValidation,
 			Message: strings.Join(passwordErrors, "; "),
@@ -525,6 +632,10 @@ func (s *AuthService) ResetPassword(input ResetPasswordInput) *ServiceError {
 		}
 	}
 
+	if svcErr := checkPasswordReuse(s.userRepo, s.config.PasswordHistorySize, user, input.Password); svcErr != nil {
+		return svcErr
+	}
+
 	// Hash new password
 	hashedPassword, err := utils.HashPassword(input.Password)
 	if err != nil {
@@ -535,6 +646,17 @@ func (s *AuthService) ResetPassword(input ResetPasswordInput) *ServiceError {
 		}
 	}
 
+	// Keep the replaced hash so it cannot be reused
+	if s.config.PasswordHistorySize > 0 && user.PasswordHash != "" {
+		if err := s.userRepo.AddPasswordHistory(user.ID, user.PasswordHash, s.config.PasswordHistorySize); err != nil {
+			return &ServiceError{
+				Err:     err,
+				Message: "Failed to update password",
+				Code:    "INTERNAL_ERROR",
+			}
+		}
+	}
+
 	// Update user password
 	user.PasswordHash = hashedPassword
 	if err := s.userRepo.Update(user); err != nil {
@@ -549,15 +671,44 @@ func (s *AuthService) ResetPassword(input ResetPasswordInput) *ServiceError {
 	s.redis.Del(ctx, "reset:"+input.Token)
 
 	// Invalidate all refresh tokens for this user
-	iter := s.redis.Scan(ctx, 0, "refresh:*", 0).Iterator()
-	for iter.Next(ctx) {
-		key := iter.Val()
-		val, err := s.redis.Get(ctx, key).Result()
-		if err == nil && val == userIDStr {
-			s.redis.Del(ctx, key)
+	revokeRefreshTokens(ctx, s.redis, userID, "")
+
+	return nil
+}
+
+// passwordHistoryReader reads the password hashes a user has replaced
+type passwordHistoryReader interface {
+	RecentPasswordHashes(userID uint, limit int) ([]string, error)
+}
+
+// checkPasswordReuse rejects a new password that matches the user's current
+// password or any of the last historySize passwords it replaced.
+func checkPasswordReuse(repo passwordHistoryReader, historySize int, user *models.User, password string) *ServiceError {
+	if historySize <= 0 {
+		return nil
+	}
+
+	hashes, err := repo.RecentPasswordHashes(user.ID, historySize)
+	if err != nil {
+		return &ServiceError{
+			Err:     err,
+			Message: "Failed to check password history",
+			Code:    "INTERNAL_ERROR",
 		}
 	}
+	if user.PasswordHash != "" {
+		hashes = append([]string{user.PasswordHash}, hashes...)
+	}
 
+	for _, hash := range hashes {
+		if match, err := utils.VerifyPassword(hash, password); err == nil && match {
+			return &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("New password must not match any of your last %d passwords", historySize),
+				Code:    "PASSWORD_REUSED",
+			}
+		}
+	}
 	return nil
 }
 
@@ -621,6 +772,7 @@ func getAllPermissions() []PermissionDTO {
 		{Module: "Transaction", Feature: "Purchase", Actions: []string{"read", "create", "update", "delete", "export"}},
 		{Module: "Report", Feature: "Sales Report", Actions: []string{"read", "export"}},
 		{Module: "Report", Feature: "Purchase Report", Actions: []string{"read", "export"}},
+		{Module: "Report", Feature: "Stock Report", Actions: []string{"read", "create"}},
 		{Module: "Settings", Feature: "Users", Actions: []string{"read", "create", "update", "delete"}},
 		{Module: "Settings", Feature: "Roles & Permissions", Actions: []string{"read", "create", "update", "delete"}},
 	}
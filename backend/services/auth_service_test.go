@@ -22,6 +22,8 @@ type mockUserRepo struct {
 	findByIDFn          func(uint) (*models.User, error)
 	updateFn            func(*models.User) error
 	findByIDWithPermsFn func(uint) (*models.User, []models.RolePermission, error)
+	updateLastLoginFn   func(uint, time.Time) error
+	passwordHistory     []string
 }
 
 func (m *mockUserRepo) Create(user *models.User) error {
@@ -59,11 +61,34 @@ func (m *mockUserRepo) FindByIDWithPermissions(id uint) (*models.User, []models.
 	return nil, nil, errors.New("not found")
 }
 
+func (m *mockUserRepo) RecentPasswordHashes(userID uint, limit int) ([]string, error) {
+	if len(m.passwordHistory) > limit {
+		return m.passwordHistory[:limit], nil
+	}
+	return m.passwordHistory, nil
+}
+
+func (m *mockUserRepo) AddPasswordHistory(userID uint, passwordHash string, keep int) error {
+	m.passwordHistory = append([]string{passwordHash}, m.passwordHistory...)
+	if len(m.passwordHistory) > keep {
+		m.passwordHistory = m.passwordHistory[:keep]
+	}
+	return nil
+}
+
+func (m *mockUserRepo) UpdateLastLogin(userID uint, at time.Time) error {
+	if m.updateLastLoginFn != nil {
+		return m.updateLastLoginFn(userID, at)
+	}
+	return nil
+}
+
 // Mock EmailService
 type mockEmailService struct {
 	sendWelcomeFn        func(string, string) error
 	sendPasswordResetFn  func(string, string, string) error
 	sendAccountApprovedFn func(string, string) error
+	sendVerificationFn   func(string, string, string) error
 }
 
 func (m *mockEmailService) SendWelcomeEmail(toEmail, userName string) error {
@@ -87,6 +112,13 @@ func (m *mockEmailService) SendAccountApprovedEmail(toEmail, userName string) er
 	return nil
 }
 
+func (m *mockEmailService) SendVerificationEmail(toEmail, userName, verifyLink string) error {
+	if m.sendVerificationFn != nil {
+		return m.sendVerificationFn(toEmail, userName, verifyLink)
+	}
+	return nil
+}
+
 // Test setup helper
 func setupAuthServiceTest(t *testing.T) (*AuthService, *mockUserRepo, *redis.Client, *miniredis.Miniredis, *config.Config) {
 	// Create miniredis instance
@@ -212,6 +244,37 @@ func TestRegister_WeakPassword_ReturnsValidationError(t *testing.T) {
 	assert.Contains(t, err.Message, "at least 8 characters")
 }
 
+func TestRegister_CustomPasswordPolicy_IsEnforced(t *testing.T) {
+	service, mockRepo, _, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+	service.SetPasswordPolicy(utils.PasswordPolicy{MinLength: 14, RequireDigit: true})
+
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		return nil, errors.New("not found")
+	}
+
+	// Meets the default policy but is shorter than 14 characters
+	_, err := service.Register(RegisterInput{
+		Name:            "John Doe",
+		Email:           "john@example.com",
+		Password:        "Password123!",
+		ConfirmPassword: "Password123!",
+	})
+	require.NotNil(t, err)
+	assert.Equal(t, ErrValidation, err.Err)
+	assert.Contains(t, err.Message, "at least 14 characters")
+
+	// No uppercase or symbol needed under this policy
+	user, err := service.Register(RegisterInput{
+		Name:            "John Doe",
+		Email:           "john@example.com",
+		Password:        "longpassword123",
+		ConfirmPassword: "longpassword123",
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, user)
+}
+
 func TestRegister_EmptyName_ReturnsValidationError(t *testing.T) {
 	service, _, _, mr, _ := setupAuthServiceTest(t)
 	defer mr.Close()
@@ -310,7 +373,135 @@ func TestLogin_ActiveUser_ReturnsTokens(t *testing.T) {
 	refreshClaims, _ := utils.ValidateToken(response.RefreshToken, cfg.JWTRefreshSecret)
 	val, redisErr := rdb.Get(context.Background(), "refresh:"+refreshClaims.ID).Result()
 	assert.NoError(t, redisErr)
-	assert.Equal(t, "1", val)
+	session, ok := parseRefreshSession(val)
+	assert.True(t, ok)
+	assert.Equal(t, uint(1), session.UserID)
+}
+
+func TestLogin_Success_RecordsLastLogin(t *testing.T) {
+	service, mockRepo, _, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	hashedPassword, _ := utils.HashPassword("Password123!")
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		return &models.User{ID: 1, Email: email, PasswordHash: hashedPassword, Status: "active"}, nil
+	}
+	var recordedID uint
+	mockRepo.updateLastLoginFn = func(userID uint, at time.Time) error {
+		recordedID = userID
+		return nil
+	}
+
+	response, svcErr := service.Login(LoginInput{Email: "john@example.com", Password: "Password123!"})
+
+	require.Nil(t, svcErr)
+	assert.Equal(t, uint(1), recordedID)
+	require.NotNil(t, response.User.LastLoginAt)
+	assert.WithinDuration(t, time.Now(), *response.User.LastLoginAt, time.Minute)
+}
+
+func TestLogin_LastLoginUpdateFails_StillLogsIn(t *testing.T) {
+	service, mockRepo, _, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	hashedPassword, _ := utils.HashPassword("Password123!")
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		return &models.User{ID: 1, Email: email, PasswordHash: hashedPassword, Status: "active"}, nil
+	}
+	mockRepo.updateLastLoginFn = func(userID uint, at time.Time) error {
+		return errors.New("db down")
+	}
+
+	response, svcErr := service.Login(LoginInput{Email: "john@example.com", Password: "Password123!"})
+
+	require.Nil(t, svcErr)
+	assert.NotEmpty(t, response.AccessToken)
+	assert.Nil(t, response.User.LastLoginAt)
+}
+
+func TestLogin_WrongPassword_DoesNotRecordLastLogin(t *testing.T) {
+	service, mockRepo, _, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	hashedPassword, _ := utils.HashPassword("Password123!")
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		return &models.User{ID: 1, Email: email, PasswordHash: hashedPassword, Status: "active"}, nil
+	}
+	called := false
+	mockRepo.updateLastLoginFn = func(userID uint, at time.Time) error {
+		called = true
+		return nil
+	}
+
+	_, svcErr := service.Login(LoginInput{Email: "john@example.com", Password: "WrongPass1!"})
+
+	require.NotNil(t, svcErr)
+	assert.False(t, called)
+}
+
+func TestLogin_SingleSession_RevokesPreviousRefreshTokens(t *testing.T) {
+	service, mockRepo, _, mr, cfg := setupAuthServiceTest(t)
+	defer mr.Close()
+	cfg.SingleSession = true
+
+	hashedPassword, _ := utils.HashPassword("Password123!")
+
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		return &models.User{
+			ID:           1,
+			Email:        email,
+			PasswordHash: hashedPassword,
+			Status:       "active",
+		}, nil
+	}
+	mockRepo.findByIDFn = func(id uint) (*models.User, error) {
+		return &models.User{ID: 1, Email: "john@example.com", Status: "active"}, nil
+	}
+
+	input := LoginInput{Email: "john@example.com", Password: "Password123!"}
+
+	first, svcErr := service.Login(input)
+	require.Nil(t, svcErr)
+	second, svcErr := service.Login(input)
+	require.Nil(t, svcErr)
+
+	// The first device's refresh token is revoked
+	tokens, err := service.RefreshToken(first.RefreshToken, "")
+	assert.Nil(t, tokens)
+	require.NotNil(t, err)
+	assert.Equal(t, ErrUnauthorized, err.Err)
+
+	// The latest login still works
+	tokens, err = service.RefreshToken(second.RefreshToken, "")
+	assert.Nil(t, err)
+	assert.NotNil(t, tokens)
+}
+
+func TestLogin_SingleSessionDisabled_KeepsPreviousRefreshTokens(t *testing.T) {
+	service, mockRepo, rdb, mr, cfg := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	hashedPassword, _ := utils.HashPassword("Password123!")
+
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		return &models.User{
+			ID:           1,
+			Email:        email,
+			PasswordHash: hashedPassword,
+			Status:       "active",
+		}, nil
+	}
+
+	input := LoginInput{Email: "john@example.com", Password: "Password123!"}
+
+	first, svcErr := service.Login(input)
+	require.Nil(t, svcErr)
+	_, svcErr = service.Login(input)
+	require.Nil(t, svcErr)
+
+	firstClaims, _ := utils.ValidateToken(first.RefreshToken, cfg.JWTRefreshSecret)
+	exists := rdb.Exists(context.Background(), "refresh:"+firstClaims.ID).Val()
+	assert.Equal(t, int64(1), exists)
 }
 
 func TestLogin_PendingUser_ReturnsForbiddenError(t *testing.T) {
@@ -418,6 +609,99 @@ func TestLogin_NonExistentEmail_ReturnsUnauthorizedError(t *testing.T) {
 	assert.Contains(t, err.Message, "Invalid email or password")
 }
 
+func setupLockoutTest(t *testing.T) (*AuthService, *miniredis.Miniredis, *config.Config) {
+	t.Helper()
+	service, mockRepo, _, mr, cfg := setupAuthServiceTest(t)
+	cfg.LoginMaxAttempts = 5
+	cfg.LoginLockoutWindow = 15 * time.Minute
+
+	hashedPassword, _ := utils.HashPassword("Password123!")
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		if email != "john@example.com" {
+			return nil, errors.New("not found")
+		}
+		return &models.User{
+			ID:           1,
+			Email:        email,
+			PasswordHash: hashedPassword,
+			Status:       "active",
+		}, nil
+	}
+	return service, mr, cfg
+}
+
+func TestLogin_RepeatedFailures_LocksAccount(t *testing.T) {
+	service, mr, _ := setupLockoutTest(t)
+	defer mr.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := service.Login(LoginInput{Email: "john@example.com", Password: "wrong"})
+		require.NotNil(t, err)
+		assert.Equal(t, "INVALID_CREDENTIALS", err.Code)
+	}
+
+	// Even the correct password is rejected while locked
+	response, err := service.Login(LoginInput{Email: "John@Example.com", Password: "Password123!"})
+	assert.Nil(t, response)
+	require.NotNil(t, err)
+	assert.Equal(t, ErrForbidden, err.Err)
+	assert.Equal(t, "ACCOUNT_LOCKED", err.Code)
+	assert.Contains(t, err.Message, "try again later")
+}
+
+func TestLogin_LockoutExpiresAfterWindow(t *testing.T) {
+	service, mr, _ := setupLockoutTest(t)
+	defer mr.Close()
+
+	for i := 0; i < 5; i++ {
+		service.Login(LoginInput{Email: "john@example.com", Password: "wrong"})
+	}
+
+	mr.FastForward(15*time.Minute + time.Second)
+
+	response, err := service.Login(LoginInput{Email: "john@example.com", Password: "Password123!"})
+	assert.Nil(t, err)
+	assert.NotNil(t, response)
+}
+
+func TestLogin_Success_ClearsFailedAttempts(t *testing.T) {
+	service, mr, _ := setupLockoutTest(t)
+	defer mr.Close()
+
+	for i := 0; i < 4; i++ {
+		service.Login(LoginInput{Email: "john@example.com", Password: "wrong"})
+	}
+	_, err := service.Login(LoginInput{Email: "john@example.com", Password: "Password123!"})
+	require.Nil(t, err)
+	assert.False(t, mr.Exists("login_attempts:john@example.com"))
+
+	// The counter starts over, so four more failures do not lock the account
+	for i := 0; i < 4; i++ {
+		service.Login(LoginInput{Email: "john@example.com", Password: "wrong"})
+	}
+	_, err = service.Login(LoginInput{Email: "john@example.com", Password: "Password123!"})
+	assert.Nil(t, err)
+}
+
+func TestLogin_NonExistentEmail_IncrementsAttempts(t *testing.T) {
+	service, mr, _ := setupLockoutTest(t)
+	defer mr.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := service.Login(LoginInput{Email: "ghost@example.com", Password: "whatever"})
+		require.NotNil(t, err)
+		assert.Equal(t, "INVALID_CREDENTIALS", err.Code)
+	}
+
+	attempts, err := mr.Get("login_attempts:ghost@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "5", attempts)
+
+	_, svcErr := service.Login(LoginInput{Email: "ghost@example.com", Password: "whatever"})
+	require.NotNil(t, svcErr)
+	assert.Equal(t, "ACCOUNT_LOCKED", svcErr.Code)
+}
+
 func TestRefreshToken_ValidToken_ReturnsNewPair(t *testing.T) {
 	service, mockRepo, rdb, mr, cfg := setupAuthServiceTest(t)
 	defer mr.Close()
@@ -438,7 +722,7 @@ func TestRefreshToken_ValidToken_ReturnsNewPair(t *testing.T) {
 		}, nil
 	}
 
-	newTokens, svcErr := service.RefreshToken(refreshToken)
+	newTokens, svcErr := service.RefreshToken(refreshToken, "")
 
 	assert.Nil(t, svcErr)
 	assert.NotNil(t, newTokens)
@@ -453,7 +737,9 @@ func TestRefreshToken_ValidToken_ReturnsNewPair(t *testing.T) {
 	newRefreshClaims, _ := utils.ValidateToken(newTokens.RefreshToken, cfg.JWTRefreshSecret)
 	val, redisErr := rdb.Get(ctx, "refresh:"+newRefreshClaims.ID).Result()
 	assert.NoError(t, redisErr)
-	assert.Equal(t, "1", val)
+	session, ok := parseRefreshSession(val)
+	assert.True(t, ok)
+	assert.Equal(t, uint(1), session.UserID)
 }
 
 func TestRefreshToken_RevokedToken_ReturnsError(t *testing.T) {
@@ -463,7 +749,7 @@ func TestRefreshToken_RevokedToken_ReturnsError(t *testing.T) {
 	// Generate refresh token but don't store in Redis (simulating revoked token)
 	refreshToken, _ := utils.GenerateRefreshToken(1, false, cfg.JWTRefreshSecret, cfg.JWTRefreshExpiry)
 
-	newTokens, err := service.RefreshToken(refreshToken)
+	newTokens, err := service.RefreshToken(refreshToken, "")
 
 	assert.Nil(t, newTokens)
 	assert.NotNil(t, err)
@@ -471,11 +757,65 @@ func TestRefreshToken_RevokedToken_ReturnsError(t *testing.T) {
 	assert.Contains(t, err.Message, "revoked")
 }
 
+func TestRefreshToken_RotatedTokenReused_RevokesAllSessions(t *testing.T) {
+	service, mockRepo, rdb, mr, cfg := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	mockRepo.findByIDFn = func(id uint) (*models.User, error) {
+		return &models.User{ID: 1, Email: "john@example.com", Status: "active"}, nil
+	}
+
+	ctx := context.Background()
+	refreshToken, _ := utils.GenerateRefreshToken(1, false, cfg.JWTRefreshSecret, cfg.JWTRefreshExpiry)
+	refreshClaims, _ := utils.ValidateToken(refreshToken, cfg.JWTRefreshSecret)
+	service.storeRefreshToken(ctx, refreshClaims.ID, 1, "")
+	// A second device of the same user
+	service.storeRefreshToken(ctx, "other-device", 1, "")
+
+	// First rotation succeeds
+	rotated, svcErr := service.RefreshToken(refreshToken, "")
+	require.Nil(t, svcErr)
+	require.NotNil(t, rotated)
+
+	// Replaying the already rotated token revokes every session
+	newTokens, svcErr := service.RefreshToken(refreshToken, "")
+
+	assert.Nil(t, newTokens)
+	require.NotNil(t, svcErr)
+	assert.Equal(t, ErrUnauthorized, svcErr.Err)
+	assert.Equal(t, "TOKEN_REUSE_DETECTED", svcErr.Code)
+	assert.Empty(t, rdb.Keys(ctx, "refresh:*").Val())
+
+	// The legitimately rotated token was revoked along with the others
+	_, svcErr = service.RefreshToken(rotated.RefreshToken, "")
+	require.NotNil(t, svcErr)
+	assert.Equal(t, "TOKEN_REVOKED", svcErr.Code)
+}
+
+func TestRefreshToken_LoggedOutToken_ReturnsRevokedWithoutRevokingOthers(t *testing.T) {
+	service, _, rdb, mr, cfg := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	refreshToken, _ := utils.GenerateRefreshToken(1, false, cfg.JWTRefreshSecret, cfg.JWTRefreshExpiry)
+	refreshClaims, _ := utils.ValidateToken(refreshToken, cfg.JWTRefreshSecret)
+	service.storeRefreshToken(ctx, refreshClaims.ID, 1, "")
+	service.storeRefreshToken(ctx, "other-device", 1, "")
+
+	require.Nil(t, service.Logout("", refreshToken))
+
+	_, svcErr := service.RefreshToken(refreshToken, "")
+
+	require.NotNil(t, svcErr)
+	assert.Equal(t, "TOKEN_REVOKED", svcErr.Code)
+	assert.Equal(t, []string{"refresh:other-device"}, rdb.Keys(ctx, "refresh:*").Val())
+}
+
 func TestRefreshToken_InvalidToken_ReturnsError(t *testing.T) {
 	service, _, _, mr, _ := setupAuthServiceTest(t)
 	defer mr.Close()
 
-	newTokens, err := service.RefreshToken("invalid-token")
+	newTokens, err := service.RefreshToken("invalid-token", "")
 
 	assert.Nil(t, newTokens)
 	assert.NotNil(t, err)
@@ -511,6 +851,88 @@ func TestLogout_ValidTokens_BlacklistsBoth(t *testing.T) {
 	assert.Error(t, redisErr) // Should be deleted
 }
 
+func TestLogoutAll_RevokesOnlyUsersRefreshTokens(t *testing.T) {
+	service, _, rdb, mr, cfg := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		token, _ := utils.GenerateRefreshToken(1, false, cfg.JWTRefreshSecret, cfg.JWTRefreshExpiry)
+		claims, _ := utils.ValidateToken(token, cfg.JWTRefreshSecret)
+		rdb.Set(ctx, "refresh:"+claims.ID, "1", cfg.JWTRefreshExpiry)
+	}
+	otherToken, _ := utils.GenerateRefreshToken(2, false, cfg.JWTRefreshSecret, cfg.JWTRefreshExpiry)
+	otherClaims, _ := utils.ValidateToken(otherToken, cfg.JWTRefreshSecret)
+	rdb.Set(ctx, "refresh:"+otherClaims.ID, "2", cfg.JWTRefreshExpiry)
+
+	revoked, svcErr := service.LogoutAll(1)
+
+	assert.Nil(t, svcErr)
+	assert.Equal(t, 3, revoked)
+	keys := rdb.Keys(ctx, "refresh:*").Val()
+	assert.Equal(t, []string{"refresh:" + otherClaims.ID}, keys)
+
+	// A second call finds nothing left to revoke
+	revoked, svcErr = service.LogoutAll(1)
+	assert.Nil(t, svcErr)
+	assert.Equal(t, 0, revoked)
+}
+
+func TestListSessions_ReturnsUsersSessionsNewestFirst(t *testing.T) {
+	service, mockRepo, rdb, mr, cfg := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	hashedPassword, _ := utils.HashPassword("Password123!")
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		return &models.User{ID: 1, Email: email, PasswordHash: hashedPassword, Status: "active"}, nil
+	}
+	mockRepo.findByIDFn = func(id uint) (*models.User, error) {
+		return &models.User{ID: 1, Email: "john@example.com", Status: "active"}, nil
+	}
+
+	first, svcErr := service.Login(LoginInput{Email: "john@example.com", Password: "Password123!", UserAgent: "Firefox"})
+	require.Nil(t, svcErr)
+	time.Sleep(2 * time.Millisecond)
+	_, svcErr = service.Login(LoginInput{Email: "john@example.com", Password: "Password123!", UserAgent: "Safari"})
+	require.Nil(t, svcErr)
+
+	// Another user's session is not listed
+	otherToken, _ := utils.GenerateRefreshToken(2, false, cfg.JWTRefreshSecret, cfg.JWTRefreshExpiry)
+	otherClaims, _ := utils.ValidateToken(otherToken, cfg.JWTRefreshSecret)
+	service.storeRefreshToken(context.Background(), otherClaims.ID, 2, "Chrome")
+
+	sessions, svcErr := service.ListSessions(1)
+
+	require.Nil(t, svcErr)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "Safari", sessions[0].UserAgent)
+	assert.Equal(t, "Firefox", sessions[1].UserAgent)
+	firstClaims, _ := utils.ValidateToken(first.RefreshToken, cfg.JWTRefreshSecret)
+	assert.Equal(t, firstClaims.ID, sessions[1].ID)
+
+	// Refreshing replaces the session with one carrying the new user agent
+	time.Sleep(2 * time.Millisecond)
+	_, svcErr = service.RefreshToken(first.RefreshToken, "Firefox 2")
+	require.Nil(t, svcErr)
+	sessions, _ = service.ListSessions(1)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "Firefox 2", sessions[0].UserAgent)
+	assert.Len(t, rdb.Keys(context.Background(), "refresh:*").Val(), 3)
+}
+
+func TestListSessions_LegacyPlainUserIDValue_Listed(t *testing.T) {
+	service, _, rdb, mr, cfg := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	rdb.Set(context.Background(), "refresh:legacy-jti", "1", cfg.JWTRefreshExpiry)
+
+	sessions, svcErr := service.ListSessions(1)
+
+	require.Nil(t, svcErr)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "legacy-jti", sessions[0].ID)
+}
+
 func TestForgotPassword_ExistingEmail_StoresTokenInRedis(t *testing.T) {
 	service, mockRepo, rdb, mr, _ := setupAuthServiceTest(t)
 	defer mr.Close()
@@ -602,6 +1024,68 @@ func TestResetPassword_ValidToken_UpdatesPassword(t *testing.T) {
 	assert.Error(t, redisErr)
 }
 
+func setupPasswordHistoryTest(t *testing.T, currentPassword string) (*AuthService, *mockUserRepo, *redis.Client, *miniredis.Miniredis, *models.User) {
+	t.Helper()
+	service, mockRepo, rdb, mr, cfg := setupAuthServiceTest(t)
+	cfg.PasswordHistorySize = 3
+
+	currentHash, err := utils.HashPassword(currentPassword)
+	require.NoError(t, err)
+	user := &models.User{ID: 1, Email: "john@example.com", Status: "active", PasswordHash: currentHash}
+	mockRepo.findByIDFn = func(id uint) (*models.User, error) {
+		return user, nil
+	}
+	return service, mockRepo, rdb, mr, user
+}
+
+func resetPasswordTo(service *AuthService, rdb *redis.Client, password string) *ServiceError {
+	rdb.Set(context.Background(), "reset:history-token", "1", time.Hour)
+	return service.ResetPassword(ResetPasswordInput{
+		Token:           "history-token",
+		Password:        password,
+		ConfirmPassword: password,
+	})
+}
+
+func TestResetPassword_ReusesCurrentPassword_ReturnsError(t *testing.T) {
+	service, _, rdb, mr, _ := setupPasswordHistoryTest(t, "OldPassword123!")
+	defer mr.Close()
+
+	svcErr := resetPasswordTo(service, rdb, "OldPassword123!")
+
+	require.NotNil(t, svcErr)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+	assert.Equal(t, "PASSWORD_REUSED", svcErr.Code)
+}
+
+func TestResetPassword_ReusesImmediatelyPreviousPassword_ReturnsError(t *testing.T) {
+	service, mockRepo, rdb, mr, user := setupPasswordHistoryTest(t, "FirstPassword123!")
+	defer mr.Close()
+
+	require.Nil(t, resetPasswordTo(service, rdb, "SecondPassword123!"))
+	require.Len(t, mockRepo.passwordHistory, 1)
+	valid, _ := utils.VerifyPassword(user.PasswordHash, "SecondPassword123!")
+	require.True(t, valid)
+
+	svcErr := resetPasswordTo(service, rdb, "FirstPassword123!")
+
+	require.NotNil(t, svcErr)
+	assert.Equal(t, "PASSWORD_REUSED", svcErr.Code)
+}
+
+func TestResetPassword_BrandNewPassword_Accepted(t *testing.T) {
+	service, mockRepo, rdb, mr, user := setupPasswordHistoryTest(t, "FirstPassword123!")
+	defer mr.Close()
+
+	require.Nil(t, resetPasswordTo(service, rdb, "SecondPassword123!"))
+	svcErr := resetPasswordTo(service, rdb, "ThirdPassword123!")
+
+	assert.Nil(t, svcErr)
+	valid, _ := utils.VerifyPassword(user.PasswordHash, "ThirdPassword123!")
+	assert.True(t, valid)
+	assert.Len(t, mockRepo.passwordHistory, 2)
+}
+
 func TestResetPassword_ExpiredToken_ReturnsError(t *testing.T) {
 	service, _, _, mr, _ := setupAuthServiceTest(t)
 	defer mr.Close()
@@ -677,6 +1161,30 @@ func TestResetPassword_InvalidatesAllRefreshTokens(t *testing.T) {
 	assert.Len(t, keys, 0)
 }
 
+func TestResetPassword_InvalidatesSessionsStoredAsJSON(t *testing.T) {
+	service, mockRepo, rdb, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	service.storeRefreshToken(ctx, "session-1", 1, "Firefox")
+	service.storeRefreshToken(ctx, "session-2", 1, "Safari")
+	service.storeRefreshToken(ctx, "other-user", 2, "Chrome")
+
+	mockRepo.findByIDFn = func(id uint) (*models.User, error) {
+		return &models.User{ID: 1}, nil
+	}
+	rdb.Set(ctx, "reset:json-sessions", "1", time.Hour)
+
+	svcErr := service.ResetPassword(ResetPasswordInput{
+		Token:           "json-sessions",
+		Password:        "NewPassword123!",
+		ConfirmPassword: "NewPassword123!",
+	})
+
+	assert.Nil(t, svcErr)
+	assert.Equal(t, []string{"refresh:other-user"}, rdb.Keys(ctx, "refresh:*").Val())
+}
+
 func TestGetCurrentUser_ValidId_ReturnsUserWithPermissions(t *testing.T) {
 	service, mockRepo, _, mr, _ := setupAuthServiceTest(t)
 	defer mr.Close()
@@ -1,8 +1,8 @@
 package services
 
 import (
+	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/repositories"
@@ -13,10 +13,12 @@ import (
 type CategoryRepositoryInterface interface {
 	Create(category *models.Category) error
 	List(params repositories.PaginationParams) ([]models.Category, int64, error)
+	ListAll() ([]models.Category, error)
 	GetByID(id uint) (*models.Category, error)
 	Update(category *models.Category) error
 	Delete(id uint) error
 	CountProductsByCategory(categoryID uint) (int64, error)
+	CountActiveProductsByCategory() (map[uint]int64, error)
 }
 
 // CategoryService handles category business logic
@@ -33,12 +35,38 @@ func NewCategoryService(repo CategoryRepositoryInterface) *CategoryService {
 type CreateCategoryInput struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	ParentID    *uint  `json:"parentId"`
 }
 
-// UpdateCategoryInput represents the input for updating a category
+// UpdateCategoryInput represents the input for updating a category.
+// An absent parentId keeps the current parent; an explicit null makes the
+// category top-level.
 type UpdateCategoryInput struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	ParentID    NullableID `json:"parentId"`
+}
+
+// NullableID is an optional JSON ID that tells an absent field apart from an
+// explicit null. Set is true when the field was present; Value is nil for null.
+type NullableID struct {
+	Set   bool
+	Value *uint
+}
+
+// UnmarshalJSON marks the ID as set and decodes it, leaving Value nil for null.
+func (n *NullableID) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+	var id uint
+	if err := json.Unmarshal(data, &id); err != nil {
+		return err
+	}
+	n.Value = &id
+	return nil
 }
 
 // ListCategories returns paginated categories
@@ -46,6 +74,53 @@ func (s *CategoryService) ListCategories(params repositories.PaginationParams) (
 	return s.repo.List(params)
 }
 
+// ActiveProductCounts returns the number of active products per category ID.
+func (s *CategoryService) ActiveProductCounts() (map[uint]int64, error) {
+	counts, err := s.repo.CountActiveProductsByCategory()
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to count products",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	return counts, nil
+}
+
+// CategoryTree returns all categories nested under their parents. Top-level
+// categories and each level of children are ordered by name.
+func (s *CategoryService) CategoryTree() ([]models.Category, error) {
+	categories, err := s.repo.ListAll()
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to fetch categories",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	byParent := make(map[uint][]models.Category)
+	var roots []models.Category
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+		} else {
+			byParent[*category.ParentID] = append(byParent[*category.ParentID], category)
+		}
+	}
+	return buildCategoryTree(roots, byParent), nil
+}
+
+// buildCategoryTree attaches children from byParent to each category, recursively.
+func buildCategoryTree(categories []models.Category, byParent map[uint][]models.Category) []models.Category {
+	tree := make([]models.Category, len(categories))
+	for i, category := range categories {
+		category.Children = buildCategoryTree(byParent[category.ID], byParent)
+		tree[i] = category
+	}
+	return tree
+}
+
 // GetCategory returns a single category by ID
 func (s *CategoryService) GetCategory(id uint) (*models.Category, error) {
 	category, err := s.repo.GetByID(id)
@@ -69,25 +144,21 @@ func (s *CategoryService) GetCategory(id uint) (*models.Category, error) {
 // CreateCategory creates a new category
 func (s *CategoryService) CreateCategory(input CreateCategoryInput) (*models.Category, error) {
 	// Validate name
-	name := strings.TrimSpace(input.Name)
-	if name == "" {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Name is required",
-			Code:    "VALIDATION_ERROR",
-		}
+	name, nameErr := requiredText("Name", input.Name, 255)
+	if nameErr != nil {
+		return nil, nameErr
 	}
-	if len(name) > 255 {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Name must be between 1 and 255 characters",
-			Code:    "VALIDATION_ERROR",
+
+	if input.ParentID != nil {
+		if err := s.validateParent(0, *input.ParentID); err != nil {
+			return nil, err
 		}
 	}
 
 	category := &models.Category{
 		Name:        name,
 		Description: input.Description,
+		ParentID:    input.ParentID,
 	}
 
 	if err := s.repo.Create(category); err != nil {
@@ -120,21 +191,26 @@ func (s *CategoryService) UpdateCategory(id uint, input UpdateCategoryInput) (*m
 	}
 
 	// Validate and update name
-	name := strings.TrimSpace(input.Name)
-	if name != "" {
-		if len(name) > 255 {
-			return nil, &ServiceError{
-				Err:     ErrValidation,
-				Message: "Name must be between 1 and 255 characters",
-				Code:    "VALIDATION_ERROR",
-			}
-		}
+	name, ok, nameErr := updatedText("Name", input.Name, 255)
+	if nameErr != nil {
+		return nil, nameErr
+	}
+	if ok {
 		category.Name = name
 	}
 
 	// Update description (allow empty to clear it)
 	category.Description = input.Description
 
+	if input.ParentID.Set {
+		if input.ParentID.Value != nil {
+			if err := s.validateParent(category.ID, *input.ParentID.Value); err != nil {
+				return nil, err
+			}
+		}
+		category.ParentID = input.ParentID.Value
+	}
+
 	if err := s.repo.Update(category); err != nil {
 		return nil, &ServiceError{
 			Err:     err,
@@ -149,7 +225,7 @@ func (s *CategoryService) UpdateCategory(id uint, input UpdateCategoryInput) (*m
 // DeleteCategory deletes a category, blocking if referenced by products
 func (s *CategoryService) DeleteCategory(id uint) error {
 	// Check if category exists
-	_, err := s.repo.GetByID(id)
+	category, err := s.repo.GetByID(id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return &ServiceError{
@@ -165,6 +241,15 @@ func (s *CategoryService) DeleteCategory(id uint) error {
 		}
 	}
 
+	// Children must be moved or deleted first
+	if len(category.Children) > 0 {
+		return &ServiceError{
+			Err:     ErrConflict,
+			Message: fmt.Sprintf("Cannot delete category. It has %d subcategory(ies); reassign them first.", len(category.Children)),
+			Code:    "CATEGORY_HAS_CHILDREN",
+		}
+	}
+
 	// Check if category is referenced by products
 	count, err := s.repo.CountProductsByCategory(id)
 	if err != nil {
@@ -192,3 +277,51 @@ func (s *CategoryService) DeleteCategory(id uint) error {
 
 	return nil
 }
+
+// validateParent checks that parentID exists and that making it the parent of
+// categoryID would not create a cycle, i.e. categoryID is not parentID or one
+// of its ancestors. categoryID is 0 for a new category.
+func (s *CategoryService) validateParent(categoryID, parentID uint) error {
+	if categoryID != 0 && parentID == categoryID {
+		return errCategoryCycle()
+	}
+
+	seen := make(map[uint]bool)
+	nextID := &parentID
+	for nextID != nil {
+		if categoryID != 0 && *nextID == categoryID {
+			return errCategoryCycle()
+		}
+		if seen[*nextID] {
+			// Existing data already loops; refuse to extend it
+			return errCategoryCycle()
+		}
+		seen[*nextID] = true
+
+		ancestor, err := s.repo.GetByID(*nextID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return &ServiceError{
+					Err:     ErrValidation,
+					Message: "Parent category not found",
+					Code:    "PARENT_CATEGORY_NOT_FOUND",
+				}
+			}
+			return &ServiceError{
+				Err:     err,
+				Message: "Failed to fetch parent category",
+				Code:    "INTERNAL_ERROR",
+			}
+		}
+		nextID = ancestor.ParentID
+	}
+	return nil
+}
+
+func errCategoryCycle() *ServiceError {
+	return &ServiceError{
+		Err:     ErrValidation,
+		Message: "A category cannot be its own ancestor",
+		Code:    "CATEGORY_CYCLE",
+	}
+}
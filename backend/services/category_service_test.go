@@ -1,6 +1,8 @@
 package services
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/pointofsale/backend/models"
@@ -14,10 +16,12 @@ import (
 type mockCategoryRepo struct {
 	createFn               func(*models.Category) error
 	listFn                 func(repositories.PaginationParams) ([]models.Category, int64, error)
+	listAllFn              func() ([]models.Category, error)
 	getByIDFn              func(uint) (*models.Category, error)
 	updateFn               func(*models.Category) error
 	deleteFn               func(uint) error
 	countProductsByCatFn   func(uint) (int64, error)
+	countActiveByCatFn     func() (map[uint]int64, error)
 }
 
 func (m *mockCategoryRepo) Create(category *models.Category) error {
@@ -34,6 +38,13 @@ func (m *mockCategoryRepo) List(params repositories.PaginationParams) ([]models.
 	return []models.Category{}, 0, nil
 }
 
+func (m *mockCategoryRepo) ListAll() ([]models.Category, error) {
+	if m.listAllFn != nil {
+		return m.listAllFn()
+	}
+	return []models.Category{}, nil
+}
+
 func (m *mockCategoryRepo) GetByID(id uint) (*models.Category, error) {
 	if m.getByIDFn != nil {
 		return m.getByIDFn(id)
@@ -62,6 +73,13 @@ func (m *mockCategoryRepo) CountProductsByCategory(categoryID uint) (int64, erro
 	return 0, nil
 }
 
+func (m *mockCategoryRepo) CountActiveProductsByCategory() (map[uint]int64, error) {
+	if m.countActiveByCatFn != nil {
+		return m.countActiveByCatFn()
+	}
+	return map[uint]int64{}, nil
+}
+
 func TestCategoryService_CreateCategory_Valid_Succeeds(t *testing.T) {
 	repo := &mockCategoryRepo{
 		createFn: func(c *models.Category) error {
@@ -244,3 +262,271 @@ func TestCategoryService_UpdateCategory_Valid_Succeeds(t *testing.T) {
 	assert.Equal(t, "New Name", category.Name)
 	assert.Equal(t, "New desc", category.Description)
 }
+
+func TestCategoryService_ActiveProductCounts_ReturnsCounts(t *testing.T) {
+	repo := &mockCategoryRepo{
+		countActiveByCatFn: func() (map[uint]int64, error) {
+			return map[uint]int64{1: 3, 2: 1}, nil
+		},
+	}
+	svc := NewCategoryService(repo)
+
+	counts, err := svc.ActiveProductCounts()
+	require.NoError(t, err)
+	assert.Equal(t, map[uint]int64{1: 3, 2: 1}, counts)
+}
+
+func TestCategoryService_ActiveProductCounts_RepoError_ReturnsInternalError(t *testing.T) {
+	repo := &mockCategoryRepo{
+		countActiveByCatFn: func() (map[uint]int64, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	svc := NewCategoryService(repo)
+
+	_, err := svc.ActiveProductCounts()
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "INTERNAL_ERROR", svcErr.Code)
+}
+
+func uintPtr(v uint) *uint {
+	return &v
+}
+
+// categoryChain returns a repo holding Clothing(1) > Men(2) > Shirts(3)
+func categoryChain() *mockCategoryRepo {
+	categories := map[uint]*models.Category{
+		1: {ID: 1, Name: "Clothing"},
+		2: {ID: 2, Name: "Men", ParentID: uintPtr(1)},
+		3: {ID: 3, Name: "Shirts", ParentID: uintPtr(2)},
+	}
+	return &mockCategoryRepo{
+		getByIDFn: func(id uint) (*models.Category, error) {
+			if c, ok := categories[id]; ok {
+				copied := *c
+				return &copied, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+}
+
+func TestCategoryService_CreateCategory_WithParent_SetsParentID(t *testing.T) {
+	svc := NewCategoryService(categoryChain())
+
+	category, err := svc.CreateCategory(CreateCategoryInput{Name: "Polo", ParentID: uintPtr(3)})
+	require.NoError(t, err)
+	require.NotNil(t, category.ParentID)
+	assert.Equal(t, uint(3), *category.ParentID)
+}
+
+func TestCategoryService_CreateCategory_MissingParent_ReturnsValidation(t *testing.T) {
+	svc := NewCategoryService(categoryChain())
+
+	_, err := svc.CreateCategory(CreateCategoryInput{Name: "Polo", ParentID: uintPtr(99)})
+	require.Error(t, err)
+
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "PARENT_CATEGORY_NOT_FOUND", serviceErr.Code)
+}
+
+func TestCategoryService_UpdateCategory_OwnParent_ReturnsCycle(t *testing.T) {
+	svc := NewCategoryService(categoryChain())
+
+	_, err := svc.UpdateCategory(2, UpdateCategoryInput{Name: "Men", ParentID: NullableID{Set: true, Value: uintPtr(2)}})
+	require.Error(t, err)
+
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "CATEGORY_CYCLE", serviceErr.Code)
+}
+
+func TestCategoryService_UpdateCategory_DescendantAsParent_ReturnsCycle(t *testing.T) {
+	updateCalled := false
+	repo := categoryChain()
+	repo.updateFn = func(c *models.Category) error {
+		updateCalled = true
+		return nil
+	}
+	svc := NewCategoryService(repo)
+
+	// Clothing under Shirts would make Clothing its own ancestor
+	_, err := svc.UpdateCategory(1, UpdateCategoryInput{Name: "Clothing", ParentID: NullableID{Set: true, Value: uintPtr(3)}})
+	require.Error(t, err)
+
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "CATEGORY_CYCLE", serviceErr.Code)
+	assert.False(t, updateCalled)
+}
+
+func TestCategoryService_UpdateCategory_Reparent_Succeeds(t *testing.T) {
+	svc := NewCategoryService(categoryChain())
+
+	category, err := svc.UpdateCategory(3, UpdateCategoryInput{Name: "Shirts", ParentID: NullableID{Set: true, Value: uintPtr(1)}})
+	require.NoError(t, err)
+	require.NotNil(t, category.ParentID)
+	assert.Equal(t, uint(1), *category.ParentID)
+}
+
+func TestCategoryService_UpdateCategory_NullParent_MakesTopLevel(t *testing.T) {
+	svc := NewCategoryService(categoryChain())
+
+	category, err := svc.UpdateCategory(3, UpdateCategoryInput{Name: "Shirts", ParentID: NullableID{Set: true}})
+	require.NoError(t, err)
+	assert.Nil(t, category.ParentID)
+}
+
+func TestCategoryService_UpdateCategory_ParentAbsent_KeepsParent(t *testing.T) {
+	svc := NewCategoryService(categoryChain())
+
+	category, err := svc.UpdateCategory(3, UpdateCategoryInput{Name: "Shirts"})
+	require.NoError(t, err)
+	require.NotNil(t, category.ParentID)
+	assert.Equal(t, uint(2), *category.ParentID)
+}
+
+func TestUpdateCategoryInput_ParentID_DistinguishesAbsentFromNull(t *testing.T) {
+	var absent, null, set UpdateCategoryInput
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"Shirts"}`), &absent))
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"Shirts","parentId":null}`), &null))
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"Shirts","parentId":4}`), &set))
+
+	assert.False(t, absent.ParentID.Set)
+	assert.True(t, null.ParentID.Set)
+	assert.Nil(t, null.ParentID.Value)
+	assert.True(t, set.ParentID.Set)
+	require.NotNil(t, set.ParentID.Value)
+	assert.Equal(t, uint(4), *set.ParentID.Value)
+}
+
+func TestCategoryService_DeleteCategory_HasChildren_ReturnsConflict(t *testing.T) {
+	deleteCalled := false
+	repo := &mockCategoryRepo{
+		getByIDFn: func(id uint) (*models.Category, error) {
+			return &models.Category{
+				ID:       id,
+				Name:     "Clothing",
+				Children: []models.Category{{ID: 2, Name: "Men", ParentID: uintPtr(id)}},
+			}, nil
+		},
+		deleteFn: func(id uint) error {
+			deleteCalled = true
+			return nil
+		},
+	}
+	svc := NewCategoryService(repo)
+
+	err := svc.DeleteCategory(1)
+	require.Error(t, err)
+
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrConflict, serviceErr.Err)
+	assert.Equal(t, "CATEGORY_HAS_CHILDREN", serviceErr.Code)
+	assert.False(t, deleteCalled)
+}
+
+func TestCategoryService_CategoryTree_NestsChildren(t *testing.T) {
+	repo := &mockCategoryRepo{
+		listAllFn: func() ([]models.Category, error) {
+			return []models.Category{
+				{ID: 1, Name: "Clothing"},
+				{ID: 4, Name: "Food"},
+				{ID: 2, Name: "Men", ParentID: uintPtr(1)},
+				{ID: 3, Name: "Shirts", ParentID: uintPtr(2)},
+				{ID: 5, Name: "Women", ParentID: uintPtr(1)},
+			}, nil
+		},
+	}
+	svc := NewCategoryService(repo)
+
+	tree, err := svc.CategoryTree()
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+	assert.Equal(t, "Clothing", tree[0].Name)
+	assert.Equal(t, "Food", tree[1].Name)
+	assert.Empty(t, tree[1].Children)
+
+	require.Len(t, tree[0].Children, 2)
+	assert.Equal(t, "Men", tree[0].Children[0].Name)
+	assert.Equal(t, "Women", tree[0].Children[1].Name)
+	require.Len(t, tree[0].Children[0].Children, 1)
+	assert.Equal(t, "Shirts", tree[0].Children[0].Children[0].Name)
+}
+
+func TestCategoryService_CategoryTree_RepoError_ReturnsInternalError(t *testing.T) {
+	repo := &mockCategoryRepo{
+		listAllFn: func() ([]models.Category, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	svc := NewCategoryService(repo)
+
+	_, err := svc.CategoryTree()
+	require.Error(t, err)
+
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "INTERNAL_ERROR", serviceErr.Code)
+}
+
+func TestCategoryService_CreateCategory_WhitespaceOnlyName_ReturnsValidation(t *testing.T) {
+	repo := &mockCategoryRepo{
+		createFn: func(c *models.Category) error {
+			t.Fatal("create should not be called")
+			return nil
+		},
+	}
+	svc := NewCategoryService(repo)
+
+	_, err := svc.CreateCategory(CreateCategoryInput{Name: " \t "})
+	require.Error(t, err)
+
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "Name is required", serviceErr.Message)
+}
+
+func TestCategoryService_CreateCategory_PaddedName_StoredTrimmed(t *testing.T) {
+	var saved *models.Category
+	repo := &mockCategoryRepo{
+		createFn: func(c *models.Category) error {
+			saved = c
+			return nil
+		},
+	}
+	svc := NewCategoryService(repo)
+
+	_, err := svc.CreateCategory(CreateCategoryInput{Name: "  Beverages  "})
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, "Beverages", saved.Name)
+}
+
+func TestCategoryService_UpdateCategory_WhitespaceOnlyName_ReturnsValidation(t *testing.T) {
+	repo := &mockCategoryRepo{
+		getByIDFn: func(id uint) (*models.Category, error) {
+			return &models.Category{ID: id, Name: "Old Name"}, nil
+		},
+		updateFn: func(c *models.Category) error {
+			t.Fatal("update should not be called")
+			return nil
+		},
+	}
+	svc := NewCategoryService(repo)
+
+	_, err := svc.UpdateCategory(1, UpdateCategoryInput{Name: "   "})
+	require.Error(t, err)
+
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "Name is required", serviceErr.Message)
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// discountCeiling returns the highest line discount the cashier may give:
+// the highest ceiling among their roles listed in RoleMaxDiscounts, or
+// MaxDiscountPercent when none of their roles is listed.
+func (s *SalesService) discountCeiling(cashierID uint) (float64, error) {
+	if len(s.cfg.RoleMaxDiscounts) == 0 || cashierID == 0 {
+		return s.cfg.MaxDiscountPercent, nil
+	}
+
+	var roleNames []string
+	err := s.db.Table("roles").
+		Joins("JOIN user_roles ur ON ur.role_id = roles.id").
+		Where("ur.user_id = ?", cashierID).
+		Pluck("roles.name", &roleNames).Error
+	if err != nil {
+		return 0, err
+	}
+
+	ceiling, found := 0.0, false
+	for _, name := range roleNames {
+		if limit, ok := s.cfg.RoleMaxDiscounts[strings.ToLower(name)]; ok && (!found || limit > ceiling) {
+			ceiling, found = limit, true
+		}
+	}
+	if !found {
+		return s.cfg.MaxDiscountPercent, nil
+	}
+	return ceiling, nil
+}
+
+// checkDiscountCeiling rejects a checkout whose largest line discount,
+// including the line's share of any sale discount, is above the cashier's
+// ceiling.
+func (s *SalesService) checkDiscountCeiling(cashierID uint, discount float64) error {
+	ceiling, err := s.discountCeiling(cashierID)
+	if err != nil {
+		return &ServiceError{Err: err, Message: "Failed to check discount limit", Code: "INTERNAL_ERROR"}
+	}
+	if discount > ceiling {
+		return &ServiceError{
+			Err:     ErrForbidden,
+			Message: fmt.Sprintf("Discount of %g%% exceeds your limit of %g%%", discount, ceiling),
+			Code:    "DISCOUNT_LIMIT_EXCEEDED",
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCheckoutGateTest(t *testing.T, limit int) (*checkoutGate, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return &checkoutGate{redis: rdb, limit: limit, now: time.Now}, mr
+}
+
+func TestCheckoutGate_ConcurrentSameVariant_AtMostLimitInFlight(t *testing.T) {
+	gate, _ := setupCheckoutGateTest(t, 2)
+	ctx := context.Background()
+
+	var (
+		mu          sync.Mutex
+		inFlight    int
+		maxInFlight int
+		busy        int
+		attempts    sync.WaitGroup
+		finished    sync.WaitGroup
+	)
+	done := make(chan struct{})
+
+	for i := 0; i < 10; i++ {
+		attempts.Add(1)
+		finished.Add(1)
+		go func() {
+			defer finished.Done()
+			release, ok := gate.acquire(ctx, []string{"variant-a"})
+			mu.Lock()
+			if ok {
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+			} else {
+				busy++
+			}
+			mu.Unlock()
+			attempts.Done()
+			if !ok {
+				return
+			}
+
+			// Hold the slot until every checkout has tried to get in
+			<-done
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			release()
+		}()
+	}
+	attempts.Wait()
+	close(done)
+	finished.Wait()
+
+	assert.Equal(t, 2, maxInFlight)
+	assert.Equal(t, 8, busy)
+}
+
+func TestCheckoutGate_DifferentVariants_ProceedIndependently(t *testing.T) {
+	gate, _ := setupCheckoutGateTest(t, 1)
+	ctx := context.Background()
+
+	releaseA, ok := gate.acquire(ctx, []string{"variant-a"})
+	require.True(t, ok)
+	defer releaseA()
+
+	_, ok = gate.acquire(ctx, []string{"variant-a"})
+	assert.False(t, ok)
+
+	releaseB, ok := gate.acquire(ctx, []string{"variant-b"})
+	assert.True(t, ok)
+	releaseB()
+}
+
+func TestCheckoutGate_Release_FreesSlot(t *testing.T) {
+	gate, _ := setupCheckoutGateTest(t, 1)
+	ctx := context.Background()
+
+	release, ok := gate.acquire(ctx, []string{"variant-a"})
+	require.True(t, ok)
+	release()
+
+	_, ok = gate.acquire(ctx, []string{"variant-a"})
+	assert.True(t, ok)
+}
+
+func TestCheckoutGate_OneVariantFull_GivesBackOtherSlots(t *testing.T) {
+	gate, mr := setupCheckoutGateTest(t, 1)
+	ctx := context.Background()
+
+	releaseB, ok := gate.acquire(ctx, []string{"variant-b"})
+	require.True(t, ok)
+	defer releaseB()
+
+	_, ok = gate.acquire(ctx, []string{"variant-a", "variant-b"})
+	assert.False(t, ok)
+
+	members, _ := mr.ZMembers(checkoutGateKey("variant-a"))
+	assert.Empty(t, members)
+}
+
+func TestCheckoutGate_DuplicateVariantInCart_TakesOneSlot(t *testing.T) {
+	gate, _ := setupCheckoutGateTest(t, 1)
+
+	release, ok := gate.acquire(context.Background(), []string{"variant-a", "variant-a"})
+
+	assert.True(t, ok)
+	release()
+}
+
+func TestCheckoutGate_ExpiredLease_IsReclaimed(t *testing.T) {
+	gate, _ := setupCheckoutGateTest(t, 1)
+	ctx := context.Background()
+
+	_, ok := gate.acquire(ctx, []string{"variant-a"})
+	require.True(t, ok)
+
+	// The first checkout never released its slot
+	now := time.Now().Add(checkoutGateLease + time.Second)
+	gate.now = func() time.Time { return now }
+
+	_, ok = gate.acquire(ctx, []string{"variant-a"})
+	assert.True(t, ok)
+}
+
+func TestCheckoutGate_RedisUnavailable_FailsOpen(t *testing.T) {
+	gate, mr := setupCheckoutGateTest(t, 1)
+	mr.Close()
+
+	release, ok := gate.acquire(context.Background(), []string{"variant-a"})
+
+	assert.True(t, ok)
+	release()
+}
+
+func TestCheckout_VariantGateFull_ReturnsBusyError(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	svc := NewSalesService(nil, nil, nil, SalesConfig{VariantCheckoutLimit: 1, Redis: rdb})
+
+	release, ok := svc.gate.acquire(context.Background(), []string{"variant-a"})
+	require.True(t, ok)
+	defer release()
+
+	result, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items:         []CheckoutItemInput{{ProductID: 1, VariantID: "variant-a", UnitID: 1, Quantity: 1}},
+	})
+
+	assert.Nil(t, result)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrBusy, svcErr.Err)
+	assert.Equal(t, "CHECKOUT_BUSY", svcErr.Code)
+}
+
+func TestNewSalesService_NoVariantLimit_DisablesGate(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	svc := NewSalesService(nil, nil, nil, SalesConfig{Redis: rdb})
+
+	assert.Nil(t, svc.gate)
+}
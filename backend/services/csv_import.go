@@ -0,0 +1,88 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// importCSV is a parsed import file. Columns maps lower-cased header names to
+// their position.
+type importCSV struct {
+	columns map[string]int
+	rows    []importCSVRow
+}
+
+// importCSVRow is one data record. Line is its position in the file, counting
+// the header as line 1; err is set when the record could not be parsed.
+type importCSVRow struct {
+	line   int
+	record []string
+	err    error
+}
+
+// readImportCSV reads a CSV with a header row that must name every required
+// column. Every row is read up front so an oversized file imports nothing.
+// Malformed records are kept, with their parse error, so they can be
+// reported by line.
+func readImportCSV(reader io.Reader, required []string, maxRows int) (*importCSV, *ServiceError) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "CSV file is empty or unreadable",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	file := &importCSV{columns: make(map[string]int, len(header))}
+	for i, name := range header {
+		file.columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, column := range required {
+		if _, ok := file.columns[column]; !ok {
+			return nil, &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("CSV header must include a %q column", column),
+				Code:    "VALIDATION_ERROR",
+			}
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, &ServiceError{Err: err, Message: "Failed to read CSV file", Code: "INTERNAL_ERROR"}
+			}
+		}
+		file.rows = append(file.rows, importCSVRow{line: line, record: record, err: err})
+		if len(file.rows) > maxRows {
+			return nil, &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("CSV file cannot have more than %d rows", maxRows),
+				Code:    "TOO_MANY_ROWS",
+			}
+		}
+	}
+	return file, nil
+}
+
+// field returns the trimmed value of a column, or "" when the file has no
+// such column or the record is short.
+func (f *importCSV) field(record []string, column string) string {
+	i, ok := f.columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
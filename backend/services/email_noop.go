@@ -0,0 +1,30 @@
+package services
+
+// NoopEmailService satisfies EmailService, UserEmailService and POMailer
+// without sending anything. It is used when email is disabled and in place
+// of a nil email service, so callers never need to check for one.
+type NoopEmailService struct{}
+
+func (NoopEmailService) SendWelcomeEmail(toEmail, userName string) error { return nil }
+
+func (NoopEmailService) SendPasswordResetEmail(toEmail, userName, resetLink string) error {
+	return nil
+}
+
+func (NoopEmailService) SendAccountApprovedEmail(toEmail, userName string) error { return nil }
+
+func (NoopEmailService) SendVerificationEmail(toEmail, userName, verifyLink string) error {
+	return nil
+}
+
+func (NoopEmailService) SendUserCredentials(toEmail, userName, tempPassword string) error {
+	return nil
+}
+
+func (NoopEmailService) SendUserApproved(toEmail, userName string) error { return nil }
+
+func (NoopEmailService) SendUserRejected(toEmail, userName string) error { return nil }
+
+func (NoopEmailService) SendPurchaseOrder(supplierEmail, poNumber string, pdfBytes []byte) error {
+	return nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestNewUserService_NilEmailService_UsesNoop(t *testing.T) {
+	service := NewUserService(&mockUserRepository{}, nil, nil, nil)
+	assert.Equal(t, NoopEmailService{}, service.emailService)
+}
+
+func TestNewAuthService_NilEmailService_UsesNoop(t *testing.T) {
+	service := NewAuthService(&mockUserRepo{}, nil, nil, nil)
+	assert.Equal(t, NoopEmailService{}, service.emailService)
+}
+
+func TestNewPOService_NilMailer_UsesNoop(t *testing.T) {
+	svc := NewPOService(nil, &mockPORepo{}, &mockStockRepo{}, nil)
+	assert.Equal(t, NoopEmailService{}, svc.cfg.Mailer)
+}
+
+// spyUserEmailService counts every send it is asked to make.
+type spyUserEmailService struct {
+	sends int
+}
+
+func (s *spyUserEmailService) SendUserCredentials(toEmail, userName, tempPassword string) error {
+	s.sends++
+	return nil
+}
+
+func (s *spyUserEmailService) SendUserApproved(toEmail, userName string) error {
+	s.sends++
+	return nil
+}
+
+func (s *spyUserEmailService) SendUserRejected(toEmail, userName string) error {
+	s.sends++
+	return nil
+}
+
+func TestCreateUser_EmailDisabled_ReturnsTempPasswordWithoutSending(t *testing.T) {
+	var passwordHash string
+	repo := &mockUserRepository{
+		findByEmailFn: func(email string) (*models.User, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+		createFn: func(user *models.User) error {
+			user.ID = 1
+			passwordHash = user.PasswordHash
+			return nil
+		},
+	}
+	spy := &spyUserEmailService{}
+	service := NewUserService(repo, nil, nil, spy)
+	service.SetEmailEnabled(false)
+
+	user, err := service.CreateUser(CreateUserInput{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	assert.Equal(t, "active", user.Status)
+	assert.Equal(t, 0, spy.sends)
+	require.NotEmpty(t, user.TempPassword)
+	match, _ := utils.VerifyPassword(passwordHash, user.TempPassword)
+	assert.True(t, match)
+}
+
+func TestCreateUser_EmailEnabled_DoesNotReturnTempPassword(t *testing.T) {
+	repo := &mockUserRepository{
+		findByEmailFn: func(email string) (*models.User, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+		createFn: func(user *models.User) error {
+			user.ID = 1
+			return nil
+		},
+	}
+	spy := &spyUserEmailService{}
+	service := NewUserService(repo, nil, nil, spy)
+
+	user, err := service.CreateUser(CreateUserInput{Name: "John Doe", Email: "john@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, spy.sends)
+	assert.Empty(t, user.TempPassword)
+}
+
+func TestBulkImport_EmailDisabled_ReturnsTempPasswordPerCreatedRow(t *testing.T) {
+	service, created, _, emailed := newImportTestService(t)
+	service.SetEmailEnabled(false)
+
+	csvData := "name,email\nJane Doe,jane@example.com\nExisting,existing@example.com\n"
+	result, err := service.BulkImport(strings.NewReader(csvData))
+
+	require.NoError(t, err)
+	require.Len(t, result.Rows, 2)
+	assert.Empty(t, *emailed)
+
+	row := result.Rows[0]
+	assert.Equal(t, UserImportCreated, row.Status)
+	require.NotEmpty(t, row.TempPassword)
+	match, _ := utils.VerifyPassword(created["jane@example.com"].PasswordHash, row.TempPassword)
+	assert.True(t, match)
+	assert.Empty(t, result.Rows[1].TempPassword)
+}
+
+func TestApproveUser_EmailDisabled_ApprovesUnverifiedWithoutSending(t *testing.T) {
+	pendingUser := &models.User{ID: 1, Email: "john@example.com", Status: "pending", EmailVerified: false}
+	repo := &mockUserRepository{
+		findByIDFn: func(id uint) (*models.User, error) {
+			return pendingUser, nil
+		},
+		updateFn: func(user *models.User) error {
+			return nil
+		},
+	}
+	spy := &spyUserEmailService{}
+	service := NewUserService(repo, nil, nil, spy)
+	service.SetEmailEnabled(false)
+
+	user, err := service.ApproveUser(1)
+	require.NoError(t, err)
+	assert.Equal(t, "active", user.Status)
+	assert.Equal(t, 0, spy.sends)
+}
+
+func TestResendCredentials_EmailDisabled_ReturnsTempPasswordWithoutSending(t *testing.T) {
+	service, _, user, _, _ := setupChangePasswordTest(t)
+	spy := &spyUserEmailService{}
+	service.emailService = spy
+	service.SetEmailEnabled(false)
+
+	tempPassword, err := service.ResendCredentials(1)
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, spy.sends)
+	require.NotEmpty(t, tempPassword)
+	match, _ := utils.VerifyPassword(user.PasswordHash, tempPassword)
+	assert.True(t, match)
+}
+
+func TestSendToSupplier_EmailDisabled_ReturnsNotConfigured(t *testing.T) {
+	po := newEmailTestPO("sent", "orders@acme.test")
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) { return po, nil },
+	}
+	mailer := &mockPOMailer{}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil, POConfig{Mailer: mailer, EmailDisabled: true})
+
+	err := svc.SendToSupplier(1)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "EMAIL_NOT_CONFIGURED", serviceErr.Code)
+	assert.Empty(t, mailer.sentTo)
+}
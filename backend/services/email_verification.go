@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/utils"
+)
+
+// emailVerificationTTL is how long an email verification link stays valid.
+const emailVerificationTTL = 24 * time.Hour
+
+// sendVerificationEmail stores a verification token for the user and emails
+// them the link to confirm their address.
+func (s *AuthService) sendVerificationEmail(ctx context.Context, user *models.User) error {
+	token, err := utils.GenerateResetToken()
+	if err != nil {
+		return err
+	}
+	if err := s.redis.Set(ctx, "verify:"+token, fmt.Sprintf("%d", user.ID), emailVerificationTTL).Err(); err != nil {
+		return err
+	}
+
+	verifyLink := fmt.Sprintf("%s/verify-email?token=%s", s.config.FrontendURL, token)
+	_ = s.emailService.SendVerificationEmail(user.Email, user.Name, verifyLink)
+	return nil
+}
+
+// ResendVerification emails a new verification link to a pending user whose
+// address is not verified yet, e.g. after the previous link expired. Like
+// ForgotPassword it always succeeds so it does not reveal which emails exist.
+func (s *AuthService) ResendVerification(email string) *ServiceError {
+	user, err := s.userRepo.FindByEmail(strings.ToLower(email))
+	if err == nil && user != nil && user.Status == "pending" && !user.EmailVerified {
+		_ = s.sendVerificationEmail(context.Background(), user)
+	}
+	return nil
+}
+
+// VerifyEmail marks the email of the user the token was issued to as verified.
+// Tokens can only be used once.
+func (s *AuthService) VerifyEmail(token string) *ServiceError {
+	if token == "" {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Token is required",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	ctx := context.Background()
+	userIDStr, err := s.redis.Get(ctx, "verify:"+token).Result()
+	if err != nil {
+		return &ServiceError{
+			Err:     ErrUnauthorized,
+			Message: "Invalid or expired verification token",
+			Code:    "INVALID_TOKEN",
+		}
+	}
+
+	var userID uint
+	if _, err := fmt.Sscanf(userIDStr, "%d", &userID); err != nil {
+		return &ServiceError{
+			Err:     ErrUnauthorized,
+			Message: "Invalid verification token",
+			Code:    "INVALID_TOKEN",
+		}
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return &ServiceError{
+			Err:     ErrNotFound,
+			Message: "User not found",
+			Code:    "USER_NOT_FOUND",
+		}
+	}
+
+	if !user.EmailVerified {
+		user.EmailVerified = true
+		if err := s.userRepo.Update(user); err != nil {
+			return &ServiceError{
+				Err:     err,
+				Message: "Failed to verify email",
+				Code:    "INTERNAL_ERROR",
+			}
+		}
+	}
+
+	s.redis.Del(ctx, "verify:"+token)
+	return nil
+}
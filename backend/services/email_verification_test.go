@@ -0,0 +1,173 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister_ValidInput_StoresVerifyTokenAndSendsEmail(t *testing.T) {
+	service, mockRepo, _, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		return nil, errors.New("not found")
+	}
+	mockRepo.createFn = func(user *models.User) error {
+		user.ID = 42
+		return nil
+	}
+	var sentTo, sentLink string
+	service.emailService = &mockEmailService{
+		sendVerificationFn: func(toEmail, userName, verifyLink string) error {
+			sentTo = toEmail
+			sentLink = verifyLink
+			return nil
+		},
+	}
+
+	user, svcErr := service.Register(RegisterInput{
+		Name:            "John Doe",
+		Email:           "John@Example.com",
+		Password:        "Password123!",
+		ConfirmPassword: "Password123!",
+	})
+
+	require.Nil(t, svcErr)
+	assert.False(t, user.EmailVerified)
+	assert.Equal(t, "john@example.com", sentTo)
+	require.True(t, strings.HasPrefix(sentLink, "http://localhost:3000/verify-email?token="))
+
+	token := strings.TrimPrefix(sentLink, "http://localhost:3000/verify-email?token=")
+	storedID, err := mr.Get("verify:" + token)
+	require.NoError(t, err)
+	assert.Equal(t, "42", storedID)
+	assert.Equal(t, emailVerificationTTL, mr.TTL("verify:"+token))
+}
+
+func TestVerifyEmail_ValidToken_MarksEmailVerified(t *testing.T) {
+	service, mockRepo, _, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	user := &models.User{ID: 1, Email: "john@example.com", Status: "pending"}
+	mockRepo.findByIDFn = func(id uint) (*models.User, error) {
+		return user, nil
+	}
+	var updated *models.User
+	mockRepo.updateFn = func(u *models.User) error {
+		updated = u
+		return nil
+	}
+	mr.Set("verify:valid-token", "1")
+
+	svcErr := service.VerifyEmail("valid-token")
+
+	require.Nil(t, svcErr)
+	require.NotNil(t, updated)
+	assert.True(t, updated.EmailVerified)
+	assert.Equal(t, "pending", updated.Status)
+	assert.False(t, mr.Exists("verify:valid-token"))
+}
+
+func TestVerifyEmail_InvalidToken_ReturnsUnauthorized(t *testing.T) {
+	service, _, _, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	svcErr := service.VerifyEmail("unknown-token")
+
+	require.NotNil(t, svcErr)
+	assert.Equal(t, ErrUnauthorized, svcErr.Err)
+	assert.Equal(t, "INVALID_TOKEN", svcErr.Code)
+}
+
+func TestVerifyEmail_EmptyToken_ReturnsValidationError(t *testing.T) {
+	service, _, _, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	svcErr := service.VerifyEmail("")
+
+	require.NotNil(t, svcErr)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+}
+
+func TestRegister_VerificationTokenNotStored_StillCreatesUser(t *testing.T) {
+	service, mockRepo, _, mr, _ := setupAuthServiceTest(t)
+
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		return nil, errors.New("not found")
+	}
+	created := false
+	mockRepo.createFn = func(user *models.User) error {
+		created = true
+		user.ID = 42
+		return nil
+	}
+	mr.Close()
+
+	user, svcErr := service.Register(RegisterInput{
+		Name:            "John Doe",
+		Email:           "john@example.com",
+		Password:        "Password123!",
+		ConfirmPassword: "Password123!",
+	})
+
+	require.Nil(t, svcErr)
+	assert.True(t, created)
+	assert.Equal(t, uint(42), user.ID)
+}
+
+func TestResendVerification_PendingUnverified_SendsNewLink(t *testing.T) {
+	service, mockRepo, _, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		assert.Equal(t, "john@example.com", email)
+		return &models.User{ID: 7, Name: "John", Email: email, Status: "pending"}, nil
+	}
+	var sentLink string
+	service.emailService = &mockEmailService{
+		sendVerificationFn: func(toEmail, userName, verifyLink string) error {
+			sentLink = verifyLink
+			return nil
+		},
+	}
+
+	svcErr := service.ResendVerification("John@Example.com")
+
+	require.Nil(t, svcErr)
+	require.True(t, strings.HasPrefix(sentLink, "http://localhost:3000/verify-email?token="))
+	token := strings.TrimPrefix(sentLink, "http://localhost:3000/verify-email?token=")
+	storedID, err := mr.Get("verify:" + token)
+	require.NoError(t, err)
+	assert.Equal(t, "7", storedID)
+}
+
+func TestResendVerification_VerifiedOrUnknown_SendsNothing(t *testing.T) {
+	service, mockRepo, _, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	sent := 0
+	service.emailService = &mockEmailService{
+		sendVerificationFn: func(toEmail, userName, verifyLink string) error {
+			sent++
+			return nil
+		},
+	}
+
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		return &models.User{ID: 7, Email: email, Status: "pending", EmailVerified: true}, nil
+	}
+	require.Nil(t, service.ResendVerification("john@example.com"))
+
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		return nil, errors.New("not found")
+	}
+	require.Nil(t, service.ResendVerification("nobody@example.com"))
+
+	assert.Equal(t, 0, sent)
+	assert.Empty(t, mr.Keys())
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pointofsale/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// itemReference is the product, variant and unit named by one line item.
+type itemReference struct {
+	ProductID uint
+	VariantID string
+	UnitID    uint
+}
+
+// referenceRules selects the checks loadItemReferences makes beyond
+// existence and active status.
+type referenceRules struct {
+	// RequireOwnership rejects lines whose variant or unit belongs to a
+	// different product than the line's product.
+	RequireOwnership bool
+	// LockVariants locks the referenced variants FOR UPDATE, in ID order so
+	// concurrent requests cannot deadlock on each other.
+	LockVariants bool
+}
+
+// itemReferences holds the products, variants and units named by a request's
+// line items, keyed by ID. Variants come with their attributes.
+type itemReferences struct {
+	products map[uint]models.Product
+	variants map[string]models.ProductVariant
+	units    map[uint]models.ProductUnit
+}
+
+// referenceProblem is one invalid reference, with the error code it would
+// carry on its own.
+type referenceProblem struct {
+	code    string
+	message string
+}
+
+// loadItemReferences loads every product, variant and unit referenced by refs
+// with one query per table and checks them against rules. Lines of products
+// that are not active are always rejected. All invalid
+// references are reported together in a single validation error, so a client
+// can fix a request in one round trip.
+func loadItemReferences(db *gorm.DB, refs []itemReference, rules referenceRules) (*itemReferences, error) {
+	loaded, err := fetchItemReferences(db, refs, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []referenceProblem
+	for i, ref := range refs {
+		problems = append(problems, loaded.check(i, ref, rules)...)
+	}
+	if svcErr := referenceError(problems); svcErr != nil {
+		return nil, svcErr
+	}
+	return loaded, nil
+}
+
+// fetchItemReferences loads the products, variants and units referenced by
+// refs with one query per table, without checking them. References that do
+// not exist are simply absent from the result.
+func fetchItemReferences(db *gorm.DB, refs []itemReference, rules referenceRules) (*itemReferences, error) {
+	productIDs := make([]uint, 0, len(refs))
+	variantIDs := make([]string, 0, len(refs))
+	unitIDs := make([]uint, 0, len(refs))
+	seenVariants := make(map[string]bool, len(refs))
+	for _, ref := range refs {
+		productIDs = append(productIDs, ref.ProductID)
+		unitIDs = append(unitIDs, ref.UnitID)
+		// A malformed ID cannot match a row and would fail the whole query.
+		if _, err := uuid.Parse(ref.VariantID); err == nil && !seenVariants[ref.VariantID] {
+			seenVariants[ref.VariantID] = true
+			variantIDs = append(variantIDs, ref.VariantID)
+		}
+	}
+
+	var products []models.Product
+	if err := db.Where("id IN ?", productIDs).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	var variants []models.ProductVariant
+	if len(variantIDs) > 0 {
+		query := db.Preload("Attributes").Where("id IN ?", variantIDs).Order("id")
+		if rules.LockVariants {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+		}
+		if err := query.Find(&variants).Error; err != nil {
+			return nil, err
+		}
+	}
+	var units []models.ProductUnit
+	if err := db.Where("id IN ?", unitIDs).Find(&units).Error; err != nil {
+		return nil, err
+	}
+
+	loaded := &itemReferences{
+		products: make(map[uint]models.Product, len(products)),
+		variants: make(map[string]models.ProductVariant, len(variants)),
+		units:    make(map[uint]models.ProductUnit, len(units)),
+	}
+	for _, p := range products {
+		loaded.products[p.ID] = p
+	}
+	for _, v := range variants {
+		loaded.variants[v.ID] = v
+	}
+	for _, u := range units {
+		loaded.units[u.ID] = u
+	}
+	return loaded, nil
+}
+
+// check returns the problems with the references of line i.
+func (r *itemReferences) check(i int, ref itemReference, rules referenceRules) []referenceProblem {
+	var problems []referenceProblem
+	product, hasProduct := r.products[ref.ProductID]
+	variant, hasVariant := r.variants[ref.VariantID]
+	unit, hasUnit := r.units[ref.UnitID]
+
+	if !hasProduct {
+		problems = append(problems, referenceProblem{"PRODUCT_NOT_FOUND", fmt.Sprintf("items[%d]: product %d not found", i, ref.ProductID)})
+	} else if product.Status != "active" {
+		problems = append(problems, referenceProblem{"PRODUCT_INACTIVE", fmt.Sprintf("items[%d]: %s is inactive", i, product.Name)})
+	}
+	if !hasVariant {
+		problems = append(problems, referenceProblem{"VARIANT_NOT_FOUND", fmt.Sprintf("items[%d]: variant %s not found", i, ref.VariantID)})
+	}
+	if !hasUnit {
+		problems = append(problems, referenceProblem{"UNIT_NOT_FOUND", fmt.Sprintf("items[%d]: unit %d not found", i, ref.UnitID)})
+	}
+
+	if rules.RequireOwnership && hasProduct {
+		if hasVariant && variant.ProductID != product.ID {
+			problems = append(problems, referenceProblem{"VARIANT_PRODUCT_MISMATCH", fmt.Sprintf("items[%d]: variant %s does not belong to %s", i, variant.ID, product.Name)})
+		}
+		if hasUnit && unit.ProductID != product.ID {
+			problems = append(problems, referenceProblem{"UNIT_PRODUCT_MISMATCH", fmt.Sprintf("items[%d]: unit %d does not belong to %s", i, unit.ID, product.Name)})
+		}
+	}
+	return problems
+}
+
+// referenceError folds problems into one validation error. A single problem
+// keeps its own code; several are reported as INVALID_REFERENCES.
+func referenceError(problems []referenceProblem) *ServiceError {
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return &ServiceError{Err: ErrValidation, Message: problems[0].message, Code: problems[0].code}
+	}
+
+	messages := make([]string, 0, len(problems))
+	for _, p := range problems {
+		messages = append(messages, p.message)
+	}
+	return &ServiceError{
+		Err:     ErrValidation,
+		Message: fmt.Sprintf("%d invalid item references: %s", len(problems), strings.Join(messages, "; ")),
+		Code:    "INVALID_REFERENCES",
+	}
+}
+
+// checkoutItemReferences lists the references of checkout or cart lines.
+func checkoutItemReferences(items []CheckoutItemInput) []itemReference {
+	refs := make([]itemReference, 0, len(items))
+	for _, item := range items {
+		refs = append(refs, itemReference{ProductID: item.ProductID, VariantID: item.VariantID, UnitID: item.UnitID})
+	}
+	return refs
+}
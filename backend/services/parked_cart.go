@@ -0,0 +1,287 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"gorm.io/gorm"
+)
+
+// Maximum lengths of the free-text fields on a parked cart
+const (
+	maxParkedCartLabel    = 100
+	maxParkedCartCustomer = 255
+)
+
+// ParkCartInput is a cart set aside to be resumed later, at any terminal.
+// Line discounts and the sale Discount are kept as given; the cashier's
+// discount ceiling is checked when the cart is checked out.
+type ParkCartInput struct {
+	Label        string              `json:"label"`
+	CustomerName string              `json:"customerName"`
+	Items        []CheckoutItemInput `json:"items"`
+	Discount     float64             `json:"discount"`
+	CashierID    uint                `json:"-"`
+}
+
+// ResumedCartItem is a parked line checked against current stock. Issue holds
+// a machine-readable reason when the line can no longer be sold as parked.
+type ResumedCartItem struct {
+	ProductID    uint   `json:"productId"`
+	VariantID    string `json:"variantId"`
+	UnitID       uint   `json:"unitId"`
+	Quantity     int    `json:"quantity"`
+	ProductName  string `json:"productName"`
+	VariantLabel string `json:"variantLabel"`
+	UnitName     string `json:"unitName"`
+	// DiscountPercent and DiscountAmount are the line discount as parked
+	DiscountPercent float64 `json:"discountPercent"`
+	DiscountAmount  float64 `json:"discountAmount"`
+	BaseQty         int     `json:"baseQty"`
+	CurrentStock    int     `json:"currentStock"`
+	Available       bool    `json:"available"`
+	Issue           string  `json:"issue,omitempty"`
+	Message         string  `json:"message,omitempty"`
+}
+
+// ResumedCart is a parked cart re-validated for checkout. CheckoutItems holds
+// the lines that can still be sold, with their discounts, ready to seed a
+// checkout together with the sale Discount.
+type ResumedCart struct {
+	ID            uint                `json:"id"`
+	Label         string              `json:"label"`
+	CustomerName  string              `json:"customerName"`
+	CashierID     *uint               `json:"cashierId,omitempty"`
+	Discount      float64             `json:"discount"`
+	CreatedAt     time.Time           `json:"createdAt"`
+	AllAvailable  bool                `json:"allAvailable"`
+	Items         []ResumedCartItem   `json:"items"`
+	CheckoutItems []CheckoutItemInput `json:"checkoutItems"`
+}
+
+// ParkCart saves a cart so it can be resumed later. Stock is not reserved;
+// availability is checked again when the cart is resumed.
+func (s *SalesService) ParkCart(input ParkCartInput) (*models.ParkedCart, error) {
+	label := strings.TrimSpace(input.Label)
+	customer := strings.TrimSpace(input.CustomerName)
+	if len(label) > maxParkedCartLabel {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: fmt.Sprintf("Label must be at most %d characters", maxParkedCartLabel),
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if len(customer) > maxParkedCartCustomer {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: fmt.Sprintf("Customer name must be at most %d characters", maxParkedCartCustomer),
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if len(input.Items) == 0 {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Cart is empty",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if len(input.Items) > s.cfg.MaxCartItems {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: fmt.Sprintf("Cart cannot have more than %d items", s.cfg.MaxCartItems),
+			Code:    "TOO_MANY_ITEMS",
+		}
+	}
+
+	if svcErr := validateCartQuantities(input.Items); svcErr != nil {
+		return nil, svcErr
+	}
+	if svcErr := validateCartDiscounts(input.Items, input.Discount); svcErr != nil {
+		return nil, svcErr
+	}
+
+	loaded, err := loadItemReferences(s.db, checkoutItemReferences(input.Items), referenceRules{
+		RequireOwnership: true,
+	})
+	if err != nil {
+		if svcErr, ok := err.(*ServiceError); ok {
+			return nil, svcErr
+		}
+		return nil, &ServiceError{Err: err, Message: "Failed to park cart", Code: "INTERNAL_ERROR"}
+	}
+
+	items := make([]models.ParkedCartItem, 0, len(input.Items))
+	for _, itemInput := range input.Items {
+		product := loaded.products[itemInput.ProductID]
+		variant := loaded.variants[itemInput.VariantID]
+		unit := loaded.units[itemInput.UnitID]
+
+		items = append(items, models.ParkedCartItem{
+			ProductID:       product.ID,
+			VariantID:       variant.ID,
+			UnitID:          unit.ID,
+			Quantity:        itemInput.Quantity,
+			ProductName:     product.Name,
+			VariantLabel:    buildSalesVariantLabel(variant.Attributes),
+			UnitName:        unit.Name,
+			DiscountPercent: itemInput.DiscountPercent,
+			DiscountAmount:  itemInput.DiscountAmount,
+		})
+	}
+
+	cart := &models.ParkedCart{
+		Label:        label,
+		CustomerName: customer,
+		Discount:     input.Discount,
+		TotalItems:   len(items),
+		Items:        items,
+	}
+	if input.CashierID != 0 {
+		cashierID := input.CashierID
+		cart.CashierID = &cashierID
+	}
+	if err := s.salesRepo.CreateParkedCart(cart); err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to park cart", Code: "INTERNAL_ERROR"}
+	}
+	return cart, nil
+}
+
+// ListParkedCarts returns parked carts, newest first. Search matches the
+// label or customer name.
+func (s *SalesService) ListParkedCarts(params repositories.PaginationParams) ([]models.ParkedCart, int64, error) {
+	return s.salesRepo.ListParkedCarts(params)
+}
+
+// ResumeParkedCart loads a parked cart and checks each line against current
+// stock. Lines for variants sold out or removed since parking are reported
+// as unavailable and left out of CheckoutItems. Lines of the same variant
+// draw on its stock in order, as they would at checkout.
+func (s *SalesService) ResumeParkedCart(id uint) (*ResumedCart, error) {
+	cart, err := s.salesRepo.GetParkedCart(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errParkedCartNotFound()
+		}
+		return nil, &ServiceError{Err: err, Message: "Failed to load parked cart", Code: "INTERNAL_ERROR"}
+	}
+
+	resumed := &ResumedCart{
+		ID:            cart.ID,
+		Label:         cart.Label,
+		CustomerName:  cart.CustomerName,
+		CashierID:     cart.CashierID,
+		Discount:      cart.Discount,
+		CreatedAt:     cart.CreatedAt,
+		AllAvailable:  true,
+		Items:         make([]ResumedCartItem, 0, len(cart.Items)),
+		CheckoutItems: []CheckoutItemInput{},
+	}
+	refs := make([]itemReference, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		refs = append(refs, itemReference{ProductID: item.ProductID, VariantID: item.VariantID, UnitID: item.UnitID})
+	}
+	loaded, err := fetchItemReferences(s.db, refs, referenceRules{})
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to load parked cart", Code: "INTERNAL_ERROR"}
+	}
+	claimed := make(map[string]int)
+
+	for _, item := range cart.Items {
+		line := ResumedCartItem{
+			ProductID:       item.ProductID,
+			VariantID:       item.VariantID,
+			UnitID:          item.UnitID,
+			Quantity:        item.Quantity,
+			ProductName:     item.ProductName,
+			VariantLabel:    item.VariantLabel,
+			UnitName:        item.UnitName,
+			DiscountPercent: item.DiscountPercent,
+			DiscountAmount:  item.DiscountAmount,
+		}
+
+		if issue, message := checkParkedItem(item, loaded, claimed, &line); issue != "" {
+			line.Issue = issue
+			line.Message = message
+			resumed.AllAvailable = false
+		} else {
+			line.Available = true
+			claimed[item.VariantID] += line.BaseQty
+			resumed.CheckoutItems = append(resumed.CheckoutItems, CheckoutItemInput{
+				ProductID:       item.ProductID,
+				VariantID:       item.VariantID,
+				UnitID:          item.UnitID,
+				Quantity:        item.Quantity,
+				DiscountPercent: item.DiscountPercent,
+				DiscountAmount:  item.DiscountAmount,
+			})
+		}
+		resumed.Items = append(resumed.Items, line)
+	}
+
+	return resumed, nil
+}
+
+// checkParkedItem fills in the current stock and base quantity of a parked
+// line and returns an issue code and message when it cannot be sold.
+// loaded holds the cart's products, variants and units; claimed holds base
+// quantities already taken by earlier lines.
+func checkParkedItem(item models.ParkedCartItem, loaded *itemReferences, claimed map[string]int, line *ResumedCartItem) (string, string) {
+	product, ok := loaded.products[item.ProductID]
+	if !ok {
+		return "PRODUCT_NOT_FOUND", fmt.Sprintf("%s is no longer available", item.ProductName)
+	}
+	if product.Status != "active" {
+		return "PRODUCT_INACTIVE", fmt.Sprintf("%s is no longer for sale", item.ProductName)
+	}
+
+	variant, ok := loaded.variants[item.VariantID]
+	if !ok {
+		return "VARIANT_NOT_FOUND", fmt.Sprintf("%s (%s) is no longer available", item.ProductName, item.VariantLabel)
+	}
+	if variant.ProductID != product.ID {
+		return "VARIANT_PRODUCT_MISMATCH", fmt.Sprintf("%s (%s) no longer belongs to %s", item.ProductName, item.VariantLabel, product.Name)
+	}
+	unit, ok := loaded.units[item.UnitID]
+	if !ok {
+		return "UNIT_NOT_FOUND", fmt.Sprintf("Unit %s of %s is no longer available", item.UnitName, item.ProductName)
+	}
+	if unit.ProductID != product.ID {
+		return "UNIT_PRODUCT_MISMATCH", fmt.Sprintf("Unit %s no longer belongs to %s", item.UnitName, product.Name)
+	}
+
+	line.CurrentStock = variant.CurrentStock
+	line.BaseQty = item.Quantity * int(unit.ToBaseUnit)
+
+	remaining := variant.CurrentStock - claimed[variant.ID]
+	if remaining <= 0 {
+		return "OUT_OF_STOCK", fmt.Sprintf("%s (%s) is out of stock", item.ProductName, item.VariantLabel)
+	}
+	if line.BaseQty > remaining {
+		return "INSUFFICIENT_STOCK", fmt.Sprintf("Insufficient stock for %s. Available: %d, requested: %d (base units)", item.ProductName, remaining, line.BaseQty)
+	}
+	return "", ""
+}
+
+// DeleteParkedCart discards a parked cart, typically once it has been
+// checked out or abandoned.
+func (s *SalesService) DeleteParkedCart(id uint) error {
+	deleted, err := s.salesRepo.DeleteParkedCart(id)
+	if err != nil {
+		return &ServiceError{Err: err, Message: "Failed to delete parked cart", Code: "INTERNAL_ERROR"}
+	}
+	if !deleted {
+		return errParkedCartNotFound()
+	}
+	return nil
+}
+
+func errParkedCartNotFound() *ServiceError {
+	return &ServiceError{
+		Err:     ErrNotFound,
+		Message: "Parked cart not found",
+		Code:    "PARKED_CART_NOT_FOUND",
+	}
+}
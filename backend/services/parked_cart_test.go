@@ -0,0 +1,289 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupParkedCartTest(t *testing.T) (*SalesService, *gorm.DB) {
+	t.Helper()
+	db := testutil.SetupTestDB(t)
+	return NewSalesService(db, repositories.NewSalesRepository(db), NewSequenceService(db)), db
+}
+
+func TestParkCart_ValidCart_StoresItemsWithNames(t *testing.T) {
+	svc, db := setupParkedCartTest(t)
+	product := testutil.CreateTestProduct(t, db)
+	cashier := testutil.CreateTestUser(t, db)
+
+	cart, err := svc.ParkCart(ParkCartInput{
+		Label:        " Table 4 ",
+		CustomerName: "Budi",
+		CashierID:    cashier.ID,
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 3},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.NotZero(t, cart.ID)
+	assert.Equal(t, "Table 4", cart.Label)
+	assert.Equal(t, "Budi", cart.CustomerName)
+	require.NotNil(t, cart.CashierID)
+	assert.Equal(t, cashier.ID, *cart.CashierID)
+	assert.Equal(t, 1, cart.TotalItems)
+
+	var items []models.ParkedCartItem
+	require.NoError(t, db.Where("cart_id = ?", cart.ID).Find(&items).Error)
+	require.Len(t, items, 1)
+	assert.Equal(t, 3, items[0].Quantity)
+	assert.Equal(t, product.Name, items[0].ProductName)
+	assert.Equal(t, product.Units[0].Name, items[0].UnitName)
+
+	// Parking does not touch stock
+	assert.Equal(t, 100, variantStock(t, db, product.Variants[0].ID))
+}
+
+func TestParkCart_InvalidInput_ReturnsValidation(t *testing.T) {
+	svc, db := setupParkedCartTest(t)
+	product := testutil.CreateTestProduct(t, db)
+	other := testutil.CreateTestProduct(t, db)
+
+	tests := []struct {
+		name  string
+		input ParkCartInput
+		code  string
+	}{
+		{"empty cart", ParkCartInput{}, "VALIDATION_ERROR"},
+		{"zero quantity", ParkCartInput{Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 0},
+		}}, "VALIDATION_ERROR"},
+		{"unit of another product", ParkCartInput{Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: other.Units[0].ID, Quantity: 1},
+		}}, "UNIT_PRODUCT_MISMATCH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.ParkCart(tt.input)
+			require.Error(t, err)
+			serviceErr, ok := err.(*ServiceError)
+			require.True(t, ok)
+			assert.Equal(t, ErrValidation, serviceErr.Err)
+			assert.Equal(t, tt.code, serviceErr.Code)
+		})
+	}
+}
+
+func TestListParkedCarts_NewestFirst_FiltersBySearch(t *testing.T) {
+	svc, db := setupParkedCartTest(t)
+	product := testutil.CreateTestProduct(t, db)
+	items := []CheckoutItemInput{
+		{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 1},
+	}
+
+	first, err := svc.ParkCart(ParkCartInput{Label: "Morning", CustomerName: "Sari", Items: items})
+	require.NoError(t, err)
+	second, err := svc.ParkCart(ParkCartInput{Label: "Afternoon", Items: items})
+	require.NoError(t, err)
+
+	carts, total, err := svc.ListParkedCarts(repositories.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, carts, 2)
+	assert.Equal(t, second.ID, carts[0].ID)
+	assert.Equal(t, first.ID, carts[1].ID)
+
+	carts, total, err = svc.ListParkedCarts(repositories.PaginationParams{Page: 1, PageSize: 10, Search: "sari"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, carts, 1)
+	assert.Equal(t, first.ID, carts[0].ID)
+}
+
+func TestResumeParkedCart_StillAvailable_ReturnsCheckoutItems(t *testing.T) {
+	svc, db := setupParkedCartTest(t)
+	product := testutil.CreateTestProductWithUnits(t, db)
+	variant := product.Variants[0]
+
+	var dozen models.ProductUnit
+	for _, unit := range product.Units {
+		if !unit.IsBase {
+			dozen = unit
+		}
+	}
+
+	parked, err := svc.ParkCart(ParkCartInput{Label: "Counter", Items: []CheckoutItemInput{
+		{ProductID: product.ID, VariantID: variant.ID, UnitID: dozen.ID, Quantity: 2},
+	}})
+	require.NoError(t, err)
+
+	resumed, err := svc.ResumeParkedCart(parked.ID)
+
+	require.NoError(t, err)
+	assert.True(t, resumed.AllAvailable)
+	require.Len(t, resumed.Items, 1)
+	assert.True(t, resumed.Items[0].Available)
+	assert.Empty(t, resumed.Items[0].Issue)
+	assert.Equal(t, 24, resumed.Items[0].BaseQty)
+	assert.Equal(t, 200, resumed.Items[0].CurrentStock)
+	require.Len(t, resumed.CheckoutItems, 1)
+	assert.Equal(t, CheckoutItemInput{ProductID: product.ID, VariantID: variant.ID, UnitID: dozen.ID, Quantity: 2}, resumed.CheckoutItems[0])
+
+	// The resumed items check out as they are
+	sale, err := svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: resumed.CheckoutItems})
+	require.NoError(t, err)
+	assert.Equal(t, 1, sale.TotalItems)
+}
+
+func TestResumeParkedCart_WithDiscounts_KeepsLineAndSaleDiscounts(t *testing.T) {
+	svc, db := setupParkedCartTest(t)
+	first := testutil.CreateTestProduct(t, db)
+	second := testutil.CreateTestProduct(t, db)
+
+	parked, err := svc.ParkCart(ParkCartInput{Discount: 500, Items: []CheckoutItemInput{
+		{ProductID: first.ID, VariantID: first.Variants[0].ID, UnitID: first.Units[0].ID, Quantity: 2, DiscountPercent: 10},
+		{ProductID: second.ID, VariantID: second.Variants[0].ID, UnitID: second.Units[0].ID, Quantity: 1, DiscountAmount: 1000},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, 500.0, parked.Discount)
+
+	resumed, err := svc.ResumeParkedCart(parked.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 500.0, resumed.Discount)
+	require.Len(t, resumed.CheckoutItems, 2)
+	assert.Equal(t, 10.0, resumed.CheckoutItems[0].DiscountPercent)
+	assert.Equal(t, 1000.0, resumed.CheckoutItems[1].DiscountAmount)
+	assert.Equal(t, 10.0, resumed.Items[0].DiscountPercent)
+	assert.Equal(t, 1000.0, resumed.Items[1].DiscountAmount)
+
+	sale, err := svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: resumed.CheckoutItems, Discount: resumed.Discount})
+	require.NoError(t, err)
+	assert.Equal(t, 500.0, sale.Discount)
+	require.Len(t, sale.Items, 2)
+	assert.Equal(t, 10.0, sale.Items[0].DiscountPercent)
+	assert.Equal(t, 1000.0, sale.Items[1].DiscountAmount)
+}
+
+func TestParkCart_InvalidDiscount_ReturnsValidation(t *testing.T) {
+	svc, db := setupParkedCartTest(t)
+	product := testutil.CreateTestProduct(t, db)
+	line := CheckoutItemInput{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 1}
+
+	both := line
+	both.DiscountPercent, both.DiscountAmount = 5, 100
+	_, err := svc.ParkCart(ParkCartInput{Items: []CheckoutItemInput{both}})
+	require.Error(t, err)
+	assert.Equal(t, ErrValidation, err.(*ServiceError).Err)
+
+	_, err = svc.ParkCart(ParkCartInput{Items: []CheckoutItemInput{line}, Discount: -1})
+	require.Error(t, err)
+	assert.Equal(t, ErrValidation, err.(*ServiceError).Err)
+}
+
+func TestResumeParkedCart_ItemOutOfStock_ReportsUnavailable(t *testing.T) {
+	svc, db := setupParkedCartTest(t)
+	inStock := testutil.CreateTestProduct(t, db)
+	soldOut := testutil.CreateTestProduct(t, db)
+
+	parked, err := svc.ParkCart(ParkCartInput{Items: []CheckoutItemInput{
+		{ProductID: inStock.ID, VariantID: inStock.Variants[0].ID, UnitID: inStock.Units[0].ID, Quantity: 1},
+		{ProductID: soldOut.ID, VariantID: soldOut.Variants[0].ID, UnitID: soldOut.Units[0].ID, Quantity: 5},
+	}})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Model(&models.ProductVariant{}).
+		Where("id = ?", soldOut.Variants[0].ID).
+		Update("current_stock", 0).Error)
+
+	resumed, err := svc.ResumeParkedCart(parked.ID)
+
+	require.NoError(t, err)
+	assert.False(t, resumed.AllAvailable)
+	require.Len(t, resumed.Items, 2)
+	assert.True(t, resumed.Items[0].Available)
+	assert.False(t, resumed.Items[1].Available)
+	assert.Equal(t, "OUT_OF_STOCK", resumed.Items[1].Issue)
+	assert.Equal(t, 0, resumed.Items[1].CurrentStock)
+	require.Len(t, resumed.CheckoutItems, 1)
+	assert.Equal(t, inStock.Variants[0].ID, resumed.CheckoutItems[0].VariantID)
+}
+
+func TestResumeParkedCart_LinesShareVariantStock_ReportsShortfall(t *testing.T) {
+	svc, db := setupParkedCartTest(t)
+	product := testutil.CreateTestProduct(t, db)
+	line := CheckoutItemInput{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 6}
+
+	parked, err := svc.ParkCart(ParkCartInput{Items: []CheckoutItemInput{line, line}})
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&models.ProductVariant{}).
+		Where("id = ?", product.Variants[0].ID).
+		Update("current_stock", 10).Error)
+
+	resumed, err := svc.ResumeParkedCart(parked.ID)
+
+	require.NoError(t, err)
+	assert.True(t, resumed.Items[0].Available)
+	assert.Equal(t, "INSUFFICIENT_STOCK", resumed.Items[1].Issue)
+	assert.Len(t, resumed.CheckoutItems, 1)
+}
+
+func TestResumeParkedCart_UnitOfOtherProduct_ReportsMismatch(t *testing.T) {
+	svc, db := setupParkedCartTest(t)
+	product := testutil.CreateTestProduct(t, db)
+	other := testutil.CreateTestProduct(t, db)
+
+	parked, err := svc.ParkCart(ParkCartInput{Items: []CheckoutItemInput{
+		{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 1},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&models.ParkedCartItem{}).
+		Where("cart_id = ?", parked.ID).
+		Update("unit_id", other.Units[0].ID).Error)
+
+	resumed, err := svc.ResumeParkedCart(parked.ID)
+
+	require.NoError(t, err)
+	assert.False(t, resumed.AllAvailable)
+	assert.Equal(t, "UNIT_PRODUCT_MISMATCH", resumed.Items[0].Issue)
+	assert.Empty(t, resumed.CheckoutItems)
+}
+
+func TestResumeParkedCart_NotFound_ReturnsNotFound(t *testing.T) {
+	svc, _ := setupParkedCartTest(t)
+
+	_, err := svc.ResumeParkedCart(999999)
+
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrNotFound, serviceErr.Err)
+}
+
+func TestDeleteParkedCart_RemovesCartAndItems(t *testing.T) {
+	svc, db := setupParkedCartTest(t)
+	product := testutil.CreateTestProduct(t, db)
+
+	parked, err := svc.ParkCart(ParkCartInput{Items: []CheckoutItemInput{
+		{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 1},
+	}})
+	require.NoError(t, err)
+
+	require.NoError(t, svc.DeleteParkedCart(parked.ID))
+
+	var count int64
+	require.NoError(t, db.Model(&models.ParkedCartItem{}).Where("cart_id = ?", parked.ID).Count(&count).Error)
+	assert.Equal(t, int64(0), count)
+
+	err = svc.DeleteParkedCart(parked.ID)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrNotFound, serviceErr.Err)
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pointofsale/backend/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidatePermissions drops the cached permissions of the given users so a
+// role change applies on their next request. Failures are only logged; the
+// entries still expire on their own.
+func invalidatePermissions(rdb *redis.Client, userIDs ...uint) {
+	if rdb == nil || len(userIDs) == 0 {
+		return
+	}
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = utils.PermissionCacheKey(userID)
+	}
+	if err := rdb.Del(context.Background(), keys...).Err(); err != nil {
+		slog.Error("failed to invalidate permission cache", "user_ids", userIDs, "error", err)
+	}
+}
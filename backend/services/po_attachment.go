@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pointofsale/backend/models"
+	"gorm.io/gorm"
+)
+
+// DefaultAttachmentMaxBytes is the attachment size limit used when none is configured.
+const DefaultAttachmentMaxBytes int64 = 10 << 20
+
+// allowedAttachmentTypes maps accepted content types to the stored file extension.
+var allowedAttachmentTypes = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+}
+
+// AttachmentMaxBytes returns the configured size limit for a single attachment.
+func (s *POService) AttachmentMaxBytes() int64 {
+	return s.cfg.AttachmentMaxBytes
+}
+
+// UploadAttachment stores a file and records it against the purchase order.
+// The content type is sniffed from the data rather than trusted from the client.
+func (s *POService) UploadAttachment(poID uint, filename string, data []byte, uploadedBy uint) (*models.POAttachment, error) {
+	if _, err := s.poRepo.GetByID(poID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &ServiceError{Err: ErrNotFound, Message: "Purchase order not found", Code: "PO_NOT_FOUND"}
+		}
+		return nil, &ServiceError{Err: err, Message: "Failed to fetch purchase order", Code: "INTERNAL_ERROR"}
+	}
+
+	if len(data) == 0 {
+		return nil, &ServiceError{Err: ErrValidation, Message: "File is empty", Code: "VALIDATION_ERROR"}
+	}
+	if int64(len(data)) > s.cfg.AttachmentMaxBytes {
+		return nil, &ServiceError{
+			Err:     ErrTooLarge,
+			Message: fmt.Sprintf("File exceeds the maximum size of %d bytes", s.cfg.AttachmentMaxBytes),
+			Code:    "ATTACHMENT_TOO_LARGE",
+		}
+	}
+
+	contentType := http.DetectContentType(data)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	ext, ok := allowedAttachmentTypes[contentType]
+	if !ok {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Only PDF, JPEG, PNG and WebP files can be attached",
+			Code:    "ATTACHMENT_TYPE_NOT_ALLOWED",
+		}
+	}
+
+	if s.cfg.Storage == nil {
+		return nil, &ServiceError{
+			Err:     fmt.Errorf("file storage is not configured"),
+			Message: "File storage is not configured",
+			Code:    "STORAGE_NOT_CONFIGURED",
+		}
+	}
+
+	name := strings.TrimSpace(filepath.Base(filename))
+	if name == "" || name == "." || name == string(filepath.Separator) {
+		name = "attachment" + ext
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+
+	objectKey := fmt.Sprintf("purchase-orders/%d/%s%s", poID, uuid.NewString(), ext)
+	fileURL, err := s.cfg.Storage.UploadImage(context.Background(), objectKey, data, contentType)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to upload attachment", Code: "INTERNAL_ERROR"}
+	}
+
+	attachment := &models.POAttachment{
+		PurchaseOrderID: poID,
+		FileURL:         fileURL,
+		ObjectKey:       objectKey,
+		Filename:        name,
+		ContentType:     contentType,
+		SizeBytes:       int64(len(data)),
+	}
+	if uploadedBy != 0 {
+		attachment.UploadedBy = &uploadedBy
+	}
+	if err := s.poRepo.CreateAttachment(attachment); err != nil {
+		s.discardAttachment(objectKey)
+		return nil, &ServiceError{Err: err, Message: "Failed to save attachment", Code: "INTERNAL_ERROR"}
+	}
+
+	return attachment, nil
+}
+
+// ListAttachments returns the attachments of a purchase order.
+func (s *POService) ListAttachments(poID uint) ([]models.POAttachment, error) {
+	if _, err := s.poRepo.GetByID(poID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &ServiceError{Err: ErrNotFound, Message: "Purchase order not found", Code: "PO_NOT_FOUND"}
+		}
+		return nil, &ServiceError{Err: err, Message: "Failed to fetch purchase order", Code: "INTERNAL_ERROR"}
+	}
+
+	attachments, err := s.poRepo.ListAttachments(poID)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to list attachments", Code: "INTERNAL_ERROR"}
+	}
+	return attachments, nil
+}
+
+// DeleteAttachment removes an attachment from a purchase order along with its
+// stored file. Attachments recorded without an object key keep their file.
+func (s *POService) DeleteAttachment(poID, attachmentID uint) error {
+	attachment, err := s.poRepo.GetAttachment(poID, attachmentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &ServiceError{Err: ErrNotFound, Message: "Attachment not found", Code: "ATTACHMENT_NOT_FOUND"}
+		}
+		return &ServiceError{Err: err, Message: "Failed to fetch attachment", Code: "INTERNAL_ERROR"}
+	}
+
+	if err := s.poRepo.DeleteAttachment(attachment.ID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &ServiceError{Err: ErrNotFound, Message: "Attachment not found", Code: "ATTACHMENT_NOT_FOUND"}
+		}
+		return &ServiceError{Err: err, Message: "Failed to delete attachment", Code: "INTERNAL_ERROR"}
+	}
+	if attachment.ObjectKey != "" {
+		s.discardAttachment(attachment.ObjectKey)
+	}
+	return nil
+}
+
+// discardAttachment removes a stored attachment file that no longer has a
+// record. A failure is logged; the record change has already been decided.
+func (s *POService) discardAttachment(objectKey string) {
+	if s.cfg.Storage == nil {
+		return
+	}
+	if err := s.cfg.Storage.Delete(context.Background(), objectKey); err != nil {
+		slog.Error("failed to delete purchase order attachment file", "object_key", objectKey, "error", err)
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFileStorage struct {
+	uploads map[string]string
+}
+
+func (f *fakeFileStorage) UploadImage(ctx context.Context, objectKey string, data []byte, contentType string) (string, error) {
+	if f.uploads == nil {
+		f.uploads = make(map[string]string)
+	}
+	f.uploads[objectKey] = contentType
+	return "http://files.local/" + objectKey, nil
+}
+
+func (f *fakeFileStorage) Delete(ctx context.Context, objectKey string) error {
+	delete(f.uploads, objectKey)
+	return nil
+}
+
+var samplePDF = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+
+func newAttachmentTestService(storage ImageStorage, maxBytes int64) (*POService, *mockPORepo) {
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+			return &models.PurchaseOrder{ID: id, Status: "draft"}, nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil, POConfig{Storage: storage, AttachmentMaxBytes: maxBytes})
+	return svc, poRepo
+}
+
+func TestUploadAttachment_PDF_ListAndDelete(t *testing.T) {
+	storage := &fakeFileStorage{}
+	svc, _ := newAttachmentTestService(storage, 0)
+
+	attachment, err := svc.UploadAttachment(3, "invoice-0042.pdf", samplePDF, 9)
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), attachment.PurchaseOrderID)
+	assert.Equal(t, "invoice-0042.pdf", attachment.Filename)
+	assert.Equal(t, "application/pdf", attachment.ContentType)
+	assert.Equal(t, int64(len(samplePDF)), attachment.SizeBytes)
+	assert.Contains(t, attachment.FileURL, "purchase-orders/3/")
+	require.NotNil(t, attachment.UploadedBy)
+	assert.Equal(t, uint(9), *attachment.UploadedBy)
+	assert.Len(t, storage.uploads, 1)
+
+	attachments, err := svc.ListAttachments(3)
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+	assert.Equal(t, attachment.ID, attachments[0].ID)
+
+	require.NoError(t, svc.DeleteAttachment(3, attachment.ID))
+	attachments, err = svc.ListAttachments(3)
+	require.NoError(t, err)
+	assert.Empty(t, attachments)
+	assert.Empty(t, storage.uploads)
+}
+
+func TestUploadAttachment_RecordFails_DeletesStoredFile(t *testing.T) {
+	storage := &fakeFileStorage{}
+	svc, poRepo := newAttachmentTestService(storage, 0)
+	poRepo.attachmentErr = errors.New("insert failed")
+
+	_, err := svc.UploadAttachment(3, "invoice.pdf", samplePDF, 9)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "INTERNAL_ERROR", serviceErr.Code)
+	assert.Empty(t, storage.uploads)
+}
+
+func TestUploadAttachment_DisallowedType_ReturnsValidation(t *testing.T) {
+	storage := &fakeFileStorage{}
+	svc, poRepo := newAttachmentTestService(storage, 0)
+
+	_, err := svc.UploadAttachment(3, "notes.pdf", []byte("just some plain text pretending to be a pdf"), 9)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "ATTACHMENT_TYPE_NOT_ALLOWED", serviceErr.Code)
+	assert.Empty(t, storage.uploads)
+	assert.Empty(t, poRepo.attachments)
+}
+
+func TestUploadAttachment_TooLarge_ReturnsTooLarge(t *testing.T) {
+	svc, _ := newAttachmentTestService(&fakeFileStorage{}, 16)
+
+	_, err := svc.UploadAttachment(3, "invoice.pdf", samplePDF, 9)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrTooLarge, serviceErr.Err)
+	assert.Equal(t, "ATTACHMENT_TOO_LARGE", serviceErr.Code)
+}
+
+func TestDeleteAttachment_OtherPO_ReturnsNotFound(t *testing.T) {
+	svc, _ := newAttachmentTestService(&fakeFileStorage{}, 0)
+
+	attachment, err := svc.UploadAttachment(3, "invoice.pdf", samplePDF, 9)
+	require.NoError(t, err)
+
+	err = svc.DeleteAttachment(4, attachment.ID)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrNotFound, serviceErr.Err)
+}
@@ -0,0 +1,108 @@
+package services
+
+import "time"
+
+// ReceivingDiscrepancy is a received PO line whose quantity or price differs
+// from what was ordered. Deltas are received minus ordered, so a negative
+// QtyDelta is a short delivery and a positive PriceDelta a price increase.
+type ReceivingDiscrepancy struct {
+	PurchaseOrderID uint       `json:"purchaseOrderId"`
+	PONumber        string     `json:"poNumber"`
+	SupplierID      uint       `json:"supplierId"`
+	SupplierName    string     `json:"supplierName"`
+	ReceivedDate    *time.Time `json:"receivedDate,omitempty"`
+	ItemID          string     `json:"itemId"`
+	ProductID       uint       `json:"productId"`
+	VariantID       string     `json:"variantId"`
+	ProductName     string     `json:"productName"`
+	VariantLabel    string     `json:"variantLabel"`
+	SKU             string     `json:"sku,omitempty"`
+	UnitName        string     `json:"unitName"`
+	OrderedQty      int        `json:"orderedQty"`
+	ReceivedQty     int        `json:"receivedQty"`
+	QtyDelta        int        `json:"qtyDelta"`
+	Price           float64    `json:"price"`
+	ReceivedPrice   float64    `json:"receivedPrice"`
+	PriceDelta      float64    `json:"priceDelta"`
+	ValueDelta      float64    `json:"valueDelta"`
+}
+
+// ReceivingDiscrepancyReport lists receiving discrepancies within a date range.
+type ReceivingDiscrepancyReport struct {
+	From            string                 `json:"from"`
+	To              string                 `json:"to"`
+	TotalValueDelta float64                `json:"totalValueDelta"`
+	Items           []ReceivingDiscrepancy `json:"items"`
+}
+
+// Discrepancies lists items of POs received within [from, to] whose received
+// quantity or price differs from the ordered one. Items never received are skipped.
+func (s *POService) Discrepancies(from, to time.Time) (*ReceivingDiscrepancyReport, error) {
+	if to.Before(from) {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "'from' date must not be after 'to' date",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	pos, err := s.poRepo.ListReceived(from, to)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to load received purchase orders", Code: "INTERNAL_ERROR"}
+	}
+
+	report := &ReceivingDiscrepancyReport{
+		From:  from.Format("2006-01-02"),
+		To:    to.Format("2006-01-02"),
+		Items: make([]ReceivingDiscrepancy, 0),
+	}
+	for _, po := range pos {
+		supplierName := ""
+		if po.Supplier != nil {
+			supplierName = po.Supplier.Name
+		}
+
+		for _, item := range po.Items {
+			if item.ReceivedQty == nil {
+				continue
+			}
+			receivedQty := *item.ReceivedQty
+			receivedPrice := item.Price
+			if item.ReceivedPrice != nil {
+				receivedPrice = *item.ReceivedPrice
+			}
+
+			priceDelta := roundTo(receivedPrice-item.Price, 2)
+			if receivedQty == item.OrderedQty && priceDelta == 0 {
+				continue
+			}
+
+			valueDelta := roundTo(float64(receivedQty)*receivedPrice-float64(item.OrderedQty)*item.Price, 2)
+			report.TotalValueDelta += valueDelta
+			report.Items = append(report.Items, ReceivingDiscrepancy{
+				PurchaseOrderID: po.ID,
+				PONumber:        po.PONumber,
+				SupplierID:      po.SupplierID,
+				SupplierName:    supplierName,
+				ReceivedDate:    po.ReceivedDate,
+				ItemID:          item.ID,
+				ProductID:       item.ProductID,
+				VariantID:       item.VariantID,
+				ProductName:     item.ProductName,
+				VariantLabel:    item.VariantLabel,
+				SKU:             item.SKU,
+				UnitName:        item.UnitName,
+				OrderedQty:      item.OrderedQty,
+				ReceivedQty:     receivedQty,
+				QtyDelta:        receivedQty - item.OrderedQty,
+				Price:           item.Price,
+				ReceivedPrice:   receivedPrice,
+				PriceDelta:      priceDelta,
+				ValueDelta:      valueDelta,
+			})
+		}
+	}
+	report.TotalValueDelta = roundTo(report.TotalValueDelta, 2)
+
+	return report, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscrepancies_MismatchedItems_ReturnsDeltas(t *testing.T) {
+	receivedDate := time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC)
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+
+	poRepo := &mockPORepo{
+		listReceivedFn: func(from, to time.Time) ([]models.PurchaseOrder, error) {
+			return []models.PurchaseOrder{
+				{
+					ID:           7,
+					PONumber:     "PO-2026-0007",
+					SupplierID:   3,
+					Supplier:     &models.Supplier{ID: 3, Name: "Acme"},
+					Status:       "received",
+					ReceivedDate: &receivedDate,
+					Items: []models.PurchaseOrderItem{
+						// Short delivery at the ordered price
+						{ID: "short", ProductName: "Rice", OrderedQty: 10, Price: 15000, ReceivedQty: intPtr(8), ReceivedPrice: floatPtr(15000)},
+						// Full delivery at a higher price
+						{ID: "pricier", ProductName: "Sugar", OrderedQty: 5, Price: 12000, ReceivedQty: intPtr(5), ReceivedPrice: floatPtr(12500)},
+						// Matches the order exactly
+						{ID: "exact", ProductName: "Salt", OrderedQty: 4, Price: 3000, ReceivedQty: intPtr(4), ReceivedPrice: floatPtr(3000)},
+						// Not received at all
+						{ID: "pending", ProductName: "Flour", OrderedQty: 2, Price: 9000},
+					},
+				},
+			}, nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil)
+
+	report, err := svc.Discrepancies(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01-01", report.From)
+	assert.Equal(t, "2026-01-31", report.To)
+	require.Len(t, report.Items, 2)
+
+	short := report.Items[0]
+	assert.Equal(t, "short", short.ItemID)
+	assert.Equal(t, uint(7), short.PurchaseOrderID)
+	assert.Equal(t, "PO-2026-0007", short.PONumber)
+	assert.Equal(t, "Acme", short.SupplierName)
+	assert.Equal(t, -2, short.QtyDelta)
+	assert.Equal(t, 0.0, short.PriceDelta)
+	assert.Equal(t, -30000.0, short.ValueDelta)
+
+	pricier := report.Items[1]
+	assert.Equal(t, "pricier", pricier.ItemID)
+	assert.Equal(t, 0, pricier.QtyDelta)
+	assert.Equal(t, 500.0, pricier.PriceDelta)
+	assert.Equal(t, 2500.0, pricier.ValueDelta)
+
+	assert.Equal(t, -27500.0, report.TotalValueDelta)
+}
+
+func TestDiscrepancies_NoReceivedPOs_ReturnsEmptyList(t *testing.T) {
+	svc := NewPOService(nil, &mockPORepo{}, &mockStockRepo{}, nil)
+
+	report, err := svc.Discrepancies(time.Now().AddDate(0, 0, -7), time.Now())
+
+	require.NoError(t, err)
+	assert.NotNil(t, report.Items)
+	assert.Empty(t, report.Items)
+}
+
+func TestDiscrepancies_FromAfterTo_ReturnsValidationError(t *testing.T) {
+	svc := NewPOService(nil, &mockPORepo{}, &mockStockRepo{}, nil)
+
+	_, err := svc.Discrepancies(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/utils"
+)
+
+// POMailer sends purchase order documents to suppliers.
+type POMailer interface {
+	SendPurchaseOrder(supplierEmail, poNumber string, pdfBytes []byte) error
+}
+
+// SendToSupplier emails a sent purchase order to its supplier with the PO
+// document attached as a PDF.
+func (s *POService) SendToSupplier(id uint) error {
+	po, err := s.GetPO(id)
+	if err != nil {
+		return err
+	}
+	if svcErr := s.emailPO(po); svcErr != nil {
+		return svcErr
+	}
+	return nil
+}
+
+// emailPO renders po and sends it to the supplier's email address.
+func (s *POService) emailPO(po *models.PurchaseOrder) *ServiceError {
+	if po.Status != "sent" {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Only sent purchase orders can be emailed to the supplier",
+			Code:    "PO_INVALID_STATUS",
+		}
+	}
+	if po.Supplier == nil || po.Supplier.Email == "" {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Supplier has no email address",
+			Code:    "SUPPLIER_NO_EMAIL",
+		}
+	}
+	if s.cfg.EmailDisabled {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Email is not configured",
+			Code:    "EMAIL_NOT_CONFIGURED",
+		}
+	}
+
+	pdfBytes, err := renderPOPDF(po, s.cfg.StoreName, utils.NewDocumentFormatter(s.cfg.DocumentFormat))
+	if err != nil {
+		return &ServiceError{Err: err, Message: "Failed to generate purchase order PDF", Code: "INTERNAL_ERROR"}
+	}
+	if err := s.cfg.Mailer.SendPurchaseOrder(po.Supplier.Email, po.PONumber, pdfBytes); err != nil {
+		return &ServiceError{Err: err, Message: "Failed to email purchase order", Code: "EMAIL_FAILED"}
+	}
+	return nil
+}
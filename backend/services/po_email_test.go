@@ -0,0 +1,124 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPOMailer records the purchase orders it is asked to send.
+type mockPOMailer struct {
+	sentTo   []string
+	poNumber string
+	pdf      []byte
+	err      error
+}
+
+func (m *mockPOMailer) SendPurchaseOrder(supplierEmail, poNumber string, pdfBytes []byte) error {
+	m.sentTo = append(m.sentTo, supplierEmail)
+	m.poNumber = poNumber
+	m.pdf = pdfBytes
+	return m.err
+}
+
+func newEmailTestPO(status, supplierEmail string) *models.PurchaseOrder {
+	return &models.PurchaseOrder{
+		ID:       1,
+		PONumber: "PO-2026-0001",
+		Status:   status,
+		Date:     "2026-01-15",
+		Supplier: &models.Supplier{Name: "Acme Supplies", Email: supplierEmail},
+		Items: []models.PurchaseOrderItem{
+			{ProductName: "Kopi Susu", VariantLabel: "Default", UnitName: "Pcs", OrderedQty: 10, Price: 5000},
+		},
+	}
+}
+
+func TestUpdatePOStatus_SendWithEmailSupplier_EmailsPDF(t *testing.T) {
+	po := newEmailTestPO("draft", "orders@acme.test")
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) { return po, nil },
+		updateFn:  func(po *models.PurchaseOrder) error { return nil },
+	}
+	mailer := &mockPOMailer{}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil, POConfig{Mailer: mailer})
+
+	updated, err := svc.UpdatePOStatus(1, "sent", nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, "sent", updated.Status)
+	assert.Equal(t, []string{"orders@acme.test"}, mailer.sentTo)
+	assert.Equal(t, "PO-2026-0001", mailer.poNumber)
+	assert.True(t, bytes.HasPrefix(mailer.pdf, []byte("%PDF")))
+}
+
+func TestUpdatePOStatus_EmailFails_StillSendsPO(t *testing.T) {
+	po := newEmailTestPO("draft", "orders@acme.test")
+	var saved *models.PurchaseOrder
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) { return po, nil },
+		updateFn: func(po *models.PurchaseOrder) error {
+			saved = po
+			return nil
+		},
+	}
+	mailer := &mockPOMailer{err: errors.New("smtp unavailable")}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil, POConfig{Mailer: mailer})
+
+	updated, err := svc.UpdatePOStatus(1, "sent", nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, "sent", updated.Status)
+	require.NotNil(t, saved)
+	assert.Equal(t, "sent", saved.Status)
+	assert.Len(t, mailer.sentTo, 1)
+}
+
+func TestUpdatePOStatus_EmailSupplierWithoutSending_ReturnsValidationError(t *testing.T) {
+	po := newEmailTestPO("sent", "orders@acme.test")
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) { return po, nil },
+	}
+	mailer := &mockPOMailer{}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil, POConfig{Mailer: mailer})
+
+	_, err := svc.UpdatePOStatus(1, "cancelled", nil, true)
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+	assert.Empty(t, mailer.sentTo)
+}
+
+func TestSendToSupplier_SupplierWithoutEmail_ReturnsError(t *testing.T) {
+	po := newEmailTestPO("sent", "")
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) { return po, nil },
+	}
+	mailer := &mockPOMailer{}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil, POConfig{Mailer: mailer})
+
+	err := svc.SendToSupplier(1)
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+	assert.Equal(t, "SUPPLIER_NO_EMAIL", svcErr.Code)
+	assert.Empty(t, mailer.sentTo)
+}
+
+func TestSendToSupplier_DraftPO_ReturnsError(t *testing.T) {
+	po := newEmailTestPO("draft", "orders@acme.test")
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) { return po, nil },
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil, POConfig{Mailer: &mockPOMailer{}})
+
+	err := svc.SendToSupplier(1)
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "PO_INVALID_STATUS", svcErr.Code)
+}
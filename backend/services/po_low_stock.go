@@ -0,0 +1,135 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+)
+
+// LowStockSuggestion is a low-stock variant shaped to prefill a PO line. The
+// suggested quantity, in the product's base unit, tops stock up to twice the
+// reorder point.
+type LowStockSuggestion struct {
+	ReorderSuggestion
+	CurrentStock int `json:"currentStock"`
+	ReorderPoint int `json:"reorderPoint"`
+}
+
+// LowStockSupplierGroup lists the low-stock variants supplied by one supplier.
+type LowStockSupplierGroup struct {
+	SupplierID   uint                 `json:"supplierId"`
+	SupplierName string               `json:"supplierName"`
+	Items        []LowStockSuggestion `json:"items"`
+}
+
+// LowStockBySupplierResult groups low-stock variants under their active
+// suppliers. Variants of products without an active supplier are listed as
+// unassigned.
+type LowStockBySupplierResult struct {
+	Suppliers  []LowStockSupplierGroup `json:"suppliers"`
+	Unassigned []LowStockSuggestion    `json:"unassigned"`
+}
+
+// LowStockBySupplier lists variants at or below their reorder point grouped
+// under each linked supplier, so a draft PO can be created per supplier. A
+// product linked to several suppliers appears in each of their groups.
+func (s *POService) LowStockBySupplier() (*LowStockBySupplierResult, error) {
+	products, err := s.poRepo.ListLowStockProducts()
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to fetch low-stock products", Code: "INTERNAL_ERROR"}
+	}
+
+	result := &LowStockBySupplierResult{
+		Suppliers:  []LowStockSupplierGroup{},
+		Unassigned: []LowStockSuggestion{},
+	}
+	groups := make(map[uint]*LowStockSupplierGroup)
+	for _, product := range products {
+		items := lowStockSuggestions(product)
+		if len(items) == 0 {
+			continue
+		}
+		if len(product.Suppliers) == 0 {
+			result.Unassigned = append(result.Unassigned, items...)
+			continue
+		}
+		for _, supplier := range product.Suppliers {
+			group, ok := groups[supplier.ID]
+			if !ok {
+				group = &LowStockSupplierGroup{SupplierID: supplier.ID, SupplierName: supplier.Name}
+				groups[supplier.ID] = group
+			}
+			group.Items = append(group.Items, items...)
+		}
+	}
+
+	for _, group := range groups {
+		result.Suppliers = append(result.Suppliers, *group)
+	}
+	sort.Slice(result.Suppliers, func(i, j int) bool {
+		return result.Suppliers[i].SupplierName < result.Suppliers[j].SupplierName
+	})
+
+	return result, nil
+}
+
+// lowStockSuggestions builds a suggestion for each low-stock variant of the
+// product, ordered in the product's base unit.
+func lowStockSuggestions(product models.Product) []LowStockSuggestion {
+	var unit *models.ProductUnit
+	for i := range product.Units {
+		if product.Units[i].IsBase {
+			unit = &product.Units[i]
+			break
+		}
+	}
+	if unit == nil && len(product.Units) > 0 {
+		unit = &product.Units[0]
+	}
+	if unit == nil {
+		return nil
+	}
+
+	items := make([]LowStockSuggestion, 0, len(product.Variants))
+	for _, variant := range product.Variants {
+		if variant.ReorderPoint <= 0 || variant.CurrentStock > variant.ReorderPoint {
+			continue
+		}
+		items = append(items, LowStockSuggestion{
+			ReorderSuggestion: ReorderSuggestion{
+				ProductID:    product.ID,
+				VariantID:    variant.ID,
+				UnitID:       unit.ID,
+				ProductName:  product.Name,
+				VariantLabel: buildVariantLabel(variant.Attributes),
+				SKU:          variant.SKU,
+				UnitName:     unit.Name,
+				OrderedQty:   2*variant.ReorderPoint - variant.CurrentStock,
+			},
+			CurrentStock: variant.CurrentStock,
+			ReorderPoint: variant.ReorderPoint,
+		})
+	}
+	return items
+}
+
+// DraftFromSuggestions creates a draft PO for the supplier from low-stock
+// suggestions, dated today.
+func (s *POService) DraftFromSuggestions(supplierID uint, items []CreatePOItemInput, createdBy uint) (*models.PurchaseOrder, error) {
+	if supplierID == 0 {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Supplier is required",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	return s.CreatePO(CreatePOInput{
+		SupplierID: supplierID,
+		Date:       time.Now().Format("2006-01-02"),
+		Notes:      "Drafted from low-stock suggestions",
+		Items:      items,
+		CreatedBy:  createdBy,
+	})
+}
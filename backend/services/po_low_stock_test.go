@@ -0,0 +1,114 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lowStockProduct(id uint, name string, suppliers []models.Supplier, variants ...models.ProductVariant) models.Product {
+	return models.Product{
+		ID:        id,
+		Name:      name,
+		Suppliers: suppliers,
+		Units: []models.ProductUnit{
+			{ID: id * 10, Name: "Box", ToBaseUnit: 12},
+			{ID: id*10 + 1, Name: "Pcs", ToBaseUnit: 1, IsBase: true},
+		},
+		Variants: variants,
+	}
+}
+
+func TestLowStockBySupplier_GroupsVariantsUnderEachSupplier(t *testing.T) {
+	alpha := models.Supplier{ID: 1, Name: "Alpha Supply"}
+	beta := models.Supplier{ID: 2, Name: "Beta Trading"}
+	poRepo := &mockPORepo{
+		listLowStockFn: func() ([]models.Product, error) {
+			return []models.Product{
+				lowStockProduct(1, "Coffee", []models.Supplier{beta, alpha},
+					models.ProductVariant{ID: "v-coffee", SKU: "COF", CurrentStock: 3, ReorderPoint: 10},
+					// Above the reorder point, not suggested
+					models.ProductVariant{ID: "v-coffee-dark", CurrentStock: 50, ReorderPoint: 10},
+				),
+				lowStockProduct(2, "Tea", []models.Supplier{alpha},
+					models.ProductVariant{ID: "v-tea", CurrentStock: 5, ReorderPoint: 5},
+				),
+				lowStockProduct(3, "Sugar", nil,
+					models.ProductVariant{ID: "v-sugar", CurrentStock: 0, ReorderPoint: 4},
+				),
+			}, nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil)
+
+	result, err := svc.LowStockBySupplier()
+
+	require.NoError(t, err)
+	require.Len(t, result.Suppliers, 2)
+
+	assert.Equal(t, uint(1), result.Suppliers[0].SupplierID)
+	assert.Equal(t, "Alpha Supply", result.Suppliers[0].SupplierName)
+	require.Len(t, result.Suppliers[0].Items, 2)
+	assert.Equal(t, "v-coffee", result.Suppliers[0].Items[0].VariantID)
+	assert.Equal(t, "v-tea", result.Suppliers[0].Items[1].VariantID)
+
+	assert.Equal(t, uint(2), result.Suppliers[1].SupplierID)
+	require.Len(t, result.Suppliers[1].Items, 1)
+	coffee := result.Suppliers[1].Items[0]
+	assert.Equal(t, "v-coffee", coffee.VariantID)
+	assert.Equal(t, uint(11), coffee.UnitID)
+	assert.Equal(t, "Pcs", coffee.UnitName)
+	assert.Equal(t, 3, coffee.CurrentStock)
+	assert.Equal(t, 10, coffee.ReorderPoint)
+	assert.Equal(t, 17, coffee.OrderedQty)
+
+	require.Len(t, result.Unassigned, 1)
+	assert.Equal(t, "v-sugar", result.Unassigned[0].VariantID)
+	assert.Equal(t, 8, result.Unassigned[0].OrderedQty)
+}
+
+func TestLowStockBySupplier_NothingLow_ReturnsEmptyGroups(t *testing.T) {
+	svc := NewPOService(nil, &mockPORepo{}, &mockStockRepo{}, nil)
+
+	result, err := svc.LowStockBySupplier()
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Suppliers)
+	assert.NotNil(t, result.Suppliers)
+	assert.Empty(t, result.Unassigned)
+}
+
+func TestDraftFromSuggestions_CreatesDraftPOForSupplier(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, NewSequenceService(db))
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	items := []CreatePOItemInput{
+		{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, OrderedQty: 17},
+	}
+
+	po, err := svc.DraftFromSuggestions(supplier.ID, items, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, "draft", po.Status)
+	assert.Equal(t, supplier.ID, po.SupplierID)
+	assert.NotEmpty(t, po.PONumber)
+	require.Len(t, po.Items, 1)
+	assert.Equal(t, 17, po.Items[0].OrderedQty)
+	assert.Equal(t, product.Name, po.Items[0].ProductName)
+}
+
+func TestDraftFromSuggestions_NoSupplier_ReturnsValidation(t *testing.T) {
+	svc := NewPOService(nil, &mockPORepo{}, &mockStockRepo{}, nil)
+
+	_, err := svc.DraftFromSuggestions(0, []CreatePOItemInput{{ProductID: 1, VariantID: "v", UnitID: 1, OrderedQty: 1}}, 0)
+
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"github.com/pointofsale/backend/models"
+	"gorm.io/gorm"
+)
+
+// applyMarkupPrices recomputes the selling price of every pricing tier of a
+// markup-priced variant from its received cost per base unit. Variants of
+// fixed-price products are left untouched. It runs in the receipt's tx.
+func applyMarkupPrices(tx *gorm.DB, variantID string, unitCost float64) error {
+	var product models.Product
+	err := tx.Joins("JOIN product_variants pv ON pv.product_id = products.id").
+		Where("pv.id = ?", variantID).
+		First(&product).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if product.PriceSetting != PriceSettingMarkup || product.MarkupType == nil {
+		return nil
+	}
+
+	var tiers []models.VariantPricingTier
+	if err := tx.Where("variant_id = ?", variantID).Find(&tiers).Error; err != nil {
+		return err
+	}
+	for _, tier := range tiers {
+		price, err := MarkupPrice(unitCost, *product.MarkupType, tier.Value)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.VariantPricingTier{}).
+			Where("id = ?", tier.ID).
+			Update("selling_price", price).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RepriceMarkupTiers recomputes the selling prices of a markup-priced
+// variant's tiers from the received price of its latest receipt, so tiers
+// rewritten by a product edit keep selling. Variants never received are left
+// unpriced.
+func RepriceMarkupTiers(tx *gorm.DB, variantID string) error {
+	var costs []float64
+	err := tx.Table("purchase_order_items poi").
+		Joins("JOIN purchase_orders po ON po.id = poi.purchase_order_id").
+		Joins("JOIN product_units pu ON pu.id = poi.unit_id").
+		Where("poi.variant_id = ? AND poi.received_qty > 0 AND poi.received_price IS NOT NULL AND pu.to_base_unit > 0", variantID).
+		Order("po.received_date DESC NULLS LAST, po.id DESC").
+		Limit(1).
+		Pluck("poi.received_price / pu.to_base_unit", &costs).Error
+	if err != nil {
+		return err
+	}
+	if len(costs) == 0 {
+		return nil
+	}
+	return applyMarkupPrices(tx, variantID, costs[0])
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// receiveVariantPO receives qty of the product's variant in unit at price
+// through a sent PO.
+func receiveVariantPO(t *testing.T, db *gorm.DB, product *models.Product, unit models.ProductUnit, qty int, price float64) {
+	t.Helper()
+	stored := createSentPO(t, db, product, unit, sentPOLine{name: product.Name, qty: qty, price: price})
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, NewSequenceService(db))
+
+	_, err := svc.ReceivePO(context.Background(), stored.ID, ReceivePOInput{
+		ReceivedDate:  "2026-02-03",
+		PaymentMethod: "cash",
+		Items:         []ReceivePOItemInput{{ItemID: stored.Items[0].ID, ReceivedQty: qty, ReceivedPrice: price, IsVerified: true}},
+	})
+	require.NoError(t, err)
+}
+
+func markupTestProduct(t *testing.T, db *gorm.DB, markupType string, markups ...float64) *models.Product {
+	t.Helper()
+	product := testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.PriceSetting = "markup"
+		p.MarkupType = &markupType
+	})
+	variantID := product.Variants[0].ID
+	require.NoError(t, db.Where("variant_id = ?", variantID).Delete(&models.VariantPricingTier{}).Error)
+	for i, markup := range markups {
+		require.NoError(t, db.Create(&models.VariantPricingTier{VariantID: variantID, MinQty: 1 + i*10, Value: markup}).Error)
+	}
+	return product
+}
+
+func tierSellingPrices(t *testing.T, db *gorm.DB, variantID string) []*float64 {
+	t.Helper()
+	var tiers []models.VariantPricingTier
+	require.NoError(t, db.Where("variant_id = ?", variantID).Order("min_qty ASC").Find(&tiers).Error)
+	prices := make([]*float64, 0, len(tiers))
+	for _, tier := range tiers {
+		prices = append(prices, tier.SellingPrice)
+	}
+	return prices
+}
+
+func TestReceivePO_PercentageMarkup_SetsTierSellingPrices(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	product := markupTestProduct(t, db, MarkupPercentage, 25, 20)
+
+	// A box of 12 at 96000 costs 8000 per base unit
+	box := models.ProductUnit{ProductID: product.ID, Name: "Box", ConversionFactor: 12, ToBaseUnit: 12}
+	require.NoError(t, db.Create(&box).Error)
+	receiveVariantPO(t, db, product, box, 2, 96000)
+
+	prices := tierSellingPrices(t, db, product.Variants[0].ID)
+	require.Len(t, prices, 2)
+	require.NotNil(t, prices[0])
+	require.NotNil(t, prices[1])
+	assert.Equal(t, 10000.0, *prices[0])
+	assert.Equal(t, 9600.0, *prices[1])
+}
+
+func TestReceivePO_FixedAmountMarkup_SetsTierSellingPrice(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	product := markupTestProduct(t, db, MarkupFixedAmount, 1500)
+
+	receiveVariantPO(t, db, product, product.Units[0], 5, 8000)
+
+	prices := tierSellingPrices(t, db, product.Variants[0].ID)
+	require.Len(t, prices, 1)
+	require.NotNil(t, prices[0])
+	assert.Equal(t, 9500.0, *prices[0])
+}
+
+func TestReceivePO_FixedPriceProduct_LeavesSellingPriceUnset(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	product := testutil.CreateTestProduct(t, db)
+
+	receiveVariantPO(t, db, product, product.Units[0], 5, 8000)
+
+	prices := tierSellingPrices(t, db, product.Variants[0].ID)
+	require.Len(t, prices, 1)
+	assert.Nil(t, prices[0])
+}
+
+func TestCheckout_MarkupProductAfterReceive_SellsAtDerivedPrice(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	product := markupTestProduct(t, db, MarkupFixedAmount, 1500)
+	receiveVariantPO(t, db, product, product.Units[0], 5, 8000)
+
+	svc := NewSalesService(db, repositories.NewSalesRepository(db), NewSequenceService(db))
+	sale, err := svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{
+		{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 2},
+	}})
+
+	require.NoError(t, err)
+	require.Len(t, sale.Items, 1)
+	assert.Equal(t, 9500.0, sale.Items[0].UnitPrice)
+	assert.Equal(t, 19000.0, sale.Subtotal)
+}
+
+func TestCheckout_MarkupProductNeverReceived_ReturnsNoPrice(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	product := markupTestProduct(t, db, MarkupFixedAmount, 1500)
+
+	svc := NewSalesService(db, repositories.NewSalesRepository(db), NewSequenceService(db), SalesConfig{RequirePricingTier: false})
+	_, err := svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{
+		{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 1},
+	}})
+
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "NO_PRICE", serviceErr.Code)
+}
+
+func TestToProductSearchResult_MarkupTiers_ListedAtSellingPrice(t *testing.T) {
+	markupType := MarkupPercentage
+	price := 10000.0
+	result := toProductSearchResult(models.Product{
+		PriceSetting: PriceSettingMarkup,
+		MarkupType:   &markupType,
+		Variants: []models.ProductVariant{
+			{ID: "priced", PricingTiers: []models.VariantPricingTier{{MinQty: 1, Value: 25, SellingPrice: &price}}},
+			{ID: "unpriced", PricingTiers: []models.VariantPricingTier{{MinQty: 1, Value: 25}}},
+		},
+	})
+
+	require.Len(t, result.Variants, 2)
+	assert.True(t, result.Variants[0].Priced)
+	assert.Equal(t, []VariantPricingTierResult{{MinQty: 1, Value: 10000}}, result.Variants[0].PricingTiers)
+	assert.False(t, result.Variants[1].Priced)
+	assert.Empty(t, result.Variants[1].PricingTiers)
+}
+
+// markupUpdateInput is an update of a markupTestProduct that keeps its units
+// and variant and sets the variant's tier markups.
+func markupUpdateInput(product *models.Product, markups ...float64) UpdateProductInput {
+	tiers := make([]CreateVariantPricingTierInput, 0, len(markups))
+	for i, markup := range markups {
+		tiers = append(tiers, CreateVariantPricingTierInput{MinQty: 1 + i*10, Value: markup})
+	}
+	variant := product.Variants[0]
+	return UpdateProductInput{
+		Name:         product.Name,
+		CategoryID:   product.CategoryID,
+		PriceSetting: product.PriceSetting,
+		MarkupType:   product.MarkupType,
+		Status:       product.Status,
+		Units:        []CreateProductUnitInput{{Name: product.Units[0].Name, IsBase: true}},
+		Variants: []CreateProductVariantInput{
+			{ID: variant.ID, SKU: variant.SKU, Barcode: variant.Barcode, PricingTiers: tiers},
+		},
+	}
+}
+
+func TestUpdateProduct_MarkupProductAfterReceive_KeepsTierSellingPrices(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	product := markupTestProduct(t, db, MarkupPercentage, 25, 20)
+	receiveVariantPO(t, db, product, product.Units[0], 5, 8000)
+
+	svc := NewProductService(repositories.NewProductRepository(db))
+	_, serviceErr := svc.UpdateProduct(product.ID, markupUpdateInput(product, 25, 20))
+	require.Nil(t, serviceErr)
+
+	prices := tierSellingPrices(t, db, product.Variants[0].ID)
+	require.Len(t, prices, 2)
+	require.NotNil(t, prices[0])
+	require.NotNil(t, prices[1])
+	assert.Equal(t, 10000.0, *prices[0])
+	assert.Equal(t, 9600.0, *prices[1])
+}
+
+func TestUpdateProduct_MarkupChanged_RederivesTierSellingPrice(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	product := markupTestProduct(t, db, MarkupPercentage, 25)
+	receiveVariantPO(t, db, product, product.Units[0], 5, 8000)
+
+	svc := NewProductService(repositories.NewProductRepository(db))
+	_, serviceErr := svc.UpdateProduct(product.ID, markupUpdateInput(product, 50))
+	require.Nil(t, serviceErr)
+
+	prices := tierSellingPrices(t, db, product.Variants[0].ID)
+	require.Len(t, prices, 1)
+	require.NotNil(t, prices[0])
+	assert.Equal(t, 12000.0, *prices[0])
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/utils"
+)
+
+const (
+	poPDFMargin    = 15.0
+	poPDFRowHeight = 7.0
+)
+
+// poPDFColumn is a column of the PO line item table.
+type poPDFColumn struct {
+	title string
+	width float64
+	align string
+}
+
+// poPDFColumns fill the 180mm printable width of an A4 page.
+var poPDFColumns = []poPDFColumn{
+	{"No", 10, "C"},
+	{"Product", 58, "L"},
+	{"SKU", 30, "L"},
+	{"Unit", 17, "L"},
+	{"Qty", 15, "R"},
+	{"Price", 25, "R"},
+	{"Total", 25, "R"},
+}
+
+// GeneratePDF renders a purchase order as a printable PDF for the supplier.
+// Line items use the product, variant, SKU and unit names stored on the PO.
+func (s *POService) GeneratePDF(id uint) ([]byte, error) {
+	po, err := s.GetPO(id)
+	if err != nil {
+		return nil, err
+	}
+
+	pdfBytes, err := renderPOPDF(po, s.cfg.StoreName, utils.NewDocumentFormatter(s.cfg.DocumentFormat))
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to generate purchase order PDF", Code: "INTERNAL_ERROR"}
+	}
+	return pdfBytes, nil
+}
+
+// renderPOPDF lays out the store header, supplier details, line items and
+// totals of a PO on A4 pages. The item table header repeats on each page.
+func renderPOPDF(po *models.PurchaseOrder, storeName string, formatter *utils.DocumentFormatter) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(poPDFMargin, poPDFMargin, poPDFMargin)
+	pdf.SetAutoPageBreak(true, poPDFMargin)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	pageW, _ := pdf.GetPageSize()
+	contentW := pageW - 2*poPDFMargin
+
+	// Store header
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(contentW, 8, fitText(pdf, tr, storeName, contentW), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.CellFormat(contentW, 8, "PURCHASE ORDER", "B", 1, "L", false, 0, "")
+	pdf.Ln(3)
+
+	// Order details on the left, supplier on the right
+	halfW := contentW / 2
+	top := pdf.GetY()
+	details := [][2]string{
+		{"PO Number", po.PONumber},
+		{"Date", formatPODate(po.Date, formatter)},
+	}
+	if po.ExpectedDate != nil {
+		details = append(details, [2]string{"Expected", formatPODate(*po.ExpectedDate, formatter)})
+	}
+	details = append(details, [2]string{"Status", po.Status})
+	for _, row := range details {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(28, 6, row[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(halfW-28, 6, fitText(pdf, tr, row[1], halfW-28), "", 1, "L", false, 0, "")
+	}
+	leftBottom := pdf.GetY()
+
+	pdf.SetXY(poPDFMargin+halfW, top)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(halfW, 6, "Supplier", "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range poSupplierLines(po.Supplier) {
+		pdf.MultiCell(halfW, 5, tr(line), "", "L", false)
+		pdf.SetX(poPDFMargin + halfW)
+	}
+	if pdf.GetY() < leftBottom {
+		pdf.SetY(leftBottom)
+	}
+	pdf.Ln(6)
+
+	// Line items
+	drawHeader := func() {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for _, col := range poPDFColumns {
+			pdf.CellFormat(col.width, poPDFRowHeight, col.title, "1", 0, col.align, true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 9)
+	}
+	drawHeader()
+
+	_, pageH := pdf.GetPageSize()
+	for i, item := range po.Items {
+		if pdf.GetY()+poPDFRowHeight > pageH-poPDFMargin {
+			pdf.AddPage()
+			drawHeader()
+		}
+		product := item.ProductName
+		if item.VariantLabel != "" && item.VariantLabel != "Default" {
+			product += " - " + item.VariantLabel
+		}
+		cells := []string{
+			strconv.Itoa(i + 1),
+			product,
+			item.SKU,
+			item.UnitName,
+			formatter.FormatNumber(float64(item.OrderedQty), 0),
+			formatter.FormatMoney(item.Price),
+			formatter.FormatMoney(float64(item.OrderedQty) * item.Price),
+		}
+		for c, col := range poPDFColumns {
+			pdf.CellFormat(col.width, poPDFRowHeight, fitText(pdf, tr, cells[c], col.width-2), "1", 0, col.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	// Totals
+	labelW := contentW - poPDFColumns[len(poPDFColumns)-1].width
+	totalW := poPDFColumns[len(poPDFColumns)-1].width
+	totals := [][2]string{
+		{"Total Items", strconv.Itoa(len(po.Items))},
+	}
+	if po.OrderedTotal != nil {
+		totals = append(totals, [2]string{"Ordered Total", formatter.FormatMoney(*po.OrderedTotal)})
+	}
+	if po.ReceivedTotal != nil && *po.ReceivedTotal != 0 {
+		totals = append(totals, [2]string{"Received Total", formatter.FormatMoney(*po.ReceivedTotal)})
+	}
+	if po.Subtotal != nil && po.GrandTotal != nil && (po.DiscountAmount != 0 || po.TaxPercent != 0) {
+		if po.DiscountAmount != 0 {
+			totals = append(totals, [2]string{"Discount", formatter.FormatMoney(-po.DiscountAmount)})
+		}
+		if po.TaxPercent != 0 {
+			tax := *po.GrandTotal - (*po.Subtotal - po.DiscountAmount)
+			label := fmt.Sprintf("Tax (%s%%)", formatter.FormatNumber(po.TaxPercent, 2))
+			totals = append(totals, [2]string{label, formatter.FormatMoney(tax)})
+		}
+		totals = append(totals, [2]string{"Grand Total", formatter.FormatMoney(*po.GrandTotal)})
+	}
+	pdf.SetFont("Helvetica", "B", 9)
+	for _, row := range totals {
+		pdf.CellFormat(labelW, poPDFRowHeight, row[0], "1", 0, "R", false, 0, "")
+		pdf.CellFormat(totalW, poPDFRowHeight, fitText(pdf, tr, row[1], totalW-2), "1", 1, "R", false, 0, "")
+	}
+
+	if po.Notes != "" {
+		pdf.Ln(5)
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(contentW, 6, "Notes", "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(contentW, 5, tr(po.Notes), "", "L", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// poSupplierLines returns the supplier contact lines printed on a PO.
+func poSupplierLines(supplier *models.Supplier) []string {
+	if supplier == nil {
+		return []string{"-"}
+	}
+	lines := []string{supplier.Name}
+	if supplier.Address != "" {
+		lines = append(lines, supplier.Address)
+	}
+	if supplier.Phone != "" {
+		lines = append(lines, fmt.Sprintf("Phone: %s", supplier.Phone))
+	}
+	if supplier.Email != "" {
+		lines = append(lines, fmt.Sprintf("Email: %s", supplier.Email))
+	}
+	return lines
+}
+
+// formatPODate renders a stored PO date with the document date format,
+// falling back to the stored value when it cannot be parsed.
+func formatPODate(value string, formatter *utils.DocumentFormatter) string {
+	if t, ok := parsePODate(value); ok {
+		return formatter.FormatDate(t)
+	}
+	return value
+}
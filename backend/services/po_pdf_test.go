@@ -0,0 +1,114 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func testPOForPDF(itemCount int) *models.PurchaseOrder {
+	expected := "2026-02-10"
+	po := &models.PurchaseOrder{
+		ID:           5,
+		PONumber:     "PO-2026-0005",
+		Date:         "2026-02-01",
+		ExpectedDate: &expected,
+		Status:       "sent",
+		Notes:        "Deliver to the back entrance",
+		Supplier: &models.Supplier{
+			Name:    "PT Sumber Rejeki",
+			Address: "Jl. Merdeka No. 10, Bandung",
+			Phone:   "022-123456",
+			Email:   "sales@sumber.example",
+		},
+	}
+	for i := 0; i < itemCount; i++ {
+		po.Items = append(po.Items, models.PurchaseOrderItem{
+			ProductName:  fmt.Sprintf("Beras Premium %d", i+1),
+			VariantLabel: "5 Kg",
+			SKU:          fmt.Sprintf("BRS-%03d", i+1),
+			UnitName:     "Sack",
+			OrderedQty:   10,
+			Price:        72500,
+		})
+	}
+	applyPOTotals(po)
+	return po
+}
+
+func TestRenderPOPDF_ProducesPDF(t *testing.T) {
+	data, err := renderPOPDF(testPOForPDF(3), "Toko Maju", utils.NewDocumentFormatter(utils.DefaultDocumentFormat()))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+	assert.Equal(t, 1, countPDFPages(data))
+}
+
+func TestRenderPOPDF_ManyItems_SpansPages(t *testing.T) {
+	data, err := renderPOPDF(testPOForPDF(60), "Toko Maju", utils.NewDocumentFormatter(utils.DefaultDocumentFormat()))
+	require.NoError(t, err)
+	assert.Greater(t, countPDFPages(data), 1)
+}
+
+func TestRenderPOPDF_TaxAndDiscount_StillRenders(t *testing.T) {
+	po := testPOForPDF(2)
+	subtotal := 1450000.0
+	po.Subtotal = &subtotal
+	po.TaxPercent = 11
+	po.DiscountAmount = 50000
+	grandTotal := poGrandTotal(subtotal, po.TaxPercent, po.DiscountAmount)
+	po.GrandTotal = &grandTotal
+
+	data, err := renderPOPDF(po, "Toko Maju", utils.NewDocumentFormatter(utils.DefaultDocumentFormat()))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+}
+
+func TestRenderPOPDF_NoSupplier_StillRenders(t *testing.T) {
+	po := testPOForPDF(1)
+	po.Supplier = nil
+	po.ExpectedDate = nil
+	data, err := renderPOPDF(po, "", utils.NewDocumentFormatter(utils.DocumentFormat{}))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+}
+
+func TestGeneratePDF_LoadsPO(t *testing.T) {
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+			assert.Equal(t, uint(5), id)
+			return testPOForPDF(2), nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil, POConfig{StoreName: "Toko Maju"})
+
+	data, err := svc.GeneratePDF(5)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+}
+
+func TestGeneratePDF_NotFound_ReturnsNotFound(t *testing.T) {
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil)
+
+	_, err := svc.GeneratePDF(99)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrNotFound, serviceErr.Err)
+}
+
+func TestFormatPODate(t *testing.T) {
+	formatter := utils.NewDocumentFormatter(utils.DefaultDocumentFormat())
+	assert.Equal(t, "01/02/2026", formatPODate("2026-02-01", formatter))
+	assert.Equal(t, "01/02/2026", formatPODate("2026-02-01T00:00:00Z", formatter))
+	assert.Equal(t, "soon", formatPODate("soon", formatter))
+}
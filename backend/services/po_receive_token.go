@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// maxReceiveTokenLength bounds the client-supplied Receive-Token.
+const maxReceiveTokenLength = 128
+
+// poReceiveTokenPending is stored under a token while its receive runs.
+const poReceiveTokenPending = "pending"
+
+// poReceiveTokenDone is stored under a token whose receive committed but
+// whose result could not be stored; a resubmission re-reads the PO.
+const poReceiveTokenDone = "done"
+
+// poReceiveTokenKey is the Redis key remembering a receive of a PO by token.
+func poReceiveTokenKey(poID uint, token string) string {
+	return fmt.Sprintf("po_receive:%d:%s", poID, token)
+}
+
+// ReceivePO processes a received PO: updates stock and creates movements.
+// When input.ReceiveToken is set, the first receive with that token for the
+// PO is remembered in Redis for ReceiveTokenTTL and a resubmission returns
+// its result instead of receiving again. A resubmission arriving while the
+// first is still running gets a retryable busy error. Redis errors fail open,
+// like the checkout gate. ctx is the request's context.
+func (s *POService) ReceivePO(ctx context.Context, id uint, input ReceivePOInput) (*models.PurchaseOrder, error) {
+	token := strings.TrimSpace(input.ReceiveToken)
+	if len(token) > maxReceiveTokenLength {
+		return nil, fieldError(fmt.Sprintf("Receive-Token must be at most %d characters", maxReceiveTokenLength))
+	}
+	if token == "" || s.cfg.Redis == nil || s.cfg.ReceiveTokenTTL <= 0 {
+		return s.receivePO(ctx, id, input)
+	}
+
+	key := poReceiveTokenKey(id, token)
+	claimed, err := s.cfg.Redis.SetNX(ctx, key, poReceiveTokenPending, s.cfg.ReceiveTokenTTL).Result()
+	if err != nil {
+		slog.Error("receive token check failed", "po_id", id, "error", err)
+		return s.receivePO(ctx, id, input)
+	}
+	if !claimed {
+		return s.replayReceive(ctx, id, key)
+	}
+
+	// Once claimed, the token is settled even if the client goes away.
+	settleCtx := context.WithoutCancel(ctx)
+	po, err := s.receivePO(ctx, id, input)
+	if err != nil {
+		// The receive runs in one transaction, so nothing was written. Free
+		// the token so the client can correct the input and resubmit.
+		s.cfg.Redis.Del(settleCtx, key)
+		return nil, err
+	}
+
+	// The receive has committed, so the token must never be freed again:
+	// a resubmission would receive the goods a second time.
+	data, err := json.Marshal(po)
+	if err == nil {
+		err = s.cfg.Redis.Set(settleCtx, key, data, s.cfg.ReceiveTokenTTL).Err()
+	}
+	if err != nil {
+		slog.Error("failed to store receive token result", "po_id", id, "error", err)
+		if err := s.cfg.Redis.Set(settleCtx, key, poReceiveTokenDone, s.cfg.ReceiveTokenTTL).Err(); err != nil {
+			// The pending marker stays until the TTL, answering busy.
+			slog.Error("failed to mark receive token done", "po_id", id, "error", err)
+		}
+	}
+	return po, nil
+}
+
+// replayReceive returns the result stored for a token that was already used.
+func (s *POService) replayReceive(ctx context.Context, id uint, key string) (*models.PurchaseOrder, error) {
+	data, err := s.cfg.Redis.Get(ctx, key).Bytes()
+	if err != nil && err != redis.Nil {
+		slog.Error("failed to read receive token result", "po_id", id, "error", err)
+		return nil, &ServiceError{Err: err, Message: "Failed to receive purchase order", Code: "INTERNAL_ERROR"}
+	}
+	// A missing key means the first receive failed and freed the token
+	// between our claim and this read; the client may resubmit.
+	if err == redis.Nil || string(data) == poReceiveTokenPending {
+		return nil, &ServiceError{
+			Err:     ErrBusy,
+			Message: "A receive with this token is already in progress, please retry",
+			Code:    "RECEIVE_IN_PROGRESS",
+		}
+	}
+
+	if string(data) == poReceiveTokenDone {
+		return s.GetPO(id)
+	}
+
+	var po models.PurchaseOrder
+	if err := json.Unmarshal(data, &po); err != nil {
+		slog.Error("failed to decode receive token result", "po_id", id, "error", err)
+		return nil, &ServiceError{Err: err, Message: "Failed to receive purchase order", Code: "INTERNAL_ERROR"}
+	}
+	return &po, nil
+}
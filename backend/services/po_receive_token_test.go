@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pointofsale/backend/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupReceiveTokenTest returns a POService without a database, so any
+// receive that is not answered from Redis would panic.
+func setupReceiveTokenTest(t *testing.T) (*POService, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return NewPOService(nil, nil, nil, nil, POConfig{Redis: rdb, ReceiveTokenTTL: time.Minute}), mr
+}
+
+func TestReceivePO_StoredToken_ReturnsPriorResult(t *testing.T) {
+	svc, mr := setupReceiveTokenTest(t)
+
+	prior := models.PurchaseOrder{ID: 7, PONumber: "PO-0007", Status: "received"}
+	data, err := json.Marshal(prior)
+	require.NoError(t, err)
+	require.NoError(t, mr.Set(poReceiveTokenKey(7, "abc"), string(data)))
+
+	po, err := svc.ReceivePO(context.Background(), 7, ReceivePOInput{ReceiveToken: "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), po.ID)
+	assert.Equal(t, "PO-0007", po.PONumber)
+	assert.Equal(t, "received", po.Status)
+}
+
+func TestReceivePO_TokenDone_ReturnsCurrentPO(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	poRepo := &mockPORepo{getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+		return &models.PurchaseOrder{ID: id, PONumber: "PO-0007", Status: "received"}, nil
+	}}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil, POConfig{Redis: rdb, ReceiveTokenTTL: time.Minute})
+	require.NoError(t, mr.Set(poReceiveTokenKey(7, "abc"), poReceiveTokenDone))
+
+	po, err := svc.ReceivePO(context.Background(), 7, ReceivePOInput{ReceiveToken: "abc"})
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), po.ID)
+	assert.Equal(t, "received", po.Status)
+	assert.True(t, mr.Exists(poReceiveTokenKey(7, "abc")))
+}
+
+func TestReceivePO_TokenInProgress_ReturnsBusy(t *testing.T) {
+	svc, mr := setupReceiveTokenTest(t)
+	require.NoError(t, mr.Set(poReceiveTokenKey(7, "abc"), poReceiveTokenPending))
+
+	_, err := svc.ReceivePO(context.Background(), 7, ReceivePOInput{ReceiveToken: "abc"})
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrBusy, svcErr.Err)
+	assert.Equal(t, "RECEIVE_IN_PROGRESS", svcErr.Code)
+}
+
+func TestReceivePO_TokenTooLong_ReturnsValidationError(t *testing.T) {
+	svc, _ := setupReceiveTokenTest(t)
+
+	_, err := svc.ReceivePO(context.Background(), 7, ReceivePOInput{ReceiveToken: strings.Repeat("a", maxReceiveTokenLength+1)})
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+}
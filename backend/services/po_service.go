@@ -1,25 +1,38 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/utils"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // PORepositoryInterface is the service-layer interface for the PO repository
 type PORepositoryInterface interface {
 	Create(po *models.PurchaseOrder) error
 	GetByID(id uint) (*models.PurchaseOrder, error)
-	List(params repositories.PaginationParams, status string, supplierID uint) ([]models.PurchaseOrder, int64, error)
+	List(params repositories.PaginationParams, filter repositories.POListFilter) ([]models.PurchaseOrder, int64, error)
 	StatusCounts() (map[string]int64, error)
 	Update(po *models.PurchaseOrder) error
+	MarkPaid(id uint, paidAt time.Time) (bool, error)
 	Delete(id uint) error
 	ReplaceItems(poID uint, items []models.PurchaseOrderItem) error
 	GetProductsForPO(supplierID uint, search string) ([]models.Product, error)
+	GetLatestForSupplier(supplierID uint) (*models.PurchaseOrder, error)
+	ListReceived(from, to time.Time) ([]models.PurchaseOrder, error)
+	ListLowStockProducts() ([]models.Product, error)
+	CreateAttachment(attachment *models.POAttachment) error
+	ListAttachments(poID uint) ([]models.POAttachment, error)
+	GetAttachment(poID, attachmentID uint) (*models.POAttachment, error)
+	DeleteAttachment(id uint) error
 }
 
 // StockMovementRepositoryInterface is the service-layer interface for stock movements
@@ -31,10 +44,12 @@ type StockMovementRepositoryInterface interface {
 
 // CreatePOInput holds the input for creating a purchase order
 type CreatePOInput struct {
-	SupplierID uint               `json:"supplierId"`
-	Date       string             `json:"date"`
-	Notes      string             `json:"notes"`
-	Items      []CreatePOItemInput `json:"items"`
+	SupplierID   uint               `json:"supplierId"`
+	Date         string             `json:"date"`
+	ExpectedDate *string            `json:"expectedDate"`
+	Notes        string             `json:"notes"`
+	Items        []CreatePOItemInput `json:"items"`
+	CreatedBy    uint               `json:"-"`
 }
 
 // CreatePOItemInput holds the input for a single PO line item
@@ -46,12 +61,20 @@ type CreatePOItemInput struct {
 	Price      float64 `json:"price"`
 }
 
-// ReceivePOInput holds the input for receiving a purchase order
+// ReceivePOInput holds the input for receiving a purchase order. TaxPercent
+// and DiscountAmount replace the PO's values when set and are kept otherwise.
+// ReceiveToken comes from the Receive-Token header and makes resubmissions
+// return the first result, see ReceivePO. ReceivedBy is the user receiving
+// the goods, taken from the request, and is recorded on the stock movements.
 type ReceivePOInput struct {
 	ReceivedDate          string             `json:"receivedDate"`
 	PaymentMethod         string             `json:"paymentMethod"`
 	SupplierBankAccountID *string            `json:"supplierBankAccountId"`
+	TaxPercent            *float64           `json:"taxPercent"`
+	DiscountAmount        *float64           `json:"discountAmount"`
 	Items                 []ReceivePOItemInput `json:"items"`
+	ReceiveToken          string             `json:"-"`
+	ReceivedBy            uint               `json:"-"`
 }
 
 // ReceivePOItemInput holds per-item input for receiving
@@ -62,21 +85,105 @@ type ReceivePOItemInput struct {
 	IsVerified    bool    `json:"isVerified"`
 }
 
+// ReorderSuggestion is a line from a previous PO shaped to prefill a new one.
+type ReorderSuggestion struct {
+	ProductID    uint    `json:"productId"`
+	VariantID    string  `json:"variantId"`
+	UnitID       uint    `json:"unitId"`
+	ProductName  string  `json:"productName"`
+	VariantLabel string  `json:"variantLabel"`
+	SKU          string  `json:"sku,omitempty"`
+	UnitName     string  `json:"unitName"`
+	OrderedQty   int     `json:"orderedQty"`
+	Price        float64 `json:"price"`
+}
+
+// LastPOSuggestion holds reorder suggestions taken from a supplier's last PO.
+// SourcePOID is nil when the supplier has no previous orders.
+type LastPOSuggestion struct {
+	SourcePOID     *uint               `json:"sourcePoId"`
+	SourcePONumber string              `json:"sourcePoNumber,omitempty"`
+	SourceDate     string              `json:"sourceDate,omitempty"`
+	Items          []ReorderSuggestion `json:"items"`
+}
+
+// POConfig holds tunable behaviour for POService.
+type POConfig struct {
+	// ApprovalThreshold is the ordered value above which a PO needs approval
+	// from a second user before it can be sent or received. Zero disables it.
+	ApprovalThreshold float64
+	// Storage receives uploaded PO attachments. Uploads fail when it is nil.
+	Storage ImageStorage
+	// AttachmentMaxBytes caps the size of a single attachment. Zero uses
+	// DefaultAttachmentMaxBytes.
+	AttachmentMaxBytes int64
+	// MaxItems caps the number of line items on a single PO. Zero uses
+	// DefaultMaxPOItems.
+	MaxItems int
+	// GapFreeNumbers reserves the PO number in the transaction that inserts
+	// the PO, so a failed create never consumes a number.
+	GapFreeNumbers bool
+	// RequireReceivedItems rejects a receive in which no item has a positive
+	// received quantity.
+	RequireReceivedItems bool
+	// RequireVerifiedItems additionally rejects a receive in which any item
+	// with a positive received quantity is not marked verified.
+	RequireVerifiedItems bool
+	// StoreName is printed in the header of generated PO documents.
+	StoreName string
+	// DocumentFormat sets the money and date formatting of generated PO
+	// documents. The zero value uses utils.DefaultDocumentFormat.
+	DocumentFormat utils.DocumentFormat
+	// Mailer emails sent POs to suppliers. Nil sends no email.
+	Mailer POMailer
+	// EmailDisabled makes emailing a PO fail with EMAIL_NOT_CONFIGURED
+	// instead of reporting a send that never happened.
+	EmailDisabled bool
+	// Redis remembers receive tokens. Tokens are ignored when it is nil.
+	Redis *redis.Client
+	// ReceiveTokenTTL is how long a receive token's result is kept for
+	// resubmissions. Zero disables receive tokens.
+	ReceiveTokenTTL time.Duration
+}
+
+// DefaultMaxPOItems is the PO line item cap used when none is configured.
+const DefaultMaxPOItems = 500
+
 // POService handles purchase order business logic
 type POService struct {
 	db        *gorm.DB
 	poRepo    PORepositoryInterface
 	stockRepo StockMovementRepositoryInterface
 	seqSvc    *SequenceService
+	cfg       POConfig
+	now       func() time.Time
 }
 
 // NewPOService creates a new PO service instance
-func NewPOService(db *gorm.DB, poRepo PORepositoryInterface, stockRepo StockMovementRepositoryInterface, seqSvc *SequenceService) *POService {
+func NewPOService(db *gorm.DB, poRepo PORepositoryInterface, stockRepo StockMovementRepositoryInterface, seqSvc *SequenceService, cfg ...POConfig) *POService {
+	var poCfg POConfig
+	if len(cfg) > 0 {
+		poCfg = cfg[0]
+	}
+	if poCfg.AttachmentMaxBytes <= 0 {
+		poCfg.AttachmentMaxBytes = DefaultAttachmentMaxBytes
+	}
+	if poCfg.MaxItems <= 0 {
+		poCfg.MaxItems = DefaultMaxPOItems
+	}
+	if poCfg.DocumentFormat == (utils.DocumentFormat{}) {
+		poCfg.DocumentFormat = utils.DefaultDocumentFormat()
+	}
+	if poCfg.Mailer == nil {
+		poCfg.Mailer = NoopEmailService{}
+	}
 	return &POService{
 		db:        db,
 		poRepo:    poRepo,
 		stockRepo: stockRepo,
 		seqSvc:    seqSvc,
+		cfg:       poCfg,
+		now:       time.Now,
 	}
 }
 
@@ -90,6 +197,17 @@ func (s *POService) CreatePO(input CreatePOInput) (*models.PurchaseOrder, error)
 			Code:    "VALIDATION_ERROR",
 		}
 	}
+	if svcErr := s.checkItemCount(len(input.Items)); svcErr != nil {
+		return nil, svcErr
+	}
+	if svcErr := validatePOItemAmounts(input.Items); svcErr != nil {
+		return nil, svcErr
+	}
+
+	expectedDate, err := normalizeExpectedDate(input.Date, input.ExpectedDate)
+	if err != nil {
+		return nil, &ServiceError{Err: ErrValidation, Message: err.Error(), Code: "VALIDATION_ERROR"}
+	}
 
 	// Validate supplier exists and is active
 	var supplier models.Supplier
@@ -111,85 +229,146 @@ func (s *POService) CreatePO(input CreatePOInput) (*models.PurchaseOrder, error)
 		}
 	}
 
-	// Generate PO number
-	poNumber, err := s.seqSvc.GeneratePONumber()
+	// Build items with denormalized fields
+	poItems, err := s.buildPOItems(input.Items)
 	if err != nil {
-		return nil, &ServiceError{Err: err, Message: "Failed to generate PO number", Code: "INTERNAL_ERROR"}
+		return nil, err
 	}
 
-	// Build items with denormalized fields
-	poItems := make([]models.PurchaseOrderItem, 0, len(input.Items))
-	for _, itemInput := range input.Items {
-		item, err := s.buildPOItem(itemInput)
-		if err != nil {
+	po := &models.PurchaseOrder{
+		SupplierID:   input.SupplierID,
+		Date:         input.Date,
+		ExpectedDate: expectedDate,
+		Status:       "draft",
+		Notes:        input.Notes,
+		Items:        poItems,
+	}
+	if input.CreatedBy != 0 {
+		createdBy := input.CreatedBy
+		po.CreatedBy = &createdBy
+	}
+
+	if s.cfg.GapFreeNumbers {
+		if err := s.createPOGapFree(po); err != nil {
 			return nil, err
 		}
-		poItems = append(poItems, *item)
+		applyPOTotals(po)
+		return po, nil
 	}
 
-	po := &models.PurchaseOrder{
-		PONumber:   poNumber,
-		SupplierID: input.SupplierID,
-		Date:       input.Date,
-		Status:     "draft",
-		Notes:      input.Notes,
-		Items:      poItems,
+	// Generate PO number
+	poNumber, err := s.seqSvc.GeneratePONumber()
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to generate PO number", Code: "INTERNAL_ERROR"}
 	}
+	po.PONumber = poNumber
 
 	if err := s.poRepo.Create(po); err != nil {
 		return nil, &ServiceError{Err: err, Message: "Failed to create purchase order", Code: "INTERNAL_ERROR"}
 	}
 
+	applyPOTotals(po)
 	return po, nil
 }
 
-// buildPOItem loads product/variant/unit data to denormalize the PO item
-func (s *POService) buildPOItem(input CreatePOItemInput) (*models.PurchaseOrderItem, error) {
-	// Load product
-	var product models.Product
-	if err := s.db.First(&product, input.ProductID).Error; err != nil {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: fmt.Sprintf("Product %d not found", input.ProductID),
-			Code:    "VALIDATION_ERROR",
-		}
+// NextPONumber previews the number the next created PO will get, following
+// the configured numbering mode. Nothing is reserved.
+func (s *POService) NextPONumber() (*NextNumber, error) {
+	kind := SequencePurchaseOrder
+	if s.cfg.GapFreeNumbers {
+		kind = SequenceReservedPurchaseOrder
 	}
+	number, err := s.seqSvc.Peek(kind)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to generate PO number", Code: "INTERNAL_ERROR"}
+	}
+	return &NextNumber{Number: number}, nil
+}
 
-	// Load variant
-	var variant models.ProductVariant
-	if err := s.db.Preload("Attributes").First(&variant, "id = ?", input.VariantID).Error; err != nil {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: fmt.Sprintf("Variant %s not found", input.VariantID),
-			Code:    "VALIDATION_ERROR",
+// createPOGapFree reserves the PO number and inserts the PO in one
+// transaction, so a failed insert releases the reserved number.
+func (s *POService) createPOGapFree(po *models.PurchaseOrder) error {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		poNumber, err := s.seqSvc.ReservePONumber(tx)
+		if err != nil {
+			return &ServiceError{Err: err, Message: "Failed to generate PO number", Code: "INTERNAL_ERROR"}
+		}
+		po.PONumber = poNumber
+
+		if err := tx.Create(po).Error; err != nil {
+			return &ServiceError{Err: err, Message: "Failed to create purchase order", Code: "INTERNAL_ERROR"}
 		}
+		return nil
+	})
+	if err != nil {
+		po.ID = 0
+		po.PONumber = ""
+		if svcErr, ok := err.(*ServiceError); ok {
+			return svcErr
+		}
+		return &ServiceError{Err: err, Message: "Failed to create purchase order", Code: "INTERNAL_ERROR"}
 	}
+	return nil
+}
 
-	// Load unit
-	var unit models.ProductUnit
-	if err := s.db.First(&unit, input.UnitID).Error; err != nil {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: fmt.Sprintf("Unit %d not found", input.UnitID),
-			Code:    "VALIDATION_ERROR",
+// buildPOItems loads the products, variants and units of the PO lines in one
+// batch and builds the items with denormalized fields. Every invalid
+// reference is reported in a single validation error.
+func (s *POService) buildPOItems(inputs []CreatePOItemInput) ([]models.PurchaseOrderItem, error) {
+	refs := make([]itemReference, 0, len(inputs))
+	for _, input := range inputs {
+		refs = append(refs, itemReference{ProductID: input.ProductID, VariantID: input.VariantID, UnitID: input.UnitID})
+	}
+	loaded, err := loadItemReferences(s.db, refs, referenceRules{
+		RequireOwnership: true,
+	})
+	if err != nil {
+		if svcErr, ok := err.(*ServiceError); ok {
+			return nil, svcErr
 		}
+		return nil, &ServiceError{Err: err, Message: "Failed to load purchase order items", Code: "INTERNAL_ERROR"}
 	}
 
-	// Build variant label from attributes
-	variantLabel := buildVariantLabel(variant.Attributes)
+	items := make([]models.PurchaseOrderItem, 0, len(inputs))
+	for _, input := range inputs {
+		product := loaded.products[input.ProductID]
+		variant := loaded.variants[input.VariantID]
+		unit := loaded.units[input.UnitID]
+		items = append(items, models.PurchaseOrderItem{
+			ProductID:    input.ProductID,
+			VariantID:    input.VariantID,
+			UnitID:       input.UnitID,
+			UnitName:     unit.Name,
+			ProductName:  product.Name,
+			VariantLabel: buildVariantLabel(variant.Attributes),
+			SKU:          variant.SKU,
+			CurrentStock: variant.CurrentStock,
+			OrderedQty:   input.OrderedQty,
+			Price:        input.Price,
+		})
+	}
+	return items, nil
+}
 
-	return &models.PurchaseOrderItem{
-		ProductID:    input.ProductID,
-		VariantID:    input.VariantID,
-		UnitID:       input.UnitID,
-		UnitName:     unit.Name,
-		ProductName:  product.Name,
-		VariantLabel: variantLabel,
-		SKU:          variant.SKU,
-		CurrentStock: variant.CurrentStock,
-		OrderedQty:   input.OrderedQty,
-		Price:        input.Price,
-	}, nil
+// normalizeExpectedDate validates an optional expected delivery date against the
+// order date. An empty value clears the expected date.
+func normalizeExpectedDate(orderDate string, expectedDate *string) (*string, error) {
+	if expectedDate == nil {
+		return nil, nil
+	}
+	value := strings.TrimSpace(*expectedDate)
+	if value == "" {
+		return nil, nil
+	}
+
+	expected, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("expected date must be in YYYY-MM-DD format")
+	}
+	if ordered, ok := parsePODate(orderDate); ok && expected.Before(ordered) {
+		return nil, fmt.Errorf("expected date cannot be before the order date")
+	}
+	return &value, nil
 }
 
 // buildVariantLabel builds a human-readable variant label from attributes
@@ -217,12 +396,17 @@ func (s *POService) GetPO(id uint) (*models.PurchaseOrder, error) {
 		}
 		return nil, &ServiceError{Err: err, Message: "Failed to fetch purchase order", Code: "INTERNAL_ERROR"}
 	}
+	applyPOTotals(po)
+	po.Overdue = isPOOverdue(po, s.today())
 	return po, nil
 }
 
-// ListPOs returns paginated purchase orders with status counts
-func (s *POService) ListPOs(params repositories.PaginationParams, status string, supplierID uint) ([]models.PurchaseOrder, int64, map[string]int64, error) {
-	pos, total, err := s.poRepo.List(params, status, supplierID)
+// ListPOs returns paginated purchase orders with status counts. Each PO is
+// flagged overdue when it is sent and its expected date has passed.
+func (s *POService) ListPOs(params repositories.PaginationParams, filter repositories.POListFilter) ([]models.PurchaseOrder, int64, map[string]int64, error) {
+	today := s.today()
+	filter.Today = today.Format("2006-01-02")
+	pos, total, err := s.poRepo.List(params, filter)
 	if err != nil {
 		return nil, 0, nil, &ServiceError{Err: err, Message: "Failed to list purchase orders", Code: "INTERNAL_ERROR"}
 	}
@@ -231,12 +415,57 @@ func (s *POService) ListPOs(params repositories.PaginationParams, status string,
 	if err != nil {
 		return nil, 0, nil, &ServiceError{Err: err, Message: "Failed to get status counts", Code: "INTERNAL_ERROR"}
 	}
+	// Every status gets a bucket, even when no PO is in it yet
+	for status := range validPOTransitions {
+		if _, ok := counts[status]; !ok {
+			counts[status] = 0
+		}
+	}
+
+	for i := range pos {
+		pos[i].Overdue = isPOOverdue(&pos[i], today)
+	}
 
 	return pos, total, counts, nil
 }
 
+// today returns the current date at midnight UTC, for comparing with PO dates.
+func (s *POService) today() time.Time {
+	t, _ := time.Parse("2006-01-02", s.now().Format("2006-01-02"))
+	return t
+}
+
+// isPOOverdue reports whether po is still awaiting goods, sent or partially
+// received, and its expected date is before today.
+func isPOOverdue(po *models.PurchaseOrder, today time.Time) bool {
+	if (po.Status != "sent" && po.Status != "partially_received") || po.ExpectedDate == nil {
+		return false
+	}
+	expected, ok := parsePODate(*po.ExpectedDate)
+	return ok && expected.Before(today)
+}
+
+// checkItemCount rejects purchase orders with more line items than the configured cap.
+func (s *POService) checkItemCount(n int) *ServiceError {
+	if n > s.cfg.MaxItems {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: fmt.Sprintf("Purchase order cannot have more than %d items", s.cfg.MaxItems),
+			Code:    "TOO_MANY_ITEMS",
+		}
+	}
+	return nil
+}
+
 // UpdatePO updates an existing draft purchase order
 func (s *POService) UpdatePO(id uint, input CreatePOInput) (*models.PurchaseOrder, error) {
+	if svcErr := s.checkItemCount(len(input.Items)); svcErr != nil {
+		return nil, svcErr
+	}
+	if svcErr := validatePOItemAmounts(input.Items); svcErr != nil {
+		return nil, svcErr
+	}
+
 	po, err := s.poRepo.GetByID(id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -253,30 +482,51 @@ func (s *POService) UpdatePO(id uint, input CreatePOInput) (*models.PurchaseOrde
 		}
 	}
 
+	expectedDate, err := normalizeExpectedDate(input.Date, input.ExpectedDate)
+	if err != nil {
+		return nil, &ServiceError{Err: ErrValidation, Message: err.Error(), Code: "VALIDATION_ERROR"}
+	}
+
+	var poItems []models.PurchaseOrderItem
+	if len(input.Items) > 0 {
+		poItems, err = s.buildPOItems(input.Items)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	po.SupplierID = input.SupplierID
 	po.Date = input.Date
+	po.ExpectedDate = expectedDate
 	po.Notes = input.Notes
+	// Any edit invalidates an earlier approval, so the approver always signs
+	// off on the PO as it will be sent.
+	po.ApprovedBy = nil
+	po.ApprovedAt = nil
 
-	if err := s.poRepo.Update(po); err != nil {
-		return nil, &ServiceError{Err: err, Message: "Failed to update purchase order", Code: "INTERNAL_ERROR"}
-	}
-
-	// Replace items if provided
-	if len(input.Items) > 0 {
-		poItems := make([]models.PurchaseOrderItem, 0, len(input.Items))
-		for _, itemInput := range input.Items {
-			item, err := s.buildPOItem(itemInput)
-			if err != nil {
-				return nil, err
-			}
-			poItems = append(poItems, *item)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(po).Error; err != nil {
+			return err
 		}
-		if err := s.poRepo.ReplaceItems(po.ID, poItems); err != nil {
-			return nil, &ServiceError{Err: err, Message: "Failed to update items", Code: "INTERNAL_ERROR"}
+		if poItems == nil {
+			return nil
 		}
+		if err := tx.Where("purchase_order_id = ?", po.ID).Delete(&models.PurchaseOrderItem{}).Error; err != nil {
+			return err
+		}
+		for i := range poItems {
+			poItems[i].PurchaseOrderID = po.ID
+		}
+		return tx.Create(&poItems).Error
+	})
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to update purchase order", Code: "INTERNAL_ERROR"}
+	}
+	if poItems != nil {
 		po.Items = poItems
 	}
 
+	applyPOTotals(po)
 	return po, nil
 }
 
@@ -305,8 +555,11 @@ func (s *POService) DeletePO(id uint) error {
 	return nil
 }
 
-// UpdatePOStatus transitions a PO to a new status
-func (s *POService) UpdatePOStatus(id uint, newStatus string) (*models.PurchaseOrder, error) {
+// UpdatePOStatus transitions a PO to a new status.
+// An expected delivery date may be supplied when sending the PO, and
+// emailSupplier emails the sent PO to the supplier. A failed email is logged
+// and does not undo the status change.
+func (s *POService) UpdatePOStatus(id uint, newStatus string, expectedDate *string, emailSupplier bool) (*models.PurchaseOrder, error) {
 	po, err := s.poRepo.GetByID(id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -323,17 +576,53 @@ func (s *POService) UpdatePOStatus(id uint, newStatus string) (*models.PurchaseO
 		}
 	}
 
+	if expectedDate != nil {
+		if newStatus != "sent" {
+			return nil, &ServiceError{
+				Err:     ErrValidation,
+				Message: "Expected date can only be set when sending a purchase order",
+				Code:    "VALIDATION_ERROR",
+			}
+		}
+		normalized, err := normalizeExpectedDate(po.Date, expectedDate)
+		if err != nil {
+			return nil, &ServiceError{Err: ErrValidation, Message: err.Error(), Code: "VALIDATION_ERROR"}
+		}
+		po.ExpectedDate = normalized
+	}
+
+	if emailSupplier && newStatus != "sent" {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "The supplier can only be emailed when sending a purchase order",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	if newStatus == "sent" {
+		if err := s.checkApproval(po); err != nil {
+			return nil, err
+		}
+	}
+
 	po.Status = newStatus
 	if err := s.poRepo.Update(po); err != nil {
 		return nil, &ServiceError{Err: err, Message: "Failed to update purchase order status", Code: "INTERNAL_ERROR"}
 	}
 
+	applyPOTotals(po)
+	if emailSupplier {
+		if svcErr := s.emailPO(po); svcErr != nil {
+			slog.Error("failed to email purchase order to supplier", "po_id", po.ID, "code", svcErr.Code, "error", svcErr.Err)
+		}
+	}
 	return po, nil
 }
 
-// ReceivePO processes a received PO: updates stock and creates movements
-func (s *POService) ReceivePO(id uint, input ReceivePOInput) (*models.PurchaseOrder, error) {
-	po, err := s.poRepo.GetByID(id)
+// Approve records a second user's approval of a purchase order.
+// The creator of a PO cannot approve it.
+func (s *POService) Approve(poID uint, approverID uint) (*models.PurchaseOrder, error) {
+	po, err := s.poRepo.GetByID(poID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, &ServiceError{Err: ErrNotFound, Message: "Purchase order not found", Code: "PO_NOT_FOUND"}
@@ -341,15 +630,193 @@ func (s *POService) ReceivePO(id uint, input ReceivePOInput) (*models.PurchaseOr
 		return nil, &ServiceError{Err: err, Message: "Failed to fetch purchase order", Code: "INTERNAL_ERROR"}
 	}
 
-	// Validate status
-	if po.Status != "sent" && po.Status != "draft" {
+	if po.Status != "draft" && po.Status != "sent" {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Only draft or sent purchase orders can be approved",
+			Code:    "PO_INVALID_STATUS",
+		}
+	}
+
+	if po.CreatedBy != nil && *po.CreatedBy == approverID {
+		return nil, &ServiceError{
+			Err:     ErrForbidden,
+			Message: "A purchase order cannot be approved by its creator",
+			Code:    "PO_SELF_APPROVAL",
+		}
+	}
+
+	if po.ApprovedBy != nil {
+		return nil, &ServiceError{
+			Err:     ErrConflict,
+			Message: "Purchase order is already approved",
+			Code:    "PO_ALREADY_APPROVED",
+		}
+	}
+
+	now := time.Now()
+	po.ApprovedBy = &approverID
+	po.ApprovedAt = &now
+	if err := s.poRepo.Update(po); err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to approve purchase order", Code: "INTERNAL_ERROR"}
+	}
+
+	applyPOTotals(po)
+	return po, nil
+}
+
+// MarkPaid records that the supplier has been paid for a purchase order,
+// which takes it out of the supplier's outstanding payables. Only fully
+// received or completed POs can be paid: a partially received PO can still
+// receive goods, which would raise its total after payment.
+func (s *POService) MarkPaid(poID uint) (*models.PurchaseOrder, error) {
+	po, err := s.getPayablePO(poID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	updated, err := s.poRepo.MarkPaid(poID, now)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to mark purchase order as paid", Code: "INTERNAL_ERROR"}
+	}
+	if !updated {
+		// The PO changed since it was read; report its current state.
+		if _, err := s.getPayablePO(poID); err != nil {
+			return nil, err
+		}
+		return nil, &ServiceError{
+			Err:     ErrConflict,
+			Message: "Purchase order is already paid",
+			Code:    "PO_ALREADY_PAID",
+		}
+	}
+
+	po.PaidAt = &now
+	applyPOTotals(po)
+	return po, nil
+}
+
+// getPayablePO loads a PO and checks that it can be marked as paid.
+func (s *POService) getPayablePO(poID uint) (*models.PurchaseOrder, error) {
+	po, err := s.poRepo.GetByID(poID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &ServiceError{Err: ErrNotFound, Message: "Purchase order not found", Code: "PO_NOT_FOUND"}
+		}
+		return nil, &ServiceError{Err: err, Message: "Failed to fetch purchase order", Code: "INTERNAL_ERROR"}
+	}
+
+	switch po.Status {
+	case "received", "completed":
+	default:
 		return nil, &ServiceError{
 			Err:     ErrValidation,
-			Message: "Only sent or draft purchase orders can be received",
+			Message: "Only fully received or completed purchase orders can be marked as paid",
 			Code:    "PO_INVALID_STATUS",
 		}
 	}
 
+	if po.PaidAt != nil {
+		return nil, &ServiceError{
+			Err:     ErrConflict,
+			Message: "Purchase order is already paid",
+			Code:    "PO_ALREADY_PAID",
+		}
+	}
+	return po, nil
+}
+
+// checkApproval blocks POs above the approval threshold that have not been approved.
+func (s *POService) checkApproval(po *models.PurchaseOrder) error {
+	if s.cfg.ApprovalThreshold <= 0 || po.ApprovedBy != nil {
+		return nil
+	}
+	if orderedValue(po.Items) <= s.cfg.ApprovalThreshold {
+		return nil
+	}
+	return &ServiceError{
+		Err:     ErrForbidden,
+		Message: fmt.Sprintf("Purchase orders above %.2f require approval from a second user", s.cfg.ApprovalThreshold),
+		Code:    "PO_APPROVAL_REQUIRED",
+	}
+}
+
+// applyPOTotals fills the computed financial totals of a PO from its items.
+// Outstanding is the ordered value of quantities not yet received. List
+// responses do not load items, so the totals are only set on single POs. A
+// received PO saved without a grand total gets one from its subtotal.
+func applyPOTotals(po *models.PurchaseOrder) {
+	var orderedTotal, receivedTotal, outstanding float64
+	for _, item := range po.Items {
+		orderedTotal += float64(item.OrderedQty) * item.Price
+
+		receivedQty := 0
+		if item.ReceivedQty != nil {
+			receivedQty = *item.ReceivedQty
+		}
+		if item.ReceivedPrice != nil {
+			receivedTotal += float64(receivedQty) * *item.ReceivedPrice
+		}
+		if remaining := item.OrderedQty - receivedQty; remaining > 0 {
+			outstanding += float64(remaining) * item.Price
+		}
+	}
+	orderedTotal = roundTo(orderedTotal, 2)
+	receivedTotal = roundTo(receivedTotal, 2)
+	outstanding = roundTo(outstanding, 2)
+	po.OrderedTotal = &orderedTotal
+	po.ReceivedTotal = &receivedTotal
+	po.Outstanding = &outstanding
+	if po.GrandTotal == nil && po.Subtotal != nil {
+		grandTotal := poGrandTotal(*po.Subtotal, po.TaxPercent, po.DiscountAmount)
+		po.GrandTotal = &grandTotal
+	}
+}
+
+// poGrandTotal applies a PO's discount and tax to its subtotal. Tax is
+// charged on the discounted amount.
+func poGrandTotal(subtotal, taxPercent, discount float64) float64 {
+	taxable := subtotal - discount
+	return roundTo(taxable+taxable*taxPercent/100, 2)
+}
+
+// receivedSubtotal is the PO subtotal after receiving items: the value of
+// earlier deliveries plus received quantity times price of this one.
+func receivedSubtotal(po *models.PurchaseOrder, itemMap map[string]*models.PurchaseOrderItem, items []ReceivePOItemInput) float64 {
+	var subtotal float64
+	if po.Subtotal != nil {
+		subtotal = *po.Subtotal
+	}
+	for _, item := range items {
+		if _, ok := itemMap[item.ItemID]; ok {
+			subtotal += float64(item.ReceivedQty) * item.ReceivedPrice
+		}
+	}
+	return subtotal
+}
+
+// orderedValue sums ordered quantity times price across PO lines.
+func orderedValue(items []models.PurchaseOrderItem) float64 {
+	var total float64
+	for _, item := range items {
+		total += float64(item.OrderedQty) * item.Price
+	}
+	return total
+}
+
+// receivePO processes a received PO: updates stock and creates movements.
+// The whole receipt runs in one transaction with the PO row locked, so
+// concurrent receipts of the same PO accumulate one after the other and a
+// failure leaves neither stock nor the PO changed.
+func (s *POService) receivePO(ctx context.Context, id uint, input ReceivePOInput) (*models.PurchaseOrder, error) {
+	if svcErr := validateReceiveItemAmounts(input.Items); svcErr != nil {
+		return nil, svcErr
+	}
+	if svcErr := validateReceiveAdjustments(input.TaxPercent, input.DiscountAmount); svcErr != nil {
+		return nil, svcErr
+	}
+
 	// Validate bank account required for non-cash
 	if input.PaymentMethod != "cash" && (input.SupplierBankAccountID == nil || *input.SupplierBankAccountID == "") {
 		return nil, &ServiceError{
@@ -359,12 +826,70 @@ func (s *POService) ReceivePO(id uint, input ReceivePOInput) (*models.PurchaseOr
 		}
 	}
 
+	var received *models.PurchaseOrder
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.receivePOTx(tx, id, input, &received)
+	})
+	if err != nil {
+		if serviceErr, ok := err.(*ServiceError); ok {
+			return nil, serviceErr
+		}
+		return nil, &ServiceError{Err: err, Message: "Failed to receive purchase order", Code: "INTERNAL_ERROR"}
+	}
+
+	applyPOTotals(received)
+	return received, nil
+}
+
+// receivePOTx performs a receipt inside tx.
+func (s *POService) receivePOTx(tx *gorm.DB, id uint, input ReceivePOInput, received **models.PurchaseOrder) error {
+	// Lock the PO so a concurrent receipt waits and then reads the quantities
+	// this one received
+	var po models.PurchaseOrder
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Supplier").
+		Preload("Items").
+		First(&po, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &ServiceError{Err: ErrNotFound, Message: "Purchase order not found", Code: "PO_NOT_FOUND"}
+		}
+		return &ServiceError{Err: err, Message: "Failed to fetch purchase order", Code: "INTERNAL_ERROR"}
+	}
+
+	// Validate status
+	if !receivablePOStatuses[po.Status] {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Only draft, sent or partially received purchase orders can be received",
+			Code:    "PO_INVALID_STATUS",
+		}
+	}
+
+	if err := s.checkApproval(&po); err != nil {
+		return err
+	}
+
 	// Build item lookup map
 	itemMap := make(map[string]*models.PurchaseOrderItem, len(po.Items))
 	for i := range po.Items {
 		itemMap[po.Items[i].ID] = &po.Items[i]
 	}
 
+	if err := s.checkReceivedItems(itemMap, input.Items); err != nil {
+		return err
+	}
+
+	taxPercent, discount := po.TaxPercent, po.DiscountAmount
+	if input.TaxPercent != nil {
+		taxPercent = *input.TaxPercent
+	}
+	if input.DiscountAmount != nil {
+		discount = *input.DiscountAmount
+	}
+	if value := receivedSubtotal(&po, itemMap, input.Items); discount > value {
+		return fieldError(fmt.Sprintf("discountAmount must not exceed the received subtotal of %.2f", value))
+	}
+
 	// Calculate totals
 	var subtotal float64
 	var totalItems int
@@ -387,24 +912,20 @@ func (s *POService) ReceivePO(id uint, input ReceivePOInput) (*models.PurchaseOr
 
 		qty := itemInput.ReceivedQty
 		price := itemInput.ReceivedPrice
-		verified := itemInput.IsVerified
-
-		poItem.ReceivedQty = &qty
-		poItem.ReceivedPrice = &price
-		poItem.IsVerified = verified
+		accumulateReceipt(poItem, qty, price, itemInput.IsVerified)
 
 		subtotal += float64(qty) * price
 		totalItems += qty
 
 		// Load unit to get toBaseUnit factor
 		var unit models.ProductUnit
-		if err := s.db.First(&unit, poItem.UnitID).Error; err == nil {
+		if err := tx.First(&unit, poItem.UnitID).Error; err == nil {
 			stockDelta := int(float64(qty) * unit.ToBaseUnit)
 			// Update variant stock
-			if err := s.db.Model(&models.ProductVariant{}).
+			if err := tx.Model(&models.ProductVariant{}).
 				Where("id = ?", poItem.VariantID).
 				Update("current_stock", gorm.Expr("current_stock + ?", stockDelta)).Error; err != nil {
-				return nil, &ServiceError{Err: err, Message: "Failed to update stock", Code: "INTERNAL_ERROR"}
+				return &ServiceError{Err: err, Message: "Failed to update stock", Code: "INTERNAL_ERROR"}
 			}
 
 			// Create stock movement
@@ -416,30 +937,147 @@ func (s *POService) ReceivePO(id uint, input ReceivePOInput) (*models.PurchaseOr
 				ReferenceID:   &po.ID,
 				Notes:         fmt.Sprintf("Received %d %s via PO %s", qty, unit.Name, po.PONumber),
 			}
-			if err := s.stockRepo.Create(movement); err != nil {
-				return nil, &ServiceError{Err: err, Message: "Failed to create stock movement", Code: "INTERNAL_ERROR"}
+			if input.ReceivedBy != 0 {
+				receivedBy := input.ReceivedBy
+				movement.CreatedBy = &receivedBy
+			}
+			if err := tx.Create(movement).Error; err != nil {
+				return &ServiceError{Err: err, Message: "Failed to create stock movement", Code: "INTERNAL_ERROR"}
+			}
+
+			if qty > 0 && unit.ToBaseUnit > 0 {
+				if err := applyMarkupPrices(tx, poItem.VariantID, price/unit.ToBaseUnit); err != nil {
+					return &ServiceError{Err: err, Message: "Failed to update markup prices", Code: "INTERNAL_ERROR"}
+				}
 			}
 		}
 	}
 
-	// Update PO
-	po.Status = "received"
+	// Update PO. Totals accumulate across deliveries; the received date and
+	// payment details are those of the latest delivery.
+	if po.Subtotal != nil {
+		subtotal += *po.Subtotal
+	}
+	if po.TotalItems != nil {
+		totalItems += *po.TotalItems
+	}
+	po.Status = receivedPOStatus(po.Items)
 	po.ReceivedDate = receivedDate
 	po.PaymentMethod = &input.PaymentMethod
 	po.SupplierBankAccountID = input.SupplierBankAccountID
 	po.Subtotal = &subtotal
 	po.TotalItems = &totalItems
+	po.TaxPercent = taxPercent
+	po.DiscountAmount = discount
+	grandTotal := poGrandTotal(subtotal, taxPercent, discount)
+	po.GrandTotal = &grandTotal
 
-	if err := s.poRepo.Update(po); err != nil {
-		return nil, &ServiceError{Err: err, Message: "Failed to update purchase order", Code: "INTERNAL_ERROR"}
+	if err := tx.Omit(clause.Associations).Save(&po).Error; err != nil {
+		return &ServiceError{Err: err, Message: "Failed to update purchase order", Code: "INTERNAL_ERROR"}
+	}
+	for i := range po.Items {
+		if err := tx.Save(&po.Items[i]).Error; err != nil {
+			return &ServiceError{Err: err, Message: "Failed to update items", Code: "INTERNAL_ERROR"}
+		}
 	}
 
-	// Replace items with updated receive data
-	if err := s.poRepo.ReplaceItems(po.ID, po.Items); err != nil {
-		return nil, &ServiceError{Err: err, Message: "Failed to update items", Code: "INTERNAL_ERROR"}
+	*received = &po
+	return nil
+}
+
+// accumulateReceipt adds a delivery of qty at price to a PO item. The
+// received price becomes the quantity-weighted average across deliveries, so
+// received quantity times price stays the item's received value. A delivery
+// of nothing leaves the price and verification untouched.
+func accumulateReceipt(item *models.PurchaseOrderItem, qty int, price float64, verified bool) {
+	previousQty := 0
+	if item.ReceivedQty != nil {
+		previousQty = *item.ReceivedQty
 	}
+	totalQty := previousQty + qty
+	item.ReceivedQty = &totalQty
 
-	return po, nil
+	if qty == 0 && item.ReceivedPrice != nil {
+		return
+	}
+	avgPrice := price
+	if item.ReceivedPrice != nil && totalQty > 0 {
+		previousValue := float64(previousQty) * *item.ReceivedPrice
+		avgPrice = roundTo((previousValue+float64(qty)*price)/float64(totalQty), 2)
+	}
+	item.ReceivedPrice = &avgPrice
+	item.IsVerified = verified
+}
+
+// checkReceivedItems enforces the configured receive rules on the items of
+// the PO being received. Inputs for items not on the PO are ignored, as they
+// are when receiving.
+func (s *POService) checkReceivedItems(itemMap map[string]*models.PurchaseOrderItem, items []ReceivePOItemInput) error {
+	if !s.cfg.RequireReceivedItems && !s.cfg.RequireVerifiedItems {
+		return nil
+	}
+
+	received := 0
+	for _, item := range items {
+		poItem, ok := itemMap[item.ItemID]
+		if !ok || item.ReceivedQty <= 0 {
+			continue
+		}
+		if s.cfg.RequireVerifiedItems && !item.IsVerified {
+			return &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("Received item %s must be verified", poItem.ProductName),
+				Code:    "PO_ITEM_NOT_VERIFIED",
+			}
+		}
+		received++
+	}
+
+	if s.cfg.RequireReceivedItems && received == 0 {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "At least one item must have a received quantity",
+			Code:    "PO_NOTHING_RECEIVED",
+		}
+	}
+	return nil
+}
+
+// LastPOForSupplier returns the items of the supplier's most recent non-draft PO
+// as reorder suggestions. Received prices are preferred over ordered prices.
+func (s *POService) LastPOForSupplier(supplierID uint) (*LastPOSuggestion, error) {
+	result := &LastPOSuggestion{Items: []ReorderSuggestion{}}
+
+	po, err := s.poRepo.GetLatestForSupplier(supplierID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return result, nil
+		}
+		return nil, &ServiceError{Err: err, Message: "Failed to fetch last purchase order", Code: "INTERNAL_ERROR"}
+	}
+
+	result.SourcePOID = &po.ID
+	result.SourcePONumber = po.PONumber
+	result.SourceDate = po.Date
+	for _, item := range po.Items {
+		price := item.Price
+		if item.ReceivedPrice != nil {
+			price = *item.ReceivedPrice
+		}
+		result.Items = append(result.Items, ReorderSuggestion{
+			ProductID:    item.ProductID,
+			VariantID:    item.VariantID,
+			UnitID:       item.UnitID,
+			ProductName:  item.ProductName,
+			VariantLabel: item.VariantLabel,
+			SKU:          item.SKU,
+			UnitName:     item.UnitName,
+			OrderedQty:   item.OrderedQty,
+			Price:        price,
+		})
+	}
+
+	return result, nil
 }
 
 // GetProductsForPO returns products eligible for a PO
@@ -1,7 +1,10 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -18,12 +21,18 @@ import (
 type mockPORepo struct {
 	createFn       func(*models.PurchaseOrder) error
 	getByIDFn      func(uint) (*models.PurchaseOrder, error)
-	listFn         func(repositories.PaginationParams, string, uint) ([]models.PurchaseOrder, int64, error)
+	listFn         func(repositories.PaginationParams, repositories.POListFilter) ([]models.PurchaseOrder, int64, error)
 	statusCountsFn func() (map[string]int64, error)
 	updateFn       func(*models.PurchaseOrder) error
+	markPaidFn     func(uint, time.Time) (bool, error)
 	deleteFn       func(uint) error
 	replaceItemsFn func(uint, []models.PurchaseOrderItem) error
 	getProductsFn  func(uint, string) ([]models.Product, error)
+	getLatestFn    func(uint) (*models.PurchaseOrder, error)
+	listReceivedFn func(time.Time, time.Time) ([]models.PurchaseOrder, error)
+	listLowStockFn func() ([]models.Product, error)
+	attachments    []models.POAttachment
+	attachmentErr  error
 }
 
 func (m *mockPORepo) Create(po *models.PurchaseOrder) error {
@@ -39,9 +48,9 @@ func (m *mockPORepo) GetByID(id uint) (*models.PurchaseOrder, error) {
 	}
 	return nil, gorm.ErrRecordNotFound
 }
-func (m *mockPORepo) List(p repositories.PaginationParams, s string, sid uint) ([]models.PurchaseOrder, int64, error) {
+func (m *mockPORepo) List(p repositories.PaginationParams, f repositories.POListFilter) ([]models.PurchaseOrder, int64, error) {
 	if m.listFn != nil {
-		return m.listFn(p, s, sid)
+		return m.listFn(p, f)
 	}
 	return nil, 0, nil
 }
@@ -57,6 +66,12 @@ func (m *mockPORepo) Update(po *models.PurchaseOrder) error {
 	}
 	return nil
 }
+func (m *mockPORepo) MarkPaid(id uint, paidAt time.Time) (bool, error) {
+	if m.markPaidFn != nil {
+		return m.markPaidFn(id, paidAt)
+	}
+	return true, nil
+}
 func (m *mockPORepo) Delete(id uint) error {
 	if m.deleteFn != nil {
 		return m.deleteFn(id)
@@ -76,6 +91,65 @@ func (m *mockPORepo) GetProductsForPO(supplierID uint, search string) ([]models.
 	return nil, nil
 }
 
+func (m *mockPORepo) GetLatestForSupplier(supplierID uint) (*models.PurchaseOrder, error) {
+	if m.getLatestFn != nil {
+		return m.getLatestFn(supplierID)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockPORepo) ListReceived(from, to time.Time) ([]models.PurchaseOrder, error) {
+	if m.listReceivedFn != nil {
+		return m.listReceivedFn(from, to)
+	}
+	return nil, nil
+}
+func (m *mockPORepo) ListLowStockProducts() ([]models.Product, error) {
+	if m.listLowStockFn != nil {
+		return m.listLowStockFn()
+	}
+	return nil, nil
+}
+
+func (m *mockPORepo) CreateAttachment(attachment *models.POAttachment) error {
+	if m.attachmentErr != nil {
+		return m.attachmentErr
+	}
+	attachment.ID = uint(len(m.attachments) + 1)
+	m.attachments = append(m.attachments, *attachment)
+	return nil
+}
+
+func (m *mockPORepo) ListAttachments(poID uint) ([]models.POAttachment, error) {
+	result := make([]models.POAttachment, 0)
+	for _, attachment := range m.attachments {
+		if attachment.PurchaseOrderID == poID {
+			result = append(result, attachment)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockPORepo) GetAttachment(poID, attachmentID uint) (*models.POAttachment, error) {
+	for _, attachment := range m.attachments {
+		if attachment.PurchaseOrderID == poID && attachment.ID == attachmentID {
+			found := attachment
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockPORepo) DeleteAttachment(id uint) error {
+	for i, attachment := range m.attachments {
+		if attachment.ID == id {
+			m.attachments = append(m.attachments[:i], m.attachments[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
 type mockStockRepo struct {
 	createFn        func(*models.StockMovement) error
 	getByVariantFn  func(string) ([]models.StockMovement, error)
@@ -137,6 +211,40 @@ func TestCreatePO_Valid_GeneratesPONumber(t *testing.T) {
 	assert.Contains(t, po.PONumber, "PO-")
 }
 
+func TestCreatePO_GapFreeNumbers_FailedInsertDoesNotConsumeNumber(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, NewSequenceService(db), POConfig{GapFreeNumbers: true})
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	input := CreatePOInput{
+		SupplierID: supplier.ID,
+		Date:       "2026-01-15",
+		Items: []CreatePOItemInput{
+			{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, OrderedQty: 10, Price: 15000},
+		},
+	}
+	year := time.Now().Year()
+
+	first, err := svc.CreatePO(input)
+	require.NoError(t, err)
+	assert.Equal(t, formatPONumber(year, 1), first.PONumber)
+
+	// A creator that does not exist violates the foreign key, failing the insert
+	failing := input
+	failing.CreatedBy = 999999
+	_, err = svc.CreatePO(failing)
+	require.Error(t, err)
+
+	next, err := svc.CreatePO(input)
+	require.NoError(t, err)
+	assert.Equal(t, formatPONumber(year, 2), next.PONumber)
+
+	var count int64
+	require.NoError(t, db.Model(&models.PurchaseOrder{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
 func TestCreatePO_DenormalizesItemFields(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	poRepo := &mockPORepo{}
@@ -172,6 +280,67 @@ func TestCreatePO_DenormalizesItemFields(t *testing.T) {
 	assert.NotEmpty(t, po.Items[0].VariantLabel)
 }
 
+func TestCreatePO_MultipleValidItems_Succeeds(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	seqSvc := NewSequenceService(db)
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, seqSvc)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	first := testutil.CreateTestProduct(t, db)
+	second := testutil.CreateTestProductWithUnits(t, db)
+	var dozen models.ProductUnit
+	for _, u := range second.Units {
+		if u.Name == "Dozen" {
+			dozen = u
+		}
+	}
+	require.NotZero(t, dozen.ID)
+
+	po, err := svc.CreatePO(CreatePOInput{
+		SupplierID: supplier.ID,
+		Date:       "2026-01-15",
+		Items: []CreatePOItemInput{
+			{ProductID: first.ID, VariantID: first.Variants[0].ID, UnitID: first.Units[0].ID, OrderedQty: 5, Price: 10000},
+			{ProductID: second.ID, VariantID: second.Variants[0].ID, UnitID: dozen.ID, OrderedQty: 2, Price: 90000},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, po.Items, 2)
+	assert.Equal(t, first.Name, po.Items[0].ProductName)
+	assert.Equal(t, second.Name, po.Items[1].ProductName)
+	assert.Equal(t, dozen.Name, po.Items[1].UnitName)
+}
+
+func TestCreatePO_SeveralInvalidReferences_ReportsAll(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	seqSvc := NewSequenceService(db)
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, seqSvc)
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	product := testutil.CreateTestProduct(t, db)
+	inactive := testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.Status = "inactive"
+	})
+
+	_, err := svc.CreatePO(CreatePOInput{
+		SupplierID: supplier.ID,
+		Date:       "2026-01-15",
+		Items: []CreatePOItemInput{
+			{ProductID: product.ID, VariantID: "not-a-uuid", UnitID: product.Units[0].ID, OrderedQty: 1, Price: 10000},
+			{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, OrderedQty: 1, Price: 10000},
+			{ProductID: inactive.ID, VariantID: inactive.Variants[0].ID, UnitID: inactive.Units[0].ID, OrderedQty: 1, Price: 10000},
+		},
+	})
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+	assert.Equal(t, "INVALID_REFERENCES", svcErr.Code)
+	assert.Contains(t, svcErr.Message, "items[0]: variant not-a-uuid not found")
+	assert.Contains(t, svcErr.Message, "items[2]: "+inactive.Name+" is inactive")
+	assert.NotContains(t, svcErr.Message, "items[1]")
+}
+
 func TestCreatePO_InactiveSupplier_ReturnsError(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	poRepo := &mockPORepo{}
@@ -220,6 +389,89 @@ func TestCreatePO_NoItemsWithQty_ReturnsValidation(t *testing.T) {
 	assert.Equal(t, ErrValidation, serviceErr.Err)
 }
 
+func TestCreatePO_TooManyItems_ReturnsValidation(t *testing.T) {
+	svc := NewPOService(nil, &mockPORepo{}, &mockStockRepo{}, nil, POConfig{MaxItems: 2})
+
+	input := CreatePOInput{
+		SupplierID: 1,
+		Date:       "2026-01-15",
+		Items:      make([]CreatePOItemInput, 3),
+	}
+
+	_, err := svc.CreatePO(input)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "TOO_MANY_ITEMS", serviceErr.Code)
+}
+
+func TestCreatePO_ItemsAtCap_Succeeds(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	seqSvc := NewSequenceService(db)
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, seqSvc, POConfig{MaxItems: 2})
+
+	supplier := testutil.CreateTestSupplier(t, db)
+	first := testutil.CreateTestProduct(t, db)
+	second := testutil.CreateTestProduct(t, db)
+
+	input := CreatePOInput{
+		SupplierID: supplier.ID,
+		Date:       "2026-01-15",
+		Items: []CreatePOItemInput{
+			{ProductID: first.ID, VariantID: first.Variants[0].ID, UnitID: first.Units[0].ID, OrderedQty: 1, Price: 1000},
+			{ProductID: second.ID, VariantID: second.Variants[0].ID, UnitID: second.Units[0].ID, OrderedQty: 1, Price: 1000},
+		},
+	}
+
+	po, err := svc.CreatePO(input)
+	require.NoError(t, err)
+	assert.Len(t, po.Items, 2)
+}
+
+func TestUpdatePO_TooManyItems_ReturnsValidation(t *testing.T) {
+	svc := NewPOService(nil, &mockPORepo{}, &mockStockRepo{}, nil, POConfig{MaxItems: 1})
+
+	_, err := svc.UpdatePO(1, CreatePOInput{
+		SupplierID: 1,
+		Date:       "2026-01-15",
+		Items:      make([]CreatePOItemInput, 2),
+	})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "TOO_MANY_ITEMS", serviceErr.Code)
+}
+
+func TestUpdatePO_NegativePrice_ReturnsFieldError(t *testing.T) {
+	svc := NewPOService(nil, &mockPORepo{}, &mockStockRepo{}, nil)
+
+	_, err := svc.UpdatePO(1, CreatePOInput{
+		SupplierID: 1,
+		Date:       "2026-01-15",
+		Items:      []CreatePOItemInput{{OrderedQty: 1, Price: -1000}},
+	})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "items[0].price must not be negative", serviceErr.Message)
+}
+
+func TestReceivePO_NegativeQuantity_ReturnsFieldError(t *testing.T) {
+	svc := NewPOService(nil, &mockPORepo{}, &mockStockRepo{}, nil)
+
+	_, err := svc.ReceivePO(context.Background(), 1, ReceivePOInput{
+		PaymentMethod: "cash",
+		Items:         []ReceivePOItemInput{{ItemID: "item-1", ReceivedQty: -2, ReceivedPrice: 1000}},
+	})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "items[0].receivedQty must not be negative", serviceErr.Message)
+}
+
 func TestUpdatePO_NonDraft_ReturnsForbidden(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	stockRepo := &mockStockRepo{}
@@ -281,7 +533,7 @@ func TestUpdatePOStatus_ValidTransition_Succeeds(t *testing.T) {
 
 	svc := NewPOService(db, poRepo, stockRepo, seqSvc)
 
-	updated, err := svc.UpdatePOStatus(1, "sent")
+	updated, err := svc.UpdatePOStatus(1, "sent", nil, false)
 	require.NoError(t, err)
 	assert.Equal(t, "sent", updated.Status)
 	require.NotNil(t, savedPO)
@@ -303,13 +555,86 @@ func TestUpdatePOStatus_InvalidTransition_ReturnsError(t *testing.T) {
 
 	svc := NewPOService(db, poRepo, stockRepo, seqSvc)
 
-	_, err := svc.UpdatePOStatus(1, "draft")
+	_, err := svc.UpdatePOStatus(1, "draft", nil, false)
 	require.Error(t, err)
 	serviceErr, ok := err.(*ServiceError)
 	require.True(t, ok)
 	assert.Equal(t, ErrValidation, serviceErr.Err)
 }
 
+func TestUpdatePOStatus_SendWithExpectedDate_SetsDate(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	stockRepo := &mockStockRepo{}
+	seqSvc := NewSequenceService(db)
+
+	draftPO := &models.PurchaseOrder{ID: 1, Status: "draft", Date: "2026-01-15"}
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+			return draftPO, nil
+		},
+	}
+
+	svc := NewPOService(db, poRepo, stockRepo, seqSvc)
+
+	expected := "2026-01-22"
+	updated, err := svc.UpdatePOStatus(1, "sent", &expected, false)
+	require.NoError(t, err)
+	require.NotNil(t, updated.ExpectedDate)
+	assert.Equal(t, "2026-01-22", *updated.ExpectedDate)
+}
+
+func TestUpdatePOStatus_ExpectedDateBeforeOrderDate_ReturnsValidation(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	stockRepo := &mockStockRepo{}
+	seqSvc := NewSequenceService(db)
+
+	draftPO := &models.PurchaseOrder{ID: 1, Status: "draft", Date: "2026-01-15"}
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+			return draftPO, nil
+		},
+	}
+
+	svc := NewPOService(db, poRepo, stockRepo, seqSvc)
+
+	expected := "2026-01-14"
+	_, err := svc.UpdatePOStatus(1, "sent", &expected, false)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+}
+
+func TestNormalizeExpectedDate(t *testing.T) {
+	valid := "2026-01-20"
+	sameDay := "2026-01-15"
+	before := "2026-01-14"
+	blank := "  "
+	malformed := "20/01/2026"
+
+	got, err := normalizeExpectedDate("2026-01-15", &valid)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01-20", *got)
+
+	got, err = normalizeExpectedDate("2026-01-15T00:00:00Z", &sameDay)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-01-15", *got)
+
+	got, err = normalizeExpectedDate("2026-01-15", nil)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	got, err = normalizeExpectedDate("2026-01-15", &blank)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = normalizeExpectedDate("2026-01-15", &before)
+	assert.Error(t, err)
+
+	_, err = normalizeExpectedDate("2026-01-15", &malformed)
+	assert.Error(t, err)
+}
+
 func TestDeletePO_NotFound_ReturnsError(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	stockRepo := &mockStockRepo{}
@@ -357,7 +682,7 @@ func TestListPOs_ReturnsStatusCounts(t *testing.T) {
 	seqSvc := NewSequenceService(db)
 
 	poRepo := &mockPORepo{
-		listFn: func(p repositories.PaginationParams, s string, sid uint) ([]models.PurchaseOrder, int64, error) {
+		listFn: func(p repositories.PaginationParams, f repositories.POListFilter) ([]models.PurchaseOrder, int64, error) {
 			return []models.PurchaseOrder{}, 0, nil
 		},
 		statusCountsFn: func() (map[string]int64, error) {
@@ -368,10 +693,48 @@ func TestListPOs_ReturnsStatusCounts(t *testing.T) {
 	svc := NewPOService(db, poRepo, stockRepo, seqSvc)
 
 	params := repositories.PaginationParams{Page: 1, PageSize: 10}
-	_, _, counts, err := svc.ListPOs(params, "", 0)
+	_, _, counts, err := svc.ListPOs(params, repositories.POListFilter{})
 	require.NoError(t, err)
 	assert.Equal(t, int64(5), counts["all"])
 	assert.Equal(t, int64(3), counts["draft"])
+	cancelled, ok := counts["cancelled"]
+	assert.True(t, ok, "cancelled bucket should be present")
+	assert.Equal(t, int64(0), cancelled)
+}
+
+func TestListPOs_FlagsAwaitedPOsPastExpectedDate(t *testing.T) {
+	past, today, future := "2026-03-09", "2026-03-10", "2026-03-11"
+	var gotFilter repositories.POListFilter
+	poRepo := &mockPORepo{
+		listFn: func(p repositories.PaginationParams, f repositories.POListFilter) ([]models.PurchaseOrder, int64, error) {
+			gotFilter = f
+			return []models.PurchaseOrder{
+				{ID: 1, Status: "sent", ExpectedDate: &past},
+				{ID: 2, Status: "sent", ExpectedDate: &today},
+				{ID: 3, Status: "sent", ExpectedDate: &future},
+				{ID: 4, Status: "received", ExpectedDate: &past},
+				{ID: 5, Status: "sent"},
+				{ID: 6, Status: "partially_received", ExpectedDate: &past},
+			}, 6, nil
+		},
+		statusCountsFn: func() (map[string]int64, error) {
+			return map[string]int64{"all": 6}, nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil)
+	svc.now = func() time.Time { return time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC) }
+
+	params := repositories.PaginationParams{Page: 1, PageSize: 10}
+	pos, _, _, err := svc.ListPOs(params, repositories.POListFilter{Overdue: true})
+	require.NoError(t, err)
+
+	assert.True(t, gotFilter.Overdue)
+	assert.Equal(t, "2026-03-10", gotFilter.Today)
+	overdue := make([]bool, 0, len(pos))
+	for _, po := range pos {
+		overdue = append(overdue, po.Overdue)
+	}
+	assert.Equal(t, []bool{true, false, false, false, false, true}, overdue)
 }
 
 func TestReceivePO_BankTransferNoBankAccount_ReturnsError(t *testing.T) {
@@ -403,9 +766,602 @@ func TestReceivePO_BankTransferNoBankAccount_ReturnsError(t *testing.T) {
 		},
 	}
 
-	_, err := svc.ReceivePO(1, input)
+	_, err := svc.ReceivePO(context.Background(), 1, input)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+}
+
+// sentPOLine is an ordered line of a PO created by createSentPO.
+type sentPOLine struct {
+	name  string
+	qty   int
+	price float64
+}
+
+// createSentPO stores a sent PO ordering the product's first variant in unit,
+// one item per line.
+func createSentPO(t *testing.T, db *gorm.DB, product *models.Product, unit models.ProductUnit, lines ...sentPOLine) *models.PurchaseOrder {
+	t.Helper()
+	supplier := testutil.CreateTestSupplier(t, db)
+	po := &models.PurchaseOrder{
+		PONumber:   fmt.Sprintf("PO-SVC-%d", product.ID),
+		SupplierID: supplier.ID,
+		Date:       "2026-02-01",
+		Status:     "sent",
+	}
+	for _, line := range lines {
+		po.Items = append(po.Items, models.PurchaseOrderItem{
+			ProductID:    product.ID,
+			VariantID:    product.Variants[0].ID,
+			UnitID:       unit.ID,
+			UnitName:     unit.Name,
+			ProductName:  line.name,
+			VariantLabel: "Default",
+			OrderedQty:   line.qty,
+			Price:        line.price,
+		})
+	}
+	require.NoError(t, db.Create(po).Error)
+	return po
+}
+
+// receivedItem returns the item with id from a received PO.
+func receivedItem(t *testing.T, po *models.PurchaseOrder, id string) models.PurchaseOrderItem {
+	t.Helper()
+	for _, item := range po.Items {
+		if item.ID == id {
+			return item
+		}
+	}
+	require.Failf(t, "item not found", "item %s is not on PO %d", id, po.ID)
+	return models.PurchaseOrderItem{}
+}
+
+// riceAndSugarPO stores a sent PO of 10 Rice at 5000 and 5 Sugar at 12000.
+func riceAndSugarPO(t *testing.T, db *gorm.DB) *models.PurchaseOrder {
+	t.Helper()
+	product := testutil.CreateTestProduct(t, db)
+	return createSentPO(t, db, product, product.Units[0],
+		sentPOLine{name: "Rice", qty: 10, price: 5000},
+		sentPOLine{name: "Sugar", qty: 5, price: 12000},
+	)
+}
+
+func TestReceivePO_NoReceivedItems_ReturnsValidationError(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	stored := riceAndSugarPO(t, db)
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, nil, POConfig{RequireReceivedItems: true})
+
+	_, err := svc.ReceivePO(context.Background(), stored.ID, ReceivePOInput{
+		ReceivedDate:  "2026-02-03",
+		PaymentMethod: "cash",
+		Items: []ReceivePOItemInput{
+			{ItemID: stored.Items[0].ID, ReceivedQty: 0, ReceivedPrice: 5000, IsVerified: true},
+		},
+	})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "PO_NOTHING_RECEIVED", serviceErr.Code)
+
+	var reloaded models.PurchaseOrder
+	require.NoError(t, db.First(&reloaded, stored.ID).Error)
+	assert.Equal(t, "sent", reloaded.Status)
+}
+
+func TestReceivePO_OnlyVerificationRequired_AllowsNothingReceived(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	stored := riceAndSugarPO(t, db)
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, NewSequenceService(db), POConfig{RequireVerifiedItems: true})
+
+	_, err := svc.ReceivePO(context.Background(), stored.ID, ReceivePOInput{
+		ReceivedDate:  "2026-02-03",
+		PaymentMethod: "cash",
+		Items: []ReceivePOItemInput{
+			{ItemID: stored.Items[0].ID, ReceivedQty: 0, ReceivedPrice: 5000},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestReceivePO_UnverifiedReceivedItem_ReturnsValidationError(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	stored := riceAndSugarPO(t, db)
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, nil, POConfig{
+		RequireReceivedItems: true,
+		RequireVerifiedItems: true,
+	})
+
+	_, err := svc.ReceivePO(context.Background(), stored.ID, ReceivePOInput{
+		ReceivedDate:  "2026-02-03",
+		PaymentMethod: "cash",
+		Items: []ReceivePOItemInput{
+			{ItemID: stored.Items[0].ID, ReceivedQty: 10, ReceivedPrice: 5000, IsVerified: true},
+			{ItemID: stored.Items[1].ID, ReceivedQty: 5, ReceivedPrice: 12000},
+		},
+	})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "PO_ITEM_NOT_VERIFIED", serviceErr.Code)
+	assert.Contains(t, serviceErr.Message, "Sugar")
+
+	var reloaded models.PurchaseOrder
+	require.NoError(t, db.First(&reloaded, stored.ID).Error)
+	assert.Equal(t, "sent", reloaded.Status)
+}
+
+func TestReceivePO_VerifiedReceivedItem_Succeeds(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	stored := riceAndSugarPO(t, db)
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, NewSequenceService(db), POConfig{
+		RequireReceivedItems: true,
+		RequireVerifiedItems: true,
+	})
+
+	po, err := svc.ReceivePO(context.Background(), stored.ID, ReceivePOInput{
+		ReceivedDate:  "2026-02-03",
+		PaymentMethod: "cash",
+		Items: []ReceivePOItemInput{
+			{ItemID: stored.Items[0].ID, ReceivedQty: 10, ReceivedPrice: 5000, IsVerified: true},
+			{ItemID: stored.Items[1].ID, ReceivedQty: 0, ReceivedPrice: 12000},
+		},
+	})
+	require.NoError(t, err)
+	// Sugar is still outstanding
+	assert.Equal(t, "partially_received", po.Status)
+
+	var reloaded models.PurchaseOrder
+	require.NoError(t, db.First(&reloaded, stored.ID).Error)
+	assert.Equal(t, "partially_received", reloaded.Status)
+}
+
+func TestReceivePO_TwoPartialReceipts_AccumulateUntilReceived(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	product := testutil.CreateTestProduct(t, db)
+	variantID := product.Variants[0].ID
+
+	stored := createSentPO(t, db, product, product.Units[0],
+		sentPOLine{name: "Rice", qty: 10, price: 5000},
+		sentPOLine{name: "Sugar", qty: 4, price: 12000},
+	)
+	riceID, sugarID := stored.Items[0].ID, stored.Items[1].ID
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, NewSequenceService(db))
+
+	po, err := svc.ReceivePO(context.Background(), stored.ID, ReceivePOInput{
+		ReceivedDate:  "2026-02-03",
+		PaymentMethod: "cash",
+		Items: []ReceivePOItemInput{
+			{ItemID: riceID, ReceivedQty: 6, ReceivedPrice: 5000, IsVerified: true},
+			{ItemID: sugarID, ReceivedQty: 4, ReceivedPrice: 12000, IsVerified: true},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "partially_received", po.Status)
+	assert.Equal(t, 6, *receivedItem(t, po, riceID).ReceivedQty)
+	assert.Equal(t, 10, *po.TotalItems)
+
+	po, err = svc.ReceivePO(context.Background(), stored.ID, ReceivePOInput{
+		ReceivedDate:  "2026-02-10",
+		PaymentMethod: "cash",
+		Items: []ReceivePOItemInput{
+			{ItemID: riceID, ReceivedQty: 4, ReceivedPrice: 5500, IsVerified: true},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "received", po.Status)
+	rice := receivedItem(t, po, riceID)
+	assert.Equal(t, 10, *rice.ReceivedQty)
+	assert.Equal(t, 5200.0, *rice.ReceivedPrice)
+	assert.Equal(t, 4, *receivedItem(t, po, sugarID).ReceivedQty)
+	assert.Equal(t, 14, *po.TotalItems)
+	assert.Equal(t, 30000.0+48000.0+22000.0, *po.Subtotal)
+	assert.Equal(t, 0.0, *po.Outstanding)
+
+	var variant models.ProductVariant
+	require.NoError(t, db.First(&variant, "id = ?", variantID).Error)
+	assert.Equal(t, product.Variants[0].CurrentStock+14, variant.CurrentStock)
+}
+
+func TestReceivePO_ConcurrentReceipts_AccumulateEveryDelivery(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	product := testutil.CreateTestProduct(t, db)
+	stored := createSentPO(t, db, product, product.Units[0], sentPOLine{name: "Rice", qty: 10, price: 5000})
+	itemID := stored.Items[0].ID
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, NewSequenceService(db))
+
+	const receipts = 5
+	var wg sync.WaitGroup
+	errs := make([]error, receipts)
+	for i := 0; i < receipts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = svc.ReceivePO(context.Background(), stored.ID, ReceivePOInput{
+				ReceivedDate:  "2026-02-03",
+				PaymentMethod: "cash",
+				Items:         []ReceivePOItemInput{{ItemID: itemID, ReceivedQty: 2, ReceivedPrice: 5000, IsVerified: true}},
+			})
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	var item models.PurchaseOrderItem
+	require.NoError(t, db.First(&item, "id = ?", itemID).Error)
+	require.NotNil(t, item.ReceivedQty)
+	assert.Equal(t, 10, *item.ReceivedQty)
+
+	var po models.PurchaseOrder
+	require.NoError(t, db.First(&po, stored.ID).Error)
+	assert.Equal(t, "received", po.Status)
+	require.NotNil(t, po.TotalItems)
+	assert.Equal(t, 10, *po.TotalItems)
+}
+
+func TestReceivePO_Received_ReturnsInvalidStatus(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	stored := riceAndSugarPO(t, db)
+	require.NoError(t, db.Model(stored).Update("status", "received").Error)
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, nil)
+
+	_, err := svc.ReceivePO(context.Background(), stored.ID, ReceivePOInput{PaymentMethod: "cash"})
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "PO_INVALID_STATUS", serviceErr.Code)
+}
+
+func TestAccumulateReceipt_ZeroDeliveryKeepsPrice(t *testing.T) {
+	qty, price := 3, 1000.0
+	item := &models.PurchaseOrderItem{OrderedQty: 5, ReceivedQty: &qty, ReceivedPrice: &price, IsVerified: true}
+
+	accumulateReceipt(item, 0, 0, false)
+
+	assert.Equal(t, 3, *item.ReceivedQty)
+	assert.Equal(t, 1000.0, *item.ReceivedPrice)
+	assert.True(t, item.IsVerified)
+}
+
+func TestLastPOForSupplier_ReturnsLatestItemsAsSuggestions(t *testing.T) {
+	receivedPrice := 14500.0
+	poRepo := &mockPORepo{
+		getLatestFn: func(supplierID uint) (*models.PurchaseOrder, error) {
+			assert.Equal(t, uint(7), supplierID)
+			return &models.PurchaseOrder{
+				ID:       12,
+				PONumber: "PO-2026-0012",
+				Date:     "2026-02-01",
+				Status:   "received",
+				Items: []models.PurchaseOrderItem{
+					{ProductID: 1, VariantID: "v-1", UnitID: 3, ProductName: "Rice", UnitName: "Kg", OrderedQty: 10, Price: 15000, ReceivedPrice: &receivedPrice},
+					{ProductID: 2, VariantID: "v-2", UnitID: 4, ProductName: "Sugar", UnitName: "Kg", OrderedQty: 5, Price: 12000},
+				},
+			}, nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil)
+
+	result, err := svc.LastPOForSupplier(7)
+	require.NoError(t, err)
+	require.NotNil(t, result.SourcePOID)
+	assert.Equal(t, uint(12), *result.SourcePOID)
+	assert.Equal(t, "PO-2026-0012", result.SourcePONumber)
+	require.Len(t, result.Items, 2)
+	assert.Equal(t, "v-1", result.Items[0].VariantID)
+	assert.Equal(t, 10, result.Items[0].OrderedQty)
+	assert.Equal(t, 14500.0, result.Items[0].Price)
+	assert.Equal(t, 12000.0, result.Items[1].Price)
+}
+
+func TestLastPOForSupplier_NoPOs_ReturnsEmpty(t *testing.T) {
+	svc := NewPOService(nil, &mockPORepo{}, &mockStockRepo{}, nil)
+
+	result, err := svc.LastPOForSupplier(7)
+	require.NoError(t, err)
+	assert.Nil(t, result.SourcePOID)
+	assert.Empty(t, result.Items)
+}
+
+func newHighValueDraftPO(createdBy uint) *models.PurchaseOrder {
+	return &models.PurchaseOrder{
+		ID:        1,
+		Date:      "2026-02-01",
+		Status:    "draft",
+		CreatedBy: &createdBy,
+		Items: []models.PurchaseOrderItem{
+			{OrderedQty: 100, Price: 15000},
+		},
+	}
+}
+
+func TestUpdatePOStatus_HighValueUnapproved_BlockedUntilApproved(t *testing.T) {
+	po := newHighValueDraftPO(5)
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+			return po, nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil, POConfig{ApprovalThreshold: 1000000})
+
+	_, err := svc.UpdatePOStatus(1, "sent", nil, false)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrForbidden, serviceErr.Err)
+	assert.Equal(t, "PO_APPROVAL_REQUIRED", serviceErr.Code)
+	assert.Equal(t, "draft", po.Status)
+
+	approved, err := svc.Approve(1, 9)
+	require.NoError(t, err)
+	require.NotNil(t, approved.ApprovedBy)
+	assert.Equal(t, uint(9), *approved.ApprovedBy)
+	assert.NotNil(t, approved.ApprovedAt)
+
+	updated, err := svc.UpdatePOStatus(1, "sent", nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, "sent", updated.Status)
+}
+
+func TestUpdatePOStatus_BelowThreshold_NoApprovalNeeded(t *testing.T) {
+	po := newHighValueDraftPO(5)
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+			return po, nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil, POConfig{ApprovalThreshold: 5000000})
+
+	updated, err := svc.UpdatePOStatus(1, "sent", nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, "sent", updated.Status)
+}
+
+func TestReceivePO_HighValueUnapproved_ReturnsForbidden(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	product := testutil.CreateTestProduct(t, db)
+	stored := createSentPO(t, db, product, product.Units[0], sentPOLine{name: "Rice", qty: 100, price: 15000})
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, nil, POConfig{ApprovalThreshold: 1000000})
+
+	_, err := svc.ReceivePO(context.Background(), stored.ID, ReceivePOInput{ReceivedDate: "2026-02-03", PaymentMethod: "cash"})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "PO_APPROVAL_REQUIRED", serviceErr.Code)
+}
+
+func TestUpdatePO_ApprovedDraftHeaderEdit_ClearsApproval(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	product := testutil.CreateTestProduct(t, db)
+	stored := createSentPO(t, db, product, product.Units[0], sentPOLine{name: "Rice", qty: 100, price: 15000})
+	approver := testutil.CreateTestUser(t, db)
+	approvedAt := time.Now()
+	require.NoError(t, db.Model(stored).Updates(map[string]interface{}{
+		"status":      "draft",
+		"approved_by": approver.ID,
+		"approved_at": approvedAt,
+	}).Error)
+	otherSupplier := testutil.CreateTestSupplier(t, db)
+	svc := NewPOService(db, repositories.NewPORepository(db), &mockStockRepo{}, nil)
+
+	updated, err := svc.UpdatePO(stored.ID, CreatePOInput{SupplierID: otherSupplier.ID, Date: "2026-02-01", Notes: "changed"})
+	require.NoError(t, err)
+	assert.Nil(t, updated.ApprovedBy)
+
+	var reloaded models.PurchaseOrder
+	require.NoError(t, db.Preload("Items").First(&reloaded, stored.ID).Error)
+	assert.Equal(t, otherSupplier.ID, reloaded.SupplierID)
+	assert.Nil(t, reloaded.ApprovedBy)
+	assert.Nil(t, reloaded.ApprovedAt)
+	assert.Len(t, reloaded.Items, 1)
+}
+
+func TestApprove_ByCreator_ReturnsForbidden(t *testing.T) {
+	po := newHighValueDraftPO(5)
+	updateCalled := false
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+			return po, nil
+		},
+		updateFn: func(po *models.PurchaseOrder) error {
+			updateCalled = true
+			return nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil, POConfig{ApprovalThreshold: 1000000})
+
+	_, err := svc.Approve(1, 5)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrForbidden, serviceErr.Err)
+	assert.Equal(t, "PO_SELF_APPROVAL", serviceErr.Code)
+	assert.False(t, updateCalled)
+	assert.Nil(t, po.ApprovedBy)
+}
+
+func TestApprove_AlreadyApproved_ReturnsConflict(t *testing.T) {
+	po := newHighValueDraftPO(5)
+	approver := uint(9)
+	po.ApprovedBy = &approver
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+			return po, nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil)
+
+	_, err := svc.Approve(1, 10)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrConflict, serviceErr.Err)
+}
+
+func TestMarkPaid_ReceivedPO_SetsPaidAt(t *testing.T) {
+	po := newHighValueDraftPO(5)
+	po.Status = "received"
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+			return po, nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil)
+
+	paid, err := svc.MarkPaid(1)
+	require.NoError(t, err)
+	assert.NotNil(t, paid.PaidAt)
+
+	_, err = svc.MarkPaid(1)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrConflict, serviceErr.Err)
+	assert.Equal(t, "PO_ALREADY_PAID", serviceErr.Code)
+}
+
+func TestMarkPaid_NotFullyReceived_ReturnsValidationError(t *testing.T) {
+	for _, status := range []string{"sent", "partially_received"} {
+		t.Run(status, func(t *testing.T) {
+			po := newHighValueDraftPO(5)
+			po.Status = status
+			markPaidCalled := false
+			poRepo := &mockPORepo{
+				getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+					return po, nil
+				},
+				markPaidFn: func(id uint, paidAt time.Time) (bool, error) {
+					markPaidCalled = true
+					return true, nil
+				},
+			}
+			svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil)
+
+			_, err := svc.MarkPaid(1)
+			require.Error(t, err)
+			serviceErr, ok := err.(*ServiceError)
+			require.True(t, ok)
+			assert.Equal(t, ErrValidation, serviceErr.Err)
+			assert.Equal(t, "PO_INVALID_STATUS", serviceErr.Code)
+			assert.False(t, markPaidCalled)
+			assert.Nil(t, po.PaidAt)
+		})
+	}
+}
+
+func TestMarkPaid_ConcurrentPayment_ReturnsConflict(t *testing.T) {
+	po := newHighValueDraftPO(5)
+	po.Status = "received"
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+			copied := *po
+			return &copied, nil
+		},
+		updateFn: func(*models.PurchaseOrder) error {
+			t.Fatal("MarkPaid must not save the whole PO")
+			return nil
+		},
+		markPaidFn: func(id uint, paidAt time.Time) (bool, error) {
+			return false, nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil)
+
+	_, err := svc.MarkPaid(1)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrConflict, serviceErr.Err)
+	assert.Equal(t, "PO_ALREADY_PAID", serviceErr.Code)
+}
+
+func TestApplyPOTotals_PartiallyReceived(t *testing.T) {
+	receivedQtyFull := 10
+	receivedPriceFull := 14500.0
+	receivedQtyPartial := 2
+	receivedPricePartial := 12000.0
+	po := &models.PurchaseOrder{
+		Items: []models.PurchaseOrderItem{
+			{OrderedQty: 10, Price: 15000, ReceivedQty: &receivedQtyFull, ReceivedPrice: &receivedPriceFull},
+			{OrderedQty: 5, Price: 12000, ReceivedQty: &receivedQtyPartial, ReceivedPrice: &receivedPricePartial},
+			{OrderedQty: 4, Price: 2500},
+		},
+	}
+
+	applyPOTotals(po)
+
+	require.NotNil(t, po.OrderedTotal)
+	assert.Equal(t, 220000.0, *po.OrderedTotal)
+	assert.Equal(t, 169000.0, *po.ReceivedTotal)
+	assert.Equal(t, 46000.0, *po.Outstanding)
+}
+
+func TestPOGrandTotal_TaxOnDiscountedSubtotal(t *testing.T) {
+	// (200000 - 20000) + 11% of 180000
+	assert.Equal(t, 199800.0, poGrandTotal(200000, 11, 20000))
+	assert.Equal(t, 200000.0, poGrandTotal(200000, 0, 0))
+}
+
+func TestApplyPOTotals_MissingGrandTotal_UsesSubtotal(t *testing.T) {
+	subtotal := 150000.0
+	po := &models.PurchaseOrder{Subtotal: &subtotal}
+
+	applyPOTotals(po)
+
+	require.NotNil(t, po.GrandTotal)
+	assert.Equal(t, 150000.0, *po.GrandTotal)
+	assert.Equal(t, 0.0, po.TaxPercent)
+	assert.Equal(t, 0.0, po.DiscountAmount)
+}
+
+func TestReceivePO_DiscountAboveSubtotal_ReturnsValidationError(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	stored := riceAndSugarPO(t, db)
+	svc := NewPOService(db, &mockPORepo{}, &mockStockRepo{}, nil)
+	discount := 60000.0
+
+	_, err := svc.ReceivePO(context.Background(), stored.ID, ReceivePOInput{
+		ReceivedDate:   "2026-02-03",
+		PaymentMethod:  "cash",
+		DiscountAmount: &discount,
+		Items: []ReceivePOItemInput{
+			{ItemID: stored.Items[0].ID, ReceivedQty: 10, ReceivedPrice: 5000, IsVerified: true},
+		},
+	})
 	require.Error(t, err)
 	serviceErr, ok := err.(*ServiceError)
 	require.True(t, ok)
 	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Contains(t, serviceErr.Message, "discountAmount")
+
+	var reloaded models.PurchaseOrder
+	require.NoError(t, db.First(&reloaded, stored.ID).Error)
+	assert.Equal(t, "sent", reloaded.Status)
+}
+
+func TestGetPO_IncludesComputedTotals(t *testing.T) {
+	receivedQty := 3
+	receivedPrice := 9000.0
+	poRepo := &mockPORepo{
+		getByIDFn: func(id uint) (*models.PurchaseOrder, error) {
+			return &models.PurchaseOrder{
+				ID:     id,
+				Status: "received",
+				Items: []models.PurchaseOrderItem{
+					{OrderedQty: 5, Price: 10000, ReceivedQty: &receivedQty, ReceivedPrice: &receivedPrice},
+				},
+			}, nil
+		},
+	}
+	svc := NewPOService(nil, poRepo, &mockStockRepo{}, nil)
+
+	po, err := svc.GetPO(1)
+	require.NoError(t, err)
+	assert.Equal(t, 50000.0, *po.OrderedTotal)
+	assert.Equal(t, 27000.0, *po.ReceivedTotal)
+	assert.Equal(t, 20000.0, *po.Outstanding)
 }
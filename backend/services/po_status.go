@@ -1,24 +1,61 @@
 package services
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
 
-// validPOTransitions defines allowed PO status transitions
+	"github.com/pointofsale/backend/models"
+)
+
+// validPOTransitions defines allowed PO status transitions.
+// Stock is booked when a PO is received, so received and completed POs never
+// move back to draft or sent. A partially received PO becomes received once
+// every item is fully received; it can also be cancelled, or closed as
+// completed, when the rest will never arrive. Stock already received stays
+// booked either way. Completed and cancelled are terminal.
 var validPOTransitions = map[string][]string{
-	"draft":    {"sent", "cancelled"},
-	"sent":     {"cancelled"},
-	"received": {"completed"},
+	"draft":              {"sent", "cancelled"},
+	"sent":               {"cancelled"},
+	"partially_received": {"cancelled", "completed"},
+	"received":           {"completed"},
+	"completed":          {},
+	"cancelled":          {},
+}
+
+// receivablePOStatuses are the statuses in which goods can be received
+// against a PO.
+var receivablePOStatuses = map[string]bool{
+	"draft":              true,
+	"sent":               true,
+	"partially_received": true,
 }
 
 // ValidatePOStatusTransition checks if the transition from current to next status is allowed.
+// The error message lists the statuses the PO may move to.
 func ValidatePOStatusTransition(current, next string) error {
 	allowed, exists := validPOTransitions[current]
 	if !exists {
-		return fmt.Errorf("invalid status transition from %s to %s", current, next)
+		return fmt.Errorf("invalid status transition from unknown status %s", current)
 	}
 	for _, s := range allowed {
 		if s == next {
 			return nil
 		}
 	}
-	return fmt.Errorf("invalid status transition from %s to %s", current, next)
+	allowedList := "none"
+	if len(allowed) > 0 {
+		allowedList = strings.Join(allowed, ", ")
+	}
+	return fmt.Errorf("invalid status transition from %s to %s (allowed: %s)", current, next, allowedList)
+}
+
+// receivedPOStatus returns the status of a PO after a receipt: received when
+// every item has been received in full, partially_received otherwise.
+func receivedPOStatus(items []models.PurchaseOrderItem) string {
+	for _, item := range items {
+		if item.ReceivedQty == nil || *item.ReceivedQty < item.OrderedQty {
+			return "partially_received"
+		}
+	}
+	return "received"
 }
@@ -3,6 +3,7 @@ package services
 import (
 	"testing"
 
+	"github.com/pointofsale/backend/models"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,3 +50,60 @@ func TestValidateStatusTransition_SentToDraft_Invalid(t *testing.T) {
 	err := ValidatePOStatusTransition("sent", "draft")
 	assert.Error(t, err)
 }
+
+func TestValidateStatusTransition_ReverseFromReceivedOrCompleted_Invalid(t *testing.T) {
+	reverse := []struct {
+		current string
+		next    string
+	}{
+		{"received", "draft"},
+		{"received", "sent"},
+		{"received", "received"},
+		{"received", "cancelled"},
+		{"completed", "draft"},
+		{"completed", "sent"},
+		{"completed", "received"},
+		{"completed", "completed"},
+		{"completed", "cancelled"},
+	}
+
+	for _, tt := range reverse {
+		err := ValidatePOStatusTransition(tt.current, tt.next)
+		assert.Error(t, err, "%s -> %s should be invalid", tt.current, tt.next)
+	}
+}
+
+func TestValidateStatusTransition_Invalid_ListsAllowedNextStates(t *testing.T) {
+	err := ValidatePOStatusTransition("received", "draft")
+	assert.EqualError(t, err, "invalid status transition from received to draft (allowed: completed)")
+
+	err = ValidatePOStatusTransition("draft", "received")
+	assert.EqualError(t, err, "invalid status transition from draft to received (allowed: sent, cancelled)")
+
+	err = ValidatePOStatusTransition("completed", "sent")
+	assert.EqualError(t, err, "invalid status transition from completed to sent (allowed: none)")
+}
+
+func TestValidateStatusTransition_PartiallyReceived_CanBeCancelledOrClosed(t *testing.T) {
+	assert.NoError(t, ValidatePOStatusTransition("partially_received", "cancelled"))
+	assert.NoError(t, ValidatePOStatusTransition("partially_received", "completed"))
+
+	for _, next := range []string{"draft", "sent", "received"} {
+		err := ValidatePOStatusTransition("partially_received", next)
+		assert.Error(t, err, "partially_received -> %s should be invalid", next)
+	}
+}
+
+func TestReceivedPOStatus(t *testing.T) {
+	full, short := 10, 4
+	assert.Equal(t, "received", receivedPOStatus([]models.PurchaseOrderItem{
+		{OrderedQty: 10, ReceivedQty: &full},
+	}))
+	assert.Equal(t, "partially_received", receivedPOStatus([]models.PurchaseOrderItem{
+		{OrderedQty: 10, ReceivedQty: &full},
+		{OrderedQty: 10, ReceivedQty: &short},
+	}))
+	assert.Equal(t, "partially_received", receivedPOStatus([]models.PurchaseOrderItem{
+		{OrderedQty: 10},
+	}))
+}
@@ -2,7 +2,20 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"sort"
+
+	"github.com/pointofsale/backend/models"
+)
+
+// PriceSettingMarkup is the price setting of products priced as a markup on
+// their received cost.
+const PriceSettingMarkup = "markup"
+
+// Markup types of products with the markup price setting.
+const (
+	MarkupPercentage  = "percentage"
+	MarkupFixedAmount = "fixed_amount"
 )
 
 // PricingTier represents a tier in the pricing structure.
@@ -37,3 +50,35 @@ func CalculateTieredPrice(tiers []PricingTier, quantity int, toBaseUnit int) (fl
 	// Fallback to lowest tier
 	return sorted[len(sorted)-1].Value, nil
 }
+
+// SellingTiers returns the tiers a variant sells at. Fixed-price tiers sell at
+// their value. Markup tiers hold the markup in Value and sell at the price
+// derived from the last received cost, so tiers not yet priced are left out.
+func SellingTiers(priceSetting string, tiers []models.VariantPricingTier) []PricingTier {
+	selling := make([]PricingTier, 0, len(tiers))
+	for _, t := range tiers {
+		value := t.Value
+		if priceSetting == PriceSettingMarkup {
+			if t.SellingPrice == nil {
+				continue
+			}
+			value = *t.SellingPrice
+		}
+		selling = append(selling, PricingTier{MinQty: t.MinQty, Value: value})
+	}
+	return selling
+}
+
+// MarkupPrice returns the selling price for a unit cost and a markup. A
+// percentage markup adds that percent of the cost, a fixed_amount markup adds
+// the amount itself. The price is rounded to 2 decimals.
+func MarkupPrice(cost float64, markupType string, markup float64) (float64, error) {
+	switch markupType {
+	case MarkupPercentage:
+		return roundTo(cost*(1+markup/100), 2), nil
+	case MarkupFixedAmount:
+		return roundTo(cost+markup, 2), nil
+	default:
+		return 0, fmt.Errorf("unknown markup type %q", markupType)
+	}
+}
@@ -61,3 +61,24 @@ func TestCalculateTieredPrice_EmptyTiers_ReturnsError(t *testing.T) {
 	_, err := CalculateTieredPrice(tiers, 5, 1)
 	assert.Error(t, err)
 }
+
+func TestMarkupPrice_Percentage_AddsPercentOfCost(t *testing.T) {
+	price, err := MarkupPrice(8000, MarkupPercentage, 25)
+	require.NoError(t, err)
+	assert.Equal(t, 10000.0, price)
+
+	price, err = MarkupPrice(1234.5, MarkupPercentage, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1357.95, price)
+}
+
+func TestMarkupPrice_FixedAmount_AddsAmountToCost(t *testing.T) {
+	price, err := MarkupPrice(8000, MarkupFixedAmount, 1500)
+	require.NoError(t, err)
+	assert.Equal(t, 9500.0, price)
+}
+
+func TestMarkupPrice_UnknownType_ReturnsError(t *testing.T) {
+	_, err := MarkupPrice(8000, "ratio", 2)
+	require.Error(t, err)
+}
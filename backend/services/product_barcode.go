@@ -0,0 +1,41 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/pointofsale/backend/utils"
+)
+
+// maxBarcodeAttempts bounds how many random barcodes are tried before giving
+// up when generated barcodes collide with existing ones.
+const maxBarcodeAttempts = 5
+
+// generateBarcode returns an in-store EAN-13 barcode that is not already used
+// by another product nor listed in taken (lower-cased barcodes of the
+// product's own variants). The generated barcode is added to taken.
+func (s *ProductService) generateBarcode(productID uint, taken map[string]struct{}) (string, error) {
+	for attempt := 0; attempt < maxBarcodeAttempts; attempt++ {
+		barcode, err := utils.GenerateEAN13()
+		if err != nil {
+			return "", err
+		}
+		key := strings.ToLower(barcode)
+		if _, exists := taken[key]; exists {
+			continue
+		}
+		exists, err := s.repo.BarcodeExistsForOtherProducts(barcode, productID)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			continue
+		}
+		taken[key] = struct{}{}
+		return barcode, nil
+	}
+	return "", &ServiceError{
+		Err:     ErrConflict,
+		Message: "Could not generate a unique barcode, please provide one",
+		Code:    "BARCODE_GENERATION_FAILED",
+	}
+}
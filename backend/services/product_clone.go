@@ -0,0 +1,107 @@
+package services
+
+import (
+	"github.com/pointofsale/backend/models"
+)
+
+// cloneNameSuffix is appended to the name of a cloned product.
+const cloneNameSuffix = " (Copy)"
+
+// maxProductNameLength mirrors the product name limit in ValidateProductInput.
+const maxProductNameLength = 255
+
+// Clone creates a copy of a product with its units, variants, attributes,
+// pricing tiers, supplier links and rack assignments. The copy starts with no
+// stock, its name gets a " (Copy)" suffix and every variant gets a new ID,
+// barcode and, when a SKU sequence is configured, SKU. Images are not copied
+// so the two products never share stored image objects. Inactive suppliers
+// and racks are left out, as a new product cannot reference them.
+func (s *ProductService) Clone(productID uint) (*models.Product, *ServiceError) {
+	source, svcErr := s.GetProduct(productID)
+	if svcErr != nil {
+		return nil, svcErr
+	}
+
+	return s.CreateProduct(cloneProductInput(source))
+}
+
+// cloneProductInput builds the create payload for a copy of product.
+func cloneProductInput(product *models.Product) CreateProductInput {
+	input := CreateProductInput{
+		Name:         cloneName(product.Name),
+		Description:  product.Description,
+		CategoryID:   product.CategoryID,
+		PriceSetting: product.PriceSetting,
+		MarkupType:   product.MarkupType,
+		HasVariants:  product.HasVariants,
+		Status:       product.Status,
+		Images:       []CreateProductImageInput{},
+		Units:        make([]CreateProductUnitInput, 0, len(product.Units)),
+		Variants:     make([]CreateProductVariantInput, 0, len(product.Variants)),
+		AutoBarcode:  true,
+	}
+
+	for _, supplier := range product.Suppliers {
+		if supplier.Active {
+			input.SupplierIDs = append(input.SupplierIDs, supplier.ID)
+		}
+	}
+
+	unitNames := make(map[uint]string, len(product.Units))
+	for _, unit := range product.Units {
+		unitNames[unit.ID] = unit.Name
+	}
+	for _, unit := range product.Units {
+		in := CreateProductUnitInput{
+			Name:             unit.Name,
+			ConversionFactor: unit.ConversionFactor,
+			IsBase:           unit.IsBase,
+		}
+		if unit.ConvertsToID != nil {
+			in.ConvertsToName = unitNames[*unit.ConvertsToID]
+		}
+		input.Units = append(input.Units, in)
+	}
+
+	for _, variant := range product.Variants {
+		reorderPoint := variant.ReorderPoint
+		in := CreateProductVariantInput{
+			CostPrice:    variant.CostPrice,
+			ReorderPoint: &reorderPoint,
+			Attributes:   make([]CreateVariantAttributeInput, 0, len(variant.Attributes)),
+			Images:       []CreateVariantImageInput{},
+			PricingTiers: make([]CreateVariantPricingTierInput, 0, len(variant.PricingTiers)),
+		}
+		for _, attr := range variant.Attributes {
+			in.Attributes = append(in.Attributes, CreateVariantAttributeInput{
+				AttributeName:  attr.AttributeName,
+				AttributeValue: attr.AttributeValue,
+			})
+		}
+		for _, tier := range variant.PricingTiers {
+			in.PricingTiers = append(in.PricingTiers, CreateVariantPricingTierInput{
+				MinQty: tier.MinQty,
+				Value:  tier.Value,
+			})
+		}
+		for _, rack := range variant.Racks {
+			if rack.Active {
+				in.RackIDs = append(in.RackIDs, rack.ID)
+			}
+		}
+		input.Variants = append(input.Variants, in)
+	}
+
+	return input
+}
+
+// cloneName appends the copy suffix, shortening the original name when the
+// result would exceed the product name limit.
+func cloneName(name string) string {
+	limit := maxProductNameLength - len(cloneNameSuffix)
+	runes := []rune(name)
+	for len(string(runes)) > limit {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + cloneNameSuffix
+}
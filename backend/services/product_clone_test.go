@@ -0,0 +1,80 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneProductInput_CopiesStructureWithoutStockOrIdentifiers(t *testing.T) {
+	baseID := uint(1)
+	costPrice := 7000.0
+	product := &models.Product{
+		ID:           10,
+		Name:         "Coffee",
+		Description:  "Arabica beans",
+		CategoryID:   3,
+		PriceSetting: "fixed",
+		HasVariants:  true,
+		Status:       "active",
+		Images:       []models.ProductImage{{ImageURL: "https://cdn.example.com/coffee.jpg"}},
+		Suppliers:    []models.Supplier{{ID: 4, Active: true}, {ID: 5, Active: false}},
+		Units: []models.ProductUnit{
+			{ID: 1, Name: "Pcs", ConversionFactor: 1, IsBase: true},
+			{ID: 2, Name: "Box", ConversionFactor: 12, ConvertsToID: &baseID},
+		},
+		Variants: []models.ProductVariant{
+			{
+				ID:           "b5a4a4d2-36a3-4e93-9f2e-8f2f2f0b7a11",
+				SKU:          "COF-000001",
+				Barcode:      "8901234567005",
+				CurrentStock: 40,
+				ReorderPoint: 5,
+				CostPrice:    &costPrice,
+				Attributes:   []models.VariantAttribute{{AttributeName: "Size", AttributeValue: "250g"}},
+				Images:       []models.VariantImage{{ImageURL: "https://cdn.example.com/coffee-250.jpg"}},
+				PricingTiers: []models.VariantPricingTier{{MinQty: 1, Value: 10000}, {MinQty: 10, Value: 9000}},
+				Racks:        []models.Rack{{ID: 6, Active: true}, {ID: 7, Active: false}},
+			},
+		},
+	}
+
+	input := cloneProductInput(product)
+
+	assert.Equal(t, "Coffee (Copy)", input.Name)
+	assert.Equal(t, "Arabica beans", input.Description)
+	assert.Equal(t, uint(3), input.CategoryID)
+	assert.True(t, input.HasVariants)
+	assert.True(t, input.AutoBarcode)
+	assert.Empty(t, input.Images)
+	assert.Equal(t, []uint{4}, input.SupplierIDs)
+
+	require.Len(t, input.Units, 2)
+	assert.Equal(t, CreateProductUnitInput{Name: "Pcs", ConversionFactor: 1, IsBase: true}, input.Units[0])
+	assert.Equal(t, CreateProductUnitInput{Name: "Box", ConversionFactor: 12, ConvertsToName: "Pcs"}, input.Units[1])
+
+	require.Len(t, input.Variants, 1)
+	variant := input.Variants[0]
+	assert.Empty(t, variant.ID)
+	assert.Empty(t, variant.SKU)
+	assert.Empty(t, variant.Barcode)
+	assert.Empty(t, variant.Images)
+	assert.Equal(t, &costPrice, variant.CostPrice)
+	require.NotNil(t, variant.ReorderPoint)
+	assert.Equal(t, 5, *variant.ReorderPoint)
+	assert.Equal(t, []CreateVariantAttributeInput{{AttributeName: "Size", AttributeValue: "250g"}}, variant.Attributes)
+	assert.Equal(t, []CreateVariantPricingTierInput{{MinQty: 1, Value: 10000}, {MinQty: 10, Value: 9000}}, variant.PricingTiers)
+	assert.Equal(t, []uint{6}, variant.RackIDs)
+
+	require.NoError(t, ValidateProductInput(input))
+}
+
+func TestCloneName_LongName_StaysWithinLimit(t *testing.T) {
+	name := cloneName(strings.Repeat("é", 200))
+
+	assert.LessOrEqual(t, len(name), maxProductNameLength)
+	assert.True(t, strings.HasSuffix(name, " (Copy)"))
+}
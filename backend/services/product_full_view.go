@@ -0,0 +1,77 @@
+package services
+
+import (
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+)
+
+// productStatsWindowDays is the look-back period of the sales stats in a product full view.
+const productStatsWindowDays = 30
+
+// VariantStock is the current stock position of a single variant.
+type VariantStock struct {
+	VariantID    string `json:"variantId"`
+	SKU          string `json:"sku,omitempty"`
+	Label        string `json:"label"`
+	CurrentStock int    `json:"currentStock"`
+	ReorderPoint int    `json:"reorderPoint"`
+}
+
+// ProductSalesSummary is a product's sales over the stats window.
+type ProductSalesSummary struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	repositories.ProductSalesStats
+}
+
+// ProductFullView bundles everything the product detail page needs.
+// Suppliers, units and variants are part of the embedded product.
+type ProductFullView struct {
+	*models.Product
+	Stock      []VariantStock      `json:"stock"`
+	TotalStock int                 `json:"totalStock"`
+	Sales      ProductSalesSummary `json:"sales"`
+}
+
+// FullView returns a product with its suppliers, per-variant stock and the
+// last 30 days of sales stats.
+func (s *ProductService) FullView(id uint) (*ProductFullView, *ServiceError) {
+	product, svcErr := s.GetProduct(id)
+	if svcErr != nil {
+		return nil, svcErr
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -productStatsWindowDays)
+	stats, err := s.repo.SalesStatsSince(product.ID, from)
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to fetch product sales stats",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	view := &ProductFullView{
+		Product: product,
+		Stock:   make([]VariantStock, 0, len(product.Variants)),
+		Sales: ProductSalesSummary{
+			From:              from,
+			To:                to,
+			ProductSalesStats: stats,
+		},
+	}
+	for _, variant := range product.Variants {
+		view.Stock = append(view.Stock, VariantStock{
+			VariantID:    variant.ID,
+			SKU:          variant.SKU,
+			Label:        buildVariantLabel(variant.Attributes),
+			CurrentStock: variant.CurrentStock,
+			ReorderPoint: variant.ReorderPoint,
+		})
+		view.TotalStock += variant.CurrentStock
+	}
+	return view, nil
+}
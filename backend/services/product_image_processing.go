@@ -10,6 +10,7 @@ import (
 
 type ImageStorage interface {
 	UploadImage(ctx context.Context, objectKey string, data []byte, contentType string) (string, error)
+	Delete(ctx context.Context, objectKey string) error
 }
 
 type decodedImagePayload struct {
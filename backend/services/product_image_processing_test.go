@@ -35,6 +35,10 @@ func (f *fakeImageStorage) UploadImage(_ context.Context, objectKey string, data
 	return f.returnedURL, nil
 }
 
+func (f *fakeImageStorage) Delete(_ context.Context, _ string) error {
+	return nil
+}
+
 func TestResolveImageURL_NonDataURL_ReturnsOriginal(t *testing.T) {
 	svc := &ProductService{}
 
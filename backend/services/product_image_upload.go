@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pointofsale/backend/models"
+	"gorm.io/gorm"
+)
+
+// DefaultProductImageMaxBytes is the per-image size limit used when none is configured.
+const DefaultProductImageMaxBytes int64 = 5 << 20
+
+// ProductImageLimits bounds the images a product may hold. A zero count
+// leaves that count unbounded; a zero MaxBytes uses DefaultProductImageMaxBytes.
+type ProductImageLimits struct {
+	// MaxProductImages caps the images attached to the product itself.
+	MaxProductImages int
+	// MaxVariantImages caps the images attached to each variant.
+	MaxVariantImages int
+	// MaxBytes caps the size of a single uploaded image, whether sent as a
+	// file or as a data URL.
+	MaxBytes int64
+}
+
+// SetImageLimits sets the image count and size limits enforced when images
+// are saved or uploaded.
+func (s *ProductService) SetImageLimits(limits ProductImageLimits) {
+	if limits.MaxBytes <= 0 {
+		limits.MaxBytes = DefaultProductImageMaxBytes
+	}
+	s.imageLimits = limits
+}
+
+// ImageMaxBytes returns the configured size limit for a single image.
+func (s *ProductService) ImageMaxBytes() int64 {
+	return s.imageLimits.MaxBytes
+}
+
+// allowedProductImageTypes maps accepted image content types to the stored file extension.
+var allowedProductImageTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// UploadImage stores an image file and appends it to the product's images.
+// The content type is sniffed from the data rather than trusted from the client.
+func (s *ProductService) UploadImage(productID uint, data []byte) (*models.ProductImage, *ServiceError) {
+	product, err := s.repo.GetByID(productID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &ServiceError{Err: ErrNotFound, Message: "Product not found", Code: "PRODUCT_NOT_FOUND"}
+		}
+		return nil, &ServiceError{Err: err, Message: "Failed to fetch product", Code: "INTERNAL_ERROR"}
+	}
+
+	if len(data) == 0 {
+		return nil, &ServiceError{Err: ErrValidation, Message: "File is empty", Code: "VALIDATION_ERROR"}
+	}
+	if int64(len(data)) > s.imageLimits.MaxBytes {
+		return nil, imageTooLargeError(s.imageLimits.MaxBytes)
+	}
+	if limit := s.imageLimits.MaxProductImages; limit > 0 && len(product.Images) >= limit {
+		return nil, tooManyImagesError("product", limit)
+	}
+
+	contentType := http.DetectContentType(data)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	ext, ok := allowedProductImageTypes[contentType]
+	if !ok {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Only JPEG, PNG and WebP images can be uploaded",
+			Code:    "IMAGE_TYPE_NOT_ALLOWED",
+		}
+	}
+
+	if s.imageStorage == nil {
+		return nil, &ServiceError{
+			Err:     fmt.Errorf("image storage is not configured"),
+			Message: "Image storage is not configured",
+			Code:    "STORAGE_NOT_CONFIGURED",
+		}
+	}
+
+	objectKey := appendExtension(fmt.Sprintf("products/%d/%s", productID, uuid.NewString()), ext)
+	imageURL, err := s.imageStorage.UploadImage(context.Background(), objectKey, data, contentType)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to upload image", Code: "INTERNAL_ERROR"}
+	}
+
+	// The count is checked again under the product lock, as a concurrent
+	// upload may have taken the last slot since the check above
+	image := &models.ProductImage{ProductID: productID, ImageURL: imageURL}
+	appended, err := s.repo.AppendImage(image, s.imageLimits.MaxProductImages)
+	if err != nil {
+		s.discardUpload(objectKey)
+		return nil, &ServiceError{Err: err, Message: "Failed to save product image", Code: "INTERNAL_ERROR"}
+	}
+	if !appended {
+		s.discardUpload(objectKey)
+		return nil, tooManyImagesError("product", s.imageLimits.MaxProductImages)
+	}
+	return image, nil
+}
+
+// discardUpload removes a stored image that was not attached to its product.
+// A failure is logged; the upload itself has already failed.
+func (s *ProductService) discardUpload(objectKey string) {
+	if err := s.imageStorage.Delete(context.Background(), objectKey); err != nil {
+		slog.Error("failed to delete orphaned product image", "object_key", objectKey, "error", err)
+	}
+}
+
+// validateImageLimits checks the images of a create or update request against
+// the configured count and size limits before anything is stored.
+func (s *ProductService) validateImageLimits(productImages []CreateProductImageInput, variants []CreateProductVariantInput) *ServiceError {
+	urls := make([]string, 0, len(productImages))
+	for _, image := range productImages {
+		urls = append(urls, image.ImageURL)
+	}
+	if err := s.checkImageSet(urls, s.imageLimits.MaxProductImages, "product"); err != nil {
+		return err
+	}
+
+	for _, variant := range variants {
+		urls = urls[:0]
+		for _, image := range variant.Images {
+			urls = append(urls, image.ImageURL)
+		}
+		if err := s.checkImageSet(urls, s.imageLimits.MaxVariantImages, "variant"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkImageSet enforces maxCount on the non-empty image URLs and the size
+// limit on any that are data URLs. Malformed data URLs are left for the
+// upload step to reject.
+func (s *ProductService) checkImageSet(urls []string, maxCount int, owner string) *ServiceError {
+	count := 0
+	for _, url := range urls {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		count++
+		payload, isDataURL, err := parseImageDataURL(url)
+		if err == nil && isDataURL && int64(len(payload.data)) > s.imageLimits.MaxBytes {
+			return imageTooLargeError(s.imageLimits.MaxBytes)
+		}
+	}
+	if maxCount > 0 && count > maxCount {
+		return tooManyImagesError(owner, maxCount)
+	}
+	return nil
+}
+
+func imageTooLargeError(maxBytes int64) *ServiceError {
+	return &ServiceError{
+		Err:     ErrTooLarge,
+		Message: fmt.Sprintf("Image exceeds the maximum size of %d bytes", maxBytes),
+		Code:    "IMAGE_TOO_LARGE",
+	}
+}
+
+func tooManyImagesError(owner string, maxCount int) *ServiceError {
+	return &ServiceError{
+		Err:     ErrValidation,
+		Message: fmt.Sprintf("A %s can have at most %d images", owner, maxCount),
+		Code:    "TOO_MANY_IMAGES",
+	}
+}
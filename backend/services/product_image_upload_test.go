@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func imageInputs(urls ...string) []CreateProductImageInput {
+	images := make([]CreateProductImageInput, len(urls))
+	for i, url := range urls {
+		images[i] = CreateProductImageInput{ImageURL: url}
+	}
+	return images
+}
+
+func variantWithImages(urls ...string) CreateProductVariantInput {
+	images := make([]CreateVariantImageInput, len(urls))
+	for i, url := range urls {
+		images[i] = CreateVariantImageInput{ImageURL: url}
+	}
+	return CreateProductVariantInput{Images: images}
+}
+
+func TestValidateImageLimits_WithinLimits_Accepted(t *testing.T) {
+	svc := NewProductService(nil)
+	svc.SetImageLimits(ProductImageLimits{MaxProductImages: 2, MaxVariantImages: 1})
+
+	err := svc.validateImageLimits(
+		imageInputs("https://cdn.example.com/a.png", tinyPNGDataURL),
+		[]CreateProductVariantInput{variantWithImages("https://cdn.example.com/v.png")},
+	)
+	assert.Nil(t, err)
+}
+
+func TestValidateImageLimits_TooManyProductImages_Rejected(t *testing.T) {
+	svc := NewProductService(nil)
+	svc.SetImageLimits(ProductImageLimits{MaxProductImages: 2})
+
+	err := svc.validateImageLimits(imageInputs("a.png", "b.png", "c.png"), nil)
+	require.NotNil(t, err)
+	assert.Equal(t, ErrValidation, err.Err)
+	assert.Equal(t, "TOO_MANY_IMAGES", err.Code)
+	assert.Contains(t, err.Message, "product can have at most 2 images")
+}
+
+func TestValidateImageLimits_TooManyVariantImages_Rejected(t *testing.T) {
+	svc := NewProductService(nil)
+	svc.SetImageLimits(ProductImageLimits{MaxVariantImages: 1})
+
+	err := svc.validateImageLimits(nil, []CreateProductVariantInput{
+		variantWithImages("a.png"),
+		variantWithImages("b.png", "c.png"),
+	})
+	require.NotNil(t, err)
+	assert.Equal(t, "TOO_MANY_IMAGES", err.Code)
+	assert.Contains(t, err.Message, "variant can have at most 1 images")
+}
+
+func TestValidateImageLimits_BlankURLsNotCounted(t *testing.T) {
+	svc := NewProductService(nil)
+	svc.SetImageLimits(ProductImageLimits{MaxProductImages: 1})
+
+	err := svc.validateImageLimits(imageInputs("a.png", "", "   "), nil)
+	assert.Nil(t, err)
+}
+
+func TestValidateImageLimits_ZeroCountIsUnbounded(t *testing.T) {
+	svc := NewProductService(nil)
+
+	urls := make([]string, 50)
+	for i := range urls {
+		urls[i] = "https://cdn.example.com/image.png"
+	}
+	err := svc.validateImageLimits(imageInputs(urls...), nil)
+	assert.Nil(t, err)
+}
+
+func TestValidateImageLimits_OversizeDataURL_Rejected(t *testing.T) {
+	svc := NewProductService(nil)
+	svc.SetImageLimits(ProductImageLimits{MaxBytes: 16})
+
+	err := svc.validateImageLimits(imageInputs(tinyPNGDataURL), nil)
+	require.NotNil(t, err)
+	assert.Equal(t, ErrTooLarge, err.Err)
+	assert.Equal(t, "IMAGE_TOO_LARGE", err.Code)
+}
+
+// countingImageStorage is an ImageStorage safe for concurrent uploads.
+type countingImageStorage struct {
+	mu      sync.Mutex
+	uploads int
+	deletes int
+}
+
+func (c *countingImageStorage) UploadImage(_ context.Context, objectKey string, _ []byte, _ string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.uploads++
+	return fmt.Sprintf("http://files.local/%s", objectKey), nil
+}
+
+func (c *countingImageStorage) Delete(_ context.Context, _ string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deletes++
+	return nil
+}
+
+func TestUploadImage_ConcurrentUploadsForLastSlot_OnlyOneSaved(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	product := testutil.CreateTestProduct(t, db)
+	require.NoError(t, db.Create(&models.ProductImage{ProductID: product.ID, ImageURL: "http://files.local/first.png"}).Error)
+
+	storage := &countingImageStorage{}
+	svc := NewProductService(repositories.NewProductRepository(db), storage)
+	svc.SetImageLimits(ProductImageLimits{MaxProductImages: 2})
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+	const uploads = 5
+	errs := make([]*ServiceError, uploads)
+	var wg sync.WaitGroup
+	for i := 0; i < uploads; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = svc.UploadImage(product.ID, png)
+		}(i)
+	}
+	wg.Wait()
+
+	saved := 0
+	for _, err := range errs {
+		if err == nil {
+			saved++
+			continue
+		}
+		assert.Equal(t, "TOO_MANY_IMAGES", err.Code)
+	}
+	assert.Equal(t, 1, saved)
+	// Uploads that lost the race are removed from storage
+	assert.Equal(t, storage.uploads-1, storage.deletes)
+
+	var count int64
+	require.NoError(t, db.Model(&models.ProductImage{}).Where("product_id = ?", product.ID).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
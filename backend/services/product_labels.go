@@ -0,0 +1,288 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/boombuler/barcode/code128"
+	"github.com/go-pdf/fpdf"
+	"github.com/google/uuid"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/utils"
+)
+
+const (
+	defaultLabelLayout = "3x8"
+	maxLabelColumns    = 5
+	maxLabelRows       = 12
+	maxLabelBatch      = 500
+
+	labelPageMargin = 10.0
+	labelGap        = 2.0
+	labelPadding    = 2.0
+	// labelReferenceHeight is the cell height (mm) the font sizes below are tuned for.
+	labelReferenceHeight = 33.0
+)
+
+// LabelLayout is a grid of labels per A4 page.
+type LabelLayout struct {
+	Columns int
+	Rows    int
+}
+
+// PerPage returns the number of labels that fit on one page.
+func (l LabelLayout) PerPage() int {
+	return l.Columns * l.Rows
+}
+
+// ParseLabelLayout parses a "COLUMNSxROWS" layout such as "3x8".
+// An empty layout uses the default grid.
+func ParseLabelLayout(layout string) (LabelLayout, error) {
+	layout = strings.ToLower(strings.TrimSpace(layout))
+	if layout == "" {
+		layout = defaultLabelLayout
+	}
+
+	colsStr, rowsStr, ok := strings.Cut(layout, "x")
+	if !ok {
+		return LabelLayout{}, fmt.Errorf("layout must be in COLUMNSxROWS format, e.g. %s", defaultLabelLayout)
+	}
+	cols, err := strconv.Atoi(colsStr)
+	if err != nil || cols < 1 || cols > maxLabelColumns {
+		return LabelLayout{}, fmt.Errorf("layout columns must be between 1 and %d", maxLabelColumns)
+	}
+	rows, err := strconv.Atoi(rowsStr)
+	if err != nil || rows < 1 || rows > maxLabelRows {
+		return LabelLayout{}, fmt.Errorf("layout rows must be between 1 and %d", maxLabelRows)
+	}
+	return LabelLayout{Columns: cols, Rows: rows}, nil
+}
+
+// shelfLabel is the printable content of a single label.
+type shelfLabel struct {
+	Name         string
+	VariantLabel string
+	Price        string
+	Code         string
+}
+
+// SetDocumentFormat sets the currency and date formatting used in generated documents.
+func (s *ProductService) SetDocumentFormat(format utils.DocumentFormat) {
+	s.docFormat = format
+}
+
+// GenerateLabels renders shelf labels for the given variants as a multi-up PDF.
+// A variant ID listed more than once prints more than one label.
+func (s *ProductService) GenerateLabels(variantIDs []string, layout string) ([]byte, *ServiceError) {
+	if len(variantIDs) == 0 {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "At least one variant is required",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if len(variantIDs) > maxLabelBatch {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: fmt.Sprintf("At most %d labels can be printed at once", maxLabelBatch),
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	ids := make([]string, 0, len(variantIDs))
+	for i, variantID := range variantIDs {
+		variantID = strings.TrimSpace(variantID)
+		if _, err := uuid.Parse(variantID); err != nil {
+			return nil, &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("variantIds[%d]: invalid variant ID", i),
+				Code:    "VALIDATION_ERROR",
+			}
+		}
+		ids = append(ids, variantID)
+	}
+
+	grid, err := ParseLabelLayout(layout)
+	if err != nil {
+		return nil, &ServiceError{Err: ErrValidation, Message: err.Error(), Code: "VALIDATION_ERROR"}
+	}
+
+	variants, err := s.repo.ListVariantsForLabels(ids)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to load variants", Code: "INTERNAL_ERROR"}
+	}
+	if len(variants) != len(ids) {
+		found := make(map[string]bool, len(variants))
+		for _, variant := range variants {
+			found[variant.ID] = true
+		}
+		for _, id := range ids {
+			if !found[id] {
+				return nil, &ServiceError{
+					Err:     ErrNotFound,
+					Message: fmt.Sprintf("Variant %s not found", id),
+					Code:    "VARIANT_NOT_FOUND",
+				}
+			}
+		}
+	}
+
+	formatter := utils.NewDocumentFormatter(s.docFormat)
+	labels := make([]shelfLabel, 0, len(variants))
+	for _, variant := range variants {
+		labels = append(labels, buildShelfLabel(variant, formatter))
+	}
+
+	pdfBytes, err := renderLabelsPDF(labels, grid)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to generate labels", Code: "INTERNAL_ERROR"}
+	}
+	return pdfBytes, nil
+}
+
+// buildShelfLabel returns the label content of a variant. The price is the
+// selling price of its lowest tier, or "-" when the variant has none yet.
+func buildShelfLabel(variant repositories.LabelVariant, formatter *utils.DocumentFormatter) shelfLabel {
+	price := "-"
+	if tiers := SellingTiers(variant.PriceSetting, variant.PricingTiers); len(tiers) > 0 {
+		price = formatter.FormatMoney(tiers[0].Value)
+	}
+	variantLabel := buildVariantLabel(variant.Attributes)
+	if variantLabel == "Default" {
+		variantLabel = ""
+	}
+	code := variant.Barcode
+	if code == "" {
+		code = variant.SKU
+	}
+	return shelfLabel{
+		Name:         variant.ProductName,
+		VariantLabel: variantLabel,
+		Price:        price,
+		Code:         code,
+	}
+}
+
+// renderLabelsPDF lays labels out left-to-right, top-to-bottom on A4 pages.
+func renderLabelsPDF(labels []shelfLabel, grid LabelLayout) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(labelPageMargin, labelPageMargin, labelPageMargin)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetDrawColor(200, 200, 200)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pageW, pageH := pdf.GetPageSize()
+	cellW := (pageW - 2*labelPageMargin - float64(grid.Columns-1)*labelGap) / float64(grid.Columns)
+	cellH := (pageH - 2*labelPageMargin - float64(grid.Rows-1)*labelGap) / float64(grid.Rows)
+	scale := cellH / labelReferenceHeight
+	if scale > 1 {
+		scale = 1
+	}
+
+	for i, label := range labels {
+		slot := i % grid.PerPage()
+		if slot == 0 {
+			pdf.AddPage()
+		}
+		x := labelPageMargin + float64(slot%grid.Columns)*(cellW+labelGap)
+		y := labelPageMargin + float64(slot/grid.Columns)*(cellH+labelGap)
+		drawShelfLabel(pdf, tr, label, x, y, cellW, cellH, scale)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func drawShelfLabel(pdf *fpdf.Fpdf, tr func(string) string, label shelfLabel, x, y, w, h, scale float64) {
+	pdf.Rect(x, y, w, h, "D")
+
+	innerW := w - 2*labelPadding
+	cursor := y + labelPadding
+	line := func(style string, size float64, text string) {
+		pdf.SetFont("Helvetica", style, size*scale)
+		lineH := size * scale * 0.45
+		pdf.SetXY(x+labelPadding, cursor)
+		pdf.CellFormat(innerW, lineH, fitText(pdf, tr, text, innerW), "", 0, "L", false, 0, "")
+		cursor += lineH
+	}
+
+	line("B", 9, label.Name)
+	if label.VariantLabel != "" {
+		line("", 7, label.VariantLabel)
+	}
+	line("B", 12, label.Price)
+
+	if label.Code == "" {
+		return
+	}
+	textH := 6 * scale * 0.45
+	barTop := cursor + labelPadding/2
+	barH := y + h - labelPadding - textH - barTop
+	if barH <= 0 {
+		return
+	}
+	if drawCode128(pdf, label.Code, x+labelPadding, barTop, innerW, barH) {
+		pdf.SetFont("Helvetica", "", 6*scale)
+		pdf.SetXY(x+labelPadding, barTop+barH)
+		pdf.CellFormat(innerW, textH, tr(label.Code), "", 0, "C", false, 0, "")
+	}
+}
+
+// drawCode128 draws code as Code 128 bars filling the given box.
+// It reports false when the code cannot be encoded.
+func drawCode128(pdf *fpdf.Fpdf, code string, x, y, w, h float64) bool {
+	bc, err := code128.Encode(code)
+	if err != nil {
+		return false
+	}
+	modules := bc.Bounds().Dx()
+	if modules == 0 {
+		return false
+	}
+	moduleW := w / float64(modules)
+
+	pdf.SetFillColor(0, 0, 0)
+	runStart := -1
+	for m := 0; m <= modules; m++ {
+		dark := m < modules && isDark(bc.At(m, 0))
+		if dark && runStart < 0 {
+			runStart = m
+		}
+		if !dark && runStart >= 0 {
+			pdf.Rect(x+float64(runStart)*moduleW, y, float64(m-runStart)*moduleW, h, "F")
+			runStart = -1
+		}
+	}
+	return true
+}
+
+func isDark(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r+g+b < 3*0x8000
+}
+
+// fitText translates text for the core fonts and truncates it with an
+// ellipsis so it fits within width at the current font.
+func fitText(pdf *fpdf.Fpdf, tr func(string) string, text string, width float64) string {
+	if translated := tr(text); pdf.GetStringWidth(translated) <= width {
+		return translated
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := tr(string(runes) + "...")
+		if pdf.GetStringWidth(candidate) <= width {
+			return candidate
+		}
+	}
+	return ""
+}
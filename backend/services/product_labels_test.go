@@ -0,0 +1,86 @@
+package services
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pdfPagePattern = regexp.MustCompile(`/Type /Page\b[^s]`)
+
+func countPDFPages(data []byte) int {
+	return len(pdfPagePattern.FindAll(data, -1))
+}
+
+func TestParseLabelLayout(t *testing.T) {
+	tests := []struct {
+		layout  string
+		want    LabelLayout
+		wantErr bool
+	}{
+		{"", LabelLayout{Columns: 3, Rows: 8}, false},
+		{"2x5", LabelLayout{Columns: 2, Rows: 5}, false},
+		{" 4X10 ", LabelLayout{Columns: 4, Rows: 10}, false},
+		{"3", LabelLayout{}, true},
+		{"0x5", LabelLayout{}, true},
+		{"3x13", LabelLayout{}, true},
+		{"ax5", LabelLayout{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLabelLayout(tt.layout)
+		if tt.wantErr {
+			assert.Error(t, err, tt.layout)
+			continue
+		}
+		require.NoError(t, err, tt.layout)
+		assert.Equal(t, tt.want, got, tt.layout)
+	}
+}
+
+func TestRenderLabelsPDF_PageCountFollowsLayout(t *testing.T) {
+	labels := []shelfLabel{
+		{Name: "Indomie Goreng", Price: "Rp 3.500", Code: "8998866200301"},
+		{Name: "Kopi Kapal Api", VariantLabel: "Sachet", Price: "Rp 1.500", Code: "SKU-KOPI-01"},
+		{Name: "Gula Pasir Premium Kualitas Terbaik Sekali Sangat Panjang", Price: "-"},
+	}
+
+	data, err := renderLabelsPDF(labels, LabelLayout{Columns: 1, Rows: 2})
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+	assert.Equal(t, 2, countPDFPages(data))
+
+	data, err = renderLabelsPDF(labels, LabelLayout{Columns: 3, Rows: 8})
+	require.NoError(t, err)
+	assert.Equal(t, 1, countPDFPages(data))
+}
+
+func TestBuildShelfLabel_PricesFromSellingTiers(t *testing.T) {
+	formatter := utils.NewDocumentFormatter(utils.DefaultDocumentFormat())
+	sellingPrice := 11000.0
+
+	fixed := repositories.LabelVariant{
+		ProductVariant: models.ProductVariant{PricingTiers: []models.VariantPricingTier{{MinQty: 1, Value: 10000}}},
+		PriceSetting:   "fixed",
+	}
+	assert.Equal(t, formatter.FormatMoney(10000), buildShelfLabel(fixed, formatter).Price)
+
+	// A 10% markup sells at its derived price, never at the markup itself
+	markup := repositories.LabelVariant{
+		ProductVariant: models.ProductVariant{PricingTiers: []models.VariantPricingTier{{MinQty: 1, Value: 10, SellingPrice: &sellingPrice}}},
+		PriceSetting:   PriceSettingMarkup,
+	}
+	assert.Equal(t, formatter.FormatMoney(11000), buildShelfLabel(markup, formatter).Price)
+
+	unpriced := repositories.LabelVariant{
+		ProductVariant: models.ProductVariant{PricingTiers: []models.VariantPricingTier{{MinQty: 1, Value: 10}}},
+		PriceSetting:   PriceSettingMarkup,
+	}
+	assert.Equal(t, "-", buildShelfLabel(unpriced, formatter).Price)
+}
@@ -0,0 +1,110 @@
+package services
+
+import "time"
+
+// VariantOverview is one variant's stock against its recent sales.
+// DaysOfCover is how many days the current stock lasts at the daily velocity,
+// nil when the variant has not sold in the window. Reorder is set when the
+// stock is at or below the reorder point or would run out within the lead
+// time of a new order.
+type VariantOverview struct {
+	VariantID     string   `json:"variantId"`
+	SKU           string   `json:"sku,omitempty"`
+	Label         string   `json:"label"`
+	CurrentStock  int      `json:"currentStock"`
+	ReorderPoint  int      `json:"reorderPoint"`
+	QuantitySold  int64    `json:"quantitySold"`
+	DailyVelocity float64  `json:"dailyVelocity"`
+	DaysOfCover   *float64 `json:"daysOfCover"`
+	Reorder       bool     `json:"reorder"`
+}
+
+// ProductFamilyOverview lists every variant of a product with its stock and
+// sales velocity over the last WindowDays days. Stock and quantities are in
+// the product's base unit.
+type ProductFamilyOverview struct {
+	ProductID         uint              `json:"productId"`
+	ProductName       string            `json:"productName"`
+	From              time.Time         `json:"from"`
+	To                time.Time         `json:"to"`
+	WindowDays        int               `json:"windowDays"`
+	LeadTimeDays      float64           `json:"leadTimeDays"`
+	TotalStock        int               `json:"totalStock"`
+	TotalQuantitySold int64             `json:"totalQuantitySold"`
+	Variants          []VariantOverview `json:"variants"`
+}
+
+// FamilyOverview returns each variant of a product with its current stock,
+// units sold over the last 30 days, days of cover and reorder flag. The lead
+// time is that of ReorderDetail: the average of recently received orders from
+// the product's suppliers, or 7 days without any.
+func (s *ProductService) FamilyOverview(productID uint) (*ProductFamilyOverview, *ServiceError) {
+	product, svcErr := s.GetProduct(productID)
+	if svcErr != nil {
+		return nil, svcErr
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -reorderVelocityWindowDays)
+	sold, err := s.repo.ListVariantQuantitiesSoldSince(product.ID, from)
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to fetch product sales",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	soldByVariant := make(map[string]int64, len(sold))
+	for _, row := range sold {
+		soldByVariant[row.VariantID] = row.QuantitySold
+	}
+
+	orders, err := s.repo.ListReceivedSupplierOrders(product.ID, reorderLeadTimeSampleSize)
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to fetch supplier purchase orders",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	leadTimeDays := float64(defaultReorderLeadTimeDays)
+	if avg, _ := averageLeadTime(orders); avg != nil {
+		leadTimeDays = *avg
+	}
+
+	overview := &ProductFamilyOverview{
+		ProductID:    product.ID,
+		ProductName:  product.Name,
+		From:         from,
+		To:           to,
+		WindowDays:   reorderVelocityWindowDays,
+		LeadTimeDays: leadTimeDays,
+		Variants:     make([]VariantOverview, 0, len(product.Variants)),
+	}
+	for _, variant := range product.Variants {
+		quantitySold := soldByVariant[variant.ID]
+		velocity := float64(quantitySold) / reorderVelocityWindowDays
+		item := VariantOverview{
+			VariantID:     variant.ID,
+			SKU:           variant.SKU,
+			Label:         buildVariantLabel(variant.Attributes),
+			CurrentStock:  variant.CurrentStock,
+			ReorderPoint:  variant.ReorderPoint,
+			QuantitySold:  quantitySold,
+			DailyVelocity: roundTo(velocity, 2),
+			Reorder:       variant.ReorderPoint > 0 && variant.CurrentStock <= variant.ReorderPoint,
+		}
+		if velocity > 0 {
+			cover := float64(variant.CurrentStock) / velocity
+			if cover < leadTimeDays {
+				item.Reorder = true
+			}
+			cover = roundTo(cover, 2)
+			item.DaysOfCover = &cover
+		}
+		overview.Variants = append(overview.Variants, item)
+		overview.TotalStock += variant.CurrentStock
+		overview.TotalQuantitySold += quantitySold
+	}
+	return overview, nil
+}
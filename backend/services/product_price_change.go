@@ -0,0 +1,234 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pointofsale/backend/repositories"
+)
+
+// Ways a price change can be expressed
+const (
+	PriceChangePercentage = "percentage"
+	PriceChangeAmount     = "amount"
+)
+
+// PriceChangePreviewInput selects the variants a price change applies to and
+// how every pricing tier changes. Variants of the listed products are
+// included along with the listed variants. A percentage value of 10 raises
+// prices by 10%; an amount value is added to each tier price.
+type PriceChangePreviewInput struct {
+	ProductIDs []uint   `json:"productIds"`
+	VariantIDs []string `json:"variantIds"`
+	ChangeType string   `json:"changeType"`
+	Value      float64  `json:"value"`
+}
+
+// PriceChangeTierPreview compares one pricing tier's current and proposed
+// price. Margins are nil when the variant has no cost price.
+type PriceChangeTierPreview struct {
+	VariantID             string   `json:"variantId"`
+	ProductID             uint     `json:"productId"`
+	ProductName           string   `json:"productName"`
+	VariantLabel          string   `json:"variantLabel"`
+	SKU                   string   `json:"sku,omitempty"`
+	MinQty                int      `json:"minQty"`
+	CostPrice             *float64 `json:"costPrice"`
+	CurrentPrice          float64  `json:"currentPrice"`
+	ProposedPrice         float64  `json:"proposedPrice"`
+	CurrentMargin         *float64 `json:"currentMargin"`
+	ProposedMargin        *float64 `json:"proposedMargin"`
+	CurrentMarginPercent  *float64 `json:"currentMarginPercent"`
+	ProposedMarginPercent *float64 `json:"proposedMarginPercent"`
+}
+
+// PriceChangePreview summarises a price change without applying it. Average
+// margins only cover tiers whose variant has a cost price.
+type PriceChangePreview struct {
+	VariantCount                 int                      `json:"variantCount"`
+	TierCount                    int                      `json:"tierCount"`
+	TiersWithCost                int                      `json:"tiersWithCost"`
+	TiersBelowCost               int                      `json:"tiersBelowCost"`
+	TotalCurrentPrice            float64                  `json:"totalCurrentPrice"`
+	TotalProposedPrice           float64                  `json:"totalProposedPrice"`
+	AverageCurrentMarginPercent  *float64                 `json:"averageCurrentMarginPercent"`
+	AverageProposedMarginPercent *float64                 `json:"averageProposedMarginPercent"`
+	Tiers                        []PriceChangeTierPreview `json:"tiers"`
+}
+
+// PreviewPriceChange projects the prices and margins that would result from
+// changing every pricing tier of the selected variants. Nothing is saved.
+func (s *ProductService) PreviewPriceChange(input PriceChangePreviewInput) (*PriceChangePreview, *ServiceError) {
+	changeType := strings.ToLower(strings.TrimSpace(input.ChangeType))
+	switch changeType {
+	case PriceChangePercentage:
+		if input.Value <= -100 {
+			return nil, &ServiceError{
+				Err:     ErrValidation,
+				Message: "Percentage change must be greater than -100",
+				Code:    "VALIDATION_ERROR",
+			}
+		}
+	case PriceChangeAmount:
+	default:
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "changeType must be 'percentage' or 'amount'",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if len(input.ProductIDs) == 0 && len(input.VariantIDs) == 0 {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "At least one product or variant is required",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	ids := make([]string, 0, len(input.VariantIDs))
+	for i, variantID := range input.VariantIDs {
+		variantID = strings.TrimSpace(variantID)
+		if _, err := uuid.Parse(variantID); err != nil {
+			return nil, &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("variantIds[%d]: invalid variant ID", i),
+				Code:    "VALIDATION_ERROR",
+			}
+		}
+		ids = append(ids, variantID)
+	}
+	if len(input.ProductIDs) > 0 {
+		productVariantIDs, err := s.repo.ListVariantIDsForProducts(input.ProductIDs)
+		if err != nil {
+			return nil, &ServiceError{Err: err, Message: "Failed to load variants", Code: "INTERNAL_ERROR"}
+		}
+		ids = append(ids, productVariantIDs...)
+	}
+
+	// A variant picked directly and through its product is previewed once
+	unique := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, &ServiceError{
+			Err:     ErrNotFound,
+			Message: "No variants found for the selected products",
+			Code:    "VARIANT_NOT_FOUND",
+		}
+	}
+
+	variants, err := s.repo.ListVariantsForLabels(unique)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to load variants", Code: "INTERNAL_ERROR"}
+	}
+	if len(variants) != len(unique) {
+		found := make(map[string]bool, len(variants))
+		for _, variant := range variants {
+			found[variant.ID] = true
+		}
+		for _, id := range unique {
+			if !found[id] {
+				return nil, &ServiceError{
+					Err:     ErrNotFound,
+					Message: fmt.Sprintf("Variant %s not found", id),
+					Code:    "VARIANT_NOT_FOUND",
+				}
+			}
+		}
+	}
+
+	preview, err := projectPriceChange(variants, changeType, input.Value)
+	if err != nil {
+		return nil, &ServiceError{Err: ErrValidation, Message: err.Error(), Code: "VALIDATION_ERROR"}
+	}
+	return preview, nil
+}
+
+// projectPriceChange applies the change to the selling price of each pricing
+// tier and computes margins against the variant's cost price. A margin
+// percentage is the share of the selling price left after cost. Markup tiers
+// sell at their derived price, so tiers not yet priced are left out.
+func projectPriceChange(variants []repositories.LabelVariant, changeType string, value float64) (*PriceChangePreview, error) {
+	preview := &PriceChangePreview{
+		VariantCount: len(variants),
+		Tiers:        []PriceChangeTierPreview{},
+	}
+	var currentMarginSum, proposedMarginSum float64
+	var marginCount int
+
+	for _, variant := range variants {
+		label := buildVariantLabel(variant.Attributes)
+		for _, tier := range SellingTiers(variant.PriceSetting, variant.PricingTiers) {
+			proposed := tier.Value + value
+			if changeType == PriceChangePercentage {
+				proposed = tier.Value * (1 + value/100)
+			}
+			proposed = roundTo(proposed, 2)
+			if proposed < 0 {
+				return nil, fmt.Errorf("proposed price for %s (min qty %d) would be negative", variant.ProductName, tier.MinQty)
+			}
+
+			row := PriceChangeTierPreview{
+				VariantID:     variant.ID,
+				ProductID:     variant.ProductID,
+				ProductName:   variant.ProductName,
+				VariantLabel:  label,
+				SKU:           variant.SKU,
+				MinQty:        tier.MinQty,
+				CostPrice:     variant.CostPrice,
+				CurrentPrice:  tier.Value,
+				ProposedPrice: proposed,
+			}
+			preview.TotalCurrentPrice += tier.Value
+			preview.TotalProposedPrice += proposed
+
+			if variant.CostPrice != nil {
+				cost := *variant.CostPrice
+				currentMargin := roundTo(tier.Value-cost, 2)
+				proposedMargin := roundTo(proposed-cost, 2)
+				row.CurrentMargin = &currentMargin
+				row.ProposedMargin = &proposedMargin
+				row.CurrentMarginPercent = marginPercent(tier.Value, cost)
+				row.ProposedMarginPercent = marginPercent(proposed, cost)
+
+				preview.TiersWithCost++
+				if proposed < cost {
+					preview.TiersBelowCost++
+				}
+				if row.CurrentMarginPercent != nil && row.ProposedMarginPercent != nil {
+					currentMarginSum += *row.CurrentMarginPercent
+					proposedMarginSum += *row.ProposedMarginPercent
+					marginCount++
+				}
+			}
+			preview.Tiers = append(preview.Tiers, row)
+		}
+	}
+
+	preview.TierCount = len(preview.Tiers)
+	preview.TotalCurrentPrice = roundTo(preview.TotalCurrentPrice, 2)
+	preview.TotalProposedPrice = roundTo(preview.TotalProposedPrice, 2)
+	if marginCount > 0 {
+		currentAvg := roundTo(currentMarginSum/float64(marginCount), 2)
+		proposedAvg := roundTo(proposedMarginSum/float64(marginCount), 2)
+		preview.AverageCurrentMarginPercent = &currentAvg
+		preview.AverageProposedMarginPercent = &proposedAvg
+	}
+	return preview, nil
+}
+
+// marginPercent returns (price - cost) / price as a percentage, or nil for a
+// zero price.
+func marginPercent(price, cost float64) *float64 {
+	if price == 0 {
+		return nil
+	}
+	percent := roundTo((price-cost)/price*100, 2)
+	return &percent
+}
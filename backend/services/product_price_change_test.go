@@ -0,0 +1,129 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func priceChangeVariant(id string, cost *float64, tiers ...models.VariantPricingTier) repositories.LabelVariant {
+	return repositories.LabelVariant{
+		ProductVariant: models.ProductVariant{ID: id, ProductID: 1, CostPrice: cost, PricingTiers: tiers},
+		ProductName:    "Coffee",
+	}
+}
+
+func TestProjectPriceChange_PercentageIncrease_ProjectsMargins(t *testing.T) {
+	variants := []repositories.LabelVariant{
+		priceChangeVariant("v1", floatPtr(6000),
+			models.VariantPricingTier{MinQty: 1, Value: 10000},
+			models.VariantPricingTier{MinQty: 12, Value: 8000},
+		),
+	}
+
+	preview, err := projectPriceChange(variants, PriceChangePercentage, 10)
+
+	require.NoError(t, err)
+	require.Len(t, preview.Tiers, 2)
+
+	retail := preview.Tiers[0]
+	assert.Equal(t, 10000.0, retail.CurrentPrice)
+	assert.Equal(t, 11000.0, retail.ProposedPrice)
+	assert.Equal(t, 4000.0, *retail.CurrentMargin)
+	assert.Equal(t, 5000.0, *retail.ProposedMargin)
+	assert.Equal(t, 40.0, *retail.CurrentMarginPercent)
+	assert.Equal(t, 45.45, *retail.ProposedMarginPercent)
+
+	bulk := preview.Tiers[1]
+	assert.Equal(t, 8800.0, bulk.ProposedPrice)
+	assert.Equal(t, 2800.0, *bulk.ProposedMargin)
+	assert.Equal(t, 25.0, *bulk.CurrentMarginPercent)
+	assert.Equal(t, 31.82, *bulk.ProposedMarginPercent)
+
+	assert.Equal(t, 1, preview.VariantCount)
+	assert.Equal(t, 2, preview.TierCount)
+	assert.Equal(t, 18000.0, preview.TotalCurrentPrice)
+	assert.Equal(t, 19800.0, preview.TotalProposedPrice)
+	assert.Equal(t, 32.5, *preview.AverageCurrentMarginPercent)
+	assert.Equal(t, 38.64, *preview.AverageProposedMarginPercent)
+	assert.Equal(t, 0, preview.TiersBelowCost)
+}
+
+func TestProjectPriceChange_AmountDecreaseBelowCost_IsCounted(t *testing.T) {
+	variants := []repositories.LabelVariant{
+		priceChangeVariant("v1", floatPtr(9500), models.VariantPricingTier{MinQty: 1, Value: 10000}),
+	}
+
+	preview, err := projectPriceChange(variants, PriceChangeAmount, -1000)
+
+	require.NoError(t, err)
+	assert.Equal(t, 9000.0, preview.Tiers[0].ProposedPrice)
+	assert.Equal(t, -500.0, *preview.Tiers[0].ProposedMargin)
+	assert.Equal(t, 1, preview.TiersBelowCost)
+}
+
+func TestProjectPriceChange_NoCostPrice_LeavesMarginsEmpty(t *testing.T) {
+	variants := []repositories.LabelVariant{
+		priceChangeVariant("v1", nil, models.VariantPricingTier{MinQty: 1, Value: 10000}),
+	}
+
+	preview, err := projectPriceChange(variants, PriceChangePercentage, 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, 10500.0, preview.Tiers[0].ProposedPrice)
+	assert.Nil(t, preview.Tiers[0].CurrentMargin)
+	assert.Nil(t, preview.Tiers[0].ProposedMarginPercent)
+	assert.Equal(t, 0, preview.TiersWithCost)
+	assert.Nil(t, preview.AverageProposedMarginPercent)
+}
+
+func TestProjectPriceChange_NegativeProposedPrice_ReturnsError(t *testing.T) {
+	variants := []repositories.LabelVariant{
+		priceChangeVariant("v1", nil, models.VariantPricingTier{MinQty: 1, Value: 500}),
+	}
+
+	_, err := projectPriceChange(variants, PriceChangeAmount, -1000)
+
+	assert.Error(t, err)
+}
+
+func TestPreviewPriceChange_InvalidInput_ReturnsValidationError(t *testing.T) {
+	service := &ProductService{}
+	tests := []PriceChangePreviewInput{
+		{VariantIDs: []string{"5f0c2a52-4c43-4bd6-9f5e-0f0f4b7c2a11"}, ChangeType: "double", Value: 2},
+		{VariantIDs: []string{"5f0c2a52-4c43-4bd6-9f5e-0f0f4b7c2a11"}, ChangeType: "percentage", Value: -100},
+		{ChangeType: "amount", Value: 1000},
+		{VariantIDs: []string{"not-a-uuid"}, ChangeType: "amount", Value: 1000},
+	}
+
+	for _, input := range tests {
+		preview, err := service.PreviewPriceChange(input)
+		assert.Nil(t, preview)
+		require.NotNil(t, err)
+		assert.Equal(t, ErrValidation, err.Err)
+	}
+}
+
+func TestProjectPriceChange_MarkupVariant_ProjectsSellingPrice(t *testing.T) {
+	variant := priceChangeVariant("v1", floatPtr(6000),
+		models.VariantPricingTier{MinQty: 1, Value: 50, SellingPrice: floatPtr(9000)},
+		models.VariantPricingTier{MinQty: 12, Value: 40},
+	)
+	variant.PriceSetting = PriceSettingMarkup
+
+	preview, err := projectPriceChange([]repositories.LabelVariant{variant}, PriceChangePercentage, 10)
+
+	require.NoError(t, err)
+	require.Len(t, preview.Tiers, 1)
+	assert.Equal(t, 9000.0, preview.Tiers[0].CurrentPrice)
+	assert.Equal(t, 9900.0, preview.Tiers[0].ProposedPrice)
+	assert.Equal(t, 3000.0, *preview.Tiers[0].CurrentMargin)
+	assert.Equal(t, 1, preview.TierCount)
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	// reorderVelocityWindowDays is the look-back period of the sales velocity.
+	reorderVelocityWindowDays = 30
+	// reorderCoverDays is how long a reorder should last once it arrives.
+	reorderCoverDays = 14
+	// defaultReorderLeadTimeDays is assumed when no supplier order has been received yet.
+	defaultReorderLeadTimeDays = 7
+	// reorderLeadTimeSampleSize caps how many received orders feed the lead time.
+	reorderLeadTimeSampleSize = 20
+)
+
+// ReorderDetail explains the reorder recommendation for a single variant.
+// The suggested quantity, in the product's base unit, restores the reorder
+// point after covering the expected sales during the lead time and the cover
+// period that follows.
+type ReorderDetail struct {
+	VariantID         string   `json:"variantId"`
+	ProductID         uint     `json:"productId"`
+	CurrentStock      int      `json:"currentStock"`
+	ReorderPoint      int      `json:"reorderPoint"`
+	BelowReorderPoint bool     `json:"belowReorderPoint"`
+	WindowDays        int      `json:"windowDays"`
+	QuantitySold      int64    `json:"quantitySold"`
+	DailyVelocity     float64  `json:"dailyVelocity"`
+	AvgLeadTimeDays   *float64 `json:"avgLeadTimeDays"`
+	LeadTimeOrders    int      `json:"leadTimeOrders"`
+	LeadTimeDays      float64  `json:"leadTimeDays"`
+	CoverDays         int      `json:"coverDays"`
+	SuggestedQty      int      `json:"suggestedQty"`
+}
+
+// ReorderDetail returns the reorder rationale for a variant: its stock against
+// the reorder point, its sales velocity over the last 30 days and the average
+// lead time of recently received orders from the product's suppliers. Without
+// received orders a 7-day lead time is assumed.
+func (s *ProductService) ReorderDetail(variantID string) (*ReorderDetail, *ServiceError) {
+	variant, err := s.repo.GetVariantByID(variantID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &ServiceError{
+				Err:     ErrNotFound,
+				Message: "Variant not found",
+				Code:    "VARIANT_NOT_FOUND",
+			}
+		}
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to fetch variant",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -reorderVelocityWindowDays)
+	sold, err := s.repo.VariantQuantitySoldSince(variantID, since)
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to fetch variant sales",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	orders, err := s.repo.ListReceivedSupplierOrders(variant.ProductID, reorderLeadTimeSampleSize)
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to fetch supplier purchase orders",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	detail := &ReorderDetail{
+		VariantID:         variant.ID,
+		ProductID:         variant.ProductID,
+		CurrentStock:      variant.CurrentStock,
+		ReorderPoint:      variant.ReorderPoint,
+		BelowReorderPoint: variant.ReorderPoint > 0 && variant.CurrentStock <= variant.ReorderPoint,
+		WindowDays:        reorderVelocityWindowDays,
+		QuantitySold:      sold,
+		DailyVelocity:     roundTo(float64(sold)/reorderVelocityWindowDays, 2),
+		LeadTimeDays:      defaultReorderLeadTimeDays,
+		CoverDays:         reorderCoverDays,
+	}
+
+	detail.AvgLeadTimeDays, detail.LeadTimeOrders = averageLeadTime(orders)
+	if detail.AvgLeadTimeDays != nil {
+		detail.LeadTimeDays = *detail.AvgLeadTimeDays
+	}
+
+	velocity := float64(sold) / reorderVelocityWindowDays
+	detail.SuggestedQty = suggestedReorderQty(variant.CurrentStock, variant.ReorderPoint, velocity, detail.LeadTimeDays)
+	return detail, nil
+}
+
+// averageLeadTime returns the average days from order to receipt of orders,
+// rounded to 2 decimals, and how many orders it covers. The average is nil
+// when no order has both dates.
+func averageLeadTime(orders []models.PurchaseOrder) (*float64, int) {
+	var totalLeadDays float64
+	var count int
+	for _, po := range orders {
+		ordered, ok := parsePODate(po.Date)
+		if !ok || po.ReceivedDate == nil {
+			continue
+		}
+		count++
+		totalLeadDays += truncateToDate(*po.ReceivedDate).Sub(ordered).Hours() / 24
+	}
+	if count == 0 {
+		return nil, 0
+	}
+	avg := roundTo(totalLeadDays/float64(count), 2)
+	return &avg, count
+}
+
+// suggestedReorderQty tops stock up to the reorder point plus the sales
+// expected over the lead time and cover period. It is never negative.
+func suggestedReorderQty(currentStock, reorderPoint int, dailyVelocity, leadTimeDays float64) int {
+	demand := int(math.Ceil(dailyVelocity * (leadTimeDays + reorderCoverDays)))
+	qty := reorderPoint + demand - currentStock
+	if qty < 0 {
+		return 0
+	}
+	return qty
+}
+
+// LowStock returns a page of variants at or below their reorder point, with
+// product and supplier details for reordering.
+func (s *ProductService) LowStock(params repositories.PaginationParams) ([]repositories.ProductLowStockItem, int64, *ServiceError) {
+	items, total, err := s.repo.ListLowStockVariants(params)
+	if err != nil {
+		return nil, 0, &ServiceError{
+			Err:     err,
+			Message: "Failed to list low-stock variants",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	return items, total, nil
+}
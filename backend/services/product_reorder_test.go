@@ -0,0 +1,25 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestedReorderQty_CoversLeadTimeAndCoverPeriod(t *testing.T) {
+	// 2/day over 7 + 14 days = 42, plus reorder point 10, less 12 in stock
+	assert.Equal(t, 40, suggestedReorderQty(12, 10, 2, 7))
+}
+
+func TestSuggestedReorderQty_RoundsDemandUp(t *testing.T) {
+	// 0.5/day over 3.5 + 14 days = 8.75, rounded up to 9
+	assert.Equal(t, 9, suggestedReorderQty(0, 0, 0.5, 3.5))
+}
+
+func TestSuggestedReorderQty_NoSales_TopsUpToReorderPoint(t *testing.T) {
+	assert.Equal(t, 7, suggestedReorderQty(3, 10, 0, 7))
+}
+
+func TestSuggestedReorderQty_AmpleStock_ReturnsZero(t *testing.T) {
+	assert.Equal(t, 0, suggestedReorderQty(500, 10, 1, 7))
+}
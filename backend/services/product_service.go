@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/utils"
 	"gorm.io/gorm"
 )
 
@@ -21,6 +22,9 @@ type ProductServiceRepository interface {
 type ProductService struct {
 	repo         ProductServiceRepository
 	imageStorage ImageStorage
+	docFormat    utils.DocumentFormat
+	skuSeq       SKUSequence
+	imageLimits  ProductImageLimits
 }
 
 // NewProductService creates a new product service instance.
@@ -29,7 +33,12 @@ func NewProductService(repo ProductServiceRepository, imageStorage ...ImageStora
 	if len(imageStorage) > 0 {
 		storage = imageStorage[0]
 	}
-	return &ProductService{repo: repo, imageStorage: storage}
+	return &ProductService{
+		repo:         repo,
+		imageStorage: storage,
+		docFormat:    utils.DefaultDocumentFormat(),
+		imageLimits:  ProductImageLimits{MaxBytes: DefaultProductImageMaxBytes},
+	}
 }
 
 // ListProducts returns paginated products with lightweight list payload.
@@ -62,6 +71,7 @@ func (s *ProductService) GetProduct(id uint) (*models.Product, *ServiceError) {
 			Code:    "INTERNAL_ERROR",
 		}
 	}
+	product.Warnings = PricingWarnings(product.Variants)
 	return product, nil
 }
 
@@ -79,6 +89,10 @@ func (s *ProductService) CreateProduct(input CreateProductInput) (*models.Produc
 		return nil, err
 	}
 
+	if err := s.validateImageLimits(input.Images, input.Variants); err != nil {
+		return nil, err
+	}
+
 	if err := s.validateGlobalVariantUniqueness(input.Variants, 0); err != nil {
 		return nil, err
 	}
@@ -111,7 +125,7 @@ func (s *ProductService) CreateProduct(input CreateProductInput) (*models.Produc
 			return err
 		}
 
-		if err := s.syncVariants(tx, product.ID, nil, input.Variants); err != nil {
+		if err := s.syncVariants(tx, product.ID, product.Name, nil, input.Variants, input.AutoBarcode); err != nil {
 			return err
 		}
 
@@ -158,7 +172,11 @@ func (s *ProductService) UpdateProduct(id uint, input UpdateProductInput) (*mode
 		}
 	}
 
-	if err := ValidateProductInput(input); err != nil {
+	storedBarcodes := make(map[string]string, len(existing.Variants))
+	for _, variant := range existing.Variants {
+		storedBarcodes[variant.ID] = variant.Barcode
+	}
+	if err := validateProductInput(input, storedBarcodes); err != nil {
 		return nil, &ServiceError{
 			Err:     ErrValidation,
 			Message: err.Error(),
@@ -170,6 +188,10 @@ func (s *ProductService) UpdateProduct(id uint, input UpdateProductInput) (*mode
 		return nil, err
 	}
 
+	if err := s.validateImageLimits(input.Images, input.Variants); err != nil {
+		return nil, err
+	}
+
 	if err := s.validateGlobalVariantUniqueness(input.Variants, id); err != nil {
 		return nil, err
 	}
@@ -225,7 +247,7 @@ func (s *ProductService) UpdateProduct(id uint, input UpdateProductInput) (*mode
 			}
 		}
 
-		if err := s.syncVariants(tx, id, existing.Variants, input.Variants); err != nil {
+		if err := s.syncVariants(tx, id, strings.TrimSpace(input.Name), existing.Variants, input.Variants, input.AutoBarcode); err != nil {
 			return err
 		}
 
@@ -254,18 +276,17 @@ func (s *ProductService) UpdateProduct(id uint, input UpdateProductInput) (*mode
 	return updated, nil
 }
 
-// DeleteProduct deletes a product if it has no stock and no purchase order references.
-func (s *ProductService) DeleteProduct(id uint) *ServiceError {
-	_, err := s.repo.GetByID(id)
-	if err != nil {
+// DeleteCheck reports the reasons, if any, that would block deleting a product.
+func (s *ProductService) DeleteCheck(id uint) (*ProductDeleteCheck, *ServiceError) {
+	if _, err := s.repo.GetByID(id); err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return &ServiceError{
+			return nil, &ServiceError{
 				Err:     ErrNotFound,
 				Message: "Product not found",
 				Code:    "PRODUCT_NOT_FOUND",
 			}
 		}
-		return &ServiceError{
+		return nil, &ServiceError{
 			Err:     err,
 			Message: "Failed to fetch product",
 			Code:    "INTERNAL_ERROR",
@@ -274,33 +295,58 @@ func (s *ProductService) DeleteProduct(id uint) *ServiceError {
 
 	stockCount, err := s.repo.CountVariantsWithStock(id)
 	if err != nil {
-		return &ServiceError{
+		return nil, &ServiceError{
 			Err:     err,
 			Message: "Failed to check product stock",
 			Code:    "INTERNAL_ERROR",
 		}
 	}
-	if stockCount > 0 {
-		return &ServiceError{
-			Err:     ErrConflict,
-			Message: "Cannot delete product with existing stock.",
-			Code:    "PRODUCT_HAS_STOCK",
-		}
-	}
 
 	poRefCount, err := s.repo.CountPurchaseOrderReferences(id)
 	if err != nil {
-		return &ServiceError{
+		return nil, &ServiceError{
 			Err:     err,
 			Message: "Failed to check purchase order references",
 			Code:    "INTERNAL_ERROR",
 		}
 	}
+
+	check := &ProductDeleteCheck{
+		ProductID:          id,
+		HasStock:           stockCount > 0,
+		VariantsWithStock:  stockCount,
+		PurchaseOrderCount: poRefCount,
+		Blockers:           []DeleteBlocker{},
+	}
+	if stockCount > 0 {
+		check.Blockers = append(check.Blockers, DeleteBlocker{
+			Code:    "PRODUCT_HAS_STOCK",
+			Message: "Cannot delete product with existing stock.",
+		})
+	}
 	if poRefCount > 0 {
+		check.Blockers = append(check.Blockers, DeleteBlocker{
+			Code:    "PRODUCT_IN_USE",
+			Message: fmt.Sprintf("Cannot delete product. It is referenced by %d purchase order(s).", poRefCount),
+		})
+	}
+	check.CanDelete = len(check.Blockers) == 0
+
+	return check, nil
+}
+
+// DeleteProduct deletes a product if it has no stock and no purchase order references.
+func (s *ProductService) DeleteProduct(id uint) *ServiceError {
+	check, serviceErr := s.DeleteCheck(id)
+	if serviceErr != nil {
+		return serviceErr
+	}
+	if !check.CanDelete {
+		blocker := check.Blockers[0]
 		return &ServiceError{
 			Err:     ErrConflict,
-			Message: fmt.Sprintf("Cannot delete product. It is referenced by %d purchase order(s).", poRefCount),
-			Code:    "PRODUCT_IN_USE",
+			Message: blocker.Message,
+			Code:    blocker.Code,
 		}
 	}
 
@@ -321,6 +367,121 @@ func (s *ProductService) DeleteProduct(id uint) *ServiceError {
 	return nil
 }
 
+// VariantRackStock returns the quantity of a variant on each associated rack plus
+// whatever part of its current stock is not allocated to any rack.
+func (s *ProductService) VariantRackStock(variantID string) (*VariantRackStock, *ServiceError) {
+	variant, err := s.repo.GetVariantByID(variantID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &ServiceError{
+				Err:     ErrNotFound,
+				Message: "Variant not found",
+				Code:    "VARIANT_NOT_FOUND",
+			}
+		}
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to fetch variant",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	racks, err := s.repo.ListVariantRackQuantities(variantID)
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to fetch variant rack stock",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	allocated := 0
+	for _, rack := range racks {
+		allocated += rack.Quantity
+	}
+	unallocated := variant.CurrentStock - allocated
+	if unallocated < 0 {
+		unallocated = 0
+	}
+
+	return &VariantRackStock{
+		VariantID:    variant.ID,
+		CurrentStock: variant.CurrentStock,
+		Racks:        racks,
+		Allocated:    allocated,
+		Unallocated:  unallocated,
+	}, nil
+}
+
+// BulkSetReorderPoints updates the reorder points of many variants at once.
+// The batch is all-or-nothing: any invalid entry leaves every variant unchanged.
+func (s *ProductService) BulkSetReorderPoints(items []ReorderPointInput) *ServiceError {
+	if len(items) == 0 {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "At least one reorder point is required",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	seen := make(map[string]bool, len(items))
+	for i, item := range items {
+		variantID := strings.TrimSpace(item.VariantID)
+		if _, err := uuid.Parse(variantID); err != nil {
+			return &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("items[%d]: invalid variant ID", i),
+				Code:    "VALIDATION_ERROR",
+			}
+		}
+		if item.ReorderPoint < 0 {
+			return &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("items[%d]: reorder point cannot be negative", i),
+				Code:    "VALIDATION_ERROR",
+			}
+		}
+		if seen[variantID] {
+			return &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("items[%d]: duplicate variant %s", i, variantID),
+				Code:    "VALIDATION_ERROR",
+			}
+		}
+		seen[variantID] = true
+	}
+
+	err := s.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			result := tx.Model(&models.ProductVariant{}).
+				Where("id = ?", strings.TrimSpace(item.VariantID)).
+				Update("reorder_point", item.ReorderPoint)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return &ServiceError{
+					Err:     ErrNotFound,
+					Message: fmt.Sprintf("Variant %s not found", item.VariantID),
+					Code:    "VARIANT_NOT_FOUND",
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if serviceErr, ok := err.(*ServiceError); ok {
+			return serviceErr
+		}
+		return &ServiceError{
+			Err:     err,
+			Message: "Failed to update reorder points",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	return nil
+}
+
 func (s *ProductService) validateReferences(input CreateProductInput) *ServiceError {
 	categoryExists, err := s.repo.CategoryExists(input.CategoryID)
 	if err != nil {
@@ -525,18 +686,38 @@ func recreateUnits(tx *gorm.DB, productID uint, units []CreateProductUnitInput)
 	return nil
 }
 
-func (s *ProductService) syncVariants(tx *gorm.DB, productID uint, existing []models.ProductVariant, inputs []CreateProductVariantInput) error {
+// syncVariants replaces the product's variants with inputs. When a SKU
+// sequence is configured, a variant saved without a SKU keeps its stored one
+// or, if it has none, gets a generated one. Barcodes are filled the same way
+// when autoBarcode is set.
+func (s *ProductService) syncVariants(tx *gorm.DB, productID uint, productName string, existing []models.ProductVariant, inputs []CreateProductVariantInput, autoBarcode bool) error {
 	existingByID := make(map[string]models.ProductVariant, len(existing))
 	for _, variant := range existing {
 		existingByID[variant.ID] = variant
 	}
 
 	incomingIDs := make(map[string]struct{}, len(inputs))
+	takenSKUs := make(map[string]struct{}, len(inputs))
+	takenBarcodes := make(map[string]struct{}, len(inputs))
 	for _, in := range inputs {
 		id := strings.TrimSpace(in.ID)
 		if id != "" {
 			incomingIDs[id] = struct{}{}
 		}
+		sku := strings.TrimSpace(in.SKU)
+		if sku == "" {
+			sku = strings.TrimSpace(existingByID[id].SKU)
+		}
+		if sku != "" {
+			takenSKUs[strings.ToLower(sku)] = struct{}{}
+		}
+		barcode := strings.TrimSpace(in.Barcode)
+		if barcode == "" {
+			barcode = strings.TrimSpace(existingByID[id].Barcode)
+		}
+		if barcode != "" {
+			takenBarcodes[strings.ToLower(barcode)] = struct{}{}
+		}
 	}
 
 	// Delete removed variants, but block deletion when stock exists.
@@ -559,10 +740,40 @@ func (s *ProductService) syncVariants(tx *gorm.DB, productID uint, existing []mo
 	// Upsert variants and nested data.
 	for _, in := range inputs {
 		trimmedID := strings.TrimSpace(in.ID)
-		if existingVariant, ok := existingByID[trimmedID]; ok {
+		existingVariant, isExisting := existingByID[trimmedID]
+		sku := strings.TrimSpace(in.SKU)
+		if sku == "" && s.skuSeq != nil {
+			sku = strings.TrimSpace(existingVariant.SKU)
+			if sku == "" {
+				generated, err := s.generateSKU(tx, productID, productName, takenSKUs)
+				if err != nil {
+					return err
+				}
+				sku = generated
+			}
+		}
+		barcode := strings.TrimSpace(in.Barcode)
+		if barcode == "" && autoBarcode {
+			barcode = strings.TrimSpace(existingVariant.Barcode)
+			if barcode == "" {
+				generated, err := s.generateBarcode(productID, takenBarcodes)
+				if err != nil {
+					return err
+				}
+				barcode = generated
+			}
+		}
+
+		if isExisting {
 			updates := map[string]interface{}{
-				"sku":     strings.TrimSpace(in.SKU),
-				"barcode": strings.TrimSpace(in.Barcode),
+				"sku":     sku,
+				"barcode": barcode,
+			}
+			if in.CostPrice != nil {
+				updates["cost_price"] = *in.CostPrice
+			}
+			if in.ReorderPoint != nil {
+				updates["reorder_point"] = *in.ReorderPoint
 			}
 			if err := tx.Model(&models.ProductVariant{}).Where("id = ?", existingVariant.ID).Updates(updates).Error; err != nil {
 				return err
@@ -575,8 +786,12 @@ func (s *ProductService) syncVariants(tx *gorm.DB, productID uint, existing []mo
 
 		newVariant := models.ProductVariant{
 			ProductID: productID,
-			SKU:       strings.TrimSpace(in.SKU),
-			Barcode:   strings.TrimSpace(in.Barcode),
+			SKU:       sku,
+			Barcode:   barcode,
+			CostPrice: in.CostPrice,
+		}
+		if in.ReorderPoint != nil {
+			newVariant.ReorderPoint = *in.ReorderPoint
 		}
 		if trimmedID != "" {
 			if _, err := uuid.Parse(trimmedID); err == nil {
@@ -655,6 +870,13 @@ func (s *ProductService) replaceVariantDetails(tx *gorm.DB, productID uint, vari
 	}
 
 	if len(input.PricingTiers) > 0 {
+		if err := ValidatePricingTiers(input.PricingTiers); err != nil {
+			return &ServiceError{
+				Err:     ErrValidation,
+				Message: err.Error(),
+				Code:    "VALIDATION_ERROR",
+			}
+		}
 		pricing := make([]models.VariantPricingTier, 0, len(input.PricingTiers))
 		for _, tier := range input.PricingTiers {
 			pricing = append(pricing, models.VariantPricingTier{
@@ -666,6 +888,9 @@ func (s *ProductService) replaceVariantDetails(tx *gorm.DB, productID uint, vari
 		if err := tx.Create(&pricing).Error; err != nil {
 			return err
 		}
+		if err := RepriceMarkupTiers(tx, variantID); err != nil {
+			return err
+		}
 	}
 
 	variant := models.ProductVariant{ID: variantID}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// SKUSequence reserves sequence numbers for generated SKUs.
+type SKUSequence interface {
+	NextSKU(tx *gorm.DB, prefix string) (string, error)
+}
+
+const (
+	// skuPrefixLength is how many characters of the product name make up the
+	// prefix of a generated SKU.
+	skuPrefixLength = 3
+	// defaultSKUPrefix is used when the product name has no usable characters.
+	defaultSKUPrefix = "SKU"
+	// maxSKUAttempts bounds how many sequence numbers are tried before giving
+	// up when generated SKUs collide with existing ones.
+	maxSKUAttempts = 5
+)
+
+// SetSKUSequence enables SKU generation for variants saved without a SKU.
+// Without a sequence such variants keep an empty SKU.
+func (s *ProductService) SetSKUSequence(seq SKUSequence) {
+	s.skuSeq = seq
+}
+
+// skuPrefix derives the SKU prefix from the first ASCII letters and digits of
+// the product name, upper-cased.
+func skuPrefix(productName string) string {
+	var b strings.Builder
+	for _, r := range productName {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			continue
+		}
+		b.WriteRune(unicode.ToUpper(r))
+		if b.Len() == skuPrefixLength {
+			break
+		}
+	}
+	if b.Len() == 0 {
+		return defaultSKUPrefix
+	}
+	return b.String()
+}
+
+// generateSKU reserves a SKU for a variant of the product that is not already
+// used by another product nor listed in taken (lower-cased SKUs of the
+// product's own variants). The generated SKU is added to taken.
+func (s *ProductService) generateSKU(tx *gorm.DB, productID uint, productName string, taken map[string]struct{}) (string, error) {
+	prefix := skuPrefix(productName)
+	for attempt := 0; attempt < maxSKUAttempts; attempt++ {
+		sku, err := s.skuSeq.NextSKU(tx, prefix)
+		if err != nil {
+			return "", err
+		}
+		key := strings.ToLower(sku)
+		if _, exists := taken[key]; exists {
+			continue
+		}
+		exists, err := s.repo.SKUExistsForOtherProducts(sku, productID)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			continue
+		}
+		taken[key] = struct{}{}
+		return sku, nil
+	}
+	return "", &ServiceError{
+		Err:     ErrConflict,
+		Message: "Could not generate a unique SKU, please provide one",
+		Code:    "SKU_GENERATION_FAILED",
+	}
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSKUPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		product  string
+		expected string
+	}{
+		{"first three letters", "Rice", "RIC"},
+		{"skips spaces and punctuation", "  k-o p i", "KOP"},
+		{"keeps digits", "7up Lemon", "7UP"},
+		{"short name", "Ox", "OX"},
+		{"skips non-ascii", "Café au lait", "CAF"},
+		{"no usable characters", "—", defaultSKUPrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, skuPrefix(tt.product))
+		})
+	}
+}
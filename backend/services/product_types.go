@@ -1,5 +1,7 @@
 package services
 
+import "github.com/pointofsale/backend/repositories"
+
 // CreateProductInput represents the payload for creating a product.
 type CreateProductInput struct {
 	Name         string                      `json:"name"`
@@ -13,6 +15,9 @@ type CreateProductInput struct {
 	Images       []CreateProductImageInput   `json:"images"`
 	Units        []CreateProductUnitInput    `json:"units"`
 	Variants     []CreateProductVariantInput `json:"variants"`
+	// AutoBarcode generates an in-store EAN-13 barcode for every variant
+	// saved without one.
+	AutoBarcode bool `json:"autoBarcode"`
 }
 
 // UpdateProductInput reuses create input shape for full replacement updates.
@@ -34,6 +39,8 @@ type CreateProductVariantInput struct {
 	ID           string                          `json:"id,omitempty"`
 	SKU          string                          `json:"sku"`
 	Barcode      string                          `json:"barcode"`
+	CostPrice    *float64                        `json:"costPrice"`
+	ReorderPoint *int                            `json:"reorderPoint"`
 	Attributes   []CreateVariantAttributeInput   `json:"attributes"`
 	Images       []CreateVariantImageInput       `json:"images"`
 	PricingTiers []CreateVariantPricingTierInput `json:"pricingTiers"`
@@ -54,3 +61,34 @@ type CreateVariantPricingTierInput struct {
 	MinQty int     `json:"minQty"`
 	Value  float64 `json:"value"`
 }
+
+// VariantRackStock describes where a variant's current stock is physically located.
+type VariantRackStock struct {
+	VariantID    string                             `json:"variantId"`
+	CurrentStock int                                `json:"currentStock"`
+	Racks        []repositories.VariantRackQuantity `json:"racks"`
+	Allocated    int                                `json:"allocated"`
+	Unallocated  int                                `json:"unallocated"`
+}
+
+// ReorderPointInput sets the reorder point of a single variant.
+type ReorderPointInput struct {
+	VariantID    string `json:"variantId"`
+	ReorderPoint int    `json:"reorderPoint"`
+}
+
+// DeleteBlocker explains one reason an entity cannot be deleted.
+type DeleteBlocker struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ProductDeleteCheck reports whether a product can be deleted and, if not, why.
+type ProductDeleteCheck struct {
+	ProductID          uint            `json:"productId"`
+	CanDelete          bool            `json:"canDelete"`
+	HasStock           bool            `json:"hasStock"`
+	VariantsWithStock  int64           `json:"variantsWithStock"`
+	PurchaseOrderCount int64           `json:"purchaseOrderCount"`
+	Blockers           []DeleteBlocker `json:"blockers"`
+}
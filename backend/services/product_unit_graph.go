@@ -0,0 +1,81 @@
+package services
+
+import "github.com/pointofsale/backend/models"
+
+// UnitGraphNode is one unit of a product and the unit it converts to.
+// Chain lists unit names from this unit down to the base unit.
+type UnitGraphNode struct {
+	ID               uint     `json:"id"`
+	Name             string   `json:"name"`
+	IsBase           bool     `json:"isBase"`
+	ConversionFactor float64  `json:"conversionFactor"`
+	ToBaseUnit       float64  `json:"toBaseUnit"`
+	ConvertsToID     *uint    `json:"convertsToId"`
+	ConvertsToName   string   `json:"convertsToName,omitempty"`
+	Chain            []string `json:"chain"`
+}
+
+// ProductUnitGraph is the unit conversion structure of a product, ordered
+// from the base unit to the largest.
+type ProductUnitGraph struct {
+	ProductID  uint            `json:"productId"`
+	BaseUnitID *uint           `json:"baseUnitId"`
+	Units      []UnitGraphNode `json:"units"`
+}
+
+// UnitGraph returns every unit of a product with its conversion factor,
+// factor to the base unit and parent unit.
+func (s *ProductService) UnitGraph(productID uint) (*ProductUnitGraph, *ServiceError) {
+	product, svcErr := s.GetProduct(productID)
+	if svcErr != nil {
+		return nil, svcErr
+	}
+	return buildUnitGraph(product.ID, product.Units), nil
+}
+
+// buildUnitGraph links units to their parents. A broken or looping parent
+// reference ends the chain rather than failing, so bad data stays visible.
+func buildUnitGraph(productID uint, units []models.ProductUnit) *ProductUnitGraph {
+	byID := make(map[uint]models.ProductUnit, len(units))
+	for _, unit := range units {
+		byID[unit.ID] = unit
+	}
+
+	graph := &ProductUnitGraph{
+		ProductID: productID,
+		Units:     make([]UnitGraphNode, 0, len(units)),
+	}
+	for _, unit := range units {
+		if unit.IsBase && graph.BaseUnitID == nil {
+			id := unit.ID
+			graph.BaseUnitID = &id
+		}
+
+		node := UnitGraphNode{
+			ID:               unit.ID,
+			Name:             unit.Name,
+			IsBase:           unit.IsBase,
+			ConversionFactor: unit.ConversionFactor,
+			ToBaseUnit:       unit.ToBaseUnit,
+			ConvertsToID:     unit.ConvertsToID,
+		}
+		if unit.ConvertsToID != nil {
+			node.ConvertsToName = byID[*unit.ConvertsToID].Name
+		}
+
+		visited := map[uint]bool{unit.ID: true}
+		node.Chain = []string{unit.Name}
+		for current := unit; current.ConvertsToID != nil; {
+			parent, ok := byID[*current.ConvertsToID]
+			if !ok || visited[parent.ID] {
+				break
+			}
+			visited[parent.ID] = true
+			node.Chain = append(node.Chain, parent.Name)
+			current = parent
+		}
+
+		graph.Units = append(graph.Units, node)
+	}
+	return graph
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildUnitGraph_LinksChainToBase(t *testing.T) {
+	pcs, dozen := uint(1), uint(2)
+	units := []models.ProductUnit{
+		{ID: 1, Name: "Pcs", IsBase: true, ConversionFactor: 1, ToBaseUnit: 1},
+		{ID: 2, Name: "Dozen", ConversionFactor: 12, ToBaseUnit: 12, ConvertsToID: &pcs},
+		{ID: 3, Name: "Box", ConversionFactor: 12, ToBaseUnit: 144, ConvertsToID: &dozen},
+	}
+
+	graph := buildUnitGraph(7, units)
+
+	assert.Equal(t, uint(7), graph.ProductID)
+	require.NotNil(t, graph.BaseUnitID)
+	assert.Equal(t, uint(1), *graph.BaseUnitID)
+	require.Len(t, graph.Units, 3)
+
+	assert.Nil(t, graph.Units[0].ConvertsToID)
+	assert.Equal(t, []string{"Pcs"}, graph.Units[0].Chain)
+	assert.Equal(t, "Pcs", graph.Units[1].ConvertsToName)
+	assert.Equal(t, []string{"Dozen", "Pcs"}, graph.Units[1].Chain)
+	assert.Equal(t, "Dozen", graph.Units[2].ConvertsToName)
+	assert.Equal(t, []string{"Box", "Dozen", "Pcs"}, graph.Units[2].Chain)
+	assert.Equal(t, float64(144), graph.Units[2].ToBaseUnit)
+}
+
+func TestBuildUnitGraph_LoopingParents_StopsChain(t *testing.T) {
+	a, b := uint(1), uint(2)
+	units := []models.ProductUnit{
+		{ID: 1, Name: "A", ConvertsToID: &b},
+		{ID: 2, Name: "B", ConvertsToID: &a},
+	}
+
+	graph := buildUnitGraph(1, units)
+
+	assert.Nil(t, graph.BaseUnitID)
+	assert.Equal(t, []string{"A", "B"}, graph.Units[0].Chain)
+	assert.Equal(t, []string{"B", "A"}, graph.Units[1].Chain)
+}
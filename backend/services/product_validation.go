@@ -1,12 +1,24 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/utils"
 )
 
 // ValidateProductInput validates product create/update payload rules that do not require database access.
 func ValidateProductInput(input CreateProductInput) error {
+	return validateProductInput(input, nil)
+}
+
+// validateProductInput implements ValidateProductInput. storedBarcodes maps
+// the IDs of a product's existing variants to their stored barcodes; a
+// variant that keeps its stored barcode is not held to the check-digit rule,
+// so products saved with older free-form barcodes stay editable.
+func validateProductInput(input CreateProductInput, storedBarcodes map[string]string) error {
 	name := strings.TrimSpace(input.Name)
 	if name == "" {
 		return fmt.Errorf("name is required")
@@ -70,7 +82,7 @@ func ValidateProductInput(input CreateProductInput) error {
 		}
 	}
 
-	if err := validateVariants(input.Variants); err != nil {
+	if err := validateVariants(input.Variants, storedBarcodes); err != nil {
 		return err
 	}
 
@@ -135,11 +147,12 @@ func validateUnits(units []CreateProductUnitInput) error {
 	return nil
 }
 
-func validateVariants(variants []CreateProductVariantInput) error {
+func validateVariants(variants []CreateProductVariantInput, storedBarcodes map[string]string) error {
 	skuSeen := make(map[string]struct{}, len(variants))
 	barcodeSeen := make(map[string]struct{}, len(variants))
 
-	for _, variant := range variants {
+	for i, variant := range variants {
+		field := fmt.Sprintf("variants[%d]", i)
 		sku := strings.TrimSpace(variant.SKU)
 		if sku != "" {
 			key := strings.ToLower(sku)
@@ -151,6 +164,11 @@ func validateVariants(variants []CreateProductVariantInput) error {
 
 		barcode := strings.TrimSpace(variant.Barcode)
 		if barcode != "" {
+			stored, ok := storedBarcodes[variant.ID]
+			unchanged := ok && strings.TrimSpace(stored) == barcode
+			if !unchanged && !utils.ValidateBarcode(barcode) {
+				return fmt.Errorf("barcode %s must be a valid EAN-8, UPC-A or EAN-13 code", barcode)
+			}
 			key := strings.ToLower(barcode)
 			if _, exists := barcodeSeen[key]; exists {
 				return fmt.Errorf("duplicate barcode")
@@ -158,28 +176,77 @@ func validateVariants(variants []CreateProductVariantInput) error {
 			barcodeSeen[key] = struct{}{}
 		}
 
-		if len(variant.PricingTiers) == 0 {
-			return fmt.Errorf("at least one pricing tier is required for each variant")
+		if variant.CostPrice != nil {
+			if msg := utils.ValidateNonNegative(*variant.CostPrice, field+".costPrice"); msg != "" {
+				return errors.New(msg)
+			}
 		}
 
-		if variant.PricingTiers[0].MinQty != 1 {
-			return fmt.Errorf("first pricing tier minQty must be 1")
+		if variant.ReorderPoint != nil {
+			if msg := utils.ValidateNonNegative(float64(*variant.ReorderPoint), field+".reorderPoint"); msg != "" {
+				return errors.New(msg)
+			}
 		}
 
-		prevMinQty := 0
-		for i, tier := range variant.PricingTiers {
-			if tier.MinQty <= 0 {
-				return fmt.Errorf("pricing tier minQty must be greater than 0")
-			}
-			if tier.Value < 0 {
-				return fmt.Errorf("pricing tier value must be non-negative")
-			}
-			if i > 0 && tier.MinQty <= prevMinQty {
-				return fmt.Errorf("pricing tiers must be sorted by minQty ascending")
-			}
-			prevMinQty = tier.MinQty
+		if err := validatePricingTiers(variant.PricingTiers, field+".pricingTiers"); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
+
+// ValidatePricingTiers checks that a variant's tiers pick exactly one price
+// for every quantity: the first tier starts at 1, minQty strictly increases
+// and every value is positive.
+func ValidatePricingTiers(tiers []CreateVariantPricingTierInput) error {
+	return validatePricingTiers(tiers, "pricingTiers")
+}
+
+// validatePricingTiers implements ValidatePricingTiers, naming offending tiers
+// relative to field.
+func validatePricingTiers(tiers []CreateVariantPricingTierInput, field string) error {
+	if len(tiers) == 0 {
+		return fmt.Errorf("at least one pricing tier is required for each variant")
+	}
+
+	if tiers[0].MinQty != 1 {
+		return fmt.Errorf("first pricing tier minQty must be 1")
+	}
+
+	prevMinQty := 0
+	for i, tier := range tiers {
+		tierField := fmt.Sprintf("%s[%d]", field, i)
+		if msg := firstViolation(
+			utils.ValidatePositive(float64(tier.MinQty), tierField+".minQty"),
+			utils.ValidatePositive(tier.Value, tierField+".value"),
+		); msg != "" {
+			return errors.New(msg)
+		}
+		if i > 0 && tier.MinQty == prevMinQty {
+			return fmt.Errorf("duplicate pricing tier for minQty %d", tier.MinQty)
+		}
+		if i > 0 && tier.MinQty < prevMinQty {
+			return fmt.Errorf("pricing tiers must be sorted by minQty ascending")
+		}
+		prevMinQty = tier.MinQty
+	}
+
+	return nil
+}
+
+// PricingWarnings lists variants that cannot be sold because they have no pricing tier.
+func PricingWarnings(variants []models.ProductVariant) []string {
+	var warnings []string
+	for _, variant := range variants {
+		if len(variant.PricingTiers) > 0 {
+			continue
+		}
+		label := variant.SKU
+		if label == "" {
+			label = variant.ID
+		}
+		warnings = append(warnings, fmt.Sprintf("variant %s has no pricing tier and cannot be sold", label))
+	}
+	return warnings
+}
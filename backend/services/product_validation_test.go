@@ -3,6 +3,7 @@ package services
 import (
 	"testing"
 
+	"github.com/pointofsale/backend/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -160,6 +161,52 @@ func TestValidateProduct_DuplicateSKU_ReturnsError(t *testing.T) {
 	assert.ErrorContains(t, err, "duplicate sku")
 }
 
+func TestValidateProduct_ValidBarcode_ReturnsNil(t *testing.T) {
+	input := validProductInput()
+	input.Variants[0].Barcode = "4006381333931"
+
+	err := ValidateProductInput(input)
+	require.NoError(t, err)
+}
+
+func TestValidateProduct_BarcodeWrongCheckDigit_ReturnsError(t *testing.T) {
+	input := validProductInput()
+	input.Variants[0].Barcode = "4006381333932"
+
+	err := ValidateProductInput(input)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "barcode 4006381333932 must be a valid EAN-8, UPC-A or EAN-13 code")
+}
+
+func TestValidateProduct_UnchangedLegacyBarcodeOnUpdate_ReturnsNil(t *testing.T) {
+	input := validProductInput()
+	input.Variants[0].ID = "variant-1"
+	input.Variants[0].Barcode = "ABC-123"
+
+	assert.NoError(t, validateProductInput(input, map[string]string{"variant-1": "ABC-123"}))
+	assert.Error(t, validateProductInput(input, map[string]string{"variant-1": "ABC-124"}))
+	assert.Error(t, validateProductInput(input, map[string]string{"variant-2": "ABC-123"}))
+}
+
+func TestValidateProduct_MalformedBarcode_ReturnsError(t *testing.T) {
+	input := validProductInput()
+	input.Variants[0].Barcode = "ABC-123"
+
+	err := ValidateProductInput(input)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "must be a valid EAN-8, UPC-A or EAN-13 code")
+}
+
+func TestValidateProduct_NegativeReorderPoint_ReturnsError(t *testing.T) {
+	input := validProductInput()
+	reorderPoint := -1
+	input.Variants[0].ReorderPoint = &reorderPoint
+
+	err := ValidateProductInput(input)
+	require.Error(t, err)
+	assert.EqualError(t, err, "variants[0].reorderPoint must not be negative")
+}
+
 func TestValidateProduct_PricingTiersMissingMinQty1_ReturnsError(t *testing.T) {
 	input := validProductInput()
 	input.Variants[0].PricingTiers = []CreateVariantPricingTierInput{
@@ -183,3 +230,90 @@ func TestValidateProduct_PricingTiersNotAscending_ReturnsError(t *testing.T) {
 	require.Error(t, err)
 	assert.ErrorContains(t, err, "pricing tiers must be sorted by minQty ascending")
 }
+
+func TestValidateProduct_PricingTiersOverlappingMinQty_ReturnsError(t *testing.T) {
+	input := validProductInput()
+	input.Variants[0].PricingTiers = []CreateVariantPricingTierInput{
+		{MinQty: 1, Value: 15000},
+		{MinQty: 10, Value: 14000},
+		{MinQty: 10, Value: 13000},
+	}
+
+	err := ValidateProductInput(input)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "duplicate pricing tier for minQty 10")
+}
+
+func TestValidateProduct_PricingTierZeroValue_ReturnsError(t *testing.T) {
+	input := validProductInput()
+	input.Variants[0].PricingTiers = []CreateVariantPricingTierInput{
+		{MinQty: 1, Value: 15000},
+		{MinQty: 10, Value: 0},
+	}
+
+	err := ValidateProductInput(input)
+	require.Error(t, err)
+	assert.EqualError(t, err, "variants[0].pricingTiers[1].value must be greater than zero")
+}
+
+func TestValidateProduct_NegativeCostPrice_ReturnsFieldError(t *testing.T) {
+	input := validProductInput()
+	costPrice := -500.0
+	input.Variants[0].CostPrice = &costPrice
+
+	err := ValidateProductInput(input)
+	require.Error(t, err)
+	assert.EqualError(t, err, "variants[0].costPrice must not be negative")
+}
+
+func TestValidateProduct_PricingTierNegativeValue_ReturnsFieldError(t *testing.T) {
+	input := validProductInput()
+	input.Variants[0].PricingTiers = []CreateVariantPricingTierInput{
+		{MinQty: 1, Value: -15000},
+	}
+
+	err := ValidateProductInput(input)
+	require.Error(t, err)
+	assert.EqualError(t, err, "variants[0].pricingTiers[0].value must be greater than zero")
+}
+
+func TestValidatePricingTiers_NegativeValue_NamesTier(t *testing.T) {
+	err := ValidatePricingTiers([]CreateVariantPricingTierInput{
+		{MinQty: 1, Value: 15000},
+		{MinQty: 5, Value: -1},
+	})
+	require.Error(t, err)
+	assert.EqualError(t, err, "pricingTiers[1].value must be greater than zero")
+}
+
+func TestValidateProduct_PricingTiersAscending_Accepted(t *testing.T) {
+	input := validProductInput()
+	input.Variants[0].PricingTiers = []CreateVariantPricingTierInput{
+		{MinQty: 1, Value: 15000},
+		{MinQty: 10, Value: 14000},
+		{MinQty: 50, Value: 13000},
+	}
+
+	assert.NoError(t, ValidateProductInput(input))
+}
+
+func TestPricingWarnings_ReportsVariantsWithoutTiers(t *testing.T) {
+	variants := []models.ProductVariant{
+		{ID: "v-1", SKU: "RC-001", PricingTiers: []models.VariantPricingTier{{MinQty: 1, Value: 15000}}},
+		{ID: "v-2", SKU: "RC-002"},
+		{ID: "v-3"},
+	}
+
+	warnings := PricingWarnings(variants)
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "RC-002")
+	assert.Contains(t, warnings[1], "v-3")
+}
+
+func TestPricingWarnings_AllPriced_ReturnsNil(t *testing.T) {
+	variants := []models.ProductVariant{
+		{ID: "v-1", PricingTiers: []models.VariantPricingTier{{MinQty: 1, Value: 15000}}},
+	}
+
+	assert.Nil(t, PricingWarnings(variants))
+}
@@ -22,6 +22,36 @@ type RackInput struct {
 type RackServiceRepository interface {
 	repositories.RackRepository
 	CleanupVariantRacks(rackID uint) error
+	ListStock(rackID uint, page, pageSize int, activeOnly bool) ([]repositories.RackStockRow, int64, error)
+	EvacuateStock(fromRackID, toRackID uint) (repositories.RackEvacuation, error)
+}
+
+// RackStockItem is a variant on a rack. RackQuantity is the amount placed on
+// this rack; CurrentStock is the variant's total stock across all racks.
+type RackStockItem struct {
+	VariantID     string `json:"variantId"`
+	ProductID     uint   `json:"productId"`
+	ProductName   string `json:"productName"`
+	ProductStatus string `json:"productStatus"`
+	SKU           string `json:"sku"`
+	VariantLabel  string `json:"variantLabel"`
+	RackQuantity  int    `json:"rackQuantity"`
+	CurrentStock  int    `json:"currentStock"`
+}
+
+// EvacuateRackInput names the rack that receives an evacuated rack's stock.
+type EvacuateRackInput struct {
+	ToRackID uint `json:"toRackId"`
+}
+
+// RackEvacuationResult reports a stock move from one rack to another.
+// VariantsMoved counts the variants and QuantityMoved their summed
+// quantities.
+type RackEvacuationResult struct {
+	FromRackID    uint `json:"fromRackId"`
+	ToRackID      uint `json:"toRackId"`
+	VariantsMoved int  `json:"variantsMoved"`
+	QuantityMoved int  `json:"quantityMoved"`
 }
 
 // RackService handles rack business logic
@@ -69,55 +99,10 @@ func (s *RackService) GetRack(id uint) (*models.Rack, *ServiceError) {
 
 // CreateRack creates a new rack with validation
 func (s *RackService) CreateRack(input RackInput) (*models.Rack, *ServiceError) {
-	// Validate name
-	trimmedName := strings.TrimSpace(input.Name)
-	if trimmedName == "" {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Name is required",
-			Code:    "VALIDATION_ERROR",
-		}
-	}
-	if len(trimmedName) > 255 {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Name must be at most 255 characters",
-			Code:    "VALIDATION_ERROR",
-		}
-	}
-
-	// Validate code
-	trimmedCode := strings.TrimSpace(input.Code)
-	if trimmedCode == "" {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Code is required",
-			Code:    "VALIDATION_ERROR",
-		}
-	}
-	if len(trimmedCode) > 50 {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Code must be at most 50 characters",
-			Code:    "VALIDATION_ERROR",
-		}
-	}
-
-	// Validate location
-	trimmedLocation := strings.TrimSpace(input.Location)
-	if trimmedLocation == "" {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Location is required",
-			Code:    "VALIDATION_ERROR",
-		}
-	}
-	if len(trimmedLocation) > 255 {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Location must be at most 255 characters",
-			Code:    "VALIDATION_ERROR",
-		}
+	// Validate name, code and location
+	trimmedName, trimmedCode, trimmedLocation, svcErr := validateRackText(input)
+	if svcErr != nil {
+		return nil, svcErr
 	}
 
 	// Validate capacity
@@ -179,55 +164,10 @@ func (s *RackService) UpdateRack(id uint, input RackInput) (*models.Rack, *Servi
 		}
 	}
 
-	// Validate name
-	trimmedName := strings.TrimSpace(input.Name)
-	if trimmedName == "" {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Name is required",
-			Code:    "VALIDATION_ERROR",
-		}
-	}
-	if len(trimmedName) > 255 {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Name must be at most 255 characters",
-			Code:    "VALIDATION_ERROR",
-		}
-	}
-
-	// Validate code
-	trimmedCode := strings.TrimSpace(input.Code)
-	if trimmedCode == "" {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Code is required",
-			Code:    "VALIDATION_ERROR",
-		}
-	}
-	if len(trimmedCode) > 50 {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Code must be at most 50 characters",
-			Code:    "VALIDATION_ERROR",
-		}
-	}
-
-	// Validate location
-	trimmedLocation := strings.TrimSpace(input.Location)
-	if trimmedLocation == "" {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Location is required",
-			Code:    "VALIDATION_ERROR",
-		}
-	}
-	if len(trimmedLocation) > 255 {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Location must be at most 255 characters",
-			Code:    "VALIDATION_ERROR",
-		}
+	// Validate name, code and location
+	trimmedName, trimmedCode, trimmedLocation, svcErr := validateRackText(input)
+	if svcErr != nil {
+		return nil, svcErr
 	}
 
 	// Validate capacity
@@ -309,3 +249,104 @@ func (s *RackService) DeleteRack(id uint) *ServiceError {
 
 	return nil
 }
+
+// ListStock returns a page of the variants assigned to a rack. When
+// activeOnly is set, variants of inactive products are left out.
+func (s *RackService) ListStock(rackID uint, page, pageSize int, activeOnly bool) ([]RackStockItem, int64, *ServiceError) {
+	if _, svcErr := s.GetRack(rackID); svcErr != nil {
+		return nil, 0, svcErr
+	}
+
+	rows, total, err := s.rackRepo.ListStock(rackID, page, pageSize, activeOnly)
+	if err != nil {
+		return nil, 0, &ServiceError{
+			Err:     err,
+			Message: "Failed to list rack stock",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	items := make([]RackStockItem, len(rows))
+	for i, row := range rows {
+		items[i] = RackStockItem{
+			VariantID:     row.VariantID,
+			ProductID:     row.ProductID,
+			ProductName:   row.ProductName,
+			ProductStatus: row.ProductStatus,
+			SKU:           row.SKU,
+			VariantLabel:  buildVariantLabel(row.Attributes),
+			RackQuantity:  row.RackQuantity,
+			CurrentStock:  row.CurrentStock,
+		}
+	}
+	return items, total, nil
+}
+
+// EvacuateStock moves all stock placed on a rack to another active rack, so
+// the rack can be deleted without losing track of where its stock went.
+func (s *RackService) EvacuateStock(fromRackID, toRackID uint) (*RackEvacuationResult, *ServiceError) {
+	if toRackID == 0 {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Destination rack is required",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if fromRackID == toRackID {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Destination rack must differ from the rack being evacuated",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if _, svcErr := s.GetRack(fromRackID); svcErr != nil {
+		return nil, svcErr
+	}
+	destination, svcErr := s.GetRack(toRackID)
+	if svcErr != nil {
+		if svcErr.Err == ErrNotFound {
+			svcErr.Message = "Destination rack not found"
+		}
+		return nil, svcErr
+	}
+	if !destination.Active {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Destination rack is inactive",
+			Code:    "RACK_INACTIVE",
+		}
+	}
+
+	moved, err := s.rackRepo.EvacuateStock(fromRackID, toRackID)
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to evacuate rack stock",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	return &RackEvacuationResult{
+		FromRackID:    fromRackID,
+		ToRackID:      toRackID,
+		VariantsMoved: moved.Variants,
+		QuantityMoved: moved.Quantity,
+	}, nil
+}
+
+// validateRackText returns the trimmed name, code and location of a rack,
+// each of which is required.
+func validateRackText(input RackInput) (string, string, string, *ServiceError) {
+	name, err := requiredText("Name", input.Name, 255)
+	if err != nil {
+		return "", "", "", err
+	}
+	code, err := requiredText("Code", input.Code, 50)
+	if err != nil {
+		return "", "", "", err
+	}
+	location, err := requiredText("Location", input.Location, 255)
+	if err != nil {
+		return "", "", "", err
+	}
+	return name, code, location, nil
+}
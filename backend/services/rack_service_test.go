@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
@@ -19,6 +20,8 @@ type mockRackRepository struct {
 	updateFn            func(rack *models.Rack) error
 	deleteFn            func(id uint) error
 	cleanupVariantsFn   func(rackID uint) error
+	listStockFn         func(rackID uint, page, pageSize int, activeOnly bool) ([]repositories.RackStockRow, int64, error)
+	evacuateFn          func(fromRackID, toRackID uint) (repositories.RackEvacuation, error)
 }
 
 func (m *mockRackRepository) List(page, pageSize int, search, active, sortBy, sortDir string) ([]models.Rack, int64, error) {
@@ -77,6 +80,20 @@ func (m *mockRackRepository) CleanupVariantRacks(rackID uint) error {
 	return nil
 }
 
+func (m *mockRackRepository) ListStock(rackID uint, page, pageSize int, activeOnly bool) ([]repositories.RackStockRow, int64, error) {
+	if m.listStockFn != nil {
+		return m.listStockFn(rackID, page, pageSize, activeOnly)
+	}
+	return nil, 0, nil
+}
+
+func (m *mockRackRepository) EvacuateStock(fromRackID, toRackID uint) (repositories.RackEvacuation, error) {
+	if m.evacuateFn != nil {
+		return m.evacuateFn(fromRackID, toRackID)
+	}
+	return repositories.RackEvacuation{}, nil
+}
+
 // TestCreateRack_Valid_Succeeds verifies successful rack creation
 func TestCreateRackService_Valid_Succeeds(t *testing.T) {
 	mockRepo := &mockRackRepository{
@@ -283,6 +300,48 @@ func TestUpdateRackService_Valid_Succeeds(t *testing.T) {
 	assert.Equal(t, 100, rack.Capacity)
 }
 
+func TestCreateRackService_WhitespaceOnlyName_ReturnsValidationError(t *testing.T) {
+	mockRepo := &mockRackRepository{
+		createFn: func(rack *models.Rack) error {
+			t.Fatal("create should not be called")
+			return nil
+		},
+	}
+	service := NewRackService(mockRepo)
+
+	_, err := service.CreateRack(RackInput{Name: "   ", Code: "R-001", Location: "Store Front", Capacity: 100})
+
+	require.NotNil(t, err)
+	assert.Equal(t, ErrValidation, err.Err)
+	assert.Equal(t, "Name is required", err.Message)
+}
+
+func TestUpdateRackService_PaddedFields_StoredTrimmed(t *testing.T) {
+	var saved *models.Rack
+	mockRepo := &mockRackRepository{
+		findByIDFn: func(id uint) (*models.Rack, error) {
+			return &models.Rack{ID: 1, Name: "OldName", Code: "R-001", Location: "Old Loc", Capacity: 50, Active: true}, nil
+		},
+		findByCodeExcludeFn: func(code string, excludeID uint) (*models.Rack, error) {
+			assert.Equal(t, "R-002", code)
+			return nil, gorm.ErrRecordNotFound
+		},
+		updateFn: func(rack *models.Rack) error {
+			saved = rack
+			return nil
+		},
+	}
+	service := NewRackService(mockRepo)
+
+	_, err := service.UpdateRack(1, RackInput{Name: "  Cold Storage  ", Code: " R-002 ", Location: "\tBack Room ", Capacity: 10})
+
+	require.Nil(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, "Cold Storage", saved.Name)
+	assert.Equal(t, "R-002", saved.Code)
+	assert.Equal(t, "Back Room", saved.Location)
+}
+
 // TestUpdateRack_NotFound_ReturnsNotFoundError verifies rack existence check
 func TestUpdateRackService_NotFound_ReturnsNotFoundError(t *testing.T) {
 	mockRepo := &mockRackRepository{
@@ -455,3 +514,108 @@ func TestDeleteRackService_CleansUpVariantRacks(t *testing.T) {
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+// TestListStock_BuildsVariantLabels verifies rack stock rows are labelled
+// from their variant attributes
+func TestListStock_BuildsVariantLabels(t *testing.T) {
+	repo := &mockRackRepository{
+		findByIDFn: func(id uint) (*models.Rack, error) {
+			return &models.Rack{ID: id, Name: "Front"}, nil
+		},
+		listStockFn: func(rackID uint, page, pageSize int, activeOnly bool) ([]repositories.RackStockRow, int64, error) {
+			assert.Equal(t, uint(3), rackID)
+			assert.True(t, activeOnly)
+			return []repositories.RackStockRow{
+				{VariantID: "v-1", ProductName: "Kaos", SKU: "KAO-R-L", RackQuantity: 4, CurrentStock: 10, Attributes: []models.VariantAttribute{
+					{AttributeName: "Color", AttributeValue: "Red"},
+					{AttributeName: "Size", AttributeValue: "L"},
+				}},
+				{VariantID: "v-2", ProductName: "Rice", SKU: "RICE-5", RackQuantity: 8, CurrentStock: 8},
+			}, 2, nil
+		},
+	}
+	svc := NewRackService(repo)
+
+	items, total, err := svc.ListStock(3, 1, 10, true)
+	require.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, items, 2)
+	assert.Equal(t, "Red / L", items[0].VariantLabel)
+	assert.Equal(t, 4, items[0].RackQuantity)
+	assert.Equal(t, 10, items[0].CurrentStock)
+	assert.Equal(t, "Default", items[1].VariantLabel)
+}
+
+// TestListStock_RackNotFound_ReturnsNotFound verifies unknown racks are rejected
+func TestListStock_RackNotFound_ReturnsNotFound(t *testing.T) {
+	repo := &mockRackRepository{
+		findByIDFn: func(id uint) (*models.Rack, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	svc := NewRackService(repo)
+
+	_, _, err := svc.ListStock(99, 1, 10, false)
+	require.NotNil(t, err)
+	assert.Equal(t, ErrNotFound, err.Err)
+}
+
+// TestEvacuateStock_Valid_ReportsMovedStock verifies the move is delegated and reported
+func TestEvacuateStock_Valid_ReportsMovedStock(t *testing.T) {
+	repo := &mockRackRepository{
+		findByIDFn: func(id uint) (*models.Rack, error) {
+			return &models.Rack{ID: id, Active: true}, nil
+		},
+		evacuateFn: func(fromRackID, toRackID uint) (repositories.RackEvacuation, error) {
+			assert.Equal(t, uint(3), fromRackID)
+			assert.Equal(t, uint(4), toRackID)
+			return repositories.RackEvacuation{Variants: 2, Quantity: 35}, nil
+		},
+	}
+	svc := NewRackService(repo)
+
+	result, err := svc.EvacuateStock(3, 4)
+	require.Nil(t, err)
+	assert.Equal(t, uint(3), result.FromRackID)
+	assert.Equal(t, uint(4), result.ToRackID)
+	assert.Equal(t, 2, result.VariantsMoved)
+	assert.Equal(t, 35, result.QuantityMoved)
+}
+
+// TestEvacuateStock_InvalidDestination_ReturnsError verifies the destination is checked before moving
+func TestEvacuateStock_InvalidDestination_ReturnsError(t *testing.T) {
+	tests := []struct {
+		name    string
+		toRack  uint
+		errKind error
+		code    string
+	}{
+		{"missing destination", 0, ErrValidation, "VALIDATION_ERROR"},
+		{"same rack", 3, ErrValidation, "VALIDATION_ERROR"},
+		{"unknown destination", 99, ErrNotFound, "RACK_NOT_FOUND"},
+		{"inactive destination", 5, ErrValidation, "RACK_INACTIVE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockRackRepository{
+				findByIDFn: func(id uint) (*models.Rack, error) {
+					if id == 99 {
+						return nil, gorm.ErrRecordNotFound
+					}
+					return &models.Rack{ID: id, Active: id != 5}, nil
+				},
+				evacuateFn: func(fromRackID, toRackID uint) (repositories.RackEvacuation, error) {
+					t.Fatal("stock must not be moved")
+					return repositories.RackEvacuation{}, nil
+				},
+			}
+			svc := NewRackService(repo)
+
+			_, err := svc.EvacuateStock(3, tt.toRack)
+			require.NotNil(t, err)
+			assert.Equal(t, tt.errKind, err.Err)
+			assert.Equal(t, tt.code, err.Code)
+		})
+	}
+}
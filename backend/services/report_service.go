@@ -0,0 +1,531 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pointofsale/backend/repositories"
+)
+
+// maxTimeSeriesBuckets bounds the size of a time series response.
+const maxTimeSeriesBuckets = 1000
+
+// ReportServiceRepository defines repository methods needed by ReportService.
+type ReportServiceRepository interface {
+	ListSalesBetween(from, to time.Time) ([]repositories.SaleAmount, error)
+	ProfitLinesBetween(from, to time.Time) ([]repositories.ProfitLine, error)
+	SalesByCashierBetween(from, to time.Time) ([]repositories.CashierSales, error)
+	PermissionDenialsBetween(from, to time.Time, minDenials int) ([]repositories.PermissionDenialCount, error)
+}
+
+// SalesBucket is the net sales within one time series interval.
+type SalesBucket struct {
+	Start        time.Time `json:"start"`
+	NetSales     float64   `json:"netSales"`
+	Transactions int       `json:"transactions"`
+}
+
+// SalesTimeSeries is net sales bucketed by hour, day or month.
+type SalesTimeSeries struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Interval string        `json:"interval"`
+	Buckets  []SalesBucket `json:"buckets"`
+	Total    float64       `json:"total"`
+}
+
+// ReportService handles reporting queries.
+type ReportService struct {
+	repo ReportServiceRepository
+	cfg  ReportConfig
+}
+
+// ReportConfig holds tunable behaviour for ReportService.
+type ReportConfig struct {
+	// Location is the store's timezone, used to bucket sales by the local
+	// day and hour. Nil means UTC.
+	Location *time.Location
+}
+
+// NewReportService creates a new report service instance.
+func NewReportService(repo ReportServiceRepository, cfg ...ReportConfig) *ReportService {
+	var reportCfg ReportConfig
+	if len(cfg) > 0 {
+		reportCfg = cfg[0]
+	}
+	return &ReportService{repo: repo, cfg: reportCfg}
+}
+
+// location returns the store's timezone, UTC when none is configured.
+func (s *ReportService) location() *time.Location {
+	if s.cfg.Location == nil {
+		return time.UTC
+	}
+	return s.cfg.Location
+}
+
+// Today returns today's date in the store's timezone, as midnight UTC like
+// the dates the reports accept.
+func (s *ReportService) Today() time.Time {
+	now := time.Now().In(s.location())
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// localRange returns the instants bounding the from and to dates (inclusive)
+// in the store's timezone: local midnight of from up to local midnight after to.
+func (s *ReportService) localRange(from, to time.Time) (time.Time, time.Time) {
+	loc := s.location()
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	return start, end
+}
+
+// SalesTimeSeries returns net sales between the from and to dates (inclusive)
+// bucketed by interval. Dates and buckets are in the store's timezone.
+// Buckets without sales are included with zeros.
+func (s *ReportService) SalesTimeSeries(from, to time.Time, interval string) (*SalesTimeSeries, error) {
+	if interval == "" {
+		interval = "day"
+	}
+	if interval != "hour" && interval != "day" && interval != "month" {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "interval must be one of hour, day, month",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	from = truncateToDate(from)
+	to = truncateToDate(to)
+	if from.After(to) {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "'from' must not be after 'to'",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	loc := s.location()
+	rangeStart, end := s.localRange(from, to)
+	start := truncateToInterval(rangeStart, interval, loc)
+
+	buckets := make([]SalesBucket, 0)
+	index := make(map[int64]int)
+	for t := start; t.Before(end); t = nextInterval(t, interval) {
+		if len(buckets) >= maxTimeSeriesBuckets {
+			return nil, &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("Date range is too large for %s interval (max %d buckets)", interval, maxTimeSeriesBuckets),
+				Code:    "VALIDATION_ERROR",
+			}
+		}
+		index[t.Unix()] = len(buckets)
+		buckets = append(buckets, SalesBucket{Start: t})
+	}
+
+	sales, err := s.repo.ListSalesBetween(rangeStart, end)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to load sales", Code: "INTERNAL_ERROR"}
+	}
+
+	var total float64
+	for _, sale := range sales {
+		i, ok := index[truncateToInterval(sale.Date, interval, loc).Unix()]
+		if !ok {
+			continue
+		}
+		buckets[i].NetSales += sale.NetAmount
+		buckets[i].Transactions++
+		total += sale.NetAmount
+	}
+	for i := range buckets {
+		buckets[i].NetSales = roundTo(buckets[i].NetSales, 2)
+	}
+
+	return &SalesTimeSeries{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Interval: interval,
+		Buckets:  buckets,
+		Total:    roundTo(total, 2),
+	}, nil
+}
+
+// HeatmapCell is the sales within one hour of one day of the week.
+type HeatmapCell struct {
+	Hour         int     `json:"hour"`
+	Transactions int     `json:"transactions"`
+	Revenue      float64 `json:"revenue"`
+}
+
+// HeatmapDay is one day of the week with a cell for each hour, 0 to 23.
+type HeatmapDay struct {
+	Day   string        `json:"day"`
+	Hours []HeatmapCell `json:"hours"`
+}
+
+// HourlyHeatmap is sales bucketed by day of the week and hour of the day.
+// Days run Sunday to Saturday.
+type HourlyHeatmap struct {
+	From         string       `json:"from"`
+	To           string       `json:"to"`
+	Days         []HeatmapDay `json:"days"`
+	Transactions int          `json:"transactions"`
+	Revenue      float64      `json:"revenue"`
+}
+
+// HourlyHeatmap returns the transaction count and net revenue of sales
+// between the from and to dates (inclusive) for every day-of-week and
+// hour-of-day pair. Dates, days and hours are in the store's timezone. Every
+// cell is included, with zeros when empty.
+func (s *ReportService) HourlyHeatmap(from, to time.Time) (*HourlyHeatmap, error) {
+	from = truncateToDate(from)
+	to = truncateToDate(to)
+	if from.After(to) {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "'from' must not be after 'to'",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	loc := s.location()
+	start, end := s.localRange(from, to)
+	sales, err := s.repo.ListSalesBetween(start, end)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to load sales", Code: "INTERNAL_ERROR"}
+	}
+
+	days := make([]HeatmapDay, 7)
+	for d := range days {
+		days[d] = HeatmapDay{Day: time.Weekday(d).String(), Hours: make([]HeatmapCell, 24)}
+		for h := range days[d].Hours {
+			days[d].Hours[h].Hour = h
+		}
+	}
+
+	var revenue float64
+	for _, sale := range sales {
+		date := sale.Date.In(loc)
+		cell := &days[date.Weekday()].Hours[date.Hour()]
+		cell.Transactions++
+		cell.Revenue += sale.NetAmount
+		revenue += sale.NetAmount
+	}
+	for d := range days {
+		for h := range days[d].Hours {
+			days[d].Hours[h].Revenue = roundTo(days[d].Hours[h].Revenue, 2)
+		}
+	}
+
+	return &HourlyHeatmap{
+		From:         from.Format("2006-01-02"),
+		To:           to.Format("2006-01-02"),
+		Days:         days,
+		Transactions: len(sales),
+		Revenue:      roundTo(revenue, 2),
+	}, nil
+}
+
+// CashierPerformance is one cashier's sales totals over a date range.
+// Line discounts are already taken off the subtotal, so gross and net differ
+// only by tax and surcharges. Discounts is what the cashier gave away in line and
+// sale discounts.
+type CashierPerformance struct {
+	CashierID     *uint   `json:"cashierId"`
+	CashierName   string  `json:"cashierName"`
+	Transactions  int     `json:"transactions"`
+	Gross         float64 `json:"gross"`
+	Net           float64 `json:"net"`
+	Surcharges    float64 `json:"surcharges"`
+	Discounts     float64 `json:"discounts"`
+	AverageBasket float64 `json:"averageBasket"`
+}
+
+// CashierPerformanceReport lists per-cashier totals between two dates.
+type CashierPerformanceReport struct {
+	From     string               `json:"from"`
+	To       string               `json:"to"`
+	Cashiers []CashierPerformance `json:"cashiers"`
+}
+
+// CashierPerformance returns transaction count, gross and net sales, and the
+// average basket (gross per transaction) for each cashier between the from
+// and to dates (inclusive, in the store's timezone), highest gross first.
+// Sales without a cashier are grouped under a nil cashier ID.
+func (s *ReportService) CashierPerformance(from, to time.Time) (*CashierPerformanceReport, error) {
+	from = truncateToDate(from)
+	to = truncateToDate(to)
+	if from.After(to) {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "'from' must not be after 'to'",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	start, end := s.localRange(from, to)
+	rows, err := s.repo.SalesByCashierBetween(start, end)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to load cashier sales", Code: "INTERNAL_ERROR"}
+	}
+
+	cashiers := make([]CashierPerformance, 0, len(rows))
+	for _, row := range rows {
+		perf := CashierPerformance{
+			CashierID:    row.CashierID,
+			CashierName:  row.CashierName,
+			Transactions: row.Transactions,
+			Gross:        roundTo(row.Gross, 2),
+			Net:          roundTo(row.Net, 2),
+			Surcharges:   roundTo(row.Surcharges, 2),
+			Discounts:    roundTo(row.Discounts, 2),
+		}
+		if row.Transactions > 0 {
+			perf.AverageBasket = roundTo(row.Gross/float64(row.Transactions), 2)
+		}
+		cashiers = append(cashiers, perf)
+	}
+	sort.Slice(cashiers, func(i, j int) bool {
+		if cashiers[i].Gross != cashiers[j].Gross {
+			return cashiers[i].Gross > cashiers[j].Gross
+		}
+		return cashiers[i].CashierName < cashiers[j].CashierName
+	})
+
+	return &CashierPerformanceReport{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Cashiers: cashiers,
+	}, nil
+}
+
+// PermissionDenial is how often one user was denied one permission on one route.
+type PermissionDenial struct {
+	UserID       *uint     `json:"userId"`
+	UserName     string    `json:"userName"`
+	UserEmail    string    `json:"userEmail"`
+	Method       string    `json:"method"`
+	Route        string    `json:"route"`
+	Permission   string    `json:"permission"`
+	Denials      int       `json:"denials"`
+	LastDeniedAt time.Time `json:"lastDeniedAt"`
+}
+
+// PermissionDenialReport lists frequent permission denials between two dates.
+type PermissionDenialReport struct {
+	From       string             `json:"from"`
+	To         string             `json:"to"`
+	MinDenials int                `json:"minDenials"`
+	Denials    []PermissionDenial `json:"denials"`
+}
+
+// PermissionDenials returns audited permission denials between the from and
+// to dates (inclusive, in the store's timezone) grouped by user, route and
+// permission, keeping groups denied at least minDenials times, most frequent
+// first. Denials are only audited while AUDIT_PERMISSION_DENIALS is on.
+func (s *ReportService) PermissionDenials(from, to time.Time, minDenials int) (*PermissionDenialReport, error) {
+	from = truncateToDate(from)
+	to = truncateToDate(to)
+	if from.After(to) {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "'from' must not be after 'to'",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if minDenials < 1 {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "minDenials must be at least 1",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	start, end := s.localRange(from, to)
+	rows, err := s.repo.PermissionDenialsBetween(start, end, minDenials)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to load permission denials", Code: "INTERNAL_ERROR"}
+	}
+
+	denials := make([]PermissionDenial, 0, len(rows))
+	for _, row := range rows {
+		denials = append(denials, PermissionDenial{
+			UserID:       row.UserID,
+			UserName:     row.UserName,
+			UserEmail:    row.UserEmail,
+			Method:       row.Method,
+			Route:        row.Route,
+			Permission:   row.Permission,
+			Denials:      row.Denials,
+			LastDeniedAt: row.LastDeniedAt.UTC(),
+		})
+	}
+
+	return &PermissionDenialReport{
+		From:       from.Format("2006-01-02"),
+		To:         to.Format("2006-01-02"),
+		MinDenials: minDenials,
+		Denials:    denials,
+	}, nil
+}
+
+// ProfitGroup is the revenue, cost and profit of one day, month, category or
+// product. Margin is profit as a percentage of revenue.
+type ProfitGroup struct {
+	Key     string  `json:"key"`
+	Label   string  `json:"label"`
+	Revenue float64 `json:"revenue"`
+	Cost    float64 `json:"cost"`
+	Profit  float64 `json:"profit"`
+	Margin  float64 `json:"margin"`
+}
+
+// ProfitSummary is revenue, cost and profit between two dates, grouped and
+// in total. UncostedLines counts sold or returned items without a recorded
+// cost; their cost is taken as zero.
+type ProfitSummary struct {
+	From          string        `json:"from"`
+	To            string        `json:"to"`
+	GroupBy       string        `json:"groupBy"`
+	Groups        []ProfitGroup `json:"groups"`
+	Revenue       float64       `json:"revenue"`
+	Cost          float64       `json:"cost"`
+	Profit        float64       `json:"profit"`
+	Margin        float64       `json:"margin"`
+	UncostedLines int           `json:"uncostedLines"`
+}
+
+// ProfitSummary returns revenue, cost and profit between the from and to
+// dates (inclusive) grouped by day, month, category or product. Dates, days
+// and months are in the store's timezone. Revenue is the discounted line
+// total, excluding tax and payment surcharges. Returns reduce revenue by the
+// returned items' share of it and cost by the returned quantity's cost in
+// the period the return was made. Day and month groups are oldest first;
+// category and product groups are most profitable first.
+func (s *ReportService) ProfitSummary(from, to time.Time, groupBy string) (*ProfitSummary, error) {
+	if groupBy == "" {
+		groupBy = "day"
+	}
+	if groupBy != "day" && groupBy != "month" && groupBy != "category" && groupBy != "product" {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "groupBy must be one of day, month, category, product",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	from = truncateToDate(from)
+	to = truncateToDate(to)
+	if from.After(to) {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "'from' must not be after 'to'",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	start, end := s.localRange(from, to)
+	lines, err := s.repo.ProfitLinesBetween(start, end)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to load sold items", Code: "INTERNAL_ERROR"}
+	}
+
+	summary := &ProfitSummary{
+		From:    from.Format("2006-01-02"),
+		To:      to.Format("2006-01-02"),
+		GroupBy: groupBy,
+		Groups:  make([]ProfitGroup, 0),
+	}
+	index := make(map[string]int)
+	for _, line := range lines {
+		key, label := profitGroupKey(line, groupBy, s.location())
+		i, ok := index[key]
+		if !ok {
+			i = len(summary.Groups)
+			index[key] = i
+			summary.Groups = append(summary.Groups, ProfitGroup{Key: key, Label: label})
+		}
+		summary.Groups[i].Revenue += line.Revenue
+		summary.Groups[i].Cost += line.Cost
+		summary.Revenue += line.Revenue
+		summary.Cost += line.Cost
+		if !line.Costed {
+			summary.UncostedLines++
+		}
+	}
+
+	for i := range summary.Groups {
+		group := &summary.Groups[i]
+		group.Revenue, group.Cost, group.Profit, group.Margin = profitFigures(group.Revenue, group.Cost)
+	}
+	summary.Revenue, summary.Cost, summary.Profit, summary.Margin = profitFigures(summary.Revenue, summary.Cost)
+
+	groups := summary.Groups
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groupBy == "day" || groupBy == "month" {
+			return groups[i].Key < groups[j].Key
+		}
+		if groups[i].Profit != groups[j].Profit {
+			return groups[i].Profit > groups[j].Profit
+		}
+		return groups[i].Label < groups[j].Label
+	})
+
+	return summary, nil
+}
+
+// profitGroupKey returns the group a profit line belongs to and its label.
+// Days and months are those of loc.
+func profitGroupKey(line repositories.ProfitLine, groupBy string, loc *time.Location) (string, string) {
+	switch groupBy {
+	case "month":
+		key := line.Date.In(loc).Format("2006-01")
+		return key, key
+	case "category":
+		return strconv.FormatUint(uint64(line.CategoryID), 10), line.CategoryName
+	case "product":
+		return strconv.FormatUint(uint64(line.ProductID), 10), line.ProductName
+	default:
+		key := line.Date.In(loc).Format("2006-01-02")
+		return key, key
+	}
+}
+
+// profitFigures rounds revenue and cost and derives profit and margin.
+func profitFigures(revenue, cost float64) (float64, float64, float64, float64) {
+	revenue = roundTo(revenue, 2)
+	cost = roundTo(cost, 2)
+	profit := roundTo(revenue-cost, 2)
+	var margin float64
+	if revenue != 0 {
+		margin = roundTo(profit/revenue*100, 2)
+	}
+	return revenue, cost, profit, margin
+}
+
+// truncateToInterval returns the start of the interval containing t in loc.
+func truncateToInterval(t time.Time, interval string, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch interval {
+	case "hour":
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+}
+
+func nextInterval(t time.Time, interval string) time.Time {
+	switch interval {
+	case "hour":
+		return t.Add(time.Hour)
+	case "month":
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
@@ -0,0 +1,419 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pointofsale/backend/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockReportRepo struct {
+	listSalesBetweenFn      func(time.Time, time.Time) ([]repositories.SaleAmount, error)
+	profitLinesBetweenFn    func(time.Time, time.Time) ([]repositories.ProfitLine, error)
+	salesByCashierBetweenFn func(time.Time, time.Time) ([]repositories.CashierSales, error)
+	permissionDenialsFn     func(time.Time, time.Time, int) ([]repositories.PermissionDenialCount, error)
+}
+
+func (m *mockReportRepo) ListSalesBetween(from, to time.Time) ([]repositories.SaleAmount, error) {
+	if m.listSalesBetweenFn != nil {
+		return m.listSalesBetweenFn(from, to)
+	}
+	return nil, nil
+}
+
+func (m *mockReportRepo) ProfitLinesBetween(from, to time.Time) ([]repositories.ProfitLine, error) {
+	if m.profitLinesBetweenFn != nil {
+		return m.profitLinesBetweenFn(from, to)
+	}
+	return nil, nil
+}
+
+func (m *mockReportRepo) SalesByCashierBetween(from, to time.Time) ([]repositories.CashierSales, error) {
+	if m.salesByCashierBetweenFn != nil {
+		return m.salesByCashierBetweenFn(from, to)
+	}
+	return nil, nil
+}
+
+func (m *mockReportRepo) PermissionDenialsBetween(from, to time.Time, minDenials int) ([]repositories.PermissionDenialCount, error) {
+	if m.permissionDenialsFn != nil {
+		return m.permissionDenialsFn(from, to, minDenials)
+	}
+	return nil, nil
+}
+
+func utcDate(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestSalesTimeSeries_Day_ZeroFillsEmptyDays(t *testing.T) {
+	repo := &mockReportRepo{
+		listSalesBetweenFn: func(from, to time.Time) ([]repositories.SaleAmount, error) {
+			assert.Equal(t, utcDate(2026, 3, 1, 0), from)
+			assert.Equal(t, utcDate(2026, 3, 5, 0), to)
+			return []repositories.SaleAmount{
+				{Date: utcDate(2026, 3, 1, 9), NetAmount: 15000},
+				{Date: utcDate(2026, 3, 1, 17), NetAmount: 5000},
+				{Date: utcDate(2026, 3, 4, 12), NetAmount: 32500},
+			}, nil
+		},
+	}
+	svc := NewReportService(repo)
+
+	series, err := svc.SalesTimeSeries(utcDate(2026, 3, 1, 0), utcDate(2026, 3, 4, 0), "day")
+	require.NoError(t, err)
+	require.Len(t, series.Buckets, 4)
+
+	assert.Equal(t, utcDate(2026, 3, 1, 0), series.Buckets[0].Start)
+	assert.Equal(t, 20000.0, series.Buckets[0].NetSales)
+	assert.Equal(t, 2, series.Buckets[0].Transactions)
+	assert.Equal(t, 0.0, series.Buckets[1].NetSales)
+	assert.Equal(t, 0, series.Buckets[1].Transactions)
+	assert.Equal(t, 0.0, series.Buckets[2].NetSales)
+	assert.Equal(t, 32500.0, series.Buckets[3].NetSales)
+	assert.Equal(t, 52500.0, series.Total)
+}
+
+func TestSalesTimeSeries_Hour_CoversWholeDays(t *testing.T) {
+	repo := &mockReportRepo{
+		listSalesBetweenFn: func(from, to time.Time) ([]repositories.SaleAmount, error) {
+			return []repositories.SaleAmount{{Date: utcDate(2026, 3, 1, 13).Add(25 * time.Minute), NetAmount: 7000}}, nil
+		},
+	}
+	svc := NewReportService(repo)
+
+	series, err := svc.SalesTimeSeries(utcDate(2026, 3, 1, 0), utcDate(2026, 3, 1, 0), "hour")
+	require.NoError(t, err)
+	require.Len(t, series.Buckets, 24)
+	assert.Equal(t, 7000.0, series.Buckets[13].NetSales)
+	assert.Equal(t, 0.0, series.Buckets[12].NetSales)
+}
+
+func TestSalesTimeSeries_Month_BucketsByCalendarMonth(t *testing.T) {
+	repo := &mockReportRepo{
+		listSalesBetweenFn: func(from, to time.Time) ([]repositories.SaleAmount, error) {
+			return []repositories.SaleAmount{
+				{Date: utcDate(2026, 1, 31, 20), NetAmount: 1000},
+				{Date: utcDate(2026, 3, 2, 8), NetAmount: 2000},
+			}, nil
+		},
+	}
+	svc := NewReportService(repo)
+
+	series, err := svc.SalesTimeSeries(utcDate(2026, 1, 1, 0), utcDate(2026, 3, 31, 0), "month")
+	require.NoError(t, err)
+	require.Len(t, series.Buckets, 3)
+	assert.Equal(t, []float64{1000, 0, 2000}, []float64{series.Buckets[0].NetSales, series.Buckets[1].NetSales, series.Buckets[2].NetSales})
+}
+
+func TestSalesTimeSeries_InvalidInterval_ReturnsValidation(t *testing.T) {
+	svc := NewReportService(&mockReportRepo{})
+
+	_, err := svc.SalesTimeSeries(utcDate(2026, 3, 1, 0), utcDate(2026, 3, 4, 0), "week")
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+}
+
+func TestSalesTimeSeries_TooManyBuckets_ReturnsValidation(t *testing.T) {
+	svc := NewReportService(&mockReportRepo{})
+
+	_, err := svc.SalesTimeSeries(utcDate(2025, 1, 1, 0), utcDate(2026, 1, 1, 0), "hour")
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+}
+
+func TestHourlyHeatmap_BucketsByWeekdayAndHour(t *testing.T) {
+	repo := &mockReportRepo{
+		listSalesBetweenFn: func(from, to time.Time) ([]repositories.SaleAmount, error) {
+			assert.Equal(t, utcDate(2026, 3, 1, 0), from)
+			assert.Equal(t, utcDate(2026, 3, 15, 0), to)
+			return []repositories.SaleAmount{
+				// Sundays at 09:xx
+				{Date: utcDate(2026, 3, 1, 9), NetAmount: 15000},
+				{Date: utcDate(2026, 3, 8, 9).Add(45 * time.Minute), NetAmount: 5000.5},
+				// Wednesday at 17:00
+				{Date: utcDate(2026, 3, 4, 17), NetAmount: 32500},
+			}, nil
+		},
+	}
+	svc := NewReportService(repo)
+
+	heatmap, err := svc.HourlyHeatmap(utcDate(2026, 3, 1, 0), utcDate(2026, 3, 14, 0))
+	require.NoError(t, err)
+	require.Len(t, heatmap.Days, 7)
+	for _, day := range heatmap.Days {
+		require.Len(t, day.Hours, 24)
+	}
+
+	assert.Equal(t, "Sunday", heatmap.Days[0].Day)
+	assert.Equal(t, 2, heatmap.Days[0].Hours[9].Transactions)
+	assert.Equal(t, 20000.5, heatmap.Days[0].Hours[9].Revenue)
+	assert.Equal(t, "Wednesday", heatmap.Days[3].Day)
+	assert.Equal(t, 17, heatmap.Days[3].Hours[17].Hour)
+	assert.Equal(t, 1, heatmap.Days[3].Hours[17].Transactions)
+	assert.Equal(t, 32500.0, heatmap.Days[3].Hours[17].Revenue)
+	assert.Equal(t, 0, heatmap.Days[3].Hours[9].Transactions)
+	assert.Equal(t, 3, heatmap.Transactions)
+	assert.Equal(t, 52500.5, heatmap.Revenue)
+}
+
+func TestHourlyHeatmap_StoreTimezone_BucketsByLocalDayAndHour(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	repo := &mockReportRepo{
+		listSalesBetweenFn: func(from, to time.Time) ([]repositories.SaleAmount, error) {
+			// Local midnight of 1 March to local midnight of 2 March
+			assert.True(t, utcDate(2026, 2, 28, 17).Equal(from))
+			assert.True(t, utcDate(2026, 3, 1, 17).Equal(to))
+			return []repositories.SaleAmount{
+				// Saturday 18:30 UTC is Sunday 01:30 in the store
+				{Date: utcDate(2026, 2, 28, 18).Add(30 * time.Minute), NetAmount: 12000},
+			}, nil
+		},
+	}
+	svc := NewReportService(repo, ReportConfig{Location: jakarta})
+
+	heatmap, err := svc.HourlyHeatmap(utcDate(2026, 3, 1, 0), utcDate(2026, 3, 1, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 1, heatmap.Days[0].Hours[1].Transactions)
+	assert.Equal(t, 12000.0, heatmap.Days[0].Hours[1].Revenue)
+	assert.Equal(t, 0, heatmap.Days[6].Hours[18].Transactions)
+}
+
+func TestHourlyHeatmap_FromAfterTo_ReturnsValidation(t *testing.T) {
+	svc := NewReportService(&mockReportRepo{})
+
+	_, err := svc.HourlyHeatmap(utcDate(2026, 3, 5, 0), utcDate(2026, 3, 1, 0))
+	require.Error(t, err)
+	assert.Equal(t, ErrValidation, err.(*ServiceError).Err)
+}
+
+func profitTestLines() []repositories.ProfitLine {
+	return []repositories.ProfitLine{
+		{Date: utcDate(2026, 3, 1, 9), ProductID: 1, ProductName: "Rice", CategoryID: 10, CategoryName: "Food", Revenue: 30000, Cost: 24000, Costed: true},
+		{Date: utcDate(2026, 3, 1, 11), ProductID: 2, ProductName: "Soap", CategoryID: 20, CategoryName: "Home", Revenue: 8000, Cost: 5000, Costed: true},
+		{Date: utcDate(2026, 3, 2, 15), ProductID: 1, ProductName: "Rice", CategoryID: 10, CategoryName: "Food", Revenue: 15000, Cost: 12000, Costed: true},
+		// One Rice returned the next month
+		{Date: utcDate(2026, 4, 1, 10), ProductID: 1, ProductName: "Rice", CategoryID: 10, CategoryName: "Food", Revenue: -10000, Cost: -8000, Costed: true},
+		{Date: utcDate(2026, 4, 2, 10), ProductID: 3, ProductName: "Tea", CategoryID: 10, CategoryName: "Food", Revenue: 5000, Cost: 0, Costed: false},
+	}
+}
+
+func TestProfitSummary_Day_GroupsOldestFirstWithReturnsInTheirDay(t *testing.T) {
+	repo := &mockReportRepo{
+		profitLinesBetweenFn: func(from, to time.Time) ([]repositories.ProfitLine, error) {
+			assert.Equal(t, utcDate(2026, 3, 1, 0), from)
+			assert.Equal(t, utcDate(2026, 4, 3, 0), to)
+			return profitTestLines(), nil
+		},
+	}
+	svc := NewReportService(repo)
+
+	summary, err := svc.ProfitSummary(utcDate(2026, 3, 1, 0), utcDate(2026, 4, 2, 0), "")
+	require.NoError(t, err)
+	assert.Equal(t, "day", summary.GroupBy)
+	require.Len(t, summary.Groups, 4)
+
+	assert.Equal(t, ProfitGroup{Key: "2026-03-01", Label: "2026-03-01", Revenue: 38000, Cost: 29000, Profit: 9000, Margin: 23.68}, summary.Groups[0])
+	assert.Equal(t, "2026-03-02", summary.Groups[1].Key)
+	assert.Equal(t, ProfitGroup{Key: "2026-04-01", Label: "2026-04-01", Revenue: -10000, Cost: -8000, Profit: -2000, Margin: 20}, summary.Groups[2])
+
+	assert.Equal(t, 48000.0, summary.Revenue)
+	assert.Equal(t, 33000.0, summary.Cost)
+	assert.Equal(t, 15000.0, summary.Profit)
+	assert.Equal(t, 31.25, summary.Margin)
+	assert.Equal(t, 1, summary.UncostedLines)
+}
+
+func TestProfitSummary_Month_NetsReturnsIntoTheirMonth(t *testing.T) {
+	svc := NewReportService(&mockReportRepo{
+		profitLinesBetweenFn: func(from, to time.Time) ([]repositories.ProfitLine, error) {
+			return profitTestLines(), nil
+		},
+	})
+
+	summary, err := svc.ProfitSummary(utcDate(2026, 3, 1, 0), utcDate(2026, 4, 30, 0), "month")
+	require.NoError(t, err)
+	require.Len(t, summary.Groups, 2)
+	assert.Equal(t, "2026-03", summary.Groups[0].Key)
+	assert.Equal(t, 12000.0, summary.Groups[0].Profit)
+	assert.Equal(t, "2026-04", summary.Groups[1].Key)
+	assert.Equal(t, -5000.0, summary.Groups[1].Revenue)
+	assert.Equal(t, 3000.0, summary.Groups[1].Profit)
+}
+
+func TestProfitSummary_Product_SortsByProfitAndNetsReturns(t *testing.T) {
+	svc := NewReportService(&mockReportRepo{
+		profitLinesBetweenFn: func(from, to time.Time) ([]repositories.ProfitLine, error) {
+			return profitTestLines(), nil
+		},
+	})
+
+	summary, err := svc.ProfitSummary(utcDate(2026, 3, 1, 0), utcDate(2026, 4, 30, 0), "product")
+	require.NoError(t, err)
+	require.Len(t, summary.Groups, 3)
+
+	assert.Equal(t, ProfitGroup{Key: "1", Label: "Rice", Revenue: 35000, Cost: 28000, Profit: 7000, Margin: 20}, summary.Groups[0])
+	assert.Equal(t, ProfitGroup{Key: "3", Label: "Tea", Revenue: 5000, Cost: 0, Profit: 5000, Margin: 100}, summary.Groups[1])
+	assert.Equal(t, ProfitGroup{Key: "2", Label: "Soap", Revenue: 8000, Cost: 5000, Profit: 3000, Margin: 37.5}, summary.Groups[2])
+}
+
+func TestProfitSummary_Category_CombinesProducts(t *testing.T) {
+	svc := NewReportService(&mockReportRepo{
+		profitLinesBetweenFn: func(from, to time.Time) ([]repositories.ProfitLine, error) {
+			return profitTestLines(), nil
+		},
+	})
+
+	summary, err := svc.ProfitSummary(utcDate(2026, 3, 1, 0), utcDate(2026, 4, 30, 0), "category")
+	require.NoError(t, err)
+	require.Len(t, summary.Groups, 2)
+	assert.Equal(t, "Food", summary.Groups[0].Label)
+	assert.Equal(t, 12000.0, summary.Groups[0].Profit)
+	assert.Equal(t, "Home", summary.Groups[1].Label)
+	assert.Equal(t, 3000.0, summary.Groups[1].Profit)
+}
+
+func TestProfitSummary_InvalidInput_ReturnsValidation(t *testing.T) {
+	svc := NewReportService(&mockReportRepo{})
+
+	_, err := svc.ProfitSummary(utcDate(2026, 3, 1, 0), utcDate(2026, 3, 4, 0), "week")
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+
+	_, err = svc.ProfitSummary(utcDate(2026, 3, 4, 0), utcDate(2026, 3, 1, 0), "day")
+	require.Error(t, err)
+	serviceErr, ok = err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+}
+
+func TestCashierPerformance_ComputesAverageBasketAndSortsByGross(t *testing.T) {
+	alice, bob := uint(1), uint(2)
+	repo := &mockReportRepo{
+		salesByCashierBetweenFn: func(from, to time.Time) ([]repositories.CashierSales, error) {
+			assert.Equal(t, utcDate(2026, 3, 1, 0), from)
+			assert.Equal(t, utcDate(2026, 3, 8, 0), to)
+			return []repositories.CashierSales{
+				{CashierID: &alice, CashierName: "Alice", Transactions: 3, Gross: 100000, Net: 99000, Surcharges: 1000, Discounts: 2500.004},
+				{CashierID: &bob, CashierName: "Bob", Transactions: 2, Gross: 150000, Net: 150000},
+				{CashierName: "", Transactions: 1, Gross: 5000, Net: 5000},
+			}, nil
+		},
+	}
+	svc := NewReportService(repo)
+
+	report, err := svc.CashierPerformance(utcDate(2026, 3, 1, 0), utcDate(2026, 3, 7, 0))
+	require.NoError(t, err)
+	require.Len(t, report.Cashiers, 3)
+
+	assert.Equal(t, "Bob", report.Cashiers[0].CashierName)
+	assert.Equal(t, 75000.0, report.Cashiers[0].AverageBasket)
+	assert.Equal(t, "Alice", report.Cashiers[1].CashierName)
+	assert.Equal(t, 33333.33, report.Cashiers[1].AverageBasket)
+	assert.Equal(t, 1000.0, report.Cashiers[1].Surcharges)
+	assert.Equal(t, 2500.0, report.Cashiers[1].Discounts)
+	assert.Nil(t, report.Cashiers[2].CashierID)
+	assert.Equal(t, "2026-03-01", report.From)
+	assert.Equal(t, "2026-03-07", report.To)
+}
+
+func TestCashierPerformance_StoreTimezone_UsesLocalDayBounds(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	repo := &mockReportRepo{
+		salesByCashierBetweenFn: func(from, to time.Time) ([]repositories.CashierSales, error) {
+			// Local midnight of 1 March to local midnight of 2 March
+			assert.True(t, utcDate(2026, 2, 28, 17).Equal(from))
+			assert.True(t, utcDate(2026, 3, 1, 17).Equal(to))
+			return nil, nil
+		},
+	}
+	svc := NewReportService(repo, ReportConfig{Location: jakarta})
+
+	report, err := svc.CashierPerformance(utcDate(2026, 3, 1, 0), utcDate(2026, 3, 1, 0))
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-01", report.From)
+	assert.Equal(t, "2026-03-01", report.To)
+}
+
+func TestCashierPerformance_FromAfterTo_ReturnsValidation(t *testing.T) {
+	svc := NewReportService(&mockReportRepo{})
+
+	_, err := svc.CashierPerformance(utcDate(2026, 3, 5, 0), utcDate(2026, 3, 1, 0))
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+}
+
+func TestPermissionDenials_PassesRangeAndThreshold(t *testing.T) {
+	userID := uint(7)
+	var gotFrom, gotTo time.Time
+	var gotMin int
+	repo := &mockReportRepo{
+		permissionDenialsFn: func(from, to time.Time, minDenials int) ([]repositories.PermissionDenialCount, error) {
+			gotFrom, gotTo, gotMin = from, to, minDenials
+			return []repositories.PermissionDenialCount{
+				{UserID: &userID, UserName: "Rina", Method: "DELETE", Route: "/api/v1/users/{id}", Permission: "Settings:Users:delete", Denials: 12, LastDeniedAt: utcDate(2024, 3, 2, 9)},
+			}, nil
+		},
+	}
+	svc := NewReportService(repo)
+
+	report, err := svc.PermissionDenials(utcDate(2024, 3, 1, 15), utcDate(2024, 3, 2, 0), 5)
+
+	require.NoError(t, err)
+	assert.Equal(t, utcDate(2024, 3, 1, 0), gotFrom)
+	assert.Equal(t, utcDate(2024, 3, 3, 0), gotTo)
+	assert.Equal(t, 5, gotMin)
+	assert.Equal(t, "2024-03-01", report.From)
+	assert.Equal(t, "2024-03-02", report.To)
+	require.Len(t, report.Denials, 1)
+	assert.Equal(t, 12, report.Denials[0].Denials)
+	assert.Equal(t, "Settings:Users:delete", report.Denials[0].Permission)
+}
+
+func TestPermissionDenials_StoreTimezone_UsesLocalDayBounds(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	var gotFrom, gotTo time.Time
+	repo := &mockReportRepo{
+		permissionDenialsFn: func(from, to time.Time, minDenials int) ([]repositories.PermissionDenialCount, error) {
+			gotFrom, gotTo = from, to
+			return nil, nil
+		},
+	}
+	svc := NewReportService(repo, ReportConfig{Location: jakarta})
+
+	_, err := svc.PermissionDenials(utcDate(2024, 3, 1, 0), utcDate(2024, 3, 1, 0), 1)
+
+	require.NoError(t, err)
+	assert.True(t, utcDate(2024, 2, 29, 17).Equal(gotFrom))
+	assert.True(t, utcDate(2024, 3, 1, 17).Equal(gotTo))
+}
+
+func TestPermissionDenials_InvalidInput_ReturnsValidation(t *testing.T) {
+	svc := NewReportService(&mockReportRepo{})
+
+	_, err := svc.PermissionDenials(utcDate(2024, 3, 5, 0), utcDate(2024, 3, 1, 0), 1)
+	require.Error(t, err)
+	assert.Equal(t, ErrValidation, err.(*ServiceError).Err)
+
+	_, err = svc.PermissionDenials(utcDate(2024, 3, 1, 0), utcDate(2024, 3, 5, 0), 0)
+	require.Error(t, err)
+	assert.Equal(t, ErrValidation, err.(*ServiceError).Err)
+}
+
+func TestToday_StoreTimezone_ReturnsLocalDate(t *testing.T) {
+	kiribati := time.FixedZone("LINT", 14*60*60)
+	svc := NewReportService(&mockReportRepo{}, ReportConfig{Location: kiribati})
+
+	now := time.Now().In(kiribati)
+	assert.Equal(t, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), svc.Today())
+}
@@ -1,10 +1,12 @@
 package services
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/repositories"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
@@ -12,16 +14,33 @@ import (
 type RoleInput struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	// ActorID is the user making the change, recorded in the audit log
+	ActorID uint `json:"-"`
+}
+
+// RoleConfig holds optional dependencies for RoleService.
+type RoleConfig struct {
+	// Redis holds the permission cache, which is cleared for users moved to
+	// another role. Nil leaves cached permissions to expire.
+	Redis *redis.Client
+	// Audit records role updates, with the role's state before and after.
+	// Nil records nothing.
+	Audit AuditRecorder
 }
 
 // RoleService handles role business logic
 type RoleService struct {
 	roleRepo repositories.RoleRepository
+	cfg      RoleConfig
 }
 
 // NewRoleService creates a new role service instance
-func NewRoleService(roleRepo repositories.RoleRepository) *RoleService {
-	return &RoleService{roleRepo: roleRepo}
+func NewRoleService(roleRepo repositories.RoleRepository, cfg ...RoleConfig) *RoleService {
+	var roleCfg RoleConfig
+	if len(cfg) > 0 {
+		roleCfg = cfg[0]
+	}
+	return &RoleService{roleRepo: roleRepo, cfg: roleCfg}
 }
 
 // ListRoles returns paginated roles with user counts
@@ -160,6 +179,7 @@ func (s *RoleService) UpdateRole(id uint, input RoleInput) (*models.Role, *Servi
 	}
 
 	// Update fields
+	before := auditSnapshot(role)
 	role.Name = trimmedName
 	role.Description = strings.TrimSpace(input.Description)
 
@@ -170,23 +190,102 @@ func (s *RoleService) UpdateRole(id uint, input RoleInput) (*models.Role, *Servi
 			Code:    "INTERNAL_ERROR",
 		}
 	}
+	recordChange(s.cfg.Audit, models.AuditEventRoleUpdated, input.ActorID, "role", role.ID, before, auditSnapshot(role))
 
 	return role, nil
 }
 
-// DeleteRole deletes a role by ID
+// DeleteRole deletes a role by ID. System roles and roles still assigned to
+// users cannot be deleted; use DeleteRoleReassigning to move the users first.
 func (s *RoleService) DeleteRole(id uint) *ServiceError {
-	// Find role
-	role, err := s.roleRepo.FindByID(id)
+	if _, svcErr := s.findDeletableRole(id); svcErr != nil {
+		return svcErr
+	}
+
+	// Block roles still assigned to users
+	userCount, err := s.roleRepo.CountUsersWithRole(id)
 	if err != nil {
+		return &ServiceError{
+			Err:     err,
+			Message: "Failed to delete role",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	if userCount > 0 {
+		return &ServiceError{
+			Err:     ErrConflict,
+			Message: fmt.Sprintf("Cannot delete role assigned to %d user(s)", userCount),
+			Code:    "ROLE_IN_USE",
+		}
+	}
+
+	// Delete role (CASCADE removes user_roles and role_permissions)
+	if err := s.roleRepo.Delete(id); err != nil {
+		return &ServiceError{
+			Err:     err,
+			Message: "Failed to delete role",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	return nil
+}
+
+// DeleteRoleReassigning deletes a role after assigning the fallback role to
+// every user holding it, in one transaction, so no user loses access
+// unexpectedly. System roles cannot be deleted.
+func (s *RoleService) DeleteRoleReassigning(id, fallbackRoleID uint) *ServiceError {
+	if _, svcErr := s.findDeletableRole(id); svcErr != nil {
+		return svcErr
+	}
+
+	if fallbackRoleID == id {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Fallback role must differ from the role being deleted",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if _, err := s.roleRepo.FindByID(fallbackRoleID); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return &ServiceError{
+				Err:     ErrValidation,
+				Message: "Fallback role not found",
+				Code:    "FALLBACK_ROLE_NOT_FOUND",
+			}
+		}
+		return &ServiceError{
+			Err:     err,
+			Message: "Failed to get fallback role",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	userIDs, err := s.roleRepo.ReassignUsersAndDelete(id, fallbackRoleID)
+	if err != nil {
+		return &ServiceError{
+			Err:     err,
+			Message: "Failed to delete role",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	invalidatePermissions(s.cfg.Redis, userIDs...)
+	return nil
+}
+
+// findDeletableRole loads a role and rejects system roles.
+func (s *RoleService) findDeletableRole(id uint) (*models.Role, *ServiceError) {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &ServiceError{
 				Err:     ErrNotFound,
 				Message: "Role not found",
 				Code:    "ROLE_NOT_FOUND",
 			}
 		}
-		return &ServiceError{
+		return nil, &ServiceError{
 			Err:     err,
 			Message: "Failed to get role",
 			Code:    "INTERNAL_ERROR",
@@ -195,21 +294,74 @@ func (s *RoleService) DeleteRole(id uint) *ServiceError {
 
 	// Block system roles
 	if role.IsSystem {
-		return &ServiceError{
+		return nil, &ServiceError{
 			Err:     ErrForbidden,
 			Message: "System roles cannot be deleted",
 			Code:    "SYSTEM_ROLE_PROTECTED",
 		}
 	}
+	return role, nil
+}
 
-	// Delete role (CASCADE removes user_roles and role_permissions)
-	if err := s.roleRepo.Delete(id); err != nil {
-		return &ServiceError{
+// CloneRole creates a new role named newName with the same permissions as the
+// source role. The clone is never a system role, even if the source is.
+func (s *RoleService) CloneRole(sourceRoleID uint, newName string) (*models.Role, *ServiceError) {
+	source, err := s.roleRepo.FindByID(sourceRoleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, &ServiceError{
+				Err:     ErrNotFound,
+				Message: "Role not found",
+				Code:    "ROLE_NOT_FOUND",
+			}
+		}
+		return nil, &ServiceError{
 			Err:     err,
-			Message: "Failed to delete role",
+			Message: "Failed to get role",
 			Code:    "INTERNAL_ERROR",
 		}
 	}
 
-	return nil
+	// Validate name
+	trimmedName := strings.TrimSpace(newName)
+	if trimmedName == "" {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Name is required",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if len(trimmedName) < 2 || len(trimmedName) > 255 {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Name must be between 2 and 255 characters",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	// Check uniqueness
+	existing, _ := s.roleRepo.FindByName(trimmedName)
+	if existing != nil {
+		return nil, &ServiceError{
+			Err:     ErrConflict,
+			Message: "Role name already exists",
+			Code:    "ROLE_NAME_EXISTS",
+		}
+	}
+
+	role := &models.Role{
+		Name:        trimmedName,
+		Description: source.Description,
+		IsSystem:    false,
+	}
+
+	if err := s.roleRepo.CloneWithPermissions(source.ID, role); err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to clone role",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	return role, nil
 }
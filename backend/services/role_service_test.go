@@ -3,8 +3,10 @@ package services
 import (
 	"testing"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/repositories"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
@@ -19,6 +21,9 @@ type mockRoleRepository struct {
 	createFn              func(role *models.Role) error
 	updateFn              func(role *models.Role) error
 	deleteFn              func(id uint) error
+	cloneFn               func(sourceID uint, role *models.Role) error
+	countUsersWithRoleFn  func(roleID uint) (int64, error)
+	reassignAndDeleteFn   func(id, fallbackID uint) ([]uint, error)
 }
 
 func (m *mockRoleRepository) List(page, pageSize int, search, sortBy, sortDir string) ([]repositories.RoleWithCount, int64, error) {
@@ -70,6 +75,27 @@ func (m *mockRoleRepository) Delete(id uint) error {
 	return nil
 }
 
+func (m *mockRoleRepository) CountUsersWithRole(roleID uint) (int64, error) {
+	if m.countUsersWithRoleFn != nil {
+		return m.countUsersWithRoleFn(roleID)
+	}
+	return 0, nil
+}
+
+func (m *mockRoleRepository) ReassignUsersAndDelete(id, fallbackID uint) ([]uint, error) {
+	if m.reassignAndDeleteFn != nil {
+		return m.reassignAndDeleteFn(id, fallbackID)
+	}
+	return nil, nil
+}
+
+func (m *mockRoleRepository) CloneWithPermissions(sourceID uint, role *models.Role) error {
+	if m.cloneFn != nil {
+		return m.cloneFn(sourceID, role)
+	}
+	return nil
+}
+
 // TestListRoles_Valid_Succeeds verifies list delegation to repository
 func TestListRoles_Valid_Succeeds(t *testing.T) {
 	mockRepo := &mockRoleRepository{
@@ -375,3 +401,236 @@ func TestDeleteRole_Regular_CleansUpRelations(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, 1, deleteCallCount, "Delete should be called once")
 }
+
+// TestCloneRole_SystemSource_CreatesNonSystemCopy verifies the clone drops the system flag
+func TestCloneRole_SystemSource_CreatesNonSystemCopy(t *testing.T) {
+	var clonedFrom uint
+	mockRepo := &mockRoleRepository{
+		findByIDFn: func(id uint) (*models.Role, error) {
+			return &models.Role{ID: id, Name: "Super Admin", Description: "All access", IsSystem: true}, nil
+		},
+		cloneFn: func(sourceID uint, role *models.Role) error {
+			clonedFrom = sourceID
+			role.ID = 9
+			return nil
+		},
+	}
+
+	service := NewRoleService(mockRepo)
+	role, err := service.CloneRole(1, "  Deputy Admin  ")
+
+	require.Nil(t, err)
+	assert.Equal(t, uint(1), clonedFrom)
+	assert.Equal(t, uint(9), role.ID)
+	assert.Equal(t, "Deputy Admin", role.Name)
+	assert.Equal(t, "All access", role.Description)
+	assert.False(t, role.IsSystem)
+}
+
+// TestCloneRole_SourceNotFound_ReturnsNotFoundError verifies a missing source is rejected
+func TestCloneRole_SourceNotFound_ReturnsNotFoundError(t *testing.T) {
+	mockRepo := &mockRoleRepository{
+		cloneFn: func(sourceID uint, role *models.Role) error {
+			t.Fatal("CloneWithPermissions should not be called")
+			return nil
+		},
+	}
+
+	service := NewRoleService(mockRepo)
+	_, err := service.CloneRole(999, "Copy")
+
+	require.NotNil(t, err)
+	assert.Equal(t, ErrNotFound, err.Err)
+	assert.Equal(t, "ROLE_NOT_FOUND", err.Code)
+}
+
+// TestCloneRole_NameExists_ReturnsConflictError verifies the new name must be unique
+func TestCloneRole_NameExists_ReturnsConflictError(t *testing.T) {
+	mockRepo := &mockRoleRepository{
+		findByIDFn: func(id uint) (*models.Role, error) {
+			return &models.Role{ID: id, Name: "Cashier"}, nil
+		},
+		findByNameFn: func(name string) (*models.Role, error) {
+			return &models.Role{ID: 2, Name: name}, nil
+		},
+		cloneFn: func(sourceID uint, role *models.Role) error {
+			t.Fatal("CloneWithPermissions should not be called")
+			return nil
+		},
+	}
+
+	service := NewRoleService(mockRepo)
+	_, err := service.CloneRole(1, "Warehouse")
+
+	require.NotNil(t, err)
+	assert.Equal(t, ErrConflict, err.Err)
+	assert.Equal(t, "ROLE_NAME_EXISTS", err.Code)
+}
+
+// TestCloneRole_EmptyName_ReturnsValidationError verifies the new name is required
+func TestCloneRole_EmptyName_ReturnsValidationError(t *testing.T) {
+	mockRepo := &mockRoleRepository{
+		findByIDFn: func(id uint) (*models.Role, error) {
+			return &models.Role{ID: id, Name: "Cashier"}, nil
+		},
+	}
+
+	service := NewRoleService(mockRepo)
+	_, err := service.CloneRole(1, "   ")
+
+	require.NotNil(t, err)
+	assert.Equal(t, ErrValidation, err.Err)
+}
+
+// TestDeleteRole_AssignedToUsers_ReturnsConflictError verifies roles in use cannot be deleted
+func TestDeleteRole_AssignedToUsers_ReturnsConflictError(t *testing.T) {
+	mockRepo := &mockRoleRepository{
+		findByIDFn: func(id uint) (*models.Role, error) {
+			return &models.Role{ID: id, Name: "Cashier", IsSystem: false}, nil
+		},
+		countUsersWithRoleFn: func(roleID uint) (int64, error) {
+			return 3, nil
+		},
+		deleteFn: func(id uint) error {
+			t.Fatal("Delete should not be called")
+			return nil
+		},
+	}
+
+	service := NewRoleService(mockRepo)
+	err := service.DeleteRole(1)
+
+	require.NotNil(t, err)
+	assert.Equal(t, ErrConflict, err.Err)
+	assert.Equal(t, "ROLE_IN_USE", err.Code)
+	assert.Equal(t, "Cannot delete role assigned to 3 user(s)", err.Message)
+}
+
+// TestDeleteRole_SystemRole_DoesNotCountUsers verifies system roles are blocked before the usage check
+func TestDeleteRole_SystemRole_DoesNotCountUsers(t *testing.T) {
+	mockRepo := &mockRoleRepository{
+		findByIDFn: func(id uint) (*models.Role, error) {
+			return &models.Role{ID: id, Name: "Super Admin", IsSystem: true}, nil
+		},
+		countUsersWithRoleFn: func(roleID uint) (int64, error) {
+			t.Fatal("CountUsersWithRole should not be called")
+			return 0, nil
+		},
+	}
+
+	service := NewRoleService(mockRepo)
+	err := service.DeleteRole(1)
+
+	require.NotNil(t, err)
+	assert.Equal(t, ErrForbidden, err.Err)
+}
+
+// TestDeleteRoleReassigning_Valid_MovesUsersAndDeletes verifies users are moved to the fallback role
+func TestDeleteRoleReassigning_Valid_MovesUsersAndDeletes(t *testing.T) {
+	var gotID, gotFallback uint
+	mockRepo := &mockRoleRepository{
+		findByIDFn: func(id uint) (*models.Role, error) {
+			return &models.Role{ID: id, Name: "Role", IsSystem: false}, nil
+		},
+		countUsersWithRoleFn: func(roleID uint) (int64, error) {
+			t.Fatal("CountUsersWithRole should not be called")
+			return 0, nil
+		},
+		reassignAndDeleteFn: func(id, fallbackID uint) ([]uint, error) {
+			gotID, gotFallback = id, fallbackID
+			return []uint{7, 8}, nil
+		},
+	}
+
+	service := NewRoleService(mockRepo)
+	err := service.DeleteRoleReassigning(1, 2)
+
+	require.Nil(t, err)
+	assert.Equal(t, uint(1), gotID)
+	assert.Equal(t, uint(2), gotFallback)
+}
+
+// TestDeleteRoleReassigning_InvalidatesMovedUsersPermissions verifies cached permissions of moved users are cleared
+func TestDeleteRoleReassigning_InvalidatesMovedUsersPermissions(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	mr.Set("perms:7", "{}")
+	mr.Set("perms:9", "{}")
+
+	mockRepo := &mockRoleRepository{
+		findByIDFn: func(id uint) (*models.Role, error) {
+			return &models.Role{ID: id, Name: "Role", IsSystem: false}, nil
+		},
+		reassignAndDeleteFn: func(id, fallbackID uint) ([]uint, error) {
+			return []uint{7}, nil
+		},
+	}
+
+	service := NewRoleService(mockRepo, RoleConfig{Redis: rdb})
+	err := service.DeleteRoleReassigning(1, 2)
+
+	require.Nil(t, err)
+	assert.False(t, mr.Exists("perms:7"))
+	assert.True(t, mr.Exists("perms:9"))
+}
+
+// TestDeleteRoleReassigning_SameRole_ReturnsValidationError verifies a role cannot fall back to itself
+func TestDeleteRoleReassigning_SameRole_ReturnsValidationError(t *testing.T) {
+	mockRepo := &mockRoleRepository{
+		findByIDFn: func(id uint) (*models.Role, error) {
+			return &models.Role{ID: id, Name: "Role", IsSystem: false}, nil
+		},
+		reassignAndDeleteFn: func(id, fallbackID uint) ([]uint, error) {
+			t.Fatal("ReassignUsersAndDelete should not be called")
+			return nil, nil
+		},
+	}
+
+	service := NewRoleService(mockRepo)
+	err := service.DeleteRoleReassigning(1, 1)
+
+	require.NotNil(t, err)
+	assert.Equal(t, ErrValidation, err.Err)
+}
+
+// TestDeleteRoleReassigning_FallbackNotFound_ReturnsValidationError verifies the fallback role must exist
+func TestDeleteRoleReassigning_FallbackNotFound_ReturnsValidationError(t *testing.T) {
+	mockRepo := &mockRoleRepository{
+		findByIDFn: func(id uint) (*models.Role, error) {
+			if id == 2 {
+				return nil, gorm.ErrRecordNotFound
+			}
+			return &models.Role{ID: id, Name: "Role", IsSystem: false}, nil
+		},
+		reassignAndDeleteFn: func(id, fallbackID uint) ([]uint, error) {
+			t.Fatal("ReassignUsersAndDelete should not be called")
+			return nil, nil
+		},
+	}
+
+	service := NewRoleService(mockRepo)
+	err := service.DeleteRoleReassigning(1, 2)
+
+	require.NotNil(t, err)
+	assert.Equal(t, ErrValidation, err.Err)
+	assert.Equal(t, "FALLBACK_ROLE_NOT_FOUND", err.Code)
+}
+
+// TestDeleteRoleReassigning_SystemRole_ReturnsForbidden verifies system roles stay protected
+func TestDeleteRoleReassigning_SystemRole_ReturnsForbidden(t *testing.T) {
+	mockRepo := &mockRoleRepository{
+		findByIDFn: func(id uint) (*models.Role, error) {
+			return &models.Role{ID: id, Name: "Super Admin", IsSystem: true}, nil
+		},
+		reassignAndDeleteFn: func(id, fallbackID uint) ([]uint, error) {
+			t.Fatal("ReassignUsersAndDelete should not be called")
+			return nil, nil
+		},
+	}
+
+	service := NewRoleService(mockRepo)
+	err := service.DeleteRoleReassigning(1, 2)
+
+	require.NotNil(t, err)
+	assert.Equal(t, ErrForbidden, err.Err)
+}
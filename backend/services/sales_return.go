@@ -0,0 +1,224 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReturnLineInput is a single item being returned from a sale. Quantity is in
+// the unit the item was sold in.
+type ReturnLineInput struct {
+	TransactionItemID uint `json:"transactionItemId"`
+	Quantity          int  `json:"quantity"`
+}
+
+// CreateReturnInput holds the input for returning items from a sale.
+type CreateReturnInput struct {
+	RefundMethod string            `json:"refundMethod"`
+	Items        []ReturnLineInput `json:"items"`
+	// CreatedBy is the user recording the return, taken from the request.
+	CreatedBy uint `json:"-"`
+}
+
+// CreateReturn records a partial or full return of items from a sale. It
+// restores stock for the returned base quantities, writes sales_return stock
+// movements linked to the original transaction and refunds the returned
+// items at the price they were sold for. Surcharges are not refunded.
+func (s *SalesService) CreateReturn(transactionID uint, input CreateReturnInput) (*models.SalesReturn, error) {
+	items, refundMethod := input.Items, input.RefundMethod
+	if !validPaymentMethods[refundMethod] {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: fmt.Sprintf("Invalid refund method: %s. Must be one of: cash, card, qris", refundMethod),
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if len(items) == 0 {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "At least one item must be returned",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	seen := make(map[uint]bool, len(items))
+	for i, item := range items {
+		if msg := utils.ValidatePositive(float64(item.Quantity), fmt.Sprintf("items[%d].quantity", i)); msg != "" {
+			return nil, fieldError(msg)
+		}
+		if seen[item.TransactionItemID] {
+			return nil, &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("Transaction item %d is listed more than once", item.TransactionItemID),
+				Code:    "VALIDATION_ERROR",
+			}
+		}
+		seen[item.TransactionItemID] = true
+	}
+
+	var created *models.SalesReturn
+
+	err := retryTx(s.cfg.CheckoutMaxRetries, s.cfg.CheckoutRetryBackoff, func(attempt int) error {
+		created = nil
+		return s.db.Transaction(func(tx *gorm.DB) error {
+			return s.createReturnTx(tx, transactionID, input, &created)
+		})
+	})
+
+	if err != nil {
+		if serviceErr, ok := err.(*ServiceError); ok {
+			return nil, serviceErr
+		}
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to process return",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	return created, nil
+}
+
+// createReturnTx performs a single return attempt inside tx.
+func (s *SalesService) createReturnTx(tx *gorm.DB, transactionID uint, input CreateReturnInput, created **models.SalesReturn) error {
+	lines, refundMethod := input.Items, input.RefundMethod
+	// Lock the sale so concurrent returns cannot exceed the sold quantities
+	var salesTx models.SalesTransaction
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&salesTx, transactionID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &ServiceError{
+				Err:     ErrNotFound,
+				Message: "Transaction not found",
+				Code:    "TRANSACTION_NOT_FOUND",
+			}
+		}
+		return err
+	}
+
+	var soldItems []models.SalesTransactionItem
+	if err := tx.Where("transaction_id = ?", salesTx.ID).Find(&soldItems).Error; err != nil {
+		return err
+	}
+	soldByID := make(map[uint]models.SalesTransactionItem, len(soldItems))
+	for _, item := range soldItems {
+		soldByID[item.ID] = item
+	}
+
+	var returned []struct {
+		TransactionItemID uint
+		Quantity          int
+	}
+	if err := tx.Model(&models.SalesReturnItem{}).
+		Select("sales_return_items.transaction_item_id, COALESCE(SUM(sales_return_items.quantity), 0) AS quantity").
+		Joins("JOIN sales_returns ON sales_returns.id = sales_return_items.return_id").
+		Where("sales_returns.transaction_id = ?", salesTx.ID).
+		Group("sales_return_items.transaction_item_id").
+		Scan(&returned).Error; err != nil {
+		return err
+	}
+	returnedQty := make(map[uint]int, len(returned))
+	for _, r := range returned {
+		returnedQty[r.TransactionItemID] = r.Quantity
+	}
+
+	returnItems := make([]models.SalesReturnItem, 0, len(lines))
+	var refundAmount float64
+
+	for _, line := range lines {
+		sold, ok := soldByID[line.TransactionItemID]
+		if !ok {
+			return &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("Item %d is not part of transaction %s", line.TransactionItemID, salesTx.TransactionNumber),
+				Code:    "ITEM_NOT_IN_TRANSACTION",
+			}
+		}
+
+		remaining := sold.Quantity - returnedQty[sold.ID]
+		if line.Quantity > remaining {
+			return &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("Cannot return %d %s of %s: only %d of %d sold remain returnable", line.Quantity, sold.UnitName, sold.ProductName, remaining, sold.Quantity),
+				Code:    "RETURN_EXCEEDS_SOLD",
+			}
+		}
+
+		// BaseQty is Quantity times the unit's base conversion, so this divides evenly
+		baseQty := line.Quantity * sold.BaseQty / sold.Quantity
+		lineRefund := float64(line.Quantity) * sold.UnitPrice
+		// A sale discount is shared across the lines in proportion to their value
+		if salesTx.Discount > 0 && salesTx.Subtotal > 0 {
+			lineRefund -= lineRefund * salesTx.Discount / salesTx.Subtotal
+		}
+		// Tax charged on top of the prices is refunded with them
+		if !salesTx.TaxInclusive && salesTx.TaxPercent > 0 {
+			lineRefund += lineRefund * salesTx.TaxPercent / 100
+		}
+		lineRefund = roundTo(lineRefund, 2)
+
+		returnItems = append(returnItems, models.SalesReturnItem{
+			TransactionItemID: sold.ID,
+			VariantID:         sold.VariantID,
+			ProductName:       sold.ProductName,
+			VariantLabel:      sold.VariantLabel,
+			UnitName:          sold.UnitName,
+			Quantity:          line.Quantity,
+			BaseQty:           baseQty,
+			UnitPrice:         sold.UnitPrice,
+			RefundAmount:      lineRefund,
+		})
+		refundAmount += lineRefund
+
+		// Restore stock
+		if err := tx.Model(&models.ProductVariant{}).
+			Where("id = ?", sold.VariantID).
+			Update("current_stock", gorm.Expr("current_stock + ?", baseQty)).Error; err != nil {
+			return err
+		}
+	}
+
+	returnNumber, err := s.seqSvc.ReserveReturnNumber(tx)
+	if err != nil {
+		return err
+	}
+
+	salesReturn := &models.SalesReturn{
+		ReturnNumber:  returnNumber,
+		TransactionID: salesTx.ID,
+		Date:          time.Now(),
+		RefundMethod:  refundMethod,
+		RefundAmount:  roundTo(refundAmount, 2),
+		TotalItems:    len(returnItems),
+		Items:         returnItems,
+	}
+	if err := tx.Create(salesReturn).Error; err != nil {
+		return err
+	}
+
+	var createdBy *uint
+	if input.CreatedBy != 0 {
+		createdBy = &input.CreatedBy
+	}
+	for _, item := range salesReturn.Items {
+		movement := &models.StockMovement{
+			VariantID:     item.VariantID,
+			MovementType:  "sales_return",
+			Quantity:      item.BaseQty, // positive for restock
+			ReferenceType: "sales_transaction",
+			ReferenceID:   &salesTx.ID,
+			Notes:         fmt.Sprintf("Return %s: %s", salesReturn.ReturnNumber, salesTx.TransactionNumber),
+			CreatedBy:     createdBy,
+		}
+		if err := tx.Create(movement).Error; err != nil {
+			return err
+		}
+	}
+
+	*created = salesReturn
+	return nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupReturnTest checks out 5 units of a fresh product and returns the sale
+func setupReturnTest(t *testing.T) (*SalesService, *gorm.DB, *models.SalesTransaction) {
+	t.Helper()
+	db := testutil.SetupTestDB(t)
+	svc := NewSalesService(db, repositories.NewSalesRepository(db), NewSequenceService(db))
+
+	product := testutil.CreateTestProduct(t, db)
+	sale, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 5},
+		},
+	})
+	require.NoError(t, err)
+	return svc, db, sale
+}
+
+func variantStock(t *testing.T, db *gorm.DB, variantID string) int {
+	t.Helper()
+	var variant models.ProductVariant
+	require.NoError(t, db.First(&variant, "id = ?", variantID).Error)
+	return variant.CurrentStock
+}
+
+func TestCreateReturn_PartialReturn_RestoresOnlyReturnedQty(t *testing.T) {
+	svc, db, sale := setupReturnTest(t)
+	soldItem := sale.Items[0]
+	stockAfterSale := variantStock(t, db, soldItem.VariantID)
+
+	result, err := svc.CreateReturn(sale.ID, CreateReturnInput{RefundMethod: "cash", Items: []ReturnLineInput{
+		{TransactionItemID: soldItem.ID, Quantity: 2},
+	}})
+
+	require.NoError(t, err)
+	assert.NotZero(t, result.ID)
+	assert.Regexp(t, `^RTN-\d{4}-\d{6}$`, result.ReturnNumber)
+	assert.Equal(t, sale.ID, result.TransactionID)
+	assert.Equal(t, "cash", result.RefundMethod)
+	assert.InDelta(t, 2*soldItem.UnitPrice, result.RefundAmount, 0.001)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, 2, result.Items[0].Quantity)
+	assert.Equal(t, 2, result.Items[0].BaseQty)
+
+	assert.Equal(t, stockAfterSale+2, variantStock(t, db, soldItem.VariantID))
+
+	var movements []models.StockMovement
+	require.NoError(t, db.Where("movement_type = ? AND reference_id = ?", "sales_return", sale.ID).Find(&movements).Error)
+	require.Len(t, movements, 1)
+	assert.Equal(t, 2, movements[0].Quantity)
+	assert.Equal(t, "sales_transaction", movements[0].ReferenceType)
+	assert.Equal(t, soldItem.VariantID, movements[0].VariantID)
+}
+
+func TestCreateReturn_SaleDiscount_ProratesRefund(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := NewSalesService(db, repositories.NewSalesRepository(db), NewSequenceService(db))
+
+	product := testutil.CreateTestProduct(t, db)
+	sale, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 5},
+		},
+		Discount:            5000,
+		CashierIsSuperAdmin: true,
+	})
+	require.NoError(t, err)
+
+	// 5000 off a 50000 subtotal takes 10% off each returned unit
+	result, err := svc.CreateReturn(sale.ID, CreateReturnInput{RefundMethod: "cash", Items: []ReturnLineInput{
+		{TransactionItemID: sale.Items[0].ID, Quantity: 2},
+	}})
+	require.NoError(t, err)
+	assert.InDelta(t, 2*sale.Items[0].UnitPrice*0.9, result.RefundAmount, 0.001)
+}
+
+func TestCreateReturn_ExclusiveTax_RefundsTax(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	cfg := DefaultSalesConfig()
+	cfg.TaxPercent = 10
+	svc := NewSalesService(db, repositories.NewSalesRepository(db), NewSequenceService(db), cfg)
+
+	product := testutil.CreateTestProduct(t, db)
+	sale, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 5},
+		},
+	})
+	require.NoError(t, err)
+
+	result, err := svc.CreateReturn(sale.ID, CreateReturnInput{RefundMethod: "cash", Items: []ReturnLineInput{
+		{TransactionItemID: sale.Items[0].ID, Quantity: 2},
+	}})
+	require.NoError(t, err)
+	assert.InDelta(t, 2*sale.Items[0].UnitPrice*1.1, result.RefundAmount, 0.001)
+}
+
+func TestCreateReturn_ExceedsSoldQty_ReturnsValidation(t *testing.T) {
+	svc, db, sale := setupReturnTest(t)
+	soldItem := sale.Items[0]
+	stockAfterSale := variantStock(t, db, soldItem.VariantID)
+
+	_, err := svc.CreateReturn(sale.ID, CreateReturnInput{RefundMethod: "cash", Items: []ReturnLineInput{
+		{TransactionItemID: soldItem.ID, Quantity: 6},
+	}})
+
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+	assert.Equal(t, "RETURN_EXCEEDS_SOLD", svcErr.Code)
+	assert.Equal(t, stockAfterSale, variantStock(t, db, soldItem.VariantID))
+}
+
+func TestCreateReturn_CumulativeReturnsExceedSoldQty_ReturnsValidation(t *testing.T) {
+	svc, _, sale := setupReturnTest(t)
+	soldItem := sale.Items[0]
+
+	_, err := svc.CreateReturn(sale.ID, CreateReturnInput{RefundMethod: "cash", Items: []ReturnLineInput{{TransactionItemID: soldItem.ID, Quantity: 3}}})
+	require.NoError(t, err)
+
+	_, err = svc.CreateReturn(sale.ID, CreateReturnInput{RefundMethod: "cash", Items: []ReturnLineInput{{TransactionItemID: soldItem.ID, Quantity: 3}}})
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "RETURN_EXCEEDS_SOLD", svcErr.Code)
+
+	// The remaining quantity can still be returned
+	_, err = svc.CreateReturn(sale.ID, CreateReturnInput{RefundMethod: "cash", Items: []ReturnLineInput{{TransactionItemID: soldItem.ID, Quantity: 2}}})
+	assert.NoError(t, err)
+}
+
+func TestCreateReturn_ItemNotOnSale_ReturnsValidation(t *testing.T) {
+	svc, _, sale := setupReturnTest(t)
+
+	_, err := svc.CreateReturn(sale.ID, CreateReturnInput{RefundMethod: "cash", Items: []ReturnLineInput{
+		{TransactionItemID: sale.Items[0].ID + 1000, Quantity: 1},
+	}})
+
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "ITEM_NOT_IN_TRANSACTION", svcErr.Code)
+}
+
+func TestCreateReturn_TransactionNotFound_ReturnsNotFound(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := NewSalesService(db, repositories.NewSalesRepository(db), NewSequenceService(db))
+
+	_, err := svc.CreateReturn(999999, CreateReturnInput{RefundMethod: "cash", Items: []ReturnLineInput{{TransactionItemID: 1, Quantity: 1}}})
+
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrNotFound, svcErr.Err)
+}
+
+func TestCreateReturn_InvalidInput_ReturnsValidation(t *testing.T) {
+	svc := NewSalesService(nil, nil, nil)
+
+	cases := []struct {
+		name         string
+		items        []ReturnLineInput
+		refundMethod string
+	}{
+		{"invalid refund method", []ReturnLineInput{{TransactionItemID: 1, Quantity: 1}}, "bitcoin"},
+		{"no items", nil, "cash"},
+		{"zero quantity", []ReturnLineInput{{TransactionItemID: 1, Quantity: 0}}, "cash"},
+		{"duplicate item", []ReturnLineInput{{TransactionItemID: 1, Quantity: 1}, {TransactionItemID: 1, Quantity: 1}}, "cash"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.CreateReturn(1, CreateReturnInput{RefundMethod: tc.refundMethod, Items: tc.items})
+			require.Error(t, err)
+			svcErr, ok := err.(*ServiceError)
+			require.True(t, ok)
+			assert.Equal(t, ErrValidation, svcErr.Err)
+		})
+	}
+}
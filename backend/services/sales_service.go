@@ -1,35 +1,52 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/repositories"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 // SalesRepositoryInterface defines repository methods needed by SalesService.
 type SalesRepositoryInterface interface {
 	Create(tx *models.SalesTransaction) error
 	GetByID(id uint) (*models.SalesTransaction, error)
-	List(params repositories.PaginationParams, dateFrom, dateTo string, paymentMethod string) ([]models.SalesTransaction, int64, error)
+	List(params repositories.PaginationParams, filter repositories.SalesListFilter) ([]models.SalesTransaction, repositories.SalesListTotals, error)
+	CreateParkedCart(cart *models.ParkedCart) error
+	GetParkedCart(id uint) (*models.ParkedCart, error)
+	ListParkedCarts(params repositories.PaginationParams) ([]models.ParkedCart, int64, error)
+	DeleteParkedCart(id uint) (bool, error)
 }
 
-// CheckoutInput is the input for creating a sales transaction.
+// CheckoutInput is the input for creating a sales transaction. TaxPercent
+// overrides the configured tax rate and is accepted from super admins only.
 type CheckoutInput struct {
-	PaymentMethod string              `json:"paymentMethod"`
-	Items         []CheckoutItemInput `json:"items"`
+	PaymentMethod       string              `json:"paymentMethod"`
+	Items               []CheckoutItemInput `json:"items"`
+	CustomerName        string              `json:"customerName"`
+	Discount            float64             `json:"discount"`
+	TaxPercent          *float64            `json:"taxPercent"`
+	CashierID           uint                `json:"-"`
+	CashierIsSuperAdmin bool                `json:"-"`
 }
 
 // CheckoutItemInput represents a single line item in the checkout.
+// DiscountPercent reduces the line's unit price; DiscountAmount instead takes
+// a fixed amount off the line total. Either is capped by the cashier's
+// discount ceiling.
 type CheckoutItemInput struct {
-	ProductID uint   `json:"productId"`
-	VariantID string `json:"variantId"`
-	UnitID    uint   `json:"unitId"`
-	Quantity  int    `json:"quantity"`
+	ProductID       uint    `json:"productId"`
+	VariantID       string  `json:"variantId"`
+	UnitID          uint    `json:"unitId"`
+	Quantity        int     `json:"quantity"`
+	DiscountPercent float64 `json:"discountPercent"`
+	DiscountAmount  float64 `json:"discountAmount"`
 }
 
 // ProductSearchResult is the DTO returned by ProductSearch.
@@ -66,9 +83,12 @@ type ProductVariantResult struct {
 	SKU          string                    `json:"sku"`
 	Barcode      string                    `json:"barcode"`
 	CurrentStock int                       `json:"currentStock"`
+	Priced       bool                      `json:"priced"`
 	Attributes   []VariantAttributeResult  `json:"attributes"`
 	Images       []VariantImageResult      `json:"images"`
 	PricingTiers []VariantPricingTierResult `json:"pricingTiers"`
+	// Available is false when the variant has no stock to sell.
+	Available bool `json:"available"`
 }
 
 // VariantAttributeResult is a simplified attribute DTO.
@@ -89,20 +109,106 @@ type VariantPricingTierResult struct {
 	Value  float64 `json:"value"`
 }
 
+// SalesConfig holds tunable behaviour for SalesService.
+type SalesConfig struct {
+	// CheckoutMaxRetries is how many times a checkout is retried after a
+	// serialization failure or deadlock before the error is surfaced.
+	CheckoutMaxRetries int
+	// CheckoutRetryBackoff is the base wait between checkout retries.
+	CheckoutRetryBackoff time.Duration
+	// PaymentSurcharges maps a payment method to a surcharge percentage of
+	// the amount due: the subtotal less discount, plus any exclusive tax.
+	PaymentSurcharges map[string]float64
+	// RequirePricingTier rejects checkout lines for variants without a pricing
+	// tier. When disabled such variants are sold at zero.
+	RequirePricingTier bool
+	// MaxCartItems caps the number of line items in a single checkout. Zero
+	// uses DefaultMaxCartItems.
+	MaxCartItems int
+	// VariantCheckoutLimit caps concurrent checkouts touching the same
+	// variant; further checkouts fail fast with a retryable busy error. Zero
+	// disables the gate. It needs Redis.
+	VariantCheckoutLimit int
+	// Redis holds the per-variant checkout slots.
+	Redis *redis.Client
+	// ZeroStockPolicy controls how variants without stock appear in product
+	// search: ZeroStockShow (the default) lists them flagged as unavailable,
+	// ZeroStockHide leaves them and products with no stock at all out.
+	ZeroStockPolicy string
+	// MaxDiscountPercent is the line discount ceiling for cashiers whose
+	// roles have no ceiling in RoleMaxDiscounts. Zero allows no discounts.
+	MaxDiscountPercent float64
+	// RoleMaxDiscounts maps a lowercase role name to its line discount
+	// ceiling. A cashier holding several listed roles gets the highest.
+	// Super admins are not capped.
+	RoleMaxDiscounts map[string]float64
+	// TaxPercent is the sales tax charged on the discounted subtotal unless
+	// a checkout sets its own. Zero charges no tax.
+	TaxPercent float64
+	// TaxInclusive means selling prices already include the tax: the tax is
+	// carved out of the total instead of added to it.
+	TaxInclusive bool
+}
+
+// Zero-stock policies for the sales catalog
+const (
+	ZeroStockShow = "show"
+	ZeroStockHide = "hide"
+)
+
+// DefaultMaxCartItems is the line item cap used when none is configured.
+const DefaultMaxCartItems = 200
+
+// DefaultSalesConfig returns the configuration used when none is supplied.
+func DefaultSalesConfig() SalesConfig {
+	return SalesConfig{
+		CheckoutMaxRetries:   3,
+		CheckoutRetryBackoff: 20 * time.Millisecond,
+		RequirePricingTier:   true,
+		MaxCartItems:         DefaultMaxCartItems,
+	}
+}
+
+// SalesSequence hands out sales transaction and return numbers.
+// *SequenceService implements it.
+type SalesSequence interface {
+	Peek(kind SequenceKind) (string, error)
+	GenerateTrxNumber() (string, error)
+	ReserveReturnNumber(tx *gorm.DB) (string, error)
+}
+
 // SalesService handles sales transaction business logic.
 type SalesService struct {
 	db        *gorm.DB
 	salesRepo SalesRepositoryInterface
-	seqSvc    *SequenceService
+	seqSvc    SalesSequence
+	cfg       SalesConfig
+	gate      *checkoutGate
+
+	// beforeCheckoutCommit runs at the end of each checkout attempt inside the
+	// transaction. Tests use it to inject database errors.
+	beforeCheckoutCommit func(tx *gorm.DB, attempt int) error
 }
 
 // NewSalesService creates a new sales service instance.
-func NewSalesService(db *gorm.DB, salesRepo SalesRepositoryInterface, seqSvc *SequenceService) *SalesService {
-	return &SalesService{
+func NewSalesService(db *gorm.DB, salesRepo SalesRepositoryInterface, seqSvc SalesSequence, cfg ...SalesConfig) *SalesService {
+	salesCfg := DefaultSalesConfig()
+	if len(cfg) > 0 {
+		salesCfg = cfg[0]
+	}
+	if salesCfg.MaxCartItems <= 0 {
+		salesCfg.MaxCartItems = DefaultMaxCartItems
+	}
+	svc := &SalesService{
 		db:        db,
 		salesRepo: salesRepo,
 		seqSvc:    seqSvc,
+		cfg:       salesCfg,
+	}
+	if salesCfg.VariantCheckoutLimit > 0 && salesCfg.Redis != nil {
+		svc.gate = &checkoutGate{redis: salesCfg.Redis, limit: salesCfg.VariantCheckoutLimit, now: time.Now}
 	}
+	return svc
 }
 
 // validPaymentMethods is the allowlist for payment methods.
@@ -112,8 +218,61 @@ var validPaymentMethods = map[string]bool{
 	"qris": true,
 }
 
+// paymentMethodOrder is the display order of payment methods at checkout.
+var paymentMethodOrder = []string{"cash", "card", "qris"}
+
+// PaymentMethodOption describes a payment method available at checkout.
+type PaymentMethodOption struct {
+	Method           string  `json:"method"`
+	SurchargePercent float64 `json:"surchargePercent"`
+}
+
+// NextTransactionNumber previews the number the next checkout will get.
+// Nothing is reserved.
+func (s *SalesService) NextTransactionNumber() (*NextNumber, error) {
+	number, err := s.seqSvc.Peek(SequenceSalesTransaction)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to generate transaction number", Code: "INTERNAL_ERROR"}
+	}
+	return &NextNumber{Number: number}, nil
+}
+
+// PaymentMethods returns the checkout-eligible payment methods with their surcharges.
+func (s *SalesService) PaymentMethods() []PaymentMethodOption {
+	options := make([]PaymentMethodOption, 0, len(paymentMethodOrder))
+	for _, method := range paymentMethodOrder {
+		options = append(options, PaymentMethodOption{
+			Method:           method,
+			SurchargePercent: s.cfg.PaymentSurcharges[method],
+		})
+	}
+	return options
+}
+
+// calculateSurcharge returns percent of subtotal rounded to two decimals.
+func calculateSurcharge(subtotal, percent float64) float64 {
+	if percent <= 0 {
+		return 0
+	}
+	return math.Round(subtotal*percent) / 100
+}
+
+// calculateTax returns the tax on amount at percent, rounded to 2 decimals.
+// An inclusive amount already contains the tax, so the tax is the part of it
+// above amount / (1 + percent/100).
+func calculateTax(amount, percent float64, inclusive bool) float64 {
+	if percent <= 0 {
+		return 0
+	}
+	if inclusive {
+		return roundTo(amount*percent/(100+percent), 2)
+	}
+	return roundTo(amount*percent/100, 2)
+}
+
 // ProductSearch searches active products by name, SKU, or barcode.
 // Returns at most 10 results. Query must be at least 3 characters.
+// Variants without stock are hidden or flagged per the zero-stock policy.
 func (s *SalesService) ProductSearch(query string) ([]ProductSearchResult, error) {
 	query = strings.TrimSpace(query)
 	if len(query) < 3 {
@@ -125,9 +284,10 @@ func (s *SalesService) ProductSearch(query string) ([]ProductSearchResult, error
 	}
 
 	searchPattern := "%" + query + "%"
+	hideZeroStock := s.cfg.ZeroStockPolicy == ZeroStockHide
 
 	var products []models.Product
-	err := s.db.
+	q := s.db.
 		Preload("Images", func(db *gorm.DB) *gorm.DB {
 			return db.Order("sort_order ASC")
 		}).
@@ -135,6 +295,9 @@ func (s *SalesService) ProductSearch(query string) ([]ProductSearchResult, error
 			return db.Order("to_base_unit ASC")
 		}).
 		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			if hideZeroStock {
+				db = db.Where("current_stock > 0")
+			}
 			return db.Order("created_at ASC")
 		}).
 		Preload("Variants.Attributes").
@@ -148,9 +311,11 @@ func (s *SalesService) ProductSearch(query string) ([]ProductSearchResult, error
 		Where(
 			"name ILIKE ? OR EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND (pv.sku ILIKE ? OR pv.barcode ILIKE ?))",
 			searchPattern, searchPattern, searchPattern,
-		).
-		Limit(10).
-		Find(&products).Error
+		)
+	if hideZeroStock {
+		q = q.Where("EXISTS (SELECT 1 FROM product_variants pv WHERE pv.product_id = products.id AND pv.current_stock > 0)")
+	}
+	err := q.Limit(10).Find(&products).Error
 	if err != nil {
 		return nil, &ServiceError{
 			Err:     err,
@@ -187,82 +352,169 @@ func (s *SalesService) Checkout(input CheckoutInput) (*models.SalesTransaction,
 			Code:    "VALIDATION_ERROR",
 		}
 	}
+	input.CustomerName = strings.TrimSpace(input.CustomerName)
+	if len(input.CustomerName) > 255 {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Customer name must be at most 255 characters",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if len(input.Items) > s.cfg.MaxCartItems {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: fmt.Sprintf("Cart cannot have more than %d items", s.cfg.MaxCartItems),
+			Code:    "TOO_MANY_ITEMS",
+		}
+	}
 
-	// Validate each item quantity
+	// Validate each item quantity and discount
+	if svcErr := validateCartQuantities(input.Items); svcErr != nil {
+		return nil, svcErr
+	}
+	if svcErr := validateCartDiscounts(input.Items, input.Discount); svcErr != nil {
+		return nil, svcErr
+	}
+	var maxDiscount float64
 	for _, item := range input.Items {
-		if item.Quantity <= 0 {
-			return nil, &ServiceError{
-				Err:     ErrValidation,
-				Message: "Item quantity must be greater than zero",
-				Code:    "VALIDATION_ERROR",
-			}
+		maxDiscount = math.Max(maxDiscount, item.DiscountPercent)
+	}
+	if input.TaxPercent != nil && (*input.TaxPercent < 0 || *input.TaxPercent > 100) {
+		return nil, fieldError("taxPercent must be between 0 and 100")
+	}
+	// Only a super admin may charge a rate other than the configured one
+	if input.TaxPercent != nil && *input.TaxPercent != s.cfg.TaxPercent && !input.CashierIsSuperAdmin {
+		return nil, &ServiceError{
+			Err:     ErrForbidden,
+			Message: "Only a super admin can override the sales tax rate",
+			Code:    "TAX_OVERRIDE_FORBIDDEN",
+		}
+	}
+	if maxDiscount > 0 && !input.CashierIsSuperAdmin {
+		if err := s.checkDiscountCeiling(input.CashierID, maxDiscount); err != nil {
+			return nil, err
 		}
 	}
 
+	if s.gate != nil {
+		variantIDs := make([]string, 0, len(input.Items))
+		for _, item := range input.Items {
+			variantIDs = append(variantIDs, item.VariantID)
+		}
+		release, ok := s.gate.acquire(context.Background(), variantIDs)
+		if !ok {
+			return nil, errCheckoutBusy()
+		}
+		defer release()
+	}
+
 	var createdTx *models.SalesTransaction
 
-	err := s.db.Transaction(func(tx *gorm.DB) error {
-		txItems := make([]models.SalesTransactionItem, 0, len(input.Items))
-		var subtotal float64
+	err := retryTx(s.cfg.CheckoutMaxRetries, s.cfg.CheckoutRetryBackoff, func(attempt int) error {
+		createdTx = nil
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			return s.checkoutTx(tx, input, attempt, &createdTx)
+		})
+		// The next attempt reads the sequence again and gets a new number
+		if isDuplicateTrxNumberError(err) {
+			return retryableError{err: err}
+		}
+		return err
+	})
 
-		for _, itemInput := range input.Items {
-			// Load variant with pessimistic lock
-			var variant models.ProductVariant
-			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-				Where("id = ?", itemInput.VariantID).
-				First(&variant).Error; err != nil {
-				return &ServiceError{
-					Err:     ErrValidation,
-					Message: fmt.Sprintf("Variant %s not found", itemInput.VariantID),
-					Code:    "VARIANT_NOT_FOUND",
-				}
+	if err != nil {
+		err = unwrapRetryable(err)
+		if serviceErr, ok := err.(*ServiceError); ok {
+			return nil, serviceErr
+		}
+		if isDuplicateTrxNumberError(err) {
+			return nil, &ServiceError{
+				Err:     ErrConflict,
+				Message: "Transaction number is already in use, please retry the checkout",
+				Code:    "DUPLICATE_TRANSACTION_NUMBER",
 			}
+		}
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to process checkout",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
 
-			// Load pricing tiers
-			var pricingTiers []models.VariantPricingTier
-			if err := tx.Where("variant_id = ?", variant.ID).Find(&pricingTiers).Error; err != nil {
-				return err
-			}
+	return createdTx, nil
+}
 
-			// Load unit
-			var unit models.ProductUnit
-			if err := tx.Where("id = ?", itemInput.UnitID).First(&unit).Error; err != nil {
-				return &ServiceError{
-					Err:     ErrValidation,
-					Message: fmt.Sprintf("Unit %d not found", itemInput.UnitID),
-					Code:    "UNIT_NOT_FOUND",
-				}
+// checkoutTx performs a single checkout attempt inside tx.
+func (s *SalesService) checkoutTx(tx *gorm.DB, input CheckoutInput, attempt int, createdTx **models.SalesTransaction) error {
+	txItems := make([]models.SalesTransactionItem, 0, len(input.Items))
+	var subtotal float64
+
+	// Lock every referenced variant up front and validate all references
+	// together, so one response lists every bad line. A unit or variant of
+	// another product would convert quantities with the wrong factor.
+	loaded, err := loadItemReferences(tx, checkoutItemReferences(input.Items), referenceRules{
+		RequireOwnership: true,
+		LockVariants:     true,
+	})
+	if err != nil {
+		return err
+	}
+
+	variantIDs := make([]string, 0, len(loaded.variants))
+	for id := range loaded.variants {
+		variantIDs = append(variantIDs, id)
+	}
+	var allTiers []models.VariantPricingTier
+	if err := tx.Where("variant_id IN ?", variantIDs).Find(&allTiers).Error; err != nil {
+		return err
+	}
+	tiersByVariant := make(map[string][]models.VariantPricingTier, len(variantIDs))
+	for _, t := range allTiers {
+		tiersByVariant[t.VariantID] = append(tiersByVariant[t.VariantID], t)
+	}
+
+	// Largest line discount, as a percentage, whether given by percent or amount
+	var lineDiscountPercent float64
+	for i, itemInput := range input.Items {
+		variant := loaded.variants[itemInput.VariantID]
+		unit := loaded.units[itemInput.UnitID]
+		product := loaded.products[itemInput.ProductID]
+		pricingTiers := tiersByVariant[variant.ID]
+
+		// Calculate base quantity
+		baseQty := itemInput.Quantity * int(unit.ToBaseUnit)
+
+		// Stock check
+		if baseQty > variant.CurrentStock {
+			return &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("Insufficient stock for %s. Available: %d, requested: %d (base units)", product.Name, variant.CurrentStock, baseQty),
+				Code:    "INSUFFICIENT_STOCK",
 			}
+		}
+
+		// Calculate tiered price
+		tiers := SellingTiers(product.PriceSetting, pricingTiers)
 
-			// Load product for name/denormalization
-			var product models.Product
-			if err := tx.First(&product, itemInput.ProductID).Error; err != nil {
+		var tierValue float64
+		if len(tiers) == 0 {
+			// A markup product has no price until it is first received
+			if product.PriceSetting == PriceSettingMarkup {
 				return &ServiceError{
 					Err:     ErrValidation,
-					Message: fmt.Sprintf("Product %d not found", itemInput.ProductID),
-					Code:    "PRODUCT_NOT_FOUND",
+					Message: fmt.Sprintf("No price derived for %s yet. Receive it on a purchase order before selling it.", product.Name),
+					Code:    "NO_PRICE",
 				}
 			}
-
-			// Calculate base quantity
-			baseQty := itemInput.Quantity * int(unit.ToBaseUnit)
-
-			// Stock check
-			if baseQty > variant.CurrentStock {
+			if s.cfg.RequirePricingTier {
 				return &ServiceError{
 					Err:     ErrValidation,
-					Message: fmt.Sprintf("Insufficient stock for %s. Available: %d, requested: %d (base units)", product.Name, variant.CurrentStock, baseQty),
-					Code:    "INSUFFICIENT_STOCK",
+					Message: fmt.Sprintf("No price defined for %s. Add a pricing tier before selling it.", product.Name),
+					Code:    "NO_PRICE",
 				}
 			}
-
-			// Calculate tiered price
-			tiers := make([]PricingTier, 0, len(pricingTiers))
-			for _, t := range pricingTiers {
-				tiers = append(tiers, PricingTier{MinQty: t.MinQty, Value: t.Value})
-			}
-
-			tierValue, err := CalculateTieredPrice(tiers, itemInput.Quantity, int(unit.ToBaseUnit))
+		} else {
+			value, err := CalculateTieredPrice(tiers, itemInput.Quantity, int(unit.ToBaseUnit))
 			if err != nil {
 				return &ServiceError{
 					Err:     err,
@@ -270,95 +522,147 @@ func (s *SalesService) Checkout(input CheckoutInput) (*models.SalesTransaction,
 					Code:    "PRICING_ERROR",
 				}
 			}
+			tierValue = value
+		}
 
-			// unitPrice = tier.value * toBaseUnit
-			unitPrice := tierValue * unit.ToBaseUnit
-			totalPrice := float64(itemInput.Quantity) * unitPrice
-
-			// Build variant label
-			var attributes []models.VariantAttribute
-			if err := tx.Where("variant_id = ?", variant.ID).Find(&attributes).Error; err != nil {
-				return err
-			}
-			variantLabel := buildSalesVariantLabel(attributes)
-
-			txItems = append(txItems, models.SalesTransactionItem{
-				ProductID:    product.ID,
-				VariantID:    variant.ID,
-				UnitID:       unit.ID,
-				ProductName:  product.Name,
-				VariantLabel: variantLabel,
-				SKU:          variant.SKU,
-				UnitName:     unit.Name,
-				Quantity:     itemInput.Quantity,
-				BaseQty:      baseQty,
-				UnitPrice:    unitPrice,
-				TotalPrice:   totalPrice,
-			})
-
-			subtotal += totalPrice
-
-			// Deduct stock
-			if err := tx.Model(&models.ProductVariant{}).
-				Where("id = ?", variant.ID).
-				Update("current_stock", gorm.Expr("current_stock - ?", baseQty)).Error; err != nil {
-				return err
+		// unitPrice = tier.value * toBaseUnit, less the line discount
+		listPrice := tierValue * unit.ToBaseUnit
+		lineTotal := float64(itemInput.Quantity) * listPrice
+		unitPrice := listPrice
+		totalPrice := lineTotal
+		discountPercent := itemInput.DiscountPercent
+		switch {
+		case itemInput.DiscountPercent > 0:
+			unitPrice = roundTo(listPrice*(100-itemInput.DiscountPercent)/100, 2)
+			totalPrice = float64(itemInput.Quantity) * unitPrice
+		case itemInput.DiscountAmount > 0:
+			if itemInput.DiscountAmount > lineTotal {
+				return fieldError(fmt.Sprintf("items[%d].discountAmount must not exceed the line total of %.2f", i, lineTotal))
 			}
+			totalPrice = roundTo(lineTotal-itemInput.DiscountAmount, 2)
+			unitPrice = roundTo(totalPrice/float64(itemInput.Quantity), 2)
+			discountPercent = roundTo(itemInput.DiscountAmount/lineTotal*100, 2)
 		}
+		lineDiscountPercent = math.Max(lineDiscountPercent, discountPercent)
+		discountAmount := roundTo(lineTotal-totalPrice, 2)
+
+		variantLabel := buildSalesVariantLabel(variant.Attributes)
+
+		txItems = append(txItems, models.SalesTransactionItem{
+			ProductID:       product.ID,
+			VariantID:       variant.ID,
+			UnitID:          unit.ID,
+			ProductName:     product.Name,
+			VariantLabel:    variantLabel,
+			SKU:             variant.SKU,
+			UnitName:        unit.Name,
+			Quantity:        itemInput.Quantity,
+			BaseQty:         baseQty,
+			UnitPrice:       unitPrice,
+			TotalPrice:      totalPrice,
+			DiscountPercent: discountPercent,
+			DiscountAmount:  discountAmount,
+			UnitCost:        variant.CostPrice,
+		})
 
-		// Generate transaction number
-		trxNumber, err := s.seqSvc.GenerateTrxNumber()
-		if err != nil {
-			return err
-		}
+		subtotal += totalPrice
 
-		// Create transaction record
-		salesTx := &models.SalesTransaction{
-			TransactionNumber: trxNumber,
-			Date:              time.Now(),
-			Subtotal:          subtotal,
-			GrandTotal:        subtotal,
-			TotalItems:        len(txItems),
-			PaymentMethod:     input.PaymentMethod,
-			Items:             txItems,
+		// Deduct stock
+		if err := tx.Model(&models.ProductVariant{}).
+			Where("id = ?", variant.ID).
+			Update("current_stock", gorm.Expr("current_stock - ?", baseQty)).Error; err != nil {
+			return err
 		}
+		// Later lines of the same variant see the reduced stock
+		variant.CurrentStock -= baseQty
+		loaded.variants[variant.ID] = variant
+	}
 
-		// Create the transaction
-		if err := tx.Create(salesTx).Error; err != nil {
+	if input.Discount > subtotal {
+		return fieldError(fmt.Sprintf("discount must not exceed the subtotal of %.2f", subtotal))
+	}
+	// Every line also carries its share of the sale discount, so the line
+	// with the largest discount of its own has the largest combined discount
+	combinedDiscountPercent := lineDiscountPercent
+	if input.Discount > 0 {
+		salePercent := input.Discount / subtotal * 100
+		combinedDiscountPercent = roundTo(100-(100-lineDiscountPercent)*(100-salePercent)/100, 2)
+	}
+	if combinedDiscountPercent > 0 && !input.CashierIsSuperAdmin {
+		if err := s.checkDiscountCeiling(input.CashierID, combinedDiscountPercent); err != nil {
 			return err
 		}
+	}
 
-		// Create stock movements
-		for _, item := range salesTx.Items {
-			movement := &models.StockMovement{
-				VariantID:     item.VariantID,
-				MovementType:  "sales",
-				Quantity:      -item.BaseQty, // negative for deduction
-				ReferenceType: "sales_transaction",
-				ReferenceID:   &salesTx.ID,
-				Notes:         fmt.Sprintf("Sales: %s", salesTx.TransactionNumber),
-			}
-			if err := tx.Create(movement).Error; err != nil {
-				return err
-			}
-		}
+	// Generate transaction number
+	trxNumber, err := s.seqSvc.GenerateTrxNumber()
+	if err != nil {
+		return err
+	}
 
-		createdTx = salesTx
-		return nil
-	})
+	discount := roundTo(input.Discount, 2)
+	taxPercent := s.cfg.TaxPercent
+	if input.TaxPercent != nil {
+		taxPercent = *input.TaxPercent
+	}
+	taxable := subtotal - discount
+	tax := calculateTax(taxable, taxPercent, s.cfg.TaxInclusive)
+	charged := taxable
+	if !s.cfg.TaxInclusive {
+		charged += tax
+	}
+	surcharge := calculateSurcharge(charged, s.cfg.PaymentSurcharges[input.PaymentMethod])
+
+	// Create transaction record
+	salesTx := &models.SalesTransaction{
+		TransactionNumber: trxNumber,
+		Date:              time.Now(),
+		Subtotal:          subtotal,
+		Discount:          discount,
+		TaxPercent:        taxPercent,
+		TaxInclusive:      s.cfg.TaxInclusive,
+		TaxAmount:         tax,
+		SurchargeAmount:   surcharge,
+		GrandTotal:        roundTo(charged+surcharge, 2),
+		TotalItems:        len(txItems),
+		PaymentMethod:     input.PaymentMethod,
+		CustomerName:      input.CustomerName,
+		Items:             txItems,
+	}
+	if input.CashierID != 0 {
+		cashierID := input.CashierID
+		salesTx.CashierID = &cashierID
+	}
 
-	if err != nil {
-		if serviceErr, ok := err.(*ServiceError); ok {
-			return nil, serviceErr
+	// Create the transaction
+	if err := tx.Create(salesTx).Error; err != nil {
+		return err
+	}
+
+	// Create stock movements
+	for _, item := range salesTx.Items {
+		movement := &models.StockMovement{
+			VariantID:     item.VariantID,
+			MovementType:  "sales",
+			Quantity:      -item.BaseQty, // negative for deduction
+			ReferenceType: "sales_transaction",
+			ReferenceID:   &salesTx.ID,
+			Notes:         fmt.Sprintf("Sales: %s", salesTx.TransactionNumber),
+			CreatedBy:     salesTx.CashierID,
 		}
-		return nil, &ServiceError{
-			Err:     err,
-			Message: "Failed to process checkout",
-			Code:    "INTERNAL_ERROR",
+		if err := tx.Create(movement).Error; err != nil {
+			return err
 		}
 	}
 
-	return createdTx, nil
+	if s.beforeCheckoutCommit != nil {
+		if err := s.beforeCheckoutCommit(tx, attempt); err != nil {
+			return err
+		}
+	}
+
+	*createdTx = salesTx
+	return nil
 }
 
 // GetTransaction retrieves a sales transaction by ID.
@@ -381,9 +685,85 @@ func (s *SalesService) GetTransaction(id uint) (*models.SalesTransaction, error)
 	return tx, nil
 }
 
-// ListTransactions returns paginated sales transactions.
-func (s *SalesService) ListTransactions(params repositories.PaginationParams, dateFrom, dateTo string, paymentMethod string) ([]models.SalesTransaction, int64, error) {
-	return s.salesRepo.List(params, dateFrom, dateTo, paymentMethod)
+// ListTransactions returns a page of sales transactions matching every given
+// filter, with the number of matches and the sum of their grand totals.
+func (s *SalesService) ListTransactions(params repositories.PaginationParams, filter repositories.SalesListFilter) ([]models.SalesTransaction, repositories.SalesListTotals, error) {
+	if err := validateSalesListFilter(filter); err != nil {
+		return nil, repositories.SalesListTotals{}, err
+	}
+
+	transactions, totals, err := s.salesRepo.List(params, filter)
+	if err != nil {
+		return nil, repositories.SalesListTotals{}, &ServiceError{
+			Err:     err,
+			Message: "Failed to fetch transactions",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	return transactions, totals, nil
+}
+
+// validateSalesListFilter checks the dates, payment methods and total range
+// of a transaction list filter.
+func validateSalesListFilter(filter repositories.SalesListFilter) error {
+	from, err := parseFilterDate("dateFrom", filter.DateFrom)
+	if err != nil {
+		return err
+	}
+	to, err := parseFilterDate("dateTo", filter.DateTo)
+	if err != nil {
+		return err
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "'dateFrom' must not be after 'dateTo'",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	for _, method := range filter.PaymentMethods {
+		if !validPaymentMethods[method] {
+			return &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("Invalid payment method: %s. Must be one of: cash, card, qris", method),
+				Code:    "VALIDATION_ERROR",
+			}
+		}
+	}
+
+	if (filter.MinTotal != nil && *filter.MinTotal < 0) || (filter.MaxTotal != nil && *filter.MaxTotal < 0) {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Total bounds must not be negative",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if filter.MinTotal != nil && filter.MaxTotal != nil && *filter.MinTotal > *filter.MaxTotal {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "'minTotal' must not be greater than 'maxTotal'",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	return nil
+}
+
+// parseFilterDate parses an optional YYYY-MM-DD filter value, returning the
+// zero time when it is empty.
+func parseFilterDate(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, &ServiceError{
+			Err:     ErrValidation,
+			Message: fmt.Sprintf("Invalid '%s' date, expected YYYY-MM-DD", name),
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	return t, nil
 }
 
 // buildSalesVariantLabel constructs a human-readable label from variant attributes.
@@ -439,8 +819,9 @@ func toProductSearchResult(p models.Product) ProductSearchResult {
 			})
 		}
 
+		// Markup tiers are listed at their derived selling price
 		tiers := make([]VariantPricingTierResult, 0, len(v.PricingTiers))
-		for _, t := range v.PricingTiers {
+		for _, t := range SellingTiers(p.PriceSetting, v.PricingTiers) {
 			tiers = append(tiers, VariantPricingTierResult{
 				MinQty: t.MinQty,
 				Value:  t.Value,
@@ -452,9 +833,11 @@ func toProductSearchResult(p models.Product) ProductSearchResult {
 			SKU:          v.SKU,
 			Barcode:      v.Barcode,
 			CurrentStock: v.CurrentStock,
+			Priced:       len(tiers) > 0,
 			Attributes:   attrs,
 			Images:       varImgs,
 			PricingTiers: tiers,
+			Available:    v.CurrentStock > 0,
 		})
 	}
 
@@ -4,11 +4,13 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/repositories"
 	"github.com/pointofsale/backend/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 
@@ -47,6 +49,32 @@ func TestCheckout_Valid_DeductsStock(t *testing.T) {
 	assert.Equal(t, initialStock-2, updatedVariant.CurrentStock)
 }
 
+func TestCheckout_WithCashier_RecordsCashierOnTransaction(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	cashier := testutil.CreateTestUser(t, db)
+	product := testutil.CreateTestProduct(t, db)
+
+	result, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 1},
+		},
+		CashierID: cashier.ID,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.CashierID)
+	assert.Equal(t, cashier.ID, *result.CashierID)
+
+	var movement models.StockMovement
+	require.NoError(t, db.Where("reference_type = ? AND reference_id = ?", "sales_transaction", result.ID).First(&movement).Error)
+	require.NotNil(t, movement.CreatedBy)
+	assert.Equal(t, cashier.ID, *movement.CreatedBy)
+}
+
 func TestCheckout_InsufficientStock_ReturnsError(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	salesRepo := repositories.NewSalesRepository(db)
@@ -158,6 +186,144 @@ func TestCheckout_UnitConversion_DeductsCorrectBaseQty(t *testing.T) {
 	assert.Equal(t, initialStock-24, updated.CurrentStock)
 }
 
+func TestCheckout_UnitFromOtherProduct_ReturnsValidationBeforeStockMath(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	// The dozen unit belongs to another product; its factor of 12 must not be used
+	withDozen := testutil.CreateTestProductWithUnits(t, db)
+	var dozenUnit models.ProductUnit
+	for _, u := range withDozen.Units {
+		if u.Name == "Dozen" {
+			dozenUnit = u
+			break
+		}
+	}
+	require.NotZero(t, dozenUnit.ID, "Dozen unit not found")
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+
+	result, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: dozenUnit.ID, Quantity: 2},
+		},
+	})
+
+	assert.Nil(t, result)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+	assert.Equal(t, "UNIT_PRODUCT_MISMATCH", svcErr.Code)
+
+	var reloaded models.ProductVariant
+	require.NoError(t, db.First(&reloaded, "id = ?", variant.ID).Error)
+	assert.Equal(t, variant.CurrentStock, reloaded.CurrentStock)
+}
+
+func TestCheckout_VariantFromOtherProduct_ReturnsValidation(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	product := testutil.CreateTestProduct(t, db)
+	other := testutil.CreateTestProduct(t, db)
+
+	_, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: other.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 1},
+		},
+	})
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "VARIANT_PRODUCT_MISMATCH", svcErr.Code)
+}
+
+func TestCheckout_SeveralInvalidReferences_ReportsAll(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+
+	_, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: 999999, VariantID: variant.ID, UnitID: product.Units[0].ID, Quantity: 1},
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: 999999, Quantity: 1},
+		},
+	})
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+	assert.Equal(t, "INVALID_REFERENCES", svcErr.Code)
+	assert.Contains(t, svcErr.Message, "items[0]: product 999999 not found")
+	assert.Contains(t, svcErr.Message, "items[1]: unit 999999 not found")
+
+	var reloaded models.ProductVariant
+	require.NoError(t, db.First(&reloaded, "id = ?", variant.ID).Error)
+	assert.Equal(t, variant.CurrentStock, reloaded.CurrentStock)
+}
+
+func TestCheckout_InactiveProduct_ReturnsValidation(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	product := testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.Status = "inactive"
+	})
+
+	_, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 1},
+		},
+	})
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+	assert.Equal(t, "PRODUCT_INACTIVE", svcErr.Code)
+}
+
+func TestCheckout_SameVariantOnTwoLines_ChecksCombinedStock(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+
+	_, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: unit.ID, Quantity: 60},
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: unit.ID, Quantity: 60},
+		},
+	})
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "INSUFFICIENT_STOCK", svcErr.Code)
+
+	var reloaded models.ProductVariant
+	require.NoError(t, db.First(&reloaded, "id = ?", variant.ID).Error)
+	assert.Equal(t, variant.CurrentStock, reloaded.CurrentStock)
+}
+
 func TestCheckout_TieredPricing_AppliesCorrectTier(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	salesRepo := repositories.NewSalesRepository(db)
@@ -310,6 +476,34 @@ func TestCheckout_CreatesStockMovements_NegativeQuantity(t *testing.T) {
 	assert.Equal(t, result.ID, *movements[0].ReferenceID)
 }
 
+func TestCheckout_RecordsUnitCostPerItem(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", variant.ID).Update("cost_price", 6500).Error)
+
+	result, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: unit.ID, Quantity: 2},
+		},
+	})
+	require.NoError(t, err)
+
+	// Later cost changes do not rewrite the recorded cost
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", variant.ID).Update("cost_price", 9000).Error)
+
+	var item models.SalesTransactionItem
+	require.NoError(t, db.Where("transaction_id = ?", result.ID).First(&item).Error)
+	require.NotNil(t, item.UnitCost)
+	assert.Equal(t, 6500.0, *item.UnitCost)
+}
+
 func TestCheckout_EmptyCart_ReturnsValidation(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	salesRepo := repositories.NewSalesRepository(db)
@@ -431,68 +625,902 @@ func TestCheckout_ConcurrentCheckout_NoOverselling(t *testing.T) {
 	assert.Equal(t, 0, finalVariant.CurrentStock)
 }
 
-func TestProductSearch_ReturnsResults(t *testing.T) {
+func TestCheckout_SerializationFailureOnFirstAttempt_RetriesAndSucceeds(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	salesRepo := repositories.NewSalesRepository(db)
 	seqService := NewSequenceService(db)
-	svc := NewSalesService(db, salesRepo, seqService)
+	svc := NewSalesService(db, salesRepo, seqService, SalesConfig{CheckoutMaxRetries: 2})
 
-	product := testutil.CreateTestProduct(t, db, func(p *models.Product) {
-		p.Name = "Unique SearchTest Product"
-		p.Status = "active"
-	})
-	_ = product
+	attempts := 0
+	svc.beforeCheckoutCommit = func(tx *gorm.DB, attempt int) error {
+		attempts++
+		if attempt == 0 {
+			return &pgconn.PgError{Code: "40001", Message: "could not serialize access due to concurrent update"}
+		}
+		return nil
+	}
 
-	results, err := svc.ProductSearch("SearchTest")
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+
+	result, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: unit.ID, Quantity: 3},
+		},
+	})
 	require.NoError(t, err)
-	require.NotEmpty(t, results)
-	assert.Equal(t, "Unique SearchTest Product", results[0].Name)
+	require.NotNil(t, result)
+	assert.Equal(t, 2, attempts)
+
+	// The failed attempt was rolled back, so stock is deducted exactly once
+	var updatedVariant models.ProductVariant
+	require.NoError(t, db.First(&updatedVariant, "id = ?", variant.ID).Error)
+	assert.Equal(t, variant.CurrentStock-3, updatedVariant.CurrentStock)
+
+	var movementCount int64
+	require.NoError(t, db.Model(&models.StockMovement{}).Where("variant_id = ?", variant.ID).Count(&movementCount).Error)
+	assert.Equal(t, int64(1), movementCount)
 }
 
-func TestProductSearch_TooShort_ReturnsError(t *testing.T) {
+func TestCheckout_SerializationFailureExhaustsRetries_ReturnsError(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	salesRepo := repositories.NewSalesRepository(db)
 	seqService := NewSequenceService(db)
-	svc := NewSalesService(db, salesRepo, seqService)
+	svc := NewSalesService(db, salesRepo, seqService, SalesConfig{CheckoutMaxRetries: 1})
 
-	_, err := svc.ProductSearch("ab") // less than 3 chars
+	attempts := 0
+	svc.beforeCheckoutCommit = func(tx *gorm.DB, attempt int) error {
+		attempts++
+		return &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}
+	}
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+
+	_, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: unit.ID, Quantity: 1},
+		},
+	})
 	require.Error(t, err)
-	serviceErr, ok := err.(*ServiceError)
+	assert.Equal(t, 2, attempts)
+
+	var updatedVariant models.ProductVariant
+	require.NoError(t, db.First(&updatedVariant, "id = ?", variant.ID).Error)
+	assert.Equal(t, variant.CurrentStock, updatedVariant.CurrentStock)
+}
+
+// queuedTrxSequence hands out the queued transaction numbers before falling
+// back to the real sequence, as a concurrent checkout taking a number would.
+type queuedTrxSequence struct {
+	*SequenceService
+	numbers []string
+	calls   int
+}
+
+func (q *queuedTrxSequence) GenerateTrxNumber() (string, error) {
+	q.calls++
+	if len(q.numbers) > 0 {
+		number := q.numbers[0]
+		q.numbers = q.numbers[1:]
+		return number, nil
+	}
+	return q.SequenceService.GenerateTrxNumber()
+}
+
+func TestCheckout_DuplicateTransactionNumber_RetriesWithNewNumber(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seq := &queuedTrxSequence{SequenceService: NewSequenceService(db)}
+	cfg := DefaultSalesConfig()
+	cfg.CheckoutRetryBackoff = 0
+	svc := NewSalesService(db, salesRepo, seq, cfg)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+	input := CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: unit.ID, Quantity: 2},
+		},
+	}
+
+	existing, err := svc.Checkout(input)
+	require.NoError(t, err)
+
+	// Hand out the taken number first
+	seq.calls = 0
+	seq.numbers = []string{existing.TransactionNumber}
+
+	result, err := svc.Checkout(input)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, 2, seq.calls)
+	assert.NotEqual(t, existing.TransactionNumber, result.TransactionNumber)
+
+	// The rejected attempt was rolled back, so stock is deducted once per checkout
+	var updatedVariant models.ProductVariant
+	require.NoError(t, db.First(&updatedVariant, "id = ?", variant.ID).Error)
+	assert.Equal(t, variant.CurrentStock-4, updatedVariant.CurrentStock)
+
+	var txCount int64
+	require.NoError(t, db.Model(&models.SalesTransaction{}).Count(&txCount).Error)
+	assert.Equal(t, int64(2), txCount)
+}
+
+func TestCheckout_DuplicateTransactionNumber_RetriesExhausted_ReturnsConflict(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seq := &queuedTrxSequence{SequenceService: NewSequenceService(db)}
+	cfg := DefaultSalesConfig()
+	cfg.CheckoutMaxRetries = 2
+	cfg.CheckoutRetryBackoff = 0
+	svc := NewSalesService(db, salesRepo, seq, cfg)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+	input := CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: unit.ID, Quantity: 1},
+		},
+	}
+
+	existing, err := svc.Checkout(input)
+	require.NoError(t, err)
+
+	// Every attempt gets the taken number
+	seq.calls = 0
+	seq.numbers = []string{existing.TransactionNumber, existing.TransactionNumber, existing.TransactionNumber}
+
+	_, err = svc.Checkout(input)
+	require.Error(t, err)
+	assert.Equal(t, 3, seq.calls)
+	svcErr, ok := err.(*ServiceError)
 	require.True(t, ok)
-	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, ErrConflict, svcErr.Err)
+	assert.Equal(t, "DUPLICATE_TRANSACTION_NUMBER", svcErr.Code)
+
+	var txCount int64
+	require.NoError(t, db.Model(&models.SalesTransaction{}).Count(&txCount).Error)
+	assert.Equal(t, int64(1), txCount)
 }
 
-func TestProductSearch_InactiveProducts_Excluded(t *testing.T) {
+func TestCheckout_CardSurcharge_AddedToGrandTotal(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	salesRepo := repositories.NewSalesRepository(db)
 	seqService := NewSequenceService(db)
-	svc := NewSalesService(db, salesRepo, seqService)
+	cfg := DefaultSalesConfig()
+	cfg.PaymentSurcharges = map[string]float64{"card": 1}
+	svc := NewSalesService(db, salesRepo, seqService, cfg)
 
-	testutil.CreateTestProduct(t, db, func(p *models.Product) {
-		p.Name = "InactiveProduct XYZ"
-		p.Status = "inactive"
-	})
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
 
-	results, err := svc.ProductSearch("InactiveProduct")
+	// 3 x 10000 = 30000 subtotal, 1% card surcharge = 300
+	result, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "card",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: unit.ID, Quantity: 3},
+		},
+	})
 	require.NoError(t, err)
-	assert.Empty(t, results)
+	assert.Equal(t, 30000.0, result.Subtotal)
+	assert.Equal(t, 300.0, result.SurchargeAmount)
+	assert.Equal(t, 30300.0, result.GrandTotal)
+
+	var saved models.SalesTransaction
+	require.NoError(t, db.First(&saved, result.ID).Error)
+	assert.Equal(t, 300.0, saved.SurchargeAmount)
 }
 
-func TestProductSearch_Max10Results(t *testing.T) {
+func TestCheckout_CashNoSurcharge_GrandTotalEqualsSubtotal(t *testing.T) {
 	db := testutil.SetupTestDB(t)
 	salesRepo := repositories.NewSalesRepository(db)
 	seqService := NewSequenceService(db)
-	svc := NewSalesService(db, salesRepo, seqService)
+	cfg := DefaultSalesConfig()
+	cfg.PaymentSurcharges = map[string]float64{"card": 1}
+	svc := NewSalesService(db, salesRepo, seqService, cfg)
 
-	// Create 12 active products with similar name
-	for i := 0; i < 12; i++ {
-		testutil.CreateTestProduct(t, db, func(p *models.Product) {
-			p.Name = "LimitTest Product Alpha"
-			p.Status = "active"
-		})
-	}
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
 
-	results, err := svc.ProductSearch("LimitTest")
+	result, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: unit.ID, Quantity: 3},
+		},
+	})
 	require.NoError(t, err)
-	assert.LessOrEqual(t, len(results), 10)
+	assert.Equal(t, 0.0, result.SurchargeAmount)
+	assert.Equal(t, result.Subtotal, result.GrandTotal)
+}
+
+func TestPaymentMethods_ListsAllWithConfiguredSurcharge(t *testing.T) {
+	cfg := DefaultSalesConfig()
+	cfg.PaymentSurcharges = map[string]float64{"card": 1.5}
+	svc := NewSalesService(nil, nil, nil, cfg)
+
+	methods := svc.PaymentMethods()
+	require.Len(t, methods, 3)
+	assert.Equal(t, PaymentMethodOption{Method: "cash", SurchargePercent: 0}, methods[0])
+	assert.Equal(t, PaymentMethodOption{Method: "card", SurchargePercent: 1.5}, methods[1])
+	assert.Equal(t, PaymentMethodOption{Method: "qris", SurchargePercent: 0}, methods[2])
+}
+
+func TestCalculateSurcharge_RoundsToCents(t *testing.T) {
+	assert.Equal(t, 0.0, calculateSurcharge(30000, 0))
+	assert.Equal(t, 300.0, calculateSurcharge(30000, 1))
+	assert.Equal(t, 10.37, calculateSurcharge(1234.5, 0.84))
+}
+
+func TestCalculateTax_ExclusiveAndInclusive(t *testing.T) {
+	assert.Equal(t, 0.0, calculateTax(30000, 0, false))
+	assert.Equal(t, 0.0, calculateTax(30000, 0, true))
+	// Exclusive: 11% on top of the amount
+	assert.Equal(t, 11000.0, calculateTax(100000, 11, false))
+	// Inclusive: the part of 111000 above 111000 / 1.11
+	assert.Equal(t, 11000.0, calculateTax(111000, 11, true))
+	assert.Equal(t, 1122.27, calculateTax(12345, 10, true))
+}
+
+func newTaxTestService(t *testing.T, db *gorm.DB, inclusive bool) *SalesService {
+	t.Helper()
+	cfg := DefaultSalesConfig()
+	cfg.TaxPercent = 11
+	cfg.TaxInclusive = inclusive
+	cfg.PaymentSurcharges = map[string]float64{"card": 1}
+	return NewSalesService(db, repositories.NewSalesRepository(db), NewSequenceService(db), cfg)
+}
+
+func taxCheckoutInput(product *models.Product, paymentMethod string) CheckoutInput {
+	return CheckoutInput{
+		PaymentMethod: paymentMethod,
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 3},
+		},
+		CashierIsSuperAdmin: true,
+	}
+}
+
+func TestCheckout_ExclusiveTax_AddsToGrandTotal(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newTaxTestService(t, db, false)
+	product := testutil.CreateTestProduct(t, db)
+
+	input := taxCheckoutInput(product, "card")
+	input.Discount = 3000
+	result, err := svc.Checkout(input)
+	require.NoError(t, err)
+
+	// (30000 - 3000) + 11% tax, then a 1% card surcharge on the amount due
+	assert.Equal(t, 30000.0, result.Subtotal)
+	assert.Equal(t, 11.0, result.TaxPercent)
+	assert.False(t, result.TaxInclusive)
+	assert.Equal(t, 2970.0, result.TaxAmount)
+	assert.Equal(t, 299.7, result.SurchargeAmount)
+	assert.Equal(t, 30269.7, result.GrandTotal)
+
+	saved, err := svc.GetTransaction(result.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2970.0, saved.TaxAmount)
+	assert.Equal(t, 11.0, saved.TaxPercent)
+	assert.Equal(t, 30269.7, saved.GrandTotal)
+}
+
+func TestCheckout_InclusiveTax_CarvedOutOfGrandTotal(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newTaxTestService(t, db, true)
+	product := testutil.CreateTestProduct(t, db)
+
+	input := taxCheckoutInput(product, "cash")
+	input.Discount = 3000
+	result, err := svc.Checkout(input)
+	require.NoError(t, err)
+
+	// Prices include the tax: 27000 due, of which 27000 * 11/111 is tax
+	assert.Equal(t, 30000.0, result.Subtotal)
+	assert.True(t, result.TaxInclusive)
+	assert.Equal(t, 2675.68, result.TaxAmount)
+	assert.Equal(t, 27000.0, result.GrandTotal)
+
+	saved, err := svc.GetTransaction(result.ID)
+	require.NoError(t, err)
+	assert.True(t, saved.TaxInclusive)
+	assert.Equal(t, 2675.68, saved.TaxAmount)
+}
+
+func TestCheckout_TaxPercentOverride_ReplacesConfiguredRate(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newTaxTestService(t, db, false)
+	product := testutil.CreateTestProduct(t, db)
+
+	input := taxCheckoutInput(product, "cash")
+	input.CashierIsSuperAdmin = true
+	zero := 0.0
+	input.TaxPercent = &zero
+	result, err := svc.Checkout(input)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, result.TaxPercent)
+	assert.Equal(t, 0.0, result.TaxAmount)
+	assert.Equal(t, 30000.0, result.GrandTotal)
+
+	five := 5.0
+	input.TaxPercent = &five
+	result, err = svc.Checkout(input)
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, result.TaxPercent)
+	assert.Equal(t, 1500.0, result.TaxAmount)
+	assert.Equal(t, 31500.0, result.GrandTotal)
+}
+
+func TestCheckout_InvalidTaxPercent_ReturnsFieldError(t *testing.T) {
+	svc := NewSalesService(nil, nil, nil, DefaultSalesConfig())
+
+	for _, percent := range []float64{-1, 100.5} {
+		taxPercent := percent
+		_, err := svc.Checkout(CheckoutInput{
+			PaymentMethod: "cash",
+			Items:         []CheckoutItemInput{{ProductID: 1, Quantity: 1}},
+			TaxPercent:    &taxPercent,
+		})
+		require.Error(t, err)
+		serviceErr, ok := err.(*ServiceError)
+		require.True(t, ok)
+		assert.Equal(t, ErrValidation, serviceErr.Err)
+		assert.Equal(t, "taxPercent must be between 0 and 100", serviceErr.Message)
+	}
+}
+
+func TestCheckout_TaxPercentOverrideByCashier_ReturnsForbidden(t *testing.T) {
+	cfg := DefaultSalesConfig()
+	cfg.TaxPercent = 11
+	svc := NewSalesService(nil, nil, nil, cfg)
+
+	zero := 0.0
+	_, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items:         []CheckoutItemInput{{ProductID: 1, Quantity: 1}},
+		TaxPercent:    &zero,
+		CashierID:     7,
+	})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrForbidden, serviceErr.Err)
+	assert.Equal(t, "TAX_OVERRIDE_FORBIDDEN", serviceErr.Code)
+}
+
+func TestCheckout_VariantWithoutPricingTier_ReturnsNoPrice(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+	require.NoError(t, db.Where("variant_id = ?", variant.ID).Delete(&models.VariantPricingTier{}).Error)
+
+	_, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: unit.ID, Quantity: 1},
+		},
+	})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "NO_PRICE", serviceErr.Code)
+
+	// Stock is untouched
+	var reloaded models.ProductVariant
+	require.NoError(t, db.First(&reloaded, "id = ?", variant.ID).Error)
+	assert.Equal(t, variant.CurrentStock, reloaded.CurrentStock)
+}
+
+func TestCheckout_PricingTierNotRequired_SellsAtZero(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	cfg := DefaultSalesConfig()
+	cfg.RequirePricingTier = false
+	svc := NewSalesService(db, salesRepo, seqService, cfg)
+
+	product := testutil.CreateTestProduct(t, db)
+	variant := product.Variants[0]
+	unit := product.Units[0]
+	require.NoError(t, db.Where("variant_id = ?", variant.ID).Delete(&models.VariantPricingTier{}).Error)
+
+	result, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: variant.ID, UnitID: unit.ID, Quantity: 1},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, result.GrandTotal)
+}
+
+func TestCheckout_TooManyItems_ReturnsValidation(t *testing.T) {
+	cfg := DefaultSalesConfig()
+	cfg.MaxCartItems = 2
+	svc := NewSalesService(nil, nil, nil, cfg)
+
+	items := make([]CheckoutItemInput, 3)
+	for i := range items {
+		items[i] = CheckoutItemInput{ProductID: uint(i + 1), Quantity: 1}
+	}
+
+	result, err := svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: items})
+	assert.Nil(t, result)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "TOO_MANY_ITEMS", serviceErr.Code)
+	assert.Contains(t, serviceErr.Message, "2 items")
+}
+
+func TestCheckout_NegativeQuantity_ReturnsFieldError(t *testing.T) {
+	svc := NewSalesService(nil, nil, nil, DefaultSalesConfig())
+
+	result, err := svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{
+		{ProductID: 1, Quantity: 2},
+		{ProductID: 2, Quantity: -1},
+	}})
+	assert.Nil(t, result)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "items[1].quantity must be greater than zero", serviceErr.Message)
+}
+
+func TestCheckout_NegativeDiscount_ReturnsFieldError(t *testing.T) {
+	svc := NewSalesService(nil, nil, nil, DefaultSalesConfig())
+
+	_, err := svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{
+		{ProductID: 1, Quantity: 1, DiscountPercent: -5},
+	}})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "items[0].discountPercent must be between 0 and 100", serviceErr.Message)
+}
+
+func TestCheckout_InvalidAmountDiscounts_ReturnFieldErrors(t *testing.T) {
+	svc := NewSalesService(nil, nil, nil, DefaultSalesConfig())
+
+	cases := []struct {
+		input   CheckoutInput
+		message string
+	}{
+		{
+			CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{{ProductID: 1, Quantity: 1, DiscountAmount: -100}}},
+			"items[0].discountAmount must not be negative",
+		},
+		{
+			CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{{ProductID: 1, Quantity: 1, DiscountPercent: 5, DiscountAmount: 100}}},
+			"items[0] can have either discountPercent or discountAmount, not both",
+		},
+		{
+			CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{{ProductID: 1, Quantity: 1}}, Discount: -1},
+			"discount must not be negative",
+		},
+	}
+	for _, tc := range cases {
+		_, err := svc.Checkout(tc.input)
+		require.Error(t, err)
+		serviceErr, ok := err.(*ServiceError)
+		require.True(t, ok)
+		assert.Equal(t, ErrValidation, serviceErr.Err)
+		assert.Equal(t, tc.message, serviceErr.Message)
+	}
+}
+
+func TestCheckout_ItemsAtCap_Succeeds(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	cfg := DefaultSalesConfig()
+	cfg.MaxCartItems = 2
+	svc := NewSalesService(db, salesRepo, seqService, cfg)
+
+	first := testutil.CreateTestProduct(t, db)
+	second := testutil.CreateTestProduct(t, db)
+
+	result, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: first.ID, VariantID: first.Variants[0].ID, UnitID: first.Units[0].ID, Quantity: 1},
+			{ProductID: second.ID, VariantID: second.Variants[0].ID, UnitID: second.Units[0].ID, Quantity: 1},
+		},
+	})
+	require.NoError(t, err)
+	assert.Len(t, result.Items, 2)
+}
+
+func TestProductSearch_VariantWithoutPricingTier_StillListedAsUnpriced(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	product := testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.Name = "Unpriced Widget"
+	})
+	variant := product.Variants[0]
+	require.NoError(t, db.Where("variant_id = ?", variant.ID).Delete(&models.VariantPricingTier{}).Error)
+
+	results, err := svc.ProductSearch("Unpriced Widget")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Variants, 1)
+	assert.False(t, results[0].Variants[0].Priced)
+	assert.Empty(t, results[0].Variants[0].PricingTiers)
+}
+
+func TestProductSearch_HideZeroStock_ExcludesOutOfStockVariant(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService, SalesConfig{ZeroStockPolicy: ZeroStockHide})
+
+	inStock := testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.Name = "ZeroStockPolicy Kept"
+	})
+	soldOut := testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.Name = "ZeroStockPolicy Gone"
+	})
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", soldOut.Variants[0].ID).Update("current_stock", 0).Error)
+
+	results, err := svc.ProductSearch("ZeroStockPolicy")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, inStock.ID, results[0].ID)
+	require.Len(t, results[0].Variants, 1)
+	assert.True(t, results[0].Variants[0].Available)
+}
+
+func TestProductSearch_ShowZeroStock_FlagsOutOfStockVariantUnavailable(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService, SalesConfig{ZeroStockPolicy: ZeroStockShow})
+
+	soldOut := testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.Name = "ZeroStockShown Widget"
+	})
+	require.NoError(t, db.Model(&models.ProductVariant{}).Where("id = ?", soldOut.Variants[0].ID).Update("current_stock", 0).Error)
+
+	results, err := svc.ProductSearch("ZeroStockShown")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Len(t, results[0].Variants, 1)
+	assert.Equal(t, 0, results[0].Variants[0].CurrentStock)
+	assert.False(t, results[0].Variants[0].Available)
+}
+
+func TestProductSearch_ReturnsResults(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	product := testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.Name = "Unique SearchTest Product"
+		p.Status = "active"
+	})
+	_ = product
+
+	results, err := svc.ProductSearch("SearchTest")
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "Unique SearchTest Product", results[0].Name)
+}
+
+func TestProductSearch_TooShort_ReturnsError(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	_, err := svc.ProductSearch("ab") // less than 3 chars
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+}
+
+func TestProductSearch_InactiveProducts_Excluded(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	testutil.CreateTestProduct(t, db, func(p *models.Product) {
+		p.Name = "InactiveProduct XYZ"
+		p.Status = "inactive"
+	})
+
+	results, err := svc.ProductSearch("InactiveProduct")
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestProductSearch_Max10Results(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	salesRepo := repositories.NewSalesRepository(db)
+	seqService := NewSequenceService(db)
+	svc := NewSalesService(db, salesRepo, seqService)
+
+	// Create 12 active products with similar name
+	for i := 0; i < 12; i++ {
+		testutil.CreateTestProduct(t, db, func(p *models.Product) {
+			p.Name = "LimitTest Product Alpha"
+			p.Status = "active"
+		})
+	}
+
+	results, err := svc.ProductSearch("LimitTest")
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(results), 10)
+}
+
+func newDiscountTestService(t *testing.T, db *gorm.DB) *SalesService {
+	t.Helper()
+	cfg := DefaultSalesConfig()
+	cfg.MaxDiscountPercent = 2
+	cfg.RoleMaxDiscounts = map[string]float64{"cashier": 5, "manager": 20}
+	return NewSalesService(db, repositories.NewSalesRepository(db), NewSequenceService(db), cfg)
+}
+
+func createDiscountTestCashier(t *testing.T, db *gorm.DB, roleNames ...string) *models.User {
+	t.Helper()
+	user := testutil.CreateTestUser(t, db)
+	for _, name := range roleNames {
+		role := testutil.CreateTestRole(t, db, func(r *models.Role) { r.Name = name })
+		require.NoError(t, db.Exec("INSERT INTO user_roles (user_id, role_id) VALUES (?, ?)", user.ID, role.ID).Error)
+	}
+	return user
+}
+
+func discountCheckoutInput(product *models.Product, cashierID uint, discount float64) CheckoutInput {
+	return CheckoutInput{
+		PaymentMethod: "cash",
+		Items: []CheckoutItemInput{
+			{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 2, DiscountPercent: discount},
+		},
+		CashierID: cashierID,
+	}
+}
+
+func TestCheckout_Discount_ReducesLinePrice(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newDiscountTestService(t, db)
+
+	cashier := createDiscountTestCashier(t, db, "Cashier")
+	product := testutil.CreateTestProduct(t, db)
+
+	// 2 x 10000 at 5% off = 2 x 9500
+	result, err := svc.Checkout(discountCheckoutInput(product, cashier.ID, 5))
+	require.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, 9500.0, result.Items[0].UnitPrice)
+	assert.Equal(t, 19000.0, result.Items[0].TotalPrice)
+	assert.Equal(t, 5.0, result.Items[0].DiscountPercent)
+	assert.Equal(t, 1000.0, result.Items[0].DiscountAmount)
+	assert.Equal(t, 19000.0, result.Subtotal)
+	assert.Equal(t, 19000.0, result.GrandTotal)
+}
+
+func TestCheckout_ItemAndSaleDiscounts_ComputesTotals(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newDiscountTestService(t, db)
+
+	manager := createDiscountTestCashier(t, db, "Manager")
+	product := testutil.CreateTestProduct(t, db)
+	line := CheckoutItemInput{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID}
+
+	amountLine, percentLine := line, line
+	amountLine.Quantity, amountLine.DiscountAmount = 2, 1500
+	percentLine.Quantity, percentLine.DiscountPercent = 1, 10
+
+	result, err := svc.Checkout(CheckoutInput{
+		PaymentMethod: "cash",
+		Items:         []CheckoutItemInput{amountLine, percentLine},
+		Discount:      2500,
+		CashierID:     manager.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Items, 2)
+
+	// 2 x 10000 less 1500
+	assert.Equal(t, 18500.0, result.Items[0].TotalPrice)
+	assert.Equal(t, 9250.0, result.Items[0].UnitPrice)
+	assert.Equal(t, 1500.0, result.Items[0].DiscountAmount)
+	assert.Equal(t, 7.5, result.Items[0].DiscountPercent)
+	// 1 x 10000 at 10% off
+	assert.Equal(t, 9000.0, result.Items[1].TotalPrice)
+	assert.Equal(t, 1000.0, result.Items[1].DiscountAmount)
+
+	assert.Equal(t, 27500.0, result.Subtotal)
+	assert.Equal(t, 2500.0, result.Discount)
+	assert.Equal(t, 25000.0, result.GrandTotal)
+
+	var stored models.SalesTransaction
+	require.NoError(t, db.First(&stored, result.ID).Error)
+	assert.Equal(t, 2500.0, stored.Discount)
+	assert.Equal(t, 25000.0, stored.GrandTotal)
+}
+
+func TestCheckout_DiscountsAboveTotals_ReturnValidation(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newDiscountTestService(t, db)
+
+	product := testutil.CreateTestProduct(t, db)
+	line := CheckoutItemInput{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 2}
+
+	overLine := line
+	overLine.DiscountAmount = 20001
+	_, err := svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{overLine}, CashierIsSuperAdmin: true})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Contains(t, serviceErr.Message, "items[0].discountAmount must not exceed the line total")
+
+	_, err = svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{line}, Discount: 20001, CashierIsSuperAdmin: true})
+	require.Error(t, err)
+	serviceErr, ok = err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Contains(t, serviceErr.Message, "discount must not exceed the subtotal")
+
+	// The rejected checkouts leave stock untouched
+	var updated models.ProductVariant
+	require.NoError(t, db.First(&updated, "id = ?", product.Variants[0].ID).Error)
+	assert.Equal(t, product.Variants[0].CurrentStock, updated.CurrentStock)
+}
+
+func TestCheckout_AmountDiscountAboveCeiling_ReturnsForbidden(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newDiscountTestService(t, db)
+
+	cashier := createDiscountTestCashier(t, db, "Cashier")
+	product := testutil.CreateTestProduct(t, db)
+	line := CheckoutItemInput{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 2}
+
+	// 2000 off 20000 is 10%, above the cashier's 5%
+	amountLine := line
+	amountLine.DiscountAmount = 2000
+	_, err := svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{amountLine}, CashierID: cashier.ID})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "DISCOUNT_LIMIT_EXCEEDED", serviceErr.Code)
+
+	_, err = svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{line}, Discount: 2000, CashierID: cashier.ID})
+	require.Error(t, err)
+	serviceErr, ok = err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "DISCOUNT_LIMIT_EXCEEDED", serviceErr.Code)
+
+	_, err = svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{line}, Discount: 1000, CashierID: cashier.ID})
+	require.NoError(t, err)
+}
+
+func TestCheckout_LineAndSaleDiscountsCombinedAboveCeiling_ReturnsForbidden(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newDiscountTestService(t, db)
+
+	cashier := createDiscountTestCashier(t, db, "Cashier")
+	product := testutil.CreateTestProduct(t, db)
+	line := CheckoutItemInput{ProductID: product.ID, VariantID: product.Variants[0].ID, UnitID: product.Units[0].ID, Quantity: 2}
+
+	// 4% off the line and then 2% off the sale is 5.92% off, above the
+	// cashier's 5% though each is below it
+	line.DiscountPercent = 4
+	_, err := svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{line}, Discount: 384, CashierID: cashier.ID})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "DISCOUNT_LIMIT_EXCEEDED", serviceErr.Code)
+
+	// 3% and then 2% is 4.94% off
+	line.DiscountPercent = 3
+	_, err = svc.Checkout(CheckoutInput{PaymentMethod: "cash", Items: []CheckoutItemInput{line}, Discount: 388, CashierID: cashier.ID})
+	require.NoError(t, err)
+}
+
+func TestCheckout_CashierRoleCappedLowerThanManager(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newDiscountTestService(t, db)
+
+	cashier := createDiscountTestCashier(t, db, "Cashier")
+	manager := createDiscountTestCashier(t, db, "Manager")
+	product := testutil.CreateTestProduct(t, db)
+
+	_, err := svc.Checkout(discountCheckoutInput(product, cashier.ID, 10))
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrForbidden, serviceErr.Err)
+	assert.Equal(t, "DISCOUNT_LIMIT_EXCEEDED", serviceErr.Code)
+
+	_, err = svc.Checkout(discountCheckoutInput(product, manager.ID, 10))
+	require.NoError(t, err)
+}
+
+func TestCheckout_DiscountAboveHighestRoleCeiling_ReturnsForbidden(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newDiscountTestService(t, db)
+
+	user := createDiscountTestCashier(t, db, "Cashier", "Manager")
+	product := testutil.CreateTestProduct(t, db)
+
+	_, err := svc.Checkout(discountCheckoutInput(product, user.ID, 20))
+	require.NoError(t, err)
+
+	_, err = svc.Checkout(discountCheckoutInput(product, user.ID, 25))
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "DISCOUNT_LIMIT_EXCEEDED", serviceErr.Code)
+
+	// The rejected checkout leaves stock untouched
+	var updated models.ProductVariant
+	require.NoError(t, db.First(&updated, "id = ?", product.Variants[0].ID).Error)
+	assert.Equal(t, product.Variants[0].CurrentStock-2, updated.CurrentStock)
+}
+
+func TestCheckout_UnlistedRole_UsesGlobalCeiling(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newDiscountTestService(t, db)
+
+	user := createDiscountTestCashier(t, db, "Stocker")
+	product := testutil.CreateTestProduct(t, db)
+
+	_, err := svc.Checkout(discountCheckoutInput(product, user.ID, 2))
+	require.NoError(t, err)
+
+	_, err = svc.Checkout(discountCheckoutInput(product, user.ID, 3))
+	require.Error(t, err)
+}
+
+func TestCheckout_SuperAdmin_DiscountUncapped(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newDiscountTestService(t, db)
+
+	admin := testutil.CreateTestUser(t, db)
+	product := testutil.CreateTestProduct(t, db)
+
+	input := discountCheckoutInput(product, admin.ID, 100)
+	input.CashierIsSuperAdmin = true
+	result, err := svc.Checkout(input)
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, result.Subtotal)
+}
+
+func TestCheckout_DiscountOutOfRange_ReturnsValidation(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	svc := newDiscountTestService(t, db)
+
+	product := testutil.CreateTestProduct(t, db)
+
+	_, err := svc.Checkout(discountCheckoutInput(product, 0, -1))
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
 }
@@ -19,6 +19,39 @@ func NewSequenceService(db *gorm.DB) *SequenceService {
 	return &SequenceService{db: db}
 }
 
+// SequenceKind names a document number series that can be previewed with Peek.
+type SequenceKind string
+
+const (
+	// SequencePurchaseOrder is the PO series numbered by GeneratePONumber.
+	SequencePurchaseOrder SequenceKind = "purchase_order"
+	// SequenceReservedPurchaseOrder is the PO series numbered by ReservePONumber.
+	SequenceReservedPurchaseOrder SequenceKind = "purchase_order_reserved"
+	// SequenceSalesTransaction is the sales transaction series.
+	SequenceSalesTransaction SequenceKind = "sales_transaction"
+)
+
+// NextNumber previews the number the next document of a series will get.
+type NextNumber struct {
+	Number string `json:"number"`
+}
+
+// Peek returns the number the next document of kind would get, without
+// reserving it or touching any counter. A concurrent create may still take the
+// number first, so the result is only a preview.
+func (s *SequenceService) Peek(kind SequenceKind) (string, error) {
+	switch kind {
+	case SequencePurchaseOrder:
+		return s.GeneratePONumber()
+	case SequenceReservedPurchaseOrder:
+		return s.peekReservedPONumber()
+	case SequenceSalesTransaction:
+		return s.GenerateTrxNumber()
+	default:
+		return "", fmt.Errorf("unknown sequence kind %q", kind)
+	}
+}
+
 // GeneratePONumber generates the next PO number in format PO-YYYY-NNNN.
 func (s *SequenceService) GeneratePONumber() (string, error) {
 	year := time.Now().Year()
@@ -46,6 +79,57 @@ func (s *SequenceService) GeneratePONumber() (string, error) {
 	return formatPONumber(year, nextSeq), nil
 }
 
+// poNumberSequence is the number_sequences name used for PO numbers.
+const poNumberSequence = "purchase_order"
+
+// ReservePONumber reserves the next PO number in format PO-YYYY-NNNN using the
+// number_sequences counter. It must run in the transaction that inserts the PO:
+// the counter row stays locked until commit and a rollback releases the
+// number, so numbering stays gap-free. The counter never falls behind POs that
+// were numbered by GeneratePONumber.
+func (s *SequenceService) ReservePONumber(tx *gorm.DB) (string, error) {
+	year := time.Now().Year()
+	prefix := fmt.Sprintf("PO-%d-", year)
+
+	var nextSeq int
+	err := tx.Raw(`
+		INSERT INTO number_sequences (name, year, last_value)
+		SELECT ?, ?, COALESCE(MAX(CAST(split_part(po_number, '-', 3) AS INTEGER)), 0) + 1
+		FROM purchase_orders WHERE po_number LIKE ?
+		ON CONFLICT (name, year) DO UPDATE
+		SET last_value = GREATEST(number_sequences.last_value, EXCLUDED.last_value - 1) + 1
+		RETURNING last_value`,
+		poNumberSequence, year, prefix+"%",
+	).Scan(&nextSeq).Error
+	if err != nil {
+		return "", err
+	}
+
+	return formatPONumber(year, nextSeq), nil
+}
+
+// peekReservedPONumber reads the number ReservePONumber would hand out next,
+// mirroring its counter arithmetic without writing the counter row.
+func (s *SequenceService) peekReservedPONumber() (string, error) {
+	year := time.Now().Year()
+	prefix := fmt.Sprintf("PO-%d-", year)
+
+	var nextSeq int
+	err := s.db.Raw(`
+		SELECT GREATEST(
+			COALESCE((SELECT last_value FROM number_sequences WHERE name = ? AND year = ?), 0),
+			COALESCE(MAX(CAST(split_part(po_number, '-', 3) AS INTEGER)), 0)
+		) + 1
+		FROM purchase_orders WHERE po_number LIKE ?`,
+		poNumberSequence, year, prefix+"%",
+	).Scan(&nextSeq).Error
+	if err != nil {
+		return "", err
+	}
+
+	return formatPONumber(year, nextSeq), nil
+}
+
 // GenerateTrxNumber generates the next transaction number in format TRX-YYYY-NNNNNN.
 func (s *SequenceService) GenerateTrxNumber() (string, error) {
 	year := time.Now().Year()
@@ -73,6 +157,58 @@ func (s *SequenceService) GenerateTrxNumber() (string, error) {
 	return formatTrxNumber(year, nextSeq), nil
 }
 
+// returnNumberSequence is the number_sequences name used for return numbers.
+const returnNumberSequence = "sales_return"
+
+// ReserveReturnNumber reserves the next sales return number in format
+// RTN-YYYY-NNNNNN using the number_sequences counter. Like ReservePONumber it
+// must run in the transaction that inserts the return: the counter row stays
+// locked until commit, so concurrent returns never get the same number.
+func (s *SequenceService) ReserveReturnNumber(tx *gorm.DB) (string, error) {
+	year := time.Now().Year()
+	prefix := fmt.Sprintf("RTN-%d-", year)
+
+	var nextSeq int
+	err := tx.Raw(`
+		INSERT INTO number_sequences (name, year, last_value)
+		SELECT ?, ?, COALESCE(MAX(CAST(split_part(return_number, '-', 3) AS INTEGER)), 0) + 1
+		FROM sales_returns WHERE return_number LIKE ?
+		ON CONFLICT (name, year) DO UPDATE
+		SET last_value = GREATEST(number_sequences.last_value, EXCLUDED.last_value - 1) + 1
+		RETURNING last_value`,
+		returnNumberSequence, year, prefix+"%",
+	).Scan(&nextSeq).Error
+	if err != nil {
+		return "", err
+	}
+
+	return formatReturnNumber(year, nextSeq), nil
+}
+
+// skuSequencePrefix namespaces the number_sequences rows used for generated
+// SKUs; the SKU prefix follows it and the year column is unused.
+const skuSequencePrefix = "sku:"
+
+// NextSKU reserves the next SKU for prefix in format PREFIX-NNNNNN using the
+// number_sequences counter. Like ReservePONumber it must run in the
+// transaction that stores the variant so a rollback releases the number.
+func (s *SequenceService) NextSKU(tx *gorm.DB, prefix string) (string, error) {
+	var nextSeq int
+	err := tx.Raw(`
+		INSERT INTO number_sequences (name, year, last_value)
+		VALUES (?, 0, 1)
+		ON CONFLICT (name, year) DO UPDATE
+		SET last_value = number_sequences.last_value + 1
+		RETURNING last_value`,
+		skuSequencePrefix+prefix,
+	).Scan(&nextSeq).Error
+	if err != nil {
+		return "", err
+	}
+
+	return formatSKU(prefix, nextSeq), nil
+}
+
 func formatPONumber(year, seq int) string {
 	return fmt.Sprintf("PO-%d-%04d", year, seq)
 }
@@ -80,3 +216,11 @@ func formatPONumber(year, seq int) string {
 func formatTrxNumber(year, seq int) string {
 	return fmt.Sprintf("TRX-%d-%06d", year, seq)
 }
+
+func formatReturnNumber(year, seq int) string {
+	return fmt.Sprintf("RTN-%d-%06d", year, seq)
+}
+
+func formatSKU(prefix string, seq int) string {
+	return fmt.Sprintf("%s-%06d", prefix, seq)
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 
+	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/testutil"
 )
 
@@ -81,6 +83,133 @@ func TestGenerateTrxNumber_Increment_Works(t *testing.T) {
 	assert.Equal(t, expected, trxNumber)
 }
 
+func TestReservePONumber_ExistingPOs_ContinuesSequence(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+
+	year := time.Now().Year()
+	createPOWithNumber(t, db, formatPONumber(year, 3))
+
+	seq := NewSequenceService(db)
+	first, err := seq.ReservePONumber(db)
+	require.NoError(t, err)
+	second, err := seq.ReservePONumber(db)
+	require.NoError(t, err)
+
+	assert.Equal(t, formatPONumber(year, 4), first)
+	assert.Equal(t, formatPONumber(year, 5), second)
+}
+
+func TestReservePONumber_RolledBack_ReleasesNumber(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	seq := NewSequenceService(db)
+	year := time.Now().Year()
+
+	errRollback := errors.New("rollback")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		poNumber, err := seq.ReservePONumber(tx)
+		require.NoError(t, err)
+		assert.Equal(t, formatPONumber(year, 1), poNumber)
+		return errRollback
+	})
+	require.ErrorIs(t, err, errRollback)
+
+	poNumber, err := seq.ReservePONumber(db)
+	require.NoError(t, err)
+	assert.Equal(t, formatPONumber(year, 1), poNumber)
+}
+
+func TestReserveReturnNumber_ExistingReturns_ContinuesSequence(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+
+	year := time.Now().Year()
+	sale := &models.SalesTransaction{TransactionNumber: formatTrxNumber(year, 999999), Date: time.Now(), PaymentMethod: "cash"}
+	require.NoError(t, db.Create(sale).Error)
+	require.NoError(t, db.Create(&models.SalesReturn{
+		ReturnNumber:  formatReturnNumber(year, 7),
+		TransactionID: sale.ID,
+		Date:          time.Now(),
+		RefundMethod:  "cash",
+	}).Error)
+
+	seq := NewSequenceService(db)
+	first, err := seq.ReserveReturnNumber(db)
+	require.NoError(t, err)
+	second, err := seq.ReserveReturnNumber(db)
+	require.NoError(t, err)
+
+	assert.Equal(t, formatReturnNumber(year, 8), first)
+	assert.Equal(t, formatReturnNumber(year, 9), second)
+}
+
+func TestPeek_PurchaseOrder_IsStableAndMatchesGenerated(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+
+	year := time.Now().Year()
+	createPOWithNumber(t, db, formatPONumber(year, 7))
+
+	seq := NewSequenceService(db)
+	first, err := seq.Peek(SequencePurchaseOrder)
+	require.NoError(t, err)
+	second, err := seq.Peek(SequencePurchaseOrder)
+	require.NoError(t, err)
+	assert.Equal(t, formatPONumber(year, 8), first)
+	assert.Equal(t, first, second)
+
+	generated, err := seq.GeneratePONumber()
+	require.NoError(t, err)
+	assert.Equal(t, first, generated)
+}
+
+func TestPeek_ReservedPurchaseOrder_DoesNotConsumeCounter(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+	seq := NewSequenceService(db)
+	year := time.Now().Year()
+
+	// Advance the counter past the highest stored PO, as happens after a
+	// gap-free PO is deleted.
+	_, err := seq.ReservePONumber(db)
+	require.NoError(t, err)
+	_, err = seq.ReservePONumber(db)
+	require.NoError(t, err)
+	createPOWithNumber(t, db, formatPONumber(year, 1))
+
+	for i := 0; i < 3; i++ {
+		peeked, err := seq.Peek(SequenceReservedPurchaseOrder)
+		require.NoError(t, err)
+		assert.Equal(t, formatPONumber(year, 3), peeked)
+	}
+
+	reserved, err := seq.ReservePONumber(db)
+	require.NoError(t, err)
+	assert.Equal(t, formatPONumber(year, 3), reserved)
+}
+
+func TestPeek_SalesTransaction_IsStableAndMatchesGenerated(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+
+	year := time.Now().Year()
+	createTrxWithNumber(t, db, formatTrxNumber(year, 41))
+
+	seq := NewSequenceService(db)
+	first, err := seq.Peek(SequenceSalesTransaction)
+	require.NoError(t, err)
+	second, err := seq.Peek(SequenceSalesTransaction)
+	require.NoError(t, err)
+	assert.Equal(t, formatTrxNumber(year, 42), first)
+	assert.Equal(t, first, second)
+
+	generated, err := seq.GenerateTrxNumber()
+	require.NoError(t, err)
+	assert.Equal(t, first, generated)
+}
+
+func TestPeek_UnknownKind_ReturnsError(t *testing.T) {
+	seq := NewSequenceService(nil)
+
+	_, err := seq.Peek(SequenceKind("invoice"))
+	assert.ErrorContains(t, err, `unknown sequence kind "invoice"`)
+}
+
 // helpers
 func createPOWithNumber(t *testing.T, db *gorm.DB, poNumber string) {
 	t.Helper()
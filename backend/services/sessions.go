@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxUserAgentLength caps the user agent stored with a session.
+const maxUserAgentLength = 255
+
+// refreshSession is the value stored under refresh:<jti> for each active
+// refresh token.
+type refreshSession struct {
+	UserID    uint      `json:"userId"`
+	IssuedAt  time.Time `json:"issuedAt"`
+	UserAgent string    `json:"userAgent,omitempty"`
+}
+
+// Session describes an active login of a user.
+type Session struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UserAgent string    `json:"userAgent,omitempty"`
+}
+
+// storeRefreshToken records an issued refresh token so it can be used, listed
+// and revoked until it expires.
+func (s *AuthService) storeRefreshToken(ctx context.Context, jti string, userID uint, userAgent string) {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	value, err := json.Marshal(refreshSession{
+		UserID:    userID,
+		IssuedAt:  time.Now().UTC(),
+		UserAgent: userAgent,
+	})
+	if err != nil {
+		return
+	}
+	s.redis.Set(ctx, "refresh:"+jti, value, s.config.JWTRefreshExpiry)
+}
+
+// parseRefreshSession decodes a stored refresh token value. Tokens issued
+// before sessions were tracked hold just the user ID.
+func parseRefreshSession(value string) (refreshSession, bool) {
+	var session refreshSession
+	if err := json.Unmarshal([]byte(value), &session); err == nil && session.UserID != 0 {
+		return session, true
+	}
+	if id, err := strconv.ParseUint(value, 10, 64); err == nil {
+		return refreshSession{UserID: uint(id)}, true
+	}
+	return refreshSession{}, false
+}
+
+// forEachRefreshSession calls fn with the jti and session of every stored
+// refresh token belonging to the user.
+func forEachRefreshSession(ctx context.Context, rdb *redis.Client, userID uint, fn func(jti string, session refreshSession)) {
+	iter := rdb.Scan(ctx, 0, "refresh:*", 0).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		val, err := rdb.Get(ctx, key).Result()
+		if err != nil {
+			continue
+		}
+		if session, ok := parseRefreshSession(val); ok && session.UserID == userID {
+			fn(key[len("refresh:"):], session)
+		}
+	}
+}
+
+// revokeRefreshTokens deletes every stored refresh token belonging to the user,
+// except the one with exceptJTI if given, and returns how many were removed.
+// Revoked tokens are blacklisted for the rest of their lifetime so using them
+// is not mistaken for token reuse.
+func revokeRefreshTokens(ctx context.Context, rdb *redis.Client, userID uint, exceptJTI string) int {
+	revoked := 0
+	forEachRefreshSession(ctx, rdb, userID, func(jti string, _ refreshSession) {
+		if exceptJTI != "" && jti == exceptJTI {
+			return
+		}
+		if ttl, err := rdb.TTL(ctx, "refresh:"+jti).Result(); err == nil && ttl > 0 {
+			rdb.Set(ctx, "blacklist:"+jti, "1", ttl)
+		}
+		if n, err := rdb.Del(ctx, "refresh:"+jti).Result(); err == nil {
+			revoked += int(n)
+		}
+	})
+	return revoked
+}
+
+// ListSessions returns the user's active sessions, newest first.
+func (s *AuthService) ListSessions(userID uint) ([]Session, *ServiceError) {
+	ctx := context.Background()
+	sessions := make([]Session, 0)
+	forEachRefreshSession(ctx, s.redis, userID, func(jti string, session refreshSession) {
+		sessions = append(sessions, Session{
+			ID:        jti,
+			CreatedAt: session.IssuedAt,
+			UserAgent: session.UserAgent,
+		})
+	})
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Stock movement type and reference type written by stock adjustments
+const (
+	StockMovementAdjustment      = "adjustment"
+	StockAdjustmentReferenceType = "stock_adjustment"
+)
+
+// StockAdjustmentServiceRepository defines repository methods needed by StockAdjustmentService.
+type StockAdjustmentServiceRepository interface {
+	List(page, pageSize int, variantID string) ([]repositories.StockAdjustmentListItem, int64, error)
+}
+
+// StockAdjustmentInput is the DTO for adjusting a variant's stock. Exactly one
+// of Delta (a signed change) or Count (the counted stock on hand) is set.
+type StockAdjustmentInput struct {
+	VariantID string `json:"variantId"`
+	Delta     *int   `json:"delta"`
+	Count     *int   `json:"count"`
+	Reason    string `json:"reason"`
+	// CreatedBy is the user making the adjustment, taken from the request.
+	CreatedBy uint `json:"-"`
+}
+
+// StockAdjustmentService handles manual stock corrections.
+type StockAdjustmentService struct {
+	db   *gorm.DB
+	repo StockAdjustmentServiceRepository
+}
+
+// NewStockAdjustmentService creates a new stock adjustment service instance.
+func NewStockAdjustmentService(db *gorm.DB, repo StockAdjustmentServiceRepository) *StockAdjustmentService {
+	return &StockAdjustmentService{db: db, repo: repo}
+}
+
+// Create applies a stock adjustment. The variant row is locked while its
+// stock is updated, and the adjustment is recorded with an adjustment stock
+// movement in the same transaction. Adjustments that would leave the stock
+// negative, or that do not change it, are rejected.
+func (s *StockAdjustmentService) Create(input StockAdjustmentInput) (*models.StockAdjustment, error) {
+	variantID := strings.TrimSpace(input.VariantID)
+	if _, err := uuid.Parse(variantID); err != nil {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "variantId must be a valid variant ID",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if (input.Delta == nil) == (input.Count == nil) {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Exactly one of delta or count is required",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if input.Count != nil && *input.Count < 0 {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "count must not be negative",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	reason, svcErr := requiredText("Reason", input.Reason, 500)
+	if svcErr != nil {
+		return nil, svcErr
+	}
+
+	var adjustment *models.StockAdjustment
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var variant models.ProductVariant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", variantID).
+			First(&variant).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return &ServiceError{
+					Err:     ErrNotFound,
+					Message: "Variant not found",
+					Code:    "VARIANT_NOT_FOUND",
+				}
+			}
+			return err
+		}
+
+		newStock := variant.CurrentStock
+		if input.Delta != nil {
+			newStock += *input.Delta
+		} else {
+			newStock = *input.Count
+		}
+		if svcErr := checkAdjustedStock(variant.CurrentStock, newStock); svcErr != nil {
+			return svcErr
+		}
+
+		if err := tx.Model(&models.ProductVariant{}).
+			Where("id = ?", variant.ID).
+			Update("current_stock", newStock).Error; err != nil {
+			return err
+		}
+
+		adjustment = &models.StockAdjustment{
+			VariantID:     variant.ID,
+			PreviousStock: variant.CurrentStock,
+			NewStock:      newStock,
+			Quantity:      newStock - variant.CurrentStock,
+			Reason:        reason,
+		}
+		if input.CreatedBy != 0 {
+			createdBy := input.CreatedBy
+			adjustment.CreatedBy = &createdBy
+		}
+		if err := tx.Create(adjustment).Error; err != nil {
+			return err
+		}
+
+		movement := &models.StockMovement{
+			VariantID:     variant.ID,
+			MovementType:  StockMovementAdjustment,
+			Quantity:      adjustment.Quantity,
+			ReferenceType: StockAdjustmentReferenceType,
+			ReferenceID:   &adjustment.ID,
+			Notes:         reason,
+			CreatedBy:     adjustment.CreatedBy,
+		}
+		return tx.Create(movement).Error
+	})
+
+	if err != nil {
+		if serviceErr, ok := err.(*ServiceError); ok {
+			return nil, serviceErr
+		}
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to adjust stock",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	return adjustment, nil
+}
+
+// checkAdjustedStock rejects an adjustment from current to newStock that
+// leaves the stock negative or unchanged.
+func checkAdjustedStock(current, newStock int) *ServiceError {
+	if newStock < 0 {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: fmt.Sprintf("Adjustment would leave stock at %d; current stock is %d", newStock, current),
+			Code:    "NEGATIVE_STOCK",
+		}
+	}
+	if newStock == current {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Adjustment does not change the stock",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	return nil
+}
+
+// List returns a page of stock adjustments, newest first, optionally limited
+// to one variant.
+func (s *StockAdjustmentService) List(page, pageSize int, variantID string) ([]repositories.StockAdjustmentListItem, int64, error) {
+	items, total, err := s.repo.List(page, pageSize, variantID)
+	if err != nil {
+		return nil, 0, &ServiceError{
+			Err:     err,
+			Message: "Failed to list stock adjustments",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	return items, total, nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestStockAdjustmentCreate_InvalidInput_ReturnsValidation(t *testing.T) {
+	svc := NewStockAdjustmentService(nil, nil)
+	variantID := "6f1c2b8e-4a7d-4c1e-9b3a-2d5e8f0a1b2c"
+
+	tests := []struct {
+		name    string
+		input   StockAdjustmentInput
+		message string
+	}{
+		{"invalid variant", StockAdjustmentInput{VariantID: "abc", Delta: intPtr(1), Reason: "Found"}, "variantId must be a valid variant ID"},
+		{"neither delta nor count", StockAdjustmentInput{VariantID: variantID, Reason: "Found"}, "Exactly one of delta or count is required"},
+		{"both delta and count", StockAdjustmentInput{VariantID: variantID, Delta: intPtr(1), Count: intPtr(5), Reason: "Found"}, "Exactly one of delta or count is required"},
+		{"negative count", StockAdjustmentInput{VariantID: variantID, Count: intPtr(-1), Reason: "Count"}, "count must not be negative"},
+		{"blank reason", StockAdjustmentInput{VariantID: variantID, Delta: intPtr(1), Reason: "   "}, "Reason is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Create(tt.input)
+			require.Error(t, err)
+			serviceErr, ok := err.(*ServiceError)
+			require.True(t, ok)
+			assert.Equal(t, ErrValidation, serviceErr.Err)
+			assert.Equal(t, tt.message, serviceErr.Message)
+		})
+	}
+}
+
+func TestCheckAdjustedStock(t *testing.T) {
+	assert.Nil(t, checkAdjustedStock(10, 0))
+	assert.Nil(t, checkAdjustedStock(10, 15))
+
+	err := checkAdjustedStock(10, -2)
+	require.NotNil(t, err)
+	assert.Equal(t, "NEGATIVE_STOCK", err.Code)
+
+	err = checkAdjustedStock(10, 10)
+	require.NotNil(t, err)
+	assert.Equal(t, ErrValidation, err.Err)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultFeedLimit = 20
+	maxFeedLimit     = 100
+)
+
+// StockMovementServiceRepository defines repository methods needed by StockMovementService.
+type StockMovementServiceRepository interface {
+	RecentFeed(limit int) ([]repositories.StockMovementFeedItem, error)
+	ListByVariant(variantID string, params repositories.PaginationParams) ([]repositories.StockLedgerEntry, int64, error)
+	FindVariant(variantID string) (*models.ProductVariant, error)
+}
+
+// StockMovementService handles stock movement queries.
+type StockMovementService struct {
+	repo StockMovementServiceRepository
+}
+
+// NewStockMovementService creates a new stock movement service instance.
+func NewStockMovementService(repo StockMovementServiceRepository) *StockMovementService {
+	return &StockMovementService{repo: repo}
+}
+
+// RecentFeed returns the latest stock movements across all variants, newest first.
+// A non-positive limit uses the default; limits above the maximum are capped.
+func (s *StockMovementService) RecentFeed(limit int) ([]repositories.StockMovementFeedItem, error) {
+	if limit <= 0 {
+		limit = defaultFeedLimit
+	}
+	if limit > maxFeedLimit {
+		limit = maxFeedLimit
+	}
+
+	items, err := s.repo.RecentFeed(limit)
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to load stock activity",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	return items, nil
+}
+
+// VariantLedger returns a page of the stock movements of a product's variant,
+// each with the running balance after it was applied.
+func (s *StockMovementService) VariantLedger(productID uint, variantID string, params repositories.PaginationParams) ([]repositories.StockLedgerEntry, int64, error) {
+	variant, err := s.repo.FindVariant(variantID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, &ServiceError{Err: err, Message: "Failed to fetch variant", Code: "INTERNAL_ERROR"}
+	}
+	if err != nil || variant.ProductID != productID {
+		return nil, 0, &ServiceError{
+			Err:     ErrNotFound,
+			Message: "Variant not found",
+			Code:    "VARIANT_NOT_FOUND",
+		}
+	}
+
+	entries, total, err := s.repo.ListByVariant(variantID, params)
+	if err != nil {
+		return nil, 0, &ServiceError{
+			Err:     err,
+			Message: "Failed to load stock movements",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	return entries, total, nil
+}
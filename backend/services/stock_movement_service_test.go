@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type mockStockFeedRepo struct {
+	recentFeedFn    func(int) ([]repositories.StockMovementFeedItem, error)
+	listByVariantFn func(string, repositories.PaginationParams) ([]repositories.StockLedgerEntry, int64, error)
+	findVariantFn   func(string) (*models.ProductVariant, error)
+}
+
+func (m *mockStockFeedRepo) RecentFeed(limit int) ([]repositories.StockMovementFeedItem, error) {
+	if m.recentFeedFn != nil {
+		return m.recentFeedFn(limit)
+	}
+	return nil, nil
+}
+
+func (m *mockStockFeedRepo) ListByVariant(variantID string, params repositories.PaginationParams) ([]repositories.StockLedgerEntry, int64, error) {
+	if m.listByVariantFn != nil {
+		return m.listByVariantFn(variantID, params)
+	}
+	return nil, 0, nil
+}
+
+func (m *mockStockFeedRepo) FindVariant(variantID string) (*models.ProductVariant, error) {
+	if m.findVariantFn != nil {
+		return m.findVariantFn(variantID)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestRecentFeed_ClampsLimit(t *testing.T) {
+	var requested []int
+	repo := &mockStockFeedRepo{
+		recentFeedFn: func(limit int) ([]repositories.StockMovementFeedItem, error) {
+			requested = append(requested, limit)
+			return nil, nil
+		},
+	}
+	svc := NewStockMovementService(repo)
+
+	_, err := svc.RecentFeed(0)
+	require.NoError(t, err)
+	_, err = svc.RecentFeed(5)
+	require.NoError(t, err)
+	_, err = svc.RecentFeed(1000)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{20, 5, 100}, requested)
+}
+
+func TestRecentFeed_RepoError_ReturnsInternalError(t *testing.T) {
+	repo := &mockStockFeedRepo{
+		recentFeedFn: func(limit int) ([]repositories.StockMovementFeedItem, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	svc := NewStockMovementService(repo)
+
+	_, err := svc.RecentFeed(10)
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "INTERNAL_ERROR", serviceErr.Code)
+}
+
+func TestVariantLedger_Valid_ReturnsEntries(t *testing.T) {
+	repo := &mockStockFeedRepo{
+		findVariantFn: func(id string) (*models.ProductVariant, error) {
+			return &models.ProductVariant{ID: id, ProductID: 3}, nil
+		},
+		listByVariantFn: func(id string, params repositories.PaginationParams) ([]repositories.StockLedgerEntry, int64, error) {
+			return []repositories.StockLedgerEntry{{ID: 1, Quantity: 5, Balance: 5}}, 1, nil
+		},
+	}
+	svc := NewStockMovementService(repo)
+
+	entries, total, err := svc.VariantLedger(3, "v-1", repositories.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 5, entries[0].Balance)
+}
+
+func TestVariantLedger_OtherProductVariant_ReturnsNotFound(t *testing.T) {
+	repo := &mockStockFeedRepo{
+		findVariantFn: func(id string) (*models.ProductVariant, error) {
+			return &models.ProductVariant{ID: id, ProductID: 4}, nil
+		},
+		listByVariantFn: func(id string, params repositories.PaginationParams) ([]repositories.StockLedgerEntry, int64, error) {
+			t.Fatal("ListByVariant should not be called")
+			return nil, 0, nil
+		},
+	}
+	svc := NewStockMovementService(repo)
+
+	_, _, err := svc.VariantLedger(3, "v-1", repositories.PaginationParams{Page: 1, PageSize: 10})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrNotFound, serviceErr.Err)
+}
+
+func TestVariantLedger_MissingVariant_ReturnsNotFound(t *testing.T) {
+	svc := NewStockMovementService(&mockStockFeedRepo{})
+
+	_, _, err := svc.VariantLedger(3, "v-1", repositories.PaginationParams{Page: 1, PageSize: 10})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "VARIANT_NOT_FOUND", serviceErr.Code)
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+)
+
+// StockSnapshotServiceRepository defines repository methods needed by StockSnapshotService.
+type StockSnapshotServiceRepository interface {
+	Capture(date string) (int64, error)
+	ListByDate(date string) ([]models.StockSnapshot, error)
+}
+
+// StockSnapshotCapture reports a snapshot that was just taken.
+type StockSnapshotCapture struct {
+	Date     string `json:"date"`
+	Variants int64  `json:"variants"`
+}
+
+// StockSnapshotReport is the frozen stock of every variant on one date.
+// Unvalued counts variants without a cost price, which add nothing to the
+// total valuation.
+type StockSnapshotReport struct {
+	Date           string                 `json:"date"`
+	Variants       int                    `json:"variants"`
+	Unvalued       int                    `json:"unvalued"`
+	TotalQuantity  int                    `json:"totalQuantity"`
+	TotalValuation float64                `json:"totalValuation"`
+	Items          []models.StockSnapshot `json:"items"`
+}
+
+// StockSnapshotService records and serves daily stock snapshots.
+type StockSnapshotService struct {
+	repo StockSnapshotServiceRepository
+	cfg  StockSnapshotConfig
+	now  func() time.Time
+}
+
+// StockSnapshotConfig holds tunable behaviour for StockSnapshotService.
+type StockSnapshotConfig struct {
+	// Location is the store's timezone, which decides the date a snapshot
+	// is taken under and when the daily capture runs. Nil means UTC.
+	Location *time.Location
+}
+
+// NewStockSnapshotService creates a new stock snapshot service instance.
+func NewStockSnapshotService(repo StockSnapshotServiceRepository, cfg ...StockSnapshotConfig) *StockSnapshotService {
+	var snapshotCfg StockSnapshotConfig
+	if len(cfg) > 0 {
+		snapshotCfg = cfg[0]
+	}
+	return &StockSnapshotService{repo: repo, cfg: snapshotCfg, now: time.Now}
+}
+
+// location returns the store's timezone, UTC when none is configured.
+func (s *StockSnapshotService) location() *time.Location {
+	if s.cfg.Location == nil {
+		return time.UTC
+	}
+	return s.cfg.Location
+}
+
+// Capture snapshots the current stock of every variant under today's date in
+// the store's timezone. Capturing again on the same day replaces that day's
+// snapshot, so the last capture of a day is its end-of-day figure.
+func (s *StockSnapshotService) Capture() (*StockSnapshotCapture, error) {
+	date := s.now().In(s.location()).Format("2006-01-02")
+	count, err := s.repo.Capture(date)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to capture stock snapshot", Code: "INTERNAL_ERROR"}
+	}
+	return &StockSnapshotCapture{Date: date, Variants: count}, nil
+}
+
+// Today returns today's date in the store's timezone, as midnight UTC like
+// the dates GetSnapshot accepts.
+func (s *StockSnapshotService) Today() time.Time {
+	now := s.now().In(s.location())
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// GetSnapshot returns the snapshot taken on date, a calendar date in the
+// store's timezone.
+func (s *StockSnapshotService) GetSnapshot(date time.Time) (*StockSnapshotReport, error) {
+	day := truncateToDate(date)
+	if day.After(s.Today()) {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "Date must not be in the future",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	dateStr := day.Format("2006-01-02")
+	items, err := s.repo.ListByDate(dateStr)
+	if err != nil {
+		return nil, &ServiceError{Err: err, Message: "Failed to load stock snapshot", Code: "INTERNAL_ERROR"}
+	}
+	if len(items) == 0 {
+		return nil, &ServiceError{
+			Err:     ErrNotFound,
+			Message: fmt.Sprintf("No stock snapshot for %s", dateStr),
+			Code:    "SNAPSHOT_NOT_FOUND",
+		}
+	}
+
+	report := &StockSnapshotReport{
+		Date:     dateStr,
+		Variants: len(items),
+		Items:    items,
+	}
+	var valuation float64
+	for _, item := range items {
+		report.TotalQuantity += item.Quantity
+		valuation += item.Valuation
+		if item.CostPrice == nil {
+			report.Unvalued++
+		}
+	}
+	report.TotalValuation = roundTo(valuation, 2)
+	return report, nil
+}
+
+// Start captures a snapshot every day at the given offset from midnight in
+// the store's timezone until ctx is cancelled.
+func (s *StockSnapshotService) Start(ctx context.Context, at time.Duration) {
+	go func() {
+		for {
+			timer := time.NewTimer(nextSnapshotRun(s.now(), at, s.location()).Sub(s.now()))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				capture, err := s.Capture()
+				if err != nil {
+					slog.Error("stock snapshot failed", "error", err)
+					continue
+				}
+				slog.Info("stock snapshot captured", "date", capture.Date, "variants", capture.Variants)
+			}
+		}
+	}()
+}
+
+// nextSnapshotRun returns the first time after now that falls at the offset
+// from midnight in loc.
+func nextSnapshotRun(now time.Time, at time.Duration, loc *time.Location) time.Time {
+	now = now.In(loc)
+	next := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).Add(at)
+	if !next.After(now) {
+		next = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, loc).Add(at)
+	}
+	return next
+}
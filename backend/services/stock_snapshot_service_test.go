@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockStockSnapshotRepo struct {
+	captured  []string
+	snapshots map[string][]models.StockSnapshot
+	err       error
+}
+
+func (m *mockStockSnapshotRepo) Capture(date string) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.captured = append(m.captured, date)
+	return 3, nil
+}
+
+func (m *mockStockSnapshotRepo) ListByDate(date string) ([]models.StockSnapshot, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.snapshots[date], nil
+}
+
+func newTestStockSnapshotService(repo *mockStockSnapshotRepo, now time.Time) *StockSnapshotService {
+	svc := NewStockSnapshotService(repo)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestStockSnapshotCapture_UsesTodayUTC(t *testing.T) {
+	repo := &mockStockSnapshotRepo{}
+	jakarta := time.FixedZone("WIB", 7*3600)
+	svc := newTestStockSnapshotService(repo, time.Date(2026, 3, 2, 5, 0, 0, 0, jakarta))
+
+	capture, err := svc.Capture()
+
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-01", capture.Date)
+	assert.Equal(t, int64(3), capture.Variants)
+	assert.Equal(t, []string{"2026-03-01"}, repo.captured)
+}
+
+func TestStockSnapshotCapture_StoreTimezone_UsesLocalDate(t *testing.T) {
+	repo := &mockStockSnapshotRepo{}
+	jakarta := time.FixedZone("WIB", 7*3600)
+	svc := NewStockSnapshotService(repo, StockSnapshotConfig{Location: jakarta})
+	// 20:00 UTC on 1 March is already 2 March in the store
+	svc.now = func() time.Time { return time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC) }
+
+	capture, err := svc.Capture()
+
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-02", capture.Date)
+	assert.Equal(t, []string{"2026-03-02"}, repo.captured)
+}
+
+func TestGetStockSnapshot_PastDate_TotalsFrozenRows(t *testing.T) {
+	cost := 1500.0
+	repo := &mockStockSnapshotRepo{snapshots: map[string][]models.StockSnapshot{
+		"2026-03-01": {
+			{VariantID: "a", Quantity: 10, CostPrice: &cost, Valuation: 15000},
+			{VariantID: "b", Quantity: 4},
+		},
+	}}
+	svc := newTestStockSnapshotService(repo, time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC))
+
+	report, err := svc.GetSnapshot(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-01", report.Date)
+	assert.Equal(t, 2, report.Variants)
+	assert.Equal(t, 1, report.Unvalued)
+	assert.Equal(t, 14, report.TotalQuantity)
+	assert.Equal(t, 15000.0, report.TotalValuation)
+	assert.Len(t, report.Items, 2)
+}
+
+func TestGetStockSnapshot_NoSnapshot_ReturnsNotFound(t *testing.T) {
+	svc := newTestStockSnapshotService(&mockStockSnapshotRepo{}, time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC))
+
+	_, err := svc.GetSnapshot(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrNotFound, serviceErr.Err)
+	assert.Equal(t, "SNAPSHOT_NOT_FOUND", serviceErr.Code)
+}
+
+func TestGetStockSnapshot_FutureDate_ReturnsValidation(t *testing.T) {
+	svc := newTestStockSnapshotService(&mockStockSnapshotRepo{}, time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC))
+
+	_, err := svc.GetSnapshot(time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC))
+
+	require.Error(t, err)
+	assert.Equal(t, ErrValidation, err.(*ServiceError).Err)
+}
+
+func TestGetStockSnapshot_RepoError_ReturnsInternal(t *testing.T) {
+	repo := &mockStockSnapshotRepo{err: errors.New("db down")}
+	svc := newTestStockSnapshotService(repo, time.Date(2026, 3, 5, 12, 0, 0, 0, time.UTC))
+
+	_, err := svc.GetSnapshot(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	require.Error(t, err)
+	assert.Equal(t, "INTERNAL_ERROR", err.(*ServiceError).Code)
+}
+
+func TestNextSnapshotRun(t *testing.T) {
+	at := 23*time.Hour + 55*time.Minute
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"earlier the same day", time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC), time.Date(2026, 3, 1, 23, 55, 0, 0, time.UTC)},
+		{"exactly at run time", time.Date(2026, 3, 1, 23, 55, 0, 0, time.UTC), time.Date(2026, 3, 2, 23, 55, 0, 0, time.UTC)},
+		{"after run time", time.Date(2026, 3, 1, 23, 58, 0, 0, time.UTC), time.Date(2026, 3, 2, 23, 55, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextSnapshotRun(tt.now, at, time.UTC))
+		})
+	}
+}
+
+func TestNextSnapshotRun_StoreTimezone_RunsAtLocalTime(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	at := 23*time.Hour + 55*time.Minute
+
+	// 20:00 UTC on 1 March is 03:00 on 2 March in the store
+	next := nextSnapshotRun(time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC), at, jakarta)
+
+	assert.True(t, time.Date(2026, 3, 2, 23, 55, 0, 0, jakarta).Equal(next))
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"io"
+	"strings"
+)
+
+// MaxSupplierImportRows caps the number of data rows in a single supplier import.
+const MaxSupplierImportRows = 500
+
+// Per-row outcomes of a supplier import
+const (
+	SupplierImportCreated = "created"
+	SupplierImportSkipped = "skipped"
+	SupplierImportError   = "error"
+)
+
+// SupplierImportRowResult is the outcome of importing one CSV row. Row is the
+// record's position in the file, counting the header as row 1.
+type SupplierImportRowResult struct {
+	Row        int    `json:"row"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Reason     string `json:"reason,omitempty"`
+	SupplierID uint   `json:"supplierId,omitempty"`
+}
+
+// SupplierImportResult summarises a bulk supplier import.
+type SupplierImportResult struct {
+	Created int                       `json:"created"`
+	Skipped int                       `json:"skipped"`
+	Failed  int                       `json:"failed"`
+	Rows    []SupplierImportRowResult `json:"rows"`
+}
+
+func (r *SupplierImportResult) add(row SupplierImportRowResult) {
+	switch row.Status {
+	case SupplierImportCreated:
+		r.Created++
+	case SupplierImportSkipped:
+		r.Skipped++
+	default:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, row)
+}
+
+// ImportCSV creates suppliers from a CSV with a header row naming the columns
+// name, address, phone, email, website, account_name and account_number (in
+// any order; all but name and address are optional). A supplier's bank
+// accounts are semicolon-separated lists in account_name and account_number,
+// paired by position. Each supplier is created with its accounts in one
+// transaction, as with CreateSupplier. Rows whose name matches an existing
+// supplier, or an earlier imported row, are skipped; invalid rows are
+// reported and do not stop the import.
+func (s *SupplierService) ImportCSV(reader io.Reader) (*SupplierImportResult, error) {
+	file, svcErr := readImportCSV(reader, []string{"name", "address"}, MaxSupplierImportRows)
+	if svcErr != nil {
+		return nil, svcErr
+	}
+
+	result := &SupplierImportResult{Rows: []SupplierImportRowResult{}}
+	seen := make(map[string]bool)
+	for _, entry := range file.rows {
+		line, record := entry.line, entry.record
+		if entry.err != nil {
+			result.add(SupplierImportRowResult{Row: line, Status: SupplierImportError, Reason: "Malformed CSV row"})
+			continue
+		}
+
+		name := file.field(record, "name")
+		row := SupplierImportRowResult{Row: line, Name: name}
+		key := strings.ToLower(name)
+		if key != "" && seen[key] {
+			row.Status = SupplierImportSkipped
+			row.Reason = "Duplicate name in file"
+			result.add(row)
+			continue
+		}
+
+		if name != "" {
+			exists, err := s.supplierRepo.NameExists(name)
+			if err != nil {
+				return nil, &ServiceError{Err: err, Message: "Failed to check supplier name", Code: "INTERNAL_ERROR"}
+			}
+			if exists {
+				row.Status = SupplierImportSkipped
+				row.Reason = "Supplier already exists"
+				result.add(row)
+				continue
+			}
+		}
+
+		accounts, ok := importBankAccounts(file.field(record, "account_name"), file.field(record, "account_number"))
+		if !ok {
+			row.Status = SupplierImportError
+			row.Reason = "account_name and account_number must list the same number of accounts"
+			result.add(row)
+			continue
+		}
+
+		supplier, err := s.CreateSupplier(CreateSupplierInput{
+			Name:         name,
+			Address:      file.field(record, "address"),
+			Phone:        file.field(record, "phone"),
+			Email:        file.field(record, "email"),
+			Website:      file.field(record, "website"),
+			BankAccounts: accounts,
+		})
+		if err != nil {
+			row.Status = SupplierImportError
+			row.Reason = "Failed to create supplier"
+			if serviceErr, ok := err.(*ServiceError); ok {
+				row.Reason = serviceErr.Message
+			}
+			result.add(row)
+			continue
+		}
+
+		// Only a row that passed validation claims its name
+		seen[key] = true
+		row.Status = SupplierImportCreated
+		row.SupplierID = supplier.ID
+		result.add(row)
+	}
+
+	return result, nil
+}
+
+// importBankAccounts pairs semicolon-separated account names and numbers by
+// position. It reports false when the lists have different lengths.
+func importBankAccounts(names, numbers string) ([]BankAccountInput, bool) {
+	nameList := splitImportList(names)
+	numberList := splitImportList(numbers)
+	if len(nameList) != len(numberList) {
+		return nil, false
+	}
+
+	accounts := make([]BankAccountInput, len(nameList))
+	for i := range nameList {
+		accounts[i] = BankAccountInput{AccountName: nameList[i], AccountNumber: numberList[i]}
+	}
+	return accounts, true
+}
+
+// splitImportList splits a semicolon-separated cell into trimmed values. An
+// empty cell yields no values.
+func splitImportList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	parts := strings.Split(value, ";")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSupplierImportTestService returns a supplier service whose repository
+// already holds "PT Existing" and records every supplier it creates.
+func newSupplierImportTestService() (*SupplierService, map[uint]*models.Supplier) {
+	created := make(map[uint]*models.Supplier)
+	repo := &mockSupplierRepo{
+		nameExistsFn: func(name string) (bool, error) {
+			return strings.EqualFold(name, "PT Existing"), nil
+		},
+		createFn: func(supplier *models.Supplier) error {
+			supplier.ID = uint(len(created) + 1)
+			created[supplier.ID] = supplier
+			return nil
+		},
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return created[id], nil
+		},
+	}
+	return NewSupplierService(repo), created
+}
+
+func TestImportCSV_ValidRows_CreatesSuppliersWithBankAccounts(t *testing.T) {
+	svc, created := newSupplierImportTestService()
+
+	csvData := "name,address,phone,account_name,account_number\n" +
+		"PT Sumber Makmur,Jakarta,0211,BCA; Mandiri,1234567890; 0987654321\n" +
+		"CV Maju Jaya,Bandung,,,\n"
+
+	result, err := svc.ImportCSV(strings.NewReader(csvData))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 0, result.Skipped)
+	assert.Equal(t, 0, result.Failed)
+	require.Len(t, created, 2)
+
+	first := created[result.Rows[0].SupplierID]
+	assert.Equal(t, "PT Sumber Makmur", first.Name)
+	assert.Equal(t, "0211", first.Phone)
+	require.Len(t, first.BankAccounts, 2)
+	assert.Equal(t, "BCA", first.BankAccounts[0].AccountName)
+	assert.Equal(t, "1234567890", first.BankAccounts[0].AccountNumber)
+	assert.Equal(t, "Mandiri", first.BankAccounts[1].AccountName)
+	assert.Equal(t, "0987654321", first.BankAccounts[1].AccountNumber)
+
+	assert.Empty(t, created[result.Rows[1].SupplierID].BankAccounts)
+}
+
+func TestImportCSV_DuplicateNames_SkipsRows(t *testing.T) {
+	svc, created := newSupplierImportTestService()
+
+	csvData := "name,address\n" +
+		"PT Existing,Jakarta\n" +
+		"CV Maju Jaya,Bandung\n" +
+		"cv maju jaya,Surabaya\n"
+
+	result, err := svc.ImportCSV(strings.NewReader(csvData))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 2, result.Skipped)
+	assert.Len(t, created, 1)
+	assert.Equal(t, SupplierImportSkipped, result.Rows[0].Status)
+	assert.Equal(t, "Supplier already exists", result.Rows[0].Reason)
+	assert.Equal(t, SupplierImportSkipped, result.Rows[2].Status)
+	assert.Equal(t, "Duplicate name in file", result.Rows[2].Reason)
+}
+
+func TestImportCSV_InvalidRowThenCorrectedRow_CreatesSupplier(t *testing.T) {
+	svc, created := newSupplierImportTestService()
+
+	csvData := "name,address\n" +
+		"CV Maju Jaya,\n" +
+		"CV Maju Jaya,Bandung\n"
+
+	result, err := svc.ImportCSV(strings.NewReader(csvData))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	assert.Len(t, created, 1)
+	assert.Equal(t, SupplierImportError, result.Rows[0].Status)
+	assert.Equal(t, SupplierImportCreated, result.Rows[1].Status)
+}
+
+func TestImportCSV_InvalidRows_ReportedByLine(t *testing.T) {
+	svc, created := newSupplierImportTestService()
+
+	csvData := "name,address,account_name,account_number\n" +
+		"CV Maju Jaya,Bandung,,\n" +
+		"Bro\"ken,Jakarta,,\n" +
+		"PT Mismatch,Jakarta,BCA;Mandiri,123\n" +
+		"PT Twice,Jakarta,BCA;BCA Ops,123456; 123456\n" +
+		",Jakarta,,\n"
+
+	result, err := svc.ImportCSV(strings.NewReader(csvData))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, result.Created)
+	assert.Len(t, created, 1)
+	require.Len(t, result.Rows, 5)
+
+	assert.Equal(t, 3, result.Rows[1].Row)
+	assert.Equal(t, SupplierImportError, result.Rows[1].Status)
+	assert.Equal(t, "Malformed CSV row", result.Rows[1].Reason)
+
+	assert.Equal(t, 4, result.Rows[2].Row)
+	assert.Equal(t, SupplierImportError, result.Rows[2].Status)
+	assert.Contains(t, result.Rows[2].Reason, "same number of accounts")
+
+	assert.Equal(t, 5, result.Rows[3].Row)
+	assert.Equal(t, "Bank account 2: accountNumber duplicates bank account 1", result.Rows[3].Reason)
+
+	assert.Equal(t, 6, result.Rows[4].Row)
+	assert.Equal(t, "Name is required", result.Rows[4].Reason)
+}
+
+func TestImportCSV_MissingRequiredColumn_ReturnsValidationError(t *testing.T) {
+	svc, _ := newSupplierImportTestService()
+
+	_, err := svc.ImportCSV(strings.NewReader("name,phone\nPT A,0211\n"))
+
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Contains(t, serviceErr.Message, "address")
+}
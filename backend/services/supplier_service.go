@@ -2,7 +2,9 @@ package services
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/repositories"
@@ -19,16 +21,40 @@ type SupplierRepositoryInterface interface {
 	Delete(id uint) error
 	CountPurchaseOrdersBySupplierID(supplierID uint) (int64, error)
 	CleanupProductSuppliers(supplierID uint) error
+	ListReceivedPurchaseOrders(supplierID uint, from, to time.Time) ([]models.PurchaseOrder, error)
+	ListPurchaseOrders(supplierID uint, from, to time.Time) ([]models.PurchaseOrder, error)
+	ListUnpaidPurchaseOrders(supplierID uint) ([]models.PurchaseOrder, error)
+	EmailExists(email string, excludeID uint) (bool, error)
+	NameExists(name string) (bool, error)
+}
+
+// SupplierConfig holds tunable behaviour for SupplierService.
+type SupplierConfig struct {
+	// UniqueEmail rejects a supplier email already used by another supplier,
+	// compared case-insensitively.
+	UniqueEmail bool
+	// BankAccountMinLength and BankAccountMaxLength bound the number of
+	// digits in a bank account number. A zero bound is not enforced.
+	BankAccountMinLength int
+	BankAccountMaxLength int
+	// PaymentTermDays is how many days after receipt a purchase order is due
+	// for payment. Zero makes it due on receipt.
+	PaymentTermDays int
 }
 
 // SupplierService handles supplier business logic
 type SupplierService struct {
 	supplierRepo SupplierRepositoryInterface
+	cfg          SupplierConfig
 }
 
 // NewSupplierService creates a new supplier service instance
-func NewSupplierService(supplierRepo SupplierRepositoryInterface) *SupplierService {
-	return &SupplierService{supplierRepo: supplierRepo}
+func NewSupplierService(supplierRepo SupplierRepositoryInterface, cfg ...SupplierConfig) *SupplierService {
+	var supplierCfg SupplierConfig
+	if len(cfg) > 0 {
+		supplierCfg = cfg[0]
+	}
+	return &SupplierService{supplierRepo: supplierRepo, cfg: supplierCfg}
 }
 
 // BankAccountInput is the DTO for bank account input
@@ -94,43 +120,24 @@ func (s *SupplierService) GetSupplier(id uint) (*models.Supplier, error) {
 // CreateSupplier creates a new supplier with validation
 func (s *SupplierService) CreateSupplier(input CreateSupplierInput) (*models.Supplier, error) {
 	// Validate name
-	trimmedName := strings.TrimSpace(input.Name)
-	if trimmedName == "" {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Name is required",
-			Code:    "VALIDATION_ERROR",
-		}
-	}
-	if len(trimmedName) > 255 {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Name must be at most 255 characters",
-			Code:    "VALIDATION_ERROR",
-		}
+	trimmedName, nameErr := requiredText("Name", input.Name, 255)
+	if nameErr != nil {
+		return nil, nameErr
 	}
 
 	// Validate address
-	trimmedAddress := strings.TrimSpace(input.Address)
-	if trimmedAddress == "" {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Address is required",
-			Code:    "VALIDATION_ERROR",
-		}
+	trimmedAddress, addressErr := requiredText("Address", input.Address, 0)
+	if addressErr != nil {
+		return nil, addressErr
 	}
 
-	// Validate email (optional, but if provided must be valid)
-	if input.Email != "" && !utils.ValidateEmail(input.Email) {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Invalid email format",
-			Code:    "VALIDATION_ERROR",
-		}
+	// Validate email and website (optional, but if provided must be valid)
+	if err := s.validateContact(input.Email, input.Website, 0); err != nil {
+		return nil, err
 	}
 
 	// Validate bank accounts
-	if err := validateBankAccounts(input.BankAccounts); err != nil {
+	if err := s.validateBankAccounts(input.BankAccounts); err != nil {
 		return nil, err
 	}
 
@@ -182,30 +189,26 @@ func (s *SupplierService) UpdateSupplier(id uint, input UpdateSupplierInput) (*m
 	}
 
 	// Validate name
-	if input.Name != "" {
-		trimmedName := strings.TrimSpace(input.Name)
-		if len(trimmedName) > 255 {
-			return nil, &ServiceError{
-				Err:     ErrValidation,
-				Message: "Name must be at most 255 characters",
-				Code:    "VALIDATION_ERROR",
-			}
-		}
+	trimmedName, ok, nameErr := updatedText("Name", input.Name, 255)
+	if nameErr != nil {
+		return nil, nameErr
+	}
+	if ok {
 		supplier.Name = trimmedName
 	}
 
 	// Validate address
-	if input.Address != "" {
-		supplier.Address = strings.TrimSpace(input.Address)
+	trimmedAddress, ok, addressErr := updatedText("Address", input.Address, 0)
+	if addressErr != nil {
+		return nil, addressErr
+	}
+	if ok {
+		supplier.Address = trimmedAddress
 	}
 
-	// Validate email
-	if input.Email != "" && !utils.ValidateEmail(input.Email) {
-		return nil, &ServiceError{
-			Err:     ErrValidation,
-			Message: "Invalid email format",
-			Code:    "VALIDATION_ERROR",
-		}
+	// Validate email and website
+	if err := s.validateContact(input.Email, input.Website, id); err != nil {
+		return nil, err
 	}
 	if input.Email != "" {
 		supplier.Email = strings.TrimSpace(input.Email)
@@ -226,7 +229,7 @@ func (s *SupplierService) UpdateSupplier(id uint, input UpdateSupplierInput) (*m
 	var bankAccounts []models.SupplierBankAccount
 	if input.BankAccounts != nil {
 		// Validate bank accounts
-		if err := validateBankAccounts(*input.BankAccounts); err != nil {
+		if err := s.validateBankAccounts(*input.BankAccounts); err != nil {
 			return nil, err
 		}
 		for _, ba := range *input.BankAccounts {
@@ -254,6 +257,47 @@ func (s *SupplierService) UpdateSupplier(id uint, input UpdateSupplierInput) (*m
 	return updated, nil
 }
 
+// validateContact checks the email and website formats and, when configured,
+// that no other supplier already uses the email. Empty values are skipped.
+func (s *SupplierService) validateContact(email, website string, excludeID uint) error {
+	email = strings.TrimSpace(email)
+	website = strings.TrimSpace(website)
+
+	if email != "" && !utils.ValidateEmail(email) {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Invalid email format",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if website != "" && !utils.ValidateURL(website) {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Invalid website URL",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	if email != "" && s.cfg.UniqueEmail {
+		exists, err := s.supplierRepo.EmailExists(email, excludeID)
+		if err != nil {
+			return &ServiceError{
+				Err:     err,
+				Message: "Failed to check supplier email",
+				Code:    "INTERNAL_ERROR",
+			}
+		}
+		if exists {
+			return &ServiceError{
+				Err:     ErrConflict,
+				Message: "Email is already used by another supplier",
+				Code:    "SUPPLIER_EMAIL_EXISTS",
+			}
+		}
+	}
+	return nil
+}
+
 // DeleteSupplier deletes a supplier with reference checking
 func (s *SupplierService) DeleteSupplier(id uint) error {
 	// Find supplier
@@ -304,8 +348,292 @@ func (s *SupplierService) DeleteSupplier(id uint) error {
 	return nil
 }
 
-// validateBankAccounts validates bank account inputs
-func validateBankAccounts(accounts []BankAccountInput) *ServiceError {
+// SupplierPerformance holds procurement metrics for a supplier over a period.
+// Rates are fractions between 0 and 1 and are nil when there is nothing to measure.
+type SupplierPerformance struct {
+	SupplierID       uint     `json:"supplierId"`
+	From             string   `json:"from"`
+	To               string   `json:"to"`
+	ReceivedOrders   int      `json:"receivedOrders"`
+	OnTimeOrders     int      `json:"onTimeOrders"`
+	OnTimeRate       *float64 `json:"onTimeRate"`
+	AvgLeadTimeDays  *float64 `json:"avgLeadTimeDays"`
+	TotalOrderedQty  int      `json:"totalOrderedQty"`
+	TotalReceivedQty int      `json:"totalReceivedQty"`
+	FillRate         *float64 `json:"fillRate"`
+	TotalSpend       float64  `json:"totalSpend"`
+}
+
+// Performance computes on-time receipt rate, average lead time, fill rate, and
+// total spend for purchase orders received from the supplier between from and to.
+// Only orders with an expected date count towards the on-time rate.
+func (s *SupplierService) Performance(supplierID uint, from, to time.Time) (*SupplierPerformance, error) {
+	if err := s.checkSupplierPeriod(supplierID, from, to); err != nil {
+		return nil, err
+	}
+
+	orders, err := s.supplierRepo.ListReceivedPurchaseOrders(supplierID, from, to)
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to load supplier purchase orders",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	perf := &SupplierPerformance{
+		SupplierID:     supplierID,
+		From:           from.Format("2006-01-02"),
+		To:             to.Format("2006-01-02"),
+		ReceivedOrders: len(orders),
+	}
+
+	withExpected := 0
+	leadTimeOrders := 0
+	var totalLeadDays float64
+
+	for _, po := range orders {
+		if po.ReceivedDate == nil {
+			continue
+		}
+		received := truncateToDate(*po.ReceivedDate)
+
+		if po.ExpectedDate != nil {
+			if expected, ok := parsePODate(*po.ExpectedDate); ok {
+				withExpected++
+				if !received.After(expected) {
+					perf.OnTimeOrders++
+				}
+			}
+		}
+
+		if ordered, ok := parsePODate(po.Date); ok {
+			leadTimeOrders++
+			totalLeadDays += received.Sub(ordered).Hours() / 24
+		}
+
+		for _, item := range po.Items {
+			perf.TotalOrderedQty += item.OrderedQty
+			if item.ReceivedQty != nil {
+				perf.TotalReceivedQty += *item.ReceivedQty
+			}
+		}
+		perf.TotalSpend += receivedValue(po)
+	}
+
+	if withExpected > 0 {
+		rate := roundTo(float64(perf.OnTimeOrders)/float64(withExpected), 4)
+		perf.OnTimeRate = &rate
+	}
+	if leadTimeOrders > 0 {
+		avg := roundTo(totalLeadDays/float64(leadTimeOrders), 2)
+		perf.AvgLeadTimeDays = &avg
+	}
+	if perf.TotalOrderedQty > 0 {
+		rate := roundTo(float64(perf.TotalReceivedQty)/float64(perf.TotalOrderedQty), 4)
+		perf.FillRate = &rate
+	}
+	perf.TotalSpend = roundTo(perf.TotalSpend, 2)
+
+	return perf, nil
+}
+
+// SupplierMetrics summarises a supplier's purchase orders dated within a
+// period. Received figures cover only the orders that have been received.
+type SupplierMetrics struct {
+	SupplierID         uint    `json:"supplierId"`
+	From               string  `json:"from"`
+	To                 string  `json:"to"`
+	TotalOrders        int     `json:"totalOrders"`
+	ReceivedOrders     int     `json:"receivedOrders"`
+	TotalReceivedValue float64 `json:"totalReceivedValue"`
+	AvgTurnaroundDays  float64 `json:"avgTurnaroundDays"`
+	DiscrepancyItems   int     `json:"discrepancyItems"`
+}
+
+// Metrics counts the supplier's purchase orders dated between from and to and,
+// for those received, sums their grand totals, averages the days from PO date to
+// receipt and counts lines whose received quantity differs from the ordered
+// quantity. A supplier without orders gets all zeros.
+func (s *SupplierService) Metrics(supplierID uint, from, to time.Time) (*SupplierMetrics, error) {
+	if err := s.checkSupplierPeriod(supplierID, from, to); err != nil {
+		return nil, err
+	}
+
+	orders, err := s.supplierRepo.ListPurchaseOrders(supplierID, from, to)
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to load supplier purchase orders",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	metrics := &SupplierMetrics{
+		SupplierID:  supplierID,
+		From:        from.Format("2006-01-02"),
+		To:          to.Format("2006-01-02"),
+		TotalOrders: len(orders),
+	}
+
+	turnaroundOrders := 0
+	var totalTurnaroundDays float64
+
+	for _, po := range orders {
+		if po.ReceivedDate == nil {
+			continue
+		}
+		metrics.ReceivedOrders++
+		metrics.TotalReceivedValue += receivedValue(po)
+		if ordered, ok := parsePODate(po.Date); ok {
+			turnaroundOrders++
+			totalTurnaroundDays += truncateToDate(*po.ReceivedDate).Sub(ordered).Hours() / 24
+		}
+		for _, item := range po.Items {
+			if item.ReceivedQty != nil && *item.ReceivedQty != item.OrderedQty {
+				metrics.DiscrepancyItems++
+			}
+		}
+	}
+
+	if turnaroundOrders > 0 {
+		metrics.AvgTurnaroundDays = roundTo(totalTurnaroundDays/float64(turnaroundOrders), 2)
+	}
+	metrics.TotalReceivedValue = roundTo(metrics.TotalReceivedValue, 2)
+
+	return metrics, nil
+}
+
+// SupplierPayables is what is owed to a supplier for goods received on
+// purchase orders that have not been paid.
+type SupplierPayables struct {
+	SupplierID       uint           `json:"supplierId"`
+	TotalOutstanding float64        `json:"totalOutstanding"`
+	Orders           []PayableOrder `json:"orders"`
+}
+
+// PayableOrder is an unpaid purchase order contributing to a supplier's
+// payables. DueDate is empty when the receipt date is unknown.
+type PayableOrder struct {
+	POID         uint    `json:"poId"`
+	PONumber     string  `json:"poNumber"`
+	Status       string  `json:"status"`
+	ReceivedDate string  `json:"receivedDate,omitempty"`
+	DueDate      string  `json:"dueDate,omitempty"`
+	Amount       float64 `json:"amount"`
+}
+
+// OutstandingPayables sums the received value of the supplier's unpaid
+// purchase orders and lists them with their payment due dates.
+func (s *SupplierService) OutstandingPayables(supplierID uint) (*SupplierPayables, error) {
+	if _, err := s.supplierRepo.FindByID(supplierID); err != nil {
+		return nil, &ServiceError{
+			Err:     ErrNotFound,
+			Message: "Supplier not found",
+			Code:    "SUPPLIER_NOT_FOUND",
+		}
+	}
+
+	orders, err := s.supplierRepo.ListUnpaidPurchaseOrders(supplierID)
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to load supplier purchase orders",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	payables := &SupplierPayables{
+		SupplierID: supplierID,
+		Orders:     make([]PayableOrder, 0, len(orders)),
+	}
+	for _, po := range orders {
+		amount := receivedValue(po)
+		order := PayableOrder{
+			POID:     po.ID,
+			PONumber: po.PONumber,
+			Status:   po.Status,
+			Amount:   roundTo(amount, 2),
+		}
+		if po.ReceivedDate != nil {
+			received := truncateToDate(*po.ReceivedDate)
+			order.ReceivedDate = received.Format("2006-01-02")
+			order.DueDate = received.AddDate(0, 0, s.cfg.PaymentTermDays).Format("2006-01-02")
+		}
+		payables.TotalOutstanding += amount
+		payables.Orders = append(payables.Orders, order)
+	}
+	payables.TotalOutstanding = roundTo(payables.TotalOutstanding, 2)
+
+	return payables, nil
+}
+
+// receivedValue is what is owed for the goods received on a PO: its grand
+// total after discount and tax, its subtotal when no grand total is stored,
+// or else the received quantities at received prices.
+func receivedValue(po models.PurchaseOrder) float64 {
+	if po.GrandTotal != nil {
+		return *po.GrandTotal
+	}
+	if po.Subtotal != nil {
+		return *po.Subtotal
+	}
+	var value float64
+	for _, item := range po.Items {
+		if item.ReceivedQty != nil && item.ReceivedPrice != nil {
+			value += float64(*item.ReceivedQty) * *item.ReceivedPrice
+		}
+	}
+	return value
+}
+
+// checkSupplierPeriod validates a reporting period and that the supplier exists.
+func (s *SupplierService) checkSupplierPeriod(supplierID uint, from, to time.Time) error {
+	if to.Before(from) {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "'from' date must not be after 'to' date",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	if _, err := s.supplierRepo.FindByID(supplierID); err != nil {
+		return &ServiceError{
+			Err:     ErrNotFound,
+			Message: "Supplier not found",
+			Code:    "SUPPLIER_NOT_FOUND",
+		}
+	}
+	return nil
+}
+
+// parsePODate parses a PO date column, which may be returned either as a plain
+// date or as an RFC3339 timestamp depending on the driver.
+func parsePODate(value string) (time.Time, bool) {
+	if len(value) < 10 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02", value[:10])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func truncateToDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func roundTo(value float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(value*factor) / factor
+}
+
+// validateBankAccounts validates bank account inputs. The inputs are the
+// supplier's complete set of accounts, so duplicates are checked among them.
+func (s *SupplierService) validateBankAccounts(accounts []BankAccountInput) *ServiceError {
+	seen := make(map[string]int, len(accounts))
 	for i, ba := range accounts {
 		if strings.TrimSpace(ba.AccountName) == "" {
 			return &ServiceError{
@@ -321,6 +649,46 @@ func validateBankAccounts(accounts []BankAccountInput) *ServiceError {
 				Code:    "VALIDATION_ERROR",
 			}
 		}
+		if err := s.validateBankAccountNumber(strings.TrimSpace(ba.AccountNumber)); err != "" {
+			return &ServiceError{
+				Err:     ErrValidation,
+				Message: fmt.Sprintf("Bank account %d: %s", i+1, err),
+				Code:    "INVALID_BANK_ACCOUNT",
+			}
+		}
+		// A supplier may not list a number twice; other suppliers may share it
+		key := strings.TrimSpace(ba.AccountNumber)
+		if first, ok := seen[key]; ok {
+			return &ServiceError{
+				Err:     ErrConflict,
+				Message: fmt.Sprintf("Bank account %d: accountNumber duplicates bank account %d", i+1, first),
+				Code:    "DUPLICATE_BANK_ACCOUNT",
+			}
+		}
+		seen[key] = i + 1
 	}
 	return nil
 }
+
+// validateBankAccountNumber checks a bank account number is all digits and
+// within the configured length range, returning a message when it is not.
+func (s *SupplierService) validateBankAccountNumber(number string) string {
+	for _, r := range number {
+		if r < '0' || r > '9' {
+			return "accountNumber must contain only digits"
+		}
+	}
+
+	minLen, maxLen := s.cfg.BankAccountMinLength, s.cfg.BankAccountMaxLength
+	if (minLen > 0 && len(number) < minLen) || (maxLen > 0 && len(number) > maxLen) {
+		switch {
+		case minLen > 0 && maxLen > 0:
+			return fmt.Sprintf("accountNumber must be between %d and %d digits", minLen, maxLen)
+		case minLen > 0:
+			return fmt.Sprintf("accountNumber must be at least %d digits", minLen)
+		default:
+			return fmt.Sprintf("accountNumber must be at most %d digits", maxLen)
+		}
+	}
+	return ""
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/repositories"
@@ -19,6 +20,11 @@ type mockSupplierRepo struct {
 	deleteFn                          func(uint) error
 	countPurchaseOrdersBySupplierIDFn func(uint) (int64, error)
 	cleanupProductSuppliersFn         func(uint) error
+	listReceivedPurchaseOrdersFn      func(uint, time.Time, time.Time) ([]models.PurchaseOrder, error)
+	listPurchaseOrdersFn              func(uint, time.Time, time.Time) ([]models.PurchaseOrder, error)
+	listUnpaidPurchaseOrdersFn        func(uint) ([]models.PurchaseOrder, error)
+	emailExistsFn                     func(string, uint) (bool, error)
+	nameExistsFn                      func(string) (bool, error)
 }
 
 func (m *mockSupplierRepo) Create(supplier *models.Supplier) error {
@@ -70,6 +76,41 @@ func (m *mockSupplierRepo) CleanupProductSuppliers(supplierID uint) error {
 	return nil
 }
 
+func (m *mockSupplierRepo) ListReceivedPurchaseOrders(supplierID uint, from, to time.Time) ([]models.PurchaseOrder, error) {
+	if m.listReceivedPurchaseOrdersFn != nil {
+		return m.listReceivedPurchaseOrdersFn(supplierID, from, to)
+	}
+	return nil, nil
+}
+
+func (m *mockSupplierRepo) ListPurchaseOrders(supplierID uint, from, to time.Time) ([]models.PurchaseOrder, error) {
+	if m.listPurchaseOrdersFn != nil {
+		return m.listPurchaseOrdersFn(supplierID, from, to)
+	}
+	return nil, nil
+}
+
+func (m *mockSupplierRepo) ListUnpaidPurchaseOrders(supplierID uint) ([]models.PurchaseOrder, error) {
+	if m.listUnpaidPurchaseOrdersFn != nil {
+		return m.listUnpaidPurchaseOrdersFn(supplierID)
+	}
+	return nil, nil
+}
+
+func (m *mockSupplierRepo) EmailExists(email string, excludeID uint) (bool, error) {
+	if m.emailExistsFn != nil {
+		return m.emailExistsFn(email, excludeID)
+	}
+	return false, nil
+}
+
+func (m *mockSupplierRepo) NameExists(name string) (bool, error) {
+	if m.nameExistsFn != nil {
+		return m.nameExistsFn(name)
+	}
+	return false, nil
+}
+
 func TestCreateSupplier_Valid_Succeeds(t *testing.T) {
 	repo := &mockSupplierRepo{
 		createFn: func(s *models.Supplier) error {
@@ -157,6 +198,90 @@ func TestCreateSupplier_InvalidEmail_ReturnsValidation(t *testing.T) {
 	assert.Contains(t, serviceErr.Message, "email")
 }
 
+func TestCreateSupplier_InvalidWebsite_ReturnsValidation(t *testing.T) {
+	repo := &mockSupplierRepo{}
+	svc := NewSupplierService(repo)
+
+	input := CreateSupplierInput{
+		Name:    "Test",
+		Address: "Addr",
+		Website: "htp://example",
+	}
+
+	supplier, err := svc.CreateSupplier(input)
+	assert.Nil(t, supplier)
+	require.Error(t, err)
+
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Contains(t, serviceErr.Message, "website")
+}
+
+func TestCreateSupplier_DuplicateEmailWithUniquePolicy_ReturnsConflict(t *testing.T) {
+	createCalled := false
+	repo := &mockSupplierRepo{
+		emailExistsFn: func(email string, excludeID uint) (bool, error) {
+			assert.Equal(t, "Sales@Example.com", email)
+			assert.Equal(t, uint(0), excludeID)
+			return true, nil
+		},
+		createFn: func(supplier *models.Supplier) error {
+			createCalled = true
+			return nil
+		},
+	}
+	svc := NewSupplierService(repo, SupplierConfig{UniqueEmail: true})
+
+	input := CreateSupplierInput{
+		Name:    "Test",
+		Address: "Addr",
+		Email:   "Sales@Example.com",
+	}
+
+	supplier, err := svc.CreateSupplier(input)
+	assert.Nil(t, supplier)
+	require.Error(t, err)
+
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrConflict, serviceErr.Err)
+	assert.Equal(t, "SUPPLIER_EMAIL_EXISTS", serviceErr.Code)
+	assert.False(t, createCalled)
+}
+
+func TestCreateSupplier_DuplicateEmailWithoutPolicy_Succeeds(t *testing.T) {
+	repo := &mockSupplierRepo{
+		emailExistsFn: func(email string, excludeID uint) (bool, error) {
+			t.Fatal("email uniqueness should not be checked when the policy is off")
+			return true, nil
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	_, err := svc.CreateSupplier(CreateSupplierInput{Name: "Test", Address: "Addr", Email: "sales@example.com"})
+	require.NoError(t, err)
+}
+
+func TestUpdateSupplier_DuplicateEmail_ExcludesSelf(t *testing.T) {
+	repo := &mockSupplierRepo{
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return &models.Supplier{ID: id, Name: "Test", Address: "Addr", Active: true}, nil
+		},
+		emailExistsFn: func(email string, excludeID uint) (bool, error) {
+			assert.Equal(t, uint(7), excludeID)
+			return true, nil
+		},
+	}
+	svc := NewSupplierService(repo, SupplierConfig{UniqueEmail: true})
+
+	_, err := svc.UpdateSupplier(7, UpdateSupplierInput{Email: "sales@example.com"})
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrConflict, serviceErr.Err)
+}
+
 func TestCreateSupplier_BankAccountMissingFields_ReturnsValidation(t *testing.T) {
 	repo := &mockSupplierRepo{}
 	svc := NewSupplierService(repo)
@@ -196,6 +321,212 @@ func TestCreateSupplier_BankAccountMissingFields_ReturnsValidation(t *testing.T)
 	assert.Equal(t, ErrValidation, serviceErr.Err)
 }
 
+func numericBankAccountConfig() SupplierConfig {
+	return SupplierConfig{BankAccountMinLength: 6, BankAccountMaxLength: 12}
+}
+
+func TestCreateSupplier_NonNumericBankAccount_ReturnsValidation(t *testing.T) {
+	svc := NewSupplierService(&mockSupplierRepo{}, numericBankAccountConfig())
+
+	supplier, err := svc.CreateSupplier(CreateSupplierInput{
+		Name:         "Test",
+		Address:      "Addr",
+		BankAccounts: []BankAccountInput{{AccountName: "BCA", AccountNumber: "12345O789"}},
+	})
+
+	assert.Nil(t, supplier)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "INVALID_BANK_ACCOUNT", serviceErr.Code)
+	assert.Contains(t, serviceErr.Message, "only digits")
+}
+
+func TestCreateSupplier_BankAccountLengthOutOfRange_ReturnsValidation(t *testing.T) {
+	svc := NewSupplierService(&mockSupplierRepo{}, numericBankAccountConfig())
+
+	for _, number := range []string{"12345", "1234567890123"} {
+		supplier, err := svc.CreateSupplier(CreateSupplierInput{
+			Name:         "Test",
+			Address:      "Addr",
+			BankAccounts: []BankAccountInput{{AccountName: "BCA", AccountNumber: number}},
+		})
+
+		assert.Nil(t, supplier, number)
+		serviceErr, ok := err.(*ServiceError)
+		require.True(t, ok, number)
+		assert.Equal(t, ErrValidation, serviceErr.Err)
+		assert.Equal(t, "Bank account 1: accountNumber must be between 6 and 12 digits", serviceErr.Message)
+	}
+}
+
+func TestCreateSupplier_ValidNumericBankAccount_Succeeds(t *testing.T) {
+	repo := &mockSupplierRepo{
+		createFn: func(s *models.Supplier) error {
+			s.ID = 1
+			return nil
+		},
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return &models.Supplier{ID: 1, Name: "Test", Address: "Addr", Active: true}, nil
+		},
+	}
+	svc := NewSupplierService(repo, numericBankAccountConfig())
+
+	supplier, err := svc.CreateSupplier(CreateSupplierInput{
+		Name:         "Test",
+		Address:      "Addr",
+		BankAccounts: []BankAccountInput{{AccountName: "BCA", AccountNumber: "1234567890"}},
+	})
+
+	require.NoError(t, err)
+	assert.NotNil(t, supplier)
+}
+
+func TestUpdateSupplier_NonNumericBankAccount_ReturnsValidation(t *testing.T) {
+	repo := &mockSupplierRepo{
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return &models.Supplier{ID: 1, Name: "Old", Address: "Addr", Active: true}, nil
+		},
+		updateFn: func(s *models.Supplier, ba []models.SupplierBankAccount) error {
+			t.Fatal("update should not be called")
+			return nil
+		},
+	}
+	svc := NewSupplierService(repo, numericBankAccountConfig())
+
+	_, err := svc.UpdateSupplier(1, UpdateSupplierInput{
+		Name:         "New",
+		Address:      "Addr",
+		BankAccounts: &[]BankAccountInput{{AccountName: "BCA", AccountNumber: "123-456-789"}},
+	})
+
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+}
+
+func TestCreateSupplier_DuplicateBankAccountNumber_ReturnsConflict(t *testing.T) {
+	repo := &mockSupplierRepo{
+		createFn: func(s *models.Supplier) error {
+			t.Fatal("create should not be called")
+			return nil
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	supplier, err := svc.CreateSupplier(CreateSupplierInput{
+		Name:    "Test",
+		Address: "Addr",
+		BankAccounts: []BankAccountInput{
+			{AccountName: "BCA", AccountNumber: "1234567890"},
+			{AccountName: "Mandiri", AccountNumber: "0987654321"},
+			{AccountName: "BCA again", AccountNumber: " 1234567890 "},
+		},
+	})
+
+	assert.Nil(t, supplier)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrConflict, serviceErr.Err)
+	assert.Equal(t, "DUPLICATE_BANK_ACCOUNT", serviceErr.Code)
+	assert.Equal(t, "Bank account 3: accountNumber duplicates bank account 1", serviceErr.Message)
+}
+
+func TestUpdateSupplier_DuplicateBankAccountNumber_ReturnsConflict(t *testing.T) {
+	repo := &mockSupplierRepo{
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return &models.Supplier{ID: id, Name: "Test", Address: "Addr", Active: true}, nil
+		},
+		updateFn: func(s *models.Supplier, ba []models.SupplierBankAccount) error {
+			t.Fatal("update should not be called")
+			return nil
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	_, err := svc.UpdateSupplier(1, UpdateSupplierInput{
+		BankAccounts: &[]BankAccountInput{
+			{AccountName: "BCA", AccountNumber: "0082001234"},
+			{AccountName: "BCA again", AccountNumber: "0082001234"},
+		},
+	})
+
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrConflict, serviceErr.Err)
+	assert.Equal(t, "DUPLICATE_BANK_ACCOUNT", serviceErr.Code)
+}
+
+func TestCreateSupplier_BankAccountNumberOfOtherSupplier_Succeeds(t *testing.T) {
+	repo := &mockSupplierRepo{
+		createFn: func(s *models.Supplier) error {
+			s.ID = 2
+			return nil
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	// Each supplier lists the number once, so both are accepted
+	for _, name := range []string{"Supplier A", "Supplier B"} {
+		_, err := svc.CreateSupplier(CreateSupplierInput{
+			Name:         name,
+			Address:      "Addr",
+			BankAccounts: []BankAccountInput{{AccountName: "BCA", AccountNumber: "1234567890"}},
+		})
+		require.NoError(t, err, name)
+	}
+}
+
+func TestCreateSupplier_WhitespaceOnlyName_ReturnsValidation(t *testing.T) {
+	svc := NewSupplierService(&mockSupplierRepo{})
+
+	_, err := svc.CreateSupplier(CreateSupplierInput{Name: "   ", Address: "Addr"})
+
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "Name is required", serviceErr.Message)
+}
+
+func TestCreateSupplier_PaddedNameAndAddress_StoredTrimmed(t *testing.T) {
+	var saved *models.Supplier
+	repo := &mockSupplierRepo{
+		createFn: func(s *models.Supplier) error {
+			saved = s
+			return nil
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	_, err := svc.CreateSupplier(CreateSupplierInput{Name: "  PT Sumber Makmur ", Address: " Jakarta  "})
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, "PT Sumber Makmur", saved.Name)
+	assert.Equal(t, "Jakarta", saved.Address)
+}
+
+func TestUpdateSupplier_WhitespaceOnlyName_ReturnsValidation(t *testing.T) {
+	repo := &mockSupplierRepo{
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return &models.Supplier{ID: id, Name: "Old", Address: "Addr", Active: true}, nil
+		},
+		updateFn: func(s *models.Supplier, ba []models.SupplierBankAccount) error {
+			t.Fatal("update should not be called")
+			return nil
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	for _, input := range []UpdateSupplierInput{{Name: "   "}, {Address: "\t"}} {
+		_, err := svc.UpdateSupplier(1, input)
+
+		serviceErr, ok := err.(*ServiceError)
+		require.True(t, ok)
+		assert.Equal(t, ErrValidation, serviceErr.Err)
+		assert.Contains(t, serviceErr.Message, "is required")
+	}
+}
+
 func TestUpdateSupplier_SyncsBankAccountsAtomically(t *testing.T) {
 	existingSupplier := &models.Supplier{
 		ID:      1,
@@ -328,3 +659,271 @@ func TestDeleteSupplier_NoReferences_Deletes(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, deleted)
 }
+
+func TestSupplierPerformance_ComputesMetrics(t *testing.T) {
+	expectedOnTime := "2024-03-10"
+	expectedLate := "2024-03-05"
+	receivedOnTime := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+	receivedLate := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+	subtotal, grandTotal := 450000.0, 445500.0
+	qty8, qty10, qty5 := 8, 10, 5
+	price, priceB := 25000.0, 10000.0
+
+	repo := &mockSupplierRepo{
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return &models.Supplier{ID: id, Name: "PT Sumber Makmur"}, nil
+		},
+		listReceivedPurchaseOrdersFn: func(supplierID uint, from, to time.Time) ([]models.PurchaseOrder, error) {
+			return []models.PurchaseOrder{
+				{
+					// Ordered 3 Mar, received 8 Mar (5 days), on time
+					Date:         "2024-03-03",
+					ExpectedDate: &expectedOnTime,
+					ReceivedDate: &receivedOnTime,
+					Subtotal:     &subtotal,
+					GrandTotal:   &grandTotal,
+					Items: []models.PurchaseOrderItem{
+						{OrderedQty: 10, ReceivedQty: &qty8, ReceivedPrice: &price},
+						{OrderedQty: 10, ReceivedQty: &qty10, ReceivedPrice: &priceB},
+					},
+				},
+				{
+					// Ordered 1 Mar, received 11 Mar (10 days), late
+					Date:         "2024-03-01T00:00:00Z",
+					ExpectedDate: &expectedLate,
+					ReceivedDate: &receivedLate,
+					Items: []models.PurchaseOrderItem{
+						{OrderedQty: 10, ReceivedQty: &qty5, ReceivedPrice: &priceB},
+					},
+				},
+			}, nil
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	perf, err := svc.Performance(1, from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, perf.ReceivedOrders)
+	assert.Equal(t, 1, perf.OnTimeOrders)
+	require.NotNil(t, perf.OnTimeRate)
+	assert.Equal(t, 0.5, *perf.OnTimeRate)
+	require.NotNil(t, perf.AvgLeadTimeDays)
+	assert.Equal(t, 7.5, *perf.AvgLeadTimeDays)
+	assert.Equal(t, 30, perf.TotalOrderedQty)
+	assert.Equal(t, 23, perf.TotalReceivedQty)
+	require.NotNil(t, perf.FillRate)
+	assert.Equal(t, 0.7667, *perf.FillRate)
+	// 445500 grand total + 5 * 10000
+	assert.Equal(t, 495500.0, perf.TotalSpend)
+	assert.Equal(t, "2024-03-01", perf.From)
+	assert.Equal(t, "2024-03-31", perf.To)
+}
+
+func TestOutstandingPayables_SumsUnpaidOrdersWithDueDates(t *testing.T) {
+	receivedA := time.Date(2024, 3, 8, 14, 30, 0, 0, time.UTC)
+	receivedB := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	subtotal := 450000.0
+	taxedSubtotal, taxedGrandTotal := 100000.0, 99000.0
+	qty5, price := 5, 10000.0
+
+	repo := &mockSupplierRepo{
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return &models.Supplier{ID: id, Name: "PT Sumber Makmur"}, nil
+		},
+		listUnpaidPurchaseOrdersFn: func(supplierID uint) ([]models.PurchaseOrder, error) {
+			return []models.PurchaseOrder{
+				{ID: 1, PONumber: "PO-0001", Status: "received", ReceivedDate: &receivedA, Subtotal: &subtotal},
+				{
+					// No stored subtotal, valued from received items
+					ID: 2, PONumber: "PO-0002", Status: "partially_received", ReceivedDate: &receivedB,
+					Items: []models.PurchaseOrderItem{
+						{OrderedQty: 10, ReceivedQty: &qty5, ReceivedPrice: &price},
+					},
+				},
+				{
+					// 10% discount then 10% tax: the grand total is owed
+					ID: 3, PONumber: "PO-0003", Status: "received", ReceivedDate: &receivedB,
+					Subtotal: &taxedSubtotal, GrandTotal: &taxedGrandTotal,
+				},
+			}, nil
+		},
+	}
+	svc := NewSupplierService(repo, SupplierConfig{PaymentTermDays: 30})
+
+	payables, err := svc.OutstandingPayables(1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 599000.0, payables.TotalOutstanding)
+	require.Len(t, payables.Orders, 3)
+	assert.Equal(t, "PO-0001", payables.Orders[0].PONumber)
+	assert.Equal(t, 450000.0, payables.Orders[0].Amount)
+	assert.Equal(t, "2024-03-08", payables.Orders[0].ReceivedDate)
+	assert.Equal(t, "2024-04-07", payables.Orders[0].DueDate)
+	assert.Equal(t, 50000.0, payables.Orders[1].Amount)
+	assert.Equal(t, "2024-04-19", payables.Orders[1].DueDate)
+	assert.Equal(t, 99000.0, payables.Orders[2].Amount)
+}
+
+func TestOutstandingPayables_SupplierNotFound_ReturnsNotFound(t *testing.T) {
+	repo := &mockSupplierRepo{
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return nil, errors.New("record not found")
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	_, err := svc.OutstandingPayables(99)
+	require.Error(t, err)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrNotFound, svcErr.Err)
+}
+
+func TestSupplierPerformance_NoOrders_ReturnsNilRates(t *testing.T) {
+	repo := &mockSupplierRepo{
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return &models.Supplier{ID: id}, nil
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	perf, err := svc.Performance(1, time.Now().AddDate(0, 0, -30), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, perf.ReceivedOrders)
+	assert.Nil(t, perf.OnTimeRate)
+	assert.Nil(t, perf.AvgLeadTimeDays)
+	assert.Nil(t, perf.FillRate)
+	assert.Equal(t, 0.0, perf.TotalSpend)
+}
+
+func TestSupplierPerformance_FromAfterTo_ReturnsValidation(t *testing.T) {
+	svc := NewSupplierService(&mockSupplierRepo{})
+
+	_, err := svc.Performance(1, time.Now(), time.Now().AddDate(0, 0, -1))
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+}
+
+func TestSupplierPerformance_SupplierNotFound_ReturnsNotFound(t *testing.T) {
+	repo := &mockSupplierRepo{
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return nil, errors.New("record not found")
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	_, err := svc.Performance(99, time.Now().AddDate(0, 0, -30), time.Now())
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrNotFound, serviceErr.Err)
+}
+
+func TestSupplierMetrics_AggregatesOrders(t *testing.T) {
+	received1 := time.Date(2024, 3, 8, 15, 30, 0, 0, time.UTC)
+	received2 := time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)
+	subtotal1, subtotal2 := 450000.0, 50000.5
+	grandTotal2 := 55000.5
+	qty8, qty10, qty5 := 8, 10, 5
+
+	repo := &mockSupplierRepo{
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return &models.Supplier{ID: id, Name: "PT Sumber Makmur"}, nil
+		},
+		listPurchaseOrdersFn: func(supplierID uint, from, to time.Time) ([]models.PurchaseOrder, error) {
+			assert.Equal(t, uint(1), supplierID)
+			return []models.PurchaseOrder{
+				{
+					// Received after 5 days, one short line
+					Status:       "received",
+					Date:         "2024-03-03",
+					ReceivedDate: &received1,
+					Subtotal:     &subtotal1,
+					Items: []models.PurchaseOrderItem{
+						{OrderedQty: 10, ReceivedQty: &qty8},
+						{OrderedQty: 10, ReceivedQty: &qty10},
+					},
+				},
+				{
+					// Received after 10 days, one short line
+					Status:       "completed",
+					Date:         "2024-03-03T00:00:00Z",
+					ReceivedDate: &received2,
+					Subtotal:     &subtotal2,
+					GrandTotal:   &grandTotal2,
+					Items: []models.PurchaseOrderItem{
+						{OrderedQty: 10, ReceivedQty: &qty5},
+					},
+				},
+				{
+					// Not received yet: counted, nothing else
+					Status: "sent",
+					Date:   "2024-03-20",
+					Items:  []models.PurchaseOrderItem{{OrderedQty: 4}},
+				},
+			}, nil
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	metrics, err := svc.Metrics(1, from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint(1), metrics.SupplierID)
+	assert.Equal(t, "2024-03-01", metrics.From)
+	assert.Equal(t, "2024-03-31", metrics.To)
+	assert.Equal(t, 3, metrics.TotalOrders)
+	assert.Equal(t, 2, metrics.ReceivedOrders)
+	assert.Equal(t, 505000.5, metrics.TotalReceivedValue)
+	assert.Equal(t, 7.5, metrics.AvgTurnaroundDays)
+	assert.Equal(t, 2, metrics.DiscrepancyItems)
+}
+
+func TestSupplierMetrics_NoOrders_ReturnsZeros(t *testing.T) {
+	repo := &mockSupplierRepo{
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return &models.Supplier{ID: id}, nil
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	metrics, err := svc.Metrics(1, time.Now().AddDate(0, 0, -30), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, metrics.TotalOrders)
+	assert.Equal(t, 0, metrics.ReceivedOrders)
+	assert.Equal(t, 0.0, metrics.TotalReceivedValue)
+	assert.Equal(t, 0.0, metrics.AvgTurnaroundDays)
+	assert.Equal(t, 0, metrics.DiscrepancyItems)
+}
+
+func TestSupplierMetrics_SupplierNotFound_ReturnsNotFound(t *testing.T) {
+	repo := &mockSupplierRepo{
+		findByIDFn: func(id uint) (*models.Supplier, error) {
+			return nil, errors.New("record not found")
+		},
+	}
+	svc := NewSupplierService(repo)
+
+	_, err := svc.Metrics(99, time.Now().AddDate(0, 0, -30), time.Now())
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrNotFound, serviceErr.Err)
+}
+
+func TestSupplierMetrics_FromAfterTo_ReturnsValidation(t *testing.T) {
+	svc := NewSupplierService(&mockSupplierRepo{})
+
+	_, err := svc.Metrics(1, time.Now(), time.Now().AddDate(0, 0, -1))
+	require.Error(t, err)
+	serviceErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+}
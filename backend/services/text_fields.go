@@ -0,0 +1,42 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// requiredText trims value and checks the result is not blank and at most
+// maxLen characters. field names the input in error messages. Values are
+// always stored trimmed, so "   " counts as missing rather than as a name.
+func requiredText(field, value string, maxLen int) (string, *ServiceError) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return "", &ServiceError{
+			Err:     ErrValidation,
+			Message: fmt.Sprintf("%s is required", field),
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if maxLen > 0 && len(trimmed) > maxLen {
+		return "", &ServiceError{
+			Err:     ErrValidation,
+			Message: fmt.Sprintf("%s must be at most %d characters", field, maxLen),
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	return trimmed, nil
+}
+
+// updatedText is requiredText for partial updates, where an empty value
+// leaves the field unchanged. It reports whether a new value was given; a
+// whitespace-only value is rejected instead of being treated as absent.
+func updatedText(field, value string, maxLen int) (string, bool, *ServiceError) {
+	if value == "" {
+		return "", false, nil
+	}
+	trimmed, err := requiredText(field, value, maxLen)
+	if err != nil {
+		return "", false, err
+	}
+	return trimmed, true, nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredText(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr string
+	}{
+		{"Shelf A", "Shelf A", ""},
+		{"  Shelf A \t", "Shelf A", ""},
+		{"", "", "Name is required"},
+		{"   ", "", "Name is required"},
+		{"\t\n", "", "Name is required"},
+		{strings.Repeat("a", 11), "", "Name must be at most 10 characters"},
+		{"  " + strings.Repeat("a", 10) + "  ", strings.Repeat("a", 10), ""},
+	}
+
+	for _, tt := range tests {
+		got, err := requiredText("Name", tt.value, 10)
+		if tt.wantErr != "" {
+			require.NotNil(t, err, "%q", tt.value)
+			assert.Equal(t, ErrValidation, err.Err)
+			assert.Equal(t, tt.wantErr, err.Message)
+			continue
+		}
+		require.Nil(t, err, "%q", tt.value)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestUpdatedText(t *testing.T) {
+	_, ok, err := updatedText("Name", "", 255)
+	assert.Nil(t, err)
+	assert.False(t, ok, "empty value leaves the field unchanged")
+
+	_, ok, err = updatedText("Name", "   ", 255)
+	require.NotNil(t, err)
+	assert.Equal(t, "Name is required", err.Message)
+	assert.False(t, ok)
+
+	got, ok, err := updatedText("Name", "  New Name ", 255)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "New Name", got)
+}
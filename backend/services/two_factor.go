@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/utils"
+)
+
+const (
+	// twoFactorIssuer is the account issuer shown in authenticator apps.
+	twoFactorIssuer = "PointOfSale"
+	// twoFactorChallengeTTL is how long a password-verified login waits for its code.
+	twoFactorChallengeTTL = 5 * time.Minute
+)
+
+// TwoFactorSetup is returned when a user starts enabling two-factor authentication.
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauthUrl"`
+}
+
+// EnableTwoFactor generates a new TOTP secret for the user. Two-factor stays
+// off until the user confirms a code from their authenticator app.
+func (s *AuthService) EnableTwoFactor(userID uint) (*TwoFactorSetup, *ServiceError) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     ErrNotFound,
+			Message: "User not found",
+			Code:    "USER_NOT_FOUND",
+		}
+	}
+	if user.TwoFactorEnabled {
+		return nil, &ServiceError{
+			Err:     ErrConflict,
+			Message: "Two-factor authentication is already enabled",
+			Code:    "TWO_FACTOR_ALREADY_ENABLED",
+		}
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to generate two-factor secret",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	user.TwoFactorSecret = secret
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to save two-factor secret",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	return &TwoFactorSetup{
+		Secret:     secret,
+		OTPAuthURL: utils.TOTPURL(twoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor turns two-factor on once the user proves their
+// authenticator app produces valid codes for the pending secret.
+func (s *AuthService) ConfirmTwoFactor(userID uint, code string) *ServiceError {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return &ServiceError{
+			Err:     ErrNotFound,
+			Message: "User not found",
+			Code:    "USER_NOT_FOUND",
+		}
+	}
+	if user.TwoFactorEnabled {
+		return &ServiceError{
+			Err:     ErrConflict,
+			Message: "Two-factor authentication is already enabled",
+			Code:    "TWO_FACTOR_ALREADY_ENABLED",
+		}
+	}
+	if user.TwoFactorSecret == "" {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Two-factor setup has not been started",
+			Code:    "TWO_FACTOR_NOT_STARTED",
+		}
+	}
+	if !utils.ValidateTOTPCode(user.TwoFactorSecret, code, time.Now()) {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Invalid two-factor code",
+			Code:    "INVALID_TWO_FACTOR_CODE",
+		}
+	}
+
+	user.TwoFactorEnabled = true
+	if err := s.userRepo.Update(user); err != nil {
+		return &ServiceError{
+			Err:     err,
+			Message: "Failed to enable two-factor authentication",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	return nil
+}
+
+// startTwoFactorChallenge stores a short-lived pending login and returns the
+// token the client exchanges, together with a code, via VerifyTwoFactor.
+func (s *AuthService) startTwoFactorChallenge(ctx context.Context, user *models.User) (*LoginResponse, *ServiceError) {
+	tempToken, err := utils.GenerateResetToken()
+	if err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to start two-factor login",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+	if err := s.redis.Set(ctx, "2fa_pending:"+tempToken, fmt.Sprintf("%d", user.ID), twoFactorChallengeTTL).Err(); err != nil {
+		return nil, &ServiceError{
+			Err:     err,
+			Message: "Failed to start two-factor login",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	return &LoginResponse{
+		TwoFactorRequired: true,
+		TwoFactorToken:    tempToken,
+	}, nil
+}
+
+// VerifyTwoFactor completes a two-factor login. Wrong codes count towards the
+// account lockout, so codes cannot be brute-forced by repeating the login.
+func (s *AuthService) VerifyTwoFactor(tempToken, code, userAgent string) (*LoginResponse, *ServiceError) {
+	ctx := context.Background()
+	pendingKey := "2fa_pending:" + tempToken
+	userIDStr, err := s.redis.Get(ctx, pendingKey).Result()
+	if err != nil || tempToken == "" {
+		return nil, &ServiceError{
+			Err:     ErrUnauthorized,
+			Message: "Two-factor login has expired, please sign in again",
+			Code:    "INVALID_TOKEN",
+		}
+	}
+
+	var userID uint
+	if _, err := fmt.Sscanf(userIDStr, "%d", &userID); err != nil {
+		return nil, &ServiceError{
+			Err:     ErrUnauthorized,
+			Message: "Two-factor login has expired, please sign in again",
+			Code:    "INVALID_TOKEN",
+		}
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || !user.TwoFactorEnabled {
+		return nil, &ServiceError{
+			Err:     ErrUnauthorized,
+			Message: "Two-factor login has expired, please sign in again",
+			Code:    "INVALID_TOKEN",
+		}
+	}
+
+	attemptsKey := loginAttemptsKey(user.Email)
+	if svcErr := s.checkLoginLocked(ctx, attemptsKey); svcErr != nil {
+		s.redis.Del(ctx, pendingKey)
+		return nil, svcErr
+	}
+	if !utils.ValidateTOTPCode(user.TwoFactorSecret, code, time.Now()) {
+		s.recordFailedLogin(ctx, attemptsKey)
+		return nil, &ServiceError{
+			Err:     ErrUnauthorized,
+			Message: "Invalid two-factor code",
+			Code:    "INVALID_TWO_FACTOR_CODE",
+		}
+	}
+
+	s.redis.Del(ctx, pendingKey)
+	return s.issueLoginTokens(ctx, user, userAgent)
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTwoFactorTest returns an auth service whose only user has two-factor enabled
+func setupTwoFactorTest(t *testing.T) (*AuthService, *miniredis.Miniredis, *models.User) {
+	t.Helper()
+	service, mockRepo, _, mr, cfg := setupAuthServiceTest(t)
+	cfg.LoginMaxAttempts = 5
+	cfg.LoginLockoutWindow = 15 * time.Minute
+
+	hashedPassword, _ := utils.HashPassword("Password123!")
+	secret, err := utils.GenerateTOTPSecret()
+	require.NoError(t, err)
+	user := &models.User{
+		ID:               1,
+		Email:            "john@example.com",
+		PasswordHash:     hashedPassword,
+		Status:           "active",
+		TwoFactorSecret:  secret,
+		TwoFactorEnabled: true,
+	}
+	mockRepo.findByEmailFn = func(email string) (*models.User, error) {
+		return user, nil
+	}
+	mockRepo.findByIDFn = func(id uint) (*models.User, error) {
+		return user, nil
+	}
+	return service, mr, user
+}
+
+func currentTOTPCode(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := utils.GenerateTOTPCode(secret, time.Now())
+	require.NoError(t, err)
+	return code
+}
+
+func TestLogin_TwoFactorEnabled_ReturnsChallengeWithoutTokens(t *testing.T) {
+	service, mr, _ := setupTwoFactorTest(t)
+	defer mr.Close()
+
+	response, svcErr := service.Login(LoginInput{Email: "john@example.com", Password: "Password123!"})
+
+	require.Nil(t, svcErr)
+	assert.True(t, response.TwoFactorRequired)
+	assert.NotEmpty(t, response.TwoFactorToken)
+	assert.Nil(t, response.TokenPair)
+	assert.Nil(t, response.User)
+	assert.True(t, mr.Exists("2fa_pending:"+response.TwoFactorToken))
+}
+
+func TestVerifyTwoFactor_ValidCode_ReturnsTokens(t *testing.T) {
+	service, mr, user := setupTwoFactorTest(t)
+	defer mr.Close()
+
+	challenge, svcErr := service.Login(LoginInput{Email: "john@example.com", Password: "Password123!"})
+	require.Nil(t, svcErr)
+
+	response, svcErr := service.VerifyTwoFactor(challenge.TwoFactorToken, currentTOTPCode(t, user.TwoFactorSecret), "")
+
+	require.Nil(t, svcErr)
+	require.NotNil(t, response.TokenPair)
+	assert.NotEmpty(t, response.AccessToken)
+	assert.NotEmpty(t, response.RefreshToken)
+	assert.Equal(t, uint(1), response.User.ID)
+	assert.False(t, response.TwoFactorRequired)
+
+	// The challenge token can only be used once
+	assert.False(t, mr.Exists("2fa_pending:"+challenge.TwoFactorToken))
+}
+
+func TestVerifyTwoFactor_InvalidCode_ReturnsUnauthorized(t *testing.T) {
+	service, mr, _ := setupTwoFactorTest(t)
+	defer mr.Close()
+
+	challenge, svcErr := service.Login(LoginInput{Email: "john@example.com", Password: "Password123!"})
+	require.Nil(t, svcErr)
+
+	response, svcErr := service.VerifyTwoFactor(challenge.TwoFactorToken, "000000", "")
+
+	assert.Nil(t, response)
+	require.NotNil(t, svcErr)
+	assert.Equal(t, ErrUnauthorized, svcErr.Err)
+	assert.Equal(t, "INVALID_TWO_FACTOR_CODE", svcErr.Code)
+	attempts, err := mr.Get("login_attempts:john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "1", attempts)
+}
+
+func TestVerifyTwoFactor_RepeatedInvalidCodes_LocksAccount(t *testing.T) {
+	service, mr, user := setupTwoFactorTest(t)
+	defer mr.Close()
+
+	challenge, svcErr := service.Login(LoginInput{Email: "john@example.com", Password: "Password123!"})
+	require.Nil(t, svcErr)
+	for i := 0; i < 5; i++ {
+		service.VerifyTwoFactor(challenge.TwoFactorToken, "000000", "")
+	}
+
+	_, svcErr = service.VerifyTwoFactor(challenge.TwoFactorToken, currentTOTPCode(t, user.TwoFactorSecret), "")
+
+	require.NotNil(t, svcErr)
+	assert.Equal(t, "ACCOUNT_LOCKED", svcErr.Code)
+}
+
+func TestVerifyTwoFactor_ExpiredToken_ReturnsUnauthorized(t *testing.T) {
+	service, mr, user := setupTwoFactorTest(t)
+	defer mr.Close()
+
+	challenge, svcErr := service.Login(LoginInput{Email: "john@example.com", Password: "Password123!"})
+	require.Nil(t, svcErr)
+	mr.FastForward(twoFactorChallengeTTL + time.Second)
+
+	_, svcErr = service.VerifyTwoFactor(challenge.TwoFactorToken, currentTOTPCode(t, user.TwoFactorSecret), "")
+
+	require.NotNil(t, svcErr)
+	assert.Equal(t, ErrUnauthorized, svcErr.Err)
+	assert.Equal(t, "INVALID_TOKEN", svcErr.Code)
+}
+
+func TestEnableTwoFactor_ThenConfirm_EnablesTwoFactor(t *testing.T) {
+	service, mockRepo, _, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	user := &models.User{ID: 1, Email: "john@example.com", Status: "active"}
+	mockRepo.findByIDFn = func(id uint) (*models.User, error) {
+		return user, nil
+	}
+
+	setup, svcErr := service.EnableTwoFactor(1)
+	require.Nil(t, svcErr)
+	assert.NotEmpty(t, setup.Secret)
+	assert.Contains(t, setup.OTPAuthURL, "otpauth://totp/")
+	assert.Contains(t, setup.OTPAuthURL, "secret="+setup.Secret)
+	assert.False(t, user.TwoFactorEnabled)
+
+	svcErr = service.ConfirmTwoFactor(1, "000000")
+	require.NotNil(t, svcErr)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+	assert.Equal(t, "INVALID_TWO_FACTOR_CODE", svcErr.Code)
+	assert.False(t, user.TwoFactorEnabled)
+
+	svcErr = service.ConfirmTwoFactor(1, currentTOTPCode(t, setup.Secret))
+	assert.Nil(t, svcErr)
+	assert.True(t, user.TwoFactorEnabled)
+}
+
+func TestEnableTwoFactor_AlreadyEnabled_ReturnsConflict(t *testing.T) {
+	service, mr, _ := setupTwoFactorTest(t)
+	defer mr.Close()
+
+	setup, svcErr := service.EnableTwoFactor(1)
+
+	assert.Nil(t, setup)
+	require.NotNil(t, svcErr)
+	assert.Equal(t, ErrConflict, svcErr.Err)
+	assert.Equal(t, "TWO_FACTOR_ALREADY_ENABLED", svcErr.Code)
+}
+
+func TestConfirmTwoFactor_NotStarted_ReturnsValidationError(t *testing.T) {
+	service, mockRepo, _, mr, _ := setupAuthServiceTest(t)
+	defer mr.Close()
+
+	mockRepo.findByIDFn = func(id uint) (*models.User, error) {
+		return &models.User{ID: 1, Email: "john@example.com"}, nil
+	}
+
+	svcErr := service.ConfirmTwoFactor(1, "123456")
+
+	require.NotNil(t, svcErr)
+	assert.Equal(t, "TWO_FACTOR_NOT_STARTED", svcErr.Code)
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// retryableSQLStates are Postgres error codes for transactions that failed only
+// because of contention and are safe to run again from the start.
+var retryableSQLStates = map[string]bool{
+	"40001": true, // serialization_failure
+	"40P01": true, // deadlock_detected
+}
+
+// uniqueViolationSQLState is the Postgres error code for a unique constraint
+// violation.
+const uniqueViolationSQLState = "23505"
+
+// trxNumberConstraint is the unique constraint on sales transaction numbers.
+const trxNumberConstraint = "sales_transactions_transaction_number_key"
+
+// retryableError marks an error that is safe to retry even though its SQL
+// state is not in retryableSQLStates.
+type retryableError struct {
+	err error
+}
+
+func (e retryableError) Error() string {
+	return e.err.Error()
+}
+
+// isRetryableTxError reports whether err is a serialization failure, deadlock
+// or an error marked retryable by the caller.
+func isRetryableTxError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if _, ok := err.(retryableError); ok {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && retryableSQLStates[pgErr.Code]
+}
+
+// retryTx runs fn until it succeeds, returns a non-retryable error, or
+// maxRetries additional attempts have been made. The wait between attempts
+// grows linearly with the attempt number.
+func retryTx(maxRetries int, backoff time.Duration, fn func(attempt int) error) error {
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 && backoff > 0 {
+			time.Sleep(backoff * time.Duration(attempt))
+		}
+		err = fn(attempt)
+		if !isRetryableTxError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// unwrapRetryable returns the error wrapped by retryableError, or err itself.
+func unwrapRetryable(err error) error {
+	if re, ok := err.(retryableError); ok {
+		return re.err
+	}
+	return err
+}
+
+// isDuplicateTrxNumberError reports whether err is a unique violation on the
+// sales transaction number, i.e. another checkout took the same number first.
+func isDuplicateTrxNumberError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationSQLState && pgErr.ConstraintName == trxNumberConstraint
+}
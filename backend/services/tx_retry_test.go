@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryableTxError_SerializationFailure_ReturnsTrue(t *testing.T) {
+	err := &pgconn.PgError{Code: "40001", Message: "could not serialize access due to concurrent update"}
+	assert.True(t, isRetryableTxError(err))
+}
+
+func TestIsRetryableTxError_Deadlock_ReturnsTrue(t *testing.T) {
+	err := fmt.Errorf("checkout: %w", &pgconn.PgError{Code: "40P01", Message: "deadlock detected"})
+	assert.True(t, isRetryableTxError(err))
+}
+
+func TestIsRetryableTxError_OtherErrors_ReturnsFalse(t *testing.T) {
+	assert.False(t, isRetryableTxError(nil))
+	assert.False(t, isRetryableTxError(&pgconn.PgError{Code: "23505", Message: "duplicate key value"}))
+	assert.False(t, isRetryableTxError(errors.New("ERROR: could not serialize access (SQLSTATE 40001)")))
+	assert.False(t, isRetryableTxError(&ServiceError{Err: ErrValidation, Message: "40001 units requested"}))
+}
+
+func TestRetryTx_FirstAttemptSerializationFailure_Retries(t *testing.T) {
+	calls := 0
+	err := retryTx(3, 0, func(attempt int) error {
+		calls++
+		if attempt == 0 {
+			return &pgconn.PgError{Code: "40001"}
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetryTx_ExhaustsRetries_ReturnsLastError(t *testing.T) {
+	calls := 0
+	err := retryTx(2, 0, func(attempt int) error {
+		calls++
+		return &pgconn.PgError{Code: "40P01"}
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryTx_NonRetryableError_StopsImmediately(t *testing.T) {
+	calls := 0
+	err := retryTx(3, 0, func(attempt int) error {
+		calls++
+		return &ServiceError{Err: ErrValidation, Message: "Cart is empty"}
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIsRetryableTxError_MarkedRetryable_ReturnsTrue(t *testing.T) {
+	err := retryableError{err: errors.New("ERROR: duplicate key value (SQLSTATE 23505)")}
+	assert.True(t, isRetryableTxError(err))
+	assert.Equal(t, "ERROR: duplicate key value (SQLSTATE 23505)", unwrapRetryable(err).Error())
+}
+
+func TestIsDuplicateTrxNumberError(t *testing.T) {
+	assert.True(t, isDuplicateTrxNumberError(&pgconn.PgError{Code: "23505", ConstraintName: "sales_transactions_transaction_number_key"}))
+	assert.True(t, isDuplicateTrxNumberError(fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505", ConstraintName: "sales_transactions_transaction_number_key"})))
+	assert.False(t, isDuplicateTrxNumberError(nil))
+	assert.False(t, isDuplicateTrxNumberError(&pgconn.PgError{Code: "23505", ConstraintName: "products_sku_key"}))
+	assert.False(t, isDuplicateTrxNumberError(&pgconn.PgError{Code: "23503", ConstraintName: "sales_transactions_transaction_number_key"}))
+	assert.False(t, isDuplicateTrxNumberError(errors.New(`ERROR: duplicate key value violates unique constraint "sales_transactions_transaction_number_key" (SQLSTATE 23505)`)))
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/pointofsale/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func accessReportKeys(rows []AccessReportRow, userID uint) []string {
+	keys := make([]string, 0)
+	for _, row := range rows {
+		if row.UserID == userID {
+			keys = append(keys, row.Module+"/"+row.Feature+"/"+row.Action)
+		}
+	}
+	return keys
+}
+
+func TestAccessReport_MultiRoleUser_ReturnsUnionOfRoles(t *testing.T) {
+	product := models.Permission{ID: 1, Module: "Master Data", Feature: "Product"}
+	sales := models.Permission{ID: 2, Module: "Transaction", Feature: "Sales"}
+	repo := &mockUserRepository{
+		listWithPermissionsFn: func() ([]models.User, []models.RolePermission, error) {
+			users := []models.User{
+				{ID: 1, Name: "Jane", Email: "jane@example.com", Status: "active", Roles: []models.Role{{ID: 10}, {ID: 20}}},
+			}
+			rolePerms := []models.RolePermission{
+				{RoleID: 10, PermissionID: 1, Permission: product, Actions: pq.StringArray{"read", "update"}},
+				{RoleID: 20, PermissionID: 1, Permission: product, Actions: pq.StringArray{"read", "export"}},
+				{RoleID: 20, PermissionID: 2, Permission: sales, Actions: pq.StringArray{"create"}},
+				// Not assigned to the user
+				{RoleID: 30, PermissionID: 2, Permission: sales, Actions: pq.StringArray{"delete"}},
+			}
+			return users, rolePerms, nil
+		},
+	}
+	service := NewUserService(repo, nil, nil, nil)
+
+	rows, err := service.AccessReport()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Master Data/Product/export",
+		"Master Data/Product/read",
+		"Master Data/Product/update",
+		"Transaction/Sales/create",
+	}, accessReportKeys(rows, 1))
+	assert.Equal(t, "jane@example.com", rows[0].Email)
+}
+
+func TestAccessReport_SuperAdmin_ExpandsToFullCatalog(t *testing.T) {
+	repo := &mockUserRepository{
+		listWithPermissionsFn: func() ([]models.User, []models.RolePermission, error) {
+			return []models.User{{ID: 1, Name: "Admin", IsSuperAdmin: true}}, nil, nil
+		},
+	}
+	service := NewUserService(repo, nil, nil, nil)
+
+	rows, err := service.AccessReport()
+
+	require.NoError(t, err)
+	expected := 0
+	for _, perm := range getAllPermissions() {
+		expected += len(perm.Actions)
+	}
+	assert.Len(t, rows, expected)
+	keys := accessReportKeys(rows, 1)
+	assert.Contains(t, keys, "Settings/Roles & Permissions/delete")
+	assert.Contains(t, keys, "Report/Sales Report/export")
+}
+
+func TestAccessReport_UserWithoutRoles_HasNoRows(t *testing.T) {
+	repo := &mockUserRepository{
+		listWithPermissionsFn: func() ([]models.User, []models.RolePermission, error) {
+			return []models.User{{ID: 1, Name: "Pending"}}, nil, nil
+		},
+	}
+	service := NewUserService(repo, nil, nil, nil)
+
+	rows, err := service.AccessReport()
+
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+}
+
+func TestExportAccessReport_CSV_WritesHeaderAndRows(t *testing.T) {
+	repo := &mockUserRepository{
+		listWithPermissionsFn: func() ([]models.User, []models.RolePermission, error) {
+			users := []models.User{{ID: 7, Name: "Jane", Email: "jane@example.com", Status: "active", Roles: []models.Role{{ID: 10}}}}
+			rolePerms := []models.RolePermission{
+				{RoleID: 10, Permission: models.Permission{Module: "Settings", Feature: "Users"}, Actions: pq.StringArray{"read"}},
+			}
+			return users, rolePerms, nil
+		},
+	}
+	service := NewUserService(repo, nil, nil, nil)
+
+	data, err := service.ExportAccessReport(AccessReportFormatCSV)
+
+	require.NoError(t, err)
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"User ID", "Name", "Email", "Status", "Module", "Feature", "Action"},
+		{"7", "Jane", "jane@example.com", "active", "Settings", "Users", "read"},
+	}, records)
+}
+
+func TestExportAccessReport_XLSX_ReturnsWorkbook(t *testing.T) {
+	service := NewUserService(&mockUserRepository{}, nil, nil, nil)
+
+	data, err := service.ExportAccessReport(AccessReportFormatXLSX)
+
+	require.NoError(t, err)
+	_, err = zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+}
+
+func TestExportAccessReport_InvalidFormat_ReturnsValidationError(t *testing.T) {
+	service := NewUserService(&mockUserRepository{}, nil, nil, nil)
+
+	data, err := service.ExportAccessReport("pdf")
+
+	assert.Nil(t, data)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pointofsale/backend/repositories"
+)
+
+// userExportPageSize is how many users are loaded per query while exporting.
+const userExportPageSize = 200
+
+var userExportHeader = []string{"id", "name", "email", "phone", "status", "roles", "createdAt"}
+
+// Export writes every user matching the search, sort and status filters of
+// ListUsers to w as CSV. Users are loaded a page at a time and flushed as they
+// are written, so the whole list is never held in memory. Roles are role names
+// separated by semicolons, matching BulkImport. Page and PageSize in params
+// are ignored.
+func (s *UserService) Export(w io.Writer, params repositories.PaginationParams, status string) error {
+	cw := csv.NewWriter(w)
+	params.PageSize = userExportPageSize
+
+	wroteHeader := false
+	for page, exported := 1, int64(0); ; page++ {
+		params.Page = page
+		users, total, err := s.userRepo.List(params, status)
+		if err != nil {
+			return &ServiceError{Err: err, Message: "Failed to fetch users", Code: "INTERNAL_ERROR"}
+		}
+
+		if !wroteHeader {
+			if err := cw.Write(userExportHeader); err != nil {
+				return &ServiceError{Err: err, Message: "Failed to write users export", Code: "INTERNAL_ERROR"}
+			}
+			wroteHeader = true
+		}
+		for _, user := range users {
+			roles := make([]string, 0, len(user.Roles))
+			for _, role := range user.Roles {
+				roles = append(roles, role.Name)
+			}
+			record := []string{
+				strconv.FormatUint(uint64(user.ID), 10),
+				user.Name,
+				user.Email,
+				user.Phone,
+				user.Status,
+				strings.Join(roles, ";"),
+				user.CreatedAt.UTC().Format(time.RFC3339),
+			}
+			if err := cw.Write(record); err != nil {
+				return &ServiceError{Err: err, Message: "Failed to write users export", Code: "INTERNAL_ERROR"}
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return &ServiceError{Err: err, Message: "Failed to write users export", Code: "INTERNAL_ERROR"}
+		}
+
+		exported += int64(len(users))
+		if len(users) < userExportPageSize || exported >= total {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/pointofsale/backend/repositories"
+	"github.com/pointofsale/backend/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport_MultiplePages_WritesEveryUser(t *testing.T) {
+	const totalUsers = 2*userExportPageSize + 50
+	pages := make([]int, 0)
+	repo := &mockUserRepository{
+		listFn: func(params repositories.PaginationParams, status string) ([]models.User, int64, error) {
+			assert.Equal(t, "active", status)
+			assert.Equal(t, "ann", params.Search)
+			pages = append(pages, params.Page)
+
+			users := make([]models.User, 0, params.PageSize)
+			for id := (params.Page-1)*params.PageSize + 1; id <= totalUsers && len(users) < params.PageSize; id++ {
+				users = append(users, models.User{ID: uint(id), Name: fmt.Sprintf("User %d", id), Status: "active"})
+			}
+			return users, totalUsers, nil
+		},
+	}
+	service := NewUserService(repo, nil, nil, nil)
+
+	var out strings.Builder
+	err := service.Export(&out, repositories.PaginationParams{Page: 7, PageSize: 10, Search: "ann"}, "active")
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, pages)
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, totalUsers+1)
+	assert.Equal(t, userExportHeader, records[0])
+	assert.Equal(t, "1", records[1][0])
+	assert.Equal(t, fmt.Sprint(totalUsers), records[totalUsers][0])
+}
+
+func TestExport_FormatsRolesAndCreatedAt(t *testing.T) {
+	createdAt := time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC)
+	repo := &mockUserRepository{
+		listFn: func(params repositories.PaginationParams, status string) ([]models.User, int64, error) {
+			return []models.User{{
+				ID:        4,
+				Name:      "Smith, Bob",
+				Email:     "bob@example.com",
+				Phone:     "0811",
+				Status:    "active",
+				CreatedAt: createdAt,
+				Roles:     []models.Role{{Name: "Cashier"}, {Name: "Manager"}},
+			}}, 1, nil
+		},
+	}
+	service := NewUserService(repo, nil, nil, nil)
+
+	var out strings.Builder
+	require.NoError(t, service.Export(&out, repositories.PaginationParams{}, ""))
+
+	assert.Equal(t,
+		"id,name,email,phone,status,roles,createdAt\n"+
+			`4,"Smith, Bob",bob@example.com,0811,active,Cashier;Manager,2024-03-05T08:30:00Z`+"\n",
+		out.String())
+}
+
+func TestExport_NoUsers_WritesHeaderOnly(t *testing.T) {
+	repo := &mockUserRepository{
+		listFn: func(params repositories.PaginationParams, status string) ([]models.User, int64, error) {
+			return []models.User{}, 0, nil
+		},
+	}
+	service := NewUserService(repo, nil, nil, nil)
+
+	var out strings.Builder
+	require.NoError(t, service.Export(&out, repositories.PaginationParams{}, ""))
+
+	assert.Equal(t, "id,name,email,phone,status,roles,createdAt\n", out.String())
+}
+
+func TestExport_RepositoryError_WritesNothing(t *testing.T) {
+	repo := &mockUserRepository{
+		listFn: func(params repositories.PaginationParams, status string) ([]models.User, int64, error) {
+			return nil, 0, errors.New("db down")
+		},
+	}
+	service := NewUserService(repo, nil, nil, nil)
+
+	var out strings.Builder
+	err := service.Export(&out, repositories.PaginationParams{}, "")
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "INTERNAL_ERROR", svcErr.Code)
+	assert.Empty(t, out.String())
+}
+
+func TestExport_SortByStatusAcrossPages_WritesEachUserOnce(t *testing.T) {
+	db := testutil.SetupTestDB(t)
+
+	// Insert directly to skip hashing a password per user
+	users := make([]models.User, userExportPageSize+50)
+	for i := range users {
+		users[i] = models.User{
+			Name:         fmt.Sprintf("Export User %d", i),
+			Email:        fmt.Sprintf("export-%d@example.com", i),
+			PasswordHash: "unused",
+			Status:       "active",
+		}
+		if i%10 == 0 {
+			users[i].Status = "inactive"
+		}
+	}
+	require.NoError(t, db.CreateInBatches(users, 100).Error)
+
+	service := NewUserService(repositories.NewUserRepository(db), nil, nil, nil)
+	var out strings.Builder
+	err := service.Export(&out, repositories.PaginationParams{Search: "export-", SortBy: "status", SortDir: "asc"}, "")
+	require.NoError(t, err)
+
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, len(users)+1)
+	seen := make(map[string]int, len(users))
+	for _, record := range records[1:] {
+		seen[record[0]]++
+	}
+	for _, user := range users {
+		assert.Equal(t, 1, seen[fmt.Sprint(user.ID)], "user %d", user.ID)
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pointofsale/backend/models"
+)
+
+// MaxUserImportRows caps the number of data rows in a single user import.
+const MaxUserImportRows = 500
+
+// Per-row outcomes of a user import
+const (
+	UserImportCreated = "created"
+	UserImportSkipped = "skipped"
+	UserImportError   = "error"
+)
+
+// UserImportRowResult is the outcome of importing one CSV row. Row is the
+// record's position in the file, counting the header as row 1. TempPassword
+// is set for created users when email is disabled, as with CreateUser.
+type UserImportRowResult struct {
+	Row          int    `json:"row"`
+	Email        string `json:"email"`
+	Status       string `json:"status"`
+	Reason       string `json:"reason,omitempty"`
+	UserID       uint   `json:"userId,omitempty"`
+	TempPassword string `json:"tempPassword,omitempty"`
+}
+
+// UserImportResult summarises a bulk user import.
+type UserImportResult struct {
+	Created int                   `json:"created"`
+	Skipped int                   `json:"skipped"`
+	Failed  int                   `json:"failed"`
+	Rows    []UserImportRowResult `json:"rows"`
+}
+
+func (r *UserImportResult) add(row UserImportRowResult) {
+	switch row.Status {
+	case UserImportCreated:
+		r.Created++
+	case UserImportSkipped:
+		r.Skipped++
+	default:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, row)
+}
+
+// BulkImport creates users from a CSV with a header row naming the columns
+// name, email, phone and roles (in any order; phone and roles are optional).
+// Roles are role names separated by semicolons. Each user gets a temporary
+// password by email, or in its row result when email is disabled, as with
+// CreateUser. Rows with an email that already exists, or that repeats an
+// earlier imported row, are skipped; invalid rows are reported and do not stop
+// the import.
+func (s *UserService) BulkImport(reader io.Reader) (*UserImportResult, error) {
+	file, svcErr := readImportCSV(reader, []string{"name", "email"}, MaxUserImportRows)
+	if svcErr != nil {
+		return nil, svcErr
+	}
+
+	result := &UserImportResult{Rows: []UserImportRowResult{}}
+	seen := make(map[string]bool)
+	for _, entry := range file.rows {
+		line, record := entry.line, entry.record
+		if entry.err != nil {
+			result.add(UserImportRowResult{Row: line, Status: UserImportError, Reason: "Malformed CSV row"})
+			continue
+		}
+
+		email := strings.ToLower(file.field(record, "email"))
+		row := UserImportRowResult{Row: line, Email: email}
+		if email != "" && seen[email] {
+			row.Status = UserImportSkipped
+			row.Reason = "Duplicate email in file"
+			result.add(row)
+			continue
+		}
+
+		roleIDs, reason, err := s.resolveImportRoles(file.field(record, "roles"))
+		if err != nil {
+			return nil, &ServiceError{Err: err, Message: "Failed to look up roles", Code: "INTERNAL_ERROR"}
+		}
+		if reason != "" {
+			row.Status = UserImportError
+			row.Reason = reason
+			result.add(row)
+			continue
+		}
+
+		user, err := s.CreateUser(CreateUserInput{
+			Name:    file.field(record, "name"),
+			Email:   email,
+			Phone:   file.field(record, "phone"),
+			RoleIDs: roleIDs,
+		})
+		if err != nil {
+			row.Status = UserImportError
+			row.Reason = "Failed to create user"
+			if serviceErr, ok := err.(*ServiceError); ok {
+				row.Reason = serviceErr.Message
+				if serviceErr.Err == ErrConflict {
+					row.Status = UserImportSkipped
+				}
+			}
+			result.add(row)
+			continue
+		}
+
+		// Only a row that passed validation claims its email
+		seen[email] = true
+		row.Status = UserImportCreated
+		row.UserID = user.ID
+		row.TempPassword = user.TempPassword
+		result.add(row)
+	}
+
+	return result, nil
+}
+
+// resolveImportRoles maps semicolon-separated role names to role IDs. It
+// returns a reason when a name does not match any role.
+func (s *UserService) resolveImportRoles(value string) ([]uint, string, error) {
+	names := make([]string, 0)
+	for _, name := range strings.Split(value, ";") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, "", nil
+	}
+
+	roles, err := s.userRepo.FindRolesByNames(names)
+	if err != nil {
+		return nil, "", err
+	}
+	byName := make(map[string]models.Role, len(roles))
+	for _, role := range roles {
+		byName[strings.ToLower(role.Name)] = role
+	}
+
+	roleIDs := make([]uint, 0, len(names))
+	for _, name := range names {
+		role, ok := byName[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Sprintf("Unknown role: %s", name), nil
+		}
+		roleIDs = append(roleIDs, role.ID)
+	}
+	return roleIDs, "", nil
+}
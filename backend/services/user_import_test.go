@@ -0,0 +1,180 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pointofsale/backend/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newImportTestService returns a user service whose repository already holds
+// existing@example.com and knows the roles Cashier (ID 2) and Manager (ID 3).
+func newImportTestService(t *testing.T) (*UserService, map[string]*models.User, map[uint][]uint, *[]string) {
+	t.Helper()
+	created := make(map[string]*models.User)
+	synced := make(map[uint][]uint)
+	emailed := make([]string, 0)
+	roles := []models.Role{{ID: 2, Name: "Cashier"}, {ID: 3, Name: "Manager"}}
+
+	repo := &mockUserRepository{
+		findByEmailFn: func(email string) (*models.User, error) {
+			if email == "existing@example.com" {
+				return &models.User{ID: 1, Email: email}, nil
+			}
+			if user, ok := created[email]; ok {
+				return user, nil
+			}
+			return nil, gorm.ErrRecordNotFound
+		},
+		createFn: func(user *models.User) error {
+			user.ID = uint(len(created) + 10)
+			created[user.Email] = user
+			return nil
+		},
+		syncRolesFn: func(userID uint, roleIDs []uint) error {
+			synced[userID] = roleIDs
+			return nil
+		},
+		findRolesByNamesFn: func(names []string) ([]models.Role, error) {
+			found := make([]models.Role, 0)
+			for _, role := range roles {
+				for _, name := range names {
+					if strings.EqualFold(role.Name, name) {
+						found = append(found, role)
+					}
+				}
+			}
+			return found, nil
+		},
+	}
+	emailSvc := &mockUserEmailService{
+		sendUserCredentialsFn: func(toEmail, userName, tempPassword string) error {
+			assert.NotEmpty(t, tempPassword)
+			emailed = append(emailed, toEmail)
+			return nil
+		},
+	}
+	return NewUserService(repo, nil, nil, emailSvc), created, synced, &emailed
+}
+
+func TestBulkImport_MixedRows_ReportsEachOutcome(t *testing.T) {
+	service, created, synced, emailed := newImportTestService(t)
+
+	csvData := strings.Join([]string{
+		"name,email,phone,roles",
+		"Jane Doe,Jane@Example.com,0811,Cashier",
+		"Existing,existing@example.com,,",
+		"Jane Again,jane@example.com,,",
+		"Bad Email,not-an-email,,",
+		"Unknown Role,unknown@example.com,,Owner",
+		`"Smith, Bob",bob@example.com,,cashier;Manager`,
+	}, "\n")
+
+	result, err := service.BulkImport(strings.NewReader(csvData))
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Created)
+	assert.Equal(t, 2, result.Skipped)
+	assert.Equal(t, 2, result.Failed)
+	require.Len(t, result.Rows, 6)
+
+	statuses := make([]string, 0, len(result.Rows))
+	for _, row := range result.Rows {
+		statuses = append(statuses, fmt.Sprintf("%d:%s", row.Row, row.Status))
+	}
+	assert.Equal(t, []string{"2:created", "3:skipped", "4:skipped", "5:error", "6:error", "7:created"}, statuses)
+	assert.Equal(t, "Email already exists", result.Rows[1].Reason)
+	assert.Equal(t, "Duplicate email in file", result.Rows[2].Reason)
+	assert.Equal(t, "Invalid email format", result.Rows[3].Reason)
+	assert.Equal(t, "Unknown role: Owner", result.Rows[4].Reason)
+
+	require.Contains(t, created, "jane@example.com")
+	assert.Equal(t, "0811", created["jane@example.com"].Phone)
+	assert.Equal(t, "Smith, Bob", created["bob@example.com"].Name)
+	assert.Equal(t, []uint{2, 3}, synced[created["bob@example.com"].ID])
+	assert.Equal(t, []string{"jane@example.com", "bob@example.com"}, *emailed)
+}
+
+func TestBulkImport_InvalidRowThenCorrectedRow_CreatesUser(t *testing.T) {
+	service, created, _, _ := newImportTestService(t)
+
+	csvData := "name,email,roles\n" +
+		"Ann Lee,ann@example.com,Owner\n" +
+		"Ann Lee,ann@example.com,Cashier\n"
+
+	result, err := service.BulkImport(strings.NewReader(csvData))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, UserImportError, result.Rows[0].Status)
+	assert.Equal(t, UserImportCreated, result.Rows[1].Status)
+	assert.Contains(t, created, "ann@example.com")
+}
+
+func TestBulkImport_ColumnsInAnyOrder_WithoutOptionalColumns(t *testing.T) {
+	service, created, _, _ := newImportTestService(t)
+
+	result, err := service.BulkImport(strings.NewReader("Email,Name\nann@example.com,Ann Lee\n"))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, "Ann Lee", created["ann@example.com"].Name)
+}
+
+func TestBulkImport_MissingEmailColumn_ReturnsValidationError(t *testing.T) {
+	service, _, _, _ := newImportTestService(t)
+
+	result, err := service.BulkImport(strings.NewReader("name,phone\nAnn,0811\n"))
+
+	assert.Nil(t, result)
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+}
+
+func TestBulkImport_EmptyFile_ReturnsValidationError(t *testing.T) {
+	service, _, _, _ := newImportTestService(t)
+
+	_, err := service.BulkImport(strings.NewReader(""))
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+}
+
+func TestBulkImport_TooManyRows_CreatesNoUsers(t *testing.T) {
+	service, created, _, _ := newImportTestService(t)
+
+	var b strings.Builder
+	b.WriteString("name,email\n")
+	for i := 0; i <= MaxUserImportRows; i++ {
+		fmt.Fprintf(&b, "User %d,user%d@example.com\n", i, i)
+	}
+
+	_, err := service.BulkImport(strings.NewReader(b.String()))
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, "TOO_MANY_ROWS", svcErr.Code)
+	assert.Empty(t, created)
+}
+
+func TestBulkImport_MalformedRow_IsReportedAndImportContinues(t *testing.T) {
+	service, _, _, _ := newImportTestService(t)
+
+	csvData := "name,email\n" +
+		"Bad \"Quote,bad@example.com\n" +
+		"Ann Lee,ann@example.com\n"
+
+	result, err := service.BulkImport(strings.NewReader(csvData))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 1, result.Created)
+	assert.Equal(t, "Malformed CSV row", result.Rows[0].Reason)
+}
@@ -1,8 +1,10 @@
 package services
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"math/big"
 	"strings"
 
 	"github.com/pointofsale/backend/config"
@@ -23,6 +25,10 @@ type UserRepositoryForUsers interface {
 	List(params repositories.PaginationParams, status string) ([]models.User, int64, error)
 	Delete(id uint) error
 	SyncRoles(userID uint, roleIDs []uint) error
+	ListWithPermissions() ([]models.User, []models.RolePermission, error)
+	RecentPasswordHashes(userID uint, limit int) ([]string, error)
+	AddPasswordHistory(userID uint, passwordHash string, keep int) error
+	FindRolesByNames(names []string) ([]models.Role, error)
 }
 
 // UserEmailService defines the email operations for user management
@@ -38,18 +44,46 @@ type UserService struct {
 	redis        *redis.Client
 	config       *config.Config
 	emailService UserEmailService
+	emailEnabled bool
+	pwPolicy     utils.PasswordPolicy
+	audit        AuditRecorder
 }
 
-// NewUserService creates a new user service instance
+// NewUserService creates a new user service instance. A nil emailSvc sends no
+// email.
 func NewUserService(userRepo UserRepositoryForUsers, rdb *redis.Client, cfg *config.Config, emailSvc UserEmailService) *UserService {
+	if emailSvc == nil {
+		emailSvc = NoopEmailService{}
+	}
 	return &UserService{
 		userRepo:     userRepo,
 		redis:        rdb,
 		config:       cfg,
 		emailService: emailSvc,
+		emailEnabled: true,
+		pwPolicy:     utils.DefaultPasswordPolicy(),
 	}
 }
 
+// SetEmailEnabled turns user emails on or off. With email off nothing is
+// sent, temporary passwords are handed back to the admin instead and users
+// can be approved without a verified email address.
+func (s *UserService) SetEmailEnabled(enabled bool) {
+	s.emailEnabled = enabled
+}
+
+// SetAuditRecorder records user updates, with the user's state before and
+// after, in the audit log.
+func (s *UserService) SetAuditRecorder(recorder AuditRecorder) {
+	s.audit = recorder
+}
+
+// SetPasswordPolicy sets the rules new passwords must satisfy. Temporary
+// passwords for admin-created users are generated to satisfy it too.
+func (s *UserService) SetPasswordPolicy(policy utils.PasswordPolicy) {
+	s.pwPolicy = policy
+}
+
 // CreateUserInput represents the input for creating a user
 type CreateUserInput struct {
 	Name           string   `json:"name"`
@@ -69,6 +103,8 @@ type UpdateUserInput struct {
 	RoleIDs        []uint   `json:"roleIds,omitempty"`
 	Status         string   `json:"status,omitempty"`
 	ProfilePicture *string  `json:"profilePicture,omitempty"`
+	// ActorID is the user making the change, recorded in the audit log
+	ActorID uint `json:"-"`
 }
 
 // ListUsers returns paginated users with optional filtering
@@ -142,7 +178,7 @@ func (s *UserService) CreateUser(input CreateUserInput) (*models.User, error) {
 	}
 
 	// Generate temporary password
-	tempPassword := generateTempPassword()
+	tempPassword := generateTempPassword(s.pwPolicy)
 
 	// Hash password
 	hashedPassword, err := utils.HashPassword(tempPassword)
@@ -183,17 +219,20 @@ func (s *UserService) CreateUser(input CreateUserInput) (*models.User, error) {
 	}
 
 	// Send credentials email (non-blocking)
-	if s.emailService != nil {
+	if s.emailEnabled {
 		_ = s.emailService.SendUserCredentials(user.Email, user.Name, tempPassword)
 	}
 
 	// Reload user with roles
 	createdUser, _ := s.userRepo.FindByID(user.ID)
-	if createdUser != nil {
-		return createdUser, nil
+	if createdUser == nil {
+		createdUser = user
+	}
+	if !s.emailEnabled {
+		createdUser.TempPassword = tempPassword
 	}
 
-	return user, nil
+	return createdUser, nil
 }
 
 // UpdateUser updates an existing user
@@ -215,6 +254,8 @@ func (s *UserService) UpdateUser(id uint, input UpdateUserInput) (*models.User,
 		}
 	}
 
+	before := auditSnapshot(user)
+
 	// Super admin protection: cannot change status or isSuperAdmin
 	if user.IsSuperAdmin {
 		if input.Status != "" && input.Status != user.Status {
@@ -299,16 +340,19 @@ func (s *UserService) UpdateUser(id uint, input UpdateUserInput) (*models.User,
 		if err := s.userRepo.SyncRoles(user.ID, input.RoleIDs); err != nil {
 			// Log error but don't fail the update
 			_ = err
+		} else {
+			invalidatePermissions(s.redis, user.ID)
 		}
 	}
 
 	// Reload user with roles
 	updatedUser, _ := s.userRepo.FindByID(user.ID)
-	if updatedUser != nil {
-		return updatedUser, nil
+	if updatedUser == nil {
+		updatedUser = user
 	}
+	recordChange(s.audit, models.AuditEventUserUpdated, input.ActorID, "user", user.ID, before, auditSnapshot(updatedUser))
 
-	return user, nil
+	return updatedUser, nil
 }
 
 // DeleteUser deletes a user by ID
@@ -388,6 +432,16 @@ func (s *UserService) ApproveUser(id uint) (*models.User, error) {
 		}
 	}
 
+	// Only users who proved ownership of their email can be approved. Without
+	// email there is no way to verify it.
+	if s.emailEnabled && !user.EmailVerified {
+		return nil, &ServiceError{
+			Err:     ErrValidation,
+			Message: "User has not verified their email address",
+			Code:    "EMAIL_NOT_VERIFIED",
+		}
+	}
+
 	// Update status to active
 	user.Status = "active"
 	if err := s.userRepo.Update(user); err != nil {
@@ -399,7 +453,7 @@ func (s *UserService) ApproveUser(id uint) (*models.User, error) {
 	}
 
 	// Send approval email (non-blocking)
-	if s.emailService != nil {
+	if s.emailEnabled {
 		_ = s.emailService.SendUserApproved(user.Email, user.Name)
 	}
 
@@ -435,7 +489,7 @@ func (s *UserService) RejectUser(id uint) error {
 	}
 
 	// Send rejection email before deletion (non-blocking)
-	if s.emailService != nil {
+	if s.emailEnabled {
 		_ = s.emailService.SendUserRejected(user.Email, user.Name)
 	}
 
@@ -451,22 +505,245 @@ func (s *UserService) RejectUser(id uint) error {
 	return nil
 }
 
-// generateTempPassword generates a random 16-character temporary password
-func generateTempPassword() string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()"
-	const length = 16
+// ResendCredentials gives an active user a new temporary password and emails
+// it, for when the original credentials email was lost. The old password
+// stops working and every session of the user is signed out. With email
+// disabled the password is returned instead of sent.
+func (s *UserService) ResendCredentials(id uint) (string, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", &ServiceError{
+				Err:     ErrNotFound,
+				Message: "User not found",
+				Code:    "USER_NOT_FOUND",
+			}
+		}
+		return "", &ServiceError{
+			Err:     err,
+			Message: "Failed to fetch user",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	if user.Status != "active" {
+		return "", &ServiceError{
+			Err:     ErrValidation,
+			Message: "Credentials can only be resent to active users",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
 
-	b := make([]byte, length)
-	rand.Read(b)
+	tempPassword := generateTempPassword(s.pwPolicy)
+	hashedPassword, err := utils.HashPassword(tempPassword)
+	if err != nil {
+		return "", &ServiceError{
+			Err:     err,
+			Message: "Failed to process password",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	user.PasswordHash = hashedPassword
+	if err := s.userRepo.Update(user); err != nil {
+		return "", &ServiceError{
+			Err:     err,
+			Message: "Failed to update password",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	// The old password no longer works, so neither should its sessions
+	if s.redis != nil {
+		revokeRefreshTokens(context.Background(), s.redis, user.ID, "")
+	}
+
+	if !s.emailEnabled {
+		return tempPassword, nil
+	}
+	if err := s.emailService.SendUserCredentials(user.Email, user.Name, tempPassword); err != nil {
+		return "", &ServiceError{
+			Err:     err,
+			Message: "Failed to send credentials email",
+			Code:    "EMAIL_FAILED",
+		}
+	}
+
+	return "", nil
+}
 
-	password := make([]byte, length)
-	for i := 0; i < length; i++ {
-		password[i] = charset[int(b[i])%len(charset)]
+// ChangePassword changes the password of a logged-in user after verifying
+// their current one. All other sessions of the user are signed out; the
+// session of currentRefreshToken, if given, stays active.
+func (s *UserService) ChangePassword(userID uint, currentPassword, newPassword, confirmPassword, currentRefreshToken string) error {
+	if currentPassword == "" {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Current password is required",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if passwordErrors := s.pwPolicy.Validate(newPassword); len(passwordErrors) > 0 {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: strings.Join(passwordErrors, "; "),
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+	if newPassword != confirmPassword {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "Passwords do not match",
+			Code:    "VALIDATION_ERROR",
+		}
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return &ServiceError{
+				Err:     ErrNotFound,
+				Message: "User not found",
+				Code:    "USER_NOT_FOUND",
+			}
+		}
+		return &ServiceError{
+			Err:     err,
+			Message: "Failed to fetch user",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	if match, err := utils.VerifyPassword(user.PasswordHash, currentPassword); err != nil || !match {
+		return &ServiceError{
+			Err:     ErrUnauthorized,
+			Message: "Current password is incorrect",
+			Code:    "INVALID_CURRENT_PASSWORD",
+		}
+	}
+	if newPassword == currentPassword {
+		return &ServiceError{
+			Err:     ErrValidation,
+			Message: "New password must be different from the current password",
+			Code:    "PASSWORD_UNCHANGED",
+		}
+	}
+
+	historySize := 0
+	if s.config != nil {
+		historySize = s.config.PasswordHistorySize
+	}
+	if svcErr := checkPasswordReuse(s.userRepo, historySize, user, newPassword); svcErr != nil {
+		return svcErr
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return &ServiceError{
+			Err:     err,
+			Message: "Failed to process password",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	// Keep the replaced hash so it cannot be reused
+	if historySize > 0 {
+		if err := s.userRepo.AddPasswordHistory(user.ID, user.PasswordHash, historySize); err != nil {
+			return &ServiceError{
+				Err:     err,
+				Message: "Failed to update password",
+				Code:    "INTERNAL_ERROR",
+			}
+		}
+	}
+
+	user.PasswordHash = hashedPassword
+	if err := s.userRepo.Update(user); err != nil {
+		return &ServiceError{
+			Err:     err,
+			Message: "Failed to update password",
+			Code:    "INTERNAL_ERROR",
+		}
+	}
+
+	// Sign out every other session
+	if s.redis != nil {
+		keepJTI := ""
+		if currentRefreshToken != "" && s.config != nil {
+			if claims, err := utils.ValidateToken(currentRefreshToken, s.config.JWTRefreshSecret); err == nil && claims.UserID == userID {
+				keepJTI = claims.ID
+			}
+		}
+		revokeRefreshTokens(context.Background(), s.redis, userID, keepJTI)
+	}
+
+	return nil
+}
+
+// tempPasswordLength is the length of generated temporary passwords unless
+// the password policy requires otherwise.
+const tempPasswordLength = 16
+
+// generateTempPassword generates a random temporary password that satisfies
+// the policy: 16 characters, or the policy's minimum or maximum length when
+// it falls outside that, with one character from each required class.
+func generateTempPassword(policy utils.PasswordPolicy) string {
+	const (
+		upper   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+		lower   = "abcdefghijklmnopqrstuvwxyz"
+		digits  = "0123456789"
+		symbols = "!@#$%^&*()"
+		charset = upper + lower + digits + symbols
+	)
+
+	length := tempPasswordLength
+	if policy.MinLength > length {
+		length = policy.MinLength
+	}
+	if policy.MaxLength > 0 && length > policy.MaxLength {
+		length = policy.MaxLength
+	}
+
+	var required []string
+	if policy.RequireUpper {
+		required = append(required, upper)
+	}
+	if policy.RequireLower {
+		required = append(required, lower)
+	}
+	if policy.RequireDigit {
+		required = append(required, digits)
+	}
+	if policy.RequireSymbol {
+		required = append(required, symbols)
+	}
+
+	password := make([]byte, 0, length)
+	for _, set := range required {
+		password = append(password, set[randomIndex(len(set))])
+	}
+	for len(password) < length {
+		password = append(password, charset[randomIndex(len(charset))])
+	}
+
+	// Shuffle so the required characters are not always first
+	for i := len(password) - 1; i > 0; i-- {
+		j := randomIndex(i + 1)
+		password[i], password[j] = password[j], password[i]
 	}
 
 	return string(password)
 }
 
+// randomIndex returns a uniformly random index in [0, n).
+func randomIndex(n int) int {
+	v, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0
+	}
+	return int(v.Int64())
+}
+
 // Alternative: generate temp password with base64 encoding
 func generateTempPasswordBase64() string {
 	b := make([]byte, 12)
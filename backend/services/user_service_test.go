@@ -4,10 +4,14 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/alicebob/miniredis/v2"
+	"github.com/pointofsale/backend/config"
 	"github.com/pointofsale/backend/models"
 	"github.com/pointofsale/backend/repositories"
 	"github.com/pointofsale/backend/utils"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
@@ -23,6 +27,10 @@ type mockUserRepository struct {
 	listFn                  func(repositories.PaginationParams, string) ([]models.User, int64, error)
 	deleteFn                func(uint) error
 	syncRolesFn             func(uint, []uint) error
+	listWithPermissionsFn   func() ([]models.User, []models.RolePermission, error)
+	recentPasswordHashesFn  func(uint, int) ([]string, error)
+	addPasswordHistoryFn    func(uint, string, int) error
+	findRolesByNamesFn      func([]string) ([]models.Role, error)
 }
 
 func (m *mockUserRepository) Create(user *models.User) error {
@@ -81,6 +89,34 @@ func (m *mockUserRepository) SyncRoles(userID uint, roleIDs []uint) error {
 	return nil
 }
 
+func (m *mockUserRepository) ListWithPermissions() ([]models.User, []models.RolePermission, error) {
+	if m.listWithPermissionsFn != nil {
+		return m.listWithPermissionsFn()
+	}
+	return []models.User{}, []models.RolePermission{}, nil
+}
+
+func (m *mockUserRepository) RecentPasswordHashes(userID uint, limit int) ([]string, error) {
+	if m.recentPasswordHashesFn != nil {
+		return m.recentPasswordHashesFn(userID, limit)
+	}
+	return []string{}, nil
+}
+
+func (m *mockUserRepository) AddPasswordHistory(userID uint, passwordHash string, keep int) error {
+	if m.addPasswordHistoryFn != nil {
+		return m.addPasswordHistoryFn(userID, passwordHash, keep)
+	}
+	return nil
+}
+
+func (m *mockUserRepository) FindRolesByNames(names []string) ([]models.Role, error) {
+	if m.findRolesByNamesFn != nil {
+		return m.findRolesByNamesFn(names)
+	}
+	return []models.Role{}, nil
+}
+
 // Mock UserEmailService for user-specific emails
 type mockUserEmailService struct {
 	sendUserCredentialsFn func(string, string, string) error
@@ -247,6 +283,36 @@ func TestUpdateUser_ValidInput_UpdatesFields(t *testing.T) {
 	assert.Equal(t, "new@example.com", updatedUser.Email)
 }
 
+func TestUpdateUser_RolesChanged_ClearsPermissionCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	require.NoError(t, mr.Set("perms:1", `{"isSuperAdmin":false}`))
+	require.NoError(t, mr.Set("perms:2", `{"isSuperAdmin":false}`))
+
+	existingUser := &models.User{ID: 1, Name: "Jane", Email: "jane@example.com", Status: "active"}
+	repo := &mockUserRepository{
+		findByIDFn: func(id uint) (*models.User, error) {
+			return existingUser, nil
+		},
+		findByEmailExcludingFn: func(email string, excludeID uint) (*models.User, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+		updateFn: func(user *models.User) error {
+			return nil
+		},
+		syncRolesFn: func(userID uint, roleIDs []uint) error {
+			return nil
+		},
+	}
+	service := NewUserService(repo, rdb, nil, nil)
+
+	_, err := service.UpdateUser(1, UpdateUserInput{Name: "Jane", Email: "jane@example.com", RoleIDs: []uint{2}})
+	require.NoError(t, err)
+
+	assert.False(t, mr.Exists("perms:1"))
+	assert.True(t, mr.Exists("perms:2"))
+}
+
 func TestUpdateUser_SuperAdmin_BlocksStatusChange(t *testing.T) {
 	superAdmin := &models.User{
 		ID:           1,
@@ -418,10 +484,11 @@ func TestDeleteUser_Regular_Succeeds(t *testing.T) {
 
 func TestApproveUser_PendingUser_SetsActive(t *testing.T) {
 	pendingUser := &models.User{
-		ID:     1,
-		Name:   "Pending User",
-		Email:  "pending@example.com",
-		Status: "pending",
+		ID:            1,
+		Name:          "Pending User",
+		Email:         "pending@example.com",
+		Status:        "pending",
+		EmailVerified: true,
 	}
 
 	var updatedUser *models.User
@@ -477,6 +544,37 @@ func TestApproveUser_ActiveUser_ReturnsBadRequest(t *testing.T) {
 	assert.Contains(t, serviceErr.Message, "pending")
 }
 
+func TestApproveUser_UnverifiedEmail_ReturnsValidationError(t *testing.T) {
+	pendingUser := &models.User{
+		ID:     1,
+		Status: "pending",
+	}
+
+	var updated bool
+	repo := &mockUserRepository{
+		findByIDFn: func(id uint) (*models.User, error) {
+			return pendingUser, nil
+		},
+		updateFn: func(user *models.User) error {
+			updated = true
+			return nil
+		},
+	}
+
+	service := NewUserService(repo, nil, nil, nil)
+
+	user, err := service.ApproveUser(1)
+	require.Error(t, err)
+	assert.Nil(t, user)
+	assert.False(t, updated)
+
+	var serviceErr *ServiceError
+	require.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "EMAIL_NOT_VERIFIED", serviceErr.Code)
+	assert.Equal(t, "pending", pendingUser.Status)
+}
+
 func TestRejectUser_PendingUser_DeletesUser(t *testing.T) {
 	pendingUser := &models.User{
 		ID:     1,
@@ -603,10 +701,201 @@ func TestGetUser_NotFound_ReturnsNotFound(t *testing.T) {
 
 // Helper function to test password generation
 func TestGenerateTempPassword_Length(t *testing.T) {
-	password := generateTempPassword()
+	password := generateTempPassword(utils.DefaultPasswordPolicy())
 	assert.Len(t, password, 16)
 
 	// Verify password can be hashed
 	_, err := utils.HashPassword(password)
 	assert.NoError(t, err)
 }
+
+func TestGenerateTempPassword_SatisfiesPolicy(t *testing.T) {
+	policies := []utils.PasswordPolicy{
+		utils.DefaultPasswordPolicy(),
+		{MinLength: 24, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true},
+		{MinLength: 8, MaxLength: 10, RequireDigit: true, RequireSymbol: true},
+	}
+
+	for _, policy := range policies {
+		for i := 0; i < 20; i++ {
+			password := generateTempPassword(policy)
+			assert.Empty(t, policy.Validate(password), "password %q", password)
+		}
+	}
+}
+
+func TestChangePassword_CustomPolicy_RejectsShortPassword(t *testing.T) {
+	service, _, _, _, _ := setupChangePasswordTest(t)
+	service.SetPasswordPolicy(utils.PasswordPolicy{MinLength: 20})
+
+	err := service.ChangePassword(1, "Password@123", "NewPassword@456", "NewPassword@456", "")
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+	assert.Contains(t, svcErr.Message, "at least 20 characters")
+}
+
+// setupChangePasswordTest returns a user service whose only user has the
+// password "Password@123"
+func setupChangePasswordTest(t *testing.T) (*UserService, *mockUserRepository, *models.User, *miniredis.Miniredis, *config.Config) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	cfg := &config.Config{
+		JWTRefreshSecret: "test-refresh-secret",
+		JWTRefreshExpiry: 7 * 24 * time.Hour,
+	}
+
+	hash, err := utils.HashPassword("Password@123")
+	require.NoError(t, err)
+	user := &models.User{ID: 1, Email: "john@example.com", PasswordHash: hash, Status: "active"}
+	repo := &mockUserRepository{
+		findByIDFn: func(id uint) (*models.User, error) {
+			return user, nil
+		},
+	}
+
+	return NewUserService(repo, rdb, cfg, nil), repo, user, mr, cfg
+}
+
+func TestChangePassword_ValidInput_UpdatesHashAndKeepsCurrentSession(t *testing.T) {
+	service, _, user, mr, cfg := setupChangePasswordTest(t)
+
+	currentToken, err := utils.GenerateRefreshToken(1, false, cfg.JWTRefreshSecret, cfg.JWTRefreshExpiry)
+	require.NoError(t, err)
+	currentClaims, err := utils.ValidateToken(currentToken, cfg.JWTRefreshSecret)
+	require.NoError(t, err)
+	mr.Set("refresh:"+currentClaims.ID, `{"userId":1}`)
+	mr.Set("refresh:other-device", `{"userId":1}`)
+	mr.SetTTL("refresh:other-device", time.Hour)
+	mr.Set("refresh:other-user", `{"userId":2}`)
+
+	err = service.ChangePassword(1, "Password@123", "NewPassword@456", "NewPassword@456", currentToken)
+
+	require.NoError(t, err)
+	match, _ := utils.VerifyPassword(user.PasswordHash, "NewPassword@456")
+	assert.True(t, match)
+	assert.True(t, mr.Exists("refresh:"+currentClaims.ID))
+	assert.False(t, mr.Exists("refresh:other-device"))
+	assert.True(t, mr.Exists("blacklist:other-device"))
+	assert.True(t, mr.Exists("refresh:other-user"))
+}
+
+func TestChangePassword_WrongCurrentPassword_ReturnsUnauthorized(t *testing.T) {
+	service, _, user, _, _ := setupChangePasswordTest(t)
+	originalHash := user.PasswordHash
+
+	err := service.ChangePassword(1, "Wrong@123", "NewPassword@456", "NewPassword@456", "")
+
+	var serviceErr *ServiceError
+	require.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, ErrUnauthorized, serviceErr.Err)
+	assert.Equal(t, "INVALID_CURRENT_PASSWORD", serviceErr.Code)
+	assert.Equal(t, originalHash, user.PasswordHash)
+}
+
+func TestChangePassword_SameAsCurrent_ReturnsValidationError(t *testing.T) {
+	service, _, _, _, _ := setupChangePasswordTest(t)
+
+	err := service.ChangePassword(1, "Password@123", "Password@123", "Password@123", "")
+
+	var serviceErr *ServiceError
+	require.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, ErrValidation, serviceErr.Err)
+	assert.Equal(t, "PASSWORD_UNCHANGED", serviceErr.Code)
+}
+
+func TestChangePassword_InvalidNewPassword_ReturnsValidationError(t *testing.T) {
+	service, _, _, _, _ := setupChangePasswordTest(t)
+
+	cases := []struct {
+		name            string
+		newPassword     string
+		confirmPassword string
+	}{
+		{"weak password", "weak", "weak"},
+		{"mismatched confirmation", "NewPassword@456", "NewPassword@789"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := service.ChangePassword(1, "Password@123", tc.newPassword, tc.confirmPassword, "")
+
+			var serviceErr *ServiceError
+			require.True(t, errors.As(err, &serviceErr))
+			assert.Equal(t, ErrValidation, serviceErr.Err)
+		})
+	}
+}
+
+func TestChangePassword_ReusedPassword_ReturnsValidationError(t *testing.T) {
+	service, repo, _, _, cfg := setupChangePasswordTest(t)
+	cfg.PasswordHistorySize = 3
+	oldHash, err := utils.HashPassword("OldPassword@789")
+	require.NoError(t, err)
+	repo.recentPasswordHashesFn = func(userID uint, limit int) ([]string, error) {
+		return []string{oldHash}, nil
+	}
+
+	err = service.ChangePassword(1, "Password@123", "OldPassword@789", "OldPassword@789", "")
+
+	var serviceErr *ServiceError
+	require.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, "PASSWORD_REUSED", serviceErr.Code)
+}
+
+func TestResendCredentials_ActiveUser_EmailsNewPasswordAndRevokesSessions(t *testing.T) {
+	service, _, user, mr, _ := setupChangePasswordTest(t)
+	var sentPassword string
+	service.emailService = &mockUserEmailService{
+		sendUserCredentialsFn: func(toEmail, userName, tempPassword string) error {
+			assert.Equal(t, "john@example.com", toEmail)
+			sentPassword = tempPassword
+			return nil
+		},
+	}
+	mr.Set("refresh:device-a", `{"userId":1}`)
+	mr.SetTTL("refresh:device-a", time.Hour)
+	mr.Set("refresh:other-user", `{"userId":2}`)
+
+	tempPassword, err := service.ResendCredentials(1)
+
+	require.NoError(t, err)
+	assert.Empty(t, tempPassword)
+	require.NotEmpty(t, sentPassword)
+	match, _ := utils.VerifyPassword(user.PasswordHash, sentPassword)
+	assert.True(t, match)
+	oldMatch, _ := utils.VerifyPassword(user.PasswordHash, "Password@123")
+	assert.False(t, oldMatch)
+	assert.False(t, mr.Exists("refresh:device-a"))
+	assert.True(t, mr.Exists("blacklist:device-a"))
+	assert.True(t, mr.Exists("refresh:other-user"))
+}
+
+func TestResendCredentials_InactiveUser_ReturnsValidationError(t *testing.T) {
+	service, _, user, _, _ := setupChangePasswordTest(t)
+	user.Status = "pending"
+	originalHash := user.PasswordHash
+
+	_, err := service.ResendCredentials(1)
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrValidation, svcErr.Err)
+	assert.Equal(t, originalHash, user.PasswordHash)
+}
+
+func TestResendCredentials_UserNotFound_ReturnsNotFound(t *testing.T) {
+	repo := &mockUserRepository{
+		findByIDFn: func(id uint) (*models.User, error) {
+			return nil, gorm.ErrRecordNotFound
+		},
+	}
+	service := NewUserService(repo, nil, nil, nil)
+
+	_, err := service.ResendCredentials(99)
+
+	svcErr, ok := err.(*ServiceError)
+	require.True(t, ok)
+	assert.Equal(t, ErrNotFound, svcErr.Err)
+}
@@ -88,6 +88,19 @@ func (s *MinIOImageStorage) UploadImage(ctx context.Context, objectKey string, d
 	return fmt.Sprintf("%s/%s/%s", s.publicBaseURL, s.bucket, key), nil
 }
 
+// Delete removes a stored object. Removing a missing object is not an error.
+func (s *MinIOImageStorage) Delete(ctx context.Context, objectKey string) error {
+	key := strings.TrimLeft(strings.TrimSpace(objectKey), "/")
+	if key == "" {
+		return fmt.Errorf("object key is required")
+	}
+
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return fmt.Errorf("remove object from minio: %w", err)
+	}
+	return nil
+}
+
 func (s *MinIOImageStorage) ensureBucket(ctx context.Context) error {
 	exists, err := s.client.BucketExists(ctx, s.bucket)
 	if err != nil {
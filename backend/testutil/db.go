@@ -108,12 +108,15 @@ func SetupTestDBNoTx(t *testing.T) *gorm.DB {
 	// Cleanup: truncate tables in reverse dependency order
 	t.Cleanup(func() {
 		tables := []string{
+			"alerts", "audit_logs", "stock_snapshots",
+			"parked_cart_items", "parked_carts",
 			"stock_movements",
+			"sales_return_items", "sales_returns",
 			"sales_transaction_items", "sales_transactions",
-			"purchase_order_items", "purchase_orders",
+			"po_attachments", "purchase_order_items", "purchase_orders", "number_sequences",
 			"variant_racks", "variant_pricing_tiers", "variant_images", "variant_attributes",
 			"product_variants", "product_units", "product_suppliers", "product_images", "products",
-			"role_permissions", "user_roles", "permissions", "roles", "users",
+			"role_permissions", "user_roles", "permissions", "roles", "password_histories", "users",
 			"supplier_bank_accounts", "suppliers", "categories", "racks",
 		}
 		for _, table := range tables {
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// ean13InStorePrefix starts generated EAN-13 codes. GS1 reserves prefixes
+// 200-299 for restricted in-store circulation, so generated codes never clash
+// with manufacturer-assigned barcodes.
+const ean13InStorePrefix = "2"
+
+// GenerateEAN13 returns a random in-store EAN-13 barcode with a valid check digit.
+func GenerateEAN13() (string, error) {
+	digits := 12 - len(ean13InStorePrefix)
+	n, err := rand.Int(rand.Reader, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate barcode: %w", err)
+	}
+
+	body := fmt.Sprintf("%s%0*d", ean13InStorePrefix, digits, n)
+	return fmt.Sprintf("%s%d", body, gtinCheckDigit(body)), nil
+}
+
+// ValidateBarcode checks that code is an EAN-8, UPC-A or EAN-13 barcode whose
+// last digit is the correct GS1 check digit.
+func ValidateBarcode(code string) bool {
+	switch len(code) {
+	case 8, 12, 13:
+	default:
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	last := len(code) - 1
+	return int(code[last]-'0') == gtinCheckDigit(code[:last])
+}
+
+// gtinCheckDigit computes the GS1 check digit for body, the barcode digits
+// without the check digit. Weights alternate 3 and 1 starting from the
+// rightmost digit.
+func gtinCheckDigit(body string) int {
+	sum := 0
+	for i := len(body) - 1; i >= 0; i-- {
+		d := int(body[i] - '0')
+		if (len(body)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
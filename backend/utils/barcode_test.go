@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateEAN13_ProducesValidInStoreCodes(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		code, err := GenerateEAN13()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(code) != 13 {
+			t.Fatalf("expected 13 digits, got %q", code)
+		}
+		if !strings.HasPrefix(code, ean13InStorePrefix) {
+			t.Errorf("expected in-store prefix, got %q", code)
+		}
+		if !ValidateBarcode(code) {
+			t.Errorf("generated barcode %q has an invalid check digit", code)
+		}
+	}
+}
+
+func TestValidateBarcode_ValidCodes_ReturnsTrue(t *testing.T) {
+	validCodes := []string{
+		"4006381333931", // EAN-13
+		"8901234567005", // EAN-13
+		"036000291452",  // UPC-A
+		"96385074",      // EAN-8
+	}
+
+	for _, code := range validCodes {
+		t.Run(code, func(t *testing.T) {
+			if !ValidateBarcode(code) {
+				t.Errorf("expected %s to be valid, but got false", code)
+			}
+		})
+	}
+}
+
+func TestValidateBarcode_InvalidCodes_ReturnsFalse(t *testing.T) {
+	invalidCodes := []string{
+		"",
+		"4006381333932", // wrong check digit
+		"8901234567000", // wrong check digit
+		"400638133393",  // 12 digits, wrong check digit
+		"40063813339",   // unsupported length
+		"40063813339a1",
+		"ABCDEFGHIJKLM",
+		" 4006381333931",
+	}
+
+	for _, code := range invalidCodes {
+		t.Run(code, func(t *testing.T) {
+			if ValidateBarcode(code) {
+				t.Errorf("expected %q to be invalid, but got true", code)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package utils
+
+import "fmt"
+
+// PermissionCacheKey returns the Redis key a user's merged permissions are
+// cached under. The permission middleware fills it and the services that
+// change roles delete it, so both must build the key here.
+func PermissionCacheKey(userID uint) string {
+	return fmt.Sprintf("perms:%d", userID)
+}
@@ -0,0 +1,11 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionCacheKey(t *testing.T) {
+	assert.Equal(t, "perms:42", PermissionCacheKey(42))
+}
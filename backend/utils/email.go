@@ -3,9 +3,12 @@ package utils
 import (
 	"bytes"
 	_ "embed"
+	"encoding/base64"
 	"fmt"
 	"html/template"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 )
 
@@ -24,6 +27,12 @@ var credentialsTemplate string
 //go:embed templates/rejection.html
 var rejectionTemplate string
 
+//go:embed templates/verify_email.html
+var verifyEmailTemplate string
+
+//go:embed templates/purchase_order.html
+var purchaseOrderTemplate string
+
 // EmailService handles email sending operations.
 type EmailService struct {
 	host string
@@ -59,6 +68,16 @@ func (s *EmailService) SendPasswordResetEmail(toEmail, userName, resetLink strin
 	return s.sendEmail(toEmail, subject, passwordResetTemplate, data)
 }
 
+// SendVerificationEmail sends the email address verification link.
+func (s *EmailService) SendVerificationEmail(toEmail, userName, verifyLink string) error {
+	subject := "Point of Sale — Verify Your Email"
+	data := map[string]string{
+		"UserName":   userName,
+		"VerifyLink": verifyLink,
+	}
+	return s.sendEmail(toEmail, subject, verifyEmailTemplate, data)
+}
+
 // SendAccountApprovedEmail sends account approval notification.
 func (s *EmailService) SendAccountApprovedEmail(toEmail, userName string) error {
 	subject := "Point of Sale — Account Approved"
@@ -88,30 +107,58 @@ func (s *EmailService) SendRejectionEmail(toEmail, userName string) error {
 	return s.sendEmail(toEmail, subject, rejectionTemplate, data)
 }
 
+// SendPurchaseOrder sends a purchase order to a supplier with the PO
+// document attached as a PDF.
+func (s *EmailService) SendPurchaseOrder(supplierEmail, poNumber string, pdfBytes []byte) error {
+	subject := fmt.Sprintf("Purchase Order %s", poNumber)
+	body, err := renderEmailTemplate(purchaseOrderTemplate, map[string]string{
+		"PONumber": poNumber,
+	})
+	if err != nil {
+		return err
+	}
+
+	message, err := s.buildMessageWithAttachment(supplierEmail, subject, body, EmailAttachment{
+		Filename:    fmt.Sprintf("purchase-order-%s.pdf", poNumber),
+		ContentType: "application/pdf",
+		Data:        pdfBytes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+	return s.deliver(supplierEmail, message)
+}
+
 // sendEmail is a generic email sending function.
 func (s *EmailService) sendEmail(to, subject, templateStr string, data map[string]string) error {
-	// Parse template
+	body, err := renderEmailTemplate(templateStr, data)
+	if err != nil {
+		return err
+	}
+	return s.deliver(to, s.buildMessage(to, subject, body))
+}
+
+// renderEmailTemplate executes an HTML email template with data.
+func renderEmailTemplate(templateStr string, data map[string]string) (string, error) {
 	tmpl, err := template.New("email").Parse(templateStr)
 	if err != nil {
-		return fmt.Errorf("failed to parse email template: %w", err)
+		return "", fmt.Errorf("failed to parse email template: %w", err)
 	}
 
-	// Execute template
 	var body bytes.Buffer
 	if err := tmpl.Execute(&body, data); err != nil {
-		return fmt.Errorf("failed to execute email template: %w", err)
+		return "", fmt.Errorf("failed to execute email template: %w", err)
 	}
+	return body.String(), nil
+}
 
-	// Build message
-	message := s.buildMessage(to, subject, body.String())
-
+// deliver hands a complete message to the SMTP server.
+func (s *EmailService) deliver(to, message string) error {
 	// For Mailpit in development, no authentication is needed
 	addr := fmt.Sprintf("%s:%s", s.host, s.port)
-	err = smtp.SendMail(addr, nil, s.from, []string{to}, []byte(message))
-	if err != nil {
+	if err := smtp.SendMail(addr, nil, s.from, []string{to}, []byte(message)); err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
-
 	return nil
 }
 
@@ -129,3 +176,61 @@ func (s *EmailService) buildMessage(to, subject, htmlBody string) string {
 
 	return msg.String()
 }
+
+// EmailAttachment is a file attached to an outgoing email.
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// buildMessageWithAttachment constructs a multipart/mixed message with an
+// HTML body followed by a base64-encoded attachment.
+func (s *EmailService) buildMessageWithAttachment(to, subject, htmlBody string, attachment EmailAttachment) (string, error) {
+	var parts bytes.Buffer
+	mw := multipart.NewWriter(&parts)
+
+	bodyPart, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/html; charset=UTF-8"},
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, err := bodyPart.Write([]byte(htmlBody)); err != nil {
+		return "", err
+	}
+
+	filePart, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {fmt.Sprintf("%s; name=%q", attachment.ContentType, attachment.Filename)},
+		"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", attachment.Filename)},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return "", err
+	}
+	// RFC 2045 limits encoded lines to 76 characters
+	encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+	for len(encoded) > 76 {
+		if _, err := filePart.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return "", err
+		}
+		encoded = encoded[76:]
+	}
+	if _, err := filePart.Write([]byte(encoded + "\r\n")); err != nil {
+		return "", err
+	}
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	var msg strings.Builder
+	msg.WriteString(fmt.Sprintf("From: %s\r\n", s.from))
+	msg.WriteString(fmt.Sprintf("To: %s\r\n", to))
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%s\r\n", mw.Boundary()))
+	msg.WriteString("\r\n")
+	msg.Write(parts.Bytes())
+
+	return msg.String(), nil
+}
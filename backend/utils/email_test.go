@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMessageWithAttachment_ParsesAsMultipart(t *testing.T) {
+	svc := NewEmailService("localhost", "1025", "noreply@pointofsale.local")
+	pdf := []byte(strings.Repeat("%PDF-1.3 test content ", 20))
+
+	raw, err := svc.buildMessageWithAttachment("orders@supplier.test", "Purchase Order PO-1", "<p>Hello</p>", EmailAttachment{
+		Filename:    "purchase-order-PO-1.pdf",
+		ContentType: "application/pdf",
+		Data:        pdf,
+	})
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(strings.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, "orders@supplier.test", msg.Header.Get("To"))
+	assert.Equal(t, "Purchase Order PO-1", msg.Header.Get("Subject"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	bodyPart, err := reader.NextPart()
+	require.NoError(t, err)
+	body, err := io.ReadAll(bodyPart)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Hello</p>", string(body))
+
+	filePart, err := reader.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "purchase-order-PO-1.pdf", filePart.FileName())
+	encoded, err := io.ReadAll(filePart)
+	require.NoError(t, err)
+	for _, line := range strings.Split(strings.TrimSpace(string(encoded)), "\r\n") {
+		assert.LessOrEqual(t, len(line), 76)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	require.NoError(t, err)
+	assert.Equal(t, pdf, decoded)
+
+	_, err = reader.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DocumentFormat holds locale settings used when rendering money and dates in
+// generated documents such as purchase orders, receipts, and reports.
+type DocumentFormat struct {
+	CurrencySymbol     string
+	ThousandsSeparator string
+	DecimalSeparator   string
+	CurrencyDecimals   int
+	DateFormat         string // Go time layout, e.g. "02/01/2006"
+}
+
+// DefaultDocumentFormat returns Indonesian Rupiah formatting (e.g. "Rp 28.000").
+func DefaultDocumentFormat() DocumentFormat {
+	return DocumentFormat{
+		CurrencySymbol:     "Rp",
+		ThousandsSeparator: ".",
+		DecimalSeparator:   ",",
+		CurrencyDecimals:   0,
+		DateFormat:         "02/01/2006",
+	}
+}
+
+// DocumentFormatter formats values for generated documents.
+type DocumentFormatter struct {
+	format DocumentFormat
+}
+
+// NewDocumentFormatter creates a formatter. Empty separators and date format fall
+// back to the defaults; a negative decimal count is treated as zero.
+func NewDocumentFormatter(format DocumentFormat) *DocumentFormatter {
+	defaults := DefaultDocumentFormat()
+	if format.DecimalSeparator == "" {
+		format.DecimalSeparator = defaults.DecimalSeparator
+	}
+	if format.DateFormat == "" {
+		format.DateFormat = defaults.DateFormat
+	}
+	if format.CurrencyDecimals < 0 {
+		format.CurrencyDecimals = 0
+	}
+	return &DocumentFormatter{format: format}
+}
+
+// FormatNumber renders a number with the configured grouping and decimal separators.
+func (f *DocumentFormatter) FormatNumber(value float64, decimals int) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	factor := math.Pow(10, float64(decimals))
+	rounded := math.Round(math.Abs(value)*factor) / factor
+	negative := value < 0 && rounded != 0
+
+	raw := strconv.FormatFloat(rounded, 'f', decimals, 64)
+	intPart, fracPart, _ := strings.Cut(raw, ".")
+
+	var b strings.Builder
+	if negative {
+		b.WriteByte('-')
+	}
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(f.format.ThousandsSeparator)
+		}
+		b.WriteRune(digit)
+	}
+	if fracPart != "" {
+		b.WriteString(f.format.DecimalSeparator)
+		b.WriteString(fracPart)
+	}
+	return b.String()
+}
+
+// FormatMoney renders an amount with the currency symbol, e.g. "Rp 28.000".
+// Negative amounts are rendered as "-Rp 28.000".
+func (f *DocumentFormatter) FormatMoney(amount float64) string {
+	number := f.FormatNumber(amount, f.format.CurrencyDecimals)
+	if f.format.CurrencySymbol == "" {
+		return number
+	}
+	if strings.HasPrefix(number, "-") {
+		return "-" + f.format.CurrencySymbol + " " + number[1:]
+	}
+	return f.format.CurrencySymbol + " " + number
+}
+
+// FormatDate renders a date using the configured layout.
+func (f *DocumentFormatter) FormatDate(t time.Time) string {
+	return t.Format(f.format.DateFormat)
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMoney_DefaultFormat_UsesIndonesianGrouping(t *testing.T) {
+	f := NewDocumentFormatter(DefaultDocumentFormat())
+
+	assert.Equal(t, "Rp 28.000", f.FormatMoney(28000))
+	assert.Equal(t, "Rp 1.250.000", f.FormatMoney(1250000))
+	assert.Equal(t, "Rp 999", f.FormatMoney(999))
+	assert.Equal(t, "Rp 0", f.FormatMoney(0))
+	assert.Equal(t, "Rp 15.001", f.FormatMoney(15000.5))
+	assert.Equal(t, "-Rp 4.500", f.FormatMoney(-4500))
+}
+
+func TestFormatMoney_ConfiguredSeparators_RendersWithThem(t *testing.T) {
+	f := NewDocumentFormatter(DocumentFormat{
+		CurrencySymbol:     "$",
+		ThousandsSeparator: ",",
+		DecimalSeparator:   ".",
+		CurrencyDecimals:   2,
+		DateFormat:         "2006-01-02",
+	})
+
+	assert.Equal(t, "$ 1,234,567.89", f.FormatMoney(1234567.891))
+	assert.Equal(t, "$ 0.50", f.FormatMoney(0.5))
+	assert.Equal(t, "-$ 12.00", f.FormatMoney(-12))
+}
+
+func TestFormatNumber_NoSymbolOrGrouping(t *testing.T) {
+	f := NewDocumentFormatter(DocumentFormat{DecimalSeparator: ","})
+
+	assert.Equal(t, "1234567", f.FormatNumber(1234567, 0))
+	assert.Equal(t, "12,35", f.FormatNumber(12.345, 2))
+	assert.Equal(t, "0", f.FormatNumber(-0.0001, 0))
+	assert.Equal(t, "1234", f.FormatMoney(1234))
+}
+
+func TestFormatDate_UsesConfiguredLayout(t *testing.T) {
+	date := time.Date(2026, 3, 7, 14, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, "07/03/2026", NewDocumentFormatter(DefaultDocumentFormat()).FormatDate(date))
+	assert.Equal(t, "07 Mar 2026", NewDocumentFormatter(DocumentFormat{DateFormat: "02 Jan 2006"}).FormatDate(date))
+	assert.Equal(t, "2026-03-07", NewDocumentFormatter(DocumentFormat{DateFormat: "2006-01-02"}).FormatDate(date))
+}
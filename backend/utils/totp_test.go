@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the base32 encoding of the RFC 6238 SHA1 test key "12345678901234567890".
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTPCode_RFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := GenerateTOTPCode(rfc6238Secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("unexpected error at %d: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("at %d: expected %s, got %s", tt.unix, tt.want, got)
+		}
+	}
+}
+
+func TestValidateTOTPCode_AllowsOneStepDrift(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, _ := GenerateTOTPCode(rfc6238Secret, now)
+
+	if !ValidateTOTPCode(rfc6238Secret, code, now) {
+		t.Error("expected current code to be valid")
+	}
+	if !ValidateTOTPCode(rfc6238Secret, code, now.Add(30*time.Second)) {
+		t.Error("expected code from the previous step to be valid")
+	}
+	if !ValidateTOTPCode(rfc6238Secret, code, now.Add(-30*time.Second)) {
+		t.Error("expected code from the next step to be valid")
+	}
+	if ValidateTOTPCode(rfc6238Secret, code, now.Add(90*time.Second)) {
+		t.Error("expected code two steps old to be rejected")
+	}
+}
+
+func TestValidateTOTPCode_InvalidInput_ReturnsFalse(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+
+	if ValidateTOTPCode(rfc6238Secret, "000000", now) {
+		t.Error("expected wrong code to be rejected")
+	}
+	if ValidateTOTPCode(rfc6238Secret, "12345", now) {
+		t.Error("expected short code to be rejected")
+	}
+	if ValidateTOTPCode("not base32!", "005924", now) {
+		t.Error("expected invalid secret to be rejected")
+	}
+}
+
+func TestGenerateTOTPSecret_RoundTrips(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("expected 32 base32 characters, got %d", len(secret))
+	}
+
+	now := time.Now()
+	code, err := GenerateTOTPCode(secret, now)
+	if err != nil {
+		t.Fatalf("expected generated secret to decode, got %v", err)
+	}
+	if !ValidateTOTPCode(secret, code, now) {
+		t.Error("expected code from generated secret to validate")
+	}
+}
+
+func TestTOTPURL_ContainsSecretAndIssuer(t *testing.T) {
+	u := TOTPURL("PointOfSale", "john@example.com", rfc6238Secret)
+
+	if !strings.HasPrefix(u, "otpauth://totp/PointOfSale:john@example.com?") {
+		t.Errorf("unexpected URL prefix: %s", u)
+	}
+	if !strings.Contains(u, "secret="+rfc6238Secret) || !strings.Contains(u, "issuer=PointOfSale") {
+		t.Errorf("URL missing secret or issuer: %s", u)
+	}
+}
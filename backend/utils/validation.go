@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 	"unicode"
@@ -8,6 +10,8 @@ import (
 
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9\-]+(\.[a-zA-Z0-9\-]+)*\.[a-zA-Z]{2,}$`)
 
+var hostnameRegex = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9\-]*[a-zA-Z0-9])?\.)+[a-zA-Z]{2,}$`)
+
 // ValidateEmail checks if the email format is valid
 func ValidateEmail(email string) bool {
 	if email == "" {
@@ -16,12 +20,65 @@ func ValidateEmail(email string) bool {
 	return emailRegex.MatchString(email)
 }
 
-// ValidatePassword checks password strength and returns a list of unmet requirements
+// ValidateURL checks if a website URL is valid. The scheme is optional but must
+// be http or https when given, and the host must be a dotted domain name.
+func ValidateURL(rawURL string) bool {
+	if rawURL == "" || strings.ContainsAny(rawURL, " \t\n") {
+		return false
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return false
+	}
+	return hostnameRegex.MatchString(parsed.Hostname())
+}
+
+// PasswordPolicy holds the rules a new password must satisfy. A MaxLength of
+// zero means no upper limit.
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// DefaultPasswordPolicy returns the built-in policy: at least 8 characters
+// with an uppercase letter, a lowercase letter, a digit and a special character.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		MinLength:     8,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+}
+
+// ValidatePassword checks password strength against the default policy and
+// returns a list of unmet requirements
 func ValidatePassword(password string) []string {
+	return DefaultPasswordPolicy().Validate(password)
+}
+
+// Validate checks the password against the policy and returns one message
+// per unmet rule.
+func (p PasswordPolicy) Validate(password string) []string {
 	var errors []string
 
-	if len(password) < 8 {
-		errors = append(errors, "Password must be at least 8 characters")
+	length := len(password)
+	if length < p.MinLength {
+		errors = append(errors, fmt.Sprintf("Password must be at least %d characters", p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		errors = append(errors, fmt.Sprintf("Password must be at most %d characters", p.MaxLength))
 	}
 
 	hasUpper := false
@@ -42,16 +99,16 @@ func ValidatePassword(password string) []string {
 		}
 	}
 
-	if !hasUpper {
+	if p.RequireUpper && !hasUpper {
 		errors = append(errors, "Password must contain at least one uppercase letter")
 	}
-	if !hasLower {
+	if p.RequireLower && !hasLower {
 		errors = append(errors, "Password must contain at least one lowercase letter")
 	}
-	if !hasDigit {
+	if p.RequireDigit && !hasDigit {
 		errors = append(errors, "Password must contain at least one digit")
 	}
-	if !hasSpecial {
+	if p.RequireSymbol && !hasSpecial {
 		errors = append(errors, "Password must contain at least one special character")
 	}
 
@@ -65,3 +122,21 @@ func ValidateRequired(field, name string) string {
 	}
 	return ""
 }
+
+// ValidateNonNegative returns an error message naming field when value is
+// below zero, or an empty string otherwise.
+func ValidateNonNegative(value float64, field string) string {
+	if value < 0 {
+		return field + " must not be negative"
+	}
+	return ""
+}
+
+// ValidatePositive returns an error message naming field unless value is
+// above zero, or an empty string otherwise.
+func ValidatePositive(value float64, field string) string {
+	if value <= 0 {
+		return field + " must be greater than zero"
+	}
+	return ""
+}
@@ -45,6 +45,45 @@ func TestValidateEmail_InvalidFormats_ReturnsFalse(t *testing.T) {
 	}
 }
 
+func TestValidateURL_ValidFormats_ReturnsTrue(t *testing.T) {
+	validURLs := []string{
+		"https://example.com",
+		"http://shop.example.co.id/path?q=1",
+		"www.example.com",
+		"example-supplier.com",
+		"https://example.com:8443",
+	}
+
+	for _, rawURL := range validURLs {
+		t.Run(rawURL, func(t *testing.T) {
+			if !ValidateURL(rawURL) {
+				t.Errorf("expected %s to be valid, but got false", rawURL)
+			}
+		})
+	}
+}
+
+func TestValidateURL_InvalidFormats_ReturnsFalse(t *testing.T) {
+	invalidURLs := []string{
+		"",
+		"example",
+		"htp://example.com",
+		"ftp://example.com",
+		"https://",
+		"https://exa mple.com",
+		"example..com",
+		"https://-example.com",
+	}
+
+	for _, rawURL := range invalidURLs {
+		t.Run(rawURL, func(t *testing.T) {
+			if ValidateURL(rawURL) {
+				t.Errorf("expected %s to be invalid, but got true", rawURL)
+			}
+		})
+	}
+}
+
 func TestValidatePassword_StrongPassword_ReturnsNoErrors(t *testing.T) {
 	strongPasswords := []string{
 		"SecurePass123!",
@@ -173,6 +212,58 @@ func TestValidatePassword_MultipleErrors_ReturnsAll(t *testing.T) {
 	}
 }
 
+func TestPasswordPolicy_Default_MatchesValidatePassword(t *testing.T) {
+	for _, password := range []string{"weak", "password123!", "SecurePass123!"} {
+		got := DefaultPasswordPolicy().Validate(password)
+		want := ValidatePassword(password)
+		if len(got) != len(want) {
+			t.Errorf("policy and ValidatePassword disagree for %q: %v vs %v", password, got, want)
+		}
+	}
+}
+
+func TestPasswordPolicy_CustomLengths_ReportsConfiguredLimits(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 12, MaxLength: 16}
+
+	errors := policy.Validate("short")
+	if len(errors) != 1 || errors[0] != "Password must be at least 12 characters" {
+		t.Errorf("expected only the minimum length error, got: %v", errors)
+	}
+
+	errors = policy.Validate("this-password-is-too-long")
+	if len(errors) != 1 || errors[0] != "Password must be at most 16 characters" {
+		t.Errorf("expected only the maximum length error, got: %v", errors)
+	}
+}
+
+func TestPasswordPolicy_ClassesNotRequired_AreNotChecked(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 8, RequireLower: true}
+
+	if errors := policy.Validate("lowercaseonly"); len(errors) > 0 {
+		t.Errorf("expected no errors, got: %v", errors)
+	}
+}
+
+func TestPasswordPolicy_EachFailedRule_IsSeparateMessage(t *testing.T) {
+	policy := PasswordPolicy{MinLength: 10, RequireUpper: true, RequireDigit: true, RequireSymbol: true}
+
+	errors := policy.Validate("abc")
+	expected := []string{
+		"Password must be at least 10 characters",
+		"Password must contain at least one uppercase letter",
+		"Password must contain at least one digit",
+		"Password must contain at least one special character",
+	}
+	if len(errors) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expected), len(errors), errors)
+	}
+	for i := range expected {
+		if errors[i] != expected[i] {
+			t.Errorf("error %d: expected %q, got %q", i, expected[i], errors[i])
+		}
+	}
+}
+
 func TestValidateRequired_EmptyField_ReturnsError(t *testing.T) {
 	tests := []struct {
 		field string
@@ -227,3 +318,39 @@ func TestValidateRequired_WhitespaceOnly_ReturnsError(t *testing.T) {
 		t.Error("expected error for whitespace-only field, got empty string")
 	}
 }
+
+func TestValidateNonNegative(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{-0.01, "items[0].price must not be negative"},
+		{-5, "items[0].price must not be negative"},
+		{0, ""},
+		{12.5, ""},
+	}
+
+	for _, tt := range tests {
+		if got := ValidateNonNegative(tt.value, "items[0].price"); got != tt.expected {
+			t.Errorf("ValidateNonNegative(%v) = %q, expected %q", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestValidatePositive(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{-1, "items[0].quantity must be greater than zero"},
+		{0, "items[0].quantity must be greater than zero"},
+		{0.5, ""},
+		{3, ""},
+	}
+
+	for _, tt := range tests {
+		if got := ValidatePositive(tt.value, "items[0].quantity"); got != tt.expected {
+			t.Errorf("ValidatePositive(%v) = %q, expected %q", tt.value, got, tt.expected)
+		}
+	}
+}